@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+// Package adaptiverate implements a per-domain send rate that adapts to the throttling responses
+// of the receiving mail servers
+package adaptiverate
+
+import (
+	"context"
+	"errors"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wneessen/go-mail"
+)
+
+const (
+	// DefaultSmoothing is the default smoothing factor of the throttle rate. Each result accounts for
+	// this share of the smoothed throttle rate.
+	DefaultSmoothing = 0.2
+
+	// DefaultThreshold is the default throttle rate above which the send rate of a domain is reduced
+	// and below which it is increased again
+	DefaultThreshold = 0.1
+
+	// DefaultDecrease is the default factor by which the send rate is reduced after a throttled send
+	DefaultDecrease = 0.5
+
+	// DefaultIncrease is the default factor by which the send rate is increased after a successful
+	// send
+	DefaultIncrease = 1.1
+)
+
+// Option is a function type that modifies the configuration of a Controller
+type Option func(*Controller)
+
+// Controller adapts the send rate of each destination domain to the throttling of its mail servers
+//
+// The results of the sends to a domain are recorded with Record or RecordError. The share of
+// throttled sends, i. e. sends that were rejected with a 4xx reply, is smoothed exponentially. While
+// the smoothed throttle rate exceeds the threshold, each throttled send reduces the send rate of the
+// domain by the decrease factor. While it is below the threshold, each successful send increases the
+// send rate by the increase factor. The send rate always stays within the bounds of the Controller.
+// Each send needs to be reserved with Reserve or Wait, which spread the sends to a domain according
+// to its current send rate. A Controller is safe for concurrent use.
+type Controller struct {
+	decrease  float64
+	increase  float64
+	initial   float64
+	max       float64
+	min       float64
+	mutex     sync.Mutex
+	now       func() time.Time
+	smoothing float64
+	states    map[string]*state
+	threshold float64
+}
+
+// state holds the send rate and the throttle rate of a single domain
+type state struct {
+	next         time.Time
+	rate         float64
+	throttleRate float64
+}
+
+// NewController returns a new Controller that keeps the send rate of each domain between the given
+// minimum and maximum number of messages per second. The send rate of a domain starts at the
+// maximum, unless WithInitialRate is used. A maximum below the minimum is raised to the minimum.
+func NewController(minRate, maxRate float64, opts ...Option) *Controller {
+	if maxRate < minRate {
+		maxRate = minRate
+	}
+	controller := &Controller{
+		decrease:  DefaultDecrease,
+		increase:  DefaultIncrease,
+		initial:   maxRate,
+		max:       maxRate,
+		min:       minRate,
+		now:       time.Now,
+		smoothing: DefaultSmoothing,
+		states:    make(map[string]*state),
+		threshold: DefaultThreshold,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(controller)
+	}
+	controller.initial = controller.bound(controller.initial)
+	return controller
+}
+
+// WithInitialRate sets the send rate in messages per second with which each domain starts
+func WithInitialRate(rate float64) Option {
+	return func(c *Controller) {
+		c.initial = rate
+	}
+}
+
+// WithSmoothing sets the smoothing factor of the throttle rate, between 0 and 1. A higher factor
+// makes the throttle rate react faster to the latest results. Factors outside of the range are
+// ignored.
+func WithSmoothing(smoothing float64) Option {
+	return func(c *Controller) {
+		if smoothing > 0 && smoothing <= 1 {
+			c.smoothing = smoothing
+		}
+	}
+}
+
+// WithThreshold sets the throttle rate, between 0 and 1, above which the send rate of a domain is
+// reduced and below which it is increased again. Thresholds outside of the range are ignored.
+func WithThreshold(threshold float64) Option {
+	return func(c *Controller) {
+		if threshold >= 0 && threshold < 1 {
+			c.threshold = threshold
+		}
+	}
+}
+
+// WithFactors sets the factors by which the send rate is reduced after a throttled send and
+// increased after a successful send. The decrease factor must be between 0 and 1 and the increase
+// factor must be greater than 1, otherwise the respective factor is ignored.
+func WithFactors(decrease, increase float64) Option {
+	return func(c *Controller) {
+		if decrease > 0 && decrease < 1 {
+			c.decrease = decrease
+		}
+		if increase > 1 {
+			c.increase = increase
+		}
+	}
+}
+
+// Record records the result of a send to the given domain and adapts its send rate
+func (c *Controller) Record(domain string, throttled bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	domainState := c.stateFor(normalizeDomain(domain))
+	result := 0.0
+	if throttled {
+		result = 1
+	}
+	domainState.throttleRate = c.smoothing*result + (1-c.smoothing)*domainState.throttleRate
+	switch {
+	case throttled && domainState.throttleRate > c.threshold:
+		domainState.rate = c.bound(domainState.rate * c.decrease)
+	case !throttled && domainState.throttleRate < c.threshold:
+		domainState.rate = c.bound(domainState.rate * c.increase)
+	}
+}
+
+// RecordError records the result of a send to the given domain, as returned by mail.Client.Send,
+// and adapts its send rate. A nil error is recorded as successful send and an error that holds a
+// 4xx reply of the server as throttled send. Other errors, i. e. network errors or permanent
+// rejections, do not say anything about the throttling of the domain and are not recorded.
+func (c *Controller) RecordError(domain string, err error) {
+	if err == nil {
+		c.Record(domain, false)
+		return
+	}
+	if isThrottled(err) {
+		c.Record(domain, true)
+	}
+}
+
+// Rate returns the current send rate of the given domain in messages per second
+func (c *Controller) Rate(domain string) float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if domainState, ok := c.states[normalizeDomain(domain)]; ok {
+		return domainState.rate
+	}
+	return c.initial
+}
+
+// ThrottleRate returns the current smoothed share of throttled sends of the given domain, between
+// 0 and 1
+func (c *Controller) ThrottleRate(domain string) float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if domainState, ok := c.states[normalizeDomain(domain)]; ok {
+		return domainState.throttleRate
+	}
+	return 0
+}
+
+// Rates returns the current send rates of all domains that have been used with the Controller, in
+// messages per second, i. e. for exporting them as metrics
+func (c *Controller) Rates() map[string]float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	rates := make(map[string]float64, len(c.states))
+	for domain, domainState := range c.states {
+		rates[domain] = domainState.rate
+	}
+	return rates
+}
+
+// Reserve reserves a send to the given domain and returns the point in time at which the send may
+// take place. The returned time is never before the current time. A send rate of zero means that
+// the sends to the domain are not limited.
+func (c *Controller) Reserve(domain string) time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	domainState := c.stateFor(normalizeDomain(domain))
+	slot := c.now()
+	if domainState.next.After(slot) {
+		slot = domainState.next
+	}
+	if domainState.rate > 0 {
+		domainState.next = slot.Add(time.Duration(float64(time.Second) / domainState.rate))
+	}
+	return slot
+}
+
+// Wait reserves a send to the given domain and blocks until the send may take place or the context
+// is done. Note that the reservation is not released if the context is done.
+func (c *Controller) Wait(ctx context.Context, domain string) error {
+	delay := c.Reserve(domain).Sub(c.now())
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// bound returns the given send rate within the bounds of the Controller
+func (c *Controller) bound(rate float64) float64 {
+	if rate < c.min {
+		return c.min
+	}
+	if rate > c.max {
+		return c.max
+	}
+	return rate
+}
+
+// stateFor returns the state of the given domain, which is created with the initial send rate if
+// it does not exist yet
+func (c *Controller) stateFor(domain string) *state {
+	domainState, ok := c.states[domain]
+	if !ok {
+		domainState = &state{rate: c.initial}
+		c.states[domain] = domainState
+	}
+	return domainState
+}
+
+// isThrottled reports whether the given error holds a 4xx reply of the server
+func isThrottled(err error) bool {
+	var sendErr *mail.SendError
+	if errors.As(err, &sendErr) {
+		return sendErr.Code() >= 400 && sendErr.Code() < 500
+	}
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 400 && protoErr.Code < 500
+	}
+	return false
+}
+
+// normalizeDomain returns the case-insensitive representation of the given domain
+func normalizeDomain(domain string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+}
@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package adaptiverate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/wneessen/go-mail"
+)
+
+// testClock is a manually advanced clock for the Controller
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func newTestController(minRate, maxRate float64, opts ...Option) (*Controller, *testClock) {
+	clock := &testClock{now: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)}
+	controller := NewController(minRate, maxRate, opts...)
+	controller.now = clock.Now
+	return controller, clock
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestController_Record(t *testing.T) {
+	t.Run("throttled sends reduce the rate", func(t *testing.T) {
+		controller, _ := newTestController(1, 10)
+		controller.Record("example.com", true)
+		if rate := controller.Rate("example.com"); !almostEqual(rate, 5) {
+			t.Errorf("expected rate: %f, got: %f", 5.0, rate)
+		}
+		if throttle := controller.ThrottleRate("EXAMPLE.com"); !almostEqual(throttle, DefaultSmoothing) {
+			t.Errorf("expected throttle rate: %f, got: %f", DefaultSmoothing, throttle)
+		}
+		for i := 0; i < 10; i++ {
+			controller.Record("example.com", true)
+		}
+		if rate := controller.Rate("example.com"); !almostEqual(rate, 1) {
+			t.Errorf("expected rate at lower bound: %f, got: %f", 1.0, rate)
+		}
+	})
+	t.Run("successful sends recover the rate", func(t *testing.T) {
+		controller, _ := newTestController(1, 10, WithInitialRate(2))
+		controller.Record("example.com", true)
+		// The throttle rate needs to fall below the threshold before the rate is increased
+		controller.Record("example.com", false)
+		if rate := controller.Rate("example.com"); !almostEqual(rate, 1) {
+			t.Errorf("expected rate to stay at: %f, got: %f", 1.0, rate)
+		}
+		for i := 0; i < 100; i++ {
+			controller.Record("example.com", false)
+		}
+		if rate := controller.Rate("example.com"); !almostEqual(rate, 10) {
+			t.Errorf("expected rate at upper bound: %f, got: %f", 10.0, rate)
+		}
+	})
+	t.Run("single throttle below threshold keeps the rate", func(t *testing.T) {
+		controller, _ := newTestController(1, 10, WithSmoothing(0.05))
+		controller.Record("example.com", true)
+		if rate := controller.Rate("example.com"); !almostEqual(rate, 10) {
+			t.Errorf("expected rate: %f, got: %f", 10.0, rate)
+		}
+	})
+	t.Run("domains are independent", func(t *testing.T) {
+		controller, _ := newTestController(1, 10, WithFactors(0.8, 1.2))
+		controller.Record("example.com", true)
+		controller.Record("example.org.", false)
+		rates := controller.Rates()
+		if len(rates) != 2 {
+			t.Fatalf("expected 2 rates, got: %d", len(rates))
+		}
+		if !almostEqual(rates["example.com"], 8) || !almostEqual(rates["example.org"], 10) {
+			t.Errorf("expected independent rates, got: %v", rates)
+		}
+		if rate := controller.Rate("unknown.example.com"); !almostEqual(rate, 10) {
+			t.Errorf("expected initial rate for unknown domain: %f, got: %f", 10.0, rate)
+		}
+	})
+	t.Run("invalid options are ignored", func(t *testing.T) {
+		controller, _ := newTestController(10, 1, WithSmoothing(2), WithThreshold(1), WithFactors(2, 0.5), nil)
+		if controller.smoothing != DefaultSmoothing || controller.threshold != DefaultThreshold ||
+			controller.decrease != DefaultDecrease || controller.increase != DefaultIncrease {
+			t.Error("expected invalid options to be ignored")
+		}
+		if controller.max != 10 {
+			t.Errorf("expected maximum to be raised to the minimum, got: %f", controller.max)
+		}
+	})
+}
+
+func TestController_RecordError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		recorded  bool
+		throttled bool
+	}{
+		{"success", nil, true, false},
+		{"temporary reply", &textproto.Error{Code: 451, Msg: "4.7.1 Try again later"}, true, true},
+		{"wrapped temporary reply", fmt.Errorf("send failed: %w", &textproto.Error{Code: 421, Msg: "4.7.0"}), true, true},
+		{"permanent reply", &textproto.Error{Code: 550, Msg: "5.1.1 User unknown"}, false, false},
+		{"send error without reply", &mail.SendError{Reason: mail.ErrConnCheck}, false, false},
+		{"network error", errors.New("connection reset"), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller, _ := newTestController(1, 10, WithInitialRate(5))
+			controller.RecordError("example.com", tt.err)
+			_, recorded := controller.Rates()["example.com"]
+			if recorded != tt.recorded {
+				t.Errorf("expected recorded: %t, got: %t", tt.recorded, recorded)
+			}
+			if throttled := controller.ThrottleRate("example.com") > 0; throttled != tt.throttled {
+				t.Errorf("expected throttled: %t, got: %t", tt.throttled, throttled)
+			}
+		})
+	}
+}
+
+func TestController_Reserve(t *testing.T) {
+	t.Run("sends are spread by rate", func(t *testing.T) {
+		controller, clock := newTestController(1, 4)
+		start := clock.now
+		for i := 0; i < 4; i++ {
+			want := start.Add(time.Millisecond * 250 * time.Duration(i))
+			if got := controller.Reserve("example.com"); !got.Equal(want) {
+				t.Errorf("reservation %d: expected %s, got: %s", i, want, got)
+			}
+		}
+		controller.Record("example.com", true)
+		want := start.Add(time.Second)
+		if got := controller.Reserve("example.com"); !got.Equal(want) {
+			t.Errorf("expected reservation at %s, got: %s", want, got)
+		}
+		// The reduced rate of 2 messages per second applies to the next reservation
+		want = want.Add(time.Millisecond * 500)
+		if got := controller.Reserve("example.com"); !got.Equal(want) {
+			t.Errorf("expected reservation at reduced rate %s, got: %s", want, got)
+		}
+	})
+	t.Run("zero rate is not limited", func(t *testing.T) {
+		controller, clock := newTestController(0, 0)
+		for i := 0; i < 10; i++ {
+			if got := controller.Reserve("example.com"); !got.Equal(clock.now) {
+				t.Errorf("expected unlimited domain to be reserved immediately, got: %s", got)
+			}
+		}
+	})
+}
+
+func TestController_Wait(t *testing.T) {
+	t.Run("wait returns immediately for available slot", func(t *testing.T) {
+		controller := NewController(1, 100)
+		if err := controller.Wait(context.Background(), "example.com"); err != nil {
+			t.Errorf("failed to wait for reservation: %s", err)
+		}
+	})
+	t.Run("wait is aborted by context", func(t *testing.T) {
+		controller := NewController(0.1, 0.1)
+		controller.Reserve("example.com")
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+		if err := controller.Wait(ctx, "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected error: %s, got: %s", context.DeadlineExceeded, err)
+		}
+	})
+}
@@ -0,0 +1,245 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+)
+
+// ErrInvalidGroupName is returned if the name of an address group is empty or contains characters
+// that cannot be represented in a display name.
+var ErrInvalidGroupName = errors.New("invalid address group name")
+
+// addrGroup represents a named group of addresses in an address header, as defined in RFC 5322.
+type addrGroup struct {
+	// name is the display name of the group.
+	name string
+
+	// members holds the addresses of the group, which might be empty.
+	members []*mail.Address
+}
+
+// ToGroup adds a named group of addresses to the "To" address header of the Msg, i. e.
+// "Team: alice@example.com, bob@example.com;".
+//
+// The members of the group are recipients of the Msg like any other "To" address. A group without
+// members, like "Undisclosed recipients:;", can be used to indicate that the actual recipients are
+// only given as "Bcc" addresses. Groups can also be set with any of the "To", "Cc" or "Bcc" setters
+// by passing a value in group syntax.
+//
+// Parameters:
+//   - groupName: The display name of the group.
+//   - addrs: The addresses of the members of the group.
+//
+// Returns:
+//   - An error if the group name or any of the addresses is invalid; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.4
+func (m *Msg) ToGroup(groupName string, addrs ...string) error {
+	groupName = strings.TrimSpace(groupName)
+	if groupName == "" || strings.ContainsAny(groupName, "\r\n") {
+		return fmt.Errorf("%w: %q", ErrInvalidGroupName, groupName)
+	}
+	group := &addrGroup{name: groupName}
+	for _, addr := range addrs {
+		address, err := m.parseAddress(addr)
+		if err != nil {
+			return fmt.Errorf(errParseMailAddr, addr, err)
+		}
+		group.members = append(group.members, address)
+	}
+	return m.addAddr(HeaderTo, group.String())
+}
+
+// String returns the group in the group syntax of RFC 5322.
+func (g *addrGroup) String() string {
+	members := make([]string, len(g.members))
+	for i, member := range g.members {
+		members[i] = member.String()
+	}
+	if len(members) == 0 {
+		return formatGroupName(g.name) + ":;"
+	}
+	return formatGroupName(g.name) + ": " + strings.Join(members, ", ") + ";"
+}
+
+// contains returns true if the given address is a member of the group.
+func (g *addrGroup) contains(address *mail.Address) bool {
+	for _, member := range g.members {
+		if member == address {
+			return true
+		}
+	}
+	return false
+}
+
+// addrHeaderValues returns the values of the given address header of the Msg, where the members
+// of an address group are replaced by the group in group syntax.
+func (m *Msg) addrHeaderValues(header AddrHeader) []string {
+	var values []string
+	groups := m.addrGroups[header]
+	written := make(map[*addrGroup]bool, len(groups))
+	for _, address := range m.addrHeader[header] {
+		group := groupOf(groups, address)
+		if group == nil {
+			values = append(values, address.String())
+			continue
+		}
+		if !written[group] {
+			values = append(values, group.String())
+			written[group] = true
+		}
+	}
+	for _, group := range groups {
+		if len(group.members) == 0 {
+			values = append(values, group.String())
+		}
+	}
+	return values
+}
+
+// groupOf returns the group of the given groups the address is a member of, or nil.
+func groupOf(groups []*addrGroup, address *mail.Address) *addrGroup {
+	for _, group := range groups {
+		if group.contains(address) {
+			return group
+		}
+	}
+	return nil
+}
+
+// supportsAddrGroups returns true if the given address header may contain address groups.
+func supportsAddrGroups(header AddrHeader) bool {
+	return header == HeaderTo || header == HeaderCc || header == HeaderBcc
+}
+
+// parseAddrGroup parses the given value as address group in the group syntax of RFC 5322, validating
+// its members according to the AddressValidation of the Msg. The returned bool is false if the value
+// is not in group syntax.
+func (m *Msg) parseAddrGroup(value string) (*addrGroup, bool, error) {
+	value = strings.TrimSpace(value)
+	colon := groupColonIndex(value)
+	if colon < 0 || !strings.HasSuffix(value, ";") {
+		return nil, false, nil
+	}
+	name := strings.TrimSpace(value[:colon])
+	if len(name) >= 2 && strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
+		name = strings.ReplaceAll(strings.ReplaceAll(name[1:len(name)-1], `\"`, `"`), `\\`, `\`)
+	}
+	if decoded, err := (&mime.WordDecoder{}).DecodeHeader(name); err == nil {
+		name = decoded
+	}
+	if name == "" {
+		return nil, true, fmt.Errorf("%w: %q", ErrInvalidGroupName, value)
+	}
+	group := &addrGroup{name: name}
+	members := strings.TrimSpace(value[colon+1 : len(value)-1])
+	if members == "" {
+		return group, true, nil
+	}
+	for _, member := range splitAddressList(members) {
+		address, err := m.parseAddress(member)
+		if err != nil {
+			return nil, true, fmt.Errorf(errParseMailAddr, value, err)
+		}
+		group.members = append(group.members, address)
+	}
+	return group, true, nil
+}
+
+// groupColonIndex returns the index of the colon that ends the display name of an address group,
+// or -1 if the value does not start with a group display name.
+func groupColonIndex(value string) int {
+	inQuotes := false
+	commentDepth := 0
+	for i := 0; i < len(value); i++ {
+		switch char := value[i]; {
+		case char == '\\' && (inQuotes || commentDepth > 0):
+			i++
+		case inQuotes:
+			inQuotes = char != '"'
+		case char == '"' && commentDepth == 0:
+			inQuotes = true
+		case char == '(':
+			commentDepth++
+		case char == ')' && commentDepth > 0:
+			commentDepth--
+		case commentDepth > 0:
+		case char == ':':
+			return i
+		case char == '<' || char == '@' || char == ',' || char == ';':
+			return -1
+		}
+	}
+	return -1
+}
+
+// splitAddressList splits the given address list header value into its addresses and address
+// groups, without splitting at the commas between the members of a group.
+func splitAddressList(value string) []string {
+	var items []string
+	inQuotes, inGroup := false, false
+	commentDepth, angleDepth, start := 0, 0, 0
+	for i := 0; i < len(value); i++ {
+		switch char := value[i]; {
+		case char == '\\' && (inQuotes || commentDepth > 0):
+			i++
+		case inQuotes:
+			inQuotes = char != '"'
+		case char == '"' && commentDepth == 0:
+			inQuotes = true
+		case char == '(':
+			commentDepth++
+		case char == ')' && commentDepth > 0:
+			commentDepth--
+		case commentDepth > 0:
+		case char == '<':
+			angleDepth++
+		case char == '>' && angleDepth > 0:
+			angleDepth--
+		case angleDepth > 0:
+		case char == ':':
+			inGroup = true
+		case char == ';' && inGroup:
+			inGroup = false
+		case char == ',' && !inGroup:
+			items = appendAddressListItem(items, value[start:i])
+			start = i + 1
+		}
+	}
+	return appendAddressListItem(items, value[start:])
+}
+
+// appendAddressListItem appends the trimmed item to the given items, unless it is empty.
+func appendAddressListItem(items []string, item string) []string {
+	if item = strings.TrimSpace(item); item != "" {
+		items = append(items, item)
+	}
+	return items
+}
+
+// formatGroupName formats the given group name as display name phrase, which is quoted if it contains
+// special characters and encoded if it contains non-ASCII characters.
+func formatGroupName(name string) string {
+	needsQuotes := false
+	for i := 0; i < len(name); i++ {
+		char := name[i]
+		if char >= 0x80 {
+			return mime.QEncoding.Encode("UTF-8", name)
+		}
+		if char < ' ' || strings.IndexByte(`()<>[]:;@\,."`, char) >= 0 {
+			needsQuotes = true
+		}
+	}
+	if !needsQuotes {
+		return name
+	}
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(name, `\`, `\\`), `"`, `\"`) + `"`
+}
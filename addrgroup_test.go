@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMsg_ToGroup(t *testing.T) {
+	t.Run("group with members", func(t *testing.T) {
+		message := testMessage(t, WithHeaderFolding(NoFolding))
+		if err := message.ToGroup("Team", "alice@example.com", "Bob <bob@example.com>"); err != nil {
+			t.Fatalf("failed to add group: %s", err)
+		}
+		assertHeaderLine(t, message,
+			`To: <valid-to@domain.tld>, Team: <alice@example.com>, "Bob" <bob@example.com>;`)
+		recipients, err := message.GetRecipients()
+		if err != nil {
+			t.Fatalf("failed to get recipients: %s", err)
+		}
+		if len(recipients) != 3 || recipients[1] != "alice@example.com" || recipients[2] != "bob@example.com" {
+			t.Errorf("expected group members to be recipients, got: %v", recipients)
+		}
+	})
+	t.Run("empty group with bcc recipients", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.To(); err != nil {
+			t.Fatalf("failed to reset recipients: %s", err)
+		}
+		if err := message.ToGroup("Undisclosed recipients"); err != nil {
+			t.Fatalf("failed to add group: %s", err)
+		}
+		if err := message.Bcc("hidden@example.com"); err != nil {
+			t.Fatalf("failed to set bcc recipient: %s", err)
+		}
+		assertHeaderLine(t, message, "To: Undisclosed recipients:;")
+		recipients, err := message.GetRecipients()
+		if err != nil {
+			t.Fatalf("failed to get recipients: %s", err)
+		}
+		if len(recipients) != 1 || recipients[0] != "hidden@example.com" {
+			t.Errorf("unexpected recipients: %v", recipients)
+		}
+	})
+	t.Run("group is preserved when adding addresses", func(t *testing.T) {
+		message := NewMsg()
+		if err := message.ToGroup("Team, Inc.", "alice@example.com"); err != nil {
+			t.Fatalf("failed to add group: %s", err)
+		}
+		if err := message.AddTo("carol@example.com"); err != nil {
+			t.Fatalf("failed to add recipient: %s", err)
+		}
+		if values := message.addrHeaderValues(HeaderTo); len(values) != 2 ||
+			values[0] != `"Team, Inc.": <alice@example.com>;` || values[1] != "<carol@example.com>" {
+			t.Errorf("unexpected To header values: %v", values)
+		}
+	})
+	t.Run("invalid group fails", func(t *testing.T) {
+		message := NewMsg()
+		if err := message.ToGroup(" "); !errors.Is(err, ErrInvalidGroupName) {
+			t.Errorf("expected error: %s, got: %s", ErrInvalidGroupName, err)
+		}
+		if err := message.ToGroup("Team", "invalid"); err == nil {
+			t.Error("expected group with invalid member to fail")
+		}
+		if err := message.Cc("Team: invalid;"); err == nil {
+			t.Error("expected group with invalid member to fail")
+		}
+	})
+}
+
+func TestMsg_SetAddrHeader_group(t *testing.T) {
+	message := NewMsg()
+	if err := message.Cc(`"Support Team": alice@example.com, bob@example.com;`, "carol@example.com"); err != nil {
+		t.Fatalf("failed to set cc recipients: %s", err)
+	}
+	if addresses := message.GetCcString(); len(addresses) != 3 {
+		t.Errorf("expected 3 cc addresses, got: %v", addresses)
+	}
+	if values := message.addrHeaderValues(HeaderCc); len(values) != 2 ||
+		values[0] != "Support Team: <alice@example.com>, <bob@example.com>;" {
+		t.Errorf("unexpected Cc header values: %v", values)
+	}
+	message.CcIgnoreInvalid("Team: invalid;", "dave@example.com")
+	if values := message.addrHeaderValues(HeaderCc); len(values) != 1 || values[0] != "<dave@example.com>" {
+		t.Errorf("expected invalid group to be ignored, got: %v", values)
+	}
+}
+
+func TestEMLToMsgFromString_group(t *testing.T) {
+	eml := "From: sender@example.com\r\n" +
+		"To: Team: alice@example.com, \"Doe, Bob\" <bob@example.com>;, carol@example.com\r\n" +
+		"Cc: Undisclosed recipients:;\r\n" +
+		"Subject: Group test\r\n" +
+		"Date: Wed, 01 Nov 2023 00:00:00 +0000\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		"Hello\r\n"
+	message, err := EMLToMsgFromString(eml)
+	if err != nil {
+		t.Fatalf("failed to parse EML: %s", err)
+	}
+	if values := message.addrHeaderValues(HeaderTo); len(values) != 2 ||
+		values[0] != `Team: <alice@example.com>, "Doe, Bob" <bob@example.com>;` ||
+		values[1] != "<carol@example.com>" {
+		t.Errorf("unexpected To header values: %v", values)
+	}
+	if values := message.addrHeaderValues(HeaderCc); len(values) != 1 || values[0] != "Undisclosed recipients:;" {
+		t.Errorf("unexpected Cc header values: %v", values)
+	}
+}
+
+func TestSplitAddressList(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"alice@example.com, bob@example.com", []string{"alice@example.com", "bob@example.com"}},
+		{`"Doe, Jane" <jane@example.com>, bob@example.com`, []string{`"Doe, Jane" <jane@example.com>`, "bob@example.com"}},
+		{
+			"Team: a@example.com, b@example.com;, c@example.com",
+			[]string{"Team: a@example.com, b@example.com;", "c@example.com"},
+		},
+		{"Empty:;, (comment, with comma) d@example.com,", []string{"Empty:;", "(comment, with comma) d@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got := splitAddressList(tt.value)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("unexpected split, want: %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// assertHeaderLine writes the given Msg and checks that it contains the given header line.
+func assertHeaderLine(t *testing.T, message *Msg, line string) {
+	t.Helper()
+	buffer := bytes.NewBuffer(nil)
+	if _, err := message.WriteTo(buffer); err != nil {
+		t.Fatalf("failed to write message: %s", err)
+	}
+	if !strings.Contains(buffer.String(), "\r\n"+line+"\r\n") {
+		t.Errorf("expected message to contain header line %q, got: %s", line, buffer.String())
+	}
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// HashAddress returns the hex-encoded SHA-256 hash of the normalized form of the given address, so
+// that analytics pipelines can match recipients without storing their plain addresses.
+//
+// The address may be given with or without display name, i. e. "Toni Tester <Toni+news@Example.com>".
+// It is normalized to its lower-cased addr-spec and the sub-address, i. e. "+news", is stripped from
+// the local part, so that all variants of an address produce the same hash. If the salt is empty,
+// the hash matches the format of hashed custom-audience uploads of advertising platforms. Otherwise,
+// the salt is prepended to the normalized address before hashing.
+//
+// Parameters:
+//   - addr: The address to hash.
+//   - salt: An optional salt that is prepended to the normalized address.
+//
+// Returns:
+//   - The lower-case hex-encoded SHA-256 hash of the normalized address.
+//   - An error if the address cannot be parsed; otherwise, returns nil.
+func HashAddress(addr, salt string) (string, error) {
+	normalized, err := normalizeAddress(addr)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(salt + normalized))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// normalizeAddress parses the given address and returns its lower-cased addr-spec without the
+// sub-address of the local part, i. e. "toni@example.com" for "Toni Tester <Toni+news@Example.com>".
+//
+// Parameters:
+//   - addr: The address to normalize.
+//
+// Returns:
+//   - The normalized addr-spec of the address.
+//   - An error if the address cannot be parsed; otherwise, returns nil.
+func normalizeAddress(addr string) (string, error) {
+	parsed, err := ParseAddressSafe(strings.TrimSpace(addr))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse address: %w", err)
+	}
+	address := strings.ToLower(parsed.Address)
+	at := strings.LastIndex(address, "@")
+	if plus := strings.Index(address[:at], "+"); plus > 0 {
+		address = address[:plus] + address[at:]
+	}
+	return address, nil
+}
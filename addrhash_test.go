@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestHashAddress(t *testing.T) {
+	plainHash := sha256.Sum256([]byte("toni.tester@example.com"))
+	saltedHash := sha256.Sum256([]byte("s3cr3ttoni.tester@example.com"))
+	tests := []struct {
+		name    string
+		addr    string
+		salt    string
+		want    string
+		wantErr bool
+	}{
+		{"plain address", "toni.tester@example.com", "", hex.EncodeToString(plainHash[:]), false},
+		{"mixed case with whitespace", "  Toni.Tester@Example.COM ", "", hex.EncodeToString(plainHash[:]), false},
+		{"sub-address", "toni.tester+news@example.com", "", hex.EncodeToString(plainHash[:]), false},
+		{"display name", "Toni Tester <Toni.Tester+a+b@example.com>", "", hex.EncodeToString(plainHash[:]), false},
+		{"salted", "toni.tester@example.com", "s3cr3t", hex.EncodeToString(saltedHash[:]), false},
+		{"invalid address", "invalid", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HashAddress(tt.addr, tt.salt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error, want error: %t, got: %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("unexpected hash, want: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"Toni+News@Example.com", "toni@example.com"},
+		{"+news@example.com", "+news@example.com"},
+		{`"toni+news@home"@example.com`, `toni@example.com`},
+	}
+	for _, tt := range tests {
+		got, err := normalizeAddress(tt.addr)
+		if err != nil {
+			t.Fatalf("failed to normalize address %s: %s", tt.addr, err)
+		}
+		if got != tt.want {
+			t.Errorf("normalizeAddress(%q) = %s, want %s", tt.addr, got, tt.want)
+		}
+	}
+}
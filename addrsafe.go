@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+)
+
+// maxSafeAddressLength is the maximum length of the input of ParseAddressSafe and
+// ParseAddressListSafe. It is well above the maximum length of a header line, but prevents
+// excessive resource usage on crafted input.
+const maxSafeAddressLength = 64 * 1024
+
+var (
+	// ErrAddressTooLong is returned by ParseAddressSafe and ParseAddressListSafe if the input
+	// exceeds the maximum length.
+	ErrAddressTooLong = errors.New("address input exceeds the maximum length")
+
+	// ErrAddressParserPanic is returned by ParseAddressSafe and ParseAddressListSafe if the parser
+	// panicked on the input.
+	ErrAddressParserPanic = errors.New("address parser panicked")
+)
+
+// ParseAddressSafe parses a single RFC 5322 address, i. e. "Toni Tester <toni@example.com>", like
+// net/mail.ParseAddress, but guarantees not to panic on arbitrary input.
+//
+// The input length is limited and any panic of the parser is recovered and returned as error, so
+// that the function can be used on untrusted input of internet-facing intake services.
+//
+// Parameters:
+//   - address: The address to parse.
+//
+// Returns:
+//   - A pointer to the parsed mail.Address.
+//   - An error if the address is invalid, too long or could not be parsed; otherwise, returns nil.
+func ParseAddressSafe(address string) (parsed *mail.Address, err error) {
+	if len(address) > maxSafeAddressLength {
+		return nil, ErrAddressTooLong
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			parsed, err = nil, fmt.Errorf("%w: %v", ErrAddressParserPanic, recovered)
+		}
+	}()
+	return mail.ParseAddress(address)
+}
+
+// ParseAddressListSafe parses a comma-separated list of RFC 5322 addresses like
+// net/mail.ParseAddressList, but guarantees not to panic on arbitrary input. The members of address
+// groups are returned as part of the list.
+//
+// Parameters:
+//   - list: The address list to parse.
+//
+// Returns:
+//   - A slice of pointers to the parsed mail.Address values.
+//   - An error if any of the addresses is invalid, the input is too long or could not be parsed;
+//     otherwise, returns nil.
+func ParseAddressListSafe(list string) (parsed []*mail.Address, err error) {
+	if len(list) > maxSafeAddressLength {
+		return nil, ErrAddressTooLong
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			parsed, err = nil, fmt.Errorf("%w: %v", ErrAddressParserPanic, recovered)
+		}
+	}()
+	return mail.ParseAddressList(list)
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseAddressSafe(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    string
+		wantErr error
+	}{
+		{"valid address", "Toni Tester <toni@example.com>", "toni@example.com", nil},
+		{"invalid address", "invalid", "", nil},
+		{"too long address", strings.Repeat("a", maxSafeAddressLength) + "@example.com", "", ErrAddressTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := ParseAddressSafe(tt.address)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error: %s, got: %s", tt.wantErr, err)
+			}
+			if tt.want == "" {
+				if err == nil {
+					t.Error("expected address to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse address: %s", err)
+			}
+			if address.Address != tt.want {
+				t.Errorf("unexpected address, want: %s, got: %s", tt.want, address.Address)
+			}
+		})
+	}
+}
+
+func TestParseAddressListSafe(t *testing.T) {
+	addresses, err := ParseAddressListSafe("alice@example.com, Team: bob@example.com, carol@example.com;")
+	if err != nil {
+		t.Fatalf("failed to parse address list: %s", err)
+	}
+	if len(addresses) != 3 {
+		t.Errorf("expected 3 addresses, got: %d", len(addresses))
+	}
+	if _, err = ParseAddressListSafe(strings.Repeat("a@b.c,", maxSafeAddressLength)); !errors.Is(err, ErrAddressTooLong) {
+		t.Errorf("expected error: %s, got: %s", ErrAddressTooLong, err)
+	}
+}
+
+func FuzzParseAddressSafe(f *testing.F) {
+	f.Add("Toni Tester <toni@example.com>")
+	f.Add(`"Doe, Jane" <jane@example.com>, Team: a@example.com, b@example.com;`)
+	f.Add("=?UTF-8?Q?Toni_T=C3=A4ster?= <toni@example.com>")
+	f.Add("(comment) <@route:toni@example.com>")
+	f.Fuzz(func(t *testing.T, data string) {
+		if _, err := ParseAddressSafe(data); errors.Is(err, ErrAddressParserPanic) {
+			t.Errorf("address parser panicked: %s", err)
+		}
+		if _, err := ParseAddressListSafe(data); errors.Is(err, ErrAddressParserPanic) {
+			t.Errorf("address list parser panicked: %s", err)
+		}
+		message := NewMsg()
+		_ = message.To(splitAddressList(data)...)
+		_ = message.addrHeaderValues(HeaderTo)
+	})
+}
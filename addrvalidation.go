@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"strings"
+)
+
+const (
+	// maxLocalPartLength is the maximum length of the local part of an address, as defined in RFC 5321.
+	maxLocalPartLength = 64
+
+	// maxDomainLength is the maximum length of the domain of an address, as defined in RFC 5321.
+	maxDomainLength = 255
+
+	// maxDomainLabelLength is the maximum length of a label of a domain, as defined in RFC 1035.
+	maxDomainLabelLength = 63
+)
+
+// AddressValidation is a type that determines how the addresses of a Msg are validated.
+type AddressValidation int
+
+const (
+	// AddressValidationLenient accepts every address that can be parsed by net/mail. This is the
+	// default.
+	AddressValidationLenient AddressValidation = iota
+
+	// AddressValidationStrict only accepts addresses that can be parsed by net/mail and that consist of
+	// a US-ASCII local part of at most 64 characters and a valid domain name or address literal, as
+	// required by RFC 5321 and RFC 5322.
+	AddressValidationStrict
+
+	// AddressValidationCustom validates the addresses with the AddressValidatorFunc that is set via
+	// WithAddressValidator.
+	AddressValidationCustom
+)
+
+// AddressValidatorFunc is a function that parses and validates the given address, i. e.
+// "Toni Tester <toni@example.com>", and returns the parsed mail.Address or an error if the address
+// is not acceptable.
+type AddressValidatorFunc func(address string) (*mail.Address, error)
+
+var (
+	// ErrAddressValidatorIsNil is returned if the AddressValidationCustom is used without an
+	// AddressValidatorFunc.
+	ErrAddressValidatorIsNil = errors.New("address validator function is nil")
+
+	// ErrStrictAddressValidation is returned if an address does not pass the AddressValidationStrict.
+	ErrStrictAddressValidation = errors.New("address does not comply with strict validation")
+)
+
+// WithAddressValidation sets the AddressValidation for the addresses of the Msg during its creation
+// or initialization.
+//
+// The AddressValidation applies to all addresses that are set on the Msg, i. e. via From, To,
+// ReplyTo or RequestMDNTo. By default, every address that can be parsed by net/mail is accepted.
+// For AddressValidationCustom, the AddressValidatorFunc has to be set via WithAddressValidator.
+//
+// Parameters:
+//   - mode: The AddressValidation to apply to the addresses of the Msg.
+//
+// Returns:
+//   - A MsgOption function that sets the AddressValidation of the Msg.
+func WithAddressValidation(mode AddressValidation) MsgOption {
+	return func(m *Msg) {
+		m.addrValidation = mode
+	}
+}
+
+// WithAddressValidator sets a custom AddressValidatorFunc for the addresses of the Msg during its
+// creation or initialization, and switches to AddressValidationCustom.
+//
+// This allows to accept addresses that are technically invalid but still deliverable, i. e. legacy
+// addresses with consecutive dots in the local part, without dropping them silently like the
+// IgnoreInvalid methods do.
+//
+// Parameters:
+//   - validator: The AddressValidatorFunc that parses and validates each address of the Msg.
+//
+// Returns:
+//   - A MsgOption function that sets the AddressValidatorFunc of the Msg.
+func WithAddressValidator(validator AddressValidatorFunc) MsgOption {
+	return func(m *Msg) {
+		m.addrValidation = AddressValidationCustom
+		m.addrValidator = validator
+	}
+}
+
+// String satisfies the fmt.Stringer interface for the AddressValidation type.
+//
+// Returns:
+//   - A string representation of the AddressValidation.
+func (v AddressValidation) String() string {
+	switch v {
+	case AddressValidationLenient:
+		return "AddressValidationLenient"
+	case AddressValidationStrict:
+		return "AddressValidationStrict"
+	case AddressValidationCustom:
+		return "AddressValidationCustom"
+	default:
+		return "UnknownAddressValidation"
+	}
+}
+
+// parseAddress parses and validates the given address according to the AddressValidation of the Msg.
+func (m *Msg) parseAddress(addr string) (*mail.Address, error) {
+	switch m.addrValidation {
+	case AddressValidationCustom:
+		if m.addrValidator == nil {
+			return nil, ErrAddressValidatorIsNil
+		}
+		address, err := m.addrValidator(addr)
+		if err != nil {
+			return nil, err
+		}
+		if address == nil {
+			return nil, fmt.Errorf("address validator returned no address for %q", addr)
+		}
+		return address, nil
+	case AddressValidationStrict:
+		address, err := mail.ParseAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		if err = validateStrictAddress(address.Address); err != nil {
+			return nil, err
+		}
+		return address, nil
+	default:
+		return mail.ParseAddress(addr)
+	}
+}
+
+// validateStrictAddress checks that the given addr-spec consists of a US-ASCII local part of at most
+// maxLocalPartLength characters and a valid domain name or address literal.
+func validateStrictAddress(addrSpec string) error {
+	at := strings.LastIndex(addrSpec, "@")
+	if at < 0 {
+		return fmt.Errorf("%w: %q has no domain", ErrStrictAddressValidation, addrSpec)
+	}
+	localPart, domain := addrSpec[:at], addrSpec[at+1:]
+	if len(localPart) > maxLocalPartLength {
+		return fmt.Errorf("%w: local part of %q exceeds %d characters", ErrStrictAddressValidation,
+			addrSpec, maxLocalPartLength)
+	}
+	for i := 0; i < len(localPart); i++ {
+		if localPart[i] >= 0x80 {
+			return fmt.Errorf("%w: local part of %q contains non-ASCII characters", ErrStrictAddressValidation,
+				addrSpec)
+		}
+	}
+	if strings.HasPrefix(domain, "[") && strings.HasSuffix(domain, "]") {
+		literal := strings.TrimPrefix(domain[1:len(domain)-1], "IPv6:")
+		if net.ParseIP(literal) == nil {
+			return fmt.Errorf("%w: invalid address literal in %q", ErrStrictAddressValidation, addrSpec)
+		}
+		return nil
+	}
+	if !isDomainName(domain) {
+		return fmt.Errorf("%w: invalid domain in %q", ErrStrictAddressValidation, addrSpec)
+	}
+	return nil
+}
+
+// isDomainName returns true if the given domain consists of labels of letters, digits and hyphens,
+// that do not start or end with a hyphen, as defined in RFC 1035.
+func isDomainName(domain string) bool {
+	if domain == "" || len(domain) > maxDomainLength {
+		return false
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || len(label) > maxDomainLabelLength ||
+			strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for i := 0; i < len(label); i++ {
+			char := label[i]
+			if (char < 'a' || char > 'z') && (char < 'A' || char > 'Z') && (char < '0' || char > '9') &&
+				char != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
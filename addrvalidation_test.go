@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestWithAddressValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    AddressValidation
+		addr    string
+		wantErr bool
+	}{
+		{"lenient valid address", AddressValidationLenient, "toni.tester@example.com", false},
+		{"lenient UTF-8 local part", AddressValidationLenient, "tönï@example.com", false},
+		{"lenient invalid address", AddressValidationLenient, "toni..tester@example.com", true},
+		{"strict valid address", AddressValidationStrict, "Toni Tester <toni.tester@example.com>", false},
+		{"strict address literal", AddressValidationStrict, "toni@[192.0.2.1]", false},
+		{"strict UTF-8 local part", AddressValidationStrict, "tönï@example.com", true},
+		{"strict too long local part", AddressValidationStrict, strings.Repeat("a", 65) + "@example.com", true},
+		{"strict invalid domain", AddressValidationStrict, "toni@exa_mple.com", true},
+		{"strict domain with leading hyphen", AddressValidationStrict, "toni@-example.com", true},
+		{"strict invalid address", AddressValidationStrict, "invalid", true},
+		{"custom without validator", AddressValidationCustom, "toni@example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := NewMsg(WithAddressValidation(tt.mode))
+			if message.addrValidation != tt.mode {
+				t.Errorf("expected address validation: %s, got: %s", tt.mode, message.addrValidation)
+			}
+			err := message.To(tt.addr)
+			if tt.wantErr && err == nil {
+				t.Errorf("expected address %q to fail validation", tt.addr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected address %q to pass validation, got: %s", tt.addr, err)
+			}
+		})
+	}
+	t.Run("strict validation applies to groups and reply-to", func(t *testing.T) {
+		message := NewMsg(WithAddressValidation(AddressValidationStrict))
+		if err := message.To("Team: toni@example.com, tönï@example.com;"); err == nil {
+			t.Error("expected group with invalid member to fail validation")
+		}
+		if err := message.ReplyTo("tönï@example.com"); !errors.Is(err, ErrStrictAddressValidation) {
+			t.Errorf("expected error: %s, got: %s", ErrStrictAddressValidation, err)
+		}
+	})
+}
+
+func TestWithAddressValidator(t *testing.T) {
+	legacyValidator := func(address string) (*mail.Address, error) {
+		if parsed, err := mail.ParseAddress(address); err == nil {
+			return parsed, nil
+		}
+		if strings.Count(address, "@") != 1 {
+			return nil, errors.New("legacy address must contain exactly one @")
+		}
+		return &mail.Address{Address: address}, nil
+	}
+	t.Run("legacy addresses are accepted", func(t *testing.T) {
+		message := NewMsg(WithAddressValidator(legacyValidator))
+		if message.addrValidation != AddressValidationCustom {
+			t.Errorf("expected address validation: %s, got: %s", AddressValidationCustom,
+				message.addrValidation)
+		}
+		if err := message.To("toni..tester@example.com", "valid@example.com"); err != nil {
+			t.Fatalf("failed to set legacy address: %s", err)
+		}
+		recipients, err := message.GetRecipients()
+		if err != nil {
+			t.Fatalf("failed to get recipients: %s", err)
+		}
+		if len(recipients) != 2 || recipients[0] != "toni..tester@example.com" {
+			t.Errorf("unexpected recipients: %v", recipients)
+		}
+	})
+	t.Run("validator errors are returned", func(t *testing.T) {
+		message := NewMsg(WithAddressValidator(legacyValidator))
+		if err := message.To("invalid"); err == nil {
+			t.Error("expected address to fail custom validation")
+		}
+	})
+	t.Run("validator without address", func(t *testing.T) {
+		message := NewMsg(WithAddressValidator(func(string) (*mail.Address, error) { return nil, nil }))
+		if err := message.To("toni@example.com"); err == nil {
+			t.Error("expected address to fail custom validation")
+		}
+	})
+	t.Run("nil validator", func(t *testing.T) {
+		message := NewMsg(WithAddressValidator(nil))
+		if err := message.From("toni@example.com"); !errors.Is(err, ErrAddressValidatorIsNil) {
+			t.Errorf("expected error: %s, got: %s", ErrAddressValidatorIsNil, err)
+		}
+	})
+}
+
+func TestAddressValidation_String(t *testing.T) {
+	tests := []struct {
+		mode AddressValidation
+		want string
+	}{
+		{AddressValidationLenient, "AddressValidationLenient"},
+		{AddressValidationStrict, "AddressValidationStrict"},
+		{AddressValidationCustom, "AddressValidationCustom"},
+		{AddressValidation(99), "UnknownAddressValidation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.mode.String(); got != tt.want {
+				t.Errorf("expected string: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
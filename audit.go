@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MiddlewareAuditStamp is the MiddlewareType of the Middleware added by WithAuditStamp.
+const MiddlewareAuditStamp MiddlewareType = "audit-stamp"
+
+// auditStampVersion is the version of the audit stamp format.
+const auditStampVersion = "1"
+
+var (
+	// ErrAuditStampMissing is returned if a Msg does not have an "X-Mailer-Audit" header.
+	ErrAuditStampMissing = errors.New("audit stamp header is missing")
+
+	// ErrAuditStampMalformed is returned if the "X-Mailer-Audit" header of a Msg cannot be parsed.
+	ErrAuditStampMalformed = errors.New("audit stamp header is malformed")
+
+	// ErrAuditStampInvalid is returned if the signature of the "X-Mailer-Audit" header of a Msg
+	// does not match.
+	ErrAuditStampInvalid = errors.New("audit stamp signature is invalid")
+)
+
+type (
+	// AuditStamp represents the verified content of an "X-Mailer-Audit" header.
+	AuditStamp struct {
+		// AppID is the identifier of the application that emitted the Msg.
+		AppID string
+
+		// MessageID is the "Message-ID" of the Msg that the stamp was created for.
+		MessageID string
+
+		// Timestamp is the point in time at which the Msg was stamped.
+		Timestamp time.Time
+	}
+
+	// auditStampMiddleware is the Middleware that adds the "X-Mailer-Audit" header to a Msg.
+	auditStampMiddleware struct {
+		appID string
+		key   []byte
+		now   func() time.Time
+	}
+)
+
+// WithAuditStamp adds a Middleware to the Msg that stamps it with a signed "X-Mailer-Audit" header,
+// so that outbound mail can later be attributed to the emitting application.
+//
+// The header holds the application ID, the time of stamping and a HMAC-SHA256 signature over the
+// "Message-ID" of the Msg, the timestamp and the application ID. If the Msg does not have a
+// "Message-ID" when it is written, one is generated. Whitespace, control characters and semicolons
+// are removed from the application ID. A stamped Msg can be verified with VerifyAuditStamp using
+// the same key.
+//
+// Parameters:
+//   - appID: The identifier of the application that emits the Msg.
+//   - key: The secret key for the HMAC signature.
+//
+// Returns:
+//   - A MsgOption function that adds the audit stamp Middleware to the Msg.
+func WithAuditStamp(appID string, key []byte) MsgOption {
+	return WithMiddleware(&auditStampMiddleware{appID: sanitizeAuditAppID(appID), key: key, now: time.Now})
+}
+
+// VerifyAuditStamp verifies the "X-Mailer-Audit" header of the given Msg with the given key.
+//
+// This is typically used during incident response on a Msg that was parsed with one of the EML
+// functions, to find out which application emitted it and when.
+//
+// Parameters:
+//   - msg: The Msg with the "X-Mailer-Audit" header to verify.
+//   - key: The secret key that was used to create the audit stamp.
+//
+// Returns:
+//   - The AuditStamp with the verified content of the header.
+//   - An error if the header is missing, malformed or its signature does not match.
+func VerifyAuditStamp(msg *Msg, key []byte) (AuditStamp, error) {
+	values := msg.GetGenHeader(HeaderXMailerAudit)
+	if len(values) == 0 {
+		return AuditStamp{}, ErrAuditStampMissing
+	}
+	fields := make(map[string]string)
+	for _, field := range strings.Split(values[0], ";") {
+		pair := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(pair) != 2 {
+			return AuditStamp{}, ErrAuditStampMalformed
+		}
+		fields[pair[0]] = pair[1]
+	}
+	if fields["v"] != auditStampVersion || fields["app"] == "" || fields["sig"] == "" {
+		return AuditStamp{}, ErrAuditStampMalformed
+	}
+	unixTime, err := strconv.ParseInt(fields["t"], 10, 64)
+	if err != nil {
+		return AuditStamp{}, ErrAuditStampMalformed
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(fields["sig"])
+	if err != nil {
+		return AuditStamp{}, ErrAuditStampMalformed
+	}
+	stamp := AuditStamp{AppID: fields["app"], MessageID: msg.GetMessageID(), Timestamp: time.Unix(unixTime, 0)}
+	if !hmac.Equal(signature, stamp.signature(key)) {
+		return AuditStamp{}, ErrAuditStampInvalid
+	}
+	return stamp, nil
+}
+
+// Handle adds the "X-Mailer-Audit" header to the Msg and satisfies the Middleware interface.
+//
+// Parameters:
+//   - msg: The Msg to stamp.
+//
+// Returns:
+//   - The stamped Msg.
+func (a *auditStampMiddleware) Handle(msg *Msg) *Msg {
+	if msg.GetMessageID() == "" {
+		msg.SetMessageID()
+	}
+	stamp := AuditStamp{AppID: a.appID, MessageID: msg.GetMessageID(), Timestamp: a.now()}
+	msg.SetGenHeader(HeaderXMailerAudit, fmt.Sprintf("v=%s; app=%s; t=%d; sig=%s", auditStampVersion,
+		stamp.AppID, stamp.Timestamp.Unix(), base64.RawURLEncoding.EncodeToString(stamp.signature(a.key))))
+	return msg
+}
+
+// Type returns the MiddlewareType of the audit stamp Middleware.
+//
+// Returns:
+//   - The MiddlewareAuditStamp type.
+func (a *auditStampMiddleware) Type() MiddlewareType {
+	return MiddlewareAuditStamp
+}
+
+// signature returns the HMAC-SHA256 signature of the AuditStamp for the given key.
+func (s AuditStamp) signature(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = fmt.Fprintf(mac, "%s\n%d\n%s", s.MessageID, s.Timestamp.Unix(), s.AppID)
+	return mac.Sum(nil)
+}
+
+// sanitizeAuditAppID removes whitespace, control characters and semicolons from the application ID.
+func sanitizeAuditAppID(appID string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ';' || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, appID)
+}
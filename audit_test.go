@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithAuditStamp(t *testing.T) {
+	key := []byte("audit-secret")
+	newStampedMessage := func(t *testing.T) *Msg {
+		t.Helper()
+		message := NewMsg(WithAuditStamp("billing service;", key))
+		if err := message.From(TestSenderValid); err != nil {
+			t.Fatalf("failed to set sender address: %s", err)
+		}
+		if err := message.To(TestRcptValid); err != nil {
+			t.Fatalf("failed to set recipient address: %s", err)
+		}
+		message.Subject("Testmail")
+		message.SetBodyString(TypeTextPlain, "Testmail")
+		return message
+	}
+	t.Run("stamp is added and verified after parsing", func(t *testing.T) {
+		message := newStampedMessage(t)
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if !strings.Contains(buffer.String(), "X-Mailer-Audit: v=1; app=billingservice; t=") {
+			t.Errorf("expected audit stamp header in message, got:\n%s", buffer.String())
+		}
+		parsed, err := EMLToMsgFromReader(buffer)
+		if err != nil {
+			t.Fatalf("failed to parse message: %s", err)
+		}
+		stamp, err := VerifyAuditStamp(parsed, key)
+		if err != nil {
+			t.Fatalf("failed to verify audit stamp: %s", err)
+		}
+		if stamp.AppID != "billingservice" {
+			t.Errorf("expected app ID: %s, got: %s", "billingservice", stamp.AppID)
+		}
+		if stamp.MessageID != message.GetMessageID() {
+			t.Errorf("expected message ID: %s, got: %s", message.GetMessageID(), stamp.MessageID)
+		}
+		if time.Since(stamp.Timestamp) > time.Minute {
+			t.Errorf("expected recent timestamp, got: %s", stamp.Timestamp)
+		}
+	})
+	t.Run("stamp uses timestamp of middleware clock", func(t *testing.T) {
+		message := newStampedMessage(t)
+		message.SetMessageIDWithValue("audit@domain.tld")
+		middleware, ok := message.middlewares[0].(*auditStampMiddleware)
+		if !ok {
+			t.Fatalf("expected audit stamp middleware, got: %T", message.middlewares[0])
+		}
+		middleware.now = func() time.Time { return time.Unix(1727784000, 0) }
+		message = message.applyMiddlewares(message)
+		stamp, err := VerifyAuditStamp(message, key)
+		if err != nil {
+			t.Fatalf("failed to verify audit stamp: %s", err)
+		}
+		if stamp.Timestamp.Unix() != 1727784000 {
+			t.Errorf("expected timestamp: %d, got: %d", 1727784000, stamp.Timestamp.Unix())
+		}
+		if middleware.Type() != MiddlewareAuditStamp {
+			t.Errorf("expected middleware type: %s, got: %s", MiddlewareAuditStamp, middleware.Type())
+		}
+	})
+	t.Run("verification fails on changed message ID", func(t *testing.T) {
+		message := newStampedMessage(t)
+		message = message.applyMiddlewares(message)
+		message.SetMessageIDWithValue("forged@domain.tld")
+		if _, err := VerifyAuditStamp(message, key); !errors.Is(err, ErrAuditStampInvalid) {
+			t.Errorf("expected error: %s, got: %s", ErrAuditStampInvalid, err)
+		}
+	})
+	t.Run("verification fails with wrong key", func(t *testing.T) {
+		message := newStampedMessage(t)
+		message = message.applyMiddlewares(message)
+		if _, err := VerifyAuditStamp(message, []byte("wrong")); !errors.Is(err, ErrAuditStampInvalid) {
+			t.Errorf("expected error: %s, got: %s", ErrAuditStampInvalid, err)
+		}
+	})
+	t.Run("verification fails on missing or malformed header", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			header string
+			want   error
+		}{
+			{"missing header", "", ErrAuditStampMissing},
+			{"field without value", "v=1; app", ErrAuditStampMalformed},
+			{"unknown version", "v=2; app=test; t=1; sig=AA", ErrAuditStampMalformed},
+			{"invalid timestamp", "v=1; app=test; t=now; sig=AA", ErrAuditStampMalformed},
+			{"invalid signature encoding", "v=1; app=test; t=1; sig=!!", ErrAuditStampMalformed},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				message := testMessage(t)
+				if tt.header != "" {
+					message.SetGenHeader(HeaderXMailerAudit, tt.header)
+				}
+				if _, err := VerifyAuditStamp(message, key); !errors.Is(err, tt.want) {
+					t.Errorf("expected error: %s, got: %s", tt.want, err)
+				}
+			})
+		}
+	})
+}
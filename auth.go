@@ -205,3 +205,24 @@ func (sa *SMTPAuthType) UnmarshalString(value string) error {
 	}
 	return nil
 }
+
+// AuthQuirk is a type of bit flags that adjust the SMTP authentication of the Client for servers that
+// do not strictly follow the specification of an authentication mechanism.
+type AuthQuirk int
+
+const (
+	// LoginEmptyPromptTolerant makes the LOGIN authentication tolerant to nonstandard prompts of the
+	// server. The credentials are sent based on the content of the prompts where possible, and empty
+	// or additional prompts after the credentials have been sent are answered with an empty response
+	// instead of failing the authentication.
+	LoginEmptyPromptTolerant AuthQuirk = 1 << iota
+
+	// UpperCaseUsername converts the username to upper case before it is sent to the server, for
+	// servers that only accept upper-case usernames, i. e. with CRAM-MD5 or LOGIN.
+	UpperCaseUsername
+)
+
+// has returns true if all the given AuthQuirk flags are set.
+func (q AuthQuirk) has(quirk AuthQuirk) bool {
+	return q&quirk == quirk
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// DefaultBodyCacheEntries is the default maximum number of encoded bodies held by a BodyCache.
+const DefaultBodyCacheEntries = 256
+
+type (
+	// BodyCache holds the encoded content of the body parts of messages, so that identical bodies that
+	// are shared by many messages are only encoded once.
+	//
+	// For bulk sends, many recipients often receive identical content, i. e. all recipients of the same
+	// locale or segment of a campaign. The content of each part is still rendered for every Msg, but
+	// its quoted-printable or Base64 encoding is looked up by the SHA-256 hash of the rendered content
+	// and the Encoding. The least recently used entries are evicted once the maximum number of entries
+	// is reached. A BodyCache is safe for concurrent use and is usually shared by all messages of a
+	// bulk send with WithBodyCache.
+	BodyCache struct {
+		entries    map[bodyCacheKey]*list.Element
+		maxEntries int
+		mutex      sync.Mutex
+		order      *list.List
+	}
+
+	// bodyCacheKey is the key of an encoded body in the BodyCache.
+	bodyCacheKey [sha256.Size]byte
+
+	// bodyCacheEntry is an encoded body in the BodyCache.
+	bodyCacheEntry struct {
+		encoded []byte
+		key     bodyCacheKey
+	}
+)
+
+// NewBodyCache returns a new BodyCache that holds up to the given number of encoded bodies.
+//
+// Parameters:
+//   - maxEntries: The maximum number of encoded bodies. If it is zero or less,
+//     DefaultBodyCacheEntries is used.
+//
+// Returns:
+//   - A pointer to the new BodyCache.
+func NewBodyCache(maxEntries int) *BodyCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultBodyCacheEntries
+	}
+	return &BodyCache{
+		entries:    make(map[bodyCacheKey]*list.Element),
+		maxEntries: maxEntries,
+		order:      list.New(),
+	}
+}
+
+// WithBodyCache sets the BodyCache that the encoded content of the body parts of the Msg is looked up
+// in and added to, when the Msg is written.
+//
+// Only quoted-printable and Base64 encoded parts are cached, since the content of the other parts is
+// written as it is. Attachments and embeds are not cached.
+//
+// Parameters:
+//   - cache: The BodyCache to use, which is usually shared by many messages.
+//
+// Returns:
+//   - A MsgOption function that sets the BodyCache of the Msg.
+func WithBodyCache(cache *BodyCache) MsgOption {
+	return func(m *Msg) {
+		m.bodyCache = cache
+	}
+}
+
+// Len returns the number of encoded bodies held by the BodyCache.
+//
+// Returns:
+//   - The number of entries of the BodyCache.
+func (c *BodyCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+// get returns the encoded body of the given key and marks it as recently used.
+func (c *BodyCache) get(key bodyCacheKey) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*bodyCacheEntry).encoded, true
+}
+
+// add adds the encoded body of the given key and evicts the least recently used entry, if the
+// maximum number of entries is exceeded.
+func (c *BodyCache) add(key bodyCacheKey, encoded []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&bodyCacheEntry{encoded: encoded, key: key})
+	if c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*bodyCacheEntry).key)
+	}
+}
+
+// newBodyCacheKey returns the key of the given body content with the given encoding.
+func newBodyCacheKey(encoding Encoding, content []byte) bodyCacheKey {
+	hash := sha256.New()
+	_, _ = hash.Write([]byte(encoding.String()))
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write(content)
+	var key bodyCacheKey
+	copy(key[:], hash.Sum(nil))
+	return key
+}
+
+// writeCachedBody writes the body content of the given write function with the given encoding, like
+// writeBody, but looks up the encoded content in the BodyCache of the msgWriter first. On a cache miss,
+// the content is encoded and added to the BodyCache.
+//
+// Parameters:
+//   - writeFunc: A function that writes the body content to the given io.Writer.
+//   - encoding: The encoding type to use when writing the content.
+func (mw *msgWriter) writeCachedBody(writeFunc func(io.Writer) (int64, error), encoding Encoding) {
+	if mw.err != nil {
+		return
+	}
+	content := bytes.NewBuffer(nil)
+	if _, err := writeFunc(content); err != nil {
+		mw.err = fmt.Errorf("bodyWriter function: %w", err)
+		return
+	}
+	key := newBodyCacheKey(encoding, content.Bytes())
+	encoded, ok := mw.bodyCache.get(key)
+	if !ok {
+		buffer := bytes.NewBuffer(nil)
+		encoder := &msgWriter{writer: buffer}
+		encoder.writeBody(writeFuncFromBuffer(content), encoding)
+		if encoder.err != nil {
+			mw.err = encoder.err
+			return
+		}
+		encoded = buffer.Bytes()
+		mw.bodyCache.add(key, encoded)
+	}
+	mw.writeBody(writeFuncFromBuffer(bytes.NewBuffer(encoded)), NoEncoding)
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewBodyCache(t *testing.T) {
+	if cache := NewBodyCache(0); cache.maxEntries != DefaultBodyCacheEntries {
+		t.Errorf("expected %d max entries, got: %d", DefaultBodyCacheEntries, cache.maxEntries)
+	}
+	if cache := NewBodyCache(10); cache.maxEntries != 10 {
+		t.Errorf("expected 10 max entries, got: %d", cache.maxEntries)
+	}
+}
+
+func TestWithBodyCache(t *testing.T) {
+	body := strings.Repeat("Grüße aus dem Newsletter = ", 20)
+	tests := []struct {
+		name     string
+		encoding Encoding
+		html     bool
+	}{
+		{"quoted-printable single part", EncodingQP, false},
+		{"base64 single part", EncodingB64, false},
+		{"quoted-printable alternative parts", EncodingQP, true},
+		{"base64 alternative parts", EncodingB64, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewBodyCache(0)
+			write := func(opts ...MsgOption) string {
+				message := testMessage(t, append(opts, WithEncoding(tt.encoding), WithBoundary("boundary"))...)
+				message.SetMessageIDWithValue("cache@example.com")
+				message.SetDateWithValue(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+				message.SetBodyString(TypeTextPlain, body)
+				if tt.html {
+					message.AddAlternativeString(TypeTextHTML, "<p>"+body+"</p>")
+				}
+				buffer := bytes.NewBuffer(nil)
+				n, err := message.WriteTo(buffer)
+				if err != nil {
+					t.Fatalf("failed to write message: %s", err)
+				}
+				if n != int64(buffer.Len()) {
+					t.Errorf("expected %d bytes written, got: %d", buffer.Len(), n)
+				}
+				return buffer.String()
+			}
+			want := write()
+			for i := 0; i < 3; i++ {
+				if got := write(WithBodyCache(cache)); got != want {
+					t.Errorf("expected cached output to match, got:\n%s\nwant:\n%s", got, want)
+				}
+			}
+			entries := 1
+			if tt.html {
+				entries = 2
+			}
+			if cache.Len() != entries {
+				t.Errorf("expected %d cache entries, got: %d", entries, cache.Len())
+			}
+		})
+	}
+	t.Run("cached encoding is used", func(t *testing.T) {
+		cache := NewBodyCache(0)
+		cache.add(newBodyCacheKey(EncodingQP, []byte("Testmail")), []byte("cached encoding"))
+		message := testMessage(t, WithBodyCache(cache))
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if !strings.HasSuffix(buffer.String(), "\r\n\r\ncached encoding") {
+			t.Errorf("expected the cached encoding to be written, got:\n%s", buffer.String())
+		}
+	})
+	t.Run("unencoded parts are not cached", func(t *testing.T) {
+		cache := NewBodyCache(0)
+		message := testMessage(t, WithBodyCache(cache), WithEncoding(NoEncoding))
+		if _, err := message.WriteTo(io.Discard); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if cache.Len() != 0 {
+			t.Errorf("expected no cache entries, got: %d", cache.Len())
+		}
+	})
+	t.Run("write function fails", func(t *testing.T) {
+		message := testMessage(t, WithBodyCache(NewBodyCache(0)))
+		message.SetBodyWriter(TypeTextPlain, func(io.Writer) (int64, error) {
+			return 0, errors.New("intentional failure")
+		})
+		if _, err := message.WriteTo(io.Discard); err == nil {
+			t.Error("expected write function error")
+		}
+	})
+	t.Run("concurrent use", func(t *testing.T) {
+		cache := NewBodyCache(2)
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				message := NewMsg(WithBodyCache(cache))
+				message.SetBodyString(TypeTextPlain, fmt.Sprintf("Segment %d", i%4))
+				if _, err := message.WriteTo(io.Discard); err != nil {
+					t.Errorf("failed to write message: %s", err)
+				}
+			}(i)
+		}
+		wg.Wait()
+		if cache.Len() != 2 {
+			t.Errorf("expected 2 cache entries, got: %d", cache.Len())
+		}
+	})
+}
+
+func TestBodyCache_eviction(t *testing.T) {
+	cache := NewBodyCache(2)
+	first, second, third := newBodyCacheKey(EncodingQP, []byte("1")), newBodyCacheKey(EncodingQP, []byte("2")),
+		newBodyCacheKey(EncodingQP, []byte("3"))
+	cache.add(first, []byte("1"))
+	cache.add(second, []byte("2"))
+	if _, ok := cache.get(first); !ok {
+		t.Fatal("expected first entry to be cached")
+	}
+	cache.add(third, []byte("3"))
+	if _, ok := cache.get(second); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	for _, key := range []bodyCacheKey{first, third} {
+		if _, ok := cache.get(key); !ok {
+			t.Error("expected recently used entries to be cached")
+		}
+	}
+	if newBodyCacheKey(EncodingQP, []byte("1")) == newBodyCacheKey(EncodingB64, []byte("1")) {
+		t.Error("expected keys of different encodings to differ")
+	}
+}
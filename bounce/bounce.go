@@ -0,0 +1,400 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+// Package bounce classifies incoming bounce messages as hard or soft bounces and extracts the
+// failed recipients, their diagnostic codes and the Message-ID of the original message
+package bounce
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	netmail "net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+
+	"github.com/wneessen/go-mail"
+)
+
+const (
+	// ClassUnknown is the Class of a bounce whose status cannot be determined
+	ClassUnknown Class = iota
+
+	// ClassSoft is the Class of a temporary failure, i. e. a full mailbox or a greylisted delivery,
+	// after which the recipient may be retried
+	ClassSoft
+
+	// ClassHard is the Class of a permanent failure, i. e. an unknown recipient, after which the
+	// recipient should not be retried
+	ClassHard
+)
+
+// ErrNotBounce is returned if a message is not a bounce or does not report any failed recipient
+var ErrNotBounce = errors.New("message is not a bounce")
+
+var (
+	// enhancedStatusPattern matches an enhanced status code as defined in RFC 3463
+	enhancedStatusPattern = regexp.MustCompile(`\b[245]\.\d{1,3}\.\d{1,3}\b`)
+
+	// replyCodePattern matches an SMTP reply code that indicates a failure
+	replyCodePattern = regexp.MustCompile(`(?:^|\s)([45]\d\d)[\s-]`)
+
+	// qmailRecipientPattern matches the recipient lines of a qmail bounce
+	qmailRecipientPattern = regexp.MustCompile(`(?m)^<([^<>\s]+@[^<>\s]+)>:\s*$`)
+
+	// eximRecipientPattern matches the first recipient of the failed addresses of an Exim bounce
+	eximRecipientPattern = regexp.MustCompile(
+		`(?i)following address(?:\(es\)|es)? failed:\s+<?([^<>\s]+@[^<>\s]+?)>?\s`)
+
+	// rcptToPattern matches the recipient of a rejected RCPT TO command
+	rcptToPattern = regexp.MustCompile(`(?i)RCPT TO:\s*<([^<>\s]+@[^<>\s]+)>`)
+
+	// messageIDPattern matches the Message-ID header of an original message that is quoted in the
+	// text of a bounce
+	messageIDPattern = regexp.MustCompile(`(?mi)^Message-ID:\s*(<[^<>\s]+>)`)
+
+	// subjectPattern matches the subjects of common non-standard bounces
+	subjectPattern = regexp.MustCompile(`(?i)undeliver|delivery (status notification|failure|has failed)|` +
+		`mail delivery failed|failure notice|returned mail|delivery problem`)
+
+	// softPhrases are phrases that indicate a temporary failure in the text of a bounce
+	softPhrases = []string{
+		"temporar", "try again", "will retry", "delayed", "mailbox full", "mailbox is full",
+		"over quota", "quota exceeded", "insufficient storage", "greylist", "graylist",
+	}
+
+	// hardPhrases are phrases that indicate a permanent failure in the text of a bounce
+	hardPhrases = []string{
+		"permanent", "user unknown", "unknown user", "no such user", "does not exist",
+		"mailbox unavailable", "address rejected", "invalid recipient", "recipient not found",
+	}
+)
+
+// Class is the classification of a Bounce
+type Class int
+
+// Bounce holds the delivery failure of a single recipient, as reported by a bounce message
+type Bounce struct {
+	// Action is the action of a delivery status notification, i. e. "failed" or "delayed". It is
+	// empty for non-standard bounces.
+	Action mail.DSNAction
+
+	// Class is the classification of the failure
+	Class Class
+
+	// DiagnosticCode is the reply of the remote server, i. e. "smtp; 550 5.1.1 User unknown". It is
+	// empty if the bounce does not report it.
+	DiagnosticCode string
+
+	// OriginalMessageID is the Message-ID of the bounced message, including the angle brackets. A tag
+	// set with mail.WithMessageIDTag can be extracted from it with mail.MessageIDTag. It is empty if
+	// the bounce does not reference the bounced message.
+	OriginalMessageID string
+
+	// Recipient is the address of the failed recipient
+	Recipient string
+
+	// Standard indicates that the Bounce has been read from a multipart/report delivery status
+	// notification as defined in RFC 3464, instead of from the text of a non-standard bounce
+	Standard bool
+
+	// Status is the enhanced status code of the failure, i. e. "5.1.1". It is empty if the bounce
+	// does not report it.
+	Status string
+}
+
+// Parse parses the given EML with mail.EMLToMsgFromReader and the given options, and returns the
+// bounces it reports, as ParseMsg does
+func Parse(reader io.Reader, opts ...mail.EMLOption) ([]Bounce, error) {
+	msg, err := mail.EMLToMsgFromReader(reader, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bounce message: %w", err)
+	}
+	return ParseMsg(msg)
+}
+
+// ParseMsg returns a Bounce for each failed or delayed recipient of the given bounce message
+//
+// A multipart/report delivery status notification is read from its message/delivery-status part.
+// Other messages are recognized as non-standard bounces by their sender or subject, and the
+// recipients and codes are extracted from their text, as written by common mail servers like qmail
+// and Exim. ErrNotBounce is returned if the message is not a bounce or does not report any failed
+// recipient.
+func ParseMsg(msg *mail.Msg) ([]Bounce, error) {
+	originalID := originalMessageID(msg)
+	for _, file := range msg.GetAttachments() {
+		if !strings.EqualFold(string(file.ContentType), "message/delivery-status") &&
+			!strings.EqualFold(string(file.ContentType), "message/global-delivery-status") {
+			continue
+		}
+		content := bytes.NewBuffer(nil)
+		if _, err := file.Writer(content); err != nil {
+			return nil, fmt.Errorf("failed to read delivery status: %w", err)
+		}
+		bounces, err := parseDeliveryStatus(content, originalID)
+		if err != nil {
+			return nil, err
+		}
+		if len(bounces) == 0 {
+			return nil, ErrNotBounce
+		}
+		return bounces, nil
+	}
+	return parseNonStandard(msg, originalID)
+}
+
+// String satisfies the fmt.Stringer interface for the Class type
+func (c Class) String() string {
+	switch c {
+	case ClassSoft:
+		return "soft"
+	case ClassHard:
+		return "hard"
+	default:
+		return "unknown"
+	}
+}
+
+// parseDeliveryStatus returns a Bounce for each failed or delayed recipient of the given
+// message/delivery-status content
+func parseDeliveryStatus(content io.Reader, originalID string) ([]Bounce, error) {
+	reader := textproto.NewReader(bufio.NewReader(content))
+	// The first block holds the per-message fields, which are not needed
+	if _, err := reader.ReadMIMEHeader(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to parse delivery status: %w", err)
+	}
+	var bounces []Bounce
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse delivery status: %w", err)
+		}
+		action := mail.DSNAction(strings.ToLower(strings.TrimSpace(fields.Get("Action"))))
+		if action == mail.DSNActionFailed || action == mail.DSNActionDelayed {
+			bounce := Bounce{
+				Action:            action,
+				DiagnosticCode:    strings.TrimSpace(fields.Get("Diagnostic-Code")),
+				OriginalMessageID: originalID,
+				Recipient:         fieldAddress(fields.Get("Final-Recipient")),
+				Standard:          true,
+				Status:            strings.TrimSpace(fields.Get("Status")),
+			}
+			if bounce.Recipient == "" {
+				bounce.Recipient = fieldAddress(fields.Get("Original-Recipient"))
+			}
+			bounce.Class = classify(bounce.Status, bounce.DiagnosticCode)
+			if action == mail.DSNActionDelayed {
+				bounce.Class = ClassSoft
+			}
+			bounces = append(bounces, bounce)
+		}
+		if err != nil {
+			return bounces, nil
+		}
+	}
+}
+
+// parseNonStandard returns a Bounce for each failed recipient that is found in the text of the
+// given non-standard bounce message
+func parseNonStandard(msg *mail.Msg, originalID string) ([]Bounce, error) {
+	if !isBounce(msg) {
+		return nil, ErrNotBounce
+	}
+	text := bodyText(msg)
+
+	var recipients []string
+	for _, match := range qmailRecipientPattern.FindAllStringSubmatch(text, -1) {
+		recipients = append(recipients, match[1])
+	}
+	for _, pattern := range []*regexp.Regexp{eximRecipientPattern, rcptToPattern} {
+		if len(recipients) > 0 {
+			break
+		}
+		if match := pattern.FindStringSubmatch(text); match != nil {
+			recipients = append(recipients, match[1])
+		}
+	}
+	if len(recipients) == 0 {
+		return nil, ErrNotBounce
+	}
+
+	status := enhancedStatusPattern.FindString(text)
+	diagnosticCode := diagnosticLine(text)
+	class := classify(status, diagnosticCode)
+	if class == ClassUnknown {
+		class = classifyText(text)
+	}
+	bounces := make([]Bounce, 0, len(recipients))
+	for _, recipient := range recipients {
+		bounces = append(bounces, Bounce{
+			Class: class, DiagnosticCode: diagnosticCode, OriginalMessageID: originalID,
+			Recipient: recipient, Status: status,
+		})
+	}
+	return bounces, nil
+}
+
+// isBounce reports whether the given message is sent by a mailer daemon or has the subject of a
+// common non-standard bounce
+func isBounce(msg *mail.Msg) bool {
+	for _, from := range msg.GetFrom() {
+		localPart := strings.ToLower(from.Address)
+		if index := strings.LastIndexByte(localPart, '@'); index >= 0 {
+			localPart = localPart[:index]
+		}
+		if localPart == "mailer-daemon" || localPart == "postmaster" {
+			return true
+		}
+	}
+	for _, subject := range msg.GetGenHeader(mail.HeaderSubject) {
+		if subjectPattern.MatchString(subject) {
+			return true
+		}
+	}
+	return false
+}
+
+// classify returns the Class of the given enhanced status code or, if it is empty, of the SMTP
+// reply code of the given diagnostic code
+func classify(status, diagnosticCode string) Class {
+	if status == "" {
+		status = enhancedStatusPattern.FindString(diagnosticCode)
+	}
+	switch {
+	case strings.HasPrefix(status, "4."):
+		return ClassSoft
+	// A full mailbox is reported as permanent failure, but usually resolves itself
+	case status == "5.2.2":
+		return ClassSoft
+	case strings.HasPrefix(status, "5."):
+		return ClassHard
+	}
+	if match := replyCodePattern.FindStringSubmatch(diagnosticCode); match != nil {
+		switch {
+		case match[1] == "552":
+			return ClassSoft
+		case strings.HasPrefix(match[1], "4"):
+			return ClassSoft
+		default:
+			return ClassHard
+		}
+	}
+	return ClassUnknown
+}
+
+// classifyText returns the Class that is indicated by the phrases of the given text
+func classifyText(text string) Class {
+	text = strings.ToLower(text)
+	for _, phrase := range softPhrases {
+		if strings.Contains(text, phrase) {
+			return ClassSoft
+		}
+	}
+	for _, phrase := range hardPhrases {
+		if strings.Contains(text, phrase) {
+			return ClassHard
+		}
+	}
+	return ClassUnknown
+}
+
+// diagnosticLine returns the quoted reply of the remote server in the given text, which is the first
+// line that holds an SMTP reply code and an enhanced status code of a failure or, if there is none,
+// the first line that holds an SMTP reply code
+func diagnosticLine(text string) string {
+	var fallback string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if !replyCodePattern.MatchString(" " + line + " ") {
+			continue
+		}
+		if enhancedStatusPattern.MatchString(line) {
+			return line
+		}
+		if fallback == "" {
+			fallback = line
+		}
+	}
+	return fallback
+}
+
+// originalMessageID returns the Message-ID of the original message, which is taken from the
+// quoted headers of the original message, the In-Reply-To header or the text of the bounce
+func originalMessageID(msg *mail.Msg) string {
+	for _, part := range msg.GetParts() {
+		if !strings.EqualFold(string(part.GetContentType()), "text/rfc822-headers") {
+			continue
+		}
+		content, err := part.GetContent()
+		if err != nil {
+			continue
+		}
+		if messageID := headerMessageID(content); messageID != "" {
+			return messageID
+		}
+	}
+	for _, file := range msg.GetAttachments() {
+		if !strings.EqualFold(string(file.ContentType), mail.TypeMessageRFC822.String()) {
+			continue
+		}
+		content := bytes.NewBuffer(nil)
+		if _, err := file.Writer(content); err != nil {
+			continue
+		}
+		if messageID := headerMessageID(content.Bytes()); messageID != "" {
+			return messageID
+		}
+	}
+	if inReplyTo := msg.GetGenHeader(mail.HeaderInReplyTo); len(inReplyTo) > 0 {
+		return strings.TrimSpace(inReplyTo[0])
+	}
+	if match := messageIDPattern.FindStringSubmatch(bodyText(msg)); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// headerMessageID returns the Message-ID of the given message headers
+func headerMessageID(headers []byte) string {
+	if !bytes.Contains(headers, []byte("\n\n")) && !bytes.Contains(headers, []byte("\r\n\r\n")) {
+		headers = append(append([]byte(nil), headers...), "\r\n\r\n"...)
+	}
+	message, err := netmail.ReadMessage(bytes.NewReader(headers))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(message.Header.Get("Message-ID"))
+}
+
+// bodyText returns the content of the text parts of the given message
+func bodyText(msg *mail.Msg) string {
+	var text strings.Builder
+	for _, part := range msg.GetParts() {
+		if !strings.HasPrefix(strings.ToLower(string(part.GetContentType())), "text/plain") {
+			continue
+		}
+		content, err := part.GetContent()
+		if err != nil {
+			continue
+		}
+		text.Write(content)
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+// fieldAddress returns the address of the given address field of a delivery status notification,
+// without its address type, i. e. "user@example.com" for "rfc822; user@example.com"
+func fieldAddress(field string) string {
+	if index := strings.IndexByte(field, ';'); index >= 0 {
+		field = field[index+1:]
+	}
+	return strings.Trim(strings.TrimSpace(field), "<>")
+}
@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package bounce
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wneessen/go-mail"
+)
+
+const (
+	// qmailBounce is a non-standard bounce as sent by qmail
+	qmailBounce = "From: MAILER-DAEMON@mx.example.com\r\n" +
+		"To: sender@example.com\r\n" +
+		"Subject: failure notice\r\n" +
+		"Date: Mon, 01 Jan 2024 12:00:00 +0000\r\n" +
+		"\r\n" +
+		"Hi. This is the qmail-send program at mx.example.com.\r\n" +
+		"I'm afraid I wasn't able to deliver your message to the following addresses.\r\n" +
+		"This is a permanent error; I've given up. Sorry it didn't work out.\r\n" +
+		"\r\n" +
+		"<unknown@example.org>:\r\n" +
+		"192.0.2.1 does not like recipient.\r\n" +
+		"Remote host said: 550 5.1.1 <unknown@example.org>: Recipient address rejected\r\n" +
+		"\r\n" +
+		"<other@example.org>:\r\n" +
+		"192.0.2.1 does not like recipient.\r\n" +
+		"\r\n" +
+		"--- Below this line is a copy of the message.\r\n" +
+		"\r\n" +
+		"Message-ID: <qmail@example.com>\r\n" +
+		"Subject: Newsletter\r\n"
+
+	// eximBounce is a non-standard bounce as sent by Exim
+	eximBounce = "From: Mail Delivery System <Mailer-Daemon@mx.example.com>\r\n" +
+		"To: sender@example.com\r\n" +
+		"Subject: Mail delivery failed: returning message to sender\r\n" +
+		"Date: Mon, 01 Jan 2024 12:00:00 +0000\r\n" +
+		"In-Reply-To: <exim@example.com>\r\n" +
+		"\r\n" +
+		"This message was created automatically by mail delivery software.\r\n" +
+		"\r\n" +
+		"A message that you sent could not be delivered to one or more of its\r\n" +
+		"recipients. This is a temporary error. The following address(es) failed:\r\n" +
+		"\r\n" +
+		"  full@example.org\r\n" +
+		"    host mx.example.org [192.0.2.1]\r\n" +
+		"    SMTP error from remote mail server after RCPT TO:<full@example.org>:\r\n" +
+		"    452 mailbox full\r\n"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("delivery status notification", func(t *testing.T) {
+		original := mail.NewMsg()
+		if err := original.From("sender@example.com"); err != nil {
+			t.Fatalf("failed to set FROM address: %s", err)
+		}
+		if err := original.To("unknown@example.org", "delayed@example.org"); err != nil {
+			t.Fatalf("failed to set TO addresses: %s", err)
+		}
+		original.SetMessageIDWithValue("original@example.com")
+		original.SetBodyString(mail.TypeTextPlain, "Newsletter")
+		dsn, err := mail.NewDSNMsg(original, "mta.example.com",
+			mail.DSNStatus{
+				Action: mail.DSNActionFailed, FinalRecipient: "unknown@example.org", Status: "5.1.1",
+				DiagnosticCode: "smtp; 550 5.1.1 User unknown",
+			},
+			mail.DSNStatus{Action: mail.DSNActionDelayed, FinalRecipient: "delayed@example.org", Status: "4.4.1"},
+			mail.DSNStatus{Action: mail.DSNActionDelivered, FinalRecipient: "valid@example.org", Status: "2.0.0"},
+		)
+		if err != nil {
+			t.Fatalf("failed to create DSN: %s", err)
+		}
+		if err = dsn.From("postmaster@mta.example.com"); err != nil {
+			t.Fatalf("failed to set FROM address: %s", err)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err = dsn.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write DSN: %s", err)
+		}
+
+		bounces, err := Parse(buffer)
+		if err != nil {
+			t.Fatalf("failed to parse bounce: %s", err)
+		}
+		want := []Bounce{
+			{
+				Action: mail.DSNActionFailed, Class: ClassHard, DiagnosticCode: "smtp; 550 5.1.1 User unknown",
+				OriginalMessageID: "<original@example.com>", Recipient: "unknown@example.org", Standard: true,
+				Status: "5.1.1",
+			},
+			{
+				Action: mail.DSNActionDelayed, Class: ClassSoft, OriginalMessageID: "<original@example.com>",
+				Recipient: "delayed@example.org", Standard: true, Status: "4.4.1",
+			},
+		}
+		if len(bounces) != len(want) {
+			t.Fatalf("expected %d bounces, got: %+v", len(want), bounces)
+		}
+		for i := range want {
+			if bounces[i] != want[i] {
+				t.Errorf("expected bounce %+v, got: %+v", want[i], bounces[i])
+			}
+		}
+	})
+	t.Run("qmail bounce", func(t *testing.T) {
+		bounces, err := Parse(strings.NewReader(qmailBounce))
+		if err != nil {
+			t.Fatalf("failed to parse bounce: %s", err)
+		}
+		if len(bounces) != 2 {
+			t.Fatalf("expected 2 bounces, got: %+v", bounces)
+		}
+		for i, recipient := range []string{"unknown@example.org", "other@example.org"} {
+			if bounces[i].Recipient != recipient {
+				t.Errorf("expected recipient %q, got: %q", recipient, bounces[i].Recipient)
+			}
+			if bounces[i].Class != ClassHard {
+				t.Errorf("expected class %s, got: %s", ClassHard, bounces[i].Class)
+			}
+			if bounces[i].Status != "5.1.1" {
+				t.Errorf("expected status 5.1.1, got: %q", bounces[i].Status)
+			}
+			if bounces[i].OriginalMessageID != "<qmail@example.com>" {
+				t.Errorf("expected original Message-ID <qmail@example.com>, got: %q", bounces[i].OriginalMessageID)
+			}
+			if bounces[i].Standard {
+				t.Error("expected bounce not to be standard")
+			}
+		}
+		want := "Remote host said: 550 5.1.1 <unknown@example.org>: Recipient address rejected"
+		if bounces[0].DiagnosticCode != want {
+			t.Errorf("expected diagnostic code %q, got: %q", want, bounces[0].DiagnosticCode)
+		}
+	})
+	t.Run("exim bounce", func(t *testing.T) {
+		bounces, err := Parse(strings.NewReader(eximBounce))
+		if err != nil {
+			t.Fatalf("failed to parse bounce: %s", err)
+		}
+		want := Bounce{
+			Class: ClassSoft, DiagnosticCode: "452 mailbox full", OriginalMessageID: "<exim@example.com>",
+			Recipient: "full@example.org",
+		}
+		if len(bounces) != 1 || bounces[0] != want {
+			t.Errorf("expected bounce %+v, got: %+v", want, bounces)
+		}
+	})
+	t.Run("not a bounce", func(t *testing.T) {
+		messages := []string{
+			"From: user@example.com\r\nTo: sender@example.com\r\nSubject: Hello\r\n\r\nHow are you?\r\n",
+			"From: MAILER-DAEMON@mx.example.com\r\nSubject: failure notice\r\n\r\nNo recipient here.\r\n",
+		}
+		for _, message := range messages {
+			if _, err := Parse(strings.NewReader(message)); !errors.Is(err, ErrNotBounce) {
+				t.Errorf("expected error %q, got: %v", ErrNotBounce, err)
+			}
+		}
+	})
+	t.Run("invalid message", func(t *testing.T) {
+		if _, err := Parse(strings.NewReader("invalid")); err == nil || errors.Is(err, ErrNotBounce) {
+			t.Errorf("expected parse error, got: %v", err)
+		}
+	})
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         string
+		diagnosticCode string
+		want           Class
+	}{
+		{"permanent status", "5.1.1", "", ClassHard},
+		{"temporary status", "4.2.1", "", ClassSoft},
+		{"mailbox full status", "5.2.2", "", ClassSoft},
+		{"status in diagnostic code", "", "smtp; 550 5.7.1 blocked", ClassHard},
+		{"permanent reply code", "", "smtp; 550 rejected", ClassHard},
+		{"temporary reply code", "", "smtp; 421 try later", ClassSoft},
+		{"storage reply code", "", "smtp; 552 quota", ClassSoft},
+		{"no codes", "", "rejected", ClassUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classify(tt.status, tt.diagnosticCode); got != tt.want {
+				t.Errorf("expected class %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClass_String(t *testing.T) {
+	for class, want := range map[Class]string{ClassUnknown: "unknown", ClassSoft: "soft", ClassHard: "hard"} {
+		if got := class.String(); got != want {
+			t.Errorf("expected %q, got: %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	ht "html/template"
+	tt "text/template"
+)
+
+// ErrBroadcastTemplateIsNil is returned by NewBroadcast if the template Msg is nil.
+var ErrBroadcastTemplateIsNil = errors.New("broadcast template message is nil")
+
+type (
+	// Broadcast generates individualized copies of a template Msg for a list of recipients and sends
+	// them via a Transport.
+	//
+	// Each copy is addressed to a single recipient and gets its own Message-ID. The subject and the
+	// bodies can be rendered from templates with the data of the recipient. All other headers, the
+	// attachments and the embeds are taken from the template Msg. The "To", "Cc" and "Bcc" addresses of
+	// the template Msg are ignored.
+	Broadcast struct {
+		// htmlBody is the template of the HTML body of the copies, or nil.
+		htmlBody *ht.Template
+
+		// subject is the template of the subject of the copies, or nil.
+		subject *tt.Template
+
+		// template is the Msg that the copies are generated from.
+		template *Msg
+
+		// textBody is the template of the plain text body of the copies, or nil.
+		textBody *tt.Template
+	}
+
+	// BroadcastOption is a function that is used to configure the Broadcast.
+	BroadcastOption func(*Broadcast) error
+
+	// BroadcastRecipient represents a single recipient of a Broadcast.
+	BroadcastRecipient struct {
+		// Address is the address of the recipient, which is set as "To" address of its copy.
+		Address string
+
+		// Data is the data of the recipient that is passed to the templates of the Broadcast.
+		Data interface{}
+	}
+
+	// BroadcastResult represents the outcome of the delivery of the copy for a single recipient of
+	// a Broadcast.
+	BroadcastResult struct {
+		// Err is the error that occurred while generating or sending the copy for the recipient, or nil
+		// if the copy has been sent successfully.
+		Err error
+
+		// MessageID is the value of the Message-ID header of the copy.
+		MessageID string
+
+		// Msg is the copy that was generated for the recipient. It is nil, if the copy could not be
+		// generated.
+		Msg *Msg
+
+		// Recipient is the BroadcastRecipient the copy was generated for.
+		Recipient BroadcastRecipient
+	}
+)
+
+// NewBroadcast returns a new Broadcast for the given template Msg.
+//
+// Parameters:
+//   - template: The Msg that the copies for the recipients are generated from. It should not be
+//     modified while the Broadcast is in use.
+//   - opts: Optional BroadcastOption functions to configure the Broadcast.
+//
+// Returns:
+//   - A pointer to the new Broadcast.
+//   - An error if the template Msg is nil or any of the options fails; otherwise, returns nil.
+func NewBroadcast(template *Msg, opts ...BroadcastOption) (*Broadcast, error) {
+	if template == nil {
+		return nil, ErrBroadcastTemplateIsNil
+	}
+	broadcast := &Broadcast{template: template}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(broadcast); err != nil {
+			return nil, fmt.Errorf("failed to apply broadcast option: %w", err)
+		}
+	}
+	return broadcast, nil
+}
+
+// WithBroadcastSubject sets the text/template.Template that the subject of each copy is rendered
+// from. By default, the subject of the template Msg is used.
+//
+// Parameters:
+//   - tpl: The text/template.Template of the subject.
+//
+// Returns:
+//   - A BroadcastOption function that sets the subject template of the Broadcast.
+func WithBroadcastSubject(tpl *tt.Template) BroadcastOption {
+	return func(b *Broadcast) error {
+		if tpl == nil {
+			return errors.New(errTplPointerNil)
+		}
+		b.subject = tpl
+		return nil
+	}
+}
+
+// WithBroadcastTextBody sets the text/template.Template that the plain text body of each copy is
+// rendered from. If a body template is set, the bodies of the template Msg are not used.
+//
+// Parameters:
+//   - tpl: The text/template.Template of the plain text body.
+//
+// Returns:
+//   - A BroadcastOption function that sets the plain text body template of the Broadcast.
+func WithBroadcastTextBody(tpl *tt.Template) BroadcastOption {
+	return func(b *Broadcast) error {
+		if tpl == nil {
+			return errors.New(errTplPointerNil)
+		}
+		b.textBody = tpl
+		return nil
+	}
+}
+
+// WithBroadcastHTMLBody sets the html/template.Template that the HTML body of each copy is rendered
+// from. If a plain text body template is set as well, the HTML body is added as alternative. If a
+// body template is set, the bodies of the template Msg are not used.
+//
+// Parameters:
+//   - tpl: The html/template.Template of the HTML body.
+//
+// Returns:
+//   - A BroadcastOption function that sets the HTML body template of the Broadcast.
+func WithBroadcastHTMLBody(tpl *ht.Template) BroadcastOption {
+	return func(b *Broadcast) error {
+		if tpl == nil {
+			return errors.New(errTplPointerNil)
+		}
+		b.htmlBody = tpl
+		return nil
+	}
+}
+
+// Msg generates the copy of the template Msg for the given recipient.
+//
+// Parameters:
+//   - recipient: The BroadcastRecipient to generate the copy for.
+//
+// Returns:
+//   - A pointer to the generated Msg.
+//   - An error if the address of the recipient is invalid or any of the templates fails to execute;
+//     otherwise, returns nil.
+func (b *Broadcast) Msg(recipient BroadcastRecipient) (*Msg, error) {
+	message := b.template.broadcastCopy()
+	if err := message.To(recipient.Address); err != nil {
+		return nil, err
+	}
+	message.SetMessageID()
+	if b.subject != nil {
+		buffer := bytes.NewBuffer(nil)
+		if err := b.subject.Execute(buffer, recipient.Data); err != nil {
+			return nil, fmt.Errorf(errTplExecuteFailed, err)
+		}
+		message.Subject(buffer.String())
+	}
+	if b.textBody == nil && b.htmlBody == nil {
+		return message, nil
+	}
+	message.parts = nil
+	if b.textBody != nil {
+		if err := message.SetBodyTextTemplate(b.textBody, recipient.Data); err != nil {
+			return nil, err
+		}
+	}
+	if b.htmlBody != nil {
+		setHTML := message.SetBodyHTMLTemplate
+		if b.textBody != nil {
+			setHTML = message.AddAlternativeHTMLTemplate
+		}
+		if err := setHTML(b.htmlBody, recipient.Data); err != nil {
+			return nil, err
+		}
+	}
+	return message, nil
+}
+
+// Send generates the copies of the template Msg for the given recipients and sends them with a single
+// call of the SendWithContext method of the given Transport, so that a connected Client delivers all
+// copies over its current connection.
+//
+// Recipients whose copy cannot be generated are skipped and reported in their BroadcastResult.
+//
+// Parameters:
+//   - ctx: The context.Context that controls the cancellation of the delivery.
+//   - transport: The Transport to send the copies with, i. e. a connected Client or a ClientPool.
+//   - recipients: The recipients of the Broadcast.
+//
+// Returns:
+//   - A slice of BroadcastResult, one for each of the recipients, in the same order.
+//   - An error that aggregates the errors of all recipients; otherwise, returns nil.
+func (b *Broadcast) Send(ctx context.Context, transport Transport, recipients []BroadcastRecipient) (
+	[]BroadcastResult, error,
+) {
+	results := make([]BroadcastResult, len(recipients))
+	messages := make([]*Msg, 0, len(recipients))
+	for i, recipient := range recipients {
+		results[i].Recipient = recipient
+		message, err := b.Msg(recipient)
+		if err != nil {
+			results[i].Err = fmt.Errorf("failed to generate message for %q: %w", recipient.Address, err)
+			continue
+		}
+		results[i].Msg = message
+		results[i].MessageID = message.GetMessageID()
+		messages = append(messages, message)
+	}
+
+	var sendErr error
+	if len(messages) > 0 {
+		sendErr = transport.SendWithContext(ctx, messages...)
+	}
+	var errs []error
+	for i := range results {
+		result := &results[i]
+		if result.Msg != nil && sendErr != nil {
+			result.Err = result.Msg.SendError()
+			if result.Err == nil && !result.Msg.IsDelivered() {
+				result.Err = sendErr
+			}
+		}
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+	return results, joinErrors(errs)
+}
+
+// broadcastCopy returns a copy of the Msg, created with Clone, without its "To", "Cc" and "Bcc"
+// addresses and without its recipient parameters.
+func (m *Msg) broadcastCopy() *Msg {
+	copied := m.Clone()
+	copied.addrGroups = nil
+	delete(copied.addrHeader, HeaderTo)
+	delete(copied.addrHeader, HeaderCc)
+	delete(copied.addrHeader, HeaderBcc)
+	copied.rcptParams = nil
+	return copied
+}
@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	ht "html/template"
+	"strings"
+	"testing"
+	tt "text/template"
+	"time"
+)
+
+// broadcastData is the recipient data used in the Broadcast tests.
+type broadcastData struct {
+	Name string
+}
+
+func TestNewBroadcast(t *testing.T) {
+	t.Run("nil template message", func(t *testing.T) {
+		if _, err := NewBroadcast(nil); !errors.Is(err, ErrBroadcastTemplateIsNil) {
+			t.Errorf("expected error: %s, got: %s", ErrBroadcastTemplateIsNil, err)
+		}
+	})
+	t.Run("nil templates", func(t *testing.T) {
+		options := []BroadcastOption{WithBroadcastSubject(nil), WithBroadcastTextBody(nil), WithBroadcastHTMLBody(nil)}
+		for _, option := range options {
+			if _, err := NewBroadcast(testMessage(t), option); err == nil {
+				t.Error("expected nil template to fail")
+			}
+		}
+	})
+}
+
+func TestBroadcast_Msg(t *testing.T) {
+	template := testMessage(t)
+	if err := template.Cc("cc@domain.tld"); err != nil {
+		t.Fatalf("failed to set cc address: %s", err)
+	}
+	template.SetMessageID()
+	template.SetGenHeader(HeaderXMailer, "broadcast")
+	broadcast, err := NewBroadcast(template,
+		WithBroadcastSubject(tt.Must(tt.New("subject").Parse("Hello {{.Name}}"))),
+		WithBroadcastTextBody(tt.Must(tt.New("text").Parse("Dear {{.Name}},"))),
+		WithBroadcastHTMLBody(ht.Must(ht.New("html").Parse("<p>Dear {{.Name}},</p>"))),
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("failed to create broadcast: %s", err)
+	}
+
+	t.Run("copies are personalized", func(t *testing.T) {
+		first, err := broadcast.Msg(BroadcastRecipient{Address: "alice@domain.tld", Data: broadcastData{"Alice"}})
+		if err != nil {
+			t.Fatalf("failed to generate message: %s", err)
+		}
+		second, err := broadcast.Msg(BroadcastRecipient{Address: "bob@domain.tld", Data: broadcastData{"Bob <&>"}})
+		if err != nil {
+			t.Fatalf("failed to generate message: %s", err)
+		}
+		if first.GetMessageID() == second.GetMessageID() || first.GetMessageID() == template.GetMessageID() {
+			t.Errorf("expected individual Message-IDs, got: %s and %s", first.GetMessageID(),
+				second.GetMessageID())
+		}
+		recipients, err := second.GetRecipients()
+		if err != nil {
+			t.Fatalf("failed to get recipients: %s", err)
+		}
+		if len(recipients) != 1 || recipients[0] != "bob@domain.tld" {
+			t.Errorf("expected single recipient, got: %v", recipients)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err = second.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		for _, want := range []string{
+			"Subject: Hello Bob <&>", "Dear Bob <&>,", "<p>Dear Bob &lt;&amp;&gt;,</p>",
+			"X-Mailer: broadcast", "From: <valid-from@domain.tld>",
+		} {
+			if !strings.Contains(buffer.String(), want) {
+				t.Errorf("expected message to contain %q, got: %s", want, buffer.String())
+			}
+		}
+	})
+	t.Run("template message is not modified", func(t *testing.T) {
+		if _, err := broadcast.Msg(BroadcastRecipient{Address: "alice@domain.tld"}); err != nil {
+			t.Fatalf("failed to generate message: %s", err)
+		}
+		recipients, err := template.GetRecipients()
+		if err != nil {
+			t.Fatalf("failed to get recipients: %s", err)
+		}
+		if len(recipients) != 2 || recipients[0] != TestRcptValid {
+			t.Errorf("expected template recipients to be unchanged, got: %v", recipients)
+		}
+		if subject := template.GetGenHeader(HeaderSubject); len(subject) != 1 || subject[0] != "Testmail" {
+			t.Errorf("expected template subject to be unchanged, got: %v", subject)
+		}
+		if len(template.GetParts()) != 1 {
+			t.Errorf("expected template parts to be unchanged, got: %d", len(template.GetParts()))
+		}
+	})
+	t.Run("template bodies are used without body templates", func(t *testing.T) {
+		plain, err := NewBroadcast(template)
+		if err != nil {
+			t.Fatalf("failed to create broadcast: %s", err)
+		}
+		message, err := plain.Msg(BroadcastRecipient{Address: "alice@domain.tld"})
+		if err != nil {
+			t.Fatalf("failed to generate message: %s", err)
+		}
+		content, err := message.GetParts()[0].GetContent()
+		if err != nil {
+			t.Fatalf("failed to get part content: %s", err)
+		}
+		if string(content) != "Testmail" {
+			t.Errorf("expected template body, got: %s", content)
+		}
+	})
+	t.Run("invalid recipient address", func(t *testing.T) {
+		if _, err := broadcast.Msg(BroadcastRecipient{Address: "invalid"}); err == nil {
+			t.Error("expected invalid recipient address to fail")
+		}
+	})
+	t.Run("failing template", func(t *testing.T) {
+		failing, err := NewBroadcast(template, WithBroadcastSubject(tt.Must(tt.New("subject").Parse("{{.Missing}}"))))
+		if err != nil {
+			t.Fatalf("failed to create broadcast: %s", err)
+		}
+		if _, err = failing.Msg(BroadcastRecipient{Address: "alice@domain.tld", Data: broadcastData{}}); err == nil {
+			t.Error("expected failing template to fail")
+		}
+	})
+}
+
+func TestBroadcast_Send(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	PortAdder.Add(1)
+	serverPort := int(TestServerPortBase + PortAdder.Load())
+	featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+	go func() {
+		if err := simpleSMTPServer(ctx, t, &serverProps{
+			FeatureSet: featureSet,
+			ListenPort: serverPort,
+		}); err != nil {
+			t.Errorf("failed to start test server: %s", err)
+			return
+		}
+	}()
+	time.Sleep(time.Millisecond * 30)
+
+	client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS))
+	if err != nil {
+		t.Fatalf("failed to create new client: %s", err)
+	}
+	if err = client.DialWithContext(context.Background()); err != nil {
+		t.Fatalf("failed to connect to the test server: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Errorf("failed to close client: %s", err)
+		}
+	})
+
+	broadcast, err := NewBroadcast(testMessage(t),
+		WithBroadcastTextBody(tt.Must(tt.New("text").Parse("Dear {{.Name}},"))))
+	if err != nil {
+		t.Fatalf("failed to create broadcast: %s", err)
+	}
+	recipients := []BroadcastRecipient{
+		{Address: TestRcptValid, Data: broadcastData{"Alice"}},
+		{Address: "invalid", Data: broadcastData{"Bob"}},
+		{Address: "invalid-to@domain.tld", Data: broadcastData{"Carol"}},
+		{Address: TestRcptValid, Data: broadcastData{"Dave"}},
+	}
+	results, err := broadcast.Send(context.Background(), client, recipients)
+	if err == nil {
+		t.Error("expected broadcast to report failed recipients")
+	}
+	if len(results) != len(recipients) {
+		t.Fatalf("expected %d results, got: %d", len(recipients), len(results))
+	}
+	for i, wantErr := range []bool{false, true, true, false} {
+		result := results[i]
+		if result.Recipient.Address != recipients[i].Address {
+			t.Errorf("result %d: unexpected recipient: %s", i, result.Recipient.Address)
+		}
+		if wantErr != (result.Err != nil) {
+			t.Errorf("result %d: unexpected error: %v", i, result.Err)
+		}
+		if !wantErr && (!result.Msg.IsDelivered() || result.MessageID != result.Msg.GetMessageID()) {
+			t.Errorf("result %d: expected delivered message with Message-ID %s", i, result.MessageID)
+		}
+	}
+	if results[1].Msg != nil {
+		t.Error("expected no message for invalid recipient address")
+	}
+	if results[0].MessageID == results[3].MessageID {
+		t.Error("expected individual Message-IDs")
+	}
+}
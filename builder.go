@@ -0,0 +1,268 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type (
+	// MsgBuilder builds a Msg with chainable methods.
+	//
+	// The methods of the Msg either return an error, i. e. Msg.From and Msg.To, or do not, i. e.
+	// Msg.Subject, so that constructing a Msg requires an error check after most of the calls. The
+	// methods of the MsgBuilder can be chained instead, while the errors are collected and returned
+	// at once by MsgBuilder.Build. A MsgBuilder is not safe for concurrent use.
+	MsgBuilder struct {
+		errlist []error
+		msg     *Msg
+	}
+
+	// MsgBuildError is returned by MsgBuilder.Build and holds all errors that occurred while
+	// building the Msg, in the order of the calls that caused them.
+	MsgBuildError struct {
+		errlist []error
+	}
+)
+
+// NewMsgBuilder returns a new MsgBuilder for a Msg created with NewMsg and the given MsgOption values.
+//
+// Parameters:
+//   - opts: Optional MsgOption values to customize the Msg.
+//
+// Returns:
+//   - A pointer to the new MsgBuilder.
+func NewMsgBuilder(opts ...MsgOption) *MsgBuilder {
+	return &MsgBuilder{msg: NewMsg(opts...)}
+}
+
+// From sets the "FROM" address of the Msg, as Msg.From does.
+//
+// Parameters:
+//   - from: The "FROM" address to set in the Msg.
+//
+// Returns:
+//   - The MsgBuilder, for chaining.
+func (b *MsgBuilder) From(from string) *MsgBuilder {
+	b.addError(b.msg.From(from))
+	return b
+}
+
+// To sets the "TO" addresses of the Msg, as Msg.To does.
+//
+// Parameters:
+//   - rcpts: One or more recipient email addresses.
+//
+// Returns:
+//   - The MsgBuilder, for chaining.
+func (b *MsgBuilder) To(rcpts ...string) *MsgBuilder {
+	b.addError(b.msg.To(rcpts...))
+	return b
+}
+
+// Cc sets the "CC" addresses of the Msg, as Msg.Cc does.
+//
+// Parameters:
+//   - rcpts: One or more recipient email addresses.
+//
+// Returns:
+//   - The MsgBuilder, for chaining.
+func (b *MsgBuilder) Cc(rcpts ...string) *MsgBuilder {
+	b.addError(b.msg.Cc(rcpts...))
+	return b
+}
+
+// Bcc sets the "BCC" addresses of the Msg, as Msg.Bcc does.
+//
+// Parameters:
+//   - rcpts: One or more recipient email addresses.
+//
+// Returns:
+//   - The MsgBuilder, for chaining.
+func (b *MsgBuilder) Bcc(rcpts ...string) *MsgBuilder {
+	b.addError(b.msg.Bcc(rcpts...))
+	return b
+}
+
+// ReplyTo sets the "Reply-To" address of the Msg, as Msg.ReplyTo does.
+//
+// Parameters:
+//   - addr: The email address to set as "Reply-To".
+//
+// Returns:
+//   - The MsgBuilder, for chaining.
+func (b *MsgBuilder) ReplyTo(addr string) *MsgBuilder {
+	b.addError(b.msg.ReplyTo(addr))
+	return b
+}
+
+// Subject sets the "Subject" header of the Msg, as Msg.Subject does.
+//
+// Parameters:
+//   - subj: The subject line of the Msg.
+//
+// Returns:
+//   - The MsgBuilder, for chaining.
+func (b *MsgBuilder) Subject(subj string) *MsgBuilder {
+	b.msg.Subject(subj)
+	return b
+}
+
+// Header sets a generic header of the Msg, as Msg.SetGenHeader does.
+//
+// Parameters:
+//   - header: The Header to set.
+//   - values: One or more values of the header.
+//
+// Returns:
+//   - The MsgBuilder, for chaining.
+func (b *MsgBuilder) Header(header Header, values ...string) *MsgBuilder {
+	b.msg.SetGenHeader(header, values...)
+	return b
+}
+
+// TextBody sets the "text/plain" body of the Msg. If a body has already been set, i. e. with
+// HTMLBody, the text is added as alternative part instead.
+//
+// Parameters:
+//   - content: The plain text content of the body.
+//   - opts: Optional PartOption values to customize the part.
+//
+// Returns:
+//   - The MsgBuilder, for chaining.
+func (b *MsgBuilder) TextBody(content string, opts ...PartOption) *MsgBuilder {
+	return b.body(TypeTextPlain, content, opts...)
+}
+
+// HTMLBody sets the "text/html" body of the Msg. If a body has already been set, i. e. with
+// TextBody, the HTML is added as alternative part instead.
+//
+// Parameters:
+//   - content: The HTML content of the body.
+//   - opts: Optional PartOption values to customize the part.
+//
+// Returns:
+//   - The MsgBuilder, for chaining.
+func (b *MsgBuilder) HTMLBody(content string, opts ...PartOption) *MsgBuilder {
+	return b.body(TypeTextHTML, content, opts...)
+}
+
+// AttachFile attaches a file from the filesystem to the Msg, as Msg.AttachFile does. Unlike
+// Msg.AttachFile, a file that does not exist is reported as error by MsgBuilder.Build.
+//
+// Parameters:
+//   - name: The name of the file to be attached.
+//   - opts: Optional FileOption values to customize the attachment.
+//
+// Returns:
+//   - The MsgBuilder, for chaining.
+func (b *MsgBuilder) AttachFile(name string, opts ...FileOption) *MsgBuilder {
+	count := len(b.msg.attachments)
+	b.msg.AttachFile(name, opts...)
+	if len(b.msg.attachments) == count {
+		b.addError(fmt.Errorf("failed to attach file %q: file not found", name))
+	}
+	return b
+}
+
+// AttachReader attaches a file from an io.Reader to the Msg, as Msg.AttachReader does.
+//
+// Parameters:
+//   - name: The name of the file to be attached.
+//   - reader: The io.Reader providing the file data to be attached.
+//   - opts: Optional FileOption values to customize the attachment.
+//
+// Returns:
+//   - The MsgBuilder, for chaining.
+func (b *MsgBuilder) AttachReader(name string, reader io.Reader, opts ...FileOption) *MsgBuilder {
+	b.addError(b.msg.AttachReader(name, reader, opts...))
+	return b
+}
+
+// Apply calls the given function with the Msg that is being built, for settings that the MsgBuilder
+// does not provide a method for.
+//
+// Parameters:
+//   - apply: The function to call with the Msg. A non-nil error is collected like the errors of the
+//     other methods.
+//
+// Returns:
+//   - The MsgBuilder, for chaining.
+func (b *MsgBuilder) Apply(apply func(*Msg) error) *MsgBuilder {
+	b.addError(apply(b.msg))
+	return b
+}
+
+// Build returns the built Msg, after validating that it has a "FROM" address and at least one
+// recipient.
+//
+// Returns:
+//   - A pointer to the built Msg, which is returned even if errors occurred, so that it can be
+//     inspected.
+//   - A MsgBuildError holding all errors that occurred while building the Msg; otherwise, returns nil.
+func (b *MsgBuilder) Build() (*Msg, error) {
+	errlist := append([]error(nil), b.errlist...)
+	if len(b.msg.GetAddrHeader(HeaderFrom)) == 0 && len(b.msg.GetAddrHeader(HeaderEnvelopeFrom)) == 0 {
+		errlist = append(errlist, ErrNoFromAddress)
+	}
+	if _, err := b.msg.GetRecipients(); err != nil {
+		errlist = append(errlist, err)
+	}
+	if len(errlist) > 0 {
+		return b.msg, &MsgBuildError{errlist: errlist}
+	}
+	return b.msg, nil
+}
+
+// Error satisfies the error interface for the MsgBuildError type.
+func (e *MsgBuildError) Error() string {
+	var errMessage strings.Builder
+	errMessage.WriteString("failed to build message:")
+	for i := range e.errlist {
+		errMessage.WriteRune(' ')
+		errMessage.WriteString(e.errlist[i].Error())
+		if i != len(e.errlist)-1 {
+			errMessage.WriteString(",")
+		}
+	}
+	return errMessage.String()
+}
+
+// Errors returns all errors that occurred while building the Msg.
+//
+// Returns:
+//   - A slice of the errors, in the order of the calls that caused them.
+func (e *MsgBuildError) Errors() []error {
+	return e.errlist
+}
+
+// Unwrap returns all errors that occurred while building the Msg, so that errors.Is and errors.As
+// can match them with Go 1.20 and later.
+//
+// Returns:
+//   - A slice of the errors, in the order of the calls that caused them.
+func (e *MsgBuildError) Unwrap() []error {
+	return e.errlist
+}
+
+// body sets the body of the Msg with the given content type, or adds an alternative part if the
+// body has already been set.
+func (b *MsgBuilder) body(contentType ContentType, content string, opts ...PartOption) *MsgBuilder {
+	if len(b.msg.parts) == 0 {
+		b.msg.SetBodyString(contentType, content, opts...)
+		return b
+	}
+	b.msg.AddAlternativeString(contentType, content, opts...)
+	return b
+}
+
+// addError collects the given error, if it is not nil.
+func (b *MsgBuilder) addError(err error) {
+	if err != nil {
+		b.errlist = append(b.errlist, err)
+	}
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMsgBuilder_Build(t *testing.T) {
+	t.Run("build a valid message", func(t *testing.T) {
+		msg, err := NewMsgBuilder(WithCharset(CharsetUTF8)).
+			From("valid-from@domain.tld").
+			To("valid-to@domain.tld").
+			Cc("valid-cc@domain.tld").
+			Bcc("valid-bcc@domain.tld").
+			ReplyTo("valid-reply@domain.tld").
+			Subject("Testmail").
+			Header(HeaderXMailer, "go-mail builder").
+			TextBody("Testmail").
+			HTMLBody("<p>Testmail</p>").
+			AttachReader("test.txt", strings.NewReader("Attachment")).
+			AttachFile("testdata/attachment.txt").
+			Build()
+		if err != nil {
+			t.Fatalf("failed to build message: %s", err)
+		}
+		rcpts, err := msg.GetRecipients()
+		if err != nil {
+			t.Fatalf("failed to get recipients: %s", err)
+		}
+		if len(rcpts) != 3 {
+			t.Errorf("expected 3 recipients, got: %d", len(rcpts))
+		}
+		if subject := msg.GetGenHeader(HeaderSubject); len(subject) != 1 || subject[0] != "Testmail" {
+			t.Errorf("expected subject to be %q, got: %v", "Testmail", subject)
+		}
+		parts := msg.GetParts()
+		if len(parts) != 2 {
+			t.Fatalf("expected 2 parts, got: %d", len(parts))
+		}
+		if parts[0].GetContentType() != TypeTextPlain || parts[1].GetContentType() != TypeTextHTML {
+			t.Errorf("expected text and HTML part, got: %s and %s", parts[0].GetContentType(),
+				parts[1].GetContentType())
+		}
+		if len(msg.GetAttachments()) != 2 {
+			t.Errorf("expected 2 attachments, got: %d", len(msg.GetAttachments()))
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err = msg.WriteTo(buffer); err != nil {
+			t.Errorf("failed to write built message: %s", err)
+		}
+	})
+	t.Run("errors are accumulated", func(t *testing.T) {
+		msg, err := NewMsgBuilder().
+			From("invalid").
+			To("valid-to@domain.tld", "invalid").
+			AttachFile("testdata/does-not-exist.txt").
+			Subject("Testmail").
+			Build()
+		if err == nil {
+			t.Fatal("expected building the message to fail")
+		}
+		if msg == nil {
+			t.Fatal("expected message to be returned with error")
+		}
+		var buildErr *MsgBuildError
+		if !errors.As(err, &buildErr) {
+			t.Fatalf("expected error to be of type *MsgBuildError, got: %T", err)
+		}
+		errlist := buildErr.Errors()
+		if len(errlist) != 5 {
+			t.Fatalf("expected 5 errors, got: %d: %s", len(errlist), err)
+		}
+		if !errors.Is(errlist[3], ErrNoFromAddress) {
+			t.Errorf("expected error %q, got: %s", ErrNoFromAddress, errlist[3])
+		}
+		if !errors.Is(errlist[4], ErrNoRcptAddresses) {
+			t.Errorf("expected error %q, got: %s", ErrNoRcptAddresses, errlist[4])
+		}
+		if !strings.Contains(err.Error(), `failed to attach file "testdata/does-not-exist.txt"`) {
+			t.Errorf("expected error message to mention missing file, got: %s", err)
+		}
+		if len(buildErr.Unwrap()) != len(errlist) {
+			t.Errorf("expected Unwrap to return all errors")
+		}
+	})
+	t.Run("apply collects errors", func(t *testing.T) {
+		applyErr := errors.New("apply failed")
+		_, err := NewMsgBuilder().
+			From("valid-from@domain.tld").
+			To("valid-to@domain.tld").
+			Apply(func(msg *Msg) error {
+				msg.SetImportance(ImportanceHigh)
+				return nil
+			}).
+			Apply(func(*Msg) error { return applyErr }).
+			Build()
+		var buildErr *MsgBuildError
+		if !errors.As(err, &buildErr) {
+			t.Fatalf("expected error to be of type *MsgBuildError, got: %T", err)
+		}
+		if len(buildErr.Errors()) != 1 || !errors.Is(buildErr.Errors()[0], applyErr) {
+			t.Errorf("expected only the apply error, got: %s", err)
+		}
+	})
+	t.Run("build is repeatable", func(t *testing.T) {
+		builder := NewMsgBuilder().To("valid-to@domain.tld")
+		if _, err := builder.Build(); err == nil {
+			t.Fatal("expected building the message without FROM address to fail")
+		}
+		if _, err := builder.From("valid-from@domain.tld").Build(); err != nil {
+			t.Errorf("expected building the message to succeed, got: %s", err)
+		}
+	})
+}
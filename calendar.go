@@ -0,0 +1,432 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// CalendarMethodRequest is the iTIP method used to invite attendees to an event or to update an
+	// existing event.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc5546#section-3.2.2
+	CalendarMethodRequest CalendarMethod = "REQUEST"
+
+	// CalendarMethodReply is the iTIP method used by an attendee to reply to an invitation.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc5546#section-3.2.3
+	CalendarMethodReply CalendarMethod = "REPLY"
+
+	// CalendarMethodCancel is the iTIP method used by the organizer to cancel an event.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc5546#section-3.2.5
+	CalendarMethodCancel CalendarMethod = "CANCEL"
+)
+
+const (
+	// CalendarPartStatNeedsAction indicates that the attendee has not yet responded to the invitation.
+	CalendarPartStatNeedsAction CalendarPartStat = "NEEDS-ACTION"
+
+	// CalendarPartStatAccepted indicates that the attendee accepted the invitation.
+	CalendarPartStatAccepted CalendarPartStat = "ACCEPTED"
+
+	// CalendarPartStatDeclined indicates that the attendee declined the invitation.
+	CalendarPartStatDeclined CalendarPartStat = "DECLINED"
+
+	// CalendarPartStatTentative indicates that the attendee tentatively accepted the invitation.
+	CalendarPartStatTentative CalendarPartStat = "TENTATIVE"
+)
+
+// calendarLineLength is the maximum length of a content line in octets, excluding the line break.
+//
+// https://datatracker.ietf.org/doc/html/rfc5545#section-3.1
+const calendarLineLength = 75
+
+// calendarTimeFormat is the iCalendar DATE-TIME format in UTC.
+//
+// https://datatracker.ietf.org/doc/html/rfc5545#section-3.3.5
+const calendarTimeFormat = "20060102T150405Z"
+
+var (
+	// ErrCalendarNilEvent is returned if a nil CalendarEvent is added to a Msg.
+	ErrCalendarNilEvent = errors.New("calendar event must not be nil")
+
+	// ErrCalendarNoStart is returned if a CalendarEvent has no start time set.
+	ErrCalendarNoStart = errors.New("calendar event has no start time")
+
+	// ErrCalendarInvalidEnd is returned if the end time of a CalendarEvent is before its start time.
+	ErrCalendarInvalidEnd = errors.New("calendar event ends before it starts")
+
+	// ErrCalendarNoOrganizer is returned if neither the CalendarEvent nor the Msg provide an organizer.
+	ErrCalendarNoOrganizer = errors.New("calendar event has no organizer")
+
+	// ErrCalendarNoAttendee is returned if a CalendarEvent requires at least one attendee, but none is set.
+	ErrCalendarNoAttendee = errors.New("calendar event has no attendee")
+
+	// ErrCalendarInvalidURL is returned if the URL of a CalendarEvent cannot be parsed, i. e. because it
+	// contains line breaks.
+	ErrCalendarInvalidURL = errors.New("calendar event has an invalid URL")
+)
+
+type (
+	// CalendarMethod is a type wrapper for a string and represents the iTIP method of a calendar object.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc5546#section-1.4
+	CalendarMethod string
+
+	// CalendarPartStat is a type wrapper for a string and represents the participation status of a
+	// CalendarAttendee.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc5545#section-3.2.12
+	CalendarPartStat string
+
+	// CalendarAttendee represents an attendee of a CalendarEvent.
+	CalendarAttendee struct {
+		// Address is the mail address of the attendee.
+		Address string
+
+		// Name is the optional common name of the attendee.
+		Name string
+
+		// Optional marks the attendee as optional participant. By default, attendees are required
+		// participants.
+		Optional bool
+
+		// PartStat is the participation status of the attendee. If empty, CalendarPartStatNeedsAction
+		// is used.
+		PartStat CalendarPartStat
+
+		// RSVP indicates whether a reply is expected from the attendee.
+		RSVP bool
+	}
+
+	// CalendarEvent represents a single event of a calendar invitation.
+	//
+	// The CalendarEvent is rendered as VEVENT component of a VCALENDAR object, which is attached to
+	// the Msg as text/calendar alternative part.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc5545#section-3.6.1
+	CalendarEvent struct {
+		// Attendees holds the attendees of the event.
+		Attendees []CalendarAttendee
+
+		// Description is the optional description of the event.
+		Description string
+
+		// End is the end time of the event. If it is zero, the event has no duration.
+		End time.Time
+
+		// Location is the optional location of the event.
+		Location string
+
+		// Organizer is the mail address of the organizer of the event. If empty, the From address
+		// of the Msg is used.
+		Organizer string
+
+		// Sequence is the revision sequence number of the event. It needs to be incremented each time
+		// the organizer sends an update or cancellation of the event.
+		Sequence int
+
+		// Start is the start time of the event.
+		Start time.Time
+
+		// Summary is the title of the event.
+		Summary string
+
+		// Timestamp is the point in time the calendar object was created. If zero, the current time
+		// is used.
+		Timestamp time.Time
+
+		// UID is the globally unique identifier of the event. Updates, replies and cancellations need
+		// to refer to the same UID as the original invitation. If empty, a random UID is generated.
+		UID string
+
+		// URL is an optional URL associated with the event.
+		URL string
+	}
+)
+
+// SetCalendarInvite adds a calendar invitation for the given CalendarEvent to the Msg.
+//
+// The event is rendered as iCalendar object with the REQUEST method and added to the Msg as
+// "text/calendar; method=REQUEST" alternative part, so that mail clients offer to accept or decline
+// the invitation. If the event has no UID set, a random UID is generated and stored in the event.
+// If the event has no organizer set, the From address of the Msg is used.
+//
+// Parameters:
+//   - event: A pointer to the CalendarEvent to invite the attendees to.
+//   - opts: Optional parameters for customizing the calendar part.
+//
+// Returns:
+//   - An error if the CalendarEvent is invalid; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5545
+//   - https://datatracker.ietf.org/doc/html/rfc6047
+func (m *Msg) SetCalendarInvite(event *CalendarEvent, opts ...PartOption) error {
+	return m.addCalendarEvent(CalendarMethodRequest, event, opts...)
+}
+
+// SetCalendarReply adds a reply to a calendar invitation for the given CalendarEvent to the Msg.
+//
+// The event is rendered as iCalendar object with the REPLY method and added to the Msg as
+// "text/calendar; method=REPLY" alternative part. The event needs to carry the UID of the original
+// invitation and at least one attendee with the participation status of the reply.
+//
+// Parameters:
+//   - event: A pointer to the CalendarEvent that is replied to.
+//   - opts: Optional parameters for customizing the calendar part.
+//
+// Returns:
+//   - An error if the CalendarEvent is invalid; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5546#section-3.2.3
+//   - https://datatracker.ietf.org/doc/html/rfc6047
+func (m *Msg) SetCalendarReply(event *CalendarEvent, opts ...PartOption) error {
+	return m.addCalendarEvent(CalendarMethodReply, event, opts...)
+}
+
+// SetCalendarCancel adds a cancellation of the given CalendarEvent to the Msg.
+//
+// The event is rendered as iCalendar object with the CANCEL method and added to the Msg as
+// "text/calendar; method=CANCEL" alternative part. The event needs to carry the UID of the original
+// invitation and an increased Sequence number.
+//
+// Parameters:
+//   - event: A pointer to the CalendarEvent that is cancelled.
+//   - opts: Optional parameters for customizing the calendar part.
+//
+// Returns:
+//   - An error if the CalendarEvent is invalid; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5546#section-3.2.5
+//   - https://datatracker.ietf.org/doc/html/rfc6047
+func (m *Msg) SetCalendarCancel(event *CalendarEvent, opts ...PartOption) error {
+	return m.addCalendarEvent(CalendarMethodCancel, event, opts...)
+}
+
+// ICS renders the CalendarEvent as iCalendar object with the given CalendarMethod.
+//
+// The content lines are folded at 75 octets and terminated with CRLF, and all text values are
+// escaped as required by RFC 5545. All times are converted to UTC.
+//
+// Parameters:
+//   - method: The CalendarMethod of the iCalendar object.
+//
+// Returns:
+//   - The rendered iCalendar object.
+//   - An error if the CalendarEvent is invalid for the given method.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5545
+func (e *CalendarEvent) ICS(method CalendarMethod) ([]byte, error) {
+	if e.Start.IsZero() {
+		return nil, ErrCalendarNoStart
+	}
+	if !e.End.IsZero() && e.End.Before(e.Start) {
+		return nil, ErrCalendarInvalidEnd
+	}
+	if e.Organizer == "" {
+		return nil, ErrCalendarNoOrganizer
+	}
+	organizer, err := mail.ParseAddress(e.Organizer)
+	if err != nil {
+		return nil, fmt.Errorf(errParseMailAddr, e.Organizer, err)
+	}
+	if method == CalendarMethodReply && len(e.Attendees) == 0 {
+		return nil, ErrCalendarNoAttendee
+	}
+	if e.URL != "" {
+		if _, err = url.Parse(e.URL); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCalendarInvalidURL, err)
+		}
+	}
+	if e.UID == "" {
+		e.UID = calendarUID()
+	}
+	timestamp := e.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	buffer := bytes.Buffer{}
+	writeLine := func(line string) {
+		buffer.WriteString(foldCalendarLine(line))
+		buffer.WriteString(SingleNewLine)
+	}
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("PRODID:-//go-mail//go-mail " + VERSION + "//EN")
+	writeLine("VERSION:2.0")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("METHOD:" + string(method))
+	writeLine("BEGIN:VEVENT")
+	writeLine("UID:" + escapeCalendarText(e.UID))
+	writeLine("SEQUENCE:" + fmt.Sprint(e.Sequence))
+	writeLine("DTSTAMP:" + timestamp.UTC().Format(calendarTimeFormat))
+	writeLine("DTSTART:" + e.Start.UTC().Format(calendarTimeFormat))
+	if !e.End.IsZero() {
+		writeLine("DTEND:" + e.End.UTC().Format(calendarTimeFormat))
+	}
+	if e.Summary != "" {
+		writeLine("SUMMARY:" + escapeCalendarText(e.Summary))
+	}
+	if e.Description != "" {
+		writeLine("DESCRIPTION:" + escapeCalendarText(e.Description))
+	}
+	if e.Location != "" {
+		writeLine("LOCATION:" + escapeCalendarText(e.Location))
+	}
+	if e.URL != "" {
+		writeLine("URL:" + e.URL)
+	}
+	writeLine(calendarPerson("ORGANIZER", organizer.Name, "") + "mailto:" + organizer.Address)
+	for _, attendee := range e.Attendees {
+		address, err := mail.ParseAddress(attendee.Address)
+		if err != nil {
+			return nil, fmt.Errorf(errParseMailAddr, attendee.Address, err)
+		}
+		name := attendee.Name
+		if name == "" {
+			name = address.Name
+		}
+		role := "REQ-PARTICIPANT"
+		if attendee.Optional {
+			role = "OPT-PARTICIPANT"
+		}
+		partStat := attendee.PartStat
+		if partStat == "" {
+			partStat = CalendarPartStatNeedsAction
+		}
+		params := fmt.Sprintf(";ROLE=%s;PARTSTAT=%s", role, partStat)
+		if attendee.RSVP {
+			params += ";RSVP=TRUE"
+		}
+		writeLine(calendarPerson("ATTENDEE", name, params) + "mailto:" + address.Address)
+	}
+	status := "CONFIRMED"
+	if method == CalendarMethodCancel {
+		status = "CANCELLED"
+	}
+	writeLine("STATUS:" + status)
+	writeLine("END:VEVENT")
+	writeLine("END:VCALENDAR")
+
+	return buffer.Bytes(), nil
+}
+
+// addCalendarEvent renders the given CalendarEvent with the given CalendarMethod and adds it to the
+// Msg as text/calendar alternative part.
+//
+// Parameters:
+//   - method: The CalendarMethod of the iCalendar object.
+//   - event: A pointer to the CalendarEvent to render.
+//   - opts: Optional parameters for customizing the calendar part.
+//
+// Returns:
+//   - An error if the CalendarEvent is invalid; otherwise, returns nil.
+func (m *Msg) addCalendarEvent(method CalendarMethod, event *CalendarEvent, opts ...PartOption) error {
+	if event == nil {
+		return ErrCalendarNilEvent
+	}
+	if event.Organizer == "" {
+		from := m.GetFromString()
+		if len(from) == 0 {
+			return ErrCalendarNoOrganizer
+		}
+		event.Organizer = from[0]
+	}
+	ics, err := event.ICS(method)
+	if err != nil {
+		return err
+	}
+	contentType := ContentType(fmt.Sprintf("%s; method=%s", TypeTextCalendar, method))
+	m.AddAlternativeString(contentType, string(ics), opts...)
+	return nil
+}
+
+// calendarPerson returns the property name and parameters for a calendar user property, like
+// ORGANIZER or ATTENDEE, including the quoted common name, if given.
+//
+// Parameters:
+//   - property: The name of the property.
+//   - name: The optional common name of the calendar user.
+//   - params: Additional parameters, each starting with a semicolon.
+//
+// Returns:
+//   - The property name and parameters, followed by a colon.
+func calendarPerson(property, name, params string) string {
+	if name != "" {
+		name = strings.NewReplacer(`"`, "'", "\r", "", "\n", " ").Replace(name)
+		property += fmt.Sprintf(`;CN="%s"`, name)
+	}
+	return property + params + ":"
+}
+
+// calendarUID generates a random, globally unique identifier for a CalendarEvent.
+//
+// Returns:
+//   - The random UID in the form "random@hostname".
+func calendarUID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost.localdomain"
+	}
+	randString, _ := randomStringSecure(22)
+	return fmt.Sprintf("%s@%s", randString, hostname)
+}
+
+// escapeCalendarText escapes a text value as required for iCalendar TEXT properties.
+//
+// Parameters:
+//   - text: The text value to escape.
+//
+// Returns:
+//   - The escaped text value.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5545#section-3.3.11
+func escapeCalendarText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`,
+		"\r", `\n`).Replace(text)
+}
+
+// foldCalendarLine folds an iCalendar content line, so that no line exceeds 75 octets. UTF-8
+// multi-octet sequences are never split.
+//
+// Parameters:
+//   - line: The content line to fold.
+//
+// Returns:
+//   - The folded content line, without a trailing line break.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5545#section-3.1
+func foldCalendarLine(line string) string {
+	if len(line) <= calendarLineLength {
+		return line
+	}
+	buffer := strings.Builder{}
+	lineLength := 0
+	for _, char := range line {
+		charLength := len(string(char))
+		if lineLength+charLength > calendarLineLength {
+			buffer.WriteString(SingleNewLine + " ")
+			lineLength = 1
+		}
+		buffer.WriteRune(char)
+		lineLength += charLength
+	}
+	return buffer.String()
+}
@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarEvent_ICS(t *testing.T) {
+	start := time.Date(2024, 10, 1, 14, 0, 0, 0, time.FixedZone("CEST", 7200))
+	t.Run("render event with attendees", func(t *testing.T) {
+		event := &CalendarEvent{
+			Attendees: []CalendarAttendee{
+				{Address: "toni.tester@example.com", Name: "Toni Tester", RSVP: true},
+				{Address: `"Tina Tester" <tina.tester@example.com>`, Optional: true, PartStat: CalendarPartStatAccepted},
+			},
+			Description: "Agenda:\n1. Review, planning; wrap-up",
+			End:         start.Add(time.Hour),
+			Location:    "Room 1",
+			Organizer:   "Alice <alice@example.com>",
+			Sequence:    1,
+			Start:       start,
+			Summary:     "Weekly sync",
+			Timestamp:   start,
+			UID:         "event-1@example.com",
+			URL:         "https://example.com/meetings/1",
+		}
+		ics, err := event.ICS(CalendarMethodRequest)
+		if err != nil {
+			t.Fatalf("failed to render calendar event: %s", err)
+		}
+		want := []string{
+			"BEGIN:VCALENDAR\r\n",
+			"METHOD:REQUEST\r\n",
+			"UID:event-1@example.com\r\n",
+			"SEQUENCE:1\r\n",
+			"DTSTAMP:20241001T120000Z\r\n",
+			"DTSTART:20241001T120000Z\r\n",
+			"DTEND:20241001T130000Z\r\n",
+			"SUMMARY:Weekly sync\r\n",
+			`DESCRIPTION:Agenda:\n1. Review\, planning\; wrap-up` + "\r\n",
+			"LOCATION:Room 1\r\n",
+			"URL:https://example.com/meetings/1\r\n",
+			`ORGANIZER;CN="Alice":mailto:alice@example.com` + "\r\n",
+			`ATTENDEE;CN="Toni Tester";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=T`,
+			`ATTENDEE;CN="Tina Tester";ROLE=OPT-PARTICIPANT;PARTSTAT=ACCEPTED:mailto:tin`,
+			"STATUS:CONFIRMED\r\n",
+			"END:VEVENT\r\nEND:VCALENDAR\r\n",
+		}
+		for _, line := range want {
+			if !bytes.Contains(ics, []byte(line)) {
+				t.Errorf("expected calendar object to contain %q, got:\n%s", line, ics)
+			}
+		}
+		for _, line := range strings.Split(string(ics), "\r\n") {
+			if len(line) > calendarLineLength {
+				t.Errorf("expected lines to be folded at %d octets, got %d: %s", calendarLineLength, len(line), line)
+			}
+		}
+	})
+	t.Run("render cancellation", func(t *testing.T) {
+		event := &CalendarEvent{Organizer: "alice@example.com", Start: start}
+		ics, err := event.ICS(CalendarMethodCancel)
+		if err != nil {
+			t.Fatalf("failed to render calendar event: %s", err)
+		}
+		if !bytes.Contains(ics, []byte("METHOD:CANCEL\r\n")) || !bytes.Contains(ics, []byte("STATUS:CANCELLED\r\n")) {
+			t.Errorf("expected cancelled calendar object, got:\n%s", ics)
+		}
+		if bytes.Contains(ics, []byte("DTEND:")) {
+			t.Errorf("expected no DTEND for event without end time, got:\n%s", ics)
+		}
+		if event.UID == "" {
+			t.Error("expected UID to be generated")
+		}
+	})
+	t.Run("render invalid events", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			event  CalendarEvent
+			method CalendarMethod
+			want   error
+		}{
+			{"no start", CalendarEvent{Organizer: "alice@example.com"}, CalendarMethodRequest, ErrCalendarNoStart},
+			{
+				"end before start", CalendarEvent{Organizer: "alice@example.com", Start: start, End: start.Add(-1)},
+				CalendarMethodRequest, ErrCalendarInvalidEnd,
+			},
+			{"no organizer", CalendarEvent{Start: start}, CalendarMethodRequest, ErrCalendarNoOrganizer},
+			{
+				"reply without attendee", CalendarEvent{Organizer: "alice@example.com", Start: start},
+				CalendarMethodReply, ErrCalendarNoAttendee,
+			},
+			{
+				"URL with line break", CalendarEvent{
+					Organizer: "alice@example.com", Start: start,
+					URL: "https://example.com/\r\nATTENDEE:mailto:mallory@example.com",
+				},
+				CalendarMethodRequest, ErrCalendarInvalidURL,
+			},
+			{
+				"URL with line feed", CalendarEvent{
+					Organizer: "alice@example.com", Start: start, URL: "https://example.com/\nMETHOD:CANCEL",
+				},
+				CalendarMethodRequest, ErrCalendarInvalidURL,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, err := tt.event.ICS(tt.method); !errors.Is(err, tt.want) {
+					t.Errorf("expected error: %s, got: %s", tt.want, err)
+				}
+			})
+		}
+	})
+	t.Run("render with invalid addresses", func(t *testing.T) {
+		event := &CalendarEvent{Organizer: "invalid", Start: start}
+		if _, err := event.ICS(CalendarMethodRequest); err == nil {
+			t.Error("expected error for invalid organizer address")
+		}
+		event = &CalendarEvent{
+			Attendees: []CalendarAttendee{{Address: "invalid"}},
+			Organizer: "alice@example.com", Start: start,
+		}
+		if _, err := event.ICS(CalendarMethodRequest); err == nil {
+			t.Error("expected error for invalid attendee address")
+		}
+	})
+}
+
+func TestMsg_SetCalendarInvite(t *testing.T) {
+	start := time.Now().Add(time.Hour * 24)
+	t.Run("invite uses from address as organizer", func(t *testing.T) {
+		message := testMessage(t)
+		event := &CalendarEvent{
+			Attendees: []CalendarAttendee{{Address: TestRcptValid, RSVP: true}},
+			Start:     start,
+			Summary:   "Planning",
+		}
+		if err := message.SetCalendarInvite(event); err != nil {
+			t.Fatalf("failed to set calendar invite: %s", err)
+		}
+		if event.Organizer != "<valid-from@domain.tld>" {
+			t.Errorf("expected organizer to be set from From address, got: %s", event.Organizer)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if !strings.Contains(buffer.String(), "Content-Type: multipart/alternative") {
+			t.Errorf("expected multipart/alternative message, got:\n%s", buffer.String())
+		}
+		if !strings.Contains(buffer.String(), "Content-Type: text/calendar; method=REQUEST; charset=UTF-8") {
+			t.Errorf("expected text/calendar part, got:\n%s", buffer.String())
+		}
+	})
+	t.Run("reply and cancel", func(t *testing.T) {
+		message := testMessage(t)
+		event := &CalendarEvent{
+			Attendees: []CalendarAttendee{{Address: TestRcptValid, PartStat: CalendarPartStatDeclined}},
+			Organizer: TestSenderValid, Start: start, UID: "event@example.com",
+		}
+		if err := message.SetCalendarReply(event); err != nil {
+			t.Fatalf("failed to set calendar reply: %s", err)
+		}
+		if err := message.SetCalendarCancel(event); err != nil {
+			t.Fatalf("failed to set calendar cancellation: %s", err)
+		}
+		parts := message.GetParts()
+		if len(parts) != 3 {
+			t.Fatalf("expected 3 parts, got: %d", len(parts))
+		}
+		if parts[1].GetContentType() != "text/calendar; method=REPLY" {
+			t.Errorf("unexpected content type for reply: %s", parts[1].GetContentType())
+		}
+		if parts[2].GetContentType() != "text/calendar; method=CANCEL" {
+			t.Errorf("unexpected content type for cancellation: %s", parts[2].GetContentType())
+		}
+	})
+	t.Run("invite fails without organizer", func(t *testing.T) {
+		message := NewMsg()
+		if err := message.SetCalendarInvite(&CalendarEvent{Start: start}); !errors.Is(err, ErrCalendarNoOrganizer) {
+			t.Errorf("expected error: %s, got: %s", ErrCalendarNoOrganizer, err)
+		}
+	})
+	t.Run("invite fails with nil event", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.SetCalendarInvite(nil); !errors.Is(err, ErrCalendarNilEvent) {
+			t.Errorf("expected error: %s, got: %s", ErrCalendarNilEvent, err)
+		}
+	})
+	t.Run("invite fails with invalid event", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.SetCalendarInvite(&CalendarEvent{}); !errors.Is(err, ErrCalendarNoStart) {
+			t.Errorf("expected error: %s, got: %s", ErrCalendarNoStart, err)
+		}
+		if len(message.GetParts()) != 1 {
+			t.Errorf("expected no part to be added for invalid event, got: %d parts", len(message.GetParts()))
+		}
+	})
+}
+
+func TestFoldCalendarLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("ä", 80)
+	folded := foldCalendarLine(line)
+	for _, part := range strings.Split(folded, "\r\n") {
+		if len(part) > calendarLineLength {
+			t.Errorf("expected folded line to be at most %d octets, got: %d", calendarLineLength, len(part))
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != line {
+		t.Errorf("expected unfolded line to match original")
+	}
+}
@@ -0,0 +1,380 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/bits"
+	"sort"
+	"unicode"
+	"unicode/utf16"
+)
+
+const (
+	// cfbDirEntrySize is the size of a directory entry of a compound file.
+	cfbDirEntrySize = 128
+
+	// cfbDIFATHeaderEntries is the number of DIFAT entries in the header of a compound file.
+	cfbDIFATHeaderEntries = 109
+
+	// cfbMiniSectorSize is the size of a sector of the mini stream of a compound file.
+	cfbMiniSectorSize = 64
+
+	// cfbMiniStreamCutoff is the size from which a stream is stored in regular sectors instead of
+	// the mini stream.
+	cfbMiniStreamCutoff = 4096
+
+	// cfbSectorSize is the size of a sector of a version 3 compound file.
+	cfbSectorSize = 512
+
+	// cfbDIFSect marks a DIFAT sector in the FAT.
+	cfbDIFSect uint32 = 0xfffffffc
+
+	// cfbEndOfChain marks the last sector of a chain.
+	cfbEndOfChain uint32 = 0xfffffffe
+
+	// cfbFATSect marks a FAT sector in the FAT.
+	cfbFATSect uint32 = 0xfffffffd
+
+	// cfbFreeSect marks an unused sector.
+	cfbFreeSect uint32 = 0xffffffff
+
+	// cfbNoStream marks the absence of a sibling or child directory entry.
+	cfbNoStream uint32 = 0xffffffff
+)
+
+// cfbSignature is the signature at the start of a compound file.
+var cfbSignature = []byte{0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1}
+
+type (
+	// cfbEntry is a storage or stream of a compound file.
+	cfbEntry struct {
+		// children holds the storages and streams of a storage.
+		children []*cfbEntry
+
+		// data holds the content of a stream.
+		data []byte
+
+		// name is the name of the storage or stream.
+		name string
+
+		// storage indicates that the entry is a storage.
+		storage bool
+	}
+
+	// cfbDirEntry is a directory entry of a compound file while it is written.
+	cfbDirEntry struct {
+		*cfbEntry
+
+		// child is the index of the root of the red-black tree of the children of a storage.
+		child uint32
+
+		// left is the index of the left sibling in the red-black tree.
+		left uint32
+
+		// red indicates that the entry is a red node of the red-black tree.
+		red bool
+
+		// right is the index of the right sibling in the red-black tree.
+		right uint32
+
+		// size is the size of the stream.
+		size int
+
+		// start is the first sector of the stream.
+		start uint32
+	}
+
+	// cfbSectors holds the regular sectors of a compound file and their FAT chains.
+	cfbSectors struct {
+		// data holds the content of the sectors.
+		data bytes.Buffer
+
+		// fat holds the FAT entries of the sectors.
+		fat []uint32
+	}
+)
+
+// newCFBStorage returns a new storage with the given name.
+func newCFBStorage(name string) *cfbEntry {
+	return &cfbEntry{name: name, storage: true}
+}
+
+// addStorage adds a new storage with the given name to the storage and returns it.
+func (e *cfbEntry) addStorage(name string) *cfbEntry {
+	child := newCFBStorage(name)
+	e.children = append(e.children, child)
+	return child
+}
+
+// addStream adds a new stream with the given name and content to the storage.
+func (e *cfbEntry) addStream(name string, data []byte) {
+	e.children = append(e.children, &cfbEntry{data: data, name: name})
+}
+
+// writeCFB writes the given root storage as a version 3 compound file to the given io.Writer.
+//
+// Parameters:
+//   - writer: The io.Writer to write the compound file to.
+//   - root: The root storage of the compound file.
+//
+// Returns:
+//   - The number of bytes written.
+//   - An error if writing fails; otherwise, returns nil.
+//
+// References:
+//   - https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-cfb
+func writeCFB(writer io.Writer, root *cfbEntry) (int64, error) {
+	entries := []*cfbDirEntry{{cfbEntry: root, left: cfbNoStream, right: cfbNoStream}}
+	cfbAddChildren(&entries, 0)
+
+	// Streams smaller than the cutoff are stored in the mini stream, all others in regular sectors.
+	var miniStream bytes.Buffer
+	var miniFAT []uint32
+	var largeEntries []*cfbDirEntry
+	for _, entry := range entries[1:] {
+		entry.size = len(entry.data)
+		entry.start = cfbEndOfChain
+		switch {
+		case entry.storage:
+			entry.start = 0
+		case entry.size >= cfbMiniStreamCutoff:
+			largeEntries = append(largeEntries, entry)
+		case entry.size > 0:
+			entry.start = uint32(len(miniFAT))
+			miniFAT = cfbAppendChain(miniFAT, cfbSectorCount(entry.size, cfbMiniSectorSize))
+			miniStream.Write(entry.data)
+			miniStream.Write(make([]byte, len(miniFAT)*cfbMiniSectorSize-miniStream.Len()))
+		}
+	}
+
+	sectors := &cfbSectors{}
+	for _, entry := range largeEntries {
+		entry.start = sectors.add(entry.data)
+	}
+	entries[0].size = miniStream.Len()
+	entries[0].start = sectors.add(miniStream.Bytes())
+	miniFATSectors := cfbSectorCount(len(miniFAT)*4, cfbSectorSize)
+	miniFATStart := sectors.add(cfbEncodeUint32s(miniFAT, miniFATSectors*cfbSectorSize/4))
+	dirBuffer := bytes.NewBuffer(nil)
+	for i, entry := range entries {
+		dirBuffer.Write(entry.encode(i == 0))
+	}
+	for dirBuffer.Len()%cfbSectorSize != 0 {
+		dirBuffer.Write(cfbUnusedDirEntry())
+	}
+	dirStart := sectors.add(dirBuffer.Bytes())
+
+	// The FAT must also cover its own sectors and the DIFAT sectors.
+	dataSectors := len(sectors.fat)
+	fatSectors, difatSectors := 1, 0
+	for {
+		if fatSectors > cfbDIFATHeaderEntries {
+			difatSectors = cfbSectorCount((fatSectors-cfbDIFATHeaderEntries)*4, cfbSectorSize-4)
+		}
+		if dataSectors+fatSectors+difatSectors <= fatSectors*cfbSectorSize/4 {
+			break
+		}
+		fatSectors++
+	}
+	difat := make([]uint32, fatSectors)
+	for i := range difat {
+		difat[i] = uint32(dataSectors + i)
+		sectors.fat = append(sectors.fat, cfbFATSect)
+	}
+	for i := 0; i < difatSectors; i++ {
+		sectors.fat = append(sectors.fat, cfbDIFSect)
+	}
+
+	header := make([]byte, cfbSectorSize)
+	copy(header, cfbSignature)
+	binary.LittleEndian.PutUint16(header[24:], 0x003e)
+	binary.LittleEndian.PutUint16(header[26:], 0x0003)
+	binary.LittleEndian.PutUint16(header[28:], 0xfffe)
+	binary.LittleEndian.PutUint16(header[30:], 9)
+	binary.LittleEndian.PutUint16(header[32:], 6)
+	binary.LittleEndian.PutUint32(header[44:], uint32(fatSectors))
+	binary.LittleEndian.PutUint32(header[48:], dirStart)
+	binary.LittleEndian.PutUint32(header[56:], cfbMiniStreamCutoff)
+	binary.LittleEndian.PutUint32(header[60:], miniFATStart)
+	binary.LittleEndian.PutUint32(header[64:], uint32(miniFATSectors))
+	binary.LittleEndian.PutUint32(header[68:], cfbEndOfChain)
+	if difatSectors > 0 {
+		binary.LittleEndian.PutUint32(header[68:], uint32(dataSectors+fatSectors))
+	}
+	binary.LittleEndian.PutUint32(header[72:], uint32(difatSectors))
+	for i := 0; i < cfbDIFATHeaderEntries; i++ {
+		value := cfbFreeSect
+		if i < len(difat) {
+			value = difat[i]
+		}
+		binary.LittleEndian.PutUint32(header[76+i*4:], value)
+	}
+
+	output := bytes.NewBuffer(header)
+	output.Write(sectors.data.Bytes())
+	output.Write(cfbEncodeUint32s(sectors.fat, fatSectors*cfbSectorSize/4))
+	remaining := difat
+	if len(remaining) > cfbDIFATHeaderEntries {
+		remaining = remaining[cfbDIFATHeaderEntries:]
+	} else {
+		remaining = nil
+	}
+	for i := 0; i < difatSectors; i++ {
+		count := len(remaining)
+		if count > cfbSectorSize/4-1 {
+			count = cfbSectorSize/4 - 1
+		}
+		next := cfbEndOfChain
+		if i < difatSectors-1 {
+			next = uint32(dataSectors + fatSectors + i + 1)
+		}
+		output.Write(cfbEncodeUint32s(remaining[:count], cfbSectorSize/4-1))
+		output.Write(cfbEncodeUint32s([]uint32{next}, 1))
+		remaining = remaining[count:]
+	}
+	return output.WriteTo(writer)
+}
+
+// cfbAddChildren appends the children of the storage at the given index to the directory entries,
+// arranges them in a red-black tree and recurses into the child storages.
+func cfbAddChildren(entries *[]*cfbDirEntry, index int) {
+	parent := (*entries)[index]
+	parent.child = cfbNoStream
+	if !parent.storage || len(parent.children) == 0 {
+		return
+	}
+	children := make([]*cfbEntry, len(parent.children))
+	copy(children, parent.children)
+	sort.SliceStable(children, func(i, j int) bool {
+		return cfbCompareNames(children[i].name, children[j].name) < 0
+	})
+	indexes := make([]int, len(children))
+	for i, child := range children {
+		indexes[i] = len(*entries)
+		*entries = append(*entries, &cfbDirEntry{cfbEntry: child})
+	}
+	parent.child = cfbBuildTree(*entries, indexes, 0, bits.Len(uint(len(indexes)))-1)
+	for _, childIndex := range indexes {
+		cfbAddChildren(entries, childIndex)
+	}
+}
+
+// cfbBuildTree arranges the sorted directory entries at the given indexes in a balanced binary tree
+// and returns the index of its root. The nodes on the deepest level are colored red, which keeps the
+// number of black nodes equal on all paths, as required for a red-black tree.
+func cfbBuildTree(entries []*cfbDirEntry, indexes []int, depth, maxDepth int) uint32 {
+	if len(indexes) == 0 {
+		return cfbNoStream
+	}
+	mid := len(indexes) / 2
+	node := entries[indexes[mid]]
+	node.left = cfbBuildTree(entries, indexes[:mid], depth+1, maxDepth)
+	node.right = cfbBuildTree(entries, indexes[mid+1:], depth+1, maxDepth)
+	node.red = depth == maxDepth && depth > 0
+	return uint32(indexes[mid])
+}
+
+// cfbCompareNames compares two directory entry names in the order required for the red-black
+// tree: shorter names come first, names of equal length are compared by their upper case.
+func cfbCompareNames(a, b string) int {
+	aUnits, bUnits := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	if len(aUnits) != len(bUnits) {
+		return len(aUnits) - len(bUnits)
+	}
+	for i := range aUnits {
+		aUpper, bUpper := unicode.ToUpper(rune(aUnits[i])), unicode.ToUpper(rune(bUnits[i]))
+		if aUpper != bUpper {
+			return int(aUpper) - int(bUpper)
+		}
+	}
+	return 0
+}
+
+// encode returns the binary representation of the directory entry. The root storage is encoded
+// as root entry.
+func (e *cfbDirEntry) encode(root bool) []byte {
+	entry := make([]byte, cfbDirEntrySize)
+	units := utf16.Encode([]rune(e.name))
+	if len(units) > 31 {
+		units = units[:31]
+	}
+	for i, unit := range units {
+		binary.LittleEndian.PutUint16(entry[i*2:], unit)
+	}
+	binary.LittleEndian.PutUint16(entry[64:], uint16((len(units)+1)*2))
+	switch {
+	case root:
+		entry[66] = 5
+	case e.storage:
+		entry[66] = 1
+	default:
+		entry[66] = 2
+	}
+	if !e.red {
+		entry[67] = 1
+	}
+	binary.LittleEndian.PutUint32(entry[68:], e.left)
+	binary.LittleEndian.PutUint32(entry[72:], e.right)
+	binary.LittleEndian.PutUint32(entry[76:], e.child)
+	binary.LittleEndian.PutUint32(entry[116:], e.start)
+	binary.LittleEndian.PutUint32(entry[120:], uint32(e.size))
+	return entry
+}
+
+// cfbUnusedDirEntry returns the binary representation of an unused directory entry.
+func cfbUnusedDirEntry() []byte {
+	entry := make([]byte, cfbDirEntrySize)
+	binary.LittleEndian.PutUint32(entry[68:], cfbNoStream)
+	binary.LittleEndian.PutUint32(entry[72:], cfbNoStream)
+	binary.LittleEndian.PutUint32(entry[76:], cfbNoStream)
+	return entry
+}
+
+// add appends the given data to the sectors, padded to full sectors, and returns the first sector
+// of its chain, or cfbEndOfChain if the data is empty.
+func (s *cfbSectors) add(data []byte) uint32 {
+	if len(data) == 0 {
+		return cfbEndOfChain
+	}
+	start := uint32(len(s.fat))
+	count := cfbSectorCount(len(data), cfbSectorSize)
+	s.fat = cfbAppendChain(s.fat, count)
+	s.data.Write(data)
+	s.data.Write(make([]byte, count*cfbSectorSize-len(data)))
+	return start
+}
+
+// cfbAppendChain appends a chain of the given number of consecutive sectors to the given
+// allocation table.
+func cfbAppendChain(table []uint32, count int) []uint32 {
+	start := uint32(len(table))
+	for i := 1; i < count; i++ {
+		table = append(table, start+uint32(i))
+	}
+	return append(table, cfbEndOfChain)
+}
+
+// cfbSectorCount returns the number of sectors of the given size that are needed for the given
+// number of bytes.
+func cfbSectorCount(length, sectorSize int) int {
+	return (length + sectorSize - 1) / sectorSize
+}
+
+// cfbEncodeUint32s returns the little-endian representation of the given values, padded with
+// cfbFreeSect to the given number of values.
+func cfbEncodeUint32s(values []uint32, count int) []byte {
+	data := make([]byte, count*4)
+	for i := 0; i < count; i++ {
+		value := cfbFreeSect
+		if i < len(values) {
+			value = values[i]
+		}
+		binary.LittleEndian.PutUint32(data[i*4:], value)
+	}
+	return data
+}
@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"unicode/utf16"
+)
+
+// readTestCFB parses the given compound file and returns the content of its streams by their path,
+// i. e. "storage/stream". It fails the test if the structure of the file or the red-black trees of
+// the directory are invalid.
+func readTestCFB(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	if len(data) < cfbSectorSize || !bytes.Equal(data[:8], cfbSignature) || len(data)%cfbSectorSize != 0 {
+		t.Fatal("invalid compound file header")
+	}
+	uint32At := func(buffer []byte, offset int) uint32 {
+		return binary.LittleEndian.Uint32(buffer[offset:])
+	}
+	sector := func(index uint32) []byte {
+		offset := int(index+1) * cfbSectorSize
+		if offset+cfbSectorSize > len(data) {
+			t.Fatalf("sector %d out of range", index)
+		}
+		return data[offset : offset+cfbSectorSize]
+	}
+	fatSectors := make([]uint32, 0)
+	for i := 0; i < cfbDIFATHeaderEntries && len(fatSectors) < int(uint32At(data, 44)); i++ {
+		fatSectors = append(fatSectors, uint32At(data, 76+i*4))
+	}
+	for next := uint32At(data, 68); next != cfbEndOfChain; {
+		difat := sector(next)
+		for i := 0; i < cfbSectorSize/4-1 && len(fatSectors) < int(uint32At(data, 44)); i++ {
+			fatSectors = append(fatSectors, uint32At(difat, i*4))
+		}
+		next = uint32At(difat, cfbSectorSize-4)
+	}
+	var fat []uint32
+	for _, index := range fatSectors {
+		for i := 0; i < cfbSectorSize/4; i++ {
+			fat = append(fat, uint32At(sector(index), i*4))
+		}
+	}
+	chain := func(table []uint32, start uint32, read func(uint32) []byte) []byte {
+		var result []byte
+		for index, count := start, 0; index != cfbEndOfChain; index, count = table[index], count+1 {
+			if int(index) >= len(table) || count > len(table) {
+				t.Fatalf("invalid sector chain starting at %d", start)
+			}
+			result = append(result, read(index)...)
+		}
+		return result
+	}
+	directory := chain(fat, uint32At(data, 48), sector)
+	var miniFAT []uint32
+	miniFATData := chain(fat, uint32At(data, 60), sector)
+	for i := 0; i < len(miniFATData); i += 4 {
+		miniFAT = append(miniFAT, uint32At(miniFATData, i))
+	}
+	entry := func(index uint32) []byte {
+		return directory[int(index)*cfbDirEntrySize : int(index+1)*cfbDirEntrySize]
+	}
+	name := func(index uint32) string {
+		raw := entry(index)
+		units := make([]uint16, int(binary.LittleEndian.Uint16(raw[64:]))/2-1)
+		for i := range units {
+			units[i] = binary.LittleEndian.Uint16(raw[i*2:])
+		}
+		return string(utf16.Decode(units))
+	}
+	root := entry(0)
+	miniStream := chain(fat, uint32At(root, 116), sector)
+
+	streams := make(map[string][]byte)
+	var checkTree func(index uint32, prefix string)
+	var walk func(index uint32, prefix string, depth int) ([]string, int)
+	walk = func(index uint32, prefix string, depth int) ([]string, int) {
+		if index == cfbNoStream {
+			return nil, 0
+		}
+		if depth > 64 {
+			t.Fatal("directory tree is too deep")
+		}
+		raw := entry(index)
+		left, leftBlack := walk(uint32At(raw, 68), prefix, depth+1)
+		right, rightBlack := walk(uint32At(raw, 72), prefix, depth+1)
+		if leftBlack != rightBlack {
+			t.Errorf("unequal black height below %s", name(index))
+		}
+		black := leftBlack
+		if raw[67] == 1 {
+			black++
+		} else if (uint32At(raw, 68) != cfbNoStream && entry(uint32At(raw, 68))[67] == 0) ||
+			(uint32At(raw, 72) != cfbNoStream && entry(uint32At(raw, 72))[67] == 0) {
+			t.Errorf("red node %s has a red child", name(index))
+		}
+		path := prefix + name(index)
+		switch raw[66] {
+		case 1:
+			checkTree(uint32At(raw, 76), path+"/")
+		case 2:
+			size := int(uint32At(raw, 120))
+			content := []byte{}
+			if size > 0 && size < cfbMiniStreamCutoff {
+				content = chain(miniFAT, uint32At(raw, 116), func(index uint32) []byte {
+					return miniStream[int(index)*cfbMiniSectorSize : int(index+1)*cfbMiniSectorSize]
+				})
+			} else if size > 0 {
+				content = chain(fat, uint32At(raw, 116), sector)
+			}
+			streams[path] = content[:size]
+		default:
+			t.Errorf("unexpected object type %d of %s", raw[66], path)
+		}
+		names := append(append(left, name(index)), right...)
+		return names, black
+	}
+	if root[66] != 5 {
+		t.Fatalf("unexpected root entry type: %d", root[66])
+	}
+	checkTree = func(index uint32, prefix string) {
+		names, _ := walk(index, prefix, 0)
+		for i := 1; i < len(names); i++ {
+			if cfbCompareNames(names[i-1], names[i]) >= 0 {
+				t.Errorf("directory entries %s and %s are not in order", names[i-1], names[i])
+			}
+		}
+	}
+	checkTree(uint32At(root, 76), "")
+	return streams
+}
+
+func TestWriteCFB(t *testing.T) {
+	large := bytes.Repeat([]byte("0123456789abcdef"), 512)
+	root := newCFBStorage("Root Entry")
+	root.addStream("small", []byte("small stream"))
+	root.addStream("empty", nil)
+	root.addStream("large", large)
+	storage := root.addStorage("storage")
+	for i := 0; i < 25; i++ {
+		storage.addStream(fmt.Sprintf("stream%d", i), []byte(fmt.Sprintf("content %d", i)))
+	}
+	buffer := bytes.NewBuffer(nil)
+	n, err := writeCFB(buffer, root)
+	if err != nil {
+		t.Fatalf("failed to write compound file: %s", err)
+	}
+	if n != int64(buffer.Len()) {
+		t.Errorf("expected %d bytes written, got: %d", buffer.Len(), n)
+	}
+	streams := readTestCFB(t, buffer.Bytes())
+	if len(streams) != 28 {
+		t.Errorf("expected 28 streams, got: %d", len(streams))
+	}
+	if string(streams["small"]) != "small stream" || len(streams["empty"]) != 0 ||
+		!bytes.Equal(streams["large"], large) || string(streams["storage/stream17"]) != "content 17" {
+		t.Error("unexpected stream content")
+	}
+}
+
+func TestWriteCFB_DIFAT(t *testing.T) {
+	large := bytes.Repeat([]byte{0xab}, cfbDIFATHeaderEntries*cfbSectorSize*cfbSectorSize/4+1)
+	root := newCFBStorage("Root Entry")
+	root.addStream("large", large)
+	buffer := bytes.NewBuffer(nil)
+	if _, err := writeCFB(buffer, root); err != nil {
+		t.Fatalf("failed to write compound file: %s", err)
+	}
+	if difatSectors := binary.LittleEndian.Uint32(buffer.Bytes()[72:]); difatSectors != 1 {
+		t.Errorf("expected 1 DIFAT sector, got: %d", difatSectors)
+	}
+	if streams := readTestCFB(t, buffer.Bytes()); !bytes.Equal(streams["large"], large) {
+		t.Error("unexpected stream content")
+	}
+}
+
+func TestCfbCompareNames(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"b", "aa", -1},
+		{"abc", "ABD", -1},
+		{"abc", "ABC", 0},
+		{"__substg1.0_0037001F", "__properties_version1.0", -1},
+	}
+	for _, tt := range tests {
+		got := cfbCompareNames(tt.a, tt.b)
+		if (got < 0 && tt.want >= 0) || (got == 0 && tt.want != 0) || (got > 0 && tt.want <= 0) {
+			t.Errorf("cfbCompareNames(%q, %q) = %d, want sign of %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
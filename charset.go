@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	// charsetAliases maps the lower-case alias of a charset to its canonical Charset.
+	charsetAliases = map[string]Charset{
+		"ascii":          CharsetASCII,
+		"us-ascii":       CharsetASCII,
+		"ansi_x3.4-1968": CharsetASCII,
+		"utf8":           CharsetUTF8,
+		"utf-8":          CharsetUTF8,
+		"utf7":           CharsetUTF7,
+		"utf-7":          CharsetUTF7,
+		"latin1":         CharsetISO88591,
+		"latin-1":        CharsetISO88591,
+		"l1":             CharsetISO88591,
+		"iso8859-1":      CharsetISO88591,
+		"iso_8859-1":     CharsetISO88591,
+		"iso-8859-1":     CharsetISO88591,
+		"latin2":         CharsetISO88592,
+		"iso8859-2":      CharsetISO88592,
+		"iso-8859-2":     CharsetISO88592,
+		"latin9":         CharsetISO885915,
+		"iso8859-15":     CharsetISO885915,
+		"iso-8859-15":    CharsetISO885915,
+		"cp1250":         CharsetWindows1250,
+		"windows-1250":   CharsetWindows1250,
+		"cp1251":         CharsetWindows1251,
+		"windows-1251":   CharsetWindows1251,
+		"cp1252":         CharsetWindows1252,
+		"windows-1252":   CharsetWindows1252,
+		"cp1255":         CharsetWindows1255,
+		"windows-1255":   CharsetWindows1255,
+		"cp1256":         CharsetWindows1256,
+		"windows-1256":   CharsetWindows1256,
+		"koi8r":          CharsetKOI8R,
+		"koi8-r":         CharsetKOI8R,
+		"koi8u":          CharsetKOI8U,
+		"koi8-u":         CharsetKOI8U,
+		"big5":           CharsetBig5,
+		"big-5":          CharsetBig5,
+		"gbk":            CharsetGBK,
+		"cp936":          CharsetGBK,
+		"gb2312":         CharsetGB2312,
+		"gb18030":        CharsetGB18030,
+		"euc-kr":         CharsetEUCKR,
+		"euckr":          CharsetEUCKR,
+		"ks_c_5601-1987": CharsetEUCKR,
+		"shift_jis":      CharsetShiftJIS,
+		"shift-jis":      CharsetShiftJIS,
+		"sjis":           CharsetShiftJIS,
+		"iso-2022-jp":    CharsetISO2022JP,
+		"iso-2022-kr":    CharsetISO2022KR,
+		"tis-620":        CharsetTIS620,
+		"tis620":         CharsetTIS620,
+	}
+
+	// charsetAliasMutex protects charsetAliases from concurrent access.
+	charsetAliasMutex sync.RWMutex
+)
+
+// RegisterCharsetAlias registers an alias for the given Charset.
+//
+// Aliases are used by the EML parser to map the charset names found in the wild to the canonical
+// Charset constants. The alias is case-insensitive. Registering an alias that already exists
+// overrides the existing mapping. RegisterCharsetAlias is safe for concurrent use.
+//
+// Parameters:
+//   - alias: The alias of the charset, i. e. "latin1".
+//   - charset: The canonical Charset the alias maps to.
+func RegisterCharsetAlias(alias string, charset Charset) {
+	alias = strings.ToLower(strings.TrimSpace(alias))
+	if alias == "" {
+		return
+	}
+	charsetAliasMutex.Lock()
+	defer charsetAliasMutex.Unlock()
+	charsetAliases[alias] = charset
+}
+
+// ParseCharset maps the given charset name to its canonical Charset.
+//
+// The lookup is case-insensitive and ignores surrounding whitespace and quotes. If no alias is
+// registered for the name, a possible "x-" prefix of a non-standard charset name is removed and
+// the lookup is repeated. If the name still cannot be mapped, it is returned unchanged as Charset,
+// so that unknown charsets are preserved.
+//
+// Parameters:
+//   - name: The charset name to map, i. e. the charset parameter of a "Content-Type" header.
+//
+// Returns:
+//   - The canonical Charset for the given name.
+func ParseCharset(name string) Charset {
+	alias := strings.ToLower(strings.Trim(strings.TrimSpace(name), `"'`))
+	charsetAliasMutex.RLock()
+	defer charsetAliasMutex.RUnlock()
+	if charset, ok := charsetAliases[alias]; ok {
+		return charset
+	}
+	if strings.HasPrefix(alias, "x-") {
+		if charset, ok := charsetAliases[strings.TrimPrefix(alias, "x-")]; ok {
+			return charset
+		}
+	}
+	return Charset(name)
+}
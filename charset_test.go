@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseCharset(t *testing.T) {
+	tests := []struct {
+		name string
+		want Charset
+	}{
+		{"utf8", CharsetUTF8},
+		{"UTF-8", CharsetUTF8},
+		{" Latin1 ", CharsetISO88591},
+		{`"iso8859-1"`, CharsetISO88591},
+		{"cp1252", CharsetWindows1252},
+		{"x-gbk", CharsetGBK},
+		{"X-SJIS", CharsetShiftJIS},
+		{"x-unknown", "x-unknown"},
+		{"ISO-8859-8", "ISO-8859-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseCharset(tt.name); got != tt.want {
+				t.Errorf("unexpected charset for %q, want: %s, got: %s", tt.name, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRegisterCharsetAlias(t *testing.T) {
+	t.Cleanup(func() {
+		charsetAliasMutex.Lock()
+		delete(charsetAliases, "x-mac-cyrillic-test")
+		delete(charsetAliases, "mac-cyrillic-test")
+		charsetAliasMutex.Unlock()
+	})
+	RegisterCharsetAlias("", CharsetKOI8R)
+	if _, ok := charsetAliases[""]; ok {
+		t.Error("expected empty alias not to be registered")
+	}
+	RegisterCharsetAlias("Mac-Cyrillic-Test", CharsetWindows1251)
+	if got := ParseCharset("mac-cyrillic-test"); got != CharsetWindows1251 {
+		t.Errorf("unexpected charset, want: %s, got: %s", CharsetWindows1251, got)
+	}
+	if got := ParseCharset("x-mac-cyrillic-test"); got != CharsetWindows1251 {
+		t.Errorf("unexpected charset, want: %s, got: %s", CharsetWindows1251, got)
+	}
+}
+
+func TestEMLToMsgFromString_charsetAlias(t *testing.T) {
+	t.Run("plain body with charset alias", func(t *testing.T) {
+		message, err := EMLToMsgFromString(fmt.Sprintf(exampleMailCharset, "text/plain; charset=latin1"))
+		if err != nil {
+			t.Fatalf("failed to parse EML: %s", err)
+		}
+		if message.Charset() != CharsetISO88591.String() {
+			t.Errorf("unexpected charset, want: %s, got: %s", CharsetISO88591, message.Charset())
+		}
+	})
+	t.Run("multipart body with x- charset", func(t *testing.T) {
+		content := "multipart/mixed; boundary=\"boundary\"\r\n\r\n--boundary\r\n" +
+			"Content-Type: text/plain; charset=x-gbk"
+		message, err := EMLToMsgFromString(fmt.Sprintf(exampleMailCharset, content) + "--boundary--\r\n")
+		if err != nil {
+			t.Fatalf("failed to parse EML: %s", err)
+		}
+		parts := message.GetParts()
+		if len(parts) != 1 {
+			t.Fatalf("expected 1 part, got: %d", len(parts))
+		}
+		if parts[0].GetCharset() != CharsetGBK {
+			t.Errorf("unexpected part charset, want: %s, got: %s", CharsetGBK, parts[0].GetCharset())
+		}
+	})
+}
+
+// exampleMailCharset is an EML with a placeholder for the Content-Type header
+const exampleMailCharset = "Date: Tue, 01 Oct 2024 12:00:00 +0000\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"From: <valid-from@domain.tld>\r\n" +
+	"To: <valid-to@domain.tld>\r\n" +
+	"Subject: Testmail\r\n" +
+	"Content-Type: %s\r\n" +
+	"Content-Transfer-Encoding: 7bit\r\n" +
+	"\r\n" +
+	"Testmail\r\n"
@@ -9,7 +9,9 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/mail"
 	"os"
 	"strings"
 	"sync"
@@ -83,6 +85,26 @@ const (
 )
 
 type (
+	// CommandTimeouts holds distinct timeouts for the phases of the SMTP session. A zero duration means
+	// that the phase uses the connection timeout of the Client.
+	CommandTimeouts struct {
+		// Connect is the timeout for establishing the connection and receiving the server greeting.
+		Connect time.Duration
+
+		// Hello is the timeout for the EHLO/HELO command and the STARTTLS negotiation.
+		Hello time.Duration
+
+		// Auth is the timeout for the SMTP authentication.
+		Auth time.Duration
+
+		// Envelope is the timeout for the MAIL FROM and RCPT TO commands of a message.
+		Envelope time.Duration
+
+		// Data is the timeout for the DATA command, the transmission of the message and the response
+		// of the server to the termination of the DATA phase.
+		Data time.Duration
+	}
+
 	// DialContextFunc defines a function type for establishing a network connection using context, network
 	// type, and address. It is used to specify custom DialContext function.
 	//
@@ -114,9 +136,31 @@ type (
 	//   - https://datatracker.ietf.org/doc/html/rfc3207#section-2
 	//   - https://datatracker.ietf.org/doc/html/rfc8314
 	Client struct {
+		// authQuirks holds the AuthQuirk flags that adjust the authentication for nonstandard servers.
+		authQuirks AuthQuirk
+
+		// commandTimeouts holds the timeouts for the individual phases of the SMTP session.
+		commandTimeouts CommandTimeouts
+
 		// connTimeout specifies timeout for the connection to the SMTP server.
 		connTimeout time.Duration
 
+		// dane holds the configuration of the DANE verification, if enabled with WithDANE.
+		dane *daneConfig
+
+		// daneRecords holds the usable TLSA records of the server that were looked up when connecting.
+		daneRecords []TLSARecord
+
+		// debugHook is the smtp.DebugHook that is called for each line of the SMTP protocol.
+		debugHook smtp.DebugHook
+
+		// defaultFrom is the "From" address that is set on each Msg without a "From" address at send time.
+		defaultFrom string
+
+		// defaultReplyTo is the "Reply-To" address that is set on each Msg without a "Reply-To" address
+		// at send time.
+		defaultReplyTo string
+
 		// dialContextFunc is the DialContextFunc that is used by the Client to connect to the SMTP server.
 		dialContextFunc DialContextFunc
 
@@ -128,6 +172,10 @@ type (
 		// email.
 		dsnReturnType DSNMailReturnOption
 
+		// eightBitFallback is the Encoding used to re-encode 8bit messages for SMTP servers that do not
+		// support 8BITMIME. If empty, such messages are rejected.
+		eightBitFallback Encoding
+
 		// fallbackPort is used as an alternative port number in case the primary port is unavailable or
 		// fails to bind.
 		//
@@ -142,6 +190,10 @@ type (
 		// host is the hostname of the SMTP server we are connecting to.
 		host string
 
+		// hostProfileStore is an optional HostProfileStore that records the SMTP features negotiated
+		// with the server on each successful dial.
+		hostProfileStore HostProfileStore
+
 		// isEncrypted indicates wether the Client connection is encrypted or not.
 		isEncrypted bool
 
@@ -151,22 +203,55 @@ type (
 		// logger is a logger that satisfies the log.Logger interface.
 		logger log.Logger
 
+		// maxMessageSize is the maximum size in bytes of a Msg that is sent by the Client. A value of
+		// zero disables the client-side size check.
+		maxMessageSize int64
+
+		// metricsCollector is the MetricsCollector that is called with the metrics of the Client.
+		metricsCollector MetricsCollector
+
 		// mutex is used to synchronize access to shared resources, ensuring that only one goroutine can
 		// modify them at a time.
 		mutex sync.RWMutex
 
+		// noInitialResponse holds the SASL mechanisms for which the initial response is not sent with
+		// the AUTH command. An empty, non-nil slice disables the initial response for all mechanisms.
+		noInitialResponse []string
+
 		// noNoop indicates that the Client should skip the "NOOP" command during the dial.
 		//
 		// This is useful for servers which delay potentially unwanted clients when they perform commands
 		// other than AUTH.
 		noNoop bool
 
+		// noPipelining indicates that the Client should not use the PIPELINING extension, even if the
+		// server advertises it.
+		noPipelining bool
+
+		// noReplyGuard holds the configuration of the no-reply guard, if enabled with WithNoReplyGuard.
+		noReplyGuard *noReplyGuard
+
+		// partialDelivery indicates that a Msg is delivered to the accepted recipients, even if some of
+		// its recipients were rejected.
+		partialDelivery bool
+
 		// pass represents a password or a secret token used for the SMTP authentication.
 		pass string
 
+		// policyRules holds the PolicyRule values of the content policy that is evaluated for each Msg
+		// before its delivery.
+		policyRules []PolicyRule
+
 		// port specifies the network port that is used to establish the connection with the SMTP server.
 		port int
 
+		// quotaManager is the QuotaManager that is consulted before each Msg is sent.
+		quotaManager QuotaManager
+
+		// rcptGraylist holds the lower-cased addresses of the recipients that have been permanently
+		// rejected, if the recipient graylist is enabled with WithRecipientGraylist.
+		rcptGraylist map[string]struct{}
+
 		// requestDSN indicates wether we want to request DSN (Delivery Status Notifications).
 		requestDSN bool
 
@@ -191,6 +276,10 @@ type (
 		// tlsconfig is a pointer to tls.Config that specifies the TLS configuration for the STARTTLS communication.
 		tlsconfig *tls.Config
 
+		// unixSocket is the path of the Unix domain socket the Client connects to. If set, the Client
+		// connects to the socket instead of host and port.
+		unixSocket string
+
 		// useDebugLog indicates whether debug level logging is enabled for the Client.
 		useDebugLog bool
 
@@ -229,6 +318,16 @@ var (
 	// ErrServerNoUnencoded indicates that the server does not support 8BITMIME for unencoded 8-bit messages.
 	ErrServerNoUnencoded = errors.New("message is 8bit unencoded, but server does not support 8BITMIME")
 
+	// ErrServerNoRequireTLS indicates that the server does not support REQUIRETLS for messages that require TLS.
+	ErrServerNoRequireTLS = errors.New("message requires TLS, but server does not support REQUIRETLS")
+
+	// ErrRequireTLSNotEncrypted indicates that the connection is not encrypted for a message that requires TLS.
+	ErrRequireTLSNotEncrypted = errors.New("message requires TLS, but the connection is not encrypted")
+
+	// ErrRequireTLSNotVerified indicates that the server certificate is not verified for a message that
+	// requires TLS.
+	ErrRequireTLSNotVerified = errors.New("message requires TLS, but the server certificate is not verified")
+
 	// ErrInvalidDSNMailReturnOption is returned when an invalid DSNMailReturnOption is provided as argument
 	// to the WithDSN Option.
 	ErrInvalidDSNMailReturnOption = errors.New("DSN mail return option can only be HDRS or FULL")
@@ -248,6 +347,21 @@ var (
 
 	// ErrDialContextFuncIsNil indicates that a required dial context function is not provided.
 	ErrDialContextFuncIsNil = errors.New("dial context function is nil")
+
+	// ErrDebugHookIsNil indicates that a required debug hook function is not provided.
+	ErrDebugHookIsNil = errors.New("debug hook function is nil")
+
+	// ErrMetricsCollectorIsNil indicates that a required MetricsCollector is not provided.
+	ErrMetricsCollectorIsNil = errors.New("metrics collector is nil")
+
+	// ErrQuotaManagerIsNil indicates that a required QuotaManager is not provided.
+	ErrQuotaManagerIsNil = errors.New("quota manager is nil")
+
+	// ErrInvalidMaxMessageSize is returned when the maximum message size provided is not greater than zero.
+	ErrInvalidMaxMessageSize = errors.New("maximum message size must be greater than zero")
+
+	// ErrInvalidUnixSocket is returned when the specified path of the Unix domain socket is empty.
+	ErrInvalidUnixSocket = errors.New("unix socket path cannot be empty")
 )
 
 // NewClient creates a new Client instance with the provided host and optional configuration Option functions.
@@ -338,6 +452,29 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithCommandTimeouts sets distinct timeouts for the phases of the SMTP session.
+//
+// Mail providers have very different latency profiles per phase, i. e. a quick greeting but a slow
+// authentication backend or a long-running content scan after the DATA phase. Each phase for which
+// no timeout is given uses the connection timeout of the Client, as set with WithTimeout.
+//
+// Parameters:
+//   - timeouts: The CommandTimeouts for the phases of the SMTP session. None of them may be negative.
+//
+// Returns:
+//   - An Option function that sets the timeouts for the phases of the SMTP session.
+//   - An error if any of the timeouts is negative.
+func WithCommandTimeouts(timeouts CommandTimeouts) Option {
+	return func(c *Client) error {
+		if timeouts.Connect < 0 || timeouts.Hello < 0 || timeouts.Auth < 0 || timeouts.Envelope < 0 ||
+			timeouts.Data < 0 {
+			return ErrInvalidTimeout
+		}
+		c.commandTimeouts = timeouts
+		return nil
+	}
+}
+
 // WithSSL enables implicit SSL/TLS for the Client.
 //
 // This function configures the Client to use implicit SSL/TLS for secure communication.
@@ -390,8 +527,12 @@ func WithDebugLog() Option {
 
 // WithLogger defines a custom logger for the Client.
 //
-// This function sets a custom logger for the Client, which must satisfy the log.Logger interface. The custom
-// logger is used only when debug logging is enabled. By default, log.Stdlog is used if no custom logger is provided.
+// This function sets a custom logger for the Client, which must satisfy the log.Logger interface. The SMTP
+// communication is logged to the custom logger only when debug logging is enabled. By default, log.Stdlog is used
+// for the debug logging if no custom logger is provided. If debug logging is enabled, the Client additionally
+// logs its activity, like established connections and sent messages, to the custom logger. These log
+// messages carry log.Field values, like the host, the Message-ID and the duration, so that they can be routed
+// into a structured logging pipeline, i. e. via log.NewSlog.
 //
 // Parameters:
 //   - logger: A logger that satisfies the log.Logger interface.
@@ -668,6 +809,72 @@ func WithoutNoop() Option {
 	}
 }
 
+// WithoutPipelining disables the use of the PIPELINING extension.
+//
+// By default, the Client sends the MAIL FROM and all RCPT TO commands of a Msg in a single write if
+// the server advertises the PIPELINING extension, which saves a round trip per recipient. This option
+// sends each command separately and waits for its reply instead, which can be useful for servers that
+// advertise but mishandle PIPELINING.
+//
+// Returns:
+//   - An Option function that disables the use of the PIPELINING extension.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2920
+func WithoutPipelining() Option {
+	return func(c *Client) error {
+		c.noPipelining = true
+		return nil
+	}
+}
+
+// WithoutSASLInitialResponse disables sending the initial response with the AUTH command for the
+// given SMTPAuthType values.
+//
+// Some servers mishandle the initial response of the SASL-IR extension. With this option, the
+// Client issues the AUTH command without the initial response and sends it as answer to the empty
+// challenge of the server instead. If no SMTPAuthType is given, the initial response is disabled
+// for all authentication mechanisms.
+//
+// Parameters:
+//   - authTypes: The SMTPAuthType values for which the initial response is disabled.
+//
+// Returns:
+//   - An Option function that disables the initial response for the given authentication types.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc4954#section-4
+func WithoutSASLInitialResponse(authTypes ...SMTPAuthType) Option {
+	return func(c *Client) error {
+		c.noInitialResponse = make([]string, 0, len(authTypes))
+		for _, authType := range authTypes {
+			c.noInitialResponse = append(c.noInitialResponse, strings.TrimSuffix(string(authType), "-NOENC"))
+		}
+		return nil
+	}
+}
+
+// WithAuthQuirk enables the given AuthQuirk flags for the SMTP authentication of the Client.
+//
+// Some servers implement the authentication mechanisms in a nonstandard way, i. e. by sending
+// unexpected LOGIN prompts or by requiring upper-case usernames, which otherwise lead to hard
+// authentication failures. The quirks only apply to the built-in authentication mechanisms and
+// not to a custom smtp.Auth set with WithSMTPAuthCustom.
+//
+// Parameters:
+//   - quirks: The AuthQuirk flags to enable.
+//
+// Returns:
+//   - An Option function that enables the given AuthQuirk flags for the Client.
+func WithAuthQuirk(quirks ...AuthQuirk) Option {
+	return func(c *Client) error {
+		for _, quirk := range quirks {
+			c.authQuirks |= quirk
+		}
+		return nil
+	}
+}
+
 // WithDialContextFunc sets the provided DialContextFunc as the DialContext for connecting to the SMTP server.
 //
 // This function overrides the default DialContext function used by the Client when establishing a connection
@@ -688,6 +895,120 @@ func WithDialContextFunc(dialCtxFunc DialContextFunc) Option {
 	}
 }
 
+// WithPartialDelivery enables the delivery of a Msg to the accepted subset of its recipients.
+//
+// By default, the Client aborts the mail transaction, if the server rejects any of the recipients of
+// a Msg. With this option, the Msg is delivered to the accepted recipients, as long as at least one
+// recipient is accepted. The rejected recipients are still reported with a SendError of the reason
+// ErrSMTPRcptTo, which holds the response of the server for each of them in
+// SendError.RejectedRecipients, but the Msg is marked as delivered.
+//
+// Returns:
+//   - An Option function that enables the partial delivery for the Client.
+func WithPartialDelivery() Option {
+	return func(c *Client) error {
+		c.partialDelivery = true
+		return nil
+	}
+}
+
+// WithDebugHook sets a hook function that is called for each line of the SMTP protocol that is
+// sent to or received from the server.
+//
+// Unlike the debug logging, the hook receives the plain protocol lines together with their
+// direction, so that they can be passed to a structured logger or collected into a full SMTP
+// transcript. Authentication data is always redacted from the lines, independent of
+// WithLogAuthData. The message content sent after the DATA command and the initial greeting
+// of the server are not passed to the hook.
+//
+// Parameters:
+//   - hook: The smtp.DebugHook that is called with the direction and the line of each protocol
+//     line.
+//
+// Returns:
+//   - An Option function that sets the debug hook for the Client.
+func WithDebugHook(hook smtp.DebugHook) Option {
+	return func(c *Client) error {
+		if hook == nil {
+			return ErrDebugHookIsNil
+		}
+		c.debugHook = hook
+		return nil
+	}
+}
+
+// WithDefaultFrom sets a default "From" address for the Client.
+//
+// Each Msg that has no "From" address when it is sent by the Client gets the default address set,
+// so that services with a single sender identity do not have to set it on every Msg. A "From"
+// address that is set on the Msg takes precedence.
+//
+// Parameters:
+//   - addr: The default "From" address, i. e. "Toni Tester <toni.tester@example.com>".
+//
+// Returns:
+//   - An Option function that sets the default "From" address for the Client.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.6.2
+func WithDefaultFrom(addr string) Option {
+	return func(c *Client) error {
+		from, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("failed to parse default from address: %w", err)
+		}
+		c.defaultFrom = from.String()
+		return nil
+	}
+}
+
+// WithDefaultReplyTo sets a default "Reply-To" address for the Client.
+//
+// Each Msg that has no "Reply-To" address when it is sent by the Client gets the default address
+// set. A "Reply-To" address that is set on the Msg takes precedence.
+//
+// Parameters:
+//   - addr: The default "Reply-To" address, i. e. "Support <support@example.com>".
+//
+// Returns:
+//   - An Option function that sets the default "Reply-To" address for the Client.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.6.2
+func WithDefaultReplyTo(addr string) Option {
+	return func(c *Client) error {
+		replyTo, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("failed to parse default reply-to address: %w", err)
+		}
+		c.defaultReplyTo = replyTo.String()
+		return nil
+	}
+}
+
+// WithUnixSocket configures the Client to connect to an SMTP server listening on the Unix domain
+// socket at the given path, instead of connecting to host and port.
+//
+// This is useful for local mail transfer agents, like Postfix or OpenSMTPD, when TCP loopback
+// connections are not available. The hostname of the Client is still used for the STARTTLS server
+// name verification and the SMTP authentication, so in most local setups the TLSPolicy should be
+// set to NoTLS. If a port fallback is configured, it is not used for Unix domain sockets.
+//
+// Parameters:
+//   - path: The path of the Unix domain socket of the SMTP server.
+//
+// Returns:
+//   - An Option function that sets the Unix domain socket for the Client.
+func WithUnixSocket(path string) Option {
+	return func(c *Client) error {
+		if path == "" {
+			return ErrInvalidUnixSocket
+		}
+		c.unixSocket = path
+		return nil
+	}
+}
+
 // WithLogAuthData enables logging of authentication data.
 //
 // This function sets the logAuthData field of the Client to true, enabling the logging of authentication data.
@@ -717,11 +1038,16 @@ func (c *Client) TLSPolicy() string {
 // ServerAddr returns the server address that is currently set on the Client in the format "host:port".
 //
 // This method constructs and returns the server address using the host and port currently configured
-// for the Client.
+// for the Client. If the Client is configured to connect to a Unix domain socket, the path of the
+// socket is returned instead.
 //
 // Returns:
-//   - A string representing the server address in the format "host:port".
+//   - A string representing the server address in the format "host:port" or the path of the Unix
+//     domain socket.
 func (c *Client) ServerAddr() string {
+	if c.unixSocket != "" {
+		return c.unixSocket
+	}
 	return fmt.Sprintf("%s:%d", c.host, c.port)
 }
 
@@ -819,10 +1145,11 @@ func (c *Client) SetDebugLog(val bool) {
 }
 
 // SetLogger sets or overrides the custom logger currently used by the Client. The logger must
-// satisfy the log.Logger interface and is only utilized when debug logging is enabled on the
-// Client.
+// satisfy the log.Logger interface. Both the SMTP communication and the client activity, i. e. the
+// outcome of each delivered or failed Msg with its host, Message-ID and duration, are only logged
+// when debug logging is enabled on the Client.
 //
-// By default, log.Stdlog is used if no custom logger is provided.
+// By default, log.Stdlog is used for the debug logging if no custom logger is provided.
 //
 // Parameters:
 //   - logger: A logger that satisfies the log.Logger interface to be set for the Client.
@@ -899,6 +1226,50 @@ func (c *Client) SetSMTPAuthCustom(smtpAuth smtp.Auth) {
 	c.smtpAuthType = SMTPAuthCustom
 }
 
+// UpdateAuth replaces the SMTP authentication settings of the Client, i. e. after the credentials
+// have been rotated.
+//
+// Unlike SetSMTPAuth, SetUsername and SetPassword, UpdateAuth is safe to call while the Client is
+// in use by other goroutines. It waits for a message transaction that is currently in progress to
+// complete on the previous settings. The established connection stays authenticated with the
+// previous credentials; the new settings are applied on the next dial of the Client.
+//
+// Parameters:
+//   - authtype: The SMTPAuthType to be used for the Client.
+//   - username: The username to be used for SMTP authentication.
+//   - password: The password to be used for SMTP authentication.
+func (c *Client) UpdateAuth(authtype SMTPAuthType, username, password string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.smtpAuthType = authtype
+	c.smtpAuth = nil
+	c.user = username
+	c.pass = password
+}
+
+// UpdateTLSConfig replaces the tls.Config of the Client, i. e. after a client certificate has been
+// rotated.
+//
+// Unlike SetTLSConfig, UpdateTLSConfig is safe to call while the Client is in use by other
+// goroutines. It waits for a message transaction that is currently in progress to complete on the
+// previous settings. The established connection keeps its TLS state; the new tls.Config is applied
+// on the next dial of the Client.
+//
+// Parameters:
+//   - tlsconfig: A pointer to the tls.Config struct to be used for the Client. Must not be nil.
+//
+// Returns:
+//   - An error if the provided tls.Config is nil.
+func (c *Client) UpdateTLSConfig(tlsconfig *tls.Config) error {
+	if tlsconfig == nil {
+		return ErrInvalidTLSConfig
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.tlsconfig = tlsconfig
+	return nil
+}
+
 // SetLogAuthData sets or overrides the logging of SMTP authentication data for the Client.
 //
 // This function sets the logAuthData field of the Client to true, enabling the logging of authentication data.
@@ -931,28 +1302,47 @@ func (c *Client) DialWithContext(dialCtx context.Context) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	ctx, cancel := context.WithDeadline(dialCtx, time.Now().Add(c.connTimeout))
+	ctx, cancel := context.WithDeadline(dialCtx, time.Now().Add(c.phaseTimeout(c.commandTimeouts.Connect)))
 	defer cancel()
+	startTime := time.Now()
 
-	if c.dialContextFunc == nil {
+	if err := c.lookupDANERecords(ctx); err != nil {
+		return err
+	}
+	// The default dialer is built on each dial, so that a tls.Config replaced with UpdateTLSConfig
+	// is used for the next implicit TLS connection.
+	dialContextFunc := c.dialContextFunc
+	if dialContextFunc == nil {
 		netDialer := net.Dialer{}
-		c.dialContextFunc = netDialer.DialContext
+		dialContextFunc = netDialer.DialContext
 
 		if c.useSSL {
-			tlsDialer := tls.Dialer{NetDialer: &netDialer, Config: c.tlsconfig}
+			tlsDialer := tls.Dialer{NetDialer: &netDialer, Config: c.daneTLSConfig()}
 			c.isEncrypted = true
-			c.dialContextFunc = tlsDialer.DialContext
+			dialContextFunc = tlsDialer.DialContext
 		}
 	}
-	connection, err := c.dialContextFunc(ctx, "tcp", c.ServerAddr())
-	if err != nil && c.fallbackPort != 0 {
+	network := "tcp"
+	if c.unixSocket != "" {
+		network = "unix"
+	}
+	connection, err := dialContextFunc(ctx, network, c.ServerAddr())
+	c.observeDial(c.ServerAddr(), startTime, err)
+	if err != nil && c.fallbackPort != 0 && c.unixSocket == "" {
 		// TODO: should we somehow log or append the previous error?
-		connection, err = c.dialContextFunc(ctx, "tcp", c.serverFallbackAddr())
+		fallbackStart := time.Now()
+		connection, err = dialContextFunc(ctx, "tcp", c.serverFallbackAddr())
+		c.observeDial(c.serverFallbackAddr(), fallbackStart, err)
 	}
 	if err != nil {
 		return err
 	}
 
+	if c.commandTimeouts.Connect > 0 {
+		if err = connection.SetDeadline(time.Now().Add(c.commandTimeouts.Connect)); err != nil {
+			return ErrDeadlineExtendFailed
+		}
+	}
 	client, err := smtp.NewClient(connection, c.host)
 	if err != nil {
 		return err
@@ -971,6 +1361,15 @@ func (c *Client) DialWithContext(dialCtx context.Context) error {
 	if c.logAuthData {
 		c.smtpClient.SetLogAuthData()
 	}
+	if c.noInitialResponse != nil {
+		c.smtpClient.SetNoInitialResponse(c.noInitialResponse...)
+	}
+	if c.debugHook != nil {
+		c.smtpClient.SetDebugHook(c.debugHook)
+	}
+	if err = c.updatePhaseDeadline(c.commandTimeouts.Hello); err != nil {
+		return err
+	}
 	if err = c.smtpClient.Hello(c.helo); err != nil {
 		return err
 	}
@@ -978,10 +1377,29 @@ func (c *Client) DialWithContext(dialCtx context.Context) error {
 	if err = c.tls(); err != nil {
 		return err
 	}
+	c.recordHostProfile()
 
-	if err = c.auth(); err != nil {
+	if err = c.updatePhaseDeadline(c.commandTimeouts.Auth); err != nil {
 		return err
 	}
+	err = c.auth()
+	if c.metricsCollector != nil && (c.smtpAuth != nil || c.smtpAuthType != SMTPAuthNoAuth) {
+		c.metricsCollector.ObserveAuth(c.ServerAddr(), c.smtpAuthType, err)
+	}
+	if err != nil {
+		return err
+	}
+	if c.commandTimeouts != (CommandTimeouts{}) {
+		if err = c.smtpClient.UpdateDeadline(c.connTimeout); err != nil {
+			return ErrDeadlineExtendFailed
+		}
+	}
+	if c.logger != nil && c.useDebugLog {
+		c.logger.Debugf(log.Log{Direction: log.DirNone, Format: "connected to SMTP server", Fields: []log.Field{
+			{Key: log.FieldHost, Value: c.ServerAddr()},
+			{Key: log.FieldDuration, Value: time.Since(startTime)},
+		}})
+	}
 
 	return nil
 }
@@ -1065,7 +1483,7 @@ func (c *Client) DialAndSendWithContext(ctx context.Context, messages ...*Msg) e
 		_ = c.Close()
 	}()
 
-	if err := c.Send(messages...); err != nil {
+	if err := c.SendWithContext(ctx, messages...); err != nil {
 		return fmt.Errorf("send failed: %w", err)
 	}
 	if err := c.Close(); err != nil {
@@ -1074,6 +1492,92 @@ func (c *Client) DialAndSendWithContext(ctx context.Context, messages ...*Msg) e
 	return nil
 }
 
+// SendWithContext attempts to send one or more Msg using the Client connection to the SMTP server,
+// like Send, but aborts the delivery when the provided context.Context is cancelled or its deadline
+// expires.
+//
+// Cancellation takes effect immediately, even in the middle of a transfer: the pending I/O on the
+// connection is interrupted and the connection is closed, since the state of the SMTP session is
+// undefined afterwards. The affected Msg and all messages that have not been sent yet are associated
+// with a SendError that wraps the error of the context, so that errors.Is(err, context.Canceled) or
+// errors.Is(err, context.DeadlineExceeded) can be used to detect the cancellation.
+//
+// Parameters:
+//   - ctx: The context.Context to control the cancellation of the delivery.
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error that aggregates any SendErrors encountered during the sending process; otherwise, returns nil.
+func (c *Client) SendWithContext(ctx context.Context, messages ...*Msg) error {
+	if err := c.checkConn(); err != nil {
+		return &SendError{Reason: ErrConnCheck, errlist: []error{err}, isTemp: isTempError(err)}
+	}
+
+	// The watcher interrupts the connection if the context is done while the messages are being sent.
+	// The finished flag is set under the mutex once sending is complete, so that a context that is
+	// done afterward does not interrupt the connection of a successful delivery.
+	smtpClient := c.smtpClient
+	done := make(chan struct{})
+	interrupted := make(chan bool, 1)
+	var watchMutex sync.Mutex
+	finished := false
+	go func() {
+		select {
+		case <-done:
+			interrupted <- false
+			return
+		case <-ctx.Done():
+		}
+		watchMutex.Lock()
+		defer watchMutex.Unlock()
+		if finished {
+			interrupted <- false
+			return
+		}
+		_ = smtpClient.Interrupt()
+		interrupted <- true
+	}()
+
+	var sendErrs []*SendError
+	for _, message := range messages {
+		var err error
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = &SendError{affectedMsg: message, Reason: ErrContextDone, errlist: []error{ctxErr}}
+		} else {
+			err = c.sendSingleMsg(message)
+		}
+		if err == nil {
+			continue
+		}
+		message.sendError = err
+
+		var sendErr *SendError
+		if errors.As(err, &sendErr) {
+			sendErrs = append(sendErrs, sendErr)
+		}
+	}
+	watchMutex.Lock()
+	finished = true
+	watchMutex.Unlock()
+	close(done)
+
+	if <-interrupted {
+		_ = smtpClient.Close()
+		ctxErr := ctx.Err()
+		for _, sendErr := range sendErrs {
+			if sendErr.Reason != ErrContextDone {
+				sendErr.errlist = append(sendErr.errlist, ctxErr)
+			}
+		}
+	}
+
+	errs := make([]error, len(sendErrs))
+	for i := range sendErrs {
+		errs[i] = sendErrs[i]
+	}
+	return joinErrors(errs)
+}
+
 // auth attempts to authenticate the client using SMTP AUTH mechanisms. It checks the connection,
 // determines the supported authentication methods, and applies the appropriate authentication
 // type. An error is returned if authentication fails.
@@ -1100,47 +1604,56 @@ func (c *Client) auth() error {
 			return fmt.Errorf("server does not support SMTP AUTH")
 		}
 
+		username := c.user
+		if c.authQuirks.has(UpperCaseUsername) {
+			username = strings.ToUpper(username)
+		}
+		loginAuth := smtp.LoginAuth
+		if c.authQuirks.has(LoginEmptyPromptTolerant) {
+			loginAuth = smtp.LoginAuthTolerant
+		}
+
 		switch c.smtpAuthType {
 		case SMTPAuthPlain:
 			if !strings.Contains(smtpAuthType, string(SMTPAuthPlain)) {
 				return ErrPlainAuthNotSupported
 			}
-			c.smtpAuth = smtp.PlainAuth("", c.user, c.pass, c.host, false)
+			c.smtpAuth = smtp.PlainAuth("", username, c.pass, c.host, false)
 		case SMTPAuthPlainNoEnc:
 			if !strings.Contains(smtpAuthType, string(SMTPAuthPlain)) {
 				return ErrPlainAuthNotSupported
 			}
-			c.smtpAuth = smtp.PlainAuth("", c.user, c.pass, c.host, true)
+			c.smtpAuth = smtp.PlainAuth("", username, c.pass, c.host, true)
 		case SMTPAuthLogin:
 			if !strings.Contains(smtpAuthType, string(SMTPAuthLogin)) {
 				return ErrLoginAuthNotSupported
 			}
-			c.smtpAuth = smtp.LoginAuth(c.user, c.pass, c.host, false)
+			c.smtpAuth = loginAuth(username, c.pass, c.host, false)
 		case SMTPAuthLoginNoEnc:
 			if !strings.Contains(smtpAuthType, string(SMTPAuthLogin)) {
 				return ErrLoginAuthNotSupported
 			}
-			c.smtpAuth = smtp.LoginAuth(c.user, c.pass, c.host, true)
+			c.smtpAuth = loginAuth(username, c.pass, c.host, true)
 		case SMTPAuthCramMD5:
 			if !strings.Contains(smtpAuthType, string(SMTPAuthCramMD5)) {
 				return ErrCramMD5AuthNotSupported
 			}
-			c.smtpAuth = smtp.CRAMMD5Auth(c.user, c.pass)
+			c.smtpAuth = smtp.CRAMMD5Auth(username, c.pass)
 		case SMTPAuthXOAUTH2:
 			if !strings.Contains(smtpAuthType, string(SMTPAuthXOAUTH2)) {
 				return ErrXOauth2AuthNotSupported
 			}
-			c.smtpAuth = smtp.XOAuth2Auth(c.user, c.pass)
+			c.smtpAuth = smtp.XOAuth2Auth(username, c.pass)
 		case SMTPAuthSCRAMSHA1:
 			if !strings.Contains(smtpAuthType, string(SMTPAuthSCRAMSHA1)) {
 				return ErrSCRAMSHA1AuthNotSupported
 			}
-			c.smtpAuth = smtp.ScramSHA1Auth(c.user, c.pass)
+			c.smtpAuth = smtp.ScramSHA1Auth(username, c.pass)
 		case SMTPAuthSCRAMSHA256:
 			if !strings.Contains(smtpAuthType, string(SMTPAuthSCRAMSHA256)) {
 				return ErrSCRAMSHA256AuthNotSupported
 			}
-			c.smtpAuth = smtp.ScramSHA256Auth(c.user, c.pass)
+			c.smtpAuth = smtp.ScramSHA256Auth(username, c.pass)
 		case SMTPAuthSCRAMSHA1PLUS:
 			if !strings.Contains(smtpAuthType, string(SMTPAuthSCRAMSHA1PLUS)) {
 				return ErrSCRAMSHA1PLUSAuthNotSupported
@@ -1149,7 +1662,7 @@ func (c *Client) auth() error {
 			if err != nil {
 				return err
 			}
-			c.smtpAuth = smtp.ScramSHA1PlusAuth(c.user, c.pass, tlsConnState)
+			c.smtpAuth = smtp.ScramSHA1PlusAuth(username, c.pass, tlsConnState)
 		case SMTPAuthSCRAMSHA256PLUS:
 			if !strings.Contains(smtpAuthType, string(SMTPAuthSCRAMSHA256PLUS)) {
 				return ErrSCRAMSHA256PLUSAuthNotSupported
@@ -1158,7 +1671,7 @@ func (c *Client) auth() error {
 			if err != nil {
 				return err
 			}
-			c.smtpAuth = smtp.ScramSHA256PlusAuth(c.user, c.pass, tlsConnState)
+			c.smtpAuth = smtp.ScramSHA256PlusAuth(username, c.pass, tlsConnState)
 		default:
 			return fmt.Errorf("unsupported SMTP AUTH type %q", c.smtpAuthType)
 		}
@@ -1188,14 +1701,81 @@ func (c *Client) auth() error {
 // Returns:
 //   - An error if any part of the sending process fails; otherwise, returns nil.
 func (c *Client) sendSingleMsg(message *Msg) error {
+	return c.sendSingleMsgWithResult(message, &SendResult{})
+}
+
+// sendSingleMsgWithResult sends out a single message like sendSingleMsg and records the outcome of
+// the delivery in the provided SendResult.
+//
+// Parameters:
+//   - message: A pointer to the Msg object representing the email message to be sent.
+//   - result: A pointer to the SendResult to record the outcome of the delivery in.
+//
+// Returns:
+//   - An error if any part of the sending process fails; otherwise, returns nil.
+func (c *Client) sendSingleMsgWithResult(message *Msg, result *SendResult) (err error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	if message.encoding == NoEncoding {
+	startTime := time.Now()
+	defer func() {
+		result.Duration = time.Since(startTime)
+		var sendErr *SendError
+		if errors.As(err, &sendErr) && c.smtpClient != nil {
+			sendErr.banner = c.smtpClient.Banner()
+		}
+		c.logSendActivity(message, result, err)
+		if c.metricsCollector != nil {
+			c.metricsCollector.ObserveMessage(result.Server, result.BytesWritten, result.Duration, err)
+		}
+	}()
+	result.MessageID = message.GetMessageID()
+	result.Server = c.ServerAddr()
+
+	if err = c.applyDefaultAddresses(message); err != nil {
+		return &SendError{
+			Reason: ErrGetSender, errlist: []error{err}, isTemp: false,
+			affectedMsg: message,
+		}
+	}
+	if err = c.checkNoReplyGuard(message); err != nil {
+		return &SendError{
+			Reason: ErrPolicyViolation, errlist: []error{err}, isTemp: false,
+			affectedMsg: message,
+		}
+	}
+	outgoing := message
+	useBinaryMIME := false
+	if message.usesEncoding(EncodingBinary) {
+		useBinaryMIME = c.supportsBinaryMIME()
+		if !useBinaryMIME {
+			outgoing = message.reencoded(EncodingBinary, EncodingB64)
+		}
+	}
+	if !useBinaryMIME && outgoing.usesEncoding(NoEncoding) {
 		if ok, _ := c.smtpClient.Extension("8BITMIME"); !ok {
-			return &SendError{Reason: ErrNoUnencoded, isTemp: false, affectedMsg: message}
+			if c.eightBitFallback == "" {
+				return &SendError{Reason: ErrNoUnencoded, isTemp: false, affectedMsg: message}
+			}
+			outgoing = outgoing.reencoded(NoEncoding, c.eightBitFallback)
+		}
+	}
+	useSMTPUTF8 := false
+	if outgoing.hasUTF8Address() || outgoing.hasUTF8Header() {
+		hasSMTPUTF8, _ := c.smtpClient.Extension("SMTPUTF8")
+		switch {
+		case hasSMTPUTF8:
+			useSMTPUTF8 = true
+		case outgoing.hasUTF8Address():
+			return &SendError{
+				Reason: ErrNoSMTPUTF8, errlist: []error{ErrServerNoSMTPUTF8}, isTemp: false,
+				affectedMsg: message,
+			}
+		default:
+			outgoing = outgoing.utf8Downgraded()
 		}
 	}
+	c.smtpClient.SetSMTPUTF8(useSMTPUTF8)
 	from, err := message.GetSender(false)
 	if err != nil {
 		return &SendError{
@@ -1210,13 +1790,76 @@ func (c *Client) sendSingleMsg(message *Msg) error {
 			affectedMsg: message,
 		}
 	}
+	rcpts, result.SkippedRecipients = c.skipGraylisted(rcpts)
+	if len(rcpts) == 0 {
+		return &SendError{
+			Reason: ErrRcptGraylisted, errlist: []error{ErrRecipientsGraylisted}, isTemp: false,
+			rcpt: result.SkippedRecipients, affectedMsg: message,
+		}
+	}
+
+	if violations := checkPolicy(message, c.policyRules, c.isEncrypted); len(violations) > 0 {
+		return &SendError{
+			Reason: ErrPolicyViolation, errlist: violations, isTemp: false,
+			affectedMsg: message,
+		}
+	}
+
+	size := int64(-1)
+	if c.maxMessageSize > 0 || c.quotaManager != nil {
+		size = outgoing.EstimatedSize()
+	}
+	if err = c.checkMessageSize(size); err != nil {
+		return &SendError{
+			Reason: ErrMessageTooLarge, errlist: []error{err}, isTemp: false,
+			affectedMsg: message,
+		}
+	}
+	if c.quotaManager != nil {
+		usage := QuotaUsage{Recipients: len(rcpts), Sender: from, Size: size, Username: c.user}
+		if err = c.quotaManager.CheckQuota(usage); err != nil {
+			return &SendError{
+				Reason: ErrQuotaExceeded, errlist: []error{err}, isTemp: true,
+				affectedMsg: message,
+			}
+		}
+		defer func() {
+			c.quotaManager.RecordUsage(usage, err)
+		}()
+	}
 
 	if c.requestDSN {
 		if c.dsnReturnType != "" {
 			c.smtpClient.SetDSNMailReturnOption(string(c.dsnReturnType))
 		}
 	}
-	if err = c.smtpClient.Mail(from); err != nil {
+	if err = c.updatePhaseDeadline(c.commandTimeouts.Envelope); err != nil {
+		return &SendError{
+			Reason: ErrConnCheck, errlist: []error{err}, isTemp: isTempError(err),
+			affectedMsg: message,
+		}
+	}
+	mailFromParams := message.mailFromParams
+	if useBinaryMIME {
+		mailFromParams = append([]string{"BODY=BINARYMIME"}, mailFromParams...)
+	}
+	if message.requireTLS {
+		if err = c.checkRequireTLS(); err != nil {
+			return &SendError{
+				Reason: ErrRequireTLS, errlist: []error{err}, isTemp: false,
+				affectedMsg: message,
+			}
+		}
+		mailFromParams = append(append([]string(nil), mailFromParams...), "REQUIRETLS")
+	}
+	var rcptReplies []smtp.RcptReply
+	pipelining := c.supportsPipelining()
+	if pipelining {
+		rcptReplies, err = c.smtpClient.MailRcptPipelined(from, mailFromParams, rcpts, message.rcptParamsFor)
+	} else {
+		err = c.smtpClient.MailWithParams(from, mailFromParams...)
+	}
+	if err != nil {
 		retError := &SendError{
 			Reason: ErrSMTPMailFrom, errlist: []error{err}, isTemp: isTempError(err),
 			affectedMsg: message,
@@ -1232,29 +1875,52 @@ func (c *Client) sendSingleMsg(message *Msg) error {
 	rcptSendErr.rcpt = make([]string, 0)
 	rcptNotifyOpt := strings.Join(c.dsnRcptNotifyType, ",")
 	c.smtpClient.SetDSNRcptNotifyOption(rcptNotifyOpt)
-	for _, rcpt := range rcpts {
-		if err = c.smtpClient.Rcpt(rcpt); err != nil {
+	for i, rcpt := range rcpts {
+		var code int
+		var response string
+		var rcptErr error
+		if pipelining {
+			code, response, rcptErr = rcptReplies[i].Code, rcptReplies[i].Msg, rcptReplies[i].Err
+		} else {
+			code, response, rcptErr = c.smtpClient.RcptWithParams(rcpt, message.rcptParamsFor(rcpt)...)
+		}
+		recipient := newRecipientResult(rcpt, code, response, rcptErr)
+		result.Recipients = append(result.Recipients, recipient)
+		if rcptErr != nil {
+			c.graylistRecipient(rcpt, code)
+			rcptSendErr.rejected = append(rcptSendErr.rejected, recipient)
 			rcptSendErr.Reason = ErrSMTPRcptTo
-			rcptSendErr.errlist = append(rcptSendErr.errlist, err)
+			rcptSendErr.errlist = append(rcptSendErr.errlist, rcptErr)
 			rcptSendErr.rcpt = append(rcptSendErr.rcpt, rcpt)
-			rcptSendErr.isTemp = isTempError(err)
+			rcptSendErr.isTemp = isTempError(rcptErr)
 			hasError = true
 		}
 	}
-	if hasError {
+	if hasError && (!c.partialDelivery || len(rcptSendErr.rejected) == len(rcpts)) {
 		if resetSendErr := c.smtpClient.Reset(); resetSendErr != nil {
 			rcptSendErr.errlist = append(rcptSendErr.errlist, resetSendErr)
 		}
 		return rcptSendErr
 	}
-	writer, err := c.smtpClient.Data()
+	var writer io.WriteCloser
+	if useBinaryMIME {
+		writer, err = c.smtpClient.Bdat()
+	} else {
+		writer, err = c.smtpClient.Data()
+	}
 	if err != nil {
 		return &SendError{
 			Reason: ErrSMTPData, errlist: []error{err}, isTemp: isTempError(err),
 			affectedMsg: message,
 		}
 	}
-	_, err = message.WriteTo(writer)
+	if err = c.updatePhaseDeadline(c.commandTimeouts.Data); err != nil {
+		return &SendError{
+			Reason: ErrSMTPData, errlist: []error{err}, isTemp: isTempError(err),
+			affectedMsg: message,
+		}
+	}
+	result.BytesWritten, err = outgoing.WriteTo(writer)
 	if err != nil {
 		return &SendError{
 			Reason: ErrWriteContent, errlist: []error{err}, isTemp: isTempError(err),
@@ -1275,9 +1941,111 @@ func (c *Client) sendSingleMsg(message *Msg) error {
 			affectedMsg: message,
 		}
 	}
+	if hasError {
+		return rcptSendErr
+	}
+	return nil
+}
+
+// applyDefaultAddresses sets the default "From" and "Reply-To" addresses of the Client on the given
+// Msg, if the Msg has no such address set.
+//
+// Parameters:
+//   - message: The Msg to set the default addresses on.
+//
+// Returns:
+//   - An error if a default address is rejected by the Msg; otherwise, returns nil.
+func (c *Client) applyDefaultAddresses(message *Msg) error {
+	if c.defaultFrom != "" && len(message.GetFrom()) == 0 {
+		if err := message.From(c.defaultFrom); err != nil {
+			return err
+		}
+	}
+	if c.defaultReplyTo != "" && len(message.GetGenHeader(HeaderReplyTo)) == 0 {
+		if err := message.ReplyTo(c.defaultReplyTo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// supportsBinaryMIME returns true if the SMTP server advertises the BINARYMIME and the CHUNKING
+// extension, which are both required to transmit binary content with the BDAT command.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc3030#section-3
+func (c *Client) supportsBinaryMIME() bool {
+	hasBinaryMIME, _ := c.smtpClient.Extension("BINARYMIME")
+	hasChunking, _ := c.smtpClient.Extension("CHUNKING")
+	return hasBinaryMIME && hasChunking
+}
+
+// checkRequireTLS checks if the connection to the SMTP server is suitable for a message that
+// requires TLS as defined in RFC 8689. The connection must be encrypted, the certificate of the
+// server must be verified either against the root CAs or by an enforcing DANE verification, and
+// the server must advertise the REQUIRETLS extension.
+//
+// Returns:
+//   - An error if the connection cannot guarantee TLS for the message; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc8689#section-4.1
+func (c *Client) checkRequireTLS() error {
+	if !c.isEncrypted {
+		return ErrRequireTLSNotEncrypted
+	}
+	daneVerified := c.dane != nil && !c.dane.reportOnly && len(c.daneRecords) > 0
+	if c.tlsconfig != nil && c.tlsconfig.InsecureSkipVerify && !daneVerified {
+		return ErrRequireTLSNotVerified
+	}
+	if ok, _ := c.smtpClient.Extension("REQUIRETLS"); !ok {
+		return ErrServerNoRequireTLS
+	}
 	return nil
 }
 
+// supportsPipelining returns true if the SMTP server advertises the PIPELINING extension and its
+// use is not disabled with WithoutPipelining.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2920
+func (c *Client) supportsPipelining() bool {
+	if c.noPipelining {
+		return false
+	}
+	hasPipelining, _ := c.smtpClient.Extension("PIPELINING")
+	return hasPipelining
+}
+
+// logSendActivity logs the outcome of sending a single message to the logger of the Client.
+//
+// This method is a no-op unless debug logging is enabled via WithDebugLog or SetDebugLog and a
+// custom logger is set on the Client, so that the logger is not flooded with an entry per message
+// by default. A successfully sent message is logged on info level, a failed one on error level.
+// The log message carries the host, the Message-ID and the duration as log.Field values, as well
+// as the error if sending failed.
+//
+// Parameters:
+//   - message: The Msg that was sent.
+//   - result: The SendResult of the message.
+//   - err: The error that occurred while sending the message, or nil.
+func (c *Client) logSendActivity(message *Msg, result *SendResult, err error) {
+	if c.logger == nil || !c.useDebugLog {
+		return
+	}
+	fields := []log.Field{
+		{Key: log.FieldHost, Value: result.Server},
+		{Key: log.FieldMessageID, Value: message.GetMessageID()},
+		{Key: log.FieldDuration, Value: result.Duration},
+	}
+	if err != nil {
+		fields = append(fields, log.Field{Key: log.FieldError, Value: err.Error()})
+		c.logger.Errorf(log.Log{Direction: log.DirNone, Format: "failed to send message", Fields: fields})
+		return
+	}
+	c.logger.Infof(log.Log{Direction: log.DirNone, Format: "message sent", Fields: fields})
+}
+
 // checkConn ensures that a required server connection is available and extends the connection
 // deadline.
 //
@@ -1310,6 +2078,39 @@ func (c *Client) checkConn() error {
 	return nil
 }
 
+// phaseTimeout returns the given timeout of a phase of the SMTP session, or the connection timeout of
+// the Client if no timeout is set for the phase.
+//
+// Parameters:
+//   - timeout: The timeout of the phase from the CommandTimeouts of the Client.
+//
+// Returns:
+//   - The timeout to apply to the phase.
+func (c *Client) phaseTimeout(timeout time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	return c.connTimeout
+}
+
+// updatePhaseDeadline extends the deadline of the connection by the given timeout of a phase of the SMTP
+// session. If no timeout is set for the phase, the deadline of the connection remains unchanged.
+//
+// Parameters:
+//   - timeout: The timeout of the phase from the CommandTimeouts of the Client.
+//
+// Returns:
+//   - An error if extending the deadline fails; otherwise, returns nil.
+func (c *Client) updatePhaseDeadline(timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	if err := c.smtpClient.UpdateDeadline(timeout); err != nil {
+		return ErrDeadlineExtendFailed
+	}
+	return nil
+}
+
 // serverFallbackAddr returns the currently set combination of hostname and fallback port.
 //
 // This method constructs and returns the server address using the host and fallback port
@@ -1346,12 +2147,21 @@ func (c *Client) setDefaultHelo() error {
 // being used and the TLS policy is not set to NoTLS, it checks for STARTTLS support. Depending
 // on the TLS policy (mandatory or opportunistic), it may initiate a TLS connection using the
 // StartTLS method. The method also retrieves the TLS connection state to determine if the
-// connection is encrypted and returns any errors encountered during these processes.
+// connection is encrypted and returns any errors encountered during these processes. If the
+// server publishes TLSA records and DANE is enabled, the connection must be encrypted.
 //
 // Returns:
 //   - An error if there is no active connection, if STARTTLS is required but not supported,
 //     or if there are issues during the TLS handshake; otherwise, returns nil.
 func (c *Client) tls() error {
+	if len(c.daneRecords) > 0 && !c.useSSL {
+		if extension, _ := c.smtpClient.Extension("STARTTLS"); !extension || c.tlspolicy == NoTLS {
+			if err := c.daneFailure(fmt.Errorf("%w: server publishes TLSA records, but the connection is "+
+				"not encrypted", ErrDANEVerification)); err != nil {
+				return err
+			}
+		}
+	}
 	if !c.useSSL && c.tlspolicy != NoTLS {
 		hasStartTLS := false
 		extension, _ := c.smtpClient.Extension("STARTTLS")
@@ -1368,7 +2178,12 @@ func (c *Client) tls() error {
 			}
 		}
 		if hasStartTLS {
-			if err := c.smtpClient.StartTLS(c.tlsconfig); err != nil {
+			startTime := time.Now()
+			err := c.smtpClient.StartTLS(c.daneTLSConfig())
+			if c.metricsCollector != nil {
+				c.metricsCollector.ObserveTLSHandshake(c.ServerAddr(), time.Since(startTime), err)
+			}
+			if err != nil {
 				return err
 			}
 		}
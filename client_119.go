@@ -48,6 +48,8 @@ func (c *Client) Send(messages ...*Msg) error {
 			for i := range errs {
 				returnErr.errlist = append(returnErr.errlist, errs[i].errlist...)
 				returnErr.rcpt = append(returnErr.rcpt, errs[i].rcpt...)
+				returnErr.msgIDs = append(returnErr.msgIDs, errs[i].MessageIDs()...)
+				returnErr.banner = errs[i].banner
 			}
 
 			// We assume that the isTemp flag from the last error we received should be the
@@ -60,3 +62,35 @@ func (c *Client) Send(messages ...*Msg) error {
 	}
 	return nil
 }
+
+// joinErrors combines the provided errors into a single error. It returns nil if the provided
+// slice is empty.
+//
+// Since errors.Join is not available before Go 1.20, multiple SendErrors are merged into a
+// single SendError with the ErrAmbiguous reason, the same way Send handles multiple errors.
+//
+// Parameters:
+//   - errs: A slice of errors to be combined.
+//
+// Returns:
+//   - An error representing all of the given errors, or nil.
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	returnErr := &SendError{Reason: ErrAmbiguous}
+	for _, err := range errs {
+		var sendErr *SendError
+		if errors.As(err, &sendErr) {
+			returnErr.errlist = append(returnErr.errlist, sendErr.errlist...)
+			returnErr.rcpt = append(returnErr.rcpt, sendErr.rcpt...)
+			returnErr.isTemp = sendErr.isTemp
+			continue
+		}
+		returnErr.errlist = append(returnErr.errlist, err)
+	}
+	return returnErr
+}
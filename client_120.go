@@ -46,3 +46,15 @@ func (c *Client) Send(messages ...*Msg) (returnErr error) {
 
 	return
 }
+
+// joinErrors combines the provided errors into a single error. It returns nil if the provided
+// slice is empty.
+//
+// Parameters:
+//   - errs: A slice of errors to be combined.
+//
+// Returns:
+//   - An error wrapping all of the given errors, or nil.
+func joinErrors(errs []error) error {
+	return errors.Join(errs...)
+}
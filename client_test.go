@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,7 +17,9 @@ import (
 	"net"
 	"net/mail"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -192,6 +195,23 @@ func TestNewClient(t *testing.T) {
 				"WithTimeout but invalid timeout", WithTimeout(-10), nil, true,
 				&ErrInvalidTimeout,
 			},
+			{
+				"WithCommandTimeouts", WithCommandTimeouts(CommandTimeouts{Auth: time.Second * 30}),
+				func(c *Client) error {
+					if c.commandTimeouts.Auth != time.Second*30 || c.phaseTimeout(c.commandTimeouts.Auth) != time.Second*30 {
+						return fmt.Errorf("failed to set auth timeout, got: %s", c.commandTimeouts.Auth)
+					}
+					if c.phaseTimeout(c.commandTimeouts.Data) != c.connTimeout {
+						return fmt.Errorf("expected unset data timeout to fall back to the connection timeout")
+					}
+					return nil
+				},
+				false, nil,
+			},
+			{
+				"WithCommandTimeouts but negative timeout", WithCommandTimeouts(CommandTimeouts{Data: -1}), nil,
+				true, &ErrInvalidTimeout,
+			},
 			{
 				"WithSSL", WithSSL(),
 				func(c *Client) error {
@@ -636,6 +656,45 @@ func TestNewClient(t *testing.T) {
 				WithDSNRcptNotifyType(DSNRcptNotifyNever, DSNRcptNotifyDelay), nil,
 				true, &ErrInvalidDSNRcptNotifyCombination,
 			},
+			{
+				"WithDefaultFrom", WithDefaultFrom("Toni Tester <toni.tester@example.com>"),
+				func(c *Client) error {
+					if c.defaultFrom != `"Toni Tester" <toni.tester@example.com>` {
+						return fmt.Errorf("unexpected default from address: %s", c.defaultFrom)
+					}
+					return nil
+				},
+				false, nil,
+			},
+			{
+				"WithDefaultFrom with invalid address", WithDefaultFrom("invalid"), nil,
+				true, nil,
+			},
+			{
+				"WithDefaultReplyTo", WithDefaultReplyTo("support@example.com"),
+				func(c *Client) error {
+					if c.defaultReplyTo != "<support@example.com>" {
+						return fmt.Errorf("unexpected default reply-to address: %s", c.defaultReplyTo)
+					}
+					return nil
+				},
+				false, nil,
+			},
+			{
+				"WithDefaultReplyTo with invalid address", WithDefaultReplyTo("invalid"), nil,
+				true, nil,
+			},
+			{
+				"WithPartialDelivery", WithPartialDelivery(),
+				func(c *Client) error {
+					if !c.partialDelivery {
+						return fmt.Errorf("failed to enable partial delivery. Want: %t, got: %t", true,
+							c.partialDelivery)
+					}
+					return nil
+				},
+				false, nil,
+			},
 			{
 				"WithoutNoop", WithoutNoop(),
 				func(c *Client) error {
@@ -646,6 +705,50 @@ func TestNewClient(t *testing.T) {
 				},
 				false, nil,
 			},
+			{
+				"WithoutPipelining", WithoutPipelining(),
+				func(c *Client) error {
+					if !c.noPipelining {
+						return fmt.Errorf("failed to disable pipelining. Want noPipelining: %t, got: %t", true,
+							c.noPipelining)
+					}
+					return nil
+				},
+				false, nil,
+			},
+			{
+				"WithoutSASLInitialResponse for all mechanisms", WithoutSASLInitialResponse(),
+				func(c *Client) error {
+					if c.noInitialResponse == nil || len(c.noInitialResponse) != 0 {
+						return fmt.Errorf("expected initial response to be disabled for all mechanisms, got: %v",
+							c.noInitialResponse)
+					}
+					return nil
+				},
+				false, nil,
+			},
+			{
+				"WithoutSASLInitialResponse for mechanisms",
+				WithoutSASLInitialResponse(SMTPAuthPlainNoEnc, SMTPAuthXOAUTH2),
+				func(c *Client) error {
+					if len(c.noInitialResponse) != 2 || c.noInitialResponse[0] != "PLAIN" ||
+						c.noInitialResponse[1] != "XOAUTH2" {
+						return fmt.Errorf("unexpected mechanisms without initial response: %v", c.noInitialResponse)
+					}
+					return nil
+				},
+				false, nil,
+			},
+			{
+				"WithAuthQuirk", WithAuthQuirk(LoginEmptyPromptTolerant, UpperCaseUsername),
+				func(c *Client) error {
+					if !c.authQuirks.has(LoginEmptyPromptTolerant) || !c.authQuirks.has(UpperCaseUsername) {
+						return fmt.Errorf("expected auth quirks to be set, got: %d", c.authQuirks)
+					}
+					return nil
+				},
+				false, nil,
+			},
 			{
 				"WithDialContextFunc with net.Dailer", WithDialContextFunc(netDailer.DialContext),
 				func(c *Client) error {
@@ -700,6 +803,35 @@ func TestNewClient(t *testing.T) {
 				"WithDialContextFunc with nil", WithDialContextFunc(nil), nil,
 				true, &ErrDialContextFuncIsNil,
 			},
+			{
+				"WithDebugHook", WithDebugHook(func(log.Direction, string) {}),
+				func(c *Client) error {
+					if c.debugHook == nil {
+						return errors.New("failed to set debug hook, got: nil")
+					}
+					return nil
+				},
+				false, nil,
+			},
+			{
+				"WithDebugHook with nil", WithDebugHook(nil), nil,
+				true, &ErrDebugHookIsNil,
+			},
+			{
+				"WithUnixSocket", WithUnixSocket("/var/run/smtp.sock"),
+				func(c *Client) error {
+					if c.unixSocket != "/var/run/smtp.sock" {
+						return fmt.Errorf("failed to set unix socket. Want: %s, got: %s",
+							"/var/run/smtp.sock", c.unixSocket)
+					}
+					return nil
+				},
+				false, nil,
+			},
+			{
+				"WithUnixSocket with empty path", WithUnixSocket(""), nil,
+				true, &ErrInvalidUnixSocket,
+			},
 			{
 				"WithLogAuthData", WithLogAuthData(),
 				func(c *Client) error {
@@ -817,6 +949,17 @@ func TestClient_ServerAddr(t *testing.T) {
 			t.Errorf("failed to get expected server address. Want: %s, got: %s", expected, got)
 		}
 	})
+	t.Run("ServerAddr of with unix socket", func(t *testing.T) {
+		client, err := NewClient(DefaultHost, WithPort(587), WithUnixSocket("/var/run/smtp.sock"))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		got := client.ServerAddr()
+		expected := "/var/run/smtp.sock"
+		if !strings.EqualFold(expected, got) {
+			t.Errorf("failed to get expected server address. Want: %s, got: %s", expected, got)
+		}
+	})
 	t.Run("ServerAddr of with port policy TLSMandatory", func(t *testing.T) {
 		client, err := NewClient(DefaultHost, WithTLSPortPolicy(TLSMandatory))
 		if err != nil {
@@ -1495,6 +1638,175 @@ func TestClient_SetSMTPAuthCustom(t *testing.T) {
 	})
 }
 
+func TestClient_UpdateAuth(t *testing.T) {
+	t.Run("UpdateAuth replaces the credentials", func(t *testing.T) {
+		client, err := NewClient(DefaultHost, WithSMTPAuthCustom(smtp.PlainAuth("", "old", "old", DefaultHost, false)))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		client.UpdateAuth(SMTPAuthLogin, "new-user", "new-pass")
+		if client.smtpAuthType != SMTPAuthLogin || client.smtpAuth != nil {
+			t.Errorf("expected auth type %s without custom auth, got: %s, %v", SMTPAuthLogin,
+				client.smtpAuthType, client.smtpAuth)
+		}
+		if client.user != "new-user" || client.pass != "new-pass" {
+			t.Errorf("expected updated credentials, got: %s/%s", client.user, client.pass)
+		}
+	})
+	t.Run("UpdateAuth waits for transaction in progress", func(t *testing.T) {
+		client, err := NewClient(DefaultHost, WithUsername("old"))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		// Holding the mutex simulates a message transaction in progress
+		client.mutex.Lock()
+		updated := make(chan struct{})
+		go func() {
+			client.UpdateAuth(SMTPAuthPlain, "new", "new")
+			close(updated)
+		}()
+		select {
+		case <-updated:
+			t.Fatal("expected UpdateAuth to wait for the transaction in progress")
+		case <-time.After(time.Millisecond * 50):
+		}
+		if client.user != "old" {
+			t.Errorf("expected username to be unchanged during transaction, got: %s", client.user)
+		}
+		client.mutex.Unlock()
+		<-updated
+		if client.user != "new" {
+			t.Errorf("expected username to be updated after transaction, got: %s", client.user)
+		}
+	})
+}
+
+func TestClient_UpdateTLSConfig(t *testing.T) {
+	t.Run("UpdateTLSConfig replaces the config", func(t *testing.T) {
+		client, err := NewClient(DefaultHost)
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.UpdateTLSConfig(nil); !errors.Is(err, ErrInvalidTLSConfig) {
+			t.Errorf("expected error: %s, got: %s", ErrInvalidTLSConfig, err)
+		}
+		tlsConfig := &tls.Config{ServerName: "rotated.domain.tld", MinVersion: tls.VersionTLS12}
+		if err = client.UpdateTLSConfig(tlsConfig); err != nil {
+			t.Fatalf("failed to update TLS config: %s", err)
+		}
+		if client.tlsconfig != tlsConfig {
+			t.Error("expected TLS config to be updated")
+		}
+	})
+	t.Run("UpdateTLSConfig applies to the next SSL dial", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				SSLListener: true,
+				FeatureSet:  featureSet,
+				ListenPort:  serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(localhostCert) {
+			t.Fatal("failed to add test certificate to root CAs")
+		}
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithSSL(),
+			WithTLSConfig(&tls.Config{RootCAs: rootCAs, ServerName: DefaultHost}))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(context.Background()); err != nil {
+			t.Fatalf("failed to connect to the test server: %s", err)
+		}
+		if err = client.Close(); err != nil {
+			t.Fatalf("failed to close client: %s", err)
+		}
+
+		rotated := &tls.Config{RootCAs: rootCAs, ServerName: "rotated.domain.tld"}
+		if err = client.UpdateTLSConfig(rotated); err != nil {
+			t.Fatalf("failed to update TLS config: %s", err)
+		}
+		err = client.DialWithContext(context.Background())
+		var hostnameErr x509.HostnameError
+		if !errors.As(err, &hostnameErr) || hostnameErr.Host != "rotated.domain.tld" {
+			t.Errorf("expected the next dial to verify the server name of the rotated config, got: %v", err)
+		}
+	})
+}
+
+func TestClient_CommandTimeouts(t *testing.T) {
+	t.Run("send with command timeouts succeeds", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS),
+			WithSMTPAuth(SMTPAuthPlain), WithUsername("test"), WithPassword("password"),
+			WithCommandTimeouts(CommandTimeouts{
+				Connect: time.Second, Hello: time.Second, Auth: time.Second, Envelope: time.Second,
+				Data: time.Second,
+			}))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialAndSend(testMessage(t)); err != nil {
+			t.Errorf("failed to send message: %s", err)
+		}
+	})
+	t.Run("connect timeout applies to the server greeting", func(t *testing.T) {
+		listener, err := net.Listen(TestServerProto, TestServerAddr+":0")
+		if err != nil {
+			t.Fatalf("failed to create listener: %s", err)
+		}
+		t.Cleanup(func() { _ = listener.Close() })
+		go func() {
+			// Accept the connection, but never send a greeting
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+		}()
+
+		client, err := NewClient(DefaultHost, WithPort(listener.Addr().(*net.TCPAddr).Port),
+			WithTLSPolicy(NoTLS), WithCommandTimeouts(CommandTimeouts{Connect: time.Millisecond * 100}))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		start := time.Now()
+		err = client.DialWithContext(context.Background())
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			t.Errorf("expected timeout error, got: %s", err)
+		}
+		if time.Since(start) > DefaultTimeout/2 {
+			t.Errorf("expected connect timeout to be applied, took: %s", time.Since(start))
+		}
+	})
+}
+
 func TestClient_SetLogAuthData(t *testing.T) {
 	t.Run("SetLogAuthData true", func(t *testing.T) {
 		client, err := NewClient(DefaultHost)
@@ -1696,6 +2008,62 @@ func TestClient_DialWithContext(t *testing.T) {
 			t.Fatalf("client has no connection")
 		}
 	})
+	t.Run("connect with debug hook", func(t *testing.T) {
+		ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+		t.Cleanup(cancelDial)
+
+		var lines []string
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS),
+			WithDebugHook(func(direction log.Direction, line string) {
+				if direction == log.DirClientToServer {
+					lines = append(lines, line)
+				}
+			}))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(ctxDial); err != nil {
+			t.Fatalf("failed to connect to the test server: %s", err)
+		}
+		if err = client.Close(); err != nil {
+			t.Errorf("failed to close the client: %s", err)
+		}
+		if len(lines) == 0 || !strings.HasPrefix(lines[0], "EHLO ") {
+			t.Fatalf("expected debug hook to receive EHLO command, got: %q", lines)
+		}
+		if lines[len(lines)-1] != "QUIT" {
+			t.Errorf("expected debug hook to receive QUIT command, got: %q", lines)
+		}
+	})
+	t.Run("connect via unix socket", func(t *testing.T) {
+		ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+		t.Cleanup(cancelDial)
+
+		socket := filepath.Join(t.TempDir(), "smtp.sock")
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{FeatureSet: featureSet, UnixSocket: socket}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		client, err := NewClient(DefaultHost, WithUnixSocket(socket), WithTLSPolicy(NoTLS))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(ctxDial); err != nil {
+			t.Fatalf("failed to connect to the test server via unix socket: %s", err)
+		}
+		t.Cleanup(func() {
+			if err = client.Close(); err != nil {
+				t.Errorf("failed to close the client: %s", err)
+			}
+		})
+		if err = client.Send(testMessage(t)); err != nil {
+			t.Errorf("failed to send message via unix socket: %s", err)
+		}
+	})
 	t.Run("fail on base port use fallback", func(t *testing.T) {
 		ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
 		t.Cleanup(cancelDial)
@@ -2495,14 +2863,25 @@ func TestClient_auth(t *testing.T) {
 	})
 }
 
-func TestClient_Send(t *testing.T) {
-	message := testMessage(t)
-	t.Run("connect and send email", func(t *testing.T) {
+func TestClient_SendWithContext(t *testing.T) {
+	hasCtxErr := func(err error, ctxErr error) bool {
+		var sendErr *SendError
+		if !errors.As(err, &sendErr) {
+			return false
+		}
+		for _, e := range sendErr.errlist {
+			if errors.Is(e, ctxErr) {
+				return true
+			}
+		}
+		return false
+	}
+	t.Run("send with active context succeeds", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 		PortAdder.Add(1)
 		serverPort := int(TestServerPortBase + PortAdder.Load())
-		featureSet := "250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
 		go func() {
 			if err := simpleSMTPServer(ctx, t, &serverProps{
 				FeatureSet: featureSet,
@@ -2514,36 +2893,244 @@ func TestClient_Send(t *testing.T) {
 		}()
 		time.Sleep(time.Millisecond * 30)
 
-		ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
-		t.Cleanup(cancelDial)
-
-		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS))
+		client, err := NewClient(DefaultHost, WithTLSPolicy(NoTLS), WithPort(serverPort))
 		if err != nil {
 			t.Fatalf("failed to create new client: %s", err)
 		}
-		if err = client.DialWithContext(ctxDial); err != nil {
-			var netErr net.Error
-			if errors.As(err, &netErr) && netErr.Timeout() {
-				t.Skip("failed to connect to the test server due to timeout")
-			}
-			t.Fatalf("failed to connect to test server: %s", err)
+		if err = client.DialWithContext(ctx); err != nil {
+			t.Fatalf("failed to connect to the test server: %s", err)
 		}
 		t.Cleanup(func() {
 			if err := client.Close(); err != nil {
 				t.Errorf("failed to close client: %s", err)
 			}
 		})
-		if err = client.Send(message); err != nil {
-			t.Errorf("failed to send email: %s", err)
+		message := testMessage(t)
+		if err = client.SendWithContext(ctx, message); err != nil {
+			t.Fatalf("failed to send message: %s", err)
 		}
-	})
-	t.Run("send with no connection should fail", func(t *testing.T) {
-		client, err := NewClient(DefaultHost)
-		if err != nil {
-			t.Fatalf("failed to create new client: %s", err)
+		if !message.IsDelivered() {
+			t.Error("expected message to be delivered")
 		}
-		if err = client.Send(message); err == nil {
-			t.Errorf("client should have failed to send email with no connection")
+	})
+	t.Run("send with cancelled context fails", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		client, err := NewClient(DefaultHost, WithTLSPolicy(NoTLS), WithPort(serverPort))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(ctx); err != nil {
+			t.Fatalf("failed to connect to the test server: %s", err)
+		}
+		sendCtx, sendCancel := context.WithCancel(ctx)
+		sendCancel()
+		messages := []*Msg{testMessage(t), testMessage(t)}
+		if err = client.SendWithContext(sendCtx, messages...); err == nil {
+			t.Fatal("expected send with cancelled context to fail")
+		}
+		for i, message := range messages {
+			if message.IsDelivered() {
+				t.Errorf("expected message %d not to be delivered", i)
+			}
+			var sendErr *SendError
+			if !errors.As(message.SendError(), &sendErr) || sendErr.Reason != ErrContextDone {
+				t.Errorf("expected SendError with reason %s, got: %s", ErrContextDone, message.SendError())
+			}
+			if !hasCtxErr(message.SendError(), context.Canceled) {
+				t.Errorf("expected SendError to wrap context error, got: %s", message.SendError())
+			}
+		}
+	})
+	t.Run("send is aborted mid-transfer", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		client, err := NewClient(DefaultHost, WithTLSPolicy(NoTLS), WithPort(serverPort))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(ctx); err != nil {
+			t.Fatalf("failed to connect to the test server: %s", err)
+		}
+		sendCtx, sendCancel := context.WithCancel(ctx)
+		defer sendCancel()
+		message := testMessage(t)
+		message.SetBodyWriter(TypeTextPlain, func(w io.Writer) (int64, error) {
+			sendCancel()
+			time.Sleep(time.Millisecond * 50)
+			n, err := w.Write([]byte(strings.Repeat("Testmail\r\n", 1000)))
+			return int64(n), err
+		})
+		following := testMessage(t)
+		if err = client.SendWithContext(sendCtx, message, following); err == nil {
+			t.Fatal("expected send to be aborted")
+		}
+		if message.IsDelivered() {
+			t.Error("expected message not to be delivered")
+		}
+		if !hasCtxErr(message.SendError(), context.Canceled) {
+			t.Errorf("expected SendError to wrap context error, got: %s", message.SendError())
+		}
+		var sendErr *SendError
+		if !errors.As(following.SendError(), &sendErr) || sendErr.Reason != ErrContextDone {
+			t.Errorf("expected SendError with reason %s for following message, got: %s", ErrContextDone,
+				following.SendError())
+		}
+	})
+	t.Run("send with context fails without connection", func(t *testing.T) {
+		client, err := NewClient(DefaultHost)
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.SendWithContext(context.Background(), testMessage(t)); err == nil {
+			t.Error("expected send to fail without connection")
+		}
+	})
+}
+
+func TestClient_Send(t *testing.T) {
+	message := testMessage(t)
+	t.Run("connect and send email", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+		t.Cleanup(cancelDial)
+
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(ctxDial); err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				t.Skip("failed to connect to the test server due to timeout")
+			}
+			t.Fatalf("failed to connect to test server: %s", err)
+		}
+		t.Cleanup(func() {
+			if err := client.Close(); err != nil {
+				t.Errorf("failed to close client: %s", err)
+			}
+		})
+		if err = client.Send(message); err != nil {
+			t.Errorf("failed to send email: %s", err)
+		}
+	})
+	t.Run("connect and send email logs client activity", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+		t.Cleanup(cancelDial)
+
+		buffer := bytes.NewBuffer(nil)
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS),
+			WithLogger(log.New(buffer, log.LevelInfo)))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(ctxDial); err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				t.Skip("failed to connect to the test server due to timeout")
+			}
+			t.Fatalf("failed to connect to test server: %s", err)
+		}
+		t.Cleanup(func() {
+			if err := client.Close(); err != nil {
+				t.Errorf("failed to close client: %s", err)
+			}
+		})
+		logMessage := testMessage(t)
+		logMessage.SetMessageIDWithValue("logging@domain.tld")
+		if err = client.Send(logMessage); err != nil {
+			t.Errorf("failed to send email: %s", err)
+		}
+		if strings.Contains(buffer.String(), "message sent") {
+			t.Errorf("expected no activity to be logged without debug logging, got: %s", buffer.String())
+		}
+		client.SetDebugLog(true)
+		if err = client.Send(logMessage); err != nil {
+			t.Errorf("failed to send email: %s", err)
+		}
+		wantInfo := fmt.Sprintf("INFO: message sent host=%s message-id=<logging@domain.tld> duration=",
+			client.ServerAddr())
+		if !strings.Contains(buffer.String(), wantInfo) {
+			t.Errorf("expected log buffer to contain %q, got: %s", wantInfo, buffer.String())
+		}
+		if err = logMessage.To("invalid@domain.tld"); err != nil {
+			t.Fatalf("failed to set recipient: %s", err)
+		}
+		if err = client.Send(logMessage); err == nil {
+			t.Error("expected sending to invalid recipient to fail")
+		}
+		if !strings.Contains(buffer.String(), "ERROR: failed to send message host=") ||
+			!strings.Contains(buffer.String(), " error=") {
+			t.Errorf("expected log buffer to contain failed message, got: %s", buffer.String())
+		}
+	})
+	t.Run("send with no connection should fail", func(t *testing.T) {
+		client, err := NewClient(DefaultHost)
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.Send(message); err == nil {
+			t.Errorf("client should have failed to send email with no connection")
 		}
 		var sendErr *SendError
 		if !errors.As(err, &sendErr) {
@@ -2701,6 +3288,75 @@ func TestClient_sendSingleMsg(t *testing.T) {
 			t.Errorf("client should have failed to send message")
 		}
 	})
+	t.Run("binary message is sent via BDAT if the server supports BINARYMIME", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			featureSet string
+			wantBDAT   bool
+		}{
+			{"server with BINARYMIME", "250-8BITMIME\r\n250-BINARYMIME\r\n250 CHUNKING", true},
+			{"server without CHUNKING", "250-8BITMIME\r\n250 BINARYMIME", false},
+			{"server without BINARYMIME", "250 8BITMIME", false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				PortAdder.Add(1)
+				serverPort := int(TestServerPortBase + PortAdder.Load())
+				go func() {
+					if err := simpleSMTPServer(ctx, t, &serverProps{
+						FeatureSet: tt.featureSet,
+						ListenPort: serverPort,
+					}); err != nil {
+						t.Errorf("failed to start test server: %s", err)
+						return
+					}
+				}()
+				time.Sleep(time.Millisecond * 30)
+
+				message := testMessage(t)
+				if err := message.AttachReader("data.bin", bytes.NewReader([]byte{0x00, 0xff, '\r', '.', '\n'}),
+					WithFileEncoding(EncodingBinary)); err != nil {
+					t.Fatalf("failed to attach file: %s", err)
+				}
+				var commands []string
+				hook := func(direction log.Direction, line string) {
+					if direction == log.DirClientToServer {
+						commands = append(commands, line)
+					}
+				}
+
+				ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+				t.Cleanup(cancelDial)
+				client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS),
+					WithDebugHook(hook))
+				if err != nil {
+					t.Fatalf("failed to create new client: %s", err)
+				}
+				if err = client.DialWithContext(ctxDial); err != nil {
+					var netErr net.Error
+					if errors.As(err, &netErr) && netErr.Timeout() {
+						t.Skip("failed to connect to the test server due to timeout")
+					}
+					t.Fatalf("failed to connect to test server: %s", err)
+				}
+				t.Cleanup(func() {
+					if err := client.Close(); err != nil {
+						t.Errorf("failed to close client: %s", err)
+					}
+				})
+				if err = client.sendSingleMsg(message); err != nil {
+					t.Fatalf("failed to send message: %s", err)
+				}
+				transcript := strings.Join(commands, "\n")
+				usedBDAT := strings.Contains(transcript, "BODY=BINARYMIME") && strings.Contains(transcript, "LAST")
+				if usedBDAT != tt.wantBDAT || usedBDAT == strings.Contains(transcript, "\nDATA") {
+					t.Errorf("expected BDAT to be used: %t, got transcript: %s", tt.wantBDAT, transcript)
+				}
+			})
+		}
+	})
 	t.Run("fail on invalid sender address", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
@@ -3148,6 +3804,347 @@ func TestClient_sendSingleMsg(t *testing.T) {
 			t.Errorf("expected ErrSMTPDataClose, got %s", sendErr.Reason)
 		}
 	})
+	t.Run("default from and reply-to addresses", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		message := NewMsg()
+		if err := message.To("valid-to@domain.tld"); err != nil {
+			t.Fatalf("failed to set recipient: %s", err)
+		}
+		message.Subject("Testmail")
+		message.SetBodyString(TypeTextPlain, "Testmail")
+		withReplyTo := testMessage(t)
+		if err := withReplyTo.ReplyTo("reply@domain.tld"); err != nil {
+			t.Fatalf("failed to set reply-to address: %s", err)
+		}
+
+		ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+		t.Cleanup(cancelDial)
+
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS),
+			WithDefaultFrom("valid-from@domain.tld"), WithDefaultReplyTo("support@domain.tld"))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(ctxDial); err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				t.Skip("failed to connect to the test server due to timeout")
+			}
+			t.Fatalf("failed to connect to test server: %s", err)
+		}
+		t.Cleanup(func() {
+			if err := client.Close(); err != nil {
+				t.Errorf("failed to close client: %s", err)
+			}
+		})
+		if err = client.sendSingleMsg(message); err != nil {
+			t.Errorf("failed to send message: %s", err)
+		}
+		if from := message.GetFromString(); len(from) != 1 || from[0] != "<valid-from@domain.tld>" {
+			t.Errorf("expected default from address to be set, got: %v", from)
+		}
+		if replyTo := message.GetGenHeader(HeaderReplyTo); len(replyTo) != 1 || replyTo[0] != "<support@domain.tld>" {
+			t.Errorf("expected default reply-to address to be set, got: %v", replyTo)
+		}
+		if err = client.sendSingleMsg(withReplyTo); err != nil {
+			t.Errorf("failed to send message: %s", err)
+		}
+		if replyTo := withReplyTo.GetGenHeader(HeaderReplyTo); len(replyTo) != 1 || replyTo[0] != "<reply@domain.tld>" {
+			t.Errorf("expected reply-to address of the message to be kept, got: %v", replyTo)
+		}
+	})
+	t.Run("partial delivery to accepted recipients", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			opts          []Option
+			to            string
+			cc            string
+			wantDelivered bool
+			wantRejected  string
+		}{
+			{"partial delivery disabled", nil, TestRcptValid, "invalid-cc@domain.tld", false, "invalid-cc@domain.tld"},
+			{
+				"partial delivery enabled", []Option{WithPartialDelivery()}, TestRcptValid, "invalid-cc@domain.tld",
+				true, "invalid-cc@domain.tld",
+			},
+			{
+				"all recipients rejected", []Option{WithPartialDelivery()}, "invalid-to@domain.tld", "",
+				false, "invalid-to@domain.tld",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				PortAdder.Add(1)
+				serverPort := int(TestServerPortBase + PortAdder.Load())
+				featureSet := "250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+				go func() {
+					if err := simpleSMTPServer(ctx, t, &serverProps{
+						FeatureSet: featureSet,
+						ListenPort: serverPort,
+					}); err != nil {
+						t.Errorf("failed to start test server: %s", err)
+						return
+					}
+				}()
+				time.Sleep(time.Millisecond * 30)
+
+				message := testMessage(t)
+				if err := message.To(tt.to); err != nil {
+					t.Fatalf("failed to set recipient: %s", err)
+				}
+				if tt.cc != "" {
+					if err := message.Cc(tt.cc); err != nil {
+						t.Fatalf("failed to set cc address: %s", err)
+					}
+				}
+
+				ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+				t.Cleanup(cancelDial)
+
+				opts := append([]Option{WithPort(serverPort), WithTLSPolicy(NoTLS)}, tt.opts...)
+				client, err := NewClient(DefaultHost, opts...)
+				if err != nil {
+					t.Fatalf("failed to create new client: %s", err)
+				}
+				if err = client.DialWithContext(ctxDial); err != nil {
+					var netErr net.Error
+					if errors.As(err, &netErr) && netErr.Timeout() {
+						t.Skip("failed to connect to the test server due to timeout")
+					}
+					t.Fatalf("failed to connect to test server: %s", err)
+				}
+				t.Cleanup(func() {
+					if err := client.Close(); err != nil {
+						t.Errorf("failed to close client: %s", err)
+					}
+				})
+				err = client.sendSingleMsg(message)
+				var sendErr *SendError
+				if !errors.As(err, &sendErr) {
+					t.Fatalf("expected SendError, got: %s", err)
+				}
+				if sendErr.Reason != ErrSMTPRcptTo {
+					t.Errorf("expected error reason %s, got: %s", ErrSMTPRcptTo, sendErr.Reason)
+				}
+				if message.IsDelivered() != tt.wantDelivered {
+					t.Errorf("expected message delivered to be %t", tt.wantDelivered)
+				}
+				if sendErr.ServerBanner() != "go-mail test server ready ESMTP" {
+					t.Errorf("unexpected server banner: %q", sendErr.ServerBanner())
+				}
+				rejected := sendErr.RejectedRecipients()
+				if len(rejected) != 1 {
+					t.Fatalf("expected 1 rejected recipient, got: %d", len(rejected))
+				}
+				if rejected[0].Accepted || rejected[0].Address != tt.wantRejected || rejected[0].Code != 500 {
+					t.Errorf("unexpected rejected recipient: %+v", rejected[0])
+				}
+			})
+		}
+	})
+	t.Run("pipelined envelope", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			opts      []Option
+			cc        string
+			wantBatch int
+			wantErr   bool
+		}{
+			{"all recipients accepted", nil, "", 3, false},
+			{"recipient rejected", nil, "invalid-cc@domain.tld", 4, true},
+			{"pipelining disabled", []Option{WithoutPipelining()}, "", 1, false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				PortAdder.Add(1)
+				serverPort := int(TestServerPortBase + PortAdder.Load())
+				featureSet := "250-8BITMIME\r\n250-PIPELINING\r\n250 SMTPUTF8"
+				go func() {
+					if err := simpleSMTPServer(ctx, t, &serverProps{
+						FeatureSet: featureSet,
+						ListenPort: serverPort,
+					}); err != nil {
+						t.Errorf("failed to start test server: %s", err)
+						return
+					}
+				}()
+				time.Sleep(time.Millisecond * 30)
+
+				message := testMessage(t)
+				if err := message.Bcc("valid-to@domain.tld"); err != nil {
+					t.Fatalf("failed to set bcc recipient: %s", err)
+				}
+				if tt.cc != "" {
+					if err := message.Cc(tt.cc); err != nil {
+						t.Fatalf("failed to set cc recipient: %s", err)
+					}
+				}
+
+				ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+				t.Cleanup(cancelDial)
+
+				var protocol []string
+				opts := append([]Option{
+					WithPort(serverPort), WithTLSPolicy(NoTLS),
+					WithDebugHook(func(direction log.Direction, line string) {
+						protocol = append(protocol, fmt.Sprintf("%d:%s", direction, line))
+					}),
+				}, tt.opts...)
+				client, err := NewClient(DefaultHost, opts...)
+				if err != nil {
+					t.Fatalf("failed to create new client: %s", err)
+				}
+				if err = client.DialWithContext(ctxDial); err != nil {
+					var netErr net.Error
+					if errors.As(err, &netErr) && netErr.Timeout() {
+						t.Skip("failed to connect to the test server due to timeout")
+					}
+					t.Fatalf("failed to connect to test server: %s", err)
+				}
+				t.Cleanup(func() {
+					if err := client.Close(); err != nil {
+						t.Errorf("failed to close client: %s", err)
+					}
+				})
+				err = client.sendSingleMsg(message)
+				batch := 0
+				clientPrefix := fmt.Sprintf("%d:", log.DirClientToServer)
+				for i, line := range protocol {
+					if !strings.HasPrefix(line, clientPrefix+"MAIL FROM:") {
+						continue
+					}
+					for batch = 0; i+batch < len(protocol) && strings.HasPrefix(protocol[i+batch], clientPrefix); {
+						batch++
+					}
+					break
+				}
+				if batch != tt.wantBatch {
+					t.Errorf("expected %d commands sent before the first reply, got: %d", tt.wantBatch, batch)
+				}
+				if !tt.wantErr {
+					if err != nil {
+						t.Errorf("failed to send message: %s", err)
+					}
+					return
+				}
+				var sendErr *SendError
+				if !errors.As(err, &sendErr) || sendErr.Reason != ErrSMTPRcptTo {
+					t.Fatalf("expected SendError with reason ErrSMTPRcptTo, got: %s", err)
+				}
+				if rcpts := sendErr.rcpt; len(rcpts) != 1 || rcpts[0] != tt.cc {
+					t.Errorf("expected rejected recipient %s, got: %v", tt.cc, rcpts)
+				}
+			})
+		}
+	})
+	t.Run("message requires TLS", func(t *testing.T) {
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(localhostCert) {
+			t.Fatal("failed to add test certificate to root CAs")
+		}
+		tests := []struct {
+			name       string
+			featureSet string
+			policy     TLSPolicy
+			tlsConfig  *tls.Config
+			wantErr    error
+		}{
+			{
+				"unencrypted connection", "250-REQUIRETLS\r\n250 STARTTLS", NoTLS, nil,
+				ErrRequireTLSNotEncrypted,
+			},
+			{
+				"unverified certificate", "250-REQUIRETLS\r\n250 STARTTLS", TLSMandatory,
+				&tls.Config{InsecureSkipVerify: true}, ErrRequireTLSNotVerified,
+			},
+			{
+				"server without REQUIRETLS", "250 STARTTLS", TLSMandatory,
+				&tls.Config{RootCAs: rootCAs, ServerName: DefaultHost}, ErrServerNoRequireTLS,
+			},
+			{
+				"verified connection", "250-REQUIRETLS\r\n250 STARTTLS", TLSMandatory,
+				&tls.Config{RootCAs: rootCAs, ServerName: DefaultHost}, nil,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				PortAdder.Add(1)
+				serverPort := int(TestServerPortBase + PortAdder.Load())
+				go func() {
+					if err := simpleSMTPServer(ctx, t, &serverProps{
+						FeatureSet: tt.featureSet,
+						ListenPort: serverPort,
+					}); err != nil {
+						t.Errorf("failed to start test server: %s", err)
+						return
+					}
+				}()
+				time.Sleep(time.Millisecond * 30)
+
+				message := testMessage(t)
+				message.RequireTLS(true)
+
+				ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+				t.Cleanup(cancelDial)
+
+				opts := []Option{WithPort(serverPort), WithTLSPolicy(tt.policy)}
+				if tt.tlsConfig != nil {
+					opts = append(opts, WithTLSConfig(tt.tlsConfig))
+				}
+				client, err := NewClient(DefaultHost, opts...)
+				if err != nil {
+					t.Fatalf("failed to create new client: %s", err)
+				}
+				if err = client.DialWithContext(ctxDial); err != nil {
+					var netErr net.Error
+					if errors.As(err, &netErr) && netErr.Timeout() {
+						t.Skip("failed to connect to the test server due to timeout")
+					}
+					t.Fatalf("failed to connect to test server: %s", err)
+				}
+				t.Cleanup(func() {
+					if err := client.Close(); err != nil {
+						t.Errorf("failed to close client: %s", err)
+					}
+				})
+				err = client.sendSingleMsg(message)
+				if tt.wantErr == nil {
+					if err != nil {
+						t.Errorf("failed to send message: %s", err)
+					}
+					return
+				}
+				var sendErr *SendError
+				if !errors.As(err, &sendErr) || sendErr.Reason != ErrRequireTLS {
+					t.Fatalf("expected SendError with reason ErrRequireTLS, got: %s", err)
+				}
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %s, got: %s", tt.wantErr, err)
+				}
+			})
+		}
+	})
 }
 
 func TestClient_checkConn(t *testing.T) {
@@ -3568,10 +4565,12 @@ type serverProps struct {
 	FailOnSTARTTLS  bool
 	FailTemp        bool
 	FeatureSet      string
+	HandleParallel  bool
 	ListenPort      int
 	SSLListener     bool
 	IsTLS           bool
 	SupportDSN      bool
+	UnixSocket      string
 }
 
 // simpleSMTPServer starts a simple TCP server that resonds to SMTP commands.
@@ -3596,6 +4595,8 @@ func simpleSMTPServer(ctx context.Context, t *testing.T, props *serverProps) err
 		if err != nil {
 			t.Fatalf("failed to create TLS listener: %s", err)
 		}
+	} else if props.UnixSocket != "" {
+		listener, err = net.Listen("unix", props.UnixSocket)
 	} else {
 		listener, err = net.Listen(TestServerProto, fmt.Sprintf("%s:%d", TestServerAddr, props.ListenPort))
 	}
@@ -3623,6 +4624,10 @@ func simpleSMTPServer(ctx context.Context, t *testing.T, props *serverProps) err
 				}
 				return fmt.Errorf("unable to accept connection: %w", err)
 			}
+			if props.HandleParallel {
+				go handleTestServerConnection(connection, t, props)
+				continue
+			}
 			handleTestServerConnection(connection, t, props)
 		}
 	}
@@ -3683,7 +4688,9 @@ func handleTestServerConnection(connection net.Conn, t *testing.T, props *server
 			}
 			from := strings.TrimPrefix(data, "MAIL FROM:")
 			from = strings.ReplaceAll(from, "BODY=8BITMIME", "")
+			from = strings.ReplaceAll(from, "BODY=BINARYMIME", "")
 			from = strings.ReplaceAll(from, "SMTPUTF8", "")
+			from = strings.ReplaceAll(from, "REQUIRETLS", "")
 			if props.SupportDSN {
 				from = strings.ReplaceAll(from, "RET=FULL", "")
 			}
@@ -3737,6 +4744,26 @@ func handleTestServerConnection(connection net.Conn, t *testing.T, props *server
 				}
 				datastring += ddata + "\n"
 			}
+		case strings.HasPrefix(data, "BDAT "):
+			fields := strings.Fields(data)
+			size, serr := strconv.Atoi(fields[1])
+			if serr != nil {
+				writeLine("501 5.5.4 Error: invalid chunk size")
+				break
+			}
+			if _, derr := io.ReadFull(reader, make([]byte, size)); derr != nil {
+				t.Logf("failed to read chunk from connection: %s", derr)
+				break
+			}
+			if len(fields) < 3 || !strings.EqualFold(fields[2], "LAST") {
+				writeLine("250 2.0.0 Ok: chunk received")
+				break
+			}
+			if props.FailOnDataClose {
+				writeLine("500 5.0.0 Error during BDAT transmission")
+				break
+			}
+			writeLine("250 2.0.0 Ok: queued as 1234567890")
 		case strings.EqualFold(data, "noop"):
 			if props.FailOnNoop {
 				writeLine("500 5.0.0 Error: fail on NOOP")
@@ -0,0 +1,329 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultClientManagerIdleTimeout is the default duration after which the ClientPool of a tenant that
+// has not been used is closed and removed from the ClientManager.
+const DefaultClientManagerIdleTimeout = time.Minute * 10
+
+var (
+	// ErrClientManagerClosed is returned when a ClientManager is used after it has been closed.
+	ErrClientManagerClosed = errors.New("client manager is closed")
+
+	// ErrTenantConfigProviderIsNil is returned when no TenantConfigProvider is provided to a
+	// ClientManager.
+	ErrTenantConfigProviderIsNil = errors.New("tenant config provider is nil")
+)
+
+type (
+	// TenantConfig holds the configuration of the Client connections of a single tenant.
+	TenantConfig struct {
+		// ClientOptions holds the Option functions that are applied to each Client of the tenant, i. e.
+		// the tenant's credentials set with WithUsername and WithPassword.
+		ClientOptions []Option
+
+		// Host is the hostname of the SMTP server of the tenant.
+		Host string
+
+		// PoolOptions holds the PoolOption functions that are applied to the ClientPool of the tenant.
+		PoolOptions []PoolOption
+	}
+
+	// TenantConfigProvider is an interface for looking up the TenantConfig of a tenant, i. e. from a
+	// database or a secrets store.
+	TenantConfigProvider interface {
+		// TenantConfig returns the TenantConfig of the tenant with the given ID, or an error if the
+		// tenant is unknown or its configuration cannot be retrieved.
+		TenantConfig(ctx context.Context, tenantID string) (TenantConfig, error)
+	}
+
+	// TenantConfigProviderFunc is an adapter to allow the use of ordinary functions as
+	// TenantConfigProvider.
+	TenantConfigProviderFunc func(ctx context.Context, tenantID string) (TenantConfig, error)
+
+	// ClientManagerOption is a function type that modifies the configuration of a ClientManager.
+	ClientManagerOption func(*ClientManager) error
+
+	// ClientManager maintains a ClientPool for each tenant of a multi-tenant application.
+	//
+	// The ClientPool of a tenant is constructed lazily from the TenantConfig returned by the
+	// TenantConfigProvider when a Msg is first sent on behalf of the tenant, and cached for subsequent
+	// sends. ClientPools that have not been used for the idle timeout are closed and removed, so that
+	// their configuration is looked up again on next use.
+	//
+	// A ClientManager is safe for concurrent use by multiple goroutines.
+	ClientManager struct {
+		// idleTimeout specifies the duration after which an unused ClientPool is closed and removed.
+		idleTimeout time.Duration
+
+		// isClosed indicates whether the ClientManager has been closed.
+		isClosed bool
+
+		// mutex is used to synchronize access to the state of the ClientManager.
+		mutex sync.Mutex
+
+		// pools maps the tenant IDs to their managed ClientPool.
+		pools map[string]*managedPool
+
+		// provider is the TenantConfigProvider that provides the configuration of the tenants.
+		provider TenantConfigProvider
+
+		// stop is closed when the ClientManager is closed to stop the eviction of idle pools.
+		stop chan struct{}
+	}
+
+	// managedPool represents the ClientPool of a single tenant of a ClientManager.
+	managedPool struct {
+		// inUse is the number of sends that currently use the ClientPool.
+		inUse int
+
+		// lastUsed is the point in time the ClientPool was last used.
+		lastUsed time.Time
+
+		// pool is the ClientPool of the tenant.
+		pool *ClientPool
+	}
+)
+
+// TenantConfig satisfies the TenantConfigProvider interface for the TenantConfigProviderFunc type by
+// calling the function.
+func (f TenantConfigProviderFunc) TenantConfig(ctx context.Context, tenantID string) (TenantConfig, error) {
+	return f(ctx, tenantID)
+}
+
+// NewClientManager creates a new ClientManager that looks up the configuration of the tenants with the
+// given TenantConfigProvider.
+//
+// The ClientManager uses DefaultClientManagerIdleTimeout, unless overridden by the provided
+// ClientManagerOption functions.
+//
+// Parameters:
+//   - provider: The TenantConfigProvider that provides the configuration of the tenants.
+//   - opts: Optional configuration functions to override the default settings.
+//
+// Returns:
+//   - A pointer to the initialized ClientManager.
+//   - An error if the provider is nil or any of the options fails.
+func NewClientManager(provider TenantConfigProvider, opts ...ClientManagerOption) (*ClientManager, error) {
+	if provider == nil {
+		return nil, ErrTenantConfigProviderIsNil
+	}
+	manager := &ClientManager{
+		idleTimeout: DefaultClientManagerIdleTimeout,
+		pools:       make(map[string]*managedPool),
+		provider:    provider,
+		stop:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(manager); err != nil {
+			return nil, err
+		}
+	}
+	if manager.idleTimeout > 0 {
+		go manager.evictIdleLoop()
+	}
+	return manager, nil
+}
+
+// WithClientManagerIdleTimeout sets the duration after which the ClientPool of a tenant that has not
+// been used is closed and removed from the ClientManager. A timeout of zero disables the eviction.
+//
+// Parameters:
+//   - timeout: The duration after which an unused ClientPool is evicted.
+//
+// Returns:
+//   - A ClientManagerOption function that sets the idle timeout, or an error if the timeout is negative.
+func WithClientManagerIdleTimeout(timeout time.Duration) ClientManagerOption {
+	return func(m *ClientManager) error {
+		if timeout < 0 {
+			return ErrInvalidPoolDuration
+		}
+		m.idleTimeout = timeout
+		return nil
+	}
+}
+
+// SendAs sends one or more Msg on behalf of the tenant with the given ID.
+//
+// Parameters:
+//   - tenantID: The ID of the tenant to send the messages for.
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error if the configuration of the tenant cannot be retrieved or any of the messages could
+//     not be sent; otherwise, returns nil.
+func (m *ClientManager) SendAs(tenantID string, messages ...*Msg) error {
+	return m.SendAsWithContext(context.Background(), tenantID, messages...)
+}
+
+// SendAsWithContext sends one or more Msg on behalf of the tenant with the given ID, like SendAs. The
+// provided context.Context is passed to the TenantConfigProvider and the ClientPool of the tenant.
+//
+// Parameters:
+//   - ctx: The context.Context to control the configuration lookup and the sending.
+//   - tenantID: The ID of the tenant to send the messages for.
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error if the configuration of the tenant cannot be retrieved or any of the messages could
+//     not be sent; otherwise, returns nil.
+func (m *ClientManager) SendAsWithContext(ctx context.Context, tenantID string, messages ...*Msg) error {
+	managed, err := m.acquire(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	defer m.release(managed)
+	return managed.pool.SendWithContext(ctx, messages...)
+}
+
+// Evict closes and removes the ClientPool of the tenant with the given ID, so that the configuration
+// of the tenant is looked up again on next use, i. e. after its credentials have been rotated.
+// Connections that are currently in use are closed as soon as they are returned to the pool.
+//
+// Parameters:
+//   - tenantID: The ID of the tenant to evict.
+func (m *ClientManager) Evict(tenantID string) {
+	m.mutex.Lock()
+	managed, ok := m.pools[tenantID]
+	delete(m.pools, tenantID)
+	m.mutex.Unlock()
+	if ok {
+		_ = managed.pool.Close()
+	}
+}
+
+// Close closes the ClientPools of all tenants. After Close the ClientManager cannot be used anymore.
+//
+// Returns:
+//   - An error if the ClientManager was already closed; otherwise, returns nil.
+func (m *ClientManager) Close() error {
+	m.mutex.Lock()
+	if m.isClosed {
+		m.mutex.Unlock()
+		return ErrClientManagerClosed
+	}
+	m.isClosed = true
+	close(m.stop)
+	pools := m.pools
+	m.pools = make(map[string]*managedPool)
+	m.mutex.Unlock()
+
+	for _, managed := range pools {
+		_ = managed.pool.Close()
+	}
+	return nil
+}
+
+// acquire returns the managed ClientPool of the tenant with the given ID and marks it as in use. If
+// the tenant has no ClientPool yet, it is created from the TenantConfig of the tenant.
+//
+// Parameters:
+//   - ctx: The context.Context that is passed to the TenantConfigProvider.
+//   - tenantID: The ID of the tenant.
+//
+// Returns:
+//   - A pointer to the managedPool of the tenant.
+//   - An error if the ClientManager is closed or the ClientPool of the tenant cannot be created.
+func (m *ClientManager) acquire(ctx context.Context, tenantID string) (*managedPool, error) {
+	m.mutex.Lock()
+	if m.isClosed {
+		m.mutex.Unlock()
+		return nil, ErrClientManagerClosed
+	}
+	if managed, ok := m.pools[tenantID]; ok {
+		managed.inUse++
+		m.mutex.Unlock()
+		return managed, nil
+	}
+	m.mutex.Unlock()
+
+	// The configuration lookup might be slow and is therefore performed without holding the lock
+	config, err := m.provider.TenantConfig(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config of tenant %q: %w", tenantID, err)
+	}
+	pool, err := NewClientPool(config.Host, append([]PoolOption{WithPoolClientOptions(config.ClientOptions...)},
+		config.PoolOptions...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client pool of tenant %q: %w", tenantID, err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.isClosed {
+		_ = pool.Close()
+		return nil, ErrClientManagerClosed
+	}
+	managed, ok := m.pools[tenantID]
+	if ok {
+		// Another goroutine created the ClientPool of the tenant in the meantime
+		_ = pool.Close()
+	} else {
+		managed = &managedPool{pool: pool}
+		m.pools[tenantID] = managed
+	}
+	managed.inUse++
+	return managed, nil
+}
+
+// release marks the given managed ClientPool as no longer in use by the caller.
+//
+// Parameters:
+//   - managed: A pointer to the managedPool to release.
+func (m *ClientManager) release(managed *managedPool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	managed.inUse--
+	managed.lastUsed = time.Now()
+}
+
+// evictIdleLoop periodically evicts the idle ClientPools until the ClientManager is closed.
+func (m *ClientManager) evictIdleLoop() {
+	interval := m.idleTimeout / 2
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case now := <-ticker.C:
+			m.evictIdle(now)
+		}
+	}
+}
+
+// evictIdle closes and removes all ClientPools that are not in use and have not been used for
+// longer than the idle timeout.
+//
+// Parameters:
+//   - now: The current point in time.
+func (m *ClientManager) evictIdle(now time.Time) {
+	var idle []*ClientPool
+	m.mutex.Lock()
+	for tenantID, managed := range m.pools {
+		if managed.inUse == 0 && now.Sub(managed.lastUsed) > m.idleTimeout {
+			idle = append(idle, managed.pool)
+			delete(m.pools, tenantID)
+		}
+	}
+	m.mutex.Unlock()
+	for _, pool := range idle {
+		_ = pool.Close()
+	}
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testTenantProvider is a TenantConfigProvider that counts the lookups per tenant.
+type testTenantProvider struct {
+	mutex   sync.Mutex
+	lookups map[string]int
+	port    int
+}
+
+var errTestUnknownTenant = errors.New("unknown tenant")
+
+func (p *testTenantProvider) TenantConfig(_ context.Context, tenantID string) (TenantConfig, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.lookups[tenantID]++
+	if tenantID == "unknown" {
+		return TenantConfig{}, errTestUnknownTenant
+	}
+	return TenantConfig{
+		ClientOptions: []Option{
+			WithPort(p.port), WithTLSPolicy(NoTLS), WithUsername(tenantID), WithPassword("secret"),
+		},
+		Host:        DefaultHost,
+		PoolOptions: []PoolOption{WithPoolSize(1)},
+	}, nil
+}
+
+func (p *testTenantProvider) lookupCount(tenantID string) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.lookups[tenantID]
+}
+
+// sendAsWithTimeout sends the Msg as the given tenant with a deadline, so that a stuck dial does not
+// block the test suite.
+func sendAsWithTimeout(t *testing.T, manager *ClientManager, tenantID string, message *Msg) error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	return manager.SendAsWithContext(ctx, tenantID, message)
+}
+
+func TestNewClientManager(t *testing.T) {
+	t.Run("nil provider fails", func(t *testing.T) {
+		if _, err := NewClientManager(nil); !errors.Is(err, ErrTenantConfigProviderIsNil) {
+			t.Errorf("expected error: %s, got: %s", ErrTenantConfigProviderIsNil, err)
+		}
+	})
+	t.Run("negative idle timeout fails", func(t *testing.T) {
+		provider := TenantConfigProviderFunc(func(context.Context, string) (TenantConfig, error) {
+			return TenantConfig{}, nil
+		})
+		_, err := NewClientManager(provider, WithClientManagerIdleTimeout(-1))
+		if !errors.Is(err, ErrInvalidPoolDuration) {
+			t.Errorf("expected error: %s, got: %s", ErrInvalidPoolDuration, err)
+		}
+	})
+}
+
+func TestClientManager_SendAs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	PortAdder.Add(1)
+	serverPort := int(TestServerPortBase + PortAdder.Load())
+	featureSet := "250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+	go func() {
+		if err := simpleSMTPServer(ctx, t, &serverProps{
+			FeatureSet:     featureSet,
+			ListenPort:     serverPort,
+			HandleParallel: true,
+		}); err != nil {
+			t.Errorf("failed to start test server: %s", err)
+			return
+		}
+	}()
+	time.Sleep(time.Millisecond * 30)
+
+	t.Run("clients are cached per tenant", func(t *testing.T) {
+		provider := &testTenantProvider{lookups: make(map[string]int), port: serverPort}
+		manager, err := NewClientManager(provider, WithClientManagerIdleTimeout(0))
+		if err != nil {
+			t.Fatalf("failed to create client manager: %s", err)
+		}
+		t.Cleanup(func() { _ = manager.Close() })
+		for _, tenantID := range []string{"tenant-a", "tenant-b", "tenant-a"} {
+			if err = sendAsWithTimeout(t, manager, tenantID, testMessage(t)); err != nil {
+				t.Fatalf("failed to send message as %s: %s", tenantID, err)
+			}
+		}
+		if provider.lookupCount("tenant-a") != 1 || provider.lookupCount("tenant-b") != 1 {
+			t.Errorf("expected one config lookup per tenant, got: %v", provider.lookups)
+		}
+		manager.Evict("tenant-a")
+		if err = sendAsWithTimeout(t, manager, "tenant-a", testMessage(t)); err != nil {
+			t.Fatalf("failed to send message after eviction: %s", err)
+		}
+		if provider.lookupCount("tenant-a") != 2 {
+			t.Errorf("expected tenant config to be looked up again, got: %d", provider.lookupCount("tenant-a"))
+		}
+	})
+	t.Run("idle clients are evicted", func(t *testing.T) {
+		provider := &testTenantProvider{lookups: make(map[string]int), port: serverPort}
+		manager, err := NewClientManager(provider, WithClientManagerIdleTimeout(time.Millisecond*20))
+		if err != nil {
+			t.Fatalf("failed to create client manager: %s", err)
+		}
+		t.Cleanup(func() { _ = manager.Close() })
+		if err = sendAsWithTimeout(t, manager, "tenant-a", testMessage(t)); err != nil {
+			t.Fatalf("failed to send message: %s", err)
+		}
+		time.Sleep(time.Millisecond * 100)
+		manager.mutex.Lock()
+		pools := len(manager.pools)
+		manager.mutex.Unlock()
+		if pools != 0 {
+			t.Errorf("expected idle client pool to be evicted, got %d pools", pools)
+		}
+	})
+	t.Run("unknown tenant fails", func(t *testing.T) {
+		provider := &testTenantProvider{lookups: make(map[string]int), port: serverPort}
+		manager, err := NewClientManager(provider)
+		if err != nil {
+			t.Fatalf("failed to create client manager: %s", err)
+		}
+		t.Cleanup(func() { _ = manager.Close() })
+		if err = sendAsWithTimeout(t, manager, "unknown", testMessage(t)); !errors.Is(err, errTestUnknownTenant) {
+			t.Errorf("expected error: %s, got: %s", errTestUnknownTenant, err)
+		}
+	})
+	t.Run("closed manager fails", func(t *testing.T) {
+		provider := &testTenantProvider{lookups: make(map[string]int), port: serverPort}
+		manager, err := NewClientManager(provider)
+		if err != nil {
+			t.Fatalf("failed to create client manager: %s", err)
+		}
+		if err = manager.Close(); err != nil {
+			t.Fatalf("failed to close client manager: %s", err)
+		}
+		if err = sendAsWithTimeout(t, manager, "tenant-a", testMessage(t)); !errors.Is(err, ErrClientManagerClosed) {
+			t.Errorf("expected error: %s, got: %s", ErrClientManagerClosed, err)
+		}
+		if err = manager.Close(); !errors.Is(err, ErrClientManagerClosed) {
+			t.Errorf("expected error: %s, got: %s", ErrClientManagerClosed, err)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"net/mail"
+	"net/textproto"
+)
+
+// Clone returns a deep copy of the Msg.
+//
+// A Msg is not safe for concurrent use: writing it updates the headers of its attachments and
+// embeds, and sending it records the delivery state and the SendError in the Msg. Clone allows one
+// base message to be prepared once and fanned out to multiple goroutines, each of which modifies
+// and sends its own copy, i. e. with different recipients.
+//
+// The headers, addresses, parts, attachments, embeds, middlewares, ESMTP parameters and the metadata
+// set with SetValue are copied, so that changes to the copy do not affect the Msg and vice versa. The
+// metadata values themselves are shared. The content of the parts and files is shared and written by
+// each copy from the same source. The content of files created from an io.Reader, an io.ReadSeeker or
+// a ResettableSource can be written concurrently, but the writes of files from an io.ReadSeeker or a
+// ResettableSource are serialized. Files that are streamed with AttachStream can only be written once,
+// by either the Msg or one of its copies. The copy is not delivered and has no SendError.
+//
+// Returns:
+//   - A pointer to the copy of the Msg.
+func (m *Msg) Clone() *Msg {
+	clone := *m
+
+	// Group members are the same *mail.Address values as the addresses of the address header, so
+	// that each address is copied once and the copies are shared the same way.
+	addresses := make(map[*mail.Address]*mail.Address)
+	cloneAddress := func(address *mail.Address) *mail.Address {
+		if cloned, ok := addresses[address]; ok {
+			return cloned
+		}
+		cloned := *address
+		addresses[address] = &cloned
+		return &cloned
+	}
+	clone.addrHeader = make(map[AddrHeader][]*mail.Address, len(m.addrHeader))
+	for header, headerAddresses := range m.addrHeader {
+		for _, address := range headerAddresses {
+			clone.addrHeader[header] = append(clone.addrHeader[header], cloneAddress(address))
+		}
+	}
+	clone.addrGroups = nil
+	if m.addrGroups != nil {
+		clone.addrGroups = make(map[AddrHeader][]*addrGroup, len(m.addrGroups))
+		for header, groups := range m.addrGroups {
+			for _, group := range groups {
+				clonedGroup := &addrGroup{name: group.name}
+				for _, member := range group.members {
+					clonedGroup.members = append(clonedGroup.members, cloneAddress(member))
+				}
+				clone.addrGroups[header] = append(clone.addrGroups[header], clonedGroup)
+			}
+		}
+	}
+	clone.genHeader = make(map[Header][]string, len(m.genHeader))
+	for header, values := range m.genHeader {
+		clone.genHeader[header] = append([]string(nil), values...)
+	}
+	clone.preformHeader = make(map[Header]string, len(m.preformHeader))
+	for header, value := range m.preformHeader {
+		clone.preformHeader[header] = value
+	}
+	clone.parts = make([]*Part, len(m.parts))
+	for i, part := range m.parts {
+		clonedPart := *part
+		clone.parts[i] = &clonedPart
+	}
+	clone.attachments = cloneFiles(m.attachments)
+	clone.embeds = cloneFiles(m.embeds)
+	clone.alternativeOrder = append([]ContentType(nil), m.alternativeOrder...)
+	clone.middlewares = append([]Middleware(nil), m.middlewares...)
+	clone.mailFromParams = append([]string(nil), m.mailFromParams...)
+	clone.rcptParams = nil
+	if m.rcptParams != nil {
+		clone.rcptParams = make(map[string][]string, len(m.rcptParams))
+		for rcpt, params := range m.rcptParams {
+			clone.rcptParams[rcpt] = append([]string(nil), params...)
+		}
+	}
+	clone.values = nil
+	if m.values != nil {
+		clone.values = make(map[interface{}]interface{}, len(m.values))
+		for key, value := range m.values {
+			clone.values[key] = value
+		}
+	}
+	if m.emlDiagnostics != nil {
+		clone.emlDiagnostics = &EMLDiagnostics{
+			Deviations: append([]EMLDeviation(nil), m.emlDiagnostics.Deviations...),
+		}
+	}
+	clone.isDelivered = false
+	clone.sendError = nil
+	return &clone
+}
+
+// cloneFiles returns a copy of the given files with copies of their headers. The writers of the
+// files are shared.
+func cloneFiles(files []*File) []*File {
+	if files == nil {
+		return nil
+	}
+	cloned := make([]*File, len(files))
+	for i, file := range files {
+		clonedFile := *file
+		clonedFile.Header = make(textproto.MIMEHeader, len(file.Header))
+		for key, values := range file.Header {
+			clonedFile.Header[key] = append([]string(nil), values...)
+		}
+		cloned[i] = &clonedFile
+	}
+	return cloned
+}
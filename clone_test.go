@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMsg_Clone(t *testing.T) {
+	t.Run("Clone copies the message", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.ToGroup("Team", "team@domain.tld"); err != nil {
+			t.Fatalf("failed to set group address: %s", err)
+		}
+		message.SetGenHeader(HeaderXMailer, "original")
+		if err := message.AddRcptParam("valid-to@domain.tld", "X-TRACKING", "original"); err != nil {
+			t.Fatalf("failed to add recipient parameter: %s", err)
+		}
+		if err := message.AttachReader("report.txt", strings.NewReader("report")); err != nil {
+			t.Fatalf("failed to attach file: %s", err)
+		}
+		message.SetValue("campaign", "original")
+		message.isDelivered = true
+		message.sendError = errors.New("send failed")
+
+		clone := message.Clone()
+		if clone.IsDelivered() || clone.SendError() != nil {
+			t.Error("expected clone to have no delivery state")
+		}
+		if clone.addrGroups[HeaderTo][0].members[0] != clone.GetAddrHeader(HeaderTo)[1] {
+			t.Error("expected group member of clone to be the address of its address header")
+		}
+		clone.addrGroups[HeaderTo][0].members[0].Address = "clone-team@domain.tld"
+		if err := clone.To("clone@domain.tld"); err != nil {
+			t.Fatalf("failed to set recipient of clone: %s", err)
+		}
+		clone.GetAddrHeader(HeaderFrom)[0].Name = "Clone"
+		clone.SetGenHeader(HeaderXMailer, "clone")
+		clone.rcptParams["valid-to@domain.tld"][0] = "NOTIFY=NEVER"
+		clone.GetParts()[0].SetContent("clone")
+		clone.GetAttachments()[0].Header.Set("X-Clone", "true")
+		clone.GetAttachments()[0].Name = "clone.txt"
+		if clone.Value("campaign") != "original" {
+			t.Errorf("expected metadata to be copied, got: %v", clone.Value("campaign"))
+		}
+		clone.SetValue("campaign", "clone")
+
+		if to := message.GetToString(); len(to) != 2 || to[0] != "<valid-to@domain.tld>" {
+			t.Errorf("expected recipients of original to be kept, got: %v", to)
+		}
+		if message.GetAddrHeader(HeaderFrom)[0].Name != "" {
+			t.Error("expected sender of original to be kept")
+		}
+		if message.addrGroups[HeaderTo][0].members[0].Address != "team@domain.tld" {
+			t.Error("expected group of original to be kept")
+		}
+		if keywords := message.GetGenHeader(HeaderXMailer); keywords[0] != "original" {
+			t.Errorf("expected header of original to be kept, got: %v", keywords)
+		}
+		if params := message.rcptParamsFor("valid-to@domain.tld"); params[0] != "X-TRACKING=original" {
+			t.Errorf("expected recipient parameters of original to be kept, got: %v", params)
+		}
+		if content, _ := message.GetParts()[0].GetContent(); string(content) != "Testmail" {
+			t.Errorf("expected part of original to be kept, got: %s", content)
+		}
+		if message.Value("campaign") != "original" {
+			t.Errorf("expected metadata of original to be kept, got: %v", message.Value("campaign"))
+		}
+		attachment := message.GetAttachments()[0]
+		if attachment.Name != "report.txt" || attachment.Header.Get("X-Clone") != "" {
+			t.Error("expected attachment of original to be kept")
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err := clone.GetAttachments()[0].Writer(buffer); err != nil || buffer.String() != "report" {
+			t.Errorf("expected attachment content to be shared, got: %s", buffer.String())
+		}
+	})
+	t.Run("clones are written concurrently", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.AttachReader("reader.txt", strings.NewReader("reader content")); err != nil {
+			t.Fatalf("failed to attach file: %s", err)
+		}
+		message.AttachReadSeeker("seeker.txt", strings.NewReader("seeker content"))
+		if err := message.AttachResettable("source.txt", newTestResettableSource("source content")); err != nil {
+			t.Fatalf("failed to attach file: %s", err)
+		}
+
+		var wg sync.WaitGroup
+		outputs := make([]string, 10)
+		for i := range outputs {
+			clone := message.Clone()
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				buffer := bytes.NewBuffer(nil)
+				if _, err := clone.WriteTo(buffer); err != nil {
+					t.Errorf("failed to write clone: %s", err)
+				}
+				outputs[i] = buffer.String()
+			}(i)
+		}
+		wg.Wait()
+		for _, output := range outputs {
+			for _, content := range []string{"reader content", "seeker content", "source content"} {
+				if !strings.Contains(output, base64.StdEncoding.EncodeToString([]byte(content))) {
+					t.Errorf("expected output of clone to contain %q", content)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,606 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/wneessen/go-mail/log"
+)
+
+const (
+	// TLSAUsageDANETA is the certificate usage of a TLSA record that pins a trust anchor of the
+	// certificate chain of the server (DANE-TA).
+	TLSAUsageDANETA uint8 = 2
+
+	// TLSAUsageDANEEE is the certificate usage of a TLSA record that pins the certificate of the
+	// server itself (DANE-EE).
+	TLSAUsageDANEEE uint8 = 3
+
+	// TLSASelectorCert is the selector of a TLSA record that matches the full certificate.
+	TLSASelectorCert uint8 = 0
+
+	// TLSASelectorSPKI is the selector of a TLSA record that matches the SubjectPublicKeyInfo of
+	// the certificate.
+	TLSASelectorSPKI uint8 = 1
+
+	// TLSAMatchingFull is the matching type of a TLSA record that holds the selected data itself.
+	TLSAMatchingFull uint8 = 0
+
+	// TLSAMatchingSHA256 is the matching type of a TLSA record that holds the SHA-256 hash of the
+	// selected data.
+	TLSAMatchingSHA256 uint8 = 1
+
+	// TLSAMatchingSHA512 is the matching type of a TLSA record that holds the SHA-512 hash of the
+	// selected data.
+	TLSAMatchingSHA512 uint8 = 2
+)
+
+const (
+	// defaultTLSALookupTimeout is the timeout for the TLSA lookup if the context has no earlier
+	// deadline.
+	defaultTLSALookupTimeout = time.Second * 5
+
+	// dnsFlagsAuthenticData is the AD bit in the flags of a DNS message header.
+	dnsFlagsAuthenticData = 0x0020
+
+	// dnsFlagsResponse is the QR bit in the flags of a DNS message header.
+	dnsFlagsResponse = 0x8000
+
+	// dnsFlagsTruncated is the TC bit in the flags of a DNS message header.
+	dnsFlagsTruncated = 0x0200
+
+	// dnsHeaderLength is the length of a DNS message header.
+	dnsHeaderLength = 12
+
+	// dnsTypeTLSA is the DNS resource record type of a TLSA record.
+	dnsTypeTLSA = 52
+
+	// resolvConfPath is the path of the resolver configuration on Unix-like systems.
+	resolvConfPath = "/etc/resolv.conf"
+)
+
+var (
+	// ErrDANEVerification indicates that the connection to the SMTP server could not be
+	// authenticated with the TLSA records of the server.
+	ErrDANEVerification = errors.New("DANE verification failed")
+
+	// errDNSTruncated indicates that a DNS response over UDP was truncated.
+	errDNSTruncated = errors.New("DNS response is truncated")
+)
+
+type (
+	// DANEOption is a function type that configures the DANE verification of the Client.
+	DANEOption func(*daneConfig)
+
+	// TLSARecord represents a DNS TLSA record as defined in RFC 6698.
+	TLSARecord struct {
+		// Data is the certificate association data of the record.
+		Data []byte
+
+		// MatchingType defines how the Data is matched against the selected certificate data.
+		MatchingType uint8
+
+		// Selector defines which part of the certificate is matched.
+		Selector uint8
+
+		// Usage defines the certificate usage of the record.
+		Usage uint8
+	}
+
+	// TLSAResolver is a function type that returns the TLSA records for the given DNS name, i. e.
+	// "_25._tcp.mx.example.com". Only records whose authenticity has been verified with DNSSEC must
+	// be returned. If the name has no TLSA records or the records are not DNSSEC-signed, no records
+	// and no error are returned. An error must only be returned if the lookup itself failed.
+	TLSAResolver func(ctx context.Context, name string) ([]TLSARecord, error)
+
+	// daneConfig holds the configuration of the DANE verification.
+	daneConfig struct {
+		// reportOnly controls whether a failed verification is only logged instead of terminating
+		// the connection.
+		reportOnly bool
+
+		// resolver is the TLSAResolver that looks up the TLSA records of the server.
+		resolver TLSAResolver
+	}
+)
+
+// WithDANE enables the DANE verification of the SMTP server as defined in RFC 7672.
+//
+// Before connecting, the Client looks up the TLSA records of the configured host and port. If the
+// server publishes DNSSEC-signed TLSA records with the usage DANE-TA or DANE-EE, the connection
+// must be encrypted and the certificate presented by the server must match one of the records,
+// otherwise the connection fails with ErrDANEVerification. For DANE-EE records, the name and the
+// expiry of the certificate are not checked, as defined by RFC 7672. If the server publishes no
+// usable TLSA records, the certificate is verified as usual.
+//
+// By default, the TLSA records are queried from the nameservers in "/etc/resolv.conf". Since the
+// authenticity of the records is taken from the AD flag of the response, the nameserver must be a
+// trusted, DNSSEC-validating resolver, i. e. on the local host.
+//
+// Parameters:
+//   - opts: Optional DANEOption functions to configure the DANE verification.
+//
+// Returns:
+//   - An Option function that enables the DANE verification for the Client.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc6698
+//   - https://datatracker.ietf.org/doc/html/rfc7672
+func WithDANE(opts ...DANEOption) Option {
+	return func(c *Client) error {
+		config := &daneConfig{resolver: lookupTLSA}
+		for _, opt := range opts {
+			if opt != nil {
+				opt(config)
+			}
+		}
+		c.dane = config
+		return nil
+	}
+}
+
+// WithDANEResolver sets the TLSAResolver that looks up the TLSA records of the server. A nil
+// resolver is ignored.
+//
+// Parameters:
+//   - resolver: The TLSAResolver to use.
+//
+// Returns:
+//   - A DANEOption that sets the TLSAResolver of the DANE verification.
+func WithDANEResolver(resolver TLSAResolver) DANEOption {
+	return func(config *daneConfig) {
+		if resolver != nil {
+			config.resolver = resolver
+		}
+	}
+}
+
+// WithDANEReportOnly configures the DANE verification to only log a failed verification as a
+// warning, instead of terminating the connection. The warning is logged to the logger of the
+// Client or, if none is set, to STDERR. The certificate is then verified as if DANE was disabled.
+//
+// Returns:
+//   - A DANEOption that enables the report-only mode of the DANE verification.
+func WithDANEReportOnly() DANEOption {
+	return func(config *daneConfig) {
+		config.reportOnly = true
+	}
+}
+
+// lookupDANERecords looks up the usable TLSA records of the server, if DANE is enabled for the
+// Client, and stores them for the verification of the TLS handshake.
+//
+// Parameters:
+//   - ctx: The context.Context that controls the timeout and cancellation of the lookup.
+//
+// Returns:
+//   - An error if the lookup failed and the DANE verification is not in report-only mode;
+//     otherwise, returns nil.
+func (c *Client) lookupDANERecords(ctx context.Context) error {
+	c.daneRecords = nil
+	if c.dane == nil || c.unixSocket != "" {
+		return nil
+	}
+	records, err := c.dane.resolver(ctx, fmt.Sprintf("_%d._tcp.%s", c.port, strings.TrimSuffix(c.host, ".")))
+	if err != nil {
+		return c.daneFailure(fmt.Errorf("%w: failed to look up TLSA records: %s", ErrDANEVerification, err))
+	}
+	for _, record := range records {
+		if isUsableTLSARecord(record) {
+			c.daneRecords = append(c.daneRecords, record)
+		}
+	}
+	return nil
+}
+
+// daneTLSConfig returns the tls.Config for the connection to the server. If DANE is enabled for
+// the Client, a copy of the configured tls.Config is returned that verifies the certificate of the
+// server against the TLSA records that were looked up when connecting.
+//
+// Returns:
+//   - A pointer to the tls.Config for the connection.
+func (c *Client) daneTLSConfig() *tls.Config {
+	if c.dane == nil || c.tlsconfig == nil {
+		return c.tlsconfig
+	}
+	config := c.tlsconfig.Clone()
+	insecure := config.InsecureSkipVerify
+	verifyConnection := config.VerifyConnection
+	config.InsecureSkipVerify = true
+	config.VerifyConnection = func(state tls.ConnectionState) error {
+		if err := c.verifyDANE(config, state, insecure); err != nil {
+			return err
+		}
+		if verifyConnection != nil {
+			return verifyConnection(state)
+		}
+		return nil
+	}
+	return config
+}
+
+// verifyDANE verifies the certificate of the server against the TLSA records of the server. If
+// there are no TLSA records or the DANE verification is in report-only mode and fails, the
+// certificate is verified against the root CAs of the tls.Config instead, unless the verification
+// is disabled with InsecureSkipVerify.
+//
+// Parameters:
+//   - config: The tls.Config of the connection.
+//   - state: The tls.ConnectionState of the handshake.
+//   - insecure: Whether the certificate verification is disabled in the configured tls.Config.
+//
+// Returns:
+//   - An error if the certificate of the server could not be verified; otherwise, returns nil.
+func (c *Client) verifyDANE(config *tls.Config, state tls.ConnectionState, insecure bool) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("server presented no certificate")
+	}
+	serverName := state.ServerName
+	if serverName == "" {
+		serverName = c.host
+	}
+	now := time.Now()
+	if config.Time != nil {
+		now = config.Time()
+	}
+	if len(c.daneRecords) > 0 {
+		err := verifyTLSARecords(c.daneRecords, state.PeerCertificates, serverName, now)
+		if err == nil {
+			return nil
+		}
+		if err = c.daneFailure(fmt.Errorf("%w: %s", ErrDANEVerification, err)); err != nil {
+			return err
+		}
+	}
+	if insecure {
+		return nil
+	}
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		CurrentTime:   now,
+		DNSName:       serverName,
+		Intermediates: certPool(state.PeerCertificates[1:]),
+		Roots:         config.RootCAs,
+	})
+	return err
+}
+
+// daneFailure handles a failed DANE verification. In report-only mode, the error is logged as a
+// warning and nil is returned; otherwise, the error is returned.
+//
+// Parameters:
+//   - err: The error of the failed DANE verification.
+//
+// Returns:
+//   - The given error, or nil in report-only mode.
+func (c *Client) daneFailure(err error) error {
+	if !c.dane.reportOnly {
+		return err
+	}
+	logger := c.logger
+	if logger == nil {
+		logger = log.New(os.Stderr, log.LevelWarn)
+	}
+	logger.Warnf(log.Log{Direction: log.DirNone, Format: "DANE verification failed", Fields: []log.Field{
+		{Key: log.FieldHost, Value: c.ServerAddr()},
+		{Key: log.FieldError, Value: err.Error()},
+	}})
+	return nil
+}
+
+// isUsableTLSARecord reports whether the given TLSARecord can be used for the DANE verification of
+// an SMTP server. RFC 7672 only allows the usages DANE-TA and DANE-EE for SMTP.
+func isUsableTLSARecord(record TLSARecord) bool {
+	return (record.Usage == TLSAUsageDANETA || record.Usage == TLSAUsageDANEEE) &&
+		(record.Selector == TLSASelectorCert || record.Selector == TLSASelectorSPKI) &&
+		record.MatchingType <= TLSAMatchingSHA512
+}
+
+// verifyTLSARecords verifies the certificate chain of the server against the given TLSA records.
+// A DANE-EE record must match the certificate of the server. A DANE-TA record must match one of
+// the other certificates in the chain, which must then be a valid trust anchor for the certificate
+// of the server and the given server name.
+//
+// Parameters:
+//   - records: The usable TLSA records of the server.
+//   - certs: The certificate chain presented by the server.
+//   - serverName: The name of the server that the certificate must be valid for.
+//   - now: The time at which the certificate chain must be valid.
+//
+// Returns:
+//   - An error if none of the TLSA records matches; otherwise, returns nil.
+func verifyTLSARecords(records []TLSARecord, certs []*x509.Certificate, serverName string, now time.Time) error {
+	for _, record := range records {
+		if record.Usage == TLSAUsageDANEEE {
+			if matchesTLSARecord(record, certs[0]) {
+				return nil
+			}
+			continue
+		}
+		for i, anchor := range certs[1:] {
+			if !matchesTLSARecord(record, anchor) {
+				continue
+			}
+			_, err := certs[0].Verify(x509.VerifyOptions{
+				CurrentTime:   now,
+				DNSName:       serverName,
+				Intermediates: certPool(certs[1 : i+1]),
+				Roots:         certPool([]*x509.Certificate{anchor}),
+			})
+			if err == nil {
+				return nil
+			}
+		}
+	}
+	return errors.New("no TLSA record matches the certificate of the server")
+}
+
+// matchesTLSARecord reports whether the given certificate matches the given TLSARecord.
+func matchesTLSARecord(record TLSARecord, cert *x509.Certificate) bool {
+	data := cert.Raw
+	if record.Selector == TLSASelectorSPKI {
+		data = cert.RawSubjectPublicKeyInfo
+	}
+	switch record.MatchingType {
+	case TLSAMatchingSHA256:
+		sum := sha256.Sum256(data)
+		data = sum[:]
+	case TLSAMatchingSHA512:
+		sum := sha512.Sum512(data)
+		data = sum[:]
+	}
+	return bytes.Equal(data, record.Data)
+}
+
+// certPool returns a new x509.CertPool with the given certificates.
+func certPool(certs []*x509.Certificate) *x509.CertPool {
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+	return pool
+}
+
+// lookupTLSA is the default TLSAResolver. It queries the TLSA records for the given name from the
+// nameservers in "/etc/resolv.conf", or from "127.0.0.1" if none are configured. Only records from
+// responses with the AD flag, which indicates that the resolver validated them with DNSSEC, are
+// returned.
+//
+// Parameters:
+//   - ctx: The context.Context that controls the timeout and cancellation of the lookup.
+//   - name: The DNS name to look up the TLSA records for.
+//
+// Returns:
+//   - The DNSSEC-validated TLSA records for the name.
+//   - An error if none of the nameservers answered the query; otherwise, returns nil.
+func lookupTLSA(ctx context.Context, name string) ([]TLSARecord, error) {
+	var err error
+	for _, server := range resolvConfNameservers(resolvConfPath) {
+		var records []TLSARecord
+		if records, err = queryTLSA(ctx, server, name); err == nil {
+			return records, nil
+		}
+	}
+	return nil, err
+}
+
+// resolvConfNameservers returns the addresses of the nameservers in the given resolver
+// configuration file, or "127.0.0.1:53" if the file does not exist or lists no nameservers.
+func resolvConfNameservers(path string) []string {
+	var servers []string
+	content, err := os.ReadFile(path)
+	if err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 && fields[0] == "nameserver" {
+				servers = append(servers, net.JoinHostPort(fields[1], "53"))
+			}
+		}
+	}
+	if len(servers) == 0 {
+		servers = append(servers, "127.0.0.1:53")
+	}
+	return servers
+}
+
+// queryTLSA queries the TLSA records for the given name from the given nameserver. The query is sent
+// over UDP and repeated over TCP if the response is truncated.
+//
+// Parameters:
+//   - ctx: The context.Context that controls the timeout and cancellation of the query.
+//   - server: The address of the nameserver.
+//   - name: The DNS name to look up the TLSA records for.
+//
+// Returns:
+//   - The DNSSEC-validated TLSA records for the name.
+//   - An error if the query failed; otherwise, returns nil.
+func queryTLSA(ctx context.Context, server, name string) ([]TLSARecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTLSALookupTimeout)
+	defer cancel()
+	idBytes := make([]byte, 2)
+	if _, err := io.ReadFull(rand.Reader, idBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate DNS query ID: %w", err)
+	}
+	id := binary.BigEndian.Uint16(idBytes)
+	query, err := tlsaQuery(id, name)
+	if err != nil {
+		return nil, err
+	}
+	response, err := exchangeDNS(ctx, "udp", server, query)
+	if err != nil {
+		return nil, err
+	}
+	records, err := parseTLSAResponse(id, response)
+	if errors.Is(err, errDNSTruncated) {
+		if response, err = exchangeDNS(ctx, "tcp", server, query); err != nil {
+			return nil, err
+		}
+		records, err = parseTLSAResponse(id, response)
+	}
+	return records, err
+}
+
+// exchangeDNS sends the given DNS query to the given nameserver and returns the response. Over TCP,
+// the messages are prefixed with their length as defined in RFC 1035.
+func exchangeDNS(ctx context.Context, network, server string, query []byte) ([]byte, error) {
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nameserver: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("failed to set nameserver deadline: %w", err)
+		}
+	}
+
+	if network == "udp" {
+		if _, err = conn.Write(query); err != nil {
+			return nil, fmt.Errorf("failed to send DNS query: %w", err)
+		}
+		response := make([]byte, 4096)
+		n, err := conn.Read(response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read DNS response: %w", err)
+		}
+		return response[:n], nil
+	}
+	length := make([]byte, 2)
+	binary.BigEndian.PutUint16(length, uint16(len(query)))
+	if _, err = conn.Write(append(length, query...)); err != nil {
+		return nil, fmt.Errorf("failed to send DNS query: %w", err)
+	}
+	if _, err = io.ReadFull(conn, length); err != nil {
+		return nil, fmt.Errorf("failed to read DNS response: %w", err)
+	}
+	response := make([]byte, binary.BigEndian.Uint16(length))
+	if _, err = io.ReadFull(conn, response); err != nil {
+		return nil, fmt.Errorf("failed to read DNS response: %w", err)
+	}
+	return response, nil
+}
+
+// tlsaQuery builds a recursive DNS query for the TLSA records of the given name. The query sets the
+// AD flag and an EDNS0 OPT record with the DO flag, so that the resolver reports whether it
+// validated the answer with DNSSEC.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc1035#section-4.1
+//   - https://datatracker.ietf.org/doc/html/rfc6840#section-5.7
+func tlsaQuery(id uint16, name string) ([]byte, error) {
+	query := make([]byte, dnsHeaderLength, 512)
+	binary.BigEndian.PutUint16(query[0:], id)
+	binary.BigEndian.PutUint16(query[2:], 0x0100|dnsFlagsAuthenticData)
+	binary.BigEndian.PutUint16(query[4:], 1)
+	binary.BigEndian.PutUint16(query[10:], 1)
+	name = strings.TrimSuffix(name, ".")
+	if len(name) == 0 || len(name) > 253 {
+		return nil, fmt.Errorf("invalid DNS name: %q", name)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("invalid DNS name: %q", name)
+		}
+		query = append(query, byte(len(label)))
+		query = append(query, label...)
+	}
+	query = append(query, 0, 0, dnsTypeTLSA, 0, 1)
+	// EDNS0 OPT record with a UDP payload size of 1232 bytes and the DO flag
+	query = append(query, 0, 0, 41, 0x04, 0xd0, 0, 0, 0x80, 0, 0, 0)
+	return query, nil
+}
+
+// parseTLSAResponse parses the TLSA records from the given DNS response. If the name does not exist
+// or the response does not have the AD flag, no records are returned.
+func parseTLSAResponse(id uint16, response []byte) ([]TLSARecord, error) {
+	if len(response) < dnsHeaderLength {
+		return nil, errors.New("DNS response is too short")
+	}
+	flags := binary.BigEndian.Uint16(response[2:])
+	if binary.BigEndian.Uint16(response[0:]) != id || flags&dnsFlagsResponse == 0 {
+		return nil, errors.New("DNS response does not match the query")
+	}
+	if flags&dnsFlagsTruncated != 0 {
+		return nil, errDNSTruncated
+	}
+	switch rcode := flags & 0x000f; rcode {
+	case 0:
+	case 3:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("DNS query failed with response code %d", rcode)
+	}
+	if flags&dnsFlagsAuthenticData == 0 {
+		return nil, nil
+	}
+
+	offset := dnsHeaderLength
+	var err error
+	for i := 0; i < int(binary.BigEndian.Uint16(response[4:])); i++ {
+		if offset, err = skipDNSName(response, offset); err != nil {
+			return nil, err
+		}
+		offset += 4
+	}
+	var records []TLSARecord
+	for i := 0; i < int(binary.BigEndian.Uint16(response[6:])); i++ {
+		if offset, err = skipDNSName(response, offset); err != nil {
+			return nil, err
+		}
+		if offset+10 > len(response) {
+			return nil, errors.New("DNS response is too short")
+		}
+		recordType := binary.BigEndian.Uint16(response[offset:])
+		length := int(binary.BigEndian.Uint16(response[offset+8:]))
+		offset += 10
+		if offset+length > len(response) {
+			return nil, errors.New("DNS response is too short")
+		}
+		if recordType == dnsTypeTLSA && length >= 3 {
+			data := response[offset : offset+length]
+			records = append(records, TLSARecord{
+				Data:         append([]byte(nil), data[3:]...),
+				MatchingType: data[2],
+				Selector:     data[1],
+				Usage:        data[0],
+			})
+		}
+		offset += length
+	}
+	return records, nil
+}
+
+// skipDNSName returns the offset behind the possibly compressed DNS name at the given offset.
+func skipDNSName(message []byte, offset int) (int, error) {
+	for offset < len(message) {
+		length := int(message[offset])
+		switch {
+		case length == 0:
+			return offset + 1, nil
+		case length&0xc0 == 0xc0:
+			return offset + 2, nil
+		default:
+			offset += length + 1
+		}
+	}
+	return 0, errors.New("DNS response is too short")
+}
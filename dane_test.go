@@ -0,0 +1,402 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wneessen/go-mail/log"
+)
+
+// testTLSAResolver returns a TLSAResolver that returns the given records and error.
+func testTLSAResolver(records []TLSARecord, err error) TLSAResolver {
+	return func(context.Context, string) ([]TLSARecord, error) {
+		return records, err
+	}
+}
+
+// localhostCertTLSA returns a DANE-EE TLSA record that matches the SubjectPublicKeyInfo of the
+// certificate of the test server.
+func localhostCertTLSA(t *testing.T) TLSARecord {
+	t.Helper()
+	keypair, err := tls.X509KeyPair(localhostCert, localhostKey)
+	if err != nil {
+		t.Fatalf("failed to read TLS keypair: %s", err)
+	}
+	cert, err := x509.ParseCertificate(keypair.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %s", err)
+	}
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return TLSARecord{Data: sum[:], MatchingType: TLSAMatchingSHA256, Selector: TLSASelectorSPKI,
+		Usage: TLSAUsageDANEEE}
+}
+
+func TestWithDANE(t *testing.T) {
+	record := localhostCertTLSA(t)
+	mismatch := TLSARecord{Data: make([]byte, 32), MatchingType: TLSAMatchingSHA256, Selector: TLSASelectorSPKI,
+		Usage: TLSAUsageDANEEE}
+	tests := []struct {
+		name        string
+		ssl         bool
+		tlsConfig   *tls.Config
+		opts        []DANEOption
+		wantErr     bool
+		wantDANEErr bool
+		wantLog     bool
+	}{
+		{"matching DANE-EE record", true, &tls.Config{}, []DANEOption{
+			WithDANEResolver(testTLSAResolver([]TLSARecord{record}, nil)),
+		}, false, false, false},
+		{"mismatching record fails", true, &tls.Config{InsecureSkipVerify: true}, []DANEOption{
+			WithDANEResolver(testTLSAResolver([]TLSARecord{mismatch}, nil)),
+		}, true, true, false},
+		{"mismatching record in report-only mode", true, &tls.Config{InsecureSkipVerify: true}, []DANEOption{
+			WithDANEResolver(testTLSAResolver([]TLSARecord{mismatch}, nil)), WithDANEReportOnly(),
+		}, false, false, true},
+		{"unusable records are ignored", true, &tls.Config{InsecureSkipVerify: true}, []DANEOption{
+			WithDANEResolver(testTLSAResolver([]TLSARecord{{Usage: 1, Data: mismatch.Data}}, nil)),
+		}, false, false, false},
+		{"no records with untrusted certificate fails", true, &tls.Config{}, []DANEOption{
+			WithDANEResolver(testTLSAResolver(nil, nil)),
+		}, true, false, false},
+		{"failed lookup fails", true, &tls.Config{InsecureSkipVerify: true}, []DANEOption{
+			WithDANEResolver(testTLSAResolver(nil, errors.New("SERVFAIL"))),
+		}, true, true, false},
+		{"unencrypted connection with records fails", false, nil, []DANEOption{
+			WithDANEResolver(testTLSAResolver([]TLSARecord{record}, nil)),
+		}, true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			PortAdder.Add(1)
+			serverPort := int(TestServerPortBase + PortAdder.Load())
+			go func() {
+				if err := simpleSMTPServer(ctx, t, &serverProps{
+					SSLListener: tt.ssl,
+					FeatureSet:  "250-8BITMIME\r\n250 DSN",
+					ListenPort:  serverPort,
+				}); err != nil {
+					t.Errorf("failed to start test server: %s", err)
+					return
+				}
+			}()
+			time.Sleep(time.Millisecond * 30)
+			ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+			t.Cleanup(cancelDial)
+
+			buffer := bytes.NewBuffer(nil)
+			opts := []Option{WithPort(serverPort), WithDANE(tt.opts...), WithLogger(log.New(buffer, log.LevelWarn))}
+			if tt.ssl {
+				opts = append(opts, WithSSL(), WithTLSConfig(tt.tlsConfig))
+			} else {
+				opts = append(opts, WithTLSPolicy(NoTLS))
+			}
+			client, err := NewClient(DefaultHost, opts...)
+			if err != nil {
+				t.Fatalf("failed to create new client: %s", err)
+			}
+			err = client.DialWithContext(ctxDial)
+			t.Cleanup(func() {
+				_ = client.Close()
+			})
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				t.Skip("failed to connect to the test server due to timeout")
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected connection to fail")
+				}
+				if isDANEErr := errors.Is(err, ErrDANEVerification); isDANEErr != tt.wantDANEErr {
+					t.Errorf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to connect to the test server: %s", err)
+			}
+			if logged := strings.Contains(buffer.String(), "DANE verification failed"); logged != tt.wantLog {
+				t.Errorf("unexpected DANE log output: %s", buffer.String())
+			}
+		})
+	}
+}
+
+func TestClient_lookupDANERecords(t *testing.T) {
+	var lookupName string
+	resolver := func(_ context.Context, name string) ([]TLSARecord, error) {
+		lookupName = name
+		return []TLSARecord{{Usage: 0}, {Usage: TLSAUsageDANEEE}}, nil
+	}
+	client, err := NewClient("mx.example.com.", WithDANE(WithDANEResolver(resolver)))
+	if err != nil {
+		t.Fatalf("failed to create new client: %s", err)
+	}
+	if err = client.lookupDANERecords(context.Background()); err != nil {
+		t.Fatalf("failed to look up TLSA records: %s", err)
+	}
+	if lookupName != "_25._tcp.mx.example.com" {
+		t.Errorf("unexpected TLSA lookup name: %s", lookupName)
+	}
+	if len(client.daneRecords) != 1 || client.daneRecords[0].Usage != TLSAUsageDANEEE {
+		t.Errorf("expected only the usable record, got: %v", client.daneRecords)
+	}
+	client.dane = nil
+	if err = client.lookupDANERecords(context.Background()); err != nil || client.daneRecords != nil {
+		t.Errorf("expected no TLSA records without DANE, got: %v, %v", client.daneRecords, err)
+	}
+}
+
+func TestVerifyTLSARecords(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %s", err)
+	}
+	caTemplate := &x509.Certificate{
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		NotAfter:              time.Now().Add(time.Hour),
+		NotBefore:             time.Now().Add(-time.Hour),
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %s", err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("failed to parse CA certificate: %s", err)
+	}
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate leaf key: %s", err)
+	}
+	leafTemplate := &x509.Certificate{
+		DNSNames:     []string{"mx.example.com"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		NotAfter:     time.Now().Add(time.Hour),
+		NotBefore:    time.Now().Add(-time.Hour),
+		SerialNumber: big.NewInt(2),
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, ca, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create leaf certificate: %s", err)
+	}
+	leaf, err := x509.ParseCertificate(leafDER)
+	if err != nil {
+		t.Fatalf("failed to parse leaf certificate: %s", err)
+	}
+	chain := []*x509.Certificate{leaf, ca}
+	caRecord := TLSARecord{Data: ca.Raw, MatchingType: TLSAMatchingFull, Selector: TLSASelectorCert,
+		Usage: TLSAUsageDANETA}
+	leafSum := sha256.Sum256(leaf.Raw)
+	leafRecord := TLSARecord{Data: leafSum[:], MatchingType: TLSAMatchingSHA256, Selector: TLSASelectorCert,
+		Usage: TLSAUsageDANEEE}
+
+	tests := []struct {
+		name       string
+		records    []TLSARecord
+		serverName string
+		now        time.Time
+		wantErr    bool
+	}{
+		{"DANE-TA record", []TLSARecord{caRecord}, "mx.example.com", time.Now(), false},
+		{"DANE-TA record with wrong name", []TLSARecord{caRecord}, "mx.example.org", time.Now(), true},
+		{"DANE-TA record with expired chain", []TLSARecord{caRecord}, "mx.example.com",
+			time.Now().Add(time.Hour * 2), true},
+		{"DANE-EE record ignores name and expiry", []TLSARecord{leafRecord}, "mx.example.org",
+			time.Now().Add(time.Hour * 2), false},
+		{"DANE-TA record matching the leaf", []TLSARecord{{Data: leaf.Raw, Usage: TLSAUsageDANETA}},
+			"mx.example.com", time.Now(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyTLSARecords(tt.records, chain, tt.serverName, tt.now)
+			if tt.wantErr && err == nil {
+				t.Error("expected verification to fail")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("failed to verify certificate: %s", err)
+			}
+		})
+	}
+}
+
+// testDNSServer starts a DNS server on UDP and TCP that answers each query with the response
+// returned by the given function for the query and network.
+func testDNSServer(t *testing.T, respond func(query []byte, network string) []byte) string {
+	t.Helper()
+	udpConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("failed to listen on UDP: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = udpConn.Close()
+	})
+	tcpListener, err := net.Listen("tcp", udpConn.LocalAddr().String())
+	if err != nil {
+		t.Skipf("failed to listen on TCP: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = tcpListener.Close()
+	})
+	go func() {
+		buffer := make([]byte, 512)
+		for {
+			n, addr, err := udpConn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			_, _ = udpConn.WriteTo(respond(buffer[:n], "udp"), addr)
+		}
+	}()
+	go func() {
+		for {
+			conn, err := tcpListener.Accept()
+			if err != nil {
+				return
+			}
+			length := make([]byte, 2)
+			if _, err = conn.Read(length); err == nil {
+				query := make([]byte, binary.BigEndian.Uint16(length))
+				if _, err = conn.Read(query); err == nil {
+					response := respond(query, "tcp")
+					binary.BigEndian.PutUint16(length, uint16(len(response)))
+					_, _ = conn.Write(append(length, response...))
+				}
+			}
+			_ = conn.Close()
+		}
+	}()
+	return udpConn.LocalAddr().String()
+}
+
+// testDNSResponse returns a response for the given DNS query with the given flags and a TLSA
+// answer with the given record data and a CNAME answer.
+func testDNSResponse(query []byte, flags uint16, data ...[]byte) []byte {
+	response := append([]byte(nil), query...)
+	binary.BigEndian.PutUint16(response[2:], flags)
+	binary.BigEndian.PutUint16(response[6:], uint16(len(data)+1))
+	binary.BigEndian.PutUint16(response[10:], 0)
+	response = response[:len(response)-11]
+	response = append(response, 0xc0, 0x0c, 0, 5, 0, 1, 0, 0, 0, 60, 0, 2, 0xc0, 0x0c)
+	for _, rdata := range data {
+		response = append(response, 0xc0, 0x0c, 0, dnsTypeTLSA, 0, 1, 0, 0, 0, 60, 0, byte(len(rdata)))
+		response = append(response, rdata...)
+	}
+	return response
+}
+
+func TestQueryTLSA(t *testing.T) {
+	const secure = dnsFlagsResponse | dnsFlagsAuthenticData | 0x0100
+	rdata := []byte{TLSAUsageDANEEE, TLSASelectorSPKI, TLSAMatchingSHA256, 0xab, 0xcd}
+	tests := []struct {
+		name    string
+		respond func(query []byte, network string) []byte
+		want    int
+		wantErr bool
+	}{
+		{"secure answer", func(query []byte, _ string) []byte {
+			return testDNSResponse(query, secure, rdata, rdata)
+		}, 2, false},
+		{"insecure answer", func(query []byte, _ string) []byte {
+			return testDNSResponse(query, dnsFlagsResponse, rdata)
+		}, 0, false},
+		{"non-existent name", func(query []byte, _ string) []byte {
+			return testDNSResponse(query, secure|3)
+		}, 0, false},
+		{"server failure", func(query []byte, _ string) []byte {
+			return testDNSResponse(query, secure|2)
+		}, 0, true},
+		{"truncated answer is repeated over TCP", func(query []byte, network string) []byte {
+			if network == "udp" {
+				return testDNSResponse(query, secure|dnsFlagsTruncated)
+			}
+			return testDNSResponse(query, secure, rdata)
+		}, 1, false},
+		{"mismatching ID", func(query []byte, _ string) []byte {
+			response := testDNSResponse(query, secure, rdata)
+			response[0]++
+			return response
+		}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testDNSServer(t, tt.respond)
+			records, err := queryTLSA(context.Background(), server, "_25._tcp.mx.example.com.")
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected query to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to query TLSA records: %s", err)
+			}
+			if len(records) != tt.want {
+				t.Fatalf("expected %d records, got: %v", tt.want, records)
+			}
+			if tt.want > 0 && (records[0].Usage != TLSAUsageDANEEE || records[0].Selector != TLSASelectorSPKI ||
+				records[0].MatchingType != TLSAMatchingSHA256 || !bytes.Equal(records[0].Data, []byte{0xab, 0xcd})) {
+				t.Errorf("unexpected record: %v", records[0])
+			}
+		})
+	}
+}
+
+func TestTLSAQuery(t *testing.T) {
+	query, err := tlsaQuery(0x1234, "_25._tcp.mx.example.com.")
+	if err != nil {
+		t.Fatalf("failed to build query: %s", err)
+	}
+	want := "\x124\x01\x20\x00\x01\x00\x00\x00\x00\x00\x01\x03_25\x04_tcp\x02mx\x07example\x03com\x00\x00\x34\x00\x01" +
+		"\x00\x00\x29\x04\xd0\x00\x00\x80\x00\x00\x00"
+	if string(query) != want {
+		t.Errorf("unexpected query, want: %q, got: %q", want, query)
+	}
+	for _, name := range []string{"", "mx..example.com", strings.Repeat("a", 64) + ".example.com"} {
+		if _, err = tlsaQuery(1, name); err == nil {
+			t.Errorf("expected invalid name %q to fail", name)
+		}
+	}
+}
+
+func TestResolvConfNameservers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resolv.conf")
+	content := "# comment\nsearch example.com\nnameserver 192.0.2.1\nnameserver 2001:db8::1\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write resolver configuration: %s", err)
+	}
+	servers := resolvConfNameservers(path)
+	if len(servers) != 2 || servers[0] != "192.0.2.1:53" || servers[1] != "[2001:db8::1]:53" {
+		t.Errorf("unexpected nameservers: %v", servers)
+	}
+	servers = resolvConfNameservers(filepath.Join(t.TempDir(), "missing"))
+	if len(servers) != 1 || servers[0] != "127.0.0.1:53" {
+		t.Errorf("expected default nameserver, got: %v", servers)
+	}
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"mime"
+	"net/mail"
+	"time"
+)
+
+type (
+	// Document is a flattened representation of a Msg, as returned by Msg.ToDocument.
+	//
+	// It holds the fields that are commonly indexed by search engines like Elasticsearch or Bleve,
+	// with decoded header values and the plain text of the body, so that sent or parsed mails can be
+	// indexed without walking the MIME structure of the Msg.
+	Document struct {
+		// MessageID is the value of the "Message-ID" header, including the angle brackets.
+		MessageID string `json:"message_id,omitempty"`
+
+		// ThreadID is the Message-ID of the first message of the thread the Msg belongs to. It is
+		// taken from the "References" or "In-Reply-To" header and equals MessageID, if the Msg does
+		// not reply to another message.
+		ThreadID string `json:"thread_id,omitempty"`
+
+		// Subject is the decoded value of the "Subject" header.
+		Subject string `json:"subject,omitempty"`
+
+		// From is the sender address, i. e. "Toni Tester <toni@example.com>".
+		From string `json:"from,omitempty"`
+
+		// To holds the addresses of the "To" recipients.
+		To []string `json:"to,omitempty"`
+
+		// Cc holds the addresses of the "Cc" recipients.
+		Cc []string `json:"cc,omitempty"`
+
+		// Date is the value of the "Date" header. It is the zero time if the header is missing or
+		// cannot be parsed.
+		Date time.Time `json:"date"`
+
+		// Body is the plain text content of the Msg, as returned by Msg.PlainText.
+		Body string `json:"body,omitempty"`
+
+		// Attachments holds the name and the content type of each attachment of the Msg.
+		Attachments []DocumentAttachment `json:"attachments,omitempty"`
+	}
+
+	// DocumentAttachment represents an attachment of a Document.
+	DocumentAttachment struct {
+		// Name is the file name of the attachment.
+		Name string `json:"name"`
+
+		// ContentType is the media type of the attachment, without any parameters.
+		ContentType string `json:"content_type"`
+	}
+)
+
+// ToDocument returns the flattened Document representation of the Msg for search indexing.
+//
+// Header values are decoded, the addresses are formatted without RFC 2047 encoding and the body is
+// converted into plain text with Msg.PlainText, so that a Msg without a "text/plain" part is still
+// indexed with the text of its "text/html" part. A Msg without any text part results in an empty
+// body. Embedded files are not listed as attachments.
+//
+// Returns:
+//   - The Document of the Msg.
+//   - An error if the content of the text part cannot be read; otherwise, returns nil.
+func (m *Msg) ToDocument() (Document, error) {
+	document := Document{
+		MessageID: normalizeMessageID(m.GetMessageID()),
+		Subject:   firstHeaderValue(m.GetGenHeader(HeaderSubject)),
+	}
+	if decoded, err := (&mime.WordDecoder{}).DecodeHeader(document.Subject); err == nil {
+		document.Subject = decoded
+	}
+	document.ThreadID = document.MessageID
+	references := messageIDList(m.GetGenHeader(HeaderReferences))
+	if len(references) == 0 {
+		references = messageIDList(m.GetGenHeader(HeaderInReplyTo))
+	}
+	if len(references) > 0 {
+		document.ThreadID = references[0]
+	}
+
+	if from := m.GetAddrHeader(HeaderFrom); len(from) > 0 {
+		document.From = documentAddress(from[0])
+	}
+	for _, address := range m.GetAddrHeader(HeaderTo) {
+		document.To = append(document.To, documentAddress(address))
+	}
+	for _, address := range m.GetAddrHeader(HeaderCc) {
+		document.Cc = append(document.Cc, documentAddress(address))
+	}
+	if date, err := mail.ParseDate(firstHeaderValue(m.GetGenHeader(HeaderDate))); err == nil {
+		document.Date = date
+	}
+
+	body, err := m.PlainText()
+	if err != nil && !errors.Is(err, ErrNoTextPart) {
+		return document, err
+	}
+	document.Body = body
+	for _, attachment := range m.GetAttachments() {
+		document.Attachments = append(document.Attachments, DocumentAttachment{
+			Name:        attachment.Name,
+			ContentType: policyFileContentType(attachment),
+		})
+	}
+	return document, nil
+}
+
+// documentAddress formats the given address as "Name <address>", or as the plain address if it has
+// no display name.
+func documentAddress(address *mail.Address) string {
+	if address.Name == "" {
+		return address.Address
+	}
+	return address.Name + " <" + address.Address + ">"
+}
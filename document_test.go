@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMsg_ToDocument(t *testing.T) {
+	t.Run("ToDocument flattens the message", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.FromFormat("Toni Tester", "valid-from@domain.tld"); err != nil {
+			t.Fatalf("failed to set from address: %s", err)
+		}
+		if err := message.Cc("cc@domain.tld"); err != nil {
+			t.Fatalf("failed to set cc address: %s", err)
+		}
+		message.Subject("Grüße")
+		message.SetMessageIDWithValue("reply@domain.tld")
+		message.SetGenHeader(HeaderReferences, "<root@domain.tld> <parent@domain.tld>")
+		date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		message.SetDateWithValue(date)
+		message.SetBodyString(TypeTextHTML, "<p>Hello <b>World</b></p>")
+		if err := message.AttachReader("report.pdf", strings.NewReader("report")); err != nil {
+			t.Fatalf("failed to attach file: %s", err)
+		}
+		if err := message.EmbedReader("logo.png", strings.NewReader("logo")); err != nil {
+			t.Fatalf("failed to embed file: %s", err)
+		}
+
+		document, err := message.ToDocument()
+		if err != nil {
+			t.Fatalf("failed to convert message to document: %s", err)
+		}
+		want := Document{
+			MessageID:   "<reply@domain.tld>",
+			ThreadID:    "<root@domain.tld>",
+			Subject:     "Grüße",
+			From:        "Toni Tester <valid-from@domain.tld>",
+			To:          []string{"valid-to@domain.tld"},
+			Cc:          []string{"cc@domain.tld"},
+			Body:        "Hello World",
+			Attachments: []DocumentAttachment{{Name: "report.pdf", ContentType: "application/pdf"}},
+		}
+		if !document.Date.Equal(date) {
+			t.Errorf("expected date %s, got: %s", date, document.Date)
+		}
+		document.Date = time.Time{}
+		if !reflect.DeepEqual(document, want) {
+			t.Errorf("unexpected document, want: %+v, got: %+v", want, document)
+		}
+	})
+	t.Run("ToDocument without references and text part", func(t *testing.T) {
+		message := testMessage(t)
+		message.parts = nil
+		message.SetMessageIDWithValue("single@domain.tld")
+		document, err := message.ToDocument()
+		if err != nil {
+			t.Fatalf("failed to convert message to document: %s", err)
+		}
+		if document.ThreadID != "<single@domain.tld>" {
+			t.Errorf("expected thread ID to be the message ID, got: %s", document.ThreadID)
+		}
+		if document.Body != "" || !document.Date.IsZero() {
+			t.Errorf("expected empty body and date, got: %q, %s", document.Body, document.Date)
+		}
+	})
+	t.Run("ToDocument fails on unreadable text part", func(t *testing.T) {
+		message := testMessage(t)
+		message.SetBodyWriter(TypeTextPlain, func(w io.Writer) (int64, error) {
+			return 0, errors.New("broken")
+		})
+		if _, err := message.ToDocument(); err == nil {
+			t.Error("expected converting a message with an unreadable text part to fail")
+		}
+	})
+}
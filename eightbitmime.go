@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"net/textproto"
+	"strings"
+)
+
+// ErrInvalid8BitMIMEFallback is returned if the fallback encoding for servers without 8BITMIME is
+// neither quoted-printable nor Base64.
+var ErrInvalid8BitMIMEFallback = errors.New("8BITMIME fallback encoding must be quoted-printable or " +
+	"base64")
+
+// With8BitMIMEFallback enables the automatic re-encoding of 8bit messages for SMTP servers that do
+// not advertise the 8BITMIME extension.
+//
+// By default, the Client refuses to send a Msg with parts or attachments in NoEncoding to such a
+// server and returns a SendError of reason ErrNoUnencoded. With this option, the affected parts and
+// attachments are written in the given encoding instead, so that the server receives conformant 7bit
+// data. The Msg itself is not modified, so it is still sent unencoded to servers that support 8BITMIME.
+// Attached messages of type message/rfc822 are never re-encoded, since RFC 2046 does not permit it.
+//
+// Parameters:
+//   - encoding: The Encoding used for the re-encoded parts. Must be EncodingQP or EncodingB64.
+//
+// Returns:
+//   - An Option function that sets the fallback encoding for the Client.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc6152
+//   - https://datatracker.ietf.org/doc/html/rfc2046#section-5.2.1
+func With8BitMIMEFallback(encoding Encoding) Option {
+	return func(c *Client) error {
+		if encoding != EncodingQP && encoding != EncodingB64 {
+			return ErrInvalid8BitMIMEFallback
+		}
+		c.eightBitFallback = encoding
+		return nil
+	}
+}
+
+// usesEncoding returns true if the Msg, any of its parts or any of its attachments and embeds is
+// configured for the given Encoding.
+func (m *Msg) usesEncoding(encoding Encoding) bool {
+	if m.encoding == encoding {
+		return true
+	}
+	for _, part := range m.parts {
+		if !part.isDeleted && part.encoding == encoding {
+			return true
+		}
+	}
+	for _, files := range [][]*File{m.attachments, m.embeds} {
+		for _, file := range files {
+			if isReencodableFile(file, encoding) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reencoded returns a copy of the Msg in which all parts, attachments and embeds in the Encoding
+// from use the Encoding to instead. The original Msg remains unchanged.
+//
+// Parameters:
+//   - from: The Encoding of the parts and files that are re-encoded.
+//   - to: The Encoding used for the re-encoded parts and files.
+//
+// Returns:
+//   - A pointer to the re-encoded copy of the Msg.
+func (m *Msg) reencoded(from, to Encoding) *Msg {
+	reencoded := *m
+	if m.encoding == from {
+		reencoded.encoding = to
+	}
+	if m.parts != nil {
+		reencoded.parts = make([]*Part, len(m.parts))
+		for i, part := range m.parts {
+			reencoded.parts[i] = part
+			if part.encoding == from {
+				partCopy := *part
+				partCopy.encoding = to
+				reencoded.parts[i] = &partCopy
+			}
+		}
+	}
+	reencoded.attachments = reencodedFiles(m.attachments, from, to)
+	reencoded.embeds = reencodedFiles(m.embeds, from, to)
+	return &reencoded
+}
+
+// reencodedFiles returns a copy of the given list of files, in which the files in the Encoding from
+// are replaced by copies that use the Encoding to.
+func reencodedFiles(files []*File, from, to Encoding) []*File {
+	if files == nil {
+		return nil
+	}
+	reencoded := make([]*File, len(files))
+	for i, file := range files {
+		reencoded[i] = file
+		if !isReencodableFile(file, from) {
+			continue
+		}
+		fileCopy := *file
+		fileCopy.Enc = to
+		fileCopy.Header = make(textproto.MIMEHeader, len(file.Header))
+		for key, values := range file.Header {
+			fileCopy.Header[key] = append([]string(nil), values...)
+		}
+		fileCopy.Header.Del(string(HeaderContentTransferEnc))
+		reencoded[i] = &fileCopy
+	}
+	return reencoded
+}
+
+// isReencodableFile returns true if the given File is transferred in the given Encoding and is not
+// an attached message of type message/rfc822.
+func isReencodableFile(file *File, encoding Encoding) bool {
+	if file.ContentType == TypeMessageRFC822 {
+		return false
+	}
+	value, _ := file.getHeader(HeaderContentTransferEnc)
+	return file.Enc == encoding || strings.EqualFold(value, encoding.String())
+}
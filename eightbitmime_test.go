@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWith8BitMIMEFallback(t *testing.T) {
+	t.Run("invalid encoding fails", func(t *testing.T) {
+		for _, encoding := range []Encoding{NoEncoding, EncodingUSASCII, ""} {
+			if _, err := NewClient(DefaultHost, With8BitMIMEFallback(encoding)); !errors.Is(err,
+				ErrInvalid8BitMIMEFallback) {
+				t.Errorf("expected error: %s, got: %s", ErrInvalid8BitMIMEFallback, err)
+			}
+		}
+	})
+	tests := []struct {
+		name       string
+		featureSet string
+		opts       []Option
+		wantErr    bool
+	}{
+		{"server supports 8BITMIME", "250-8BITMIME\r\n250 DSN", nil, false},
+		{"server without 8BITMIME fails", "250 DSN", nil, true},
+		{"server without 8BITMIME with fallback", "250 DSN", []Option{With8BitMIMEFallback(EncodingQP)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			PortAdder.Add(1)
+			serverPort := int(TestServerPortBase + PortAdder.Load())
+			go func() {
+				if err := simpleSMTPServer(ctx, t, &serverProps{
+					FeatureSet: tt.featureSet,
+					ListenPort: serverPort,
+				}); err != nil {
+					t.Errorf("failed to start test server: %s", err)
+					return
+				}
+			}()
+			time.Sleep(time.Millisecond * 30)
+			ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+			t.Cleanup(cancelDial)
+
+			client, err := NewClient(DefaultHost, append([]Option{WithPort(serverPort), WithTLSPolicy(NoTLS)},
+				tt.opts...)...)
+			if err != nil {
+				t.Fatalf("failed to create new client: %s", err)
+			}
+			if err = client.DialWithContext(ctxDial); err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					t.Skip("failed to connect to the test server due to timeout")
+				}
+				t.Fatalf("failed to connect to the test server: %s", err)
+			}
+			t.Cleanup(func() {
+				if err := client.Close(); err != nil {
+					t.Errorf("failed to close client: %s", err)
+				}
+			})
+			message := testMessage(t, WithEncoding(NoEncoding))
+			err = client.Send(message)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("failed to send message: %s", err)
+				}
+				if message.Encoding() != NoEncoding.String() {
+					t.Errorf("expected message encoding to remain unchanged, got: %s", message.Encoding())
+				}
+				return
+			}
+			var sendErr *SendError
+			if !errors.As(err, &sendErr) || sendErr.Reason != ErrNoUnencoded {
+				t.Errorf("expected SendError with reason %s, got: %s", ErrNoUnencoded, err)
+			}
+		})
+	}
+}
+
+func TestMsg_usesEncoding(t *testing.T) {
+	t.Run("message in quoted-printable", func(t *testing.T) {
+		if testMessage(t).usesEncoding(NoEncoding) {
+			t.Error("expected message not to have 8bit content")
+		}
+	})
+	t.Run("part in 8bit", func(t *testing.T) {
+		message := testMessage(t)
+		message.AddAlternativeString(TypeTextHTML, "<p>Testmail</p>", WithPartEncoding(NoEncoding))
+		if !message.usesEncoding(NoEncoding) {
+			t.Error("expected message to have 8bit content")
+		}
+	})
+	t.Run("attachment in 8bit", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.AttachReader("test.txt", strings.NewReader("Testmail"),
+			WithFileEncoding(NoEncoding)); err != nil {
+			t.Fatalf("failed to attach file: %s", err)
+		}
+		if !message.usesEncoding(NoEncoding) {
+			t.Error("expected message to have 8bit content")
+		}
+	})
+	t.Run("attached message in 8bit", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.AttachReader("forwarded.eml", strings.NewReader("Subject: Test\r\n\r\nTestmail"),
+			WithFileEncoding(NoEncoding), WithFileContentType(TypeMessageRFC822)); err != nil {
+			t.Fatalf("failed to attach file: %s", err)
+		}
+		if message.usesEncoding(NoEncoding) {
+			t.Error("expected attached message/rfc822 not to count as 8bit content")
+		}
+	})
+}
+
+func TestMsg_reencoded(t *testing.T) {
+	message := testMessage(t, WithEncoding(NoEncoding))
+	message.SetBodyString(TypeTextPlain, "Grüße aus Köln")
+	if err := message.AttachReader("test.txt", strings.NewReader("Größe"), WithFileEncoding(NoEncoding)); err != nil {
+		t.Fatalf("failed to attach file: %s", err)
+	}
+	buffer := bytes.NewBuffer(nil)
+	if _, err := message.reencoded(NoEncoding, EncodingQP).WriteTo(buffer); err != nil {
+		t.Fatalf("failed to write re-encoded message: %s", err)
+	}
+	output := buffer.String()
+	if strings.Contains(output, "8bit") || strings.Count(output, "quoted-printable") != 2 {
+		t.Errorf("expected all parts to be quoted-printable, got: %s", output)
+	}
+	if !strings.Contains(output, "Gr=C3=BC=C3=9Fe aus K=C3=B6ln") || !strings.Contains(output, "Gr=C3=B6=C3=9Fe") {
+		t.Errorf("expected quoted-printable body and attachment, got: %s", output)
+	}
+	for _, r := range output {
+		if r > 127 {
+			t.Fatalf("expected 7bit output, got: %s", output)
+		}
+	}
+
+	buffer.Reset()
+	if _, err := message.WriteTo(buffer); err != nil {
+		t.Fatalf("failed to write message: %s", err)
+	}
+	if !strings.Contains(buffer.String(), "Grüße aus Köln") || strings.Count(buffer.String(), "8bit") != 2 {
+		t.Errorf("expected original message to remain 8bit, got: %s", buffer.String())
+	}
+}
+
+func TestMsg_reencoded_binary(t *testing.T) {
+	message := testMessage(t)
+	if err := message.AttachReader("data.bin", bytes.NewReader([]byte{0x00, 0xff}),
+		WithFileEncoding(EncodingBinary)); err != nil {
+		t.Fatalf("failed to attach file: %s", err)
+	}
+	buffer := bytes.NewBuffer(nil)
+	if _, err := message.WriteTo(buffer); err != nil {
+		t.Fatalf("failed to write message: %s", err)
+	}
+	if !strings.Contains(buffer.String(), "Content-Transfer-Encoding: binary\r\n") ||
+		!strings.Contains(buffer.String(), "\r\n\r\n\x00\xff\r\n") {
+		t.Errorf("expected binary attachment to be written unencoded, got: %q", buffer.String())
+	}
+	buffer.Reset()
+	if _, err := message.reencoded(EncodingBinary, EncodingB64).WriteTo(buffer); err != nil {
+		t.Fatalf("failed to write re-encoded message: %s", err)
+	}
+	if !strings.Contains(buffer.String(), "Content-Transfer-Encoding: base64\r\n") ||
+		!strings.Contains(buffer.String(), "\r\n\r\nAP8=\r\n") {
+		t.Errorf("expected binary attachment to be re-encoded to Base64, got: %q", buffer.String())
+	}
+}
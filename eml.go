@@ -15,6 +15,7 @@ import (
 	"mime/quotedprintable"
 	netmail "net/mail"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -26,13 +27,14 @@ import (
 //
 // Parameters:
 //   - emlString: A string containing the EML formatted message.
+//   - opts: Optional EMLOption values to configure the parser.
 //
 // Returns:
 //   - A pointer to the Msg object populated with the parsed data, and an error if parsing
 //     fails.
-func EMLToMsgFromString(emlString string) (*Msg, error) {
+func EMLToMsgFromString(emlString string, opts ...EMLOption) (*Msg, error) {
 	eb := bytes.NewBufferString(emlString)
-	return EMLToMsgFromReader(eb)
+	return EMLToMsgFromReader(eb, opts...)
 }
 
 // EMLToMsgFromReader parses a reader that holds EML content and returns a pre-filled Msg pointer.
@@ -43,24 +45,28 @@ func EMLToMsgFromString(emlString string) (*Msg, error) {
 //
 // Parameters:
 //   - reader: An io.Reader containing the EML formatted message.
+//   - opts: Optional EMLOption values to configure the parser.
 //
 // Returns:
 //   - A pointer to the Msg object populated with the parsed data, and an error if parsing
 //     fails.
-func EMLToMsgFromReader(reader io.Reader) (*Msg, error) {
+func EMLToMsgFromReader(reader io.Reader, opts ...EMLOption) (*Msg, error) {
+	options := newEMLOptions(opts...)
 	msg := &Msg{
-		addrHeader:    make(map[AddrHeader][]*netmail.Address),
-		genHeader:     make(map[Header][]string),
-		preformHeader: make(map[Header]string),
-		mimever:       MIME10,
+		addrHeader:     make(map[AddrHeader][]*netmail.Address),
+		emlDiagnostics: &EMLDiagnostics{},
+		genHeader:      make(map[Header][]string),
+		preformHeader:  make(map[Header]string),
+		mimever:        MIME10,
 	}
+	options.diagnostics = msg.emlDiagnostics
 
-	parsedMsg, bodybuf, err := readEMLFromReader(reader)
+	parsedMsg, bodybuf, err := readEMLFromReader(reader, options)
 	if err != nil || parsedMsg == nil {
 		return msg, fmt.Errorf("failed to parse EML from reader: %w", err)
 	}
 
-	if err = parseEML(parsedMsg, bodybuf, msg); err != nil {
+	if err = parseEML(parsedMsg, bodybuf, msg, options); err != nil {
 		return msg, fmt.Errorf("failed to parse EML contents: %w", err)
 	}
 
@@ -76,24 +82,28 @@ func EMLToMsgFromReader(reader io.Reader) (*Msg, error) {
 //
 // Parameters:
 //   - filePath: The path to the .eml file to be parsed.
+//   - opts: Optional EMLOption values to configure the parser.
 //
 // Returns:
 //   - A pointer to the Msg object populated with the parsed data, and an error if parsing
 //     fails.
-func EMLToMsgFromFile(filePath string) (*Msg, error) {
+func EMLToMsgFromFile(filePath string, opts ...EMLOption) (*Msg, error) {
+	options := newEMLOptions(opts...)
 	msg := &Msg{
-		addrHeader:    make(map[AddrHeader][]*netmail.Address),
-		genHeader:     make(map[Header][]string),
-		preformHeader: make(map[Header]string),
-		mimever:       MIME10,
+		addrHeader:     make(map[AddrHeader][]*netmail.Address),
+		emlDiagnostics: &EMLDiagnostics{},
+		genHeader:      make(map[Header][]string),
+		preformHeader:  make(map[Header]string),
+		mimever:        MIME10,
 	}
+	options.diagnostics = msg.emlDiagnostics
 
-	parsedMsg, bodybuf, err := readEML(filePath)
+	parsedMsg, bodybuf, err := readEML(filePath, options)
 	if err != nil || parsedMsg == nil {
 		return msg, fmt.Errorf("failed to parse EML file: %w", err)
 	}
 
-	if err = parseEML(parsedMsg, bodybuf, msg); err != nil {
+	if err = parseEML(parsedMsg, bodybuf, msg, options); err != nil {
 		return msg, fmt.Errorf("failed to parse EML contents: %w", err)
 	}
 
@@ -110,14 +120,15 @@ func EMLToMsgFromFile(filePath string) (*Msg, error) {
 //   - parsedMsg: A pointer to the netmail.Message containing the parsed EML data.
 //   - bodybuf: A bytes.Buffer containing the body content of the EML message.
 //   - msg: A pointer to the Msg object to be populated with the parsed data.
+//   - options: The emlOptions that configure the parser.
 //
 // Returns:
 //   - An error if any issues occur during the parsing process; otherwise, returns nil.
-func parseEML(parsedMsg *netmail.Message, bodybuf *bytes.Buffer, msg *Msg) error {
+func parseEML(parsedMsg *netmail.Message, bodybuf *bytes.Buffer, msg *Msg, options *emlOptions) error {
 	if err := parseEMLHeaders(&parsedMsg.Header, msg); err != nil {
 		return fmt.Errorf("failed to parse EML headers: %w", err)
 	}
-	if err := parseEMLBodyParts(parsedMsg, bodybuf, msg); err != nil {
+	if err := parseEMLBodyParts(parsedMsg, bodybuf, msg, options); err != nil {
 		return fmt.Errorf("failed to parse EML body parts: %w", err)
 	}
 	return nil
@@ -131,11 +142,12 @@ func parseEML(parsedMsg *netmail.Message, bodybuf *bytes.Buffer, msg *Msg) error
 //
 // Parameters:
 //   - filePath: The path to the EML file to be opened and parsed.
+//   - options: The emlOptions that configure the parser.
 //
 // Returns:
 //   - A pointer to the parsed netmail.Message, a bytes.Buffer containing the body, and an
 //     error if any issues occur during file operations or parsing.
-func readEML(filePath string) (*netmail.Message, *bytes.Buffer, error) {
+func readEML(filePath string, options *emlOptions) (*netmail.Message, *bytes.Buffer, error) {
 	fileHandle, err := os.Open(filePath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to open EML file: %w", err)
@@ -143,7 +155,7 @@ func readEML(filePath string) (*netmail.Message, *bytes.Buffer, error) {
 	defer func() {
 		_ = fileHandle.Close()
 	}()
-	return readEMLFromReader(fileHandle)
+	return readEMLFromReader(fileHandle, options)
 }
 
 // readEMLFromReader uses net/mail to parse the header and body from a given io.Reader.
@@ -155,15 +167,33 @@ func readEML(filePath string) (*netmail.Message, *bytes.Buffer, error) {
 //
 // Parameters:
 //   - reader: An io.Reader containing the EML formatted message.
+//   - options: The emlOptions that configure the parser.
 //
 // Returns:
 //   - A pointer to the parsed netmail.Message, a bytes.Buffer containing the body, and an
 //     error if any issues occur during parsing.
-func readEMLFromReader(reader io.Reader) (*netmail.Message, *bytes.Buffer, error) {
+func readEMLFromReader(reader io.Reader, options *emlOptions) (*netmail.Message, *bytes.Buffer, error) {
+	if options.limits.hasHeaderLimits() {
+		limited, err := limitEMLHeader(reader, options.limits)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read EML: %w", err)
+		}
+		reader = limited
+	}
+	if options.lenient {
+		normalized, err := normalizeEMLHeaderLineEndings(reader, options.diagnostics)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read EML: %w", err)
+		}
+		reader = normalized
+	}
 	parsedMsg, err := netmail.ReadMessage(reader)
 	if err != nil {
 		return parsedMsg, nil, fmt.Errorf("failed to parse EML: %w", err)
 	}
+	if options.lenient {
+		repairEMLHeader(parsedMsg.Header, options.diagnostics)
+	}
 
 	buf := bytes.Buffer{}
 	if _, err = buf.ReadFrom(parsedMsg.Body); err != nil {
@@ -187,12 +217,15 @@ func readEMLFromReader(reader io.Reader) (*netmail.Message, *bytes.Buffer, error
 //   - An error if parsing the headers fails; otherwise, returns nil.
 func parseEMLHeaders(mailHeader *netmail.Header, msg *Msg) error {
 	commonHeaders := []Header{
-		HeaderContentType, HeaderImportance, HeaderInReplyTo, HeaderListUnsubscribe,
+		HeaderImportance, HeaderInReplyTo, HeaderListArchive, HeaderListHelp, HeaderListID,
+		HeaderListOwner, HeaderListPost, HeaderListSubscribe, HeaderListUnsubscribe,
 		HeaderListUnsubscribePost, HeaderMessageID, HeaderMIMEVersion, HeaderOrganization,
 		HeaderPrecedence, HeaderPriority, HeaderReferences, HeaderSubject, HeaderUserAgent,
-		HeaderXMailer, HeaderXMSMailPriority, HeaderXPriority,
+		HeaderXMailer, HeaderXMailerAudit, HeaderXMSMailPriority, HeaderXPriority,
 	}
 
+	msg.emlDiagnostics.diagnoseEMLHeaders(mailHeader)
+
 	// Extract content type, charset and encoding first
 	parseEMLEncoding(mailHeader, msg)
 	parseEMLContentTypeCharset(mailHeader, msg)
@@ -210,17 +243,11 @@ func parseEMLHeaders(mailHeader *netmail.Header, msg *Msg) error {
 	}
 	for addrHeader, addrFunc := range addrHeaders {
 		if v := mailHeader.Get(addrHeader.String()); v != "" {
-			var addrStrings []string
-			parsedAddrs, err := netmail.ParseAddressList(v)
-			if err != nil {
+			// The address list is split into its addresses and address groups, so that the groups
+			// are preserved
+			if err := addrFunc(splitAddressList(v)...); err != nil {
 				return fmt.Errorf(`failed to parse address list: %w`, err)
 			}
-			for _, addr := range parsedAddrs {
-				addrStrings = append(addrStrings, addr.String())
-			}
-			// We can skip the error checking here since netmail.ParseAddressList already performed the
-			// same address checking that the msg methods do.
-			_ = addrFunc(addrStrings...)
 		}
 	}
 
@@ -241,10 +268,6 @@ func parseEMLHeaders(mailHeader *netmail.Header, msg *Msg) error {
 	// Extract common headers
 	for _, header := range commonHeaders {
 		if value := mailHeader.Get(header.String()); value != "" {
-			if strings.EqualFold(header.String(), HeaderContentType.String()) &&
-				strings.HasPrefix(value, TypeMultipartMixed.String()) {
-				continue
-			}
 			msg.SetGenHeader(header, value)
 		}
 	}
@@ -262,10 +285,11 @@ func parseEMLHeaders(mailHeader *netmail.Header, msg *Msg) error {
 //   - parsedMsg: A pointer to the netmail.Message containing the parsed EML data.
 //   - bodybuf: A bytes.Buffer containing the body content of the EML message.
 //   - msg: A pointer to the Msg object to be populated with the parsed body content.
+//   - options: The emlOptions that configure the parser.
 //
 // Returns:
 //   - An error if any issues occur during the body parsing process; otherwise, returns nil.
-func parseEMLBodyParts(parsedMsg *netmail.Message, bodybuf *bytes.Buffer, msg *Msg) error {
+func parseEMLBodyParts(parsedMsg *netmail.Message, bodybuf *bytes.Buffer, msg *Msg, options *emlOptions) error {
 	// Extract the transfer encoding of the body
 	mediatype, params, err := mime.ParseMediaType(parsedMsg.Header.Get(HeaderContentType.String()))
 	if err != nil {
@@ -280,19 +304,26 @@ func parseEMLBodyParts(parsedMsg *netmail.Message, bodybuf *bytes.Buffer, msg *M
 		}
 	}
 	if value, ok := params["charset"]; ok {
-		msg.SetCharset(Charset(value))
+		msg.SetCharset(ParseCharset(value))
 	}
 
 	switch {
 	case strings.EqualFold(mediatype, TypeTextPlain.String()),
 		strings.EqualFold(mediatype, TypeTextHTML.String()):
+		if options.lenient {
+			bodybuf = bytes.NewBuffer(normalizeEMLTextLineEndings(bodybuf.Bytes(), mediatype, options.diagnostics))
+		}
 		if err = parseEMLBodyPlain(mediatype, parsedMsg, bodybuf, msg); err != nil {
 			return fmt.Errorf("failed to parse plain body: %w", err)
 		}
 	case strings.EqualFold(mediatype, TypeMultipartAlternative.String()),
 		strings.EqualFold(mediatype, TypeMultipartMixed.String()),
-		strings.EqualFold(mediatype, TypeMultipartRelated.String()):
-		if err = parseEMLMultipart(params, bodybuf, msg); err != nil {
+		strings.EqualFold(mediatype, TypeMultipartRelated.String()),
+		strings.EqualFold(mediatype, typeMultipartReport.String()):
+		if strings.EqualFold(mediatype, typeMultipartReport.String()) {
+			msg.reportType = params["report-type"]
+		}
+		if err = parseEMLMultipart(params, bodybuf, msg, options, 1); err != nil {
 			return fmt.Errorf("failed to parse multipart body: %w", err)
 		}
 	default:
@@ -355,113 +386,152 @@ func parseEMLBodyPlain(mediatype string, parsedMsg *netmail.Message, bodybuf *by
 // parseEMLMultipart parses a multipart body part of an EML message.
 //
 // This function handles the parsing of multipart messages, extracting the individual parts
-// and determining their content types. It processes each part according to its content type
-// and ensures that all relevant data is stored in the Msg object.
+// and determining their content types. Nested multipart/mixed, multipart/related and
+// multipart/alternative parts are parsed recursively, so that the parts, embeds and attachments
+// of the whole MIME tree are reconstructed in the Msg object.
 //
 // Parameters:
 //   - params: A map containing the parameters from the multipart content type.
 //   - bodybuf: A bytes.Buffer containing the body content of the EML message.
 //   - msg: A pointer to the Msg object to be populated with the parsed body parts.
+//   - options: The emlOptions that configure the parser.
+//   - depth: The nesting depth of the multipart body, starting with 1 for the body of the message.
 //
 // Returns:
 //   - An error if any issues occur during the parsing of the multipart body; otherwise,
 //     returns nil.
-func parseEMLMultipart(params map[string]string, bodybuf *bytes.Buffer, msg *Msg) error {
+func parseEMLMultipart(params map[string]string, bodybuf *bytes.Buffer, msg *Msg, options *emlOptions,
+	depth int,
+) error {
+	if err := options.checkEMLDepth(depth); err != nil {
+		return err
+	}
 	boundary, ok := params["boundary"]
 	if !ok {
 		return fmt.Errorf("no boundary tag found in multipart body")
 	}
+	msg.emlDiagnostics.diagnoseEMLBoundary(boundary)
 	multipartReader := multipart.NewReader(bodybuf, boundary)
-ReadNextPart:
-	multiPart, err := multipartReader.NextPart()
-	defer func() {
-		if multiPart != nil {
+	for {
+		multiPart, err := multipartReader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get next part of multipart message: %w", err)
+		}
+		if err = options.countEMLPart(); err != nil {
 			_ = multiPart.Close()
+			return err
 		}
-	}()
-	if err != nil && !errors.Is(err, io.EOF) {
-		return fmt.Errorf("failed to get next part of multipart message: %w", err)
-	}
-	for err == nil {
-		// Multipart/related and Multipart/alternative parts need to be parsed separately
-		if contentTypeSlice, ok := multiPart.Header[HeaderContentType.String()]; ok && len(contentTypeSlice) == 1 {
-			contentType, _ := parseMultiPartHeader(contentTypeSlice[0])
-			if strings.EqualFold(contentType, TypeMultipartRelated.String()) ||
-				strings.EqualFold(contentType, TypeMultipartAlternative.String()) {
-				relatedPart := &netmail.Message{
-					Header: netmail.Header(multiPart.Header),
-					Body:   multiPart,
-				}
-				relatedBuf := &bytes.Buffer{}
-				if _, err = relatedBuf.ReadFrom(multiPart); err != nil {
-					return fmt.Errorf("failed to read related multipart message to buffer: %w", err)
-				}
-				if err := parseEMLBodyParts(relatedPart, relatedBuf, msg); err != nil {
-					return fmt.Errorf("failed to parse related multipart body: %w", err)
-				}
-			}
+		err = parseEMLMultipartPart(multiPart, msg, options, depth)
+		_ = multiPart.Close()
+		if err != nil {
+			return err
 		}
+	}
+}
 
-		// Content-Disposition header means we have an attachment or embed
-		if contentDisposition, ok := multiPart.Header[HeaderContentDisposition.String()]; ok {
-			if err = parseEMLAttachmentEmbed(contentDisposition, multiPart, msg); err != nil {
-				return fmt.Errorf("failed to parse attachment/embed: %w", err)
+// parseEMLMultipartPart parses a single part of a multipart body of an EML message.
+//
+// Nested multipart parts are parsed recursively. Parts with a Content-Disposition header are
+// added as attachment or embed. Non-text parts without a Content-Disposition header are added as
+// embed if they carry a Content-ID header, and as attachment otherwise. All other parts are added
+// as body part to the Msg.
+//
+// Parameters:
+//   - multiPart: A pointer to the multipart.Part to be parsed.
+//   - msg: A pointer to the Msg object to be populated with the parsed part.
+//   - options: The emlOptions that configure the parser.
+//   - depth: The nesting depth of the multipart body that holds the part.
+//
+// Returns:
+//   - An error if any issues occur during the parsing of the part; otherwise, returns nil.
+func parseEMLMultipartPart(multiPart *multipart.Part, msg *Msg, options *emlOptions, depth int) error {
+	if options.lenient {
+		repairEMLHeader(multiPart.Header, options.diagnostics)
+	}
+	if value := multiPart.Header.Get(HeaderContentType.String()); value != "" {
+		mediatype, params, err := mime.ParseMediaType(value)
+		if err == nil && strings.HasPrefix(strings.ToLower(mediatype), "multipart/") {
+			nestedBuf := &bytes.Buffer{}
+			if _, err = nestedBuf.ReadFrom(multiPart); err != nil {
+				return fmt.Errorf("failed to read nested multipart message to buffer: %w", err)
+			}
+			if err = parseEMLMultipart(params, nestedBuf, msg, options, depth+1); err != nil {
+				return fmt.Errorf("failed to parse nested multipart body: %w", err)
 			}
-			goto ReadNextPart
+			return nil
 		}
+	}
 
-		multiPartData, mperr := io.ReadAll(multiPart)
-		if mperr != nil {
-			_ = multiPart.Close()
-			return fmt.Errorf("failed to read multipart: %w", err)
+	// Content-Disposition header means we have an attachment or embed
+	if contentDisposition, ok := multiPart.Header[HeaderContentDisposition.String()]; ok {
+		if err := parseEMLAttachmentEmbed(contentDisposition, multiPart, msg); err != nil {
+			return fmt.Errorf("failed to parse attachment/embed: %w", err)
 		}
+		return nil
+	}
 
-		multiPartContentType, ok := multiPart.Header[HeaderContentType.String()]
-		if !ok {
-			return fmt.Errorf("failed to get content-type from part")
-		}
-		contentType, optional := parseMultiPartHeader(multiPartContentType[0])
-		if strings.EqualFold(contentType, TypeMultipartRelated.String()) {
-			goto ReadNextPart
-		}
-		part := msg.newPart(ContentType(contentType))
-		if charset, ok := optional["charset"]; ok {
-			part.SetCharset(Charset(charset))
+	multiPartContentType, ok := multiPart.Header[HeaderContentType.String()]
+	if !ok {
+		return fmt.Errorf("failed to get content-type from part")
+	}
+	contentType, optional := parseMultiPartHeader(multiPartContentType[0])
+	if !strings.HasPrefix(strings.ToLower(contentType), "text/") {
+		disposition := "attachment"
+		if multiPart.Header.Get(HeaderContentID.String()) != "" {
+			disposition = "inline"
 		}
-
-		mutliPartTransferEnc, ok := multiPart.Header[HeaderContentTransferEnc.String()]
-		if !ok {
-			// If CTE is empty we can assume that it's a quoted-printable CTE since the
-			// GO stdlib multipart packages deletes that header
-			// See: https://cs.opensource.google/go/go/+/refs/tags/go1.22.0:src/mime/multipart/multipart.go;l=161
-			mutliPartTransferEnc = []string{EncodingQP.String()}
+		if err := parseEMLAttachmentEmbed([]string{disposition}, multiPart, msg); err != nil {
+			return fmt.Errorf("failed to parse attachment/embed: %w", err)
 		}
+		return nil
+	}
 
-		switch {
-		case strings.EqualFold(mutliPartTransferEnc[0], EncodingUSASCII.String()):
-			part.SetEncoding(EncodingUSASCII)
-			part.SetContent(string(multiPartData))
-		case strings.EqualFold(mutliPartTransferEnc[0], NoEncoding.String()):
-			part.SetEncoding(NoEncoding)
-			part.SetContent(string(multiPartData))
-		case strings.EqualFold(mutliPartTransferEnc[0], EncodingB64.String()):
-			part.SetEncoding(EncodingB64)
-			if err = handleEMLMultiPartBase64Encoding(multiPartData, part); err != nil {
-				return fmt.Errorf("failed to handle multipart base64 transfer-encoding: %w", err)
-			}
-		case strings.EqualFold(mutliPartTransferEnc[0], EncodingQP.String()):
-			part.SetEncoding(EncodingQP)
-			part.SetContent(string(multiPartData))
-		default:
-			return fmt.Errorf("unsupported Content-Transfer-Encoding: %s", mutliPartTransferEnc[0])
-		}
+	multiPartData, err := io.ReadAll(multiPart)
+	if err != nil {
+		return fmt.Errorf("failed to read multipart: %w", err)
+	}
+	if options.lenient {
+		multiPartData = normalizeEMLTextLineEndings(multiPartData, contentType, options.diagnostics)
+	}
+	part := msg.newPart(ContentType(contentType))
+	if charset, ok := optional["charset"]; ok {
+		part.SetCharset(ParseCharset(charset))
+	}
+	if description := multiPart.Header.Get(HeaderContentDescription.String()); description != "" {
+		part.SetDescription(description)
+	}
 
-		msg.parts = append(msg.parts, part)
-		multiPart, err = multipartReader.NextPart()
+	mutliPartTransferEnc, ok := multiPart.Header[HeaderContentTransferEnc.String()]
+	if !ok {
+		// If CTE is empty we can assume that it's a quoted-printable CTE since the
+		// GO stdlib multipart packages deletes that header
+		// See: https://cs.opensource.google/go/go/+/refs/tags/go1.22.0:src/mime/multipart/multipart.go;l=161
+		mutliPartTransferEnc = []string{EncodingQP.String()}
 	}
-	if !errors.Is(err, io.EOF) {
-		return fmt.Errorf("failed to read multipart: %w", err)
+
+	switch {
+	case strings.EqualFold(mutliPartTransferEnc[0], EncodingUSASCII.String()):
+		part.SetEncoding(EncodingUSASCII)
+		part.SetContent(string(multiPartData))
+	case strings.EqualFold(mutliPartTransferEnc[0], NoEncoding.String()):
+		part.SetEncoding(NoEncoding)
+		part.SetContent(string(multiPartData))
+	case strings.EqualFold(mutliPartTransferEnc[0], EncodingB64.String()):
+		part.SetEncoding(EncodingB64)
+		if err = handleEMLMultiPartBase64Encoding(multiPartData, part); err != nil {
+			return fmt.Errorf("failed to handle multipart base64 transfer-encoding: %w", err)
+		}
+	case strings.EqualFold(mutliPartTransferEnc[0], EncodingQP.String()):
+		part.SetEncoding(EncodingQP)
+		part.SetContent(string(multiPartData))
+	default:
+		return fmt.Errorf("unsupported Content-Transfer-Encoding: %s", mutliPartTransferEnc[0])
 	}
+
+	msg.parts = append(msg.parts, part)
 	return nil
 }
 
@@ -482,6 +552,10 @@ func parseEMLEncoding(mailHeader *netmail.Header, msg *Msg) {
 		case strings.EqualFold(value, EncodingB64.String()):
 			msg.SetEncoding(EncodingB64)
 		default:
+			if !strings.EqualFold(value, NoEncoding.String()) && !strings.EqualFold(value, EncodingUSASCII.String()) &&
+				!strings.EqualFold(value, "binary") {
+				msg.emlDiagnostics.add(EMLUnknownTransferEncoding, value)
+			}
 			msg.SetEncoding(NoEncoding)
 		}
 	}
@@ -489,23 +563,20 @@ func parseEMLEncoding(mailHeader *netmail.Header, msg *Msg) {
 
 // parseEMLContentTypeCharset parses and determines the charset and content type of the message.
 //
-// This function extracts the content type and charset from the EML headers, setting them
-// appropriately in the Msg object. It ensures that the Msg object is configured with the
-// correct content type for further processing.
+// This function extracts the charset from the Content-Type header of the EML and sets it in
+// the Msg object. The Content-Type header itself is not copied into the generic headers of the
+// Msg, since it is generated from the parts of the Msg when the Msg is written.
 //
 // Parameters:
 //   - mailHeader: A pointer to the netmail.Header containing the EML headers.
 //   - msg: A pointer to the Msg object to be updated with content type and charset information.
 func parseEMLContentTypeCharset(mailHeader *netmail.Header, msg *Msg) {
 	if value := mailHeader.Get(HeaderContentType.String()); value != "" {
-		contentType, optional := parseMultiPartHeader(value)
+		_, optional := parseMultiPartHeader(value)
 		if charset, ok := optional["charset"]; ok {
-			msg.SetCharset(Charset(charset))
+			msg.SetCharset(ParseCharset(charset))
 		}
 		msg.setEncoder()
-		if contentType != "" && !strings.EqualFold(contentType, TypeMultipartMixed.String()) {
-			msg.SetGenHeader(HeaderContentType, contentType)
-		}
 	}
 }
 
@@ -572,9 +643,29 @@ func parseMultiPartHeader(multiPartHeader string) (header string, optional map[s
 //     returns nil.
 func parseEMLAttachmentEmbed(contentDisposition []string, multiPart *multipart.Part, msg *Msg) error {
 	cdType, optional := parseMultiPartHeader(contentDisposition[0])
+	if _, params, err := mime.ParseMediaType(contentDisposition[0]); err == nil {
+		optional = params
+	}
 	filename := "generic.attachment"
-	if name, ok := optional["filename"]; ok {
-		filename = name[1 : len(name)-1]
+	if name, ok := optional["filename"]; ok && name != "" {
+		filename = strings.Trim(name, `"`)
+	}
+
+	var fileOpts []FileOption
+	if contentType, params, err := mime.ParseMediaType(multiPart.Header.Get(HeaderContentType.String())); err == nil {
+		fileOpts = append(fileOpts, WithFileContentType(ContentType(contentType)))
+		if name, ok := params["name"]; ok && name != "" && filename == "generic.attachment" {
+			filename = name
+		}
+	}
+	if description := multiPart.Header.Get(HeaderContentDescription.String()); description != "" {
+		fileOpts = append(fileOpts, WithFileDescription(description))
+	}
+	if size, err := strconv.ParseInt(optional["size"], 10, 64); err == nil && size > 0 {
+		fileOpts = append(fileOpts, WithFileSize(size))
+	}
+	if modDate, err := netmail.ParseDate(optional["modification-date"]); err == nil {
+		fileOpts = append(fileOpts, WithFileModDate(modDate))
 	}
 
 	var dataReader io.Reader
@@ -587,17 +678,14 @@ func parseEMLAttachmentEmbed(contentDisposition []string, multiPart *multipart.P
 
 	switch strings.ToLower(cdType) {
 	case "attachment":
-		if err := msg.AttachReader(filename, dataReader); err != nil {
+		if err := msg.AttachReader(filename, dataReader, fileOpts...); err != nil {
 			return fmt.Errorf("failed to attach multipart body: %w", err)
 		}
 	case "inline":
 		if contentID, _ := parseMultiPartHeader(multiPart.Header.Get(HeaderContentID.String())); contentID != "" {
-			if err := msg.EmbedReader(filename, dataReader, WithFileContentID(contentID)); err != nil {
-				return fmt.Errorf("failed to embed multipart body: %w", err)
-			}
-			return nil
+			fileOpts = append(fileOpts, WithFileContentID(contentID))
 		}
-		if err := msg.EmbedReader(filename, dataReader); err != nil {
+		if err := msg.EmbedReader(filename, dataReader, fileOpts...); err != nil {
 			return fmt.Errorf("failed to embed multipart body: %w", err)
 		}
 	default:
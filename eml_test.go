@@ -6,6 +6,7 @@ package mail
 
 import (
 	"bytes"
+	"io"
 	"strings"
 	"testing"
 )
@@ -889,6 +890,56 @@ Content-Disposition: broken; filename="test.png"
 iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVQIW2NgYGD4DwABBAEAwS2O
 UAAAAABJRU5ErkJggg==
 --abc123--`
+	exampleMailNestedMultipart = `Date: Wed, 01 Nov 2023 00:00:00 +0000
+MIME-Version: 1.0
+Message-ID: <1305604950.683004066175.AAAAAAAAaaaaaaaaB@go-mail.dev>
+Subject: Example mail with nested multipart parts
+From: "Toni Tester" <go-mail@go-mail.dev>
+To: <go-mail+test@go-mail.dev>
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: multipart/mixed; boundary="inner"
+
+--inner
+Content-Type: multipart/related; boundary="related"
+
+--related
+Content-Type: multipart/alternative; boundary="alternative"
+
+--alternative
+Content-Type: text/plain; charset=UTF-8
+Content-Transfer-Encoding: 7bit
+
+Hello
+--alternative
+Content-Type: text/html; charset=UTF-8
+Content-Transfer-Encoding: 7bit
+
+<p>Hello <img src="cid:pixel@go-mail.dev"></p>
+--alternative--
+--related
+Content-Type: image/png; name="pixel.png"
+Content-Transfer-Encoding: base64
+Content-ID: <pixel@go-mail.dev>
+
+iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVQIW2NgYGD4DwABBAEAwS2O
+UAAAAABJRU5ErkJggg==
+--related--
+--inner
+Content-Type: application/pdf; name=invoice.pdf
+Content-Description: The invoice
+Content-Transfer-Encoding: base64
+
+VGhpcyBpcyBhIHRlc3Q=
+--inner--
+--outer
+Content-Type: text/plain; charset=UTF-8
+Content-Disposition: attachment; filename=notes.txt
+Content-Transfer-Encoding: base64
+
+VGhpcyBpcyBhIHRlc3QgaW4gQmFzZTY0
+--outer--`
 )
 
 func TestEMLToMsgFromReader(t *testing.T) {
@@ -956,6 +1007,34 @@ func TestEMLToMsgFromReader(t *testing.T) {
 			t.Errorf("EML parsing with invalid EML string should fail")
 		}
 	})
+	t.Run("EMLToMsgFromReader reconstructs nested multipart trees", func(t *testing.T) {
+		parsed, err := EMLToMsgFromString(exampleMailNestedMultipart)
+		if err != nil {
+			t.Fatalf("failed to parse EML string: %s", err)
+		}
+		checkNestedMultipartMsg(t, parsed)
+		if _, ok := parsed.genHeader[HeaderContentType]; ok {
+			t.Error("expected Content-Type header not to be copied into the generic headers")
+		}
+	})
+	t.Run("EMLToMsgFromReader round-trips nested multipart trees", func(t *testing.T) {
+		parsed, err := EMLToMsgFromString(exampleMailNestedMultipart)
+		if err != nil {
+			t.Fatalf("failed to parse EML string: %s", err)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err = parsed.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write parsed message: %s", err)
+		}
+		if count := strings.Count(buffer.String(), "\r\nContent-Type: multipart/mixed;"); count != 1 {
+			t.Errorf("expected exactly one multipart/mixed Content-Type header, got: %d", count)
+		}
+		reparsed, err := EMLToMsgFromReader(buffer)
+		if err != nil {
+			t.Fatalf("failed to parse re-serialized message: %s", err)
+		}
+		checkNestedMultipartMsg(t, reparsed)
+	})
 	t.Run("EMLToMsgFromReader via EMLToMsgFromString on different examples", func(t *testing.T) {
 		tests := []struct {
 			name       string
@@ -1172,6 +1251,49 @@ func TestEMLToMsgFromFile(t *testing.T) {
 	})
 }
 
+// checkNestedMultipartMsg is a helper method that verifies that the given Msg holds the parts,
+// embeds and attachments of the exampleMailNestedMultipart EML
+func checkNestedMultipartMsg(t *testing.T, msg *Msg) {
+	t.Helper()
+	parts := msg.GetParts()
+	if len(parts) != 2 {
+		t.Fatalf("expected 2 parts, got: %d", len(parts))
+	}
+	if parts[0].GetContentType() != TypeTextPlain || parts[1].GetContentType() != TypeTextHTML {
+		t.Errorf("expected text/plain and text/html parts, got: %s and %s", parts[0].GetContentType(),
+			parts[1].GetContentType())
+	}
+	content, err := parts[0].GetContent()
+	if err != nil {
+		t.Fatalf("failed to get part content: %s", err)
+	}
+	if !strings.HasPrefix(string(content), "Hello") {
+		t.Errorf("expected plain text content to be %q, got: %q", "Hello", content)
+	}
+	embeds := msg.GetEmbeds()
+	if len(embeds) != 1 {
+		t.Fatalf("expected 1 embed, got: %d", len(embeds))
+	}
+	if embeds[0].Name != "pixel.png" || embeds[0].ContentType != "image/png" {
+		t.Errorf("unexpected embed: %s (%s)", embeds[0].Name, embeds[0].ContentType)
+	}
+	if contentID, _ := embeds[0].getHeader(HeaderContentID); contentID != "<pixel@go-mail.dev>" {
+		t.Errorf("expected embed Content-ID: %s, got: %s", "<pixel@go-mail.dev>", contentID)
+	}
+	attachments := msg.GetAttachments()
+	if len(attachments) != 2 {
+		t.Fatalf("expected 2 attachments, got: %d", len(attachments))
+	}
+	if attachments[0].Name != "invoice.pdf" || attachments[0].ContentType != "application/pdf" ||
+		attachments[0].Desc != "The invoice" {
+		t.Errorf("unexpected attachment: %s (%s): %s", attachments[0].Name, attachments[0].ContentType,
+			attachments[0].Desc)
+	}
+	if attachments[1].Name != "notes.txt" || attachments[1].ContentType != TypeTextPlain {
+		t.Errorf("unexpected attachment: %s (%s)", attachments[1].Name, attachments[1].ContentType)
+	}
+}
+
 /*
 func TestEMLToMsgFromString(t *testing.T) {
 	tests := []struct {
@@ -1551,3 +1673,23 @@ func stringToTempFile(data, name string) (string, string, error) {
 
 
 */
+
+func FuzzEMLToMsgFromString(f *testing.F) {
+	f.Add(exampleMailPlainNoEnc)
+	f.Add(exampleMailPlainB64)
+	f.Add(exampleMailPlainB64WithAttachment)
+	f.Add(exampleMailPlainB64WithEmbed)
+	f.Add(exampleMailMultipartMixedAlternativeRelated)
+	f.Add("From: a@b.c\r\nTo: Team: x@y.z;\r\n\r\nbody")
+	f.Fuzz(func(t *testing.T, data string) {
+		for _, opts := range [][]EMLOption{nil, {WithLenientParsing()}} {
+			message, err := EMLToMsgFromString(data, opts...)
+			if err != nil {
+				continue
+			}
+			if _, err = message.WriteTo(io.Discard); err != nil {
+				t.Logf("failed to write parsed message: %s", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"fmt"
+	netmail "net/mail"
+	"sort"
+	"strings"
+)
+
+// List of EMLDeviationKind values
+const (
+	// EMLMissingMIMEVersion indicates that the EML has no "MIME-Version" header.
+	EMLMissingMIMEVersion EMLDeviationKind = iota
+
+	// EMLMissingContentType indicates that the EML has no "Content-Type" header and is therefore
+	// assumed to be plain text.
+	EMLMissingContentType
+
+	// EMLMissingDate indicates that the EML has no "Date" header and the current time is used instead.
+	EMLMissingDate
+
+	// EMLEightBitHeader indicates that a header of the EML holds unencoded 8-bit data.
+	EMLEightBitHeader
+
+	// EMLInvalidBoundary indicates that the boundary of a multipart body does not comply with the
+	// syntax of RFC 2046.
+	EMLInvalidBoundary
+
+	// EMLUnknownTransferEncoding indicates that the "Content-Transfer-Encoding" of the EML is unknown
+	// and the body is taken as is.
+	EMLUnknownTransferEncoding
+
+	// EMLBareLineFeed indicates that the headers or a text part of the EML use bare LF line endings,
+	// which have been converted to CRLF in lenient parsing mode.
+	EMLBareLineFeed
+
+	// EMLMissingParameterSeparator indicates that the semicolon in front of a parameter of a header
+	// was missing and has been inserted in lenient parsing mode.
+	EMLMissingParameterSeparator
+)
+
+// maxBoundaryLength is the maximum length of a multipart boundary as defined in RFC 2046.
+const maxBoundaryLength = 70
+
+type (
+	// EMLDeviationKind represents the kind of deviation from the mail standards that was tolerated
+	// while parsing an EML.
+	EMLDeviationKind int
+
+	// EMLDeviation represents a single deviation from the mail standards that was tolerated while
+	// parsing an EML.
+	EMLDeviation struct {
+		// Detail holds additional information about the deviation, i. e. the affected header.
+		Detail string
+
+		// Kind is the EMLDeviationKind of the deviation.
+		Kind EMLDeviationKind
+	}
+
+	// EMLDiagnostics holds all deviations from the mail standards that were tolerated while
+	// parsing an EML.
+	//
+	// Intake pipelines can use the EMLDiagnostics of a parsed Msg to quantify the quality of
+	// the senders, instead of silently accepting malformed mail.
+	EMLDiagnostics struct {
+		// Deviations holds the tolerated deviations in the order they were found.
+		Deviations []EMLDeviation
+	}
+)
+
+// EMLDiagnostics returns the EMLDiagnostics of a Msg that was parsed from an EML.
+//
+// Returns:
+//   - A pointer to the EMLDiagnostics of the parsed EML, or nil if the Msg was not parsed from an EML.
+func (m *Msg) EMLDiagnostics() *EMLDiagnostics {
+	return m.emlDiagnostics
+}
+
+// Has reports whether a deviation of the given EMLDeviationKind was found.
+//
+// Parameters:
+//   - kind: The EMLDeviationKind to look for.
+//
+// Returns:
+//   - True if at least one deviation of the given kind was found, false otherwise.
+func (d *EMLDiagnostics) Has(kind EMLDeviationKind) bool {
+	if d == nil {
+		return false
+	}
+	for _, deviation := range d.Deviations {
+		if deviation.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// String satisfies the fmt.Stringer interface for the EMLDeviationKind type.
+//
+// Returns:
+//   - A string representation of the EMLDeviationKind.
+func (k EMLDeviationKind) String() string {
+	switch k {
+	case EMLMissingMIMEVersion:
+		return "missing MIME-Version header"
+	case EMLMissingContentType:
+		return "missing Content-Type header"
+	case EMLMissingDate:
+		return "missing Date header"
+	case EMLEightBitHeader:
+		return "unencoded 8-bit header"
+	case EMLInvalidBoundary:
+		return "invalid multipart boundary"
+	case EMLUnknownTransferEncoding:
+		return "unknown Content-Transfer-Encoding"
+	case EMLBareLineFeed:
+		return "bare LF line endings"
+	case EMLMissingParameterSeparator:
+		return "missing parameter separator"
+	}
+	return "unknown deviation"
+}
+
+// String satisfies the fmt.Stringer interface for the EMLDeviation type.
+//
+// Returns:
+//   - A string representation of the EMLDeviation, including its detail if present.
+func (d EMLDeviation) String() string {
+	if d.Detail == "" {
+		return d.Kind.String()
+	}
+	return fmt.Sprintf("%s: %s", d.Kind, d.Detail)
+}
+
+// add records a deviation of the given kind. It is a no-op on a nil EMLDiagnostics.
+func (d *EMLDiagnostics) add(kind EMLDeviationKind, detail string) {
+	if d == nil {
+		return
+	}
+	d.Deviations = append(d.Deviations, EMLDeviation{Detail: detail, Kind: kind})
+}
+
+// diagnoseEMLHeaders records deviations found in the top-level headers of an EML.
+func (d *EMLDiagnostics) diagnoseEMLHeaders(mailHeader *netmail.Header) {
+	if d == nil {
+		return
+	}
+	if mailHeader.Get(HeaderMIMEVersion.String()) == "" {
+		d.add(EMLMissingMIMEVersion, "")
+	}
+	if mailHeader.Get(HeaderContentType.String()) == "" {
+		d.add(EMLMissingContentType, "")
+	}
+	if mailHeader.Get(HeaderDate.String()) == "" {
+		d.add(EMLMissingDate, "")
+	}
+	names := make([]string, 0, len(*mailHeader))
+	for name := range *mailHeader {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range (*mailHeader)[name] {
+			if hasEightBitData(value) {
+				d.add(EMLEightBitHeader, name)
+				break
+			}
+		}
+	}
+}
+
+// diagnoseEMLBoundary records a deviation if the boundary of a multipart body is invalid.
+func (d *EMLDiagnostics) diagnoseEMLBoundary(boundary string) {
+	if d == nil {
+		return
+	}
+	if !isValidBoundary(boundary) {
+		d.add(EMLInvalidBoundary, boundary)
+	}
+}
+
+// hasEightBitData reports whether the given string holds any byte outside of the 7-bit range.
+func hasEightBitData(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] >= 0x80 {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidBoundary reports whether the boundary complies with the syntax of RFC 2046, section 5.1.1.
+func isValidBoundary(boundary string) bool {
+	if boundary == "" || len(boundary) > maxBoundaryLength || strings.HasSuffix(boundary, " ") {
+		return false
+	}
+	for _, char := range boundary {
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9':
+		case strings.ContainsRune("'()+_,-./:=? ", char):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"testing"
+)
+
+const (
+	// exampleMailDeviations is an EML with several tolerated deviations
+	exampleMailDeviations = "From: Töni Tester <valid-from@domain.tld>\r\n" +
+		"To: <valid-to@domain.tld>\r\n" +
+		"Subject: Grüße\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"invalid boundary with <brackets>\"\r\n" +
+		"\r\n" +
+		"--invalid boundary with <brackets>\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"Content-Transfer-Encoding: 7bit\r\n" +
+		"\r\n" +
+		"Testmail\r\n" +
+		"--invalid boundary with <brackets>--\r\n"
+
+	// exampleMailCompliant is an EML without any deviation
+	exampleMailCompliant = "Date: Tue, 01 Oct 2024 12:00:00 +0000\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"From: <valid-from@domain.tld>\r\n" +
+		"To: <valid-to@domain.tld>\r\n" +
+		"Subject: =?UTF-8?q?Gr=C3=BC=C3=9Fe?=\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"Content-Transfer-Encoding: 7bit\r\n" +
+		"\r\n" +
+		"Testmail\r\n"
+
+	// exampleMailUnknownEncoding is an EML with an unknown Content-Transfer-Encoding
+	exampleMailUnknownEncoding = "Date: Tue, 01 Oct 2024 12:00:00 +0000\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"From: <valid-from@domain.tld>\r\n" +
+		"To: <valid-to@domain.tld>\r\n" +
+		"Subject: Testmail\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"boundary\"\r\n" +
+		"Content-Transfer-Encoding: 7-bit\r\n" +
+		"\r\n" +
+		"--boundary\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"Content-Transfer-Encoding: 7bit\r\n" +
+		"\r\n" +
+		"Testmail\r\n" +
+		"--boundary--\r\n"
+)
+
+func TestMsg_EMLDiagnostics(t *testing.T) {
+	t.Run("EML with deviations", func(t *testing.T) {
+		message, err := EMLToMsgFromString(exampleMailDeviations)
+		if err != nil {
+			t.Fatalf("failed to parse EML: %s", err)
+		}
+		diagnostics := message.EMLDiagnostics()
+		if diagnostics == nil {
+			t.Fatal("expected EML diagnostics, got nil")
+		}
+		want := []EMLDeviation{
+			{Kind: EMLMissingMIMEVersion},
+			{Kind: EMLMissingDate},
+			{Kind: EMLEightBitHeader, Detail: "From"},
+			{Kind: EMLEightBitHeader, Detail: "Subject"},
+			{Kind: EMLInvalidBoundary, Detail: "invalid boundary with <brackets>"},
+		}
+		if len(diagnostics.Deviations) != len(want) {
+			t.Fatalf("unexpected deviations, want: %v, got: %v", want, diagnostics.Deviations)
+		}
+		for i := range want {
+			if diagnostics.Deviations[i] != want[i] {
+				t.Errorf("unexpected deviation %d, want: %s, got: %s", i, want[i], diagnostics.Deviations[i])
+			}
+		}
+		if !diagnostics.Has(EMLEightBitHeader) {
+			t.Error("expected diagnostics to have 8-bit header deviation")
+		}
+		if diagnostics.Has(EMLMissingContentType) {
+			t.Error("expected diagnostics not to have missing content type deviation")
+		}
+	})
+	t.Run("EML without deviations", func(t *testing.T) {
+		message, err := EMLToMsgFromString(exampleMailCompliant)
+		if err != nil {
+			t.Fatalf("failed to parse EML: %s", err)
+		}
+		if deviations := message.EMLDiagnostics().Deviations; len(deviations) != 0 {
+			t.Errorf("expected no deviations, got: %v", deviations)
+		}
+	})
+	t.Run("EML with unknown transfer encoding", func(t *testing.T) {
+		message, err := EMLToMsgFromString(exampleMailUnknownEncoding)
+		if err != nil {
+			t.Fatalf("failed to parse EML: %s", err)
+		}
+		want := EMLDeviation{Kind: EMLUnknownTransferEncoding, Detail: "7-bit"}
+		deviations := message.EMLDiagnostics().Deviations
+		if len(deviations) != 1 || deviations[0] != want {
+			t.Errorf("unexpected deviations, want: %s, got: %v", want, deviations)
+		}
+	})
+	t.Run("message not parsed from EML", func(t *testing.T) {
+		message := testMessage(t)
+		if message.EMLDiagnostics() != nil {
+			t.Error("expected no EML diagnostics for message not parsed from EML")
+		}
+		if message.EMLDiagnostics().Has(EMLMissingDate) {
+			t.Error("expected nil diagnostics to have no deviations")
+		}
+	})
+}
+
+func TestEMLDeviation_String(t *testing.T) {
+	tests := []struct {
+		deviation EMLDeviation
+		want      string
+	}{
+		{EMLDeviation{Kind: EMLMissingMIMEVersion}, "missing MIME-Version header"},
+		{EMLDeviation{Kind: EMLMissingContentType}, "missing Content-Type header"},
+		{EMLDeviation{Kind: EMLMissingDate}, "missing Date header"},
+		{EMLDeviation{Kind: EMLEightBitHeader, Detail: "Subject"}, "unencoded 8-bit header: Subject"},
+		{EMLDeviation{Kind: EMLInvalidBoundary}, "invalid multipart boundary"},
+		{EMLDeviation{Kind: EMLUnknownTransferEncoding}, "unknown Content-Transfer-Encoding"},
+		{EMLDeviation{Kind: EMLBareLineFeed, Detail: "header"}, "bare LF line endings: header"},
+		{EMLDeviation{Kind: EMLMissingParameterSeparator}, "missing parameter separator"},
+		{EMLDeviation{Kind: 9999}, "unknown deviation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.deviation.String(); got != tt.want {
+				t.Errorf("unexpected string, want: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIsValidBoundary(t *testing.T) {
+	tests := []struct {
+		boundary string
+		want     bool
+	}{
+		{"simple-boundary_1234", true},
+		{"with space", true},
+		{"", false},
+		{"trailing space ", false},
+		{"<invalid>", false},
+		{"012345678901234567890123456789012345678901234567890123456789012345678901", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.boundary, func(t *testing.T) {
+			if got := isValidBoundary(tt.boundary); got != tt.want {
+				t.Errorf("unexpected result for boundary %q, want: %t, got: %t", tt.boundary, tt.want, got)
+			}
+		})
+	}
+}
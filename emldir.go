@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+	// EMLFileError is an error type that holds the error that occurred while processing a single EML
+	// file with ParseEMLDir.
+	EMLFileError struct {
+		// Err is the error returned by the parser or the callback function.
+		Err error
+
+		// Path is the path of the affected EML file.
+		Path string
+	}
+
+	// EMLDirError is an error type that aggregates all EMLFileError that occurred during ParseEMLDir.
+	EMLDirError struct {
+		// Errors holds an EMLFileError for each EML file that failed, sorted by path.
+		Errors []*EMLFileError
+
+		// Files is the total number of EML files that were processed.
+		Files int
+	}
+)
+
+// ParseEMLDir walks the given directory and its subdirectories, parses each file with an ".eml"
+// extension and calls fn with the resulting Msg.
+//
+// The files are processed by a pool of the given number of workers. If workers is zero or
+// negative, the number of CPUs is used. Since fn is called concurrently by the workers, it must be
+// safe for concurrent use. Errors of a single file, either returned by the parser or by fn, do not
+// abort the processing of the other files. Instead they are aggregated into an EMLDirError. If the
+// context is cancelled, no further files are processed and the error of the context is returned.
+//
+// Parameters:
+//   - ctx: The context.Context to control the processing.
+//   - dir: The path of the directory with the EML files.
+//   - workers: The number of concurrent workers.
+//   - fn: The function that is called for each parsed Msg.
+//
+// Returns:
+//   - An error if the directory could not be walked, the context was cancelled or if any of the
+//     files failed, in which case the error is an *EMLDirError; otherwise, returns nil.
+func ParseEMLDir(ctx context.Context, dir string, workers int, fn func(*Msg) error) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	paths := make(chan string)
+	dirErr := &EMLDirError{}
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				err := parseEMLDirFile(path, fn)
+				mutex.Lock()
+				dirErr.Files++
+				if err != nil {
+					dirErr.Errors = append(dirErr.Errors, &EMLFileError{Err: err, Path: path})
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	walkErr := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".eml") {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case paths <- path:
+			return nil
+		}
+	})
+	close(paths)
+	wg.Wait()
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if walkErr != nil {
+		return fmt.Errorf("failed to walk EML directory: %w", walkErr)
+	}
+	if len(dirErr.Errors) > 0 {
+		sort.Slice(dirErr.Errors, func(i, j int) bool {
+			return dirErr.Errors[i].Path < dirErr.Errors[j].Path
+		})
+		return dirErr
+	}
+	return nil
+}
+
+// Error implements the error interface for the EMLFileError type.
+//
+// Returns:
+//   - A string with the path of the EML file and the error message.
+func (e *EMLFileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error of the EMLFileError.
+//
+// Returns:
+//   - The error returned by the parser or the callback function.
+func (e *EMLFileError) Unwrap() error {
+	return e.Err
+}
+
+// Error implements the error interface for the EMLDirError type.
+//
+// Returns:
+//   - A string with the number of failed files and the error message of each failed file.
+func (e *EMLDirError) Error() string {
+	var errMessage strings.Builder
+	errMessage.WriteString(fmt.Sprintf("failed to process %d of %d EML files", len(e.Errors), e.Files))
+	for _, fileErr := range e.Errors {
+		errMessage.WriteString("; ")
+		errMessage.WriteString(fileErr.Error())
+	}
+	return errMessage.String()
+}
+
+// Unwrap returns the EMLFileError of each failed file.
+//
+// Returns:
+//   - A slice of errors with an *EMLFileError for each failed file.
+func (e *EMLDirError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i := range e.Errors {
+		errs[i] = e.Errors[i]
+	}
+	return errs
+}
+
+// parseEMLDirFile parses the EML file at the given path and calls fn with the resulting Msg.
+//
+// Parameters:
+//   - path: The path of the EML file.
+//   - fn: The function that is called with the parsed Msg.
+//
+// Returns:
+//   - An error if parsing the file or fn fails; otherwise, returns nil.
+func parseEMLDirFile(path string, fn func(*Msg) error) error {
+	msg, err := EMLToMsgFromFile(path)
+	if err != nil {
+		return err
+	}
+	return fn(msg)
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParseEMLDir(t *testing.T) {
+	newEMLDir := func(t *testing.T) string {
+		t.Helper()
+		dir := t.TempDir()
+		if err := os.Mkdir(filepath.Join(dir, "sub"), 0o750); err != nil {
+			t.Fatalf("failed to create subdirectory: %s", err)
+		}
+		for _, name := range []string{"one.eml", "two.EML", filepath.Join("sub", "three.eml")} {
+			if err := testMessage(t).WriteToFile(filepath.Join(dir, name)); err != nil {
+				t.Fatalf("failed to write EML file: %s", err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not an EML"), 0o600); err != nil {
+			t.Fatalf("failed to write text file: %s", err)
+		}
+		return dir
+	}
+	t.Run("parse all EML files in directory tree", func(t *testing.T) {
+		dir := newEMLDir(t)
+		var count atomic.Int32
+		err := ParseEMLDir(context.Background(), dir, 2, func(msg *Msg) error {
+			if subject := msg.GetGenHeader(HeaderSubject); len(subject) != 1 || subject[0] != "Testmail" {
+				t.Errorf("unexpected subject of parsed message: %v", subject)
+			}
+			count.Add(1)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to parse EML directory: %s", err)
+		}
+		if count.Load() != 3 {
+			t.Errorf("expected 3 parsed messages, got: %d", count.Load())
+		}
+	})
+	t.Run("errors are aggregated per file", func(t *testing.T) {
+		dir := newEMLDir(t)
+		if err := os.WriteFile(filepath.Join(dir, "broken.eml"), []byte("broken"), 0o600); err != nil {
+			t.Fatalf("failed to write broken EML file: %s", err)
+		}
+		callbackErr := errors.New("callback failed")
+		err := ParseEMLDir(context.Background(), dir, 0, func(msg *Msg) error {
+			return callbackErr
+		})
+		var dirErr *EMLDirError
+		if !errors.As(err, &dirErr) {
+			t.Fatalf("expected EMLDirError, got: %s", err)
+		}
+		if dirErr.Files != 4 || len(dirErr.Errors) != 4 {
+			t.Fatalf("expected 4 failed of 4 files, got %d of %d", len(dirErr.Errors), dirErr.Files)
+		}
+		if dirErr.Errors[0].Path != filepath.Join(dir, "broken.eml") {
+			t.Errorf("expected errors sorted by path, got first: %s", dirErr.Errors[0].Path)
+		}
+		if errors.Is(dirErr.Errors[0], callbackErr) {
+			t.Error("expected parser error for broken EML file, got callback error")
+		}
+		for _, fileErr := range dirErr.Errors[1:] {
+			if !errors.Is(fileErr, callbackErr) {
+				t.Errorf("expected callback error, got: %s", fileErr)
+			}
+		}
+		if len(dirErr.Unwrap()) != 4 {
+			t.Errorf("expected 4 unwrapped errors, got: %d", len(dirErr.Unwrap()))
+		}
+	})
+	t.Run("cancelled context aborts processing", func(t *testing.T) {
+		dir := newEMLDir(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := ParseEMLDir(ctx, dir, 1, func(msg *Msg) error {
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected error: %s, got: %s", context.Canceled, err)
+		}
+	})
+	t.Run("non-existing directory fails", func(t *testing.T) {
+		err := ParseEMLDir(context.Background(), filepath.Join(t.TempDir(), "missing"), 1, func(msg *Msg) error {
+			return nil
+		})
+		if err == nil {
+			t.Error("expected error for non-existing directory")
+		}
+	})
+}
+
+func TestEMLDirError_Error(t *testing.T) {
+	err := &EMLDirError{
+		Errors: []*EMLFileError{{Err: errors.New("broken"), Path: "a.eml"}},
+		Files:  2,
+	}
+	want := "failed to process 1 of 2 EML files; a.eml: broken"
+	if err.Error() != want {
+		t.Errorf("unexpected error message, want: %s, got: %s", want, err.Error())
+	}
+}
@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+type (
+	// EMLOption is a function type that configures the parsing of an EML by the EMLToMsgFrom*
+	// functions.
+	EMLOption func(*emlOptions)
+
+	// emlOptions holds the configuration of the EML parser.
+	emlOptions struct {
+		// diagnostics is the EMLDiagnostics of the Msg being parsed, in which the repairs of the
+		// lenient mode are recorded.
+		diagnostics *EMLDiagnostics
+
+		// lenient indicates that common defects of legacy mail are repaired while parsing.
+		lenient bool
+
+		// limits holds the EMLLimits that are enforced while parsing.
+		limits EMLLimits
+
+		// parts counts the MIME parts of the EML that have been parsed, to enforce the MaxParts limit.
+		parts int
+	}
+)
+
+// WithLenientParsing enables a tolerant parsing mode for malformed legacy mail.
+//
+// In lenient mode, the parser repairs the following defects, which are common in real-world mail
+// archives, instead of failing or mislabeling the content:
+//   - Bare LF line endings of the headers and of text parts are converted to CRLF. The content of
+//     attachments and other non-text parts is left untouched, so that binary data is not altered.
+//   - Header values with unencoded 8-bit data that is not valid UTF-8 are converted from
+//     Windows-1252, a superset of ISO-8859-1, to UTF-8.
+//   - Missing semicolons between the parameters of the "Content-Type" and "Content-Disposition"
+//     headers are inserted, i. e. "text/plain charset=utf-8" is read as
+//     "text/plain; charset=utf-8".
+//
+// Since the whole EML is read into memory in lenient mode, it should only be used for sources
+// that are known to be affected. Each repair is recorded as EMLDeviation in the EMLDiagnostics
+// of the parsed Msg, with the kinds EMLBareLineFeed, EMLEightBitHeader and
+// EMLMissingParameterSeparator.
+//
+// Returns:
+//   - An EMLOption that enables the lenient parsing mode.
+func WithLenientParsing() EMLOption {
+	return func(o *emlOptions) {
+		o.lenient = true
+	}
+}
+
+// newEMLOptions returns the emlOptions for the given EMLOption values.
+func newEMLOptions(opts ...EMLOption) *emlOptions {
+	options := &emlOptions{}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(options)
+	}
+	return options
+}
+
+// normalizeEMLHeaderLineEndings reads the EML from the given io.Reader and converts the bare LF
+// line endings of its header section to CRLF. The body is returned unchanged, since only the
+// line endings of text parts may be converted. A conversion is recorded in the EMLDiagnostics.
+func normalizeEMLHeaderLineEndings(reader io.Reader, diagnostics *EMLDiagnostics) (io.Reader, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	headerEnd := len(data)
+	for offset := 0; offset < len(data); {
+		end := bytes.IndexByte(data[offset:], '\n')
+		if end < 0 {
+			break
+		}
+		line := data[offset : offset+end]
+		offset += end + 1
+		if len(line) == 0 || bytes.Equal(line, []byte("\r")) {
+			headerEnd = offset
+			break
+		}
+	}
+	header, changed := normalizeLineEndings(data[:headerEnd])
+	if !changed {
+		return bytes.NewReader(data), nil
+	}
+	diagnostics.add(EMLBareLineFeed, "header")
+	return io.MultiReader(bytes.NewReader(header), bytes.NewReader(data[headerEnd:])), nil
+}
+
+// normalizeEMLTextLineEndings converts the bare LF line endings of the given content of a part to
+// CRLF if the part is of a text type. A conversion is recorded in the EMLDiagnostics.
+func normalizeEMLTextLineEndings(data []byte, contentType string, diagnostics *EMLDiagnostics) []byte {
+	if !strings.HasPrefix(strings.ToLower(contentType), "text/") {
+		return data
+	}
+	normalized, changed := normalizeLineEndings(data)
+	if changed {
+		diagnostics.add(EMLBareLineFeed, contentType)
+	}
+	return normalized
+}
+
+// normalizeLineEndings converts all bare LF line endings of the given data to CRLF and reports
+// whether any line ending was converted.
+func normalizeLineEndings(data []byte) ([]byte, bool) {
+	bareLF := bytes.Count(data, []byte("\n")) - bytes.Count(data, []byte("\r\n"))
+	if bareLF == 0 {
+		return data, false
+	}
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n")), true
+}
+
+// repairEMLHeader repairs unencoded 8-bit data that is not valid UTF-8 and missing parameter
+// separators in the given header. Inserted parameter separators are recorded in the EMLDiagnostics,
+// unencoded 8-bit data is recorded as EMLEightBitHeader when the headers are diagnosed.
+func repairEMLHeader(header map[string][]string, diagnostics *EMLDiagnostics) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := header[name]
+		for i, value := range values {
+			if hasEightBitData(value) && !utf8.ValidString(value) {
+				if decoded, err := charmap.Windows1252.NewDecoder().String(value); err == nil {
+					value = decoded
+				}
+			}
+			if strings.EqualFold(name, HeaderContentType.String()) ||
+				strings.EqualFold(name, HeaderContentDisposition.String()) {
+				if repaired := repairEMLParameterSeparators(value); repaired != value {
+					diagnostics.add(EMLMissingParameterSeparator, name)
+					value = repaired
+				}
+			}
+			values[i] = value
+		}
+	}
+}
+
+// repairEMLParameterSeparators inserts missing semicolons in front of the parameters of a
+// parameterized header value. Quoted strings are left untouched.
+func repairEMLParameterSeparators(value string) string {
+	var repaired strings.Builder
+	var lastChar byte
+	inQuote := false
+	for i := 0; i < len(value); i++ {
+		char := value[i]
+		switch {
+		case inQuote && char == '\\' && i+1 < len(value):
+			repaired.WriteByte(char)
+			i++
+			char = value[i]
+		case char == '"':
+			inQuote = !inQuote
+		case !inQuote && (char == ' ' || char == '\t') && lastChar != 0 && lastChar != ';' &&
+			startsWithEMLParameter(value[i:]):
+			repaired.WriteByte(';')
+			lastChar = ';'
+		}
+		repaired.WriteByte(char)
+		if char != ' ' && char != '\t' {
+			lastChar = char
+		}
+	}
+	return repaired.String()
+}
+
+// startsWithEMLParameter reports whether the given string starts with a "name=" parameter after
+// optional whitespace.
+func startsWithEMLParameter(value string) bool {
+	value = strings.TrimLeft(value, " \t")
+	for i := 0; i < len(value); i++ {
+		char := value[i]
+		switch {
+		case char == '=':
+			return i > 0
+		case char <= ' ' || char >= 0x7f || strings.IndexByte(`()<>@,;:\"/[]?`, char) >= 0:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"mime"
+	"strings"
+	"testing"
+)
+
+const (
+	// exampleMailLegacy is an EML with bare LF line endings, unencoded Windows-1252 headers and a
+	// Content-Type without parameter separator
+	exampleMailLegacy = "Date: Tue, 01 Oct 2024 12:00:00 +0000\n" +
+		"MIME-Version: 1.0\n" +
+		"From: \"T\xf6ni Tester\" <valid-from@domain.tld>\n" +
+		"To: <valid-to@domain.tld>\n" +
+		"Subject: Gr\xfc\xdfe\n" +
+		"Content-Type: text/plain charset=utf8\n" +
+		"Content-Transfer-Encoding: 7bit\n" +
+		"\n" +
+		"Testmail\n"
+
+	// exampleMailLegacyMultipart is a multipart EML with bare LF line endings and headers without
+	// parameter separators
+	exampleMailLegacyMultipart = "Date: Tue, 01 Oct 2024 12:00:00 +0000\n" +
+		"MIME-Version: 1.0\n" +
+		"From: <valid-from@domain.tld>\n" +
+		"To: <valid-to@domain.tld>\n" +
+		"Subject: Testmail\n" +
+		"Content-Type: multipart/mixed boundary=\"legacy boundary\"\n" +
+		"\n" +
+		"--legacy boundary\n" +
+		"Content-Type: text/plain charset=utf-8\n" +
+		"Content-Transfer-Encoding: 7bit\n" +
+		"\n" +
+		"Testmail\n" +
+		"--legacy boundary\n" +
+		"Content-Type: text/plain name=\"test.txt\"\n" +
+		"Content-Disposition: attachment filename=\"test.txt\"\n" +
+		"Content-Transfer-Encoding: 7bit\n" +
+		"\n" +
+		"Attachment\n" +
+		"--legacy boundary\n" +
+		"Content-Type: application/octet-stream\n" +
+		"Content-Disposition: attachment; filename=\"test.bin\"\n" +
+		"Content-Transfer-Encoding: binary\n" +
+		"\n" +
+		"\x00\n\xff\n" +
+		"--legacy boundary--\n"
+)
+
+func TestWithLenientParsing(t *testing.T) {
+	t.Run("legacy mail fails in strict mode", func(t *testing.T) {
+		if _, err := EMLToMsgFromString(exampleMailLegacy); err == nil {
+			t.Error("expected parsing of legacy mail to fail in strict mode")
+		}
+	})
+	t.Run("legacy mail is repaired in lenient mode", func(t *testing.T) {
+		message, err := EMLToMsgFromString(exampleMailLegacy, WithLenientParsing())
+		if err != nil {
+			t.Fatalf("failed to parse EML in lenient mode: %s", err)
+		}
+		from := message.GetFrom()
+		if len(from) != 1 || from[0].Name != "Töni Tester" {
+			t.Errorf("expected repaired from name %q, got: %v", "Töni Tester", from)
+		}
+		decoder := mime.WordDecoder{}
+		subject := message.GetGenHeader(HeaderSubject)
+		if len(subject) != 1 {
+			t.Fatalf("expected subject header, got: %v", subject)
+		}
+		if decoded, err := decoder.DecodeHeader(subject[0]); err != nil || decoded != "Grüße" {
+			t.Errorf("expected repaired subject %q, got: %q", "Grüße", subject[0])
+		}
+		if message.Charset() != CharsetUTF8.String() {
+			t.Errorf("expected charset: %s, got: %s", CharsetUTF8, message.Charset())
+		}
+		for _, kind := range []EMLDeviationKind{EMLBareLineFeed, EMLEightBitHeader, EMLMissingParameterSeparator} {
+			if !message.EMLDiagnostics().Has(kind) {
+				t.Errorf("expected deviation %q to be recorded", kind)
+			}
+		}
+	})
+	t.Run("legacy multipart mail is repaired in lenient mode", func(t *testing.T) {
+		message, err := EMLToMsgFromString(exampleMailLegacyMultipart, WithLenientParsing())
+		if err != nil {
+			t.Fatalf("failed to parse EML in lenient mode: %s", err)
+		}
+		parts := message.GetParts()
+		if len(parts) != 1 {
+			t.Fatalf("expected 1 part, got: %d", len(parts))
+		}
+		content, err := parts[0].GetContent()
+		if err != nil {
+			t.Fatalf("failed to get part content: %s", err)
+		}
+		if string(content) != "Testmail" {
+			t.Errorf("expected part content %q, got: %q", "Testmail", content)
+		}
+		if charset := parts[0].GetCharset(); charset != CharsetUTF8 {
+			t.Errorf("expected part charset: %s, got: %s", CharsetUTF8, charset)
+		}
+		attachments := message.GetAttachments()
+		if len(attachments) != 2 || attachments[0].Name != "test.txt" || attachments[1].Name != "test.bin" {
+			t.Fatalf("expected attachments test.txt and test.bin, got: %v", attachments)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err = attachments[1].Writer(buffer); err != nil {
+			t.Fatalf("failed to write attachment: %s", err)
+		}
+		if buffer.String() != "\x00\n\xff" {
+			t.Errorf("expected binary attachment to be unchanged, got: %q", buffer.String())
+		}
+	})
+	t.Run("nil option is ignored", func(t *testing.T) {
+		if _, err := EMLToMsgFromString(exampleMailPlainNoEnc, nil); err != nil {
+			t.Errorf("failed to parse EML with nil option: %s", err)
+		}
+	})
+}
+
+func TestRepairEMLParameterSeparators(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"text/plain", "text/plain"},
+		{"text/plain; charset=utf-8", "text/plain; charset=utf-8"},
+		{"text/plain charset=utf-8", "text/plain; charset=utf-8"},
+		{"text/plain charset=utf-8 format=flowed", "text/plain; charset=utf-8; format=flowed"},
+		{`multipart/mixed boundary="a b=c"`, `multipart/mixed; boundary="a b=c"`},
+		{`attachment filename="a \" b=c"`, `attachment; filename="a \" b=c"`},
+		{"text/plain  (comment)", "text/plain  (comment)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := repairEMLParameterSeparators(tt.value); got != tt.want {
+				t.Errorf("unexpected repaired value, want: %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeEMLHeaderLineEndings(t *testing.T) {
+	diagnostics := &EMLDiagnostics{}
+	reader, err := normalizeEMLHeaderLineEndings(strings.NewReader("A: a\nB: b\r\n\nc\nd\n"), diagnostics)
+	if err != nil {
+		t.Fatalf("failed to normalize line endings: %s", err)
+	}
+	buffer := bytes.NewBuffer(nil)
+	if _, err = buffer.ReadFrom(reader); err != nil {
+		t.Fatalf("failed to read normalized EML: %s", err)
+	}
+	if buffer.String() != "A: a\r\nB: b\r\n\r\nc\nd\n" {
+		t.Errorf("unexpected normalized EML: %q", buffer.String())
+	}
+	want := EMLDeviation{Kind: EMLBareLineFeed, Detail: "header"}
+	if len(diagnostics.Deviations) != 1 || diagnostics.Deviations[0] != want {
+		t.Errorf("expected deviation %s, got: %v", want, diagnostics.Deviations)
+	}
+}
+
+func TestNormalizeEMLTextLineEndings(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		contentType string
+		want        string
+		deviation   bool
+	}{
+		{"text part is normalized", "a\nb\r\nc\n", "text/plain", "a\r\nb\r\nc\r\n", true},
+		{"text part with CRLF is unchanged", "a\r\nb\r\n", "text/html", "a\r\nb\r\n", false},
+		{"binary part is unchanged", "\x00\n\xff\n", "application/octet-stream", "\x00\n\xff\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := &EMLDiagnostics{}
+			got := normalizeEMLTextLineEndings([]byte(tt.data), tt.contentType, diagnostics)
+			if string(got) != tt.want {
+				t.Errorf("unexpected normalized data, want: %q, got: %q", tt.want, got)
+			}
+			if diagnostics.Has(EMLBareLineFeed) != tt.deviation {
+				t.Errorf("unexpected deviations: %v", diagnostics.Deviations)
+			}
+		})
+	}
+}
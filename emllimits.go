@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// List of EMLLimitKind values
+const (
+	// EMLLimitHeaderCount is the limit of the number of header fields of the EML.
+	EMLLimitHeaderCount EMLLimitKind = iota
+
+	// EMLLimitHeaderBytes is the limit of the total size of the header section of the EML.
+	EMLLimitHeaderBytes
+
+	// EMLLimitHeaderLineLength is the limit of the length of a single header line of the EML.
+	EMLLimitHeaderLineLength
+
+	// EMLLimitParts is the limit of the number of MIME parts of the EML.
+	EMLLimitParts
+
+	// EMLLimitDepth is the limit of the nesting depth of the multipart bodies of the EML.
+	EMLLimitDepth
+)
+
+type (
+	// EMLLimitKind represents the kind of limit of the EML parser that was exceeded.
+	EMLLimitKind int
+
+	// EMLLimits holds the limits of the EML parser, as set with WithEMLLimits.
+	//
+	// A zero value of a field means that the corresponding property of the EML is not limited.
+	EMLLimits struct {
+		// MaxHeaderCount is the maximum number of header fields of the EML. Folded continuation lines
+		// are counted as part of their header field.
+		MaxHeaderCount int
+
+		// MaxHeaderBytes is the maximum size of the header section of the EML in bytes, including the
+		// line endings.
+		MaxHeaderBytes int
+
+		// MaxHeaderLineLength is the maximum length of a single line of the header section of the EML,
+		// excluding the line ending.
+		MaxHeaderLineLength int
+
+		// MaxParts is the maximum number of MIME parts of the EML, including the parts that hold nested
+		// multipart bodies.
+		MaxParts int
+
+		// MaxDepth is the maximum nesting depth of the multipart bodies of the EML. A multipart body of
+		// the message itself has the depth 1.
+		MaxDepth int
+	}
+
+	// EMLLimitError is returned by the EMLToMsgFrom* functions if the EML exceeds one of the
+	// EMLLimits of the parser.
+	EMLLimitError struct {
+		// Kind is the EMLLimitKind of the exceeded limit.
+		Kind EMLLimitKind
+
+		// Limit is the configured value of the exceeded limit.
+		Limit int
+	}
+)
+
+// WithEMLLimits sets the limits of the EML parser.
+//
+// Intake services that parse mail from untrusted sources can use the limits to reject malicious
+// messages with an excessive number of headers, MIME parts or nesting levels early, instead of
+// spending memory and CPU time on them. The header section is checked while it is read, before it
+// is parsed. If a limit is exceeded, the parser returns an EMLLimitError.
+//
+// Parameters:
+//   - limits: The EMLLimits to enforce while parsing.
+//
+// Returns:
+//   - An EMLOption that sets the limits of the parser.
+func WithEMLLimits(limits EMLLimits) EMLOption {
+	return func(o *emlOptions) {
+		o.limits = limits
+	}
+}
+
+// Error satisfies the error interface for the EMLLimitError type.
+func (e *EMLLimitError) Error() string {
+	return fmt.Sprintf("EML exceeds the limit of %d for the %s", e.Limit, e.Kind)
+}
+
+// Is implements the errors.Is functionality and compares the EMLLimitKind of two EMLLimitError values.
+//
+// Parameters:
+//   - errorVar: The error to compare with.
+//
+// Returns:
+//   - true if errorVar is an EMLLimitError with the same EMLLimitKind, false otherwise.
+func (e *EMLLimitError) Is(errorVar error) bool {
+	var limitErr *EMLLimitError
+	if !errors.As(errorVar, &limitErr) {
+		return false
+	}
+	return e.Kind == limitErr.Kind
+}
+
+// String satisfies the fmt.Stringer interface for the EMLLimitKind type.
+func (k EMLLimitKind) String() string {
+	switch k {
+	case EMLLimitHeaderCount:
+		return "number of header fields"
+	case EMLLimitHeaderBytes:
+		return "size of the header section"
+	case EMLLimitHeaderLineLength:
+		return "length of a header line"
+	case EMLLimitParts:
+		return "number of MIME parts"
+	case EMLLimitDepth:
+		return "nesting depth of multipart bodies"
+	default:
+		return "unknown limit"
+	}
+}
+
+// hasHeaderLimits reports whether any limit of the header section is set.
+func (l EMLLimits) hasHeaderLimits() bool {
+	return l.MaxHeaderCount > 0 || l.MaxHeaderBytes > 0 || l.MaxHeaderLineLength > 0
+}
+
+// limitEMLHeader reads the header section of the EML from the given io.Reader and checks it against
+// the header limits, before it is read into memory as a whole by net/mail.
+//
+// Parameters:
+//   - reader: The io.Reader holding the EML.
+//   - limits: The EMLLimits to enforce.
+//
+// Returns:
+//   - An io.Reader that holds the complete EML, including the already read header section.
+//   - An EMLLimitError if the header section exceeds a limit, or the error of the reader;
+//     otherwise, returns nil.
+func limitEMLHeader(reader io.Reader, limits EMLLimits) (io.Reader, error) {
+	bufReader := bufio.NewReader(reader)
+	header := &bytes.Buffer{}
+	count, lineLength := 0, 0
+	for {
+		char, err := bufReader.ReadByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		header.WriteByte(char)
+		if limits.MaxHeaderBytes > 0 && header.Len() > limits.MaxHeaderBytes {
+			return nil, &EMLLimitError{Kind: EMLLimitHeaderBytes, Limit: limits.MaxHeaderBytes}
+		}
+		switch char {
+		case '\n':
+			// An empty line ends the header section.
+			if lineLength == 0 {
+				return io.MultiReader(header, bufReader), nil
+			}
+			lineLength = 0
+		case '\r':
+		default:
+			if lineLength == 0 && char != ' ' && char != '\t' {
+				count++
+				if limits.MaxHeaderCount > 0 && count > limits.MaxHeaderCount {
+					return nil, &EMLLimitError{Kind: EMLLimitHeaderCount, Limit: limits.MaxHeaderCount}
+				}
+			}
+			lineLength++
+			if limits.MaxHeaderLineLength > 0 && lineLength > limits.MaxHeaderLineLength {
+				return nil, &EMLLimitError{Kind: EMLLimitHeaderLineLength, Limit: limits.MaxHeaderLineLength}
+			}
+		}
+	}
+	return io.MultiReader(header, bufReader), nil
+}
+
+// countEMLPart counts a MIME part of the EML and checks the number of parts against the limit.
+//
+// Returns:
+//   - An EMLLimitError if the number of parts exceeds the limit; otherwise, returns nil.
+func (o *emlOptions) countEMLPart() error {
+	o.parts++
+	if o.limits.MaxParts > 0 && o.parts > o.limits.MaxParts {
+		return &EMLLimitError{Kind: EMLLimitParts, Limit: o.limits.MaxParts}
+	}
+	return nil
+}
+
+// checkEMLDepth checks the nesting depth of a multipart body of the EML against the limit.
+//
+// Parameters:
+//   - depth: The nesting depth of the multipart body.
+//
+// Returns:
+//   - An EMLLimitError if the depth exceeds the limit; otherwise, returns nil.
+func (o *emlOptions) checkEMLDepth(depth int) error {
+	if o.limits.MaxDepth > 0 && depth > o.limits.MaxDepth {
+		return &EMLLimitError{Kind: EMLLimitDepth, Limit: o.limits.MaxDepth}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// exampleMailNested is a multipart EML with a multipart/alternative body nested in a
+// multipart/mixed body, which holds three MIME parts in total
+const exampleMailNested = "Date: Tue, 01 Oct 2024 12:00:00 +0000\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"From: <valid-from@domain.tld>\r\n" +
+	"To: <valid-to@domain.tld>\r\n" +
+	"Subject: Testmail\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=\"inner\"\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: 7bit\r\n" +
+	"\r\n" +
+	"Testmail\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: 7bit\r\n" +
+	"\r\n" +
+	"<p>Testmail</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer--\r\n"
+
+func TestWithEMLLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits EMLLimits
+		kind   EMLLimitKind
+		fails  bool
+	}{
+		{"no limits", EMLLimits{}, 0, false},
+		{"limits not exceeded", EMLLimits{
+			MaxHeaderCount: 6, MaxHeaderBytes: 1024, MaxHeaderLineLength: 64, MaxParts: 3, MaxDepth: 2,
+		}, 0, false},
+		{"header count exceeded", EMLLimits{MaxHeaderCount: 5}, EMLLimitHeaderCount, true},
+		{"header bytes exceeded", EMLLimits{MaxHeaderBytes: 100}, EMLLimitHeaderBytes, true},
+		{"header line length exceeded", EMLLimits{MaxHeaderLineLength: 40}, EMLLimitHeaderLineLength, true},
+		{"parts exceeded", EMLLimits{MaxParts: 2}, EMLLimitParts, true},
+		{"depth exceeded", EMLLimits{MaxDepth: 1}, EMLLimitDepth, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := EMLToMsgFromString(exampleMailNested, WithEMLLimits(tt.limits))
+			if !tt.fails {
+				if err != nil {
+					t.Fatalf("failed to parse EML: %s", err)
+				}
+				if len(msg.GetParts()) != 2 {
+					t.Errorf("expected 2 parts, got: %d", len(msg.GetParts()))
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected parsing of EML to fail due to exceeded limit")
+			}
+			var limitErr *EMLLimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("expected error to be of type *EMLLimitError, got: %T", err)
+			}
+			if limitErr.Kind != tt.kind {
+				t.Errorf("expected limit kind to be %q, got: %q", tt.kind, limitErr.Kind)
+			}
+			if !errors.Is(err, &EMLLimitError{Kind: tt.kind}) {
+				t.Error("expected error to match an EMLLimitError of the same kind")
+			}
+			if !strings.Contains(err.Error(), tt.kind.String()) {
+				t.Errorf("expected error message to mention the limit, got: %s", err)
+			}
+		})
+	}
+	t.Run("folded header lines count as one header field", func(t *testing.T) {
+		eml := strings.Replace(exampleMailNested, "Subject: Testmail\r\n", "Subject: Test\r\n mail\r\n", 1)
+		if _, err := EMLToMsgFromString(eml, WithEMLLimits(EMLLimits{MaxHeaderCount: 6})); err != nil {
+			t.Errorf("failed to parse EML with folded header: %s", err)
+		}
+	})
+	t.Run("limits apply in lenient mode", func(t *testing.T) {
+		_, err := EMLToMsgFromString(exampleMailLegacy, WithLenientParsing(),
+			WithEMLLimits(EMLLimits{MaxHeaderCount: 2}))
+		if !errors.Is(err, &EMLLimitError{Kind: EMLLimitHeaderCount}) {
+			t.Errorf("expected header count limit to be exceeded, got: %s", err)
+		}
+	})
+}
+
+func TestEMLLimitKind_String(t *testing.T) {
+	tests := []struct {
+		kind EMLLimitKind
+		want string
+	}{
+		{EMLLimitHeaderCount, "number of header fields"},
+		{EMLLimitHeaderBytes, "size of the header section"},
+		{EMLLimitHeaderLineLength, "length of a header line"},
+		{EMLLimitParts, "number of MIME parts"},
+		{EMLLimitDepth, "nesting depth of multipart bodies"},
+		{EMLLimitKind(-1), "unknown limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.kind.String(); got != tt.want {
+				t.Errorf("expected %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
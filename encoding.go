@@ -26,6 +26,15 @@ const (
 	// https://datatracker.ietf.org/doc/html/rfc2045#section-6.8
 	EncodingB64 Encoding = "base64"
 
+	// EncodingBinary represents the binary encoding as specified in RFC 2045. Binary content is
+	// only sent unencoded to SMTP servers that support the BINARYMIME and CHUNKING extensions and
+	// is re-encoded to Base64 otherwise.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc2045#section-2.9
+	//
+	// https://datatracker.ietf.org/doc/html/rfc3030
+	EncodingBinary Encoding = "binary"
+
 	// EncodingQP represents the "quoted-printable" encoding as specified in RFC 2045.
 	//
 	// https://datatracker.ietf.org/doc/html/rfc2045#section-6.7
@@ -149,6 +158,9 @@ const (
 	// TypeAppOctetStream represents the MIME type for arbitrary binary data.
 	TypeAppOctetStream ContentType = "application/octet-stream"
 
+	// TypeMessageRFC822 represents the MIME type for an encapsulated message, i. e. a forwarded Msg.
+	TypeMessageRFC822 ContentType = "message/rfc822"
+
 	// TypeMultipartAlternative represents the MIME type for a message body that can contain multiple alternative
 	// formats.
 	TypeMultipartAlternative ContentType = "multipart/alternative"
@@ -166,6 +178,9 @@ const (
 	// TypePGPEncrypted represents the MIME type for PGP encrypted messages.
 	TypePGPEncrypted ContentType = "application/pgp-encrypted"
 
+	// TypeTextCalendar represents the MIME type for iCalendar content.
+	TypeTextCalendar ContentType = "text/calendar"
+
 	// TypeTextHTML represents the MIME type for HTML text content.
 	TypeTextHTML ContentType = "text/html"
 
@@ -37,6 +37,7 @@ func TestContentType_String(t *testing.T) {
 	}{
 		{"ContentType: text/plain", TypeTextPlain, "text/plain"},
 		{"ContentType: text/html", TypeTextHTML, "text/html"},
+		{"ContentType: text/calendar", TypeTextCalendar, "text/calendar"},
 		{
 			"ContentType: application/octet-stream", TypeAppOctetStream,
 			"application/octet-stream",
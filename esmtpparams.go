@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidESMTPParamKey is returned if the keyword of an ESMTP parameter is not a valid
+	// esmtp-keyword as defined in RFC 5321.
+	ErrInvalidESMTPParamKey = errors.New("invalid ESMTP parameter keyword")
+
+	// ErrInvalidESMTPParamValue is returned if the value of an ESMTP parameter is not valid xtext as
+	// defined in RFC 3461.
+	ErrInvalidESMTPParamValue = errors.New("invalid ESMTP parameter value")
+
+	// ErrReservedESMTPParam is returned if an ESMTP parameter is added that is managed by the Client,
+	// i. e. "BODY" or "NOTIFY".
+	ErrReservedESMTPParam = errors.New("ESMTP parameter is managed by the client")
+)
+
+// reservedMailFromParams holds the parameters of the MAIL FROM command that are set by the Client.
+var reservedMailFromParams = map[string]bool{
+	"BODY": true, "REQUIRETLS": true, "RET": true, "SMTPUTF8": true,
+}
+
+// reservedRcptParams holds the parameters of the RCPT TO command that are set by the Client.
+var reservedRcptParams = map[string]bool{"NOTIFY": true}
+
+// AddMailFromParam adds an ESMTP parameter to the MAIL FROM command that is issued when the Msg is
+// sent, i. e. "AUTH=<>" or the parameter of a custom MTA extension.
+//
+// The keyword must be a valid esmtp-keyword and the value, if any, must be valid xtext. Values with
+// other characters can be encoded with XTextEncode. Parameters that are managed by the Client, like
+// "BODY", "REQUIRETLS", "RET" or "SMTPUTF8", cannot be added. The server must support the extension
+// the parameter belongs to, otherwise it will reject the command.
+//
+// Parameters:
+//   - key: The keyword of the ESMTP parameter.
+//   - value: The value of the ESMTP parameter, or an empty string for a parameter without value.
+//
+// Returns:
+//   - An error if the keyword or the value is invalid or the parameter is managed by the Client.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5321#section-4.1.2
+//   - https://datatracker.ietf.org/doc/html/rfc3461#section-4
+func (m *Msg) AddMailFromParam(key, value string) error {
+	param, err := formatESMTPParam(key, value, reservedMailFromParams)
+	if err != nil {
+		return err
+	}
+	m.mailFromParams = append(m.mailFromParams, param)
+	return nil
+}
+
+// AddRcptParam adds an ESMTP parameter to the RCPT TO command that is issued for the given recipient
+// address when the Msg is sent.
+//
+// The keyword and the value are validated like with AddMailFromParam. The "NOTIFY" parameter is
+// managed by the Client and cannot be added. The address is matched case-insensitively against the
+// recipients of the Msg.
+//
+// Parameters:
+//   - addr: The recipient address the parameter applies to.
+//   - key: The keyword of the ESMTP parameter.
+//   - value: The value of the ESMTP parameter, or an empty string for a parameter without value.
+//
+// Returns:
+//   - An error if the keyword or the value is invalid or the parameter is managed by the Client.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5321#section-4.1.2
+func (m *Msg) AddRcptParam(addr, key, value string) error {
+	param, err := formatESMTPParam(key, value, reservedRcptParams)
+	if err != nil {
+		return err
+	}
+	if m.rcptParams == nil {
+		m.rcptParams = make(map[string][]string)
+	}
+	addr = strings.ToLower(addr)
+	m.rcptParams[addr] = append(m.rcptParams[addr], param)
+	return nil
+}
+
+// XTextEncode encodes the given value as xtext, so that it can be used as value of an ESMTP
+// parameter. Characters outside the printable US-ASCII range, as well as "+" and "=", are encoded
+// as "+" followed by their hexadecimal value.
+//
+// Parameters:
+//   - value: The value to encode.
+//
+// Returns:
+//   - The xtext encoded value.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc3461#section-4
+func XTextEncode(value string) string {
+	builder := strings.Builder{}
+	for i := 0; i < len(value); i++ {
+		char := value[i]
+		if char < '!' || char > '~' || char == '+' || char == '=' {
+			_, _ = fmt.Fprintf(&builder, "+%02X", char)
+			continue
+		}
+		builder.WriteByte(char)
+	}
+	return builder.String()
+}
+
+// formatESMTPParam validates the given keyword and value of an ESMTP parameter and returns the
+// parameter formatted as "KEY" or "KEY=value".
+func formatESMTPParam(key, value string, reserved map[string]bool) (string, error) {
+	if !isESMTPKeyword(key) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidESMTPParamKey, key)
+	}
+	key = strings.ToUpper(key)
+	if reserved[key] {
+		return "", fmt.Errorf("%w: %s", ErrReservedESMTPParam, key)
+	}
+	if value == "" {
+		return key, nil
+	}
+	if !isXText(value) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidESMTPParamValue, value)
+	}
+	return key + "=" + value, nil
+}
+
+// isESMTPKeyword returns true if the given keyword consists of a letter or digit, followed by letters,
+// digits or hyphens.
+func isESMTPKeyword(key string) bool {
+	if key == "" || key[0] == '-' {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		char := key[i]
+		if (char < 'a' || char > 'z') && (char < 'A' || char > 'Z') && (char < '0' || char > '9') &&
+			char != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// isXText returns true if the given value only consists of printable US-ASCII characters other than
+// "+" and "=", and of "+" followed by two upper-case hexadecimal digits.
+func isXText(value string) bool {
+	for i := 0; i < len(value); i++ {
+		char := value[i]
+		switch {
+		case char == '+':
+			if i+2 >= len(value) || !isUpperHexDigit(value[i+1]) || !isUpperHexDigit(value[i+2]) {
+				return false
+			}
+			i += 2
+		case char < '!' || char > '~' || char == '=':
+			return false
+		}
+	}
+	return true
+}
+
+// isUpperHexDigit returns true if the given character is a digit or an upper-case letter from A to F.
+func isUpperHexDigit(char byte) bool {
+	return (char >= '0' && char <= '9') || (char >= 'A' && char <= 'F')
+}
+
+// rcptParamsFor returns the ESMTP parameters of the RCPT TO command for the given recipient address.
+func (m *Msg) rcptParamsFor(addr string) []string {
+	if m.rcptParams == nil {
+		return nil
+	}
+	return m.rcptParams[strings.ToLower(addr)]
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMsg_AddMailFromParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    string
+		wantErr error
+	}{
+		{"parameter with value", "auth", "<>", "AUTH=<>", nil},
+		{"parameter without value", "XCUSTOM", "", "XCUSTOM", nil},
+		{"parameter with xtext encoded value", "AUTH", "user+2Bname@domain.tld", "AUTH=user+2Bname@domain.tld", nil},
+		{"empty keyword fails", "", "value", "", ErrInvalidESMTPParamKey},
+		{"keyword with invalid character fails", "X_CUSTOM", "value", "", ErrInvalidESMTPParamKey},
+		{"keyword starting with hyphen fails", "-X", "value", "", ErrInvalidESMTPParamKey},
+		{"value with space fails", "AUTH", "a b", "", ErrInvalidESMTPParamValue},
+		{"value with equal sign fails", "AUTH", "a=b", "", ErrInvalidESMTPParamValue},
+		{"value with invalid xtext fails", "AUTH", "a+2b", "", ErrInvalidESMTPParamValue},
+		{"reserved parameter fails", "body", "8BITMIME", "", ErrReservedESMTPParam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := NewMsg()
+			err := message.AddMailFromParam(tt.key, tt.value)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error: %s, got: %s", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to add parameter: %s", err)
+			}
+			if len(message.mailFromParams) != 1 || message.mailFromParams[0] != tt.want {
+				t.Errorf("unexpected parameters, want: %s, got: %v", tt.want, message.mailFromParams)
+			}
+		})
+	}
+}
+
+func TestMsg_AddRcptParam(t *testing.T) {
+	message := NewMsg()
+	if err := message.AddRcptParam("Toni@Domain.tld", "ORCPT", "rfc822;toni@domain.tld"); err != nil {
+		t.Fatalf("failed to add parameter: %s", err)
+	}
+	if err := message.AddRcptParam("toni@domain.tld", "NOTIFY", "NEVER"); !errors.Is(err, ErrReservedESMTPParam) {
+		t.Errorf("expected error: %s, got: %s", ErrReservedESMTPParam, err)
+	}
+	if params := message.rcptParamsFor("toni@domain.tld"); len(params) != 1 ||
+		params[0] != "ORCPT=rfc822;toni@domain.tld" {
+		t.Errorf("unexpected parameters: %v", params)
+	}
+	if params := message.rcptParamsFor("other@domain.tld"); len(params) != 0 {
+		t.Errorf("expected no parameters for other recipient, got: %v", params)
+	}
+}
+
+func TestXTextEncode(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"toni@domain.tld", "toni@domain.tld"},
+		{"a+b=c", "a+2Bb+3Dc"},
+		{"a b\x7f", "a+20b+7F"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := XTextEncode(tt.value); got != tt.want {
+				t.Errorf("unexpected xtext encoding, want: %q, got: %q", tt.want, got)
+			}
+			if !isXText(XTextEncode(tt.value)) {
+				t.Errorf("expected encoded value to be valid xtext")
+			}
+		})
+	}
+}
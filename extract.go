@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrNoExtractor is returned if no Extractor is registered for the content type of a File.
+	ErrNoExtractor = errors.New("no text extractor registered for content type")
+
+	// ErrFileWriterIsNil is returned if a File does not have a writer function to read its content from.
+	ErrFileWriterIsNil = errors.New("file has no content writer")
+)
+
+var (
+	// extractors maps the lower-case content type to the registered Extractor.
+	extractors = map[ContentType]Extractor{
+		TypeTextPlain: ExtractorFunc(extractPlainText),
+		TypeTextHTML:  ExtractorFunc(extractHTMLText),
+	}
+
+	// extractorMutex synchronizes the access to the extractors registry.
+	extractorMutex sync.RWMutex
+)
+
+type (
+	// Extractor is an interface for extracting searchable text from the content of a File.
+	//
+	// Extractors are registered per content type with RegisterExtractor and used by File.ExtractText.
+	// This allows the text of PDF or Office attachments of parsed messages to be indexed with
+	// user-provided extraction libraries, while go-mail itself only ships extractors for plain text and
+	// HTML.
+	Extractor interface {
+		// ExtractText returns the text of the content read from the given io.Reader. Implementations
+		// should stop early and return the error of the context.Context if it is cancelled.
+		ExtractText(ctx context.Context, reader io.Reader) (string, error)
+	}
+
+	// ExtractorFunc is an adapter to allow the use of ordinary functions as Extractor.
+	ExtractorFunc func(ctx context.Context, reader io.Reader) (string, error)
+)
+
+// ExtractText satisfies the Extractor interface for the ExtractorFunc type by calling the function.
+func (f ExtractorFunc) ExtractText(ctx context.Context, reader io.Reader) (string, error) {
+	return f(ctx, reader)
+}
+
+// RegisterExtractor registers the Extractor for the given content type.
+//
+// The content type is case-insensitive. Registering an Extractor for a content type that already has
+// one overrides the existing Extractor, including the built-in ones for "text/plain" and "text/html".
+// Registering a nil Extractor removes the Extractor for the content type. RegisterExtractor is safe for
+// concurrent use.
+//
+// Parameters:
+//   - contentType: The content type the Extractor handles, i. e. "application/pdf".
+//   - extractor: The Extractor for the content type.
+func RegisterExtractor(contentType ContentType, extractor Extractor) {
+	contentType = ContentType(strings.ToLower(strings.TrimSpace(string(contentType))))
+	if contentType == "" {
+		return
+	}
+	extractorMutex.Lock()
+	defer extractorMutex.Unlock()
+	if extractor == nil {
+		delete(extractors, contentType)
+		return
+	}
+	extractors[contentType] = extractor
+}
+
+// ExtractText returns the searchable text of the File using the Extractor registered for the
+// content type of the File.
+//
+// The content of the File is streamed into the Extractor, so that large attachments do not have to be
+// held in memory. For files of parsed messages, the content is already decoded from its transfer
+// encoding.
+//
+// Parameters:
+//   - ctx: The context.Context that is passed to the Extractor.
+//
+// Returns:
+//   - The extracted text of the File.
+//   - An error wrapping ErrNoExtractor if no Extractor is registered for the content type, or the error
+//     of the Extractor or the File content writer; otherwise, returns nil.
+func (f *File) ExtractText(ctx context.Context) (string, error) {
+	contentType := ContentType(strings.ToLower(string(f.ContentType)))
+	if index := strings.IndexByte(string(contentType), ';'); index >= 0 {
+		contentType = ContentType(strings.TrimSpace(string(contentType[:index])))
+	}
+	extractorMutex.RLock()
+	extractor, ok := extractors[contentType]
+	extractorMutex.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNoExtractor, contentType)
+	}
+	if f.Writer == nil {
+		return "", ErrFileWriterIsNil
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		_, err := f.Writer(writer)
+		_ = writer.CloseWithError(err)
+	}()
+	text, err := extractor.ExtractText(ctx, reader)
+	_ = reader.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to extract text of %s: %w", f.Name, err)
+	}
+	return text, nil
+}
+
+// extractPlainText is the built-in Extractor for "text/plain" content.
+func extractPlainText(_ context.Context, reader io.Reader) (string, error) {
+	buffer := bytes.NewBuffer(nil)
+	if _, err := buffer.ReadFrom(reader); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// extractHTMLText is the built-in Extractor for "text/html" content.
+func extractHTMLText(ctx context.Context, reader io.Reader) (string, error) {
+	document, err := extractPlainText(ctx, reader)
+	if err != nil {
+		return "", err
+	}
+	return htmlToPlainText(document), nil
+}
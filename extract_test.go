@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFile_ExtractText(t *testing.T) {
+	t.Run("plain text attachment", func(t *testing.T) {
+		message := NewMsg()
+		if err := message.AttachReader("notes.txt", strings.NewReader("Meeting notes"),
+			WithFileContentType(TypeTextPlain)); err != nil {
+			t.Fatalf("failed to attach reader: %s", err)
+		}
+		text, err := message.GetAttachments()[0].ExtractText(context.Background())
+		if err != nil {
+			t.Fatalf("failed to extract text: %s", err)
+		}
+		if text != "Meeting notes" {
+			t.Errorf("unexpected text, want: %q, got: %q", "Meeting notes", text)
+		}
+	})
+	t.Run("registered extractor of parsed message", func(t *testing.T) {
+		RegisterExtractor("Application/X-Test", ExtractorFunc(func(_ context.Context, reader io.Reader) (string, error) {
+			data, err := io.ReadAll(reader)
+			return strings.ToUpper(string(data)), err
+		}))
+		t.Cleanup(func() { RegisterExtractor("application/x-test", nil) })
+		message, err := EMLToMsgFromString("From: <valid-from@domain.tld>\r\nTo: <valid-to@domain.tld>\r\n" +
+			"Subject: Attachment\r\nMIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=\"b\"\r\n\r\n" +
+			"--b\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\nSee attachment\r\n" +
+			"--b\r\nContent-Type: application/x-test; name=\"doc.test\"\r\nContent-Transfer-Encoding: base64\r\n" +
+			"Content-Disposition: attachment; filename=\"doc.test\"\r\n\r\nc2VhcmNoYWJsZSB0ZXh0\r\n--b--\r\n")
+		if err != nil {
+			t.Fatalf("failed to parse EML: %s", err)
+		}
+		attachments := message.GetAttachments()
+		if len(attachments) != 1 {
+			t.Fatalf("expected 1 attachment, got: %d", len(attachments))
+		}
+		text, err := attachments[0].ExtractText(context.Background())
+		if err != nil {
+			t.Fatalf("failed to extract text: %s", err)
+		}
+		if text != "SEARCHABLE TEXT" {
+			t.Errorf("unexpected text, want: %q, got: %q", "SEARCHABLE TEXT", text)
+		}
+	})
+	t.Run("extractor that stops reading early", func(t *testing.T) {
+		RegisterExtractor("application/x-head", ExtractorFunc(func(_ context.Context, reader io.Reader) (string, error) {
+			head := make([]byte, 4)
+			_, err := io.ReadFull(reader, head)
+			return string(head), err
+		}))
+		t.Cleanup(func() { RegisterExtractor("application/x-head", nil) })
+		file := &File{ContentType: "application/x-head", Name: "large.bin", Writer: func(w io.Writer) (int64, error) {
+			n, err := io.Copy(w, strings.NewReader(strings.Repeat("data", 100000)))
+			return n, err
+		}}
+		if text, err := file.ExtractText(context.Background()); err != nil || text != "data" {
+			t.Errorf("expected text %q, got: %q, %v", "data", text, err)
+		}
+	})
+	t.Run("HTML content is converted", func(t *testing.T) {
+		file := &File{ContentType: "text/html; charset=UTF-8", Writer: func(w io.Writer) (int64, error) {
+			n, err := io.WriteString(w, "<p>Hello <b>World</b></p>")
+			return int64(n), err
+		}}
+		if text, err := file.ExtractText(context.Background()); err != nil || text != "Hello World" {
+			t.Errorf("expected text %q, got: %q, %v", "Hello World", text, err)
+		}
+	})
+	t.Run("unknown content type fails", func(t *testing.T) {
+		file := &File{ContentType: TypeAppOctetStream, Writer: func(io.Writer) (int64, error) { return 0, nil }}
+		if _, err := file.ExtractText(context.Background()); !errors.Is(err, ErrNoExtractor) {
+			t.Errorf("expected error: %s, got: %s", ErrNoExtractor, err)
+		}
+	})
+	t.Run("file without writer fails", func(t *testing.T) {
+		file := &File{ContentType: TypeTextPlain}
+		if _, err := file.ExtractText(context.Background()); !errors.Is(err, ErrFileWriterIsNil) {
+			t.Errorf("expected error: %s, got: %s", ErrFileWriterIsNil, err)
+		}
+	})
+	t.Run("writer error is returned", func(t *testing.T) {
+		file := &File{ContentType: TypeTextPlain, Writer: func(io.Writer) (int64, error) {
+			return 0, errors.New("broken source")
+		}}
+		if _, err := file.ExtractText(context.Background()); err == nil || !strings.Contains(err.Error(),
+			"broken source") {
+			t.Errorf("expected writer error, got: %v", err)
+		}
+	})
+	t.Run("cancelled context fails", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		file := &File{ContentType: TypeTextPlain, Writer: func(io.Writer) (int64, error) { return 0, nil }}
+		if _, err := file.ExtractText(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected error: %s, got: %s", context.Canceled, err)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FallbackTransport is a Transport that sends the messages via a primary Transport and hands all
+// messages that could not be delivered by it to a fallback Transport, i. e. a SummaryTransport that
+// records them in a log file or syslog, so that alerts are never silently lost.
+type FallbackTransport struct {
+	// fallback is the Transport that receives the messages the primary Transport failed to deliver.
+	fallback Transport
+
+	// primary is the Transport that is used to send the messages.
+	primary Transport
+}
+
+// SummaryTransport is a Transport that writes a single line summary of each message to an io.Writer
+// instead of delivering it. It is meant to be used as fallback of a FallbackTransport.
+//
+// The summary holds the time, the Message-ID, the sender, the recipients, the subject and the
+// SendError of the message. Any io.Writer can be used, i. e. an os.File, a log/syslog.Writer or
+// os.Stderr of a service whose output is collected by journald. A SummaryTransport is safe for
+// concurrent use by multiple goroutines.
+type SummaryTransport struct {
+	// mutex serializes the writes to the writer.
+	mutex sync.Mutex
+
+	// writer is the io.Writer that the summaries are written to.
+	writer io.Writer
+}
+
+var (
+	// Ensure that the FallbackTransport and the SummaryTransport satisfy the Transport interface.
+	_ Transport = (*FallbackTransport)(nil)
+	_ Transport = (*SummaryTransport)(nil)
+)
+
+// WithFallbackTransport returns a FallbackTransport that sends the messages via the primary
+// Transport and hands the messages that could not be delivered to the fallback Transport.
+//
+// Parameters:
+//   - primary: The Transport that is used to send the messages, i. e. a Client.
+//   - fallback: The Transport that receives the undelivered messages, i. e. a SummaryTransport.
+//
+// Returns:
+//   - A pointer to the FallbackTransport.
+func WithFallbackTransport(primary, fallback Transport) *FallbackTransport {
+	return &FallbackTransport{fallback: fallback, primary: primary}
+}
+
+// SendWithContext sends the given messages via the primary Transport. If the primary Transport fails,
+// all messages that have not been delivered are sent via the fallback Transport. Their SendError
+// remains set, so that the failure of the primary Transport can still be inspected.
+//
+// Parameters:
+//   - ctx: The context.Context that controls the cancellation of the delivery.
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error that wraps the errors of both the primary and the fallback Transport if the fallback
+//     Transport fails as well; otherwise, returns nil.
+func (f *FallbackTransport) SendWithContext(ctx context.Context, messages ...*Msg) error {
+	primaryErr := f.primary.SendWithContext(ctx, messages...)
+	if primaryErr == nil {
+		return nil
+	}
+	var undelivered []*Msg
+	for _, message := range messages {
+		if message != nil && (message.SendError() != nil || !message.IsDelivered()) {
+			undelivered = append(undelivered, message)
+		}
+	}
+	if len(undelivered) == 0 {
+		return primaryErr
+	}
+	// The fallback must still record the messages if the delivery failed due to the context.
+	if err := f.fallback.SendWithContext(context.Background(), undelivered...); err != nil {
+		return fmt.Errorf("fallback transport failed: %w, primary transport failed: %s", err, primaryErr)
+	}
+	return nil
+}
+
+// NewSummaryTransport returns a new SummaryTransport that writes the message summaries to the given
+// io.Writer.
+//
+// Parameters:
+//   - writer: The io.Writer that the summaries are written to.
+//
+// Returns:
+//   - A pointer to the new SummaryTransport.
+func NewSummaryTransport(writer io.Writer) *SummaryTransport {
+	return &SummaryTransport{writer: writer}
+}
+
+// SendWithContext writes a summary line for each of the given messages.
+//
+// Parameters:
+//   - ctx: The context.Context; it is not used, since the summaries are written in any case.
+//   - messages: A variadic list of pointers to Msg objects to be summarized.
+//
+// Returns:
+//   - An error if the writer is nil or any of the summaries could not be written; otherwise,
+//     returns nil.
+func (s *SummaryTransport) SendWithContext(_ context.Context, messages ...*Msg) error {
+	if s.writer == nil {
+		return errors.New("summary writer is nil")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i, message := range messages {
+		if message == nil {
+			continue
+		}
+		if _, err := io.WriteString(s.writer, messageSummary(message)+"\n"); err != nil {
+			return fmt.Errorf("failed to write summary of message %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// messageSummary returns a single line summary of the given Msg. All values are quoted, so that the
+// summary cannot span multiple lines.
+func messageSummary(message *Msg) string {
+	from, _ := message.GetSender(false)
+	rcpts, _ := message.GetRecipients()
+	subject := ""
+	if values := message.GetGenHeader(HeaderSubject); len(values) > 0 {
+		subject = values[0]
+		if decoded, err := (&mime.WordDecoder{}).DecodeHeader(subject); err == nil {
+			subject = decoded
+		}
+	}
+	errMsg := ""
+	if err := message.SendError(); err != nil {
+		errMsg = err.Error()
+	}
+	return fmt.Sprintf("%s undelivered message: message-id=%q from=%q to=%q subject=%q error=%q",
+		time.Now().UTC().Format(time.RFC3339), message.GetMessageID(), from, strings.Join(rcpts, ","), subject,
+		errMsg)
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingTransport is a Transport that records the sent messages and returns the given error.
+type recordingTransport struct {
+	err      error
+	messages []*Msg
+}
+
+func (r *recordingTransport) SendWithContext(_ context.Context, messages ...*Msg) error {
+	r.messages = append(r.messages, messages...)
+	return r.err
+}
+
+func TestFallbackTransport_SendWithContext(t *testing.T) {
+	t.Run("primary transport succeeds", func(t *testing.T) {
+		primary, fallback := &recordingTransport{}, &recordingTransport{}
+		if err := WithFallbackTransport(primary, fallback).SendWithContext(context.Background(),
+			testMessage(t)); err != nil {
+			t.Fatalf("failed to send message: %s", err)
+		}
+		if len(primary.messages) != 1 || len(fallback.messages) != 0 {
+			t.Errorf("expected message to be sent via primary transport only, got: %d/%d",
+				len(primary.messages), len(fallback.messages))
+		}
+	})
+	t.Run("primary transport fails", func(t *testing.T) {
+		primary := &recordingTransport{err: errors.New("connection refused")}
+		fallback := &recordingTransport{}
+		if err := WithFallbackTransport(primary, fallback).SendWithContext(context.Background(),
+			testMessage(t), testMessage(t)); err != nil {
+			t.Fatalf("expected fallback transport to take over, got: %s", err)
+		}
+		if len(fallback.messages) != 2 {
+			t.Errorf("expected 2 messages in fallback transport, got: %d", len(fallback.messages))
+		}
+	})
+	t.Run("both transports fail", func(t *testing.T) {
+		fallbackErr := errors.New("disk full")
+		primary := &recordingTransport{err: errors.New("connection refused")}
+		fallback := &recordingTransport{err: fallbackErr}
+		err := WithFallbackTransport(primary, fallback).SendWithContext(context.Background(), testMessage(t))
+		if !errors.Is(err, fallbackErr) || !strings.Contains(err.Error(), "connection refused") {
+			t.Errorf("expected error of both transports, got: %s", err)
+		}
+	})
+	t.Run("only undelivered messages fall back", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(context.Background()); err != nil {
+			t.Fatalf("failed to connect to the test server: %s", err)
+		}
+		t.Cleanup(func() {
+			if err := client.Close(); err != nil {
+				t.Errorf("failed to close client: %s", err)
+			}
+		})
+
+		delivered := testMessage(t)
+		rejected := testMessage(t)
+		if err = rejected.To("invalid-to@domain.tld"); err != nil {
+			t.Fatalf("failed to set recipient address: %s", err)
+		}
+		buffer := bytes.NewBuffer(nil)
+		transport := WithFallbackTransport(client, NewSummaryTransport(buffer))
+		if err = transport.SendWithContext(context.Background(), delivered, rejected); err != nil {
+			t.Fatalf("expected fallback transport to take over, got: %s", err)
+		}
+		summary := buffer.String()
+		if strings.Count(summary, "\n") != 1 || !strings.Contains(summary, `to="invalid-to@domain.tld"`) {
+			t.Errorf("expected summary of the rejected message only, got: %s", summary)
+		}
+		if rejected.SendError() == nil {
+			t.Error("expected send error of the rejected message to remain set")
+		}
+	})
+}
+
+func TestSummaryTransport_SendWithContext(t *testing.T) {
+	t.Run("summary is written", func(t *testing.T) {
+		buffer := bytes.NewBuffer(nil)
+		message := testMessage(t)
+		message.Subject("Alert\r\nBcc: injected@domain.tld")
+		message.SetMessageIDWithValue("alert@domain.tld")
+		message.sendError = &SendError{Reason: ErrSMTPRcptTo, errlist: []error{errors.New("mailbox full")}}
+		var transport Transport = NewSummaryTransport(buffer)
+		if err := transport.SendWithContext(context.Background(), message, nil); err != nil {
+			t.Fatalf("failed to write summary: %s", err)
+		}
+		summary := buffer.String()
+		for _, want := range []string{
+			` undelivered message: message-id="<alert@domain.tld>" from="valid-from@domain.tld"`,
+			`to="valid-to@domain.tld"`, `subject="Alert\r\nBcc: injected@domain.tld"`, "mailbox full",
+		} {
+			if !strings.Contains(summary, want) {
+				t.Errorf("expected summary to contain %q, got: %s", want, summary)
+			}
+		}
+		if strings.Count(summary, "\n") != 1 {
+			t.Errorf("expected a single summary line, got: %s", summary)
+		}
+	})
+	t.Run("nil writer", func(t *testing.T) {
+		if err := NewSummaryTransport(nil).SendWithContext(context.Background(), testMessage(t)); err == nil {
+			t.Error("expected nil writer to fail")
+		}
+	})
+}
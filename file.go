@@ -7,11 +7,30 @@ package mail
 import (
 	"io"
 	"net/textproto"
+	"time"
 )
 
 // FileOption is a function type used to modify properties of a File
 type FileOption func(*File)
 
+// Disposition is a type wrapper for a string and represents the disposition type of the
+// Content-Disposition header of a File.
+type Disposition string
+
+const (
+	// DispositionAttachment indicates that the File is separate from the body of the Msg and should
+	// be offered for download.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc2183#section-2.2
+	DispositionAttachment Disposition = "attachment"
+
+	// DispositionInline indicates that the File is part of the body of the Msg and should be
+	// displayed automatically.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc2183#section-2.1
+	DispositionInline Disposition = "inline"
+)
+
 // File represents a file with properties such as content type, description, encoding, headers, name, and
 // a writer function.
 //
@@ -21,10 +40,20 @@ type FileOption func(*File)
 type File struct {
 	ContentType ContentType
 	Desc        string
+	Disposition Disposition
 	Enc         Encoding
 	Header      textproto.MIMEHeader
+	ModDate     time.Time
 	Name        string
+	Size        int64
 	Writer      func(w io.Writer) (int64, error)
+
+	// stream indicates that the content of the File is read from a one-time stream and can only be
+	// written once.
+	stream bool
+
+	// streamSize is the size of the stream content in bytes, or a negative value if it is unknown.
+	streamSize int64
 }
 
 // WithFileContentID sets the "Content-ID" header in the File's MIME headers to the specified ID.
@@ -82,7 +111,9 @@ func WithFileDescription(description string) FileOption {
 // default if needed.
 //
 // Note: Quoted-printable encoding (EncodingQP) must never be used for attachments or embeds. If EncodingQP
-// is passed to this function, it will be ignored and the encoding will remain unchanged.
+// is passed to this function, it will be ignored and the encoding will remain unchanged. For text-like files
+// of a Msg created with WithAutoFileEncoding, the encoding set with this function takes precedence over the
+// automatically chosen one.
 //
 // Parameters:
 //   - encoding: The Encoding type to be assigned to the File, unless it's EncodingQP.
@@ -104,6 +135,10 @@ func WithFileEncoding(encoding Encoding) FileOption {
 // the default "application/octet-stream" is used. This FileOption allows overriding the guessed content
 // type with a specific one if required.
 //
+// The content type is guessed from the extension of the file name with mime.TypeByExtension of the
+// standard library. Additional extensions, i. e. internal file formats or ".heic", can be registered
+// for all files once with mime.AddExtensionType, which is safe for concurrent use.
+//
 // Parameters:
 //   - contentType: The ContentType to be assigned to the File.
 //
@@ -115,6 +150,88 @@ func WithFileContentType(contentType ContentType) FileOption {
 	}
 }
 
+// WithFileSize sets the size of the File in bytes, which is used in the "size" parameter of the
+// Content-Disposition header of the MIME output.
+//
+// The size is informational and is not verified against the actual content of the File. A size of zero
+// or less omits the parameter.
+//
+// Parameters:
+//   - size: The size of the File in bytes.
+//
+// Returns:
+//   - A FileOption function that sets the File's size.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2183#section-2.7
+func WithFileSize(size int64) FileOption {
+	return func(f *File) {
+		f.Size = size
+	}
+}
+
+// WithFileModDate sets the modification date of the File, which is used in the "modification-date"
+// parameter of the Content-Disposition header of the MIME output.
+//
+// A zero time.Time omits the parameter.
+//
+// Parameters:
+//   - modDate: The time the File was last modified.
+//
+// Returns:
+//   - A FileOption function that sets the File's modification date.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2183#section-2.5
+func WithFileModDate(modDate time.Time) FileOption {
+	return func(f *File) {
+		f.ModDate = modDate
+	}
+}
+
+// WithFileDisposition sets the disposition type of the Content-Disposition header of the File.
+//
+// By default, attachments use the "attachment" and embeds the "inline" disposition type. This option
+// overrides the default independent of how the File was added to the Msg, so that an attachment, i. e.
+// a PDF, can be shown inline as preview, or an embedded image can be offered for download. Embeds keep
+// their Content-ID either way. Disposition types other than DispositionInline and DispositionAttachment
+// are ignored.
+//
+// Parameters:
+//   - disposition: The Disposition type to be assigned to the File.
+//
+// Returns:
+//   - A FileOption function that sets the File's disposition type.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2183#section-2
+func WithFileDisposition(disposition Disposition) FileOption {
+	return func(f *File) {
+		if disposition != DispositionInline && disposition != DispositionAttachment {
+			return
+		}
+		f.Disposition = disposition
+	}
+}
+
+// disposition returns the disposition type of the File. If no disposition type was set with
+// WithFileDisposition, it returns the default disposition type for an attachment or embed.
+//
+// Parameters:
+//   - isAttachment: A boolean indicating whether the File is an attachment (true) or an embed (false).
+//
+// Returns:
+//   - The Disposition type of the File.
+func (f *File) disposition(isAttachment bool) Disposition {
+	if f.Disposition != "" {
+		return f.Disposition
+	}
+	if isAttachment {
+		return DispositionAttachment
+	}
+	return DispositionInline
+}
+
 // setHeader sets the value of a specified MIME header field for the File.
 //
 // This method updates the MIME headers of the File by assigning the provided value to the specified
@@ -4,7 +4,12 @@
 
 package mail
 
-import "testing"
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestFile(t *testing.T) {
 	t.Run("setHeader", func(t *testing.T) {
@@ -185,4 +190,73 @@ func TestFile(t *testing.T) {
 			})
 		}
 	})
+	t.Run("WithFileSize and WithFileModDate", func(t *testing.T) {
+		modDate := time.Date(1997, time.February, 12, 16, 29, 51, 0, time.FixedZone("EST", -5*60*60))
+		message := testMessage(t)
+		message.AttachReader("report.txt", strings.NewReader("report"), WithFileSize(6),
+			WithFileModDate(modDate))
+		attachments := message.GetAttachments()
+		if len(attachments) != 1 {
+			t.Fatalf("failed to retrieve attachments list")
+		}
+		if attachments[0].Size != 6 || !attachments[0].ModDate.Equal(modDate) {
+			t.Errorf("unexpected size or modification date: %d, %s", attachments[0].Size, attachments[0].ModDate)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		want := `Content-Disposition: attachment; filename="report.txt"; size=6; ` +
+			`modification-date="Wed, 12 Feb 1997 16:29:51 -0500"`
+		if !strings.Contains(buffer.String(), want) {
+			t.Errorf("expected Content-Disposition with size and modification date, got: %s", buffer.String())
+		}
+
+		parsed, err := EMLToMsgFromReader(buffer)
+		if err != nil {
+			t.Fatalf("failed to parse message: %s", err)
+		}
+		attachments = parsed.GetAttachments()
+		if len(attachments) != 1 {
+			t.Fatalf("expected 1 parsed attachment, got: %d", len(attachments))
+		}
+		if attachments[0].Size != 6 || !attachments[0].ModDate.Equal(modDate) {
+			t.Errorf("unexpected parsed size or modification date: %d, %s", attachments[0].Size,
+				attachments[0].ModDate)
+		}
+	})
+	t.Run("WithFileDisposition", func(t *testing.T) {
+		message := testMessage(t)
+		message.AttachReader("preview.pdf", strings.NewReader("pdf"), WithFileDisposition(DispositionInline))
+		message.EmbedReader("photo.jpg", strings.NewReader("jpg"), WithFileDisposition(DispositionAttachment))
+		message.AttachReader("report.txt", strings.NewReader("report"), WithFileDisposition("invalid"))
+		if message.GetAttachments()[1].Disposition != "" {
+			t.Errorf("expected invalid disposition to be ignored, got: %s", message.GetAttachments()[1].Disposition)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		for _, want := range []string{
+			`Content-Disposition: inline; filename="preview.pdf"`,
+			`Content-Disposition: attachment; filename="photo.jpg"`,
+			`Content-Disposition: attachment; filename="report.txt"`,
+			"Content-Id: <photo.jpg>",
+		} {
+			if !strings.Contains(buffer.String(), want) {
+				t.Errorf("expected %q in message, got: %s", want, buffer.String())
+			}
+		}
+	})
+	t.Run("WithFileSize of zero is omitted", func(t *testing.T) {
+		message := testMessage(t)
+		message.AttachReader("report.txt", strings.NewReader("report"), WithFileSize(0))
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if strings.Contains(buffer.String(), "size=") || strings.Contains(buffer.String(), "modification-date") {
+			t.Errorf("expected no size or modification date parameters, got: %s", buffer.String())
+		}
+	})
 }
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ErrRecipientsGraylisted is returned if all recipients of a Msg are on the recipient graylist of
+// the Client.
+var ErrRecipientsGraylisted = errors.New("all recipients were permanently rejected earlier")
+
+// WithRecipientGraylist enables the recipient graylist of the Client.
+//
+// Within a bulk run, i. e. a digest followed by a reminder to the same list, a recipient that is
+// permanently rejected by the server will be rejected again for each following Msg. With the
+// graylist, the Client remembers each recipient that the server rejected with a 5xx reply to the
+// RCPT TO command, and skips it for all following messages. The skipped recipients of a Msg are
+// reported in SendResult.SkippedRecipients. If all recipients of a Msg are skipped, the delivery
+// is aborted with a SendError of the reason ErrRcptGraylisted.
+//
+// The graylist is kept for the lifetime of the Client, also across reconnects, until it is cleared
+// with ResetRecipientGraylist.
+//
+// Returns:
+//   - An Option function that enables the recipient graylist for the Client.
+func WithRecipientGraylist() Option {
+	return func(c *Client) error {
+		c.rcptGraylist = make(map[string]struct{})
+		return nil
+	}
+}
+
+// GraylistedRecipients returns the recipients on the recipient graylist of the Client.
+//
+// Returns:
+//   - A sorted slice of the lower-cased addresses of the recipients that have been permanently
+//     rejected, or nil if the graylist is empty or not enabled with WithRecipientGraylist.
+func (c *Client) GraylistedRecipients() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if len(c.rcptGraylist) == 0 {
+		return nil
+	}
+	recipients := make([]string, 0, len(c.rcptGraylist))
+	for recipient := range c.rcptGraylist {
+		recipients = append(recipients, recipient)
+	}
+	sort.Strings(recipients)
+	return recipients
+}
+
+// ResetRecipientGraylist removes all recipients from the recipient graylist of the Client, i. e.
+// before the start of a new bulk run. It is a no-op, if the graylist is not enabled with
+// WithRecipientGraylist.
+func (c *Client) ResetRecipientGraylist() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.rcptGraylist != nil {
+		c.rcptGraylist = make(map[string]struct{})
+	}
+}
+
+// skipGraylisted splits the given recipients into those that are to be sent and those that are on
+// the recipient graylist. The caller must hold the mutex of the Client.
+//
+// Parameters:
+//   - rcpts: The recipient addresses of a Msg.
+//
+// Returns:
+//   - The recipients that are not on the graylist.
+//   - The recipients that are on the graylist, or nil if none are.
+func (c *Client) skipGraylisted(rcpts []string) ([]string, []string) {
+	if len(c.rcptGraylist) == 0 {
+		return rcpts, nil
+	}
+	var skipped []string
+	remaining := make([]string, 0, len(rcpts))
+	for _, rcpt := range rcpts {
+		if _, ok := c.rcptGraylist[strings.ToLower(rcpt)]; ok {
+			skipped = append(skipped, rcpt)
+			continue
+		}
+		remaining = append(remaining, rcpt)
+	}
+	return remaining, skipped
+}
+
+// graylistRecipient adds the given recipient to the recipient graylist, if the graylist is enabled
+// and the server rejected it permanently. The caller must hold the mutex of the Client.
+//
+// Parameters:
+//   - rcpt: The recipient address.
+//   - code: The SMTP reply code of the RCPT TO command.
+func (c *Client) graylistRecipient(rcpt string, code int) {
+	if c.rcptGraylist == nil || code < 500 || code > 599 {
+		return
+	}
+	c.rcptGraylist[strings.ToLower(rcpt)] = struct{}{}
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithRecipientGraylist(t *testing.T) {
+	t.Run("graylist is disabled by default", func(t *testing.T) {
+		client, err := NewClient(DefaultHost)
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		client.graylistRecipient("invalid@domain.tld", 550)
+		if recipients := client.GraylistedRecipients(); recipients != nil {
+			t.Errorf("expected no graylisted recipients, got: %v", recipients)
+		}
+		rcpts, skipped := client.skipGraylisted([]string{"invalid@domain.tld"})
+		if len(rcpts) != 1 || skipped != nil {
+			t.Errorf("expected no skipped recipients, got: %v", skipped)
+		}
+	})
+	t.Run("only permanent rejections are graylisted", func(t *testing.T) {
+		client, err := NewClient(DefaultHost, WithRecipientGraylist())
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		client.graylistRecipient("Full@domain.tld", 452)
+		client.graylistRecipient("Unknown@domain.tld", 550)
+		client.graylistRecipient("reset@domain.tld", 0)
+		recipients := client.GraylistedRecipients()
+		if len(recipients) != 1 || recipients[0] != "unknown@domain.tld" {
+			t.Errorf("expected graylisted recipient unknown@domain.tld, got: %v", recipients)
+		}
+		rcpts, skipped := client.skipGraylisted([]string{"full@domain.tld", "UNKNOWN@domain.tld"})
+		if len(rcpts) != 1 || rcpts[0] != "full@domain.tld" {
+			t.Errorf("expected remaining recipient full@domain.tld, got: %v", rcpts)
+		}
+		if len(skipped) != 1 || skipped[0] != "UNKNOWN@domain.tld" {
+			t.Errorf("expected skipped recipient UNKNOWN@domain.tld, got: %v", skipped)
+		}
+		client.ResetRecipientGraylist()
+		if recipients = client.GraylistedRecipients(); recipients != nil {
+			t.Errorf("expected no graylisted recipients after reset, got: %v", recipients)
+		}
+	})
+	t.Run("rejected recipients are skipped for following messages", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS), WithRecipientGraylist())
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(context.Background()); err != nil {
+			t.Fatalf("failed to connect to the test server: %s", err)
+		}
+		t.Cleanup(func() {
+			if err := client.Close(); err != nil {
+				t.Errorf("failed to close client: %s", err)
+			}
+		})
+
+		digest := testMessage(t)
+		if err = digest.AddTo("invalid@domain.tld"); err != nil {
+			t.Fatalf("failed to add recipient: %s", err)
+		}
+		followUp := testMessage(t)
+		if err = followUp.AddTo("Invalid@domain.tld"); err != nil {
+			t.Fatalf("failed to add recipient: %s", err)
+		}
+		single := testMessage(t)
+		if err = single.To("invalid@domain.tld"); err != nil {
+			t.Fatalf("failed to set recipient: %s", err)
+		}
+		results, err := client.SendWithResults(digest, followUp, single)
+		if err == nil {
+			t.Fatal("expected send to fail for invalid recipient")
+		}
+
+		var sendErr *SendError
+		if !errors.As(results[0].Err, &sendErr) || sendErr.Reason != ErrSMTPRcptTo {
+			t.Errorf("expected SendError with reason %s, got: %s", ErrSMTPRcptTo, results[0].Err)
+		}
+		if results[0].SkippedRecipients != nil {
+			t.Errorf("expected no skipped recipients, got: %v", results[0].SkippedRecipients)
+		}
+		if results[1].Err != nil {
+			t.Errorf("expected follow-up to be delivered, got: %s", results[1].Err)
+		}
+		if skipped := results[1].SkippedRecipients; len(skipped) != 1 || skipped[0] != "Invalid@domain.tld" {
+			t.Errorf("expected skipped recipient Invalid@domain.tld, got: %v", skipped)
+		}
+		if len(results[1].Recipients) != 1 || results[1].Recipients[0].Address != "valid-to@domain.tld" {
+			t.Errorf("expected only valid-to@domain.tld to be sent, got: %+v", results[1].Recipients)
+		}
+		if !errors.As(results[2].Err, &sendErr) || sendErr.Reason != ErrRcptGraylisted {
+			t.Errorf("expected SendError with reason %s, got: %s", ErrRcptGraylisted, results[2].Err)
+		}
+		if rcpts := sendErr.rcpt; len(rcpts) != 1 || rcpts[0] != "invalid@domain.tld" {
+			t.Errorf("expected affected recipient invalid@domain.tld, got: %v", rcpts)
+		}
+		if recipients := client.GraylistedRecipients(); len(recipients) != 1 || recipients[0] != "invalid@domain.tld" {
+			t.Errorf("expected graylisted recipient invalid@domain.tld, got: %v", recipients)
+		}
+	})
+}
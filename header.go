@@ -50,6 +50,30 @@ const (
 	// HeaderInReplyTo represents the "In-Reply-To" field.
 	HeaderInReplyTo Header = "In-Reply-To"
 
+	// HeaderListArchive is the "List-Archive" header field.
+	// https://datatracker.ietf.org/doc/html/rfc2369#section-3.6
+	HeaderListArchive Header = "List-Archive"
+
+	// HeaderListHelp is the "List-Help" header field.
+	// https://datatracker.ietf.org/doc/html/rfc2369#section-3.1
+	HeaderListHelp Header = "List-Help"
+
+	// HeaderListID is the "List-Id" header field.
+	// https://datatracker.ietf.org/doc/html/rfc2919#section-3
+	HeaderListID Header = "List-Id"
+
+	// HeaderListOwner is the "List-Owner" header field.
+	// https://datatracker.ietf.org/doc/html/rfc2369#section-3.5
+	HeaderListOwner Header = "List-Owner"
+
+	// HeaderListPost is the "List-Post" header field.
+	// https://datatracker.ietf.org/doc/html/rfc2369#section-3.4
+	HeaderListPost Header = "List-Post"
+
+	// HeaderListSubscribe is the "List-Subscribe" header field.
+	// https://datatracker.ietf.org/doc/html/rfc2369#section-3.3
+	HeaderListSubscribe Header = "List-Subscribe"
+
 	// HeaderListUnsubscribe is the "List-Unsubscribe" header field.
 	HeaderListUnsubscribe Header = "List-Unsubscribe"
 
@@ -82,6 +106,11 @@ const (
 	// HeaderSubject is the "Subject" header field.
 	HeaderSubject Header = "Subject"
 
+	// HeaderTLSRequired is the "TLS-Required" header field.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc8689#section-5
+	HeaderTLSRequired Header = "TLS-Required"
+
 	// HeaderUserAgent is the "User-Agent" header field.
 	HeaderUserAgent Header = "User-Agent"
 
@@ -91,6 +120,9 @@ const (
 	// HeaderXMailer is the "X-Mailer" header field.
 	HeaderXMailer Header = "X-Mailer"
 
+	// HeaderXMailerAudit is the "X-Mailer-Audit" header field.
+	HeaderXMailerAudit Header = "X-Mailer-Audit"
+
 	// HeaderXMSMailPriority is the "X-MSMail-Priority" header field.
 	HeaderXMSMailPriority Header = "X-MSMail-Priority"
 
@@ -28,6 +28,12 @@ var (
 		},
 		{"Header: Importance", HeaderImportance, "Importance"},
 		{"Header: In-Reply-To", HeaderInReplyTo, "In-Reply-To"},
+		{"Header: List-Archive", HeaderListArchive, "List-Archive"},
+		{"Header: List-Help", HeaderListHelp, "List-Help"},
+		{"Header: List-Id", HeaderListID, "List-Id"},
+		{"Header: List-Owner", HeaderListOwner, "List-Owner"},
+		{"Header: List-Post", HeaderListPost, "List-Post"},
+		{"Header: List-Subscribe", HeaderListSubscribe, "List-Subscribe"},
 		{"Header: List-Unsubscribe", HeaderListUnsubscribe, "List-Unsubscribe"},
 		{"Header: List-Unsubscribe-Post", HeaderListUnsubscribePost, "List-Unsubscribe-Post"},
 		{"Header: Message-ID", HeaderMessageID, "Message-ID"},
@@ -41,6 +47,7 @@ var (
 		{"Header: User-Agent", HeaderUserAgent, "User-Agent"},
 		{"Header: X-Auto-Response-Suppress", HeaderXAutoResponseSuppress, "X-Auto-Response-Suppress"},
 		{"Header: X-Mailer", HeaderXMailer, "X-Mailer"},
+		{"Header: X-Mailer-Audit", HeaderXMailerAudit, "X-Mailer-Audit"},
 		{"Header: X-MSMail-Priority", HeaderXMSMailPriority, "X-MSMail-Priority"},
 		{"Header: X-Priority", HeaderXPriority, "X-Priority"},
 	}
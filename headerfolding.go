@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"strings"
+)
+
+// MaxHeaderLineLength is the maximum length of a header line, excluding the CRLF, as defined in
+// RFC 5322.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5322#section-2.1.1
+const MaxHeaderLineLength = 998
+
+// FoldingPolicy is a type that determines how header lines are folded when a Msg is written.
+type FoldingPolicy int
+
+const (
+	// FoldingStrict folds the header lines at whitespace so that they do not exceed MaxHeaderLength
+	// characters. This is the default.
+	FoldingStrict FoldingPolicy = iota
+
+	// FoldingHardLimit only folds header lines at whitespace that would exceed the hard limit of
+	// MaxHeaderLineLength characters.
+	FoldingHardLimit
+
+	// NoFolding writes each header on a single line, regardless of its length.
+	NoFolding
+)
+
+// WithHeaderFolding sets the FoldingPolicy for the headers of the Msg during its creation or
+// initialization.
+//
+// By default, headers are folded so that their lines do not exceed MaxHeaderLength characters.
+// Some downstream gateways mangle folded headers, i. e. long "References" headers, in which case
+// FoldingHardLimit or NoFolding can be used instead.
+//
+// Parameters:
+//   - policy: The FoldingPolicy to apply to the headers of the Msg.
+//
+// Returns:
+//   - A MsgOption function that sets the FoldingPolicy of the Msg.
+func WithHeaderFolding(policy FoldingPolicy) MsgOption {
+	return func(m *Msg) {
+		m.headerFolding = policy
+	}
+}
+
+// String satisfies the fmt.Stringer interface for the FoldingPolicy type.
+//
+// Returns:
+//   - A string representation of the FoldingPolicy.
+func (p FoldingPolicy) String() string {
+	switch p {
+	case FoldingStrict:
+		return "FoldingStrict"
+	case FoldingHardLimit:
+		return "FoldingHardLimit"
+	case NoFolding:
+		return "NoFolding"
+	default:
+		return "UnknownPolicy"
+	}
+}
+
+// FoldHeader formats a header with the given key and values and folds it according to the given
+// FoldingPolicy.
+//
+// The values are joined with a comma and the header is folded at whitespace only, by inserting a
+// CRLF in front of the whitespace. Words that are longer than the line length are not split.
+//
+// Parameters:
+//   - key: The Header key.
+//   - policy: The FoldingPolicy to apply.
+//   - values: A variadic parameter representing the values of the header.
+//
+// Returns:
+//   - The formatted header, without a trailing CRLF.
+func FoldHeader(key Header, policy FoldingPolicy, values ...string) string {
+	value := strings.Join(values, ", ")
+	switch policy {
+	case NoFolding:
+		return string(key) + ": " + value
+	case FoldingHardLimit:
+		return foldHeader(key, value, MaxHeaderLineLength)
+	default:
+		return foldHeader(key, value, MaxHeaderLength)
+	}
+}
+
+// foldHeader formats the header with the given key and value, folding its lines at whitespace
+// so that they do not exceed the given line length.
+func foldHeader(key Header, value string, lineLength int) string {
+	buffer := strings.Builder{}
+	charLength := lineLength - 2
+	buffer.WriteString(string(key))
+	charLength -= len(key)
+	buffer.WriteString(": ")
+	charLength -= 2
+
+	words := strings.Split(value, " ")
+	for i, val := range words {
+		if charLength-len(val) <= 1 {
+			buffer.WriteString(SingleNewLine + " ")
+			charLength = lineLength - 3
+		}
+		buffer.WriteString(val)
+		if i < len(words)-1 {
+			buffer.WriteString(" ")
+			charLength--
+		}
+		charLength -= len(val)
+	}
+	return strings.ReplaceAll(buffer.String(), " "+SingleNewLine, SingleNewLine)
+}
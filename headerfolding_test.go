@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFoldHeader(t *testing.T) {
+	references := strings.Repeat("<message-id-1234567890@domain.tld> ", 40)
+	references = strings.TrimSpace(references)
+	tests := []struct {
+		name      string
+		policy    FoldingPolicy
+		maxLength int
+		lines     int
+	}{
+		{"strict folding", FoldingStrict, MaxHeaderLength, 21},
+		{"hard limit folding", FoldingHardLimit, MaxHeaderLineLength, 2},
+		{"no folding", NoFolding, len("References: ") + len(references), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := FoldHeader(HeaderReferences, tt.policy, references)
+			lines := strings.Split(folded, SingleNewLine)
+			if len(lines) != tt.lines {
+				t.Errorf("expected %d lines, got: %d", tt.lines, len(lines))
+			}
+			for i, line := range lines {
+				if len(line) > tt.maxLength {
+					t.Errorf("line %d exceeds %d characters: %d", i, tt.maxLength, len(line))
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("expected folded line %d to start with whitespace, got: %q", i, line)
+				}
+			}
+			unfolded := strings.ReplaceAll(folded, SingleNewLine, "")
+			if unfolded != "References: "+references {
+				t.Errorf("unfolded header does not match the original value, got: %q", unfolded)
+			}
+		})
+	}
+	t.Run("multiple values are joined", func(t *testing.T) {
+		if folded := FoldHeader(Header(HeaderTo), FoldingStrict, "a@domain.tld", "b@domain.tld"); folded !=
+			"To: a@domain.tld, b@domain.tld" {
+			t.Errorf("unexpected folded header: %q", folded)
+		}
+	})
+}
+
+func TestWithHeaderFolding(t *testing.T) {
+	references := strings.TrimSpace(strings.Repeat("<message-id-1234567890@domain.tld> ", 5))
+	message := testMessage(t, WithHeaderFolding(NoFolding))
+	message.SetGenHeader(HeaderReferences, references)
+	buffer := bytes.NewBuffer(nil)
+	if _, err := message.WriteTo(buffer); err != nil {
+		t.Fatalf("failed to write message: %s", err)
+	}
+	if !strings.Contains(buffer.String(), "References: "+references+SingleNewLine) {
+		t.Errorf("expected unfolded References header, got: %s", buffer.String())
+	}
+}
+
+func TestFoldingPolicy_String(t *testing.T) {
+	tests := []struct {
+		policy FoldingPolicy
+		want   string
+	}{
+		{FoldingStrict, "FoldingStrict"},
+		{FoldingHardLimit, "FoldingHardLimit"},
+		{NoFolding, "NoFolding"},
+		{999, "UnknownPolicy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.policy.String(); got != tt.want {
+				t.Errorf("unexpected string, want: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func FuzzFoldHeader(f *testing.F) {
+	f.Add("<message-id-1234567890@domain.tld> <message-id-0987654321@domain.tld>")
+	f.Add(strings.Repeat("a", MaxHeaderLineLength+10))
+	f.Add("")
+	f.Add("   ")
+	f.Fuzz(func(t *testing.T, data string) {
+		for _, policy := range []FoldingPolicy{FoldingStrict, FoldingHardLimit, NoFolding} {
+			folded := FoldHeader(HeaderReferences, policy, data)
+			if unfolded := strings.ReplaceAll(folded, SingleNewLine, ""); unfolded != "References: "+data &&
+				!strings.Contains(data, SingleNewLine) {
+				t.Errorf("unfolded %s header does not match the original value: %q", policy, unfolded)
+			}
+		}
+	})
+}
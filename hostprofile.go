@@ -0,0 +1,224 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// HostObservation represents the SMTP features negotiated with a server during a single session.
+	HostObservation struct {
+		// Extensions holds the ESMTP extensions advertised by the server, mapped to their parameters.
+		Extensions map[string]string
+
+		// MaxSize is the maximum message size advertised by the server via the SIZE extension. It is
+		// zero, if the server did not advertise a limit.
+		MaxSize int64
+
+		// Time is the point in time the observation was made.
+		Time time.Time
+
+		// TLSVersion is the TLS version negotiated for the session, i. e. tls.VersionTLS13. It is zero,
+		// if the session was not encrypted.
+		TLSVersion uint16
+	}
+
+	// HostProfile represents the aggregation of all HostObservation recorded for a single host.
+	//
+	// A HostProfile can be used to guide policy decisions, like SMTPUTF8 downgrades or the selection
+	// of message sizes, based on what a server has offered over time.
+	HostProfile struct {
+		// Extensions maps each ESMTP extension that was ever advertised by the host to the number of
+		// sessions in which it was advertised.
+		Extensions map[string]int
+
+		// FirstSeen is the point in time of the first observation of the host.
+		FirstSeen time.Time
+
+		// Host is the hostname of the SMTP server.
+		Host string
+
+		// Last is the most recent HostObservation recorded for the host.
+		Last HostObservation
+
+		// LastSeen is the point in time of the most recent observation of the host.
+		LastSeen time.Time
+
+		// MaxSizes maps each maximum message size advertised by the host to the number of sessions
+		// in which it was advertised.
+		MaxSizes map[int64]int
+
+		// Observations is the total number of observations recorded for the host.
+		Observations int
+
+		// TLSVersions maps each negotiated TLS version to the number of sessions in which it was used.
+		// Unencrypted sessions are counted with a TLS version of zero.
+		TLSVersions map[uint16]int
+	}
+
+	// HostProfileStore is an interface that records HostObservation per host and provides the
+	// aggregated HostProfile for it.
+	//
+	// Implementations must be safe for concurrent use, since a store might be shared between
+	// multiple Clients.
+	HostProfileStore interface {
+		// Record stores the given HostObservation for the given host.
+		Record(host string, observation HostObservation)
+
+		// Profile returns the HostProfile for the given host and reports whether any observation
+		// has been recorded for the host.
+		Profile(host string) (HostProfile, bool)
+	}
+
+	// memoryHostProfileStore is an in-memory implementation of the HostProfileStore interface.
+	memoryHostProfileStore struct {
+		// mutex is used to synchronize access to the profiles map.
+		mutex sync.RWMutex
+
+		// profiles maps the lower-cased hostnames to their HostProfile.
+		profiles map[string]*HostProfile
+	}
+)
+
+// NewHostProfileStore returns a new in-memory HostProfileStore.
+//
+// The returned store keeps all profiles for the lifetime of the process and is safe for concurrent
+// use. For persistent storage, a custom implementation of the HostProfileStore interface can be used.
+//
+// Returns:
+//   - A HostProfileStore that keeps the profiles in memory.
+func NewHostProfileStore() HostProfileStore {
+	return &memoryHostProfileStore{profiles: make(map[string]*HostProfile)}
+}
+
+// WithHostProfileStore sets a HostProfileStore for the Client, that records the SMTP features
+// negotiated with the server on each successful dial.
+//
+// Parameters:
+//   - store: The HostProfileStore to record the observations in.
+//
+// Returns:
+//   - An Option function that sets the HostProfileStore for the Client.
+func WithHostProfileStore(store HostProfileStore) Option {
+	return func(c *Client) error {
+		c.hostProfileStore = store
+		return nil
+	}
+}
+
+// HostProfile returns the HostProfile of the given host, as recorded in the HostProfileStore of
+// the Client.
+//
+// Parameters:
+//   - host: The hostname of the SMTP server.
+//
+// Returns:
+//   - The HostProfile for the host.
+//   - A boolean indicating whether a profile exists. It is false if no HostProfileStore is set or
+//     the host has not been observed yet.
+func (c *Client) HostProfile(host string) (HostProfile, bool) {
+	if c.hostProfileStore == nil {
+		return HostProfile{}, false
+	}
+	return c.hostProfileStore.Profile(host)
+}
+
+// Supports reports whether the host advertised the given ESMTP extension in the most recent
+// observation. The extension name is case-insensitive.
+//
+// Parameters:
+//   - extension: The name of the ESMTP extension.
+//
+// Returns:
+//   - True if the extension was advertised in the most recent observation, false otherwise.
+func (p HostProfile) Supports(extension string) bool {
+	_, ok := p.Last.Extensions[strings.ToUpper(extension)]
+	return ok
+}
+
+// recordHostProfile records the currently negotiated SMTP features of the Client connection in the
+// HostProfileStore, if one is set.
+func (c *Client) recordHostProfile() {
+	if c.hostProfileStore == nil || c.smtpClient == nil {
+		return
+	}
+	observation := HostObservation{
+		Extensions: c.smtpClient.Extensions(),
+		Time:       time.Now(),
+	}
+	if size, ok := observation.Extensions["SIZE"]; ok {
+		if maxSize, err := strconv.ParseInt(strings.TrimSpace(size), 10, 64); err == nil {
+			observation.MaxSize = maxSize
+		}
+	}
+	if state, err := c.smtpClient.GetTLSConnectionState(); err == nil {
+		observation.TLSVersion = state.Version
+	}
+	c.hostProfileStore.Record(c.host, observation)
+}
+
+// Record stores the given HostObservation for the given host. It satisfies the HostProfileStore
+// interface.
+func (s *memoryHostProfileStore) Record(host string, observation HostObservation) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := strings.ToLower(host)
+	profile, ok := s.profiles[key]
+	if !ok {
+		profile = &HostProfile{
+			Extensions:  make(map[string]int),
+			FirstSeen:   observation.Time,
+			Host:        host,
+			MaxSizes:    make(map[int64]int),
+			TLSVersions: make(map[uint16]int),
+		}
+		s.profiles[key] = profile
+	}
+	for extension := range observation.Extensions {
+		profile.Extensions[extension]++
+	}
+	if observation.MaxSize > 0 {
+		profile.MaxSizes[observation.MaxSize]++
+	}
+	profile.TLSVersions[observation.TLSVersion]++
+	profile.Observations++
+	profile.Last = observation
+	profile.LastSeen = observation.Time
+}
+
+// Profile returns a copy of the HostProfile for the given host. It satisfies the HostProfileStore
+// interface.
+func (s *memoryHostProfileStore) Profile(host string) (HostProfile, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	profile, ok := s.profiles[strings.ToLower(host)]
+	if !ok {
+		return HostProfile{}, false
+	}
+	profileCopy := *profile
+	profileCopy.Extensions = make(map[string]int, len(profile.Extensions))
+	for k, v := range profile.Extensions {
+		profileCopy.Extensions[k] = v
+	}
+	profileCopy.MaxSizes = make(map[int64]int, len(profile.MaxSizes))
+	for k, v := range profile.MaxSizes {
+		profileCopy.MaxSizes[k] = v
+	}
+	profileCopy.TLSVersions = make(map[uint16]int, len(profile.TLSVersions))
+	for k, v := range profile.TLSVersions {
+		profileCopy.TLSVersions[k] = v
+	}
+	profileCopy.Last.Extensions = make(map[string]string, len(profile.Last.Extensions))
+	for k, v := range profile.Last.Extensions {
+		profileCopy.Last.Extensions[k] = v
+	}
+	return profileCopy, true
+}
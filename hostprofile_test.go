@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+	"time"
+)
+
+func TestNewHostProfileStore(t *testing.T) {
+	t.Run("record and retrieve observations", func(t *testing.T) {
+		store := NewHostProfileStore()
+		if _, ok := store.Profile("mail.example.com"); ok {
+			t.Fatal("expected no profile for unobserved host")
+		}
+		now := time.Now()
+		store.Record("mail.example.com", HostObservation{
+			Extensions: map[string]string{"SIZE": "1000", "SMTPUTF8": ""},
+			MaxSize:    1000,
+			Time:       now,
+			TLSVersion: tls.VersionTLS12,
+		})
+		store.Record("MAIL.example.com", HostObservation{
+			Extensions: map[string]string{"SIZE": "2000"},
+			MaxSize:    2000,
+			Time:       now.Add(time.Minute),
+			TLSVersion: tls.VersionTLS13,
+		})
+		profile, ok := store.Profile("mail.EXAMPLE.com")
+		if !ok {
+			t.Fatal("expected profile for observed host")
+		}
+		if profile.Observations != 2 {
+			t.Errorf("expected 2 observations, got: %d", profile.Observations)
+		}
+		if profile.Extensions["SIZE"] != 2 || profile.Extensions["SMTPUTF8"] != 1 {
+			t.Errorf("unexpected extension counts: %v", profile.Extensions)
+		}
+		if profile.MaxSizes[1000] != 1 || profile.MaxSizes[2000] != 1 {
+			t.Errorf("unexpected max sizes: %v", profile.MaxSizes)
+		}
+		if profile.TLSVersions[tls.VersionTLS12] != 1 || profile.TLSVersions[tls.VersionTLS13] != 1 {
+			t.Errorf("unexpected TLS versions: %v", profile.TLSVersions)
+		}
+		if !profile.FirstSeen.Equal(now) || !profile.LastSeen.Equal(now.Add(time.Minute)) {
+			t.Errorf("unexpected first/last seen: %s/%s", profile.FirstSeen, profile.LastSeen)
+		}
+		if profile.Supports("smtputf8") {
+			t.Error("expected SMTPUTF8 to be unsupported in most recent observation")
+		}
+		if !profile.Supports("size") {
+			t.Error("expected SIZE to be supported in most recent observation")
+		}
+	})
+	t.Run("returned profile is a copy", func(t *testing.T) {
+		store := NewHostProfileStore()
+		store.Record("mail.example.com", HostObservation{Extensions: map[string]string{"SIZE": "1000"}})
+		profile, _ := store.Profile("mail.example.com")
+		profile.Extensions["SIZE"] = 100
+		profile.Last.Extensions["DSN"] = ""
+		profile, _ = store.Profile("mail.example.com")
+		if profile.Extensions["SIZE"] != 1 {
+			t.Errorf("expected stored extension count to be unchanged, got: %d", profile.Extensions["SIZE"])
+		}
+		if profile.Supports("DSN") {
+			t.Error("expected stored observation to be unchanged")
+		}
+	})
+}
+
+func TestClient_HostProfile(t *testing.T) {
+	t.Run("no profile without store", func(t *testing.T) {
+		client, err := NewClient(DefaultHost)
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if _, ok := client.HostProfile(DefaultHost); ok {
+			t.Error("expected no host profile without store")
+		}
+	})
+	t.Run("profile is recorded on dial", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-SIZE 10240000\r\n250-8BITMIME\r\n250-DSN\r\n250-STARTTLS\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		store := NewHostProfileStore()
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(TLSMandatory),
+			WithTLSConfig(&tls.Config{InsecureSkipVerify: true}), WithHostProfileStore(store))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+		t.Cleanup(cancelDial)
+		if err = client.DialWithContext(ctxDial); err != nil {
+			t.Fatalf("failed to connect to the test server: %s", err)
+		}
+		t.Cleanup(func() {
+			if err := client.Close(); err != nil {
+				t.Errorf("failed to close client: %s", err)
+			}
+		})
+
+		profile, ok := client.HostProfile(DefaultHost)
+		if !ok {
+			t.Fatal("expected host profile to be recorded")
+		}
+		if profile.Host != DefaultHost {
+			t.Errorf("expected profile host: %s, got: %s", DefaultHost, profile.Host)
+		}
+		if profile.Last.MaxSize != 10240000 {
+			t.Errorf("expected max size: %d, got: %d", 10240000, profile.Last.MaxSize)
+		}
+		if profile.Last.TLSVersion == 0 {
+			t.Error("expected TLS version to be recorded")
+		}
+		if !profile.Supports("SMTPUTF8") {
+			t.Error("expected SMTPUTF8 to be supported")
+		}
+	})
+}
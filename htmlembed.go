@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrHTMLImageOutsideBaseDir is returned if a local image reference of an HTML part points outside
+// of the base directory.
+var ErrHTMLImageOutsideBaseDir = errors.New("HTML image reference is outside of the base directory")
+
+// EmbedHTMLImages scans all "text/html" parts of the Msg for local image references, embeds the
+// referenced files and rewrites the references to "cid:" URLs.
+//
+// Each "src" attribute of an "img" element that holds a relative URL or a "file:" URL is resolved
+// against the given base directory. The file is embedded into the Msg with a generated "Content-ID",
+// so that it is sent as related part of the HTML, and the "src" attribute is replaced with the
+// matching "cid:" URL. A file that is referenced multiple times is only embedded once. Absolute URLs
+// like "https:", "cid:" and "data:" URLs are left untouched. The rest of the HTML is not modified.
+//
+// Parameters:
+//   - baseDir: The directory that relative image references are resolved against.
+//
+// Returns:
+//   - An error if a referenced file does not exist, is outside of the base directory or if the
+//     content of an HTML part cannot be read; otherwise, returns nil.
+func (m *Msg) EmbedHTMLImages(baseDir string) error {
+	baseDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve base directory: %w", err)
+	}
+	contentIDs := make(map[string]string)
+	for _, part := range m.GetParts() {
+		if part.GetContentType() != TypeTextHTML {
+			continue
+		}
+		content, err := part.GetContent()
+		if err != nil {
+			return fmt.Errorf("failed to read HTML part: %w", err)
+		}
+		var output strings.Builder
+		for _, token := range tokenizeHTML(string(content)) {
+			src, ok := token.attribute("src")
+			if token.kind != htmlTokenStartTag || token.data != "img" || !ok || !isLocalHTMLImage(src) {
+				output.WriteString(token.raw)
+				continue
+			}
+			path, err := resolveHTMLImagePath(baseDir, src)
+			if err != nil {
+				return err
+			}
+			contentID, ok := contentIDs[path]
+			if !ok {
+				if contentID, err = m.embedHTMLImage(path); err != nil {
+					return fmt.Errorf("failed to embed HTML image %q: %w", src, err)
+				}
+				contentIDs[path] = contentID
+			}
+			token.setAttribute("src", "cid:"+contentID)
+			output.WriteString(token.String())
+		}
+		part.SetContent(output.String())
+	}
+	return nil
+}
+
+// embedHTMLImage embeds the file at the given path with a generated Content-ID.
+//
+// Parameters:
+//   - path: The path of the image file.
+//
+// Returns:
+//   - The generated Content-ID without angle brackets.
+//   - An error if the file does not exist or the Content-ID cannot be generated.
+func (m *Msg) embedHTMLImage(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost.localdomain"
+	}
+	randString, err := randomStringSecure(22)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate content ID: %w", err)
+	}
+	contentID := fmt.Sprintf("%s@%s", randString, hostname)
+	m.EmbedFile(path, WithFileContentID("<"+contentID+">"))
+	return contentID, nil
+}
+
+// isLocalHTMLImage reports whether the given image source is a relative URL or a "file:" URL.
+func isLocalHTMLImage(src string) bool {
+	src = strings.TrimSpace(src)
+	if src == "" || strings.HasPrefix(src, "//") || strings.HasPrefix(src, "#") {
+		return false
+	}
+	colon := strings.IndexByte(src, ':')
+	if colon < 0 || strings.ContainsAny(src[:colon], "/?#") {
+		return true
+	}
+	return strings.EqualFold(src[:colon], "file")
+}
+
+// resolveHTMLImagePath resolves the given local image source against the base directory.
+//
+// Parameters:
+//   - baseDir: The absolute base directory.
+//   - src: The local image source, i. e. "images/logo.png" or "file:///path/logo.png".
+//
+// Returns:
+//   - The absolute path of the image file.
+//   - An error if the source cannot be parsed or points outside of the base directory.
+func resolveHTMLImagePath(baseDir, src string) (string, error) {
+	reference, err := url.Parse(strings.TrimSpace(src))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML image reference %q: %w", src, err)
+	}
+	path := filepath.FromSlash(reference.Path)
+	if !filepath.IsAbs(path) || !strings.EqualFold(reference.Scheme, "file") {
+		path = filepath.Join(baseDir, path)
+	}
+	path = filepath.Clean(path)
+	relative, err := filepath.Rel(baseDir, path)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s", ErrHTMLImageOutsideBaseDir, src)
+	}
+	return path, nil
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMsg_EmbedHTMLImages(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tempDir, "images"), 0o700); err != nil {
+		t.Fatalf("failed to create image directory: %s", err)
+	}
+	for _, name := range []string{"logo.png", "images/banner.png"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("image data"), 0o600); err != nil {
+			t.Fatalf("failed to write image file: %s", err)
+		}
+	}
+
+	t.Run("local images are embedded and rewritten", func(t *testing.T) {
+		message := testMessage(t)
+		message.AddAlternativeString(TypeTextHTML, `<p>Hi</p><img src="logo.png" alt="Logo">`+
+			`<IMG SRC='images/banner.png?v=1'><img src="logo.png"><img src="https://domain.tld/x.png">`+
+			`<img src="cid:existing">`)
+		if err := message.EmbedHTMLImages(tempDir); err != nil {
+			t.Fatalf("failed to embed HTML images: %s", err)
+		}
+		embeds := message.GetEmbeds()
+		if len(embeds) != 2 {
+			t.Fatalf("expected 2 embeds, got: %d", len(embeds))
+		}
+		contentIDs := make([]string, len(embeds))
+		for i, embed := range embeds {
+			contentID, ok := embed.getHeader(HeaderContentID)
+			if !ok || !strings.HasPrefix(contentID, "<") || !strings.HasSuffix(contentID, ">") {
+				t.Fatalf("expected generated content ID for embed %s, got: %q", embed.Name, contentID)
+			}
+			contentIDs[i] = strings.Trim(contentID, "<>")
+		}
+		content, err := message.GetParts()[1].GetContent()
+		if err != nil {
+			t.Fatalf("failed to get HTML content: %s", err)
+		}
+		want := `<p>Hi</p><img src="cid:` + contentIDs[0] + `" alt="Logo"><img src="cid:` + contentIDs[1] +
+			`"><img src="cid:` + contentIDs[0] + `"><img src="https://domain.tld/x.png"><img src="cid:existing">`
+		if string(content) != want {
+			t.Errorf("unexpected HTML content, want: %s, got: %s", want, content)
+		}
+
+		buffer := bytes.NewBuffer(nil)
+		if _, err = message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if !strings.Contains(buffer.String(), "multipart/related") {
+			t.Error("expected message to contain a multipart/related part")
+		}
+	})
+	t.Run("missing image fails", func(t *testing.T) {
+		message := NewMsg()
+		message.SetBodyString(TypeTextHTML, `<img src="missing.png">`)
+		if err := message.EmbedHTMLImages(tempDir); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected error: %s, got: %s", os.ErrNotExist, err)
+		}
+	})
+	t.Run("image outside of base directory fails", func(t *testing.T) {
+		message := NewMsg()
+		message.SetBodyString(TypeTextHTML, `<img src="../logo.png">`)
+		baseDir := filepath.Join(tempDir, "images")
+		if err := message.EmbedHTMLImages(baseDir); !errors.Is(err, ErrHTMLImageOutsideBaseDir) {
+			t.Errorf("expected error: %s, got: %s", ErrHTMLImageOutsideBaseDir, err)
+		}
+	})
+	t.Run("message without HTML part is not changed", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.EmbedHTMLImages(tempDir); err != nil {
+			t.Errorf("failed to embed HTML images: %s", err)
+		}
+		if len(message.GetEmbeds()) != 0 {
+			t.Errorf("expected no embeds, got: %d", len(message.GetEmbeds()))
+		}
+	})
+}
+
+func TestIsLocalHTMLImage(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"logo.png", true},
+		{"/images/logo.png", true},
+		{"images/a:b.png", true},
+		{"file:///tmp/logo.png", true},
+		{"https://domain.tld/logo.png", false},
+		{"//domain.tld/logo.png", false},
+		{"cid:logo", false},
+		{"data:image/png;base64,AAAA", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			if got := isLocalHTMLImage(tt.src); got != tt.want {
+				t.Errorf("unexpected result for %q, want: %t, got: %t", tt.src, tt.want, got)
+			}
+		})
+	}
+}
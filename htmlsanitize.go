@@ -0,0 +1,528 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// List of HTMLRemovalReason values
+const (
+	// HTMLRemovalDisallowedTag indicates that an element was removed because its tag is not allowed by
+	// the HTMLPolicy. The content of the element is kept.
+	HTMLRemovalDisallowedTag HTMLRemovalReason = iota
+
+	// HTMLRemovalDisallowedAttribute indicates that an attribute was removed because it is not allowed by
+	// the HTMLPolicy.
+	HTMLRemovalDisallowedAttribute
+
+	// HTMLRemovalScript indicates that a script was removed, i. e. a "script" element, an event handler
+	// attribute or a "javascript:" URL.
+	HTMLRemovalScript
+
+	// HTMLRemovalExternalForm indicates that a "form" element was removed because it posts to an
+	// external URL.
+	HTMLRemovalExternalForm
+
+	// HTMLRemovalTrackingPixel indicates that an image was removed because it is a tracking pixel.
+	HTMLRemovalTrackingPixel
+
+	// HTMLRemovalExternalImage indicates that an image was removed because it is loaded from an external
+	// URL and HTMLPolicy.BlockExternalImages is set.
+	HTMLRemovalExternalImage
+
+	// HTMLRemovalUnsafeURL indicates that an attribute was removed because its URL scheme is not allowed
+	// by the HTMLPolicy.
+	HTMLRemovalUnsafeURL
+
+	// HTMLRemovalUnsafeStyle indicates that a "style" attribute was removed because it loads external
+	// resources or evaluates expressions.
+	HTMLRemovalUnsafeStyle
+
+	// HTMLRemovalContent indicates that an element was removed together with its content, i. e. a
+	// "style" or "iframe" element.
+	HTMLRemovalContent
+)
+
+// ErrNoHTMLPart is returned if a Msg does not have a "text/html" part.
+var ErrNoHTMLPart = errors.New("message has no text/html part")
+
+// htmlContentTags holds the elements that are always removed together with their content.
+var htmlContentTags = map[string]bool{
+	"applet": true, "embed": true, "frame": true, "frameset": true, "head": true, "iframe": true,
+	"noembed": true, "noframes": true, "noscript": true, "object": true, "script": true, "style": true,
+	"svg": true, "template": true, "title": true,
+}
+
+// htmlURLAttributes holds the attributes that hold a URL.
+var htmlURLAttributes = map[string]bool{
+	"action": true, "background": true, "cite": true, "formaction": true, "href": true, "longdesc": true,
+	"poster": true, "src": true,
+}
+
+type (
+	// HTMLRemovalReason represents the reason why an element or attribute was removed by
+	// Msg.SanitizedHTML.
+	HTMLRemovalReason int
+
+	// HTMLRemoval represents an element or attribute that was removed by Msg.SanitizedHTML.
+	HTMLRemoval struct {
+		// Attribute is the name of the removed attribute, or empty if the whole element was removed.
+		Attribute string
+
+		// Reason is the HTMLRemovalReason of the removal.
+		Reason HTMLRemovalReason
+
+		// Tag is the name of the affected element.
+		Tag string
+
+		// Value is the value of the removed attribute, or of the "src" or "action" attribute of a removed
+		// image or form.
+		Value string
+	}
+
+	// HTMLPolicy is an allowlist policy for Msg.SanitizedHTML.
+	//
+	// Elements and attributes that are not explicitly allowed by the policy are removed. Scripts, event
+	// handler attributes, forms that post to external URLs and tracking pixels are always removed,
+	// independent of the policy.
+	HTMLPolicy struct {
+		// AllowedAttributes maps the lower-case name of an element to the lower-case names of the
+		// attributes that are allowed for it. Attributes for the key "*" are allowed for all elements.
+		AllowedAttributes map[string][]string
+
+		// AllowedTags holds the lower-case names of the elements that are allowed.
+		AllowedTags []string
+
+		// AllowedURLSchemes holds the lower-case URL schemes that are allowed in URL attributes like
+		// "href" or "src". Relative URLs are always allowed.
+		AllowedURLSchemes []string
+
+		// BlockExternalImages indicates that images loaded from external URLs are removed as well, so
+		// that displaying the HTML does not reveal anything to the sender.
+		BlockExternalImages bool
+	}
+)
+
+// DefaultHTMLPolicy returns an HTMLPolicy that allows the common formatting elements and attributes
+// of HTML mail, as well as "http", "https", "mailto" and "cid" URLs.
+//
+// Returns:
+//   - A pointer to a new HTMLPolicy with the default allowlist.
+func DefaultHTMLPolicy() *HTMLPolicy {
+	return &HTMLPolicy{
+		AllowedAttributes: map[string][]string{
+			"*":     {"align", "class", "dir", "id", "lang", "style", "title"},
+			"a":     {"href", "name", "target"},
+			"body":  {"bgcolor"},
+			"col":   {"span", "width"},
+			"font":  {"color", "face", "size"},
+			"img":   {"alt", "border", "height", "src", "width"},
+			"li":    {"value"},
+			"ol":    {"start", "type"},
+			"table": {"bgcolor", "border", "cellpadding", "cellspacing", "width"},
+			"td":    {"bgcolor", "colspan", "height", "rowspan", "valign", "width"},
+			"th":    {"bgcolor", "colspan", "height", "rowspan", "valign", "width"},
+			"tr":    {"bgcolor", "valign"},
+		},
+		AllowedTags: []string{
+			"a", "abbr", "b", "big", "blockquote", "body", "br", "caption", "center", "cite", "code", "col",
+			"colgroup", "dd", "del", "div", "dl", "dt", "em", "font", "h1", "h2", "h3", "h4", "h5", "h6", "hr",
+			"html", "i", "img", "ins", "kbd", "li", "ol", "p", "pre", "q", "s", "small", "span", "strike",
+			"strong", "sub", "sup", "table", "tbody", "td", "tfoot", "th", "thead", "tr", "u", "ul",
+		},
+		AllowedURLSchemes: []string{"cid", "http", "https", "mailto"},
+	}
+}
+
+// SanitizedHTML returns the content of the first "text/html" part of the Msg, sanitized with the
+// given HTMLPolicy, so that it can be displayed safely.
+//
+// This is meant for applications that display parsed mail. Scripts, event handler attributes,
+// "javascript:" URLs, forms that post to external URLs and tracking pixels (images with a width
+// and height of at most one pixel) are always removed. All elements and attributes that are not
+// allowed by the policy are removed as well. The content of removed elements is kept, except for
+// elements like "script", "style" or "iframe", which are removed together with their content. If
+// policy is nil, DefaultHTMLPolicy is used.
+//
+// Parameters:
+//   - policy: The HTMLPolicy to sanitize the HTML with.
+//
+// Returns:
+//   - The sanitized HTML.
+//   - A list of HTMLRemoval values, one for each removed element or attribute, in document order.
+//   - An error if the Msg does not have a "text/html" part or its content cannot be read.
+func (m *Msg) SanitizedHTML(policy *HTMLPolicy) (string, []HTMLRemoval, error) {
+	for _, part := range m.GetParts() {
+		if part.GetContentType() != TypeTextHTML {
+			continue
+		}
+		content, err := part.GetContent()
+		if err != nil {
+			return "", nil, err
+		}
+		if policy == nil {
+			policy = DefaultHTMLPolicy()
+		}
+		sanitized, removals := policy.sanitize(string(content))
+		return sanitized, removals, nil
+	}
+	return "", nil, ErrNoHTMLPart
+}
+
+// String satisfies the fmt.Stringer interface for the HTMLRemovalReason type.
+//
+// Returns:
+//   - A string representation of the HTMLRemovalReason.
+func (r HTMLRemovalReason) String() string {
+	switch r {
+	case HTMLRemovalDisallowedTag:
+		return "disallowed tag"
+	case HTMLRemovalDisallowedAttribute:
+		return "disallowed attribute"
+	case HTMLRemovalScript:
+		return "script"
+	case HTMLRemovalExternalForm:
+		return "external form"
+	case HTMLRemovalTrackingPixel:
+		return "tracking pixel"
+	case HTMLRemovalExternalImage:
+		return "external image"
+	case HTMLRemovalUnsafeURL:
+		return "unsafe URL"
+	case HTMLRemovalUnsafeStyle:
+		return "unsafe style"
+	case HTMLRemovalContent:
+		return "element with content"
+	}
+	return "unknown reason"
+}
+
+// sanitize sanitizes the given HTML document with the HTMLPolicy.
+func (p *HTMLPolicy) sanitize(document string) (string, []HTMLRemoval) {
+	allowedTags := make(map[string]bool, len(p.AllowedTags))
+	for _, tag := range p.AllowedTags {
+		allowedTags[tag] = true
+	}
+	var output strings.Builder
+	var removals []HTMLRemoval
+	var removedEndTags []string
+	skipTag, skipDepth := "", 0
+
+	for _, token := range tokenizeHTML(document) {
+		if skipTag != "" {
+			switch {
+			case token.kind == htmlTokenStartTag && token.data == skipTag && !token.selfClosing:
+				skipDepth++
+			case token.kind == htmlTokenEndTag && token.data == skipTag:
+				skipDepth--
+			}
+			if skipDepth == 0 {
+				skipTag = ""
+			}
+			continue
+		}
+
+		switch token.kind {
+		case htmlTokenComment:
+			continue
+		case htmlTokenText:
+			output.WriteString(strings.ReplaceAll(token.data, "<", "&lt;"))
+			continue
+		case htmlTokenEndTag:
+			if allowedTags[token.data] && !containsString(removedEndTags, token.data) {
+				output.WriteString(token.String())
+			}
+			removedEndTags = removeString(removedEndTags, token.data)
+			continue
+		}
+
+		removal, removeContent := p.checkElement(token, allowedTags)
+		if removal != nil {
+			removals = append(removals, *removal)
+			if removeContent && !token.selfClosing {
+				skipTag, skipDepth = token.data, 1
+			}
+			if !removeContent && allowedTags[token.data] && !token.selfClosing {
+				removedEndTags = append(removedEndTags, token.data)
+			}
+			continue
+		}
+		var attributeRemovals []HTMLRemoval
+		token.attributes, attributeRemovals = p.sanitizeAttributes(token)
+		removals = append(removals, attributeRemovals...)
+		output.WriteString(token.String())
+	}
+	return output.String(), removals
+}
+
+// checkElement checks whether the element of the given start tag has to be removed.
+//
+// Returns:
+//   - The HTMLRemoval if the element has to be removed, or nil if it is kept.
+//   - True if the content of the element has to be removed as well.
+func (p *HTMLPolicy) checkElement(token htmlToken, allowedTags map[string]bool) (*HTMLRemoval, bool) {
+	switch {
+	case token.data == "script":
+		return &HTMLRemoval{Reason: HTMLRemovalScript, Tag: token.data}, true
+	case htmlContentTags[token.data]:
+		return &HTMLRemoval{Reason: HTMLRemovalContent, Tag: token.data}, true
+	case token.data == "form":
+		if action, ok := token.attribute("action"); ok && isExternalURL(action) {
+			return &HTMLRemoval{Reason: HTMLRemovalExternalForm, Tag: token.data, Value: action}, false
+		}
+	case token.data == "img":
+		src, _ := token.attribute("src")
+		if isTrackingPixel(token) {
+			return &HTMLRemoval{Reason: HTMLRemovalTrackingPixel, Tag: token.data, Value: src}, false
+		}
+		if p.BlockExternalImages && isExternalURL(src) {
+			return &HTMLRemoval{Reason: HTMLRemovalExternalImage, Tag: token.data, Value: src}, false
+		}
+	}
+	if !allowedTags[token.data] {
+		return &HTMLRemoval{Reason: HTMLRemovalDisallowedTag, Tag: token.data}, false
+	}
+	return nil, false
+}
+
+// sanitizeAttributes removes all attributes of the given start tag that are not allowed.
+//
+// Returns:
+//   - The list of allowed attributes.
+//   - A list of HTMLRemoval values for the removed attributes.
+func (p *HTMLPolicy) sanitizeAttributes(token htmlToken) ([]htmlAttribute, []HTMLRemoval) {
+	var attributes []htmlAttribute
+	var removals []HTMLRemoval
+	for _, attribute := range token.attributes {
+		removal := HTMLRemoval{Attribute: attribute.name, Tag: token.data, Value: attribute.value}
+		url := strings.ToLower(strings.Join(strings.Fields(attribute.value), ""))
+		switch {
+		case strings.HasPrefix(attribute.name, "on"), htmlURLAttributes[attribute.name] &&
+			(strings.HasPrefix(url, "javascript:") || strings.HasPrefix(url, "vbscript:")):
+			removal.Reason = HTMLRemovalScript
+		case !containsString(p.AllowedAttributes[token.data], attribute.name) &&
+			!containsString(p.AllowedAttributes["*"], attribute.name):
+			removal.Reason = HTMLRemovalDisallowedAttribute
+		case htmlURLAttributes[attribute.name] && !p.isAllowedURL(url):
+			removal.Reason = HTMLRemovalUnsafeURL
+		case attribute.name == "style" && isUnsafeStyle(attribute.value):
+			removal.Reason = HTMLRemovalUnsafeStyle
+		default:
+			attributes = append(attributes, attribute)
+			continue
+		}
+		removals = append(removals, removal)
+	}
+	return attributes, removals
+}
+
+// isAllowedURL reports whether the given lower-case URL is relative or uses an allowed scheme.
+func (p *HTMLPolicy) isAllowedURL(url string) bool {
+	colon := strings.IndexByte(url, ':')
+	if colon < 0 || strings.ContainsAny(url[:colon], "/?#") {
+		return true
+	}
+	return containsString(p.AllowedURLSchemes, url[:colon])
+}
+
+// isExternalURL reports whether the given URL is an absolute URL, including protocol-relative URLs.
+func isExternalURL(url string) bool {
+	url = strings.ToLower(strings.TrimSpace(url))
+	return strings.HasPrefix(url, "//") || strings.HasPrefix(url, "http:") || strings.HasPrefix(url, "https:")
+}
+
+// isTrackingPixel reports whether the given image start tag is a tracking pixel, i. e. an image that
+// is at most one pixel wide or high, or that is hidden. The dimensions are taken from the "width" and
+// "height" attributes, which are overridden by the declarations of the "style" attribute.
+func isTrackingPixel(token htmlToken) bool {
+	width, _ := token.attribute("width")
+	height, _ := token.attribute("height")
+	style, _ := token.attribute("style")
+	for _, declaration := range parseCSSDeclarations(style) {
+		switch declaration.property {
+		case "width":
+			width = declaration.value
+		case "height":
+			height = declaration.value
+		case "max-width", "max-height":
+			if isTinyLength(declaration.value) {
+				return true
+			}
+		case "display":
+			if declaration.value == "none" {
+				return true
+			}
+		case "visibility":
+			if declaration.value == "hidden" || declaration.value == "collapse" {
+				return true
+			}
+		}
+	}
+	return isTinyLength(width) || isTinyLength(height)
+}
+
+// isTinyLength reports whether the given HTML or CSS length is at most one pixel. Lengths with other
+// units than "px", i. e. "0em" or "0%", are only considered tiny if they are zero.
+func isTinyLength(length string) bool {
+	length = strings.TrimSpace(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(length)), "!important"))
+	end := strings.IndexFunc(length, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if end < 0 {
+		end = len(length)
+	}
+	value, err := strconv.ParseFloat(length[:end], 64)
+	if err != nil {
+		return false
+	}
+	if unit := length[end:]; unit != "" && unit != "px" {
+		return value == 0
+	}
+	return value <= 1
+}
+
+// cssDeclaration represents a single property and its value of a CSS declaration list.
+type cssDeclaration struct {
+	// property is the lower-case name of the property.
+	property string
+
+	// value is the lower-case value of the property with whitespace and comments removed.
+	value string
+}
+
+// parseCSSDeclarations splits the given CSS declaration list, as found in a "style" attribute, into
+// its declarations. Escapes and comments are decoded before the declarations are split, so that the
+// result matches the interpretation of the browser.
+func parseCSSDeclarations(style string) []cssDeclaration {
+	var declarations []cssDeclaration
+	for _, declaration := range splitCSSDeclarations(decodeCSS(style)) {
+		colon := strings.IndexByte(declaration, ':')
+		if colon < 0 {
+			continue
+		}
+		property := strings.ToLower(strings.TrimSpace(declaration[:colon]))
+		value := strings.ToLower(strings.Join(strings.Fields(declaration[colon+1:]), ""))
+		if property != "" {
+			declarations = append(declarations, cssDeclaration{property: property, value: value})
+		}
+	}
+	return declarations
+}
+
+// splitCSSDeclarations splits the given decoded CSS declaration list at all semicolons that are not
+// part of a quoted string or a function argument.
+func splitCSSDeclarations(style string) []string {
+	var declarations []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(style); i++ {
+		switch char := style[i]; {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '(':
+			depth++
+		case char == ')' && depth > 0:
+			depth--
+		case char == ';' && depth == 0:
+			declarations = append(declarations, style[start:i])
+			start = i + 1
+		}
+	}
+	return append(declarations, style[start:])
+}
+
+// decodeCSS removes the comments from the given CSS and resolves its escape sequences, i. e. "\72"
+// or "\r" for "r", as described in the CSS syntax specification.
+//
+// References:
+//   - https://www.w3.org/TR/css-syntax-3/#consume-escaped-code-point
+func decodeCSS(style string) string {
+	var decoded strings.Builder
+	for i := 0; i < len(style); i++ {
+		switch {
+		case strings.HasPrefix(style[i:], "/*"):
+			end := strings.Index(style[i+2:], "*/")
+			if end < 0 {
+				return decoded.String()
+			}
+			i += end + 3
+		case style[i] == '\\' && i+1 < len(style):
+			i++
+			end := i
+			for end < len(style) && end-i < 6 && isHexDigit(style[end]) {
+				end++
+			}
+			switch {
+			case end > i:
+				codePoint, _ := strconv.ParseUint(style[i:end], 16, 32)
+				if codePoint == 0 || codePoint > unicode.MaxRune || codePoint >= 0xD800 && codePoint <= 0xDFFF {
+					codePoint = unicode.ReplacementChar
+				}
+				decoded.WriteRune(rune(codePoint))
+				// A single whitespace character after a hexadecimal escape is part of the escape
+				if end < len(style) && strings.IndexByte(" \t\r\n\f", style[end]) >= 0 {
+					end++
+				}
+				i = end - 1
+			case style[i] == '\n' || style[i] == '\f':
+			case style[i] == '\r':
+				if i+1 < len(style) && style[i+1] == '\n' {
+					i++
+				}
+			default:
+				decoded.WriteByte(style[i])
+			}
+		default:
+			decoded.WriteByte(style[i])
+		}
+	}
+	return decoded.String()
+}
+
+// isHexDigit reports whether the given character is a hexadecimal digit.
+func isHexDigit(char byte) bool {
+	return char >= '0' && char <= '9' || char >= 'a' && char <= 'f' || char >= 'A' && char <= 'F'
+}
+
+// isUnsafeStyle reports whether the given style attribute loads external resources, evaluates
+// expressions or binds behaviors. Escapes and comments are decoded before the style is checked.
+func isUnsafeStyle(style string) bool {
+	style = strings.ToLower(strings.Join(strings.Fields(decodeCSS(style)), ""))
+	for _, keyword := range []string{
+		"url(", "expression(", "javascript:", "vbscript:", "@import", "image(", "image-set(",
+		"-moz-binding", "behavior:",
+	} {
+		if strings.Contains(style, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether the list contains the given value.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// removeString removes the last occurrence of the given value from the list.
+func removeString(list []string, value string) []string {
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i] == value {
+			return append(list[:i], list[i+1:]...)
+		}
+	}
+	return list
+}
@@ -0,0 +1,261 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMsg_SanitizedHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		want     string
+		removals []HTMLRemoval
+	}{
+		{
+			"allowed elements are kept", `<p class="intro">Hello <b>World</b><br/></p>`,
+			`<p class="intro">Hello <b>World</b><br /></p>`, nil,
+		},
+		{
+			"script is removed with content", `<p>Hi</p><script type="text/javascript">alert("<p>")</script>`,
+			`<p>Hi</p>`, []HTMLRemoval{{Reason: HTMLRemovalScript, Tag: "script"}},
+		},
+		{
+			"event handler and javascript URL are removed",
+			`<a href="JavaScript:alert(1)" onclick="steal()">Link</a>`,
+			`<a>Link</a>`,
+			[]HTMLRemoval{
+				{Attribute: "href", Reason: HTMLRemovalScript, Tag: "a", Value: "JavaScript:alert(1)"},
+				{Attribute: "onclick", Reason: HTMLRemovalScript, Tag: "a", Value: "steal()"},
+			},
+		},
+		{
+			"external form is removed",
+			`<form action="https://evil.tld/post"><input name="password"></form>`,
+			``,
+			[]HTMLRemoval{
+				{Reason: HTMLRemovalExternalForm, Tag: "form", Value: "https://evil.tld/post"},
+				{Reason: HTMLRemovalDisallowedTag, Tag: "input"},
+			},
+		},
+		{
+			"tracking pixels are removed",
+			`<img src="https://track.tld/p.gif" width="1" height="1"><img src="https://track.tld/q.gif" ` +
+				`style="width: 0; height: 0"><img src="cid:spacer" width="100" height="1">` +
+				`<img src="cid:hidden" style="display:none"><img src="cid:logo" width="100" style="line-height:1px">`,
+			`<img src="cid:logo" width="100" style="line-height:1px">`,
+			[]HTMLRemoval{
+				{Reason: HTMLRemovalTrackingPixel, Tag: "img", Value: "https://track.tld/p.gif"},
+				{Reason: HTMLRemovalTrackingPixel, Tag: "img", Value: "https://track.tld/q.gif"},
+				{Reason: HTMLRemovalTrackingPixel, Tag: "img", Value: "cid:spacer"},
+				{Reason: HTMLRemovalTrackingPixel, Tag: "img", Value: "cid:hidden"},
+			},
+		},
+		{
+			"style overrides the dimension attributes",
+			`<img src="cid:a" width="1" height="1" style="width: 100px; height: 50px">` +
+				`<img src="cid:b" width="100" height="50" style="height: 1PX !important">`,
+			`<img src="cid:a" width="1" height="1" style="width: 100px; height: 50px">`,
+			[]HTMLRemoval{{Reason: HTMLRemovalTrackingPixel, Tag: "img", Value: "cid:b"}},
+		},
+		{
+			"disallowed tags keep their content", `<blink>Hello</blink> <marquee>World</marquee>`,
+			`Hello World`,
+			[]HTMLRemoval{
+				{Reason: HTMLRemovalDisallowedTag, Tag: "blink"},
+				{Reason: HTMLRemovalDisallowedTag, Tag: "marquee"},
+			},
+		},
+		{
+			"head and comments are removed", `<html><head><title>T</title><style>p{}</style></head>` +
+				`<!-- comment --><body bgcolor="#fff">Text</body></html>`,
+			`<html><body bgcolor="#fff">Text</body></html>`,
+			[]HTMLRemoval{{Reason: HTMLRemovalContent, Tag: "head"}},
+		},
+		{
+			"unsafe URL and style are removed",
+			`<a href="data:text/html;base64,AAAA" style="color: red">A</a>` +
+				`<div style="background: URL(https://track.tld/bg.png)">B</div>`,
+			`<a style="color: red">A</a><div>B</div>`,
+			[]HTMLRemoval{
+				{Attribute: "href", Reason: HTMLRemovalUnsafeURL, Tag: "a", Value: "data:text/html;base64,AAAA"},
+				{
+					Attribute: "style", Reason: HTMLRemovalUnsafeStyle, Tag: "div",
+					Value: "background: URL(https://track.tld/bg.png)",
+				},
+			},
+		},
+		{
+			"escaped and commented styles are removed",
+			`<p style="background:u\72l(http://evil/x.png)">A</p><p style="background:u/**/rl(http://evil/y.png)">B</p>` +
+				`<p style="color: \72 ed">C</p>`,
+			`<p>A</p><p>B</p><p style="color: \72 ed">C</p>`,
+			[]HTMLRemoval{
+				{
+					Attribute: "style", Reason: HTMLRemovalUnsafeStyle, Tag: "p",
+					Value: `background:u\72l(http://evil/x.png)`,
+				},
+				{
+					Attribute: "style", Reason: HTMLRemovalUnsafeStyle, Tag: "p",
+					Value: "background:u/**/rl(http://evil/y.png)",
+				},
+			},
+		},
+		{
+			"self-closing script keeps script content",
+			`<script/>alert(1)</script><p>A</p><!-->alert(2)<p>B</p>`,
+			`<p>A</p>alert(2)<p>B</p>`,
+			[]HTMLRemoval{{Reason: HTMLRemovalScript, Tag: "script"}},
+		},
+		{
+			"disallowed attributes are removed and values escaped",
+			`<a href="/relative?a=1&amp;b=2" data-x="y" title='"quoted"'>A</a> 1 < 2`,
+			`<a href="/relative?a=1&amp;b=2" title="&#34;quoted&#34;">A</a> 1 &lt; 2`,
+			[]HTMLRemoval{{Attribute: "data-x", Reason: HTMLRemovalDisallowedAttribute, Tag: "a", Value: "y"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := NewMsg()
+			message.SetBodyString(TypeTextHTML, tt.html)
+			got, removals, err := message.SanitizedHTML(nil)
+			if err != nil {
+				t.Fatalf("failed to sanitize HTML: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("unexpected sanitized HTML, want: %q, got: %q", tt.want, got)
+			}
+			if len(removals) != len(tt.removals) {
+				t.Fatalf("unexpected removals, want: %+v, got: %+v", tt.removals, removals)
+			}
+			for i := range removals {
+				if removals[i] != tt.removals[i] {
+					t.Errorf("unexpected removal %d, want: %+v, got: %+v", i, tt.removals[i], removals[i])
+				}
+			}
+		})
+	}
+	t.Run("external images are blocked by policy", func(t *testing.T) {
+		message := NewMsg()
+		message.SetBodyString(TypeTextPlain, "Plain")
+		message.AddAlternativeString(TypeTextHTML, `<img src="https://domain.tld/logo.png" alt="Logo">`)
+		policy := DefaultHTMLPolicy()
+		policy.BlockExternalImages = true
+		got, removals, err := message.SanitizedHTML(policy)
+		if err != nil {
+			t.Fatalf("failed to sanitize HTML: %s", err)
+		}
+		if got != "" || len(removals) != 1 || removals[0].Reason != HTMLRemovalExternalImage {
+			t.Errorf("expected external image to be removed, got: %q, %+v", got, removals)
+		}
+	})
+	t.Run("message without HTML part fails", func(t *testing.T) {
+		message := testMessage(t)
+		if _, _, err := message.SanitizedHTML(nil); !errors.Is(err, ErrNoHTMLPart) {
+			t.Errorf("expected error: %s, got: %s", ErrNoHTMLPart, err)
+		}
+	})
+}
+
+func TestHTMLRemovalReason_String(t *testing.T) {
+	tests := []struct {
+		reason HTMLRemovalReason
+		want   string
+	}{
+		{HTMLRemovalDisallowedTag, "disallowed tag"},
+		{HTMLRemovalDisallowedAttribute, "disallowed attribute"},
+		{HTMLRemovalScript, "script"},
+		{HTMLRemovalExternalForm, "external form"},
+		{HTMLRemovalTrackingPixel, "tracking pixel"},
+		{HTMLRemovalExternalImage, "external image"},
+		{HTMLRemovalUnsafeURL, "unsafe URL"},
+		{HTMLRemovalUnsafeStyle, "unsafe style"},
+		{HTMLRemovalContent, "element with content"},
+		{999, "unknown reason"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.reason.String(); got != tt.want {
+				t.Errorf("unexpected string, want: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTokenizeHTML(t *testing.T) {
+	tokens := tokenizeHTML(`<!DOCTYPE html><P Class=a id='b' hidden>x</P><unclosed`)
+	want := []htmlToken{
+		{kind: htmlTokenComment, data: "<!DOCTYPE html>"},
+		{
+			kind: htmlTokenStartTag, data: "p",
+			attributes: []htmlAttribute{{name: "class", value: "a"}, {name: "id", value: "b"}, {name: "hidden"}},
+		},
+		{kind: htmlTokenText, data: "x"},
+		{kind: htmlTokenEndTag, data: "p"},
+		{kind: htmlTokenComment},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("unexpected tokens, want: %+v, got: %+v", want, tokens)
+	}
+	for i := range want {
+		if tokens[i].String() != want[i].String() || tokens[i].kind != want[i].kind {
+			t.Errorf("unexpected token %d, want: %+v, got: %+v", i, want[i], tokens[i])
+		}
+	}
+}
+
+// TestTokenizeHTML_html5lib checks the tokenizer against cases derived from the html5lib tokenizer
+// tests. The tokens are rendered as string, with comments (including ignored constructs) as "#".
+func TestTokenizeHTML_html5lib(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"empty comment", `<!-->x`, []string{"#", "x"}},
+		{"empty comment with dash", `<!--->x`, []string{"#", "x"}},
+		{"comment with bang end", `<!-- a --!>x`, []string{"#", "x"}},
+		{"comment with double dash", `<!-- a -- b -->x`, []string{"#", "x"}},
+		{"unterminated comment", `<!-- a`, []string{"#"}},
+		{"empty end tag", `a</>b`, []string{"a", "#", "b"}},
+		{"bogus end tag", `</ a>b`, []string{"#", "b"}},
+		{"end tag at end of file", `a</`, []string{"a", "<", "/"}},
+		{"less-than sign before non-letter", `1 < 2 <3`, []string{"1 ", "<", " 2 ", "<", "3"}},
+		{"processing instruction", `<?xml version="1.0"?>x`, []string{"#", "x"}},
+		{"end tag with quoted attribute", `</p title=">">x`, []string{"</p>", "x"}},
+		{"self-closing script", `<script/>a</script>`, []string{"<script>", "a", "</script>"}},
+		{"self-closing void element", `<br/>`, []string{"<br />"}},
+		{"self-closing non-void element", `<div/>`, []string{"<div>"}},
+		{"duplicate attributes", `<p a=1 A=2>`, []string{`<p a="1">`}},
+		{"attribute starting with equals", `<p =a>`, []string{`<p =a="">`}},
+		{"attribute with quote in name", `<p a"b=c>`, []string{`<p a"b="c">`}},
+		{"tag name with equals", `<p=a>`, []string{`<p=a>`}},
+		{"slash between attributes", `<p a/b>`, []string{`<p a="" b="">`}},
+		{"unquoted attribute with slash", `<a href=/x/>`, []string{`<a href="/x/">`}},
+		{"character references in attribute", `<p a="&amp;&lt;">`, []string{`<p a="&amp;&lt;">`}},
+		{"unterminated attribute value", `<p a="b>c`, []string{"#"}},
+		{"raw text end tag", `<style>a</styles></style >b`, []string{"<style>", "a</styles>", "</style>", "b"}},
+		{"raw text case", `<SCRIPT>a</Script>`, []string{"<script>", "a", "</script>"}},
+		{"plaintext", `<plaintext>a</plaintext><b>`, []string{"<plaintext>", "a</plaintext><b>"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, token := range tokenizeHTML(tt.input) {
+				if token.kind == htmlTokenComment {
+					got = append(got, "#")
+					continue
+				}
+				got = append(got, token.String())
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("unexpected tokens, want: %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// ErrNoTextPart is returned if a Msg has neither a "text/plain" nor a "text/html" part.
+var ErrNoTextPart = errors.New("message has no text/plain or text/html part")
+
+// htmlTextBlockTags holds the elements that start and end a new line in the plain text representation
+// of an HTML document. The value is the number of line breaks, where 2 results in an empty line.
+var htmlTextBlockTags = map[string]int{
+	"address": 1, "article": 1, "aside": 1, "blockquote": 2, "dd": 1, "div": 1, "dl": 1, "dt": 1,
+	"fieldset": 1, "figcaption": 1, "figure": 1, "footer": 1, "form": 1, "h1": 2, "h2": 2, "h3": 2,
+	"h4": 2, "h5": 2, "h6": 2, "header": 1, "hr": 2, "li": 1, "main": 1, "nav": 1, "ol": 2, "p": 2,
+	"pre": 2, "section": 1, "table": 2, "tr": 1, "ul": 2,
+}
+
+// htmlTextWriter builds the plain text representation of an HTML document with collapsed whitespace.
+type htmlTextWriter struct {
+	// builder holds the text written so far.
+	builder strings.Builder
+
+	// footnotes holds the link targets in the order they were referenced.
+	footnotes []string
+
+	// lineBreaks is the number of pending line breaks that are written before the next text.
+	lineBreaks int
+
+	// space indicates that a space is written before the next text.
+	space bool
+}
+
+// PlainText returns the plain text content of the Msg.
+//
+// If the Msg has a "text/plain" part, the content of the first one is returned as is. Otherwise, the
+// plain text is derived from the first "text/html" part: tags are removed, block elements like
+// paragraphs, headings and list items are separated by line breaks and the content of elements like
+// "head", "script" and "style" is dropped. Link targets are appended as numbered footnotes, so that
+// they stay available without cluttering the text. This is useful for search indexing or notification
+// previews of received mails that only provide an HTML body.
+//
+// Returns:
+//   - The plain text content of the Msg.
+//   - An error if the Msg has neither a "text/plain" nor a "text/html" part or if the content of the
+//     part cannot be read; otherwise, returns nil.
+func (m *Msg) PlainText() (string, error) {
+	var htmlPart *Part
+	for _, part := range m.GetParts() {
+		switch part.GetContentType() {
+		case TypeTextPlain:
+			content, err := part.GetContent()
+			if err != nil {
+				return "", fmt.Errorf("failed to read text part: %w", err)
+			}
+			return string(content), nil
+		case TypeTextHTML:
+			if htmlPart == nil {
+				htmlPart = part
+			}
+		}
+	}
+	if htmlPart == nil {
+		return "", ErrNoTextPart
+	}
+	content, err := htmlPart.GetContent()
+	if err != nil {
+		return "", fmt.Errorf("failed to read HTML part: %w", err)
+	}
+	return htmlToPlainText(string(content)), nil
+}
+
+// htmlToPlainText converts the given HTML document into readable plain text with link footnotes.
+func htmlToPlainText(document string) string {
+	writer := &htmlTextWriter{}
+	skipTag := ""
+	skipDepth := 0
+	preDepth := 0
+	linkTarget := ""
+	linkStart := 0
+	for _, token := range tokenizeHTML(document) {
+		if skipDepth > 0 {
+			switch {
+			case token.kind == htmlTokenStartTag && token.data == skipTag && !token.selfClosing:
+				skipDepth++
+			case token.kind == htmlTokenEndTag && token.data == skipTag:
+				skipDepth--
+			}
+			continue
+		}
+		switch token.kind {
+		case htmlTokenText:
+			if preDepth > 0 {
+				writer.writePreformatted(html.UnescapeString(token.data))
+				continue
+			}
+			writer.writeText(html.UnescapeString(token.data))
+		case htmlTokenStartTag:
+			switch token.data {
+			case "br":
+				writer.lineBreak(1)
+				writer.flush(true)
+			case "img":
+				if alt, ok := token.attribute("alt"); ok {
+					writer.writeText(alt)
+				}
+			case "a":
+				linkTarget, _ = token.attribute("href")
+				linkTarget = strings.TrimSpace(linkTarget)
+				linkStart = writer.builder.Len()
+			}
+			if htmlContentTags[token.data] && !token.selfClosing {
+				skipTag, skipDepth = token.data, 1
+				continue
+			}
+			if breaks, ok := htmlTextBlockTags[token.data]; ok {
+				writer.lineBreak(breaks)
+			}
+			switch token.data {
+			case "li":
+				writer.writeText("- ")
+			case "pre":
+				preDepth++
+			}
+		case htmlTokenEndTag:
+			if breaks, ok := htmlTextBlockTags[token.data]; ok {
+				writer.lineBreak(breaks)
+			}
+			switch token.data {
+			case "a":
+				if isHTMLTextFootnote(linkTarget, writer.builder.String()[linkStart:]) {
+					writer.writeFootnote(linkTarget)
+				}
+				linkTarget = ""
+			case "pre":
+				if preDepth > 0 {
+					preDepth--
+				}
+			case "td", "th":
+				writer.space = true
+			}
+		}
+	}
+	return writer.String()
+}
+
+// isHTMLTextFootnote reports whether the given link target is added as footnote to the given link
+// text.
+func isHTMLTextFootnote(target, text string) bool {
+	if target == "" || strings.HasPrefix(target, "#") || strings.HasPrefix(strings.ToLower(target), "javascript:") {
+		return false
+	}
+	text = strings.TrimSpace(text)
+	return text != target && "mailto:"+text != target
+}
+
+// writeText writes the given text with collapsed whitespace.
+func (w *htmlTextWriter) writeText(text string) {
+	if text == "" {
+		return
+	}
+	if strings.TrimLeft(text, " \t\r\n\f") != text {
+		w.space = true
+	}
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return
+	}
+	w.flush(false)
+	w.builder.WriteString(strings.Join(words, " "))
+	w.space = strings.TrimRight(text, " \t\r\n\f") != text
+}
+
+// writePreformatted writes the given text of a "pre" element with its whitespace preserved.
+func (w *htmlTextWriter) writePreformatted(text string) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return
+	}
+	w.flush(false)
+	w.builder.WriteString(text)
+}
+
+// writeFootnote adds the given link target as footnote and writes its reference. A link target that
+// is referenced multiple times uses the same footnote.
+func (w *htmlTextWriter) writeFootnote(target string) {
+	number := len(w.footnotes) + 1
+	for i, footnote := range w.footnotes {
+		if footnote == target {
+			number = i + 1
+			break
+		}
+	}
+	if number > len(w.footnotes) {
+		w.footnotes = append(w.footnotes, target)
+	}
+	w.space = true
+	w.flush(false)
+	w.builder.WriteString(fmt.Sprintf("[%d]", number))
+}
+
+// lineBreak requests the given number of line breaks before the next text.
+func (w *htmlTextWriter) lineBreak(breaks int) {
+	if breaks > w.lineBreaks {
+		w.lineBreaks = breaks
+	}
+	w.space = false
+}
+
+// flush writes the pending line breaks or space. If force is set, pending line breaks are written
+// even at the start of the text.
+func (w *htmlTextWriter) flush(force bool) {
+	if w.lineBreaks > 0 {
+		if w.builder.Len() > 0 || force {
+			w.builder.WriteString(strings.Repeat("\n", w.lineBreaks))
+		}
+		w.lineBreaks, w.space = 0, false
+		return
+	}
+	if w.space && w.builder.Len() > 0 && !strings.HasSuffix(w.builder.String(), "\n") {
+		w.builder.WriteString(" ")
+	}
+	w.space = false
+}
+
+// String returns the plain text with trimmed surrounding whitespace, followed by the footnotes.
+func (w *htmlTextWriter) String() string {
+	text := strings.TrimSpace(w.builder.String())
+	if len(w.footnotes) == 0 {
+		return text
+	}
+	var builder strings.Builder
+	builder.WriteString(text)
+	builder.WriteString("\n\n")
+	for i, footnote := range w.footnotes {
+		builder.WriteString(fmt.Sprintf("[%d] %s\n", i+1, footnote))
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMsg_PlainText(t *testing.T) {
+	t.Run("text/plain part is preferred", func(t *testing.T) {
+		message := NewMsg()
+		message.SetBodyString(TypeTextPlain, "Plain  text\n")
+		message.AddAlternativeString(TypeTextHTML, "<p>HTML</p>")
+		got, err := message.PlainText()
+		if err != nil {
+			t.Fatalf("failed to get plain text: %s", err)
+		}
+		if got != "Plain  text\n" {
+			t.Errorf("unexpected plain text, want: %q, got: %q", "Plain  text\n", got)
+		}
+	})
+	t.Run("text is derived from HTML-only message", func(t *testing.T) {
+		message, err := EMLToMsgFromString("From: <valid-from@domain.tld>\r\nTo: <valid-to@domain.tld>\r\n" +
+			"Subject: HTML only\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=UTF-8\r\n" +
+			"Content-Transfer-Encoding: quoted-printable\r\n\r\n" +
+			"<html><head><title>Ignored</title></head><body><h1>Welcome</h1><p>Visit <a href=3D\"https://=\r\n" +
+			"domain.tld\">our site</a>.</p></body></html>\r\n")
+		if err != nil {
+			t.Fatalf("failed to parse EML: %s", err)
+		}
+		got, err := message.PlainText()
+		if err != nil {
+			t.Fatalf("failed to get plain text: %s", err)
+		}
+		want := "Welcome\n\nVisit our site [1].\n\n[1] https://domain.tld"
+		if got != want {
+			t.Errorf("unexpected plain text, want: %q, got: %q", want, got)
+		}
+	})
+	t.Run("message without text part fails", func(t *testing.T) {
+		message := NewMsg()
+		message.SetBodyString(TypeAppOctetStream, "data")
+		if _, err := message.PlainText(); !errors.Is(err, ErrNoTextPart) {
+			t.Errorf("expected error: %s, got: %s", ErrNoTextPart, err)
+		}
+	})
+}
+
+func TestHTMLToPlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"whitespace is collapsed", "  Hello \n\t <b>World</b> ! ", "Hello World !"},
+		{"entities are unescaped", "Fish &amp; Chips &lt;3", "Fish & Chips <3"},
+		{"line breaks", "Line 1<br>Line 2<br/><br/>Line 3", "Line 1\nLine 2\n\nLine 3"},
+		{"paragraphs", "<p>One</p><p>Two</p><div>Three</div>", "One\n\nTwo\n\nThree"},
+		{"lists", "<ul><li>First</li><li>Second</li></ul>Done", "- First\n- Second\n\nDone"},
+		{
+			"script and style are removed", `<style>p { color: red; }</style><script>alert("x")</script>Text`,
+			"Text",
+		},
+		{"preformatted text", "<pre>a  b\n  c</pre>", "a  b\n  c"},
+		{"image alt text", `<img src="cid:logo" alt="Logo"> Company`, "Logo Company"},
+		{"table cells", "<table><tr><td>A</td><td>B</td></tr><tr><td>C</td></tr></table>", "A B\nC"},
+		{
+			"links as footnotes",
+			`<a href="https://a.tld">A</a>, <a href="https://b.tld">B</a> and <a href="https://a.tld">again</a>`,
+			"A [1], B [2] and again [1]\n\n[1] https://a.tld\n[2] https://b.tld",
+		},
+		{
+			"links without footnotes",
+			`<a href="https://a.tld">https://a.tld</a> <a href="mailto:x@domain.tld">x@domain.tld</a> ` +
+				`<a href="#top">Top</a> <a>None</a>`,
+			"https://a.tld x@domain.tld Top None",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := htmlToPlainText(tt.html); got != tt.want {
+				t.Errorf("unexpected plain text, want: %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"html"
+	"strings"
+)
+
+// List of htmlTokenKind values
+const (
+	// htmlTokenText is a text token.
+	htmlTokenText htmlTokenKind = iota
+
+	// htmlTokenStartTag is a start tag token, i. e. "<p>" or "<br/>".
+	htmlTokenStartTag
+
+	// htmlTokenEndTag is an end tag token, i. e. "</p>".
+	htmlTokenEndTag
+
+	// htmlTokenComment is a comment, doctype or processing instruction token, or a construct that is
+	// ignored by browsers, in which case the data is empty.
+	htmlTokenComment
+)
+
+// htmlRawTextTags holds the elements whose content is not parsed as HTML.
+var htmlRawTextTags = map[string]bool{
+	"iframe": true, "noembed": true, "noframes": true, "plaintext": true, "script": true, "style": true,
+	"textarea": true, "title": true, "xmp": true,
+}
+
+// htmlVoidTags holds the void elements, which have no content and for which the self-closing flag of a
+// start tag is acknowledged. Browsers ignore the flag for all other elements, so that "<script/>" starts
+// a script element like "<script>" does.
+var htmlVoidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true, "input": true,
+	"link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+type (
+	// htmlTokenKind represents the kind of an htmlToken.
+	htmlTokenKind int
+
+	// htmlAttribute represents a single attribute of an HTML tag with its unescaped value.
+	htmlAttribute struct {
+		name  string
+		value string
+	}
+
+	// htmlToken represents a single token of an HTML document.
+	htmlToken struct {
+		// attributes holds the attributes of a start tag.
+		attributes []htmlAttribute
+
+		// data holds the raw text of a text or comment token, or the lower-case name of a tag.
+		data string
+
+		// kind is the htmlTokenKind of the token.
+		kind htmlTokenKind
+
+		// raw holds the token as it appears in the document.
+		raw string
+
+		// selfClosing indicates that a start tag of a void element is closed with "/>".
+		selfClosing bool
+	}
+)
+
+// tokenizeHTML splits the given HTML document into a list of htmlToken values.
+//
+// The tokenizer is deliberately simple and does not build a document tree or validate the nesting of
+// elements. It follows the tokenization rules of the HTML standard in the cases that matter for
+// filtering: comments end at the first "-->" or "--!>" and "<!-->" is an empty comment, the self-closing
+// flag is only kept for void elements, duplicate attributes are dropped and tags that are not closed
+// before the end of the document are ignored. The content of raw text elements like "script" or "style"
+// is returned as a single text token and ends at the matching end tag; the escaped states of script
+// content are not tracked. Content after a "plaintext" start tag is always text.
+func tokenizeHTML(document string) []htmlToken {
+	var tokens []htmlToken
+	for len(document) > 0 {
+		index := strings.IndexByte(document, '<')
+		if index != 0 {
+			if index < 0 {
+				index = len(document)
+			}
+			tokens = append(tokens, htmlToken{kind: htmlTokenText, data: document[:index], raw: document[:index]})
+			document = document[index:]
+			continue
+		}
+
+		token, rest, ok := readHTMLTag(document)
+		if !ok {
+			tokens = append(tokens, htmlToken{kind: htmlTokenText, data: "<", raw: "<"})
+			document = document[1:]
+			continue
+		}
+		token.raw = document[:len(document)-len(rest)]
+		tokens = append(tokens, token)
+		document = rest
+		if token.kind == htmlTokenStartTag && htmlRawTextTags[token.data] {
+			end := len(document)
+			if token.data != "plaintext" {
+				end = indexHTMLEndTag(document, token.data)
+			}
+			if end > 0 {
+				tokens = append(tokens, htmlToken{kind: htmlTokenText, data: document[:end], raw: document[:end]})
+			}
+			document = document[end:]
+		}
+	}
+	return tokens
+}
+
+// readHTMLTag reads a tag, comment, doctype or processing instruction at the start of the given
+// document, which must start with "<".
+//
+// Constructs that browsers ignore, like "</>" or a tag that is not closed before the end of the
+// document, are returned as comment token without data, so that they are dropped like comments.
+//
+// Returns:
+//   - The htmlToken, the remaining document and true if a tag was read, or false if the "<" does not
+//     start a tag and has to be treated as text.
+func readHTMLTag(document string) (htmlToken, string, bool) {
+	switch {
+	case strings.HasPrefix(document, "<!--"):
+		return readHTMLComment(document)
+	case strings.HasPrefix(document, "<!"), strings.HasPrefix(document, "<?"):
+		return readHTMLBogusComment(document, 2)
+	case strings.HasPrefix(document, "</"):
+		if len(document) < 3 {
+			return htmlToken{}, "", false
+		}
+		if document[2] == '>' {
+			return htmlToken{kind: htmlTokenComment}, document[3:], true
+		}
+		if !isHTMLLetter(document[2]) {
+			return readHTMLBogusComment(document, 2)
+		}
+		name, rest := readHTMLTagName(document[2:])
+		// Attributes of end tags are parsed, so that a quoted ">" does not end the tag, but discarded
+		if _, rest, ok := readHTMLAttributes(rest); ok {
+			return htmlToken{kind: htmlTokenEndTag, data: name}, rest, true
+		}
+		return htmlToken{kind: htmlTokenComment}, "", true
+	}
+	if len(document) < 2 || !isHTMLLetter(document[1]) {
+		return htmlToken{}, "", false
+	}
+
+	name, rest := readHTMLTagName(document[1:])
+	token := htmlToken{kind: htmlTokenStartTag, data: name}
+	var selfClosing, ok bool
+	token.attributes, selfClosing, rest, ok = readHTMLStartTagAttributes(rest)
+	if !ok {
+		return htmlToken{kind: htmlTokenComment}, "", true
+	}
+	token.selfClosing = selfClosing && htmlVoidTags[name]
+	return token, rest, true
+}
+
+// readHTMLComment reads a comment at the start of the given document, which must start with "<!--".
+func readHTMLComment(document string) (htmlToken, string, bool) {
+	body := document[4:]
+	switch {
+	case strings.HasPrefix(body, ">"):
+		return htmlToken{kind: htmlTokenComment, data: document[:5]}, document[5:], true
+	case strings.HasPrefix(body, "->"):
+		return htmlToken{kind: htmlTokenComment, data: document[:6]}, document[6:], true
+	}
+	end, length := strings.Index(body, "-->"), 3
+	if bang := strings.Index(body, "--!>"); bang >= 0 && (end < 0 || bang < end) {
+		end, length = bang, 4
+	}
+	if end < 0 {
+		return htmlToken{kind: htmlTokenComment, data: document}, "", true
+	}
+	end += 4 + length
+	return htmlToken{kind: htmlTokenComment, data: document[:end]}, document[end:], true
+}
+
+// readHTMLBogusComment reads a doctype, processing instruction or other bogus comment, which ends at
+// the first ">" after the given offset.
+func readHTMLBogusComment(document string, offset int) (htmlToken, string, bool) {
+	end := strings.IndexByte(document[offset:], '>')
+	if end < 0 {
+		return htmlToken{kind: htmlTokenComment, data: document}, "", true
+	}
+	end += offset + 1
+	return htmlToken{kind: htmlTokenComment, data: document[:end]}, document[end:], true
+}
+
+// readHTMLStartTagAttributes reads the attributes of a start tag up to and including the closing ">".
+//
+// Returns:
+//   - The attributes of the tag. Only the first of multiple attributes with the same name is kept.
+//   - True if the tag is closed with "/>".
+//   - The remaining document after the tag.
+//   - False if the document ends before the tag is closed.
+func readHTMLStartTagAttributes(document string) ([]htmlAttribute, bool, string, bool) {
+	var attributes []htmlAttribute
+	rest := document
+	for {
+		trimmed := strings.TrimLeft(rest, " \t\r\n\f/")
+		if trimmed == "" {
+			return nil, false, "", false
+		}
+		if trimmed[0] == '>' {
+			selfClosing := len(trimmed) < len(rest) && strings.HasSuffix(rest[:len(rest)-len(trimmed)], "/")
+			return attributes, selfClosing, trimmed[1:], true
+		}
+		var attribute htmlAttribute
+		attribute, rest = readHTMLAttribute(trimmed)
+		if !hasHTMLAttribute(attributes, attribute.name) {
+			attributes = append(attributes, attribute)
+		}
+	}
+}
+
+// readHTMLAttributes reads and discards the attributes of an end tag up to and including the closing ">".
+//
+// Returns:
+//   - The attributes of the tag.
+//   - The remaining document after the tag.
+//   - False if the document ends before the tag is closed.
+func readHTMLAttributes(document string) ([]htmlAttribute, string, bool) {
+	attributes, _, rest, ok := readHTMLStartTagAttributes(document)
+	return attributes, rest, ok
+}
+
+// readHTMLTagName reads a tag name and returns it in lower-case together with the remaining document.
+func readHTMLTagName(document string) (string, string) {
+	end := strings.IndexAny(document, " \t\r\n\f/>")
+	if end < 0 {
+		end = len(document)
+	}
+	return strings.ToLower(document[:end]), document[end:]
+}
+
+// readHTMLAttribute reads a single attribute with an optional quoted or unquoted value and returns
+// it together with the remaining document. The document must not start with whitespace, "/" or ">".
+func readHTMLAttribute(document string) (htmlAttribute, string) {
+	var attribute htmlAttribute
+	// A leading "=" is part of the attribute name
+	end := strings.IndexAny(document[1:], " \t\r\n\f/>=")
+	if end < 0 {
+		end = len(document) - 1
+	}
+	attribute.name, document = strings.ToLower(document[:end+1]), document[end+1:]
+	rest := strings.TrimLeft(document, " \t\r\n\f")
+	if !strings.HasPrefix(rest, "=") {
+		return attribute, document
+	}
+	rest = strings.TrimLeft(rest[1:], " \t\r\n\f")
+	if rest == "" {
+		return attribute, rest
+	}
+	var value string
+	switch quote := rest[0]; quote {
+	case '"', '\'':
+		end := strings.IndexByte(rest[1:], quote)
+		if end < 0 {
+			return attribute, ""
+		}
+		value, rest = rest[1:end+1], rest[end+2:]
+	default:
+		end := strings.IndexAny(rest, " \t\r\n\f>")
+		if end < 0 {
+			end = len(rest)
+		}
+		value, rest = rest[:end], rest[end:]
+	}
+	attribute.value = html.UnescapeString(value)
+	return attribute, rest
+}
+
+// hasHTMLAttribute reports whether the list of attributes contains an attribute with the given name.
+func hasHTMLAttribute(attributes []htmlAttribute, name string) bool {
+	for _, attribute := range attributes {
+		if attribute.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// indexHTMLEndTag returns the index of the end tag of the given raw text element in the document,
+// or the length of the document if there is no end tag.
+func indexHTMLEndTag(document, name string) int {
+	lower := strings.ToLower(document)
+	offset := 0
+	for {
+		index := strings.Index(lower[offset:], "</"+name)
+		if index < 0 {
+			return len(document)
+		}
+		index += offset
+		next := index + len(name) + 2
+		if next >= len(lower) || strings.IndexByte(" \t\r\n\f/>", lower[next]) >= 0 {
+			return index
+		}
+		offset = next
+	}
+}
+
+// isHTMLLetter reports whether the given byte is an ASCII letter.
+func isHTMLLetter(char byte) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+// String returns the HTML representation of the htmlToken. Attribute values are escaped, text and
+// comments are returned as is.
+func (t htmlToken) String() string {
+	switch t.kind {
+	case htmlTokenStartTag:
+		var tag strings.Builder
+		tag.WriteString("<" + t.data)
+		for _, attribute := range t.attributes {
+			tag.WriteString(" " + attribute.name + `="` + html.EscapeString(attribute.value) + `"`)
+		}
+		if t.selfClosing {
+			tag.WriteString(" /")
+		}
+		tag.WriteString(">")
+		return tag.String()
+	case htmlTokenEndTag:
+		return "</" + t.data + ">"
+	}
+	return t.data
+}
+
+// attribute returns the value of the attribute with the given name and true, or false if the
+// token does not have the attribute.
+func (t htmlToken) attribute(name string) (string, bool) {
+	for _, attribute := range t.attributes {
+		if attribute.name == name {
+			return attribute.value, true
+		}
+	}
+	return "", false
+}
+
+// setAttribute sets the value of the attribute with the given name, adding the attribute if the
+// token does not have it.
+func (t *htmlToken) setAttribute(name, value string) {
+	for i := range t.attributes {
+		if t.attributes[i].name == name {
+			t.attributes[i].value = value
+			return
+		}
+	}
+	t.attributes = append(t.attributes, htmlAttribute{name: name, value: value})
+}
@@ -0,0 +1,451 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package httptransport
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	netmail "net/mail"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/wneessen/go-mail"
+)
+
+const (
+	// SendGridEndpoint is the endpoint of the SendGrid v3 mail send API.
+	SendGridEndpoint = "https://api.sendgrid.com/v3/mail/send"
+
+	// PostmarkEndpoint is the endpoint of the Postmark email API.
+	PostmarkEndpoint = "https://api.postmarkapp.com/email"
+
+	// mailgunEndpoint is the format of the endpoint of the Mailgun MIME messages API for a domain.
+	mailgunEndpoint = "https://api.mailgun.net/v3/%s/messages.mime"
+)
+
+var (
+	// ErrAPIKeyEmpty is returned if the API key or server token of an HTTP API is empty.
+	ErrAPIKeyEmpty = errors.New("API key must not be empty")
+
+	// ErrMailgunDomainEmpty is returned if the sending domain for the Mailgun API is empty.
+	ErrMailgunDomainEmpty = errors.New("mailgun domain must not be empty")
+)
+
+type (
+	// sendGridAddress is an address in a SendGrid payload.
+	sendGridAddress struct {
+		Email string `json:"email"`
+		Name  string `json:"name,omitempty"`
+	}
+
+	// sendGridAttachment is an attachment in a SendGrid payload.
+	sendGridAttachment struct {
+		Content     string `json:"content"`
+		ContentID   string `json:"content_id,omitempty"`
+		Disposition string `json:"disposition"`
+		Filename    string `json:"filename"`
+		Type        string `json:"type"`
+	}
+
+	// sendGridContent is a body of a SendGrid payload.
+	sendGridContent struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+
+	// sendGridPayload is the request body of the SendGrid v3 mail send API.
+	sendGridPayload struct {
+		Attachments      []sendGridAttachment      `json:"attachments,omitempty"`
+		Content          []sendGridContent         `json:"content"`
+		From             sendGridAddress           `json:"from"`
+		Personalizations []sendGridPersonalization `json:"personalizations"`
+		ReplyTo          *sendGridAddress          `json:"reply_to,omitempty"`
+		Subject          string                    `json:"subject"`
+	}
+
+	// sendGridPersonalization holds the recipients of a SendGrid payload.
+	sendGridPersonalization struct {
+		Bcc []sendGridAddress `json:"bcc,omitempty"`
+		Cc  []sendGridAddress `json:"cc,omitempty"`
+		To  []sendGridAddress `json:"to"`
+	}
+
+	// postmarkAttachment is an attachment in a Postmark payload.
+	postmarkAttachment struct {
+		Content     string `json:"Content"`
+		ContentID   string `json:"ContentID,omitempty"`
+		ContentType string `json:"ContentType"`
+		Name        string `json:"Name"`
+	}
+
+	// postmarkPayload is the request body of the Postmark email API.
+	postmarkPayload struct {
+		Attachments []postmarkAttachment `json:"Attachments,omitempty"`
+		Bcc         string               `json:"Bcc,omitempty"`
+		Cc          string               `json:"Cc,omitempty"`
+		From        string               `json:"From"`
+		HTMLBody    string               `json:"HtmlBody,omitempty"`
+		ReplyTo     string               `json:"ReplyTo,omitempty"`
+		Subject     string               `json:"Subject"`
+		TextBody    string               `json:"TextBody,omitempty"`
+		To          string               `json:"To"`
+	}
+
+	// messageBody is a body part of a message, as read by readMessageContent.
+	messageBody struct {
+		content     string
+		contentType string
+	}
+
+	// messageFile is an attachment or embed of a message, as read by readMessageContent.
+	messageFile struct {
+		content     []byte
+		contentID   string
+		contentType string
+		inline      bool
+		name        string
+	}
+
+	// messageContent holds the content of a message that is mapped to the JSON payloads of the
+	// HTTP APIs.
+	messageContent struct {
+		bodies  []messageBody
+		files   []messageFile
+		replyTo string
+		subject string
+	}
+)
+
+// NewSendGrid returns a new Transport that sends the messages via the SendGrid v3 mail send API.
+//
+// Parameters:
+//   - apiKey: The SendGrid API key, which is sent as bearer token.
+//   - opts: Optional Option functions to configure the Transport.
+//
+// Returns:
+//   - A pointer to the new Transport.
+//   - An error if the API key is empty or any of the options fails; otherwise, returns nil.
+//
+// References:
+//   - https://www.twilio.com/docs/sendgrid/api-reference/mail-send/mail-send
+func NewSendGrid(apiKey string, opts ...Option) (*Transport, error) {
+	if apiKey == "" {
+		return nil, ErrAPIKeyEmpty
+	}
+	return New(SendGridEndpoint, append([]Option{
+		WithEncoder(EncodeSendGrid), WithHeader("Authorization", "Bearer "+apiKey),
+	}, opts...)...)
+}
+
+// NewMailgun returns a new Transport that sends the messages via the MIME messages API of Mailgun
+// for the given sending domain. Use WithEndpoint for domains in the EU region.
+//
+// Parameters:
+//   - domain: The sending domain that is configured in Mailgun.
+//   - apiKey: The Mailgun API key, which is sent via HTTP basic authentication.
+//   - opts: Optional Option functions to configure the Transport.
+//
+// Returns:
+//   - A pointer to the new Transport.
+//   - An error if the domain or the API key is empty or any of the options fails; otherwise,
+//     returns nil.
+//
+// References:
+//   - https://documentation.mailgun.com/docs/mailgun/api-reference/openapi-final/tag/Messages/
+func NewMailgun(domain, apiKey string, opts ...Option) (*Transport, error) {
+	if domain == "" {
+		return nil, ErrMailgunDomainEmpty
+	}
+	if apiKey == "" {
+		return nil, ErrAPIKeyEmpty
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte("api:" + apiKey))
+	return New(fmt.Sprintf(mailgunEndpoint, url.PathEscape(domain)), append([]Option{
+		WithEncoder(EncodeMailgun), WithHeader("Authorization", "Basic "+credentials),
+	}, opts...)...)
+}
+
+// NewPostmark returns a new Transport that sends the messages via the Postmark email API.
+//
+// Parameters:
+//   - serverToken: The Postmark server API token.
+//   - opts: Optional Option functions to configure the Transport.
+//
+// Returns:
+//   - A pointer to the new Transport.
+//   - An error if the server token is empty or any of the options fails; otherwise, returns nil.
+//
+// References:
+//   - https://postmarkapp.com/developer/api/email-api
+func NewPostmark(serverToken string, opts ...Option) (*Transport, error) {
+	if serverToken == "" {
+		return nil, ErrAPIKeyEmpty
+	}
+	return New(PostmarkEndpoint, append([]Option{
+		WithEncoder(EncodePostmark), WithHeader("X-Postmark-Server-Token", serverToken),
+		WithHeader("Accept", "application/json"),
+	}, opts...)...)
+}
+
+// EncodeSendGrid is an Encoder that encodes a message into the JSON payload of the SendGrid v3
+// mail send API.
+//
+// The sender, the recipients, the "Reply-To" header, the subject, the body parts and the files of
+// the message are mapped to the payload. Other headers and the middlewares of the message are not
+// applied, since SendGrid generates the message itself.
+//
+// Parameters:
+//   - message: The Msg to encode.
+//
+// Returns:
+//   - The JSON payload.
+//   - The HTTP headers of the request.
+//   - An error if the message has no sender or its content cannot be read; otherwise, nil.
+func EncodeSendGrid(message *mail.Msg) ([]byte, http.Header, error) {
+	from := message.GetFrom()
+	if len(from) == 0 {
+		return nil, nil, errors.New("failed to get sender: no FROM address set")
+	}
+	content, err := readMessageContent(message)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload := sendGridPayload{
+		From: sendGridAddress{Email: from[0].Address, Name: from[0].Name},
+		Personalizations: []sendGridPersonalization{{
+			Bcc: sendGridAddresses(message.GetBcc()),
+			Cc:  sendGridAddresses(message.GetCc()),
+			To:  sendGridAddresses(message.GetTo()),
+		}},
+		Subject: content.subject,
+	}
+	if replyTo, err := netmail.ParseAddress(content.replyTo); err == nil {
+		payload.ReplyTo = &sendGridAddress{Email: replyTo.Address, Name: replyTo.Name}
+	}
+	for _, body := range content.bodies {
+		payload.Content = append(payload.Content, sendGridContent{Type: body.contentType, Value: body.content})
+	}
+	for _, file := range content.files {
+		attachment := sendGridAttachment{
+			Content:     base64.StdEncoding.EncodeToString(file.content),
+			Disposition: "attachment",
+			Filename:    file.name,
+			Type:        file.contentType,
+		}
+		if file.inline {
+			attachment.ContentID, attachment.Disposition = file.contentID, "inline"
+		}
+		payload.Attachments = append(payload.Attachments, attachment)
+	}
+	return encodeJSON(payload)
+}
+
+// EncodeMailgun is an Encoder that encodes a message into the multipart form of the Mailgun MIME
+// messages API. The complete message is sent in RFC 5322 format, so that all headers, parts and
+// middlewares of the message are preserved, and the envelope recipients, including the "Bcc"
+// recipients, are passed in the "to" fields.
+//
+// Parameters:
+//   - message: The Msg to encode.
+//
+// Returns:
+//   - The multipart form.
+//   - The HTTP headers of the request.
+//   - An error if the message has no recipients or cannot be written; otherwise, nil.
+func EncodeMailgun(message *mail.Msg) ([]byte, http.Header, error) {
+	rcpts, err := message.GetRecipients()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get recipients: %w", err)
+	}
+	buffer := bytes.NewBuffer(nil)
+	form := multipart.NewWriter(buffer)
+	for _, rcpt := range rcpts {
+		if err = form.WriteField("to", rcpt); err != nil {
+			return nil, nil, fmt.Errorf("failed to write recipient field: %w", err)
+		}
+	}
+	part, err := form.CreateFormFile("message", "message.eml")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create message field: %w", err)
+	}
+	if _, err = message.WriteTo(part); err != nil {
+		return nil, nil, fmt.Errorf("failed to write message: %w", err)
+	}
+	if err = form.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to close multipart form: %w", err)
+	}
+	header := make(http.Header)
+	header.Set("Content-Type", form.FormDataContentType())
+	return buffer.Bytes(), header, nil
+}
+
+// EncodePostmark is an Encoder that encodes a message into the JSON payload of the Postmark email
+// API.
+//
+// The sender, the recipients, the "Reply-To" header, the subject, the first "text/plain" and
+// "text/html" body parts and the files of the message are mapped to the payload. Other headers,
+// body parts of other content types and the middlewares of the message are not applied, since
+// Postmark generates the message itself.
+//
+// Parameters:
+//   - message: The Msg to encode.
+//
+// Returns:
+//   - The JSON payload.
+//   - The HTTP headers of the request.
+//   - An error if the message has no sender or its content cannot be read; otherwise, nil.
+func EncodePostmark(message *mail.Msg) ([]byte, http.Header, error) {
+	from := message.GetFromString()
+	if len(from) == 0 {
+		return nil, nil, errors.New("failed to get sender: no FROM address set")
+	}
+	content, err := readMessageContent(message)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload := postmarkPayload{
+		Bcc:     strings.Join(message.GetBccString(), ", "),
+		Cc:      strings.Join(message.GetCcString(), ", "),
+		From:    from[0],
+		ReplyTo: content.replyTo,
+		Subject: content.subject,
+		To:      strings.Join(message.GetToString(), ", "),
+	}
+	for _, body := range content.bodies {
+		switch {
+		case body.contentType == string(mail.TypeTextPlain) && payload.TextBody == "":
+			payload.TextBody = body.content
+		case body.contentType == string(mail.TypeTextHTML) && payload.HTMLBody == "":
+			payload.HTMLBody = body.content
+		}
+	}
+	for _, file := range content.files {
+		attachment := postmarkAttachment{
+			Content:     base64.StdEncoding.EncodeToString(file.content),
+			ContentType: file.contentType,
+			Name:        file.name,
+		}
+		if file.inline {
+			attachment.ContentID = "cid:" + file.contentID
+		}
+		payload.Attachments = append(payload.Attachments, attachment)
+	}
+	return encodeJSON(payload)
+}
+
+// encodeJSON encodes the given payload as JSON request body.
+func encodeJSON(payload interface{}) ([]byte, http.Header, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode JSON payload: %w", err)
+	}
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	return body, header, nil
+}
+
+// sendGridAddresses converts the given addresses into addresses of a SendGrid payload.
+func sendGridAddresses(addresses []*netmail.Address) []sendGridAddress {
+	if len(addresses) == 0 {
+		return nil
+	}
+	converted := make([]sendGridAddress, 0, len(addresses))
+	for _, address := range addresses {
+		converted = append(converted, sendGridAddress{Email: address.Address, Name: address.Name})
+	}
+	return converted
+}
+
+// readMessageContent reads the subject, the "Reply-To" header, the body parts and the files of the
+// given message. The "text/plain" body parts are returned first, since the HTTP APIs expect them
+// in front of the other bodies.
+func readMessageContent(message *mail.Msg) (*messageContent, error) {
+	content := &messageContent{}
+	wordDecoder := &mime.WordDecoder{}
+	if values := message.GetGenHeader(mail.HeaderSubject); len(values) > 0 {
+		content.subject = values[0]
+		if decoded, err := wordDecoder.DecodeHeader(values[0]); err == nil {
+			content.subject = decoded
+		}
+	}
+	if values := message.GetGenHeader(mail.HeaderReplyTo); len(values) > 0 {
+		content.replyTo = values[0]
+	}
+
+	var plainBodies, otherBodies []messageBody
+	for _, part := range message.GetParts() {
+		if part.IsDeleted() {
+			continue
+		}
+		body, err := part.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s body part: %w", part.GetContentType(), err)
+		}
+		converted := messageBody{content: string(body), contentType: string(part.GetContentType())}
+		if part.GetContentType() == mail.TypeTextPlain {
+			plainBodies = append(plainBodies, converted)
+			continue
+		}
+		otherBodies = append(otherBodies, converted)
+	}
+	content.bodies = append(plainBodies, otherBodies...)
+
+	for _, files := range []struct {
+		files  []*mail.File
+		inline bool
+	}{{message.GetAttachments(), false}, {message.GetEmbeds(), true}} {
+		for _, file := range files.files {
+			converted, err := readMessageFile(file, files.inline)
+			if err != nil {
+				return nil, err
+			}
+			content.files = append(content.files, converted)
+		}
+	}
+	return content, nil
+}
+
+// readMessageFile reads the content and the metadata of the given attachment or embed.
+func readMessageFile(file *mail.File, inline bool) (messageFile, error) {
+	if file.Writer == nil {
+		return messageFile{}, fmt.Errorf("%w: %s", mail.ErrFileWriterIsNil, file.Name)
+	}
+	buffer := bytes.NewBuffer(nil)
+	if _, err := file.Writer(buffer); err != nil {
+		return messageFile{}, fmt.Errorf("failed to read file %s: %w", file.Name, err)
+	}
+	converted := messageFile{
+		content:     buffer.Bytes(),
+		contentType: string(file.ContentType),
+		inline:      inline,
+		name:        file.Name,
+	}
+	if converted.contentType == "" {
+		converted.contentType = file.Header.Get(mail.HeaderContentType.String())
+	}
+	if converted.contentType == "" {
+		converted.contentType = mime.TypeByExtension(path.Ext(file.Name))
+	}
+	if mediaType, _, err := mime.ParseMediaType(converted.contentType); err == nil {
+		converted.contentType = mediaType
+	}
+	if converted.contentType == "" {
+		converted.contentType = "application/octet-stream"
+	}
+	if inline {
+		converted.contentID = strings.Trim(file.Header.Get(mail.HeaderContentID.String()), "<>")
+		if converted.contentID == "" {
+			converted.contentID = file.Name
+		}
+	}
+	return converted, nil
+}
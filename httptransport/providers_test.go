@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package httptransport
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/wneessen/go-mail"
+)
+
+// testRichMessage returns a Msg with an HTML alternative, an attachment and an embed.
+func testRichMessage(t *testing.T) *mail.Msg {
+	t.Helper()
+	message := testMessage(t, "alice@example.com")
+	if err := message.Cc("carol@example.com"); err != nil {
+		t.Fatalf("failed to set cc address: %s", err)
+	}
+	if err := message.Bcc("bob@example.com"); err != nil {
+		t.Fatalf("failed to set bcc address: %s", err)
+	}
+	if err := message.ReplyTo("support@example.com"); err != nil {
+		t.Fatalf("failed to set reply-to address: %s", err)
+	}
+	message.Subject("Grüße")
+	message.AddAlternativeString(mail.TypeTextHTML, `<p>Test</p><img src="cid:logo.png">`)
+	if err := message.AttachReader("report.txt", strings.NewReader("report")); err != nil {
+		t.Fatalf("failed to attach file: %s", err)
+	}
+	if err := message.EmbedReader("logo.png", strings.NewReader("logo")); err != nil {
+		t.Fatalf("failed to embed file: %s", err)
+	}
+	return message
+}
+
+func TestNewSendGrid(t *testing.T) {
+	t.Run("payload is posted", func(t *testing.T) {
+		server, requests := testServer(t, http.StatusAccepted)
+		transport, err := NewSendGrid("key", WithEndpoint(server.URL), WithHTTPClient(server.Client()))
+		if err != nil {
+			t.Fatalf("failed to create transport: %s", err)
+		}
+		if err = transport.Send(testRichMessage(t)); err != nil {
+			t.Fatalf("failed to send message: %s", err)
+		}
+		request := (*requests)[0]
+		if request.header.Get("Authorization") != "Bearer key" ||
+			request.header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request headers: %v", request.header)
+		}
+		var payload sendGridPayload
+		if err = json.Unmarshal([]byte(request.body), &payload); err != nil {
+			t.Fatalf("failed to decode payload: %s", err)
+		}
+		recipients := payload.Personalizations[0]
+		if payload.From.Email != "sender@example.com" || payload.ReplyTo.Email != "support@example.com" ||
+			recipients.To[0].Email != "alice@example.com" || recipients.Cc[0].Email != "carol@example.com" ||
+			recipients.Bcc[0].Email != "bob@example.com" {
+			t.Errorf("unexpected addresses in payload: %s", request.body)
+		}
+		if payload.Subject != "Grüße" || len(payload.Content) != 2 || payload.Content[0].Type != "text/plain" ||
+			payload.Content[1].Type != "text/html" {
+			t.Errorf("unexpected content in payload: %s", request.body)
+		}
+		if len(payload.Attachments) != 2 {
+			t.Fatalf("expected 2 attachments, got: %d", len(payload.Attachments))
+		}
+		embed := payload.Attachments[1]
+		if payload.Attachments[0].Disposition != "attachment" || payload.Attachments[0].Type != "text/plain" ||
+			embed.Disposition != "inline" || embed.ContentID != "logo.png" || embed.Type != "image/png" ||
+			embed.Content != base64.StdEncoding.EncodeToString([]byte("logo")) {
+			t.Errorf("unexpected attachments in payload: %s", request.body)
+		}
+	})
+	t.Run("empty API key", func(t *testing.T) {
+		if _, err := NewSendGrid(""); !errors.Is(err, ErrAPIKeyEmpty) {
+			t.Errorf("expected error: %s, got: %s", ErrAPIKeyEmpty, err)
+		}
+	})
+	t.Run("message without sender", func(t *testing.T) {
+		if _, _, err := EncodeSendGrid(mail.NewMsg()); err == nil {
+			t.Error("expected message without sender to fail")
+		}
+	})
+}
+
+func TestNewMailgun(t *testing.T) {
+	t.Run("MIME message is posted", func(t *testing.T) {
+		server, requests := testServer(t, http.StatusOK)
+		transport, err := NewMailgun("mg.example.com", "key", WithEndpoint(server.URL),
+			WithHTTPClient(server.Client()))
+		if err != nil {
+			t.Fatalf("failed to create transport: %s", err)
+		}
+		if err = transport.Send(testRichMessage(t)); err != nil {
+			t.Fatalf("failed to send message: %s", err)
+		}
+		request := (*requests)[0]
+		if request.header.Get("Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte("api:key")) {
+			t.Errorf("unexpected authorization header: %s", request.header.Get("Authorization"))
+		}
+		_, params, err := mime.ParseMediaType(request.header.Get("Content-Type"))
+		if err != nil {
+			t.Fatalf("failed to parse content type: %s", err)
+		}
+		form, err := multipart.NewReader(strings.NewReader(request.body), params["boundary"]).ReadForm(1024 * 1024)
+		if err != nil {
+			t.Fatalf("failed to parse multipart form: %s", err)
+		}
+		if got := form.Value["to"]; len(got) != 3 || got[2] != "bob@example.com" {
+			t.Errorf("unexpected recipients: %v", got)
+		}
+		if len(form.File["message"]) != 1 {
+			t.Errorf("expected message field, got: %v", form.File)
+		}
+	})
+	t.Run("default endpoint", func(t *testing.T) {
+		transport, err := NewMailgun("mg.example.com", "key")
+		if err != nil {
+			t.Fatalf("failed to create transport: %s", err)
+		}
+		if transport.endpoint != "https://api.mailgun.net/v3/mg.example.com/messages.mime" {
+			t.Errorf("unexpected endpoint: %s", transport.endpoint)
+		}
+	})
+	t.Run("empty domain and API key", func(t *testing.T) {
+		if _, err := NewMailgun("", "key"); !errors.Is(err, ErrMailgunDomainEmpty) {
+			t.Errorf("expected error: %s, got: %s", ErrMailgunDomainEmpty, err)
+		}
+		if _, err := NewMailgun("mg.example.com", ""); !errors.Is(err, ErrAPIKeyEmpty) {
+			t.Errorf("expected error: %s, got: %s", ErrAPIKeyEmpty, err)
+		}
+	})
+}
+
+func TestNewPostmark(t *testing.T) {
+	t.Run("payload is posted", func(t *testing.T) {
+		server, requests := testServer(t, http.StatusOK)
+		transport, err := NewPostmark("token", WithEndpoint(server.URL), WithHTTPClient(server.Client()))
+		if err != nil {
+			t.Fatalf("failed to create transport: %s", err)
+		}
+		if err = transport.Send(testRichMessage(t)); err != nil {
+			t.Fatalf("failed to send message: %s", err)
+		}
+		request := (*requests)[0]
+		if request.header.Get("X-Postmark-Server-Token") != "token" ||
+			request.header.Get("Accept") != "application/json" {
+			t.Errorf("unexpected request headers: %v", request.header)
+		}
+		var payload postmarkPayload
+		if err = json.Unmarshal([]byte(request.body), &payload); err != nil {
+			t.Fatalf("failed to decode payload: %s", err)
+		}
+		if payload.From != "<sender@example.com>" || payload.To != "<alice@example.com>" ||
+			payload.Cc != "<carol@example.com>" || payload.Bcc != "<bob@example.com>" ||
+			!strings.Contains(payload.ReplyTo, "support@example.com") {
+			t.Errorf("unexpected addresses in payload: %s", request.body)
+		}
+		if payload.Subject != "Grüße" || payload.TextBody != "Test" || !strings.HasPrefix(payload.HTMLBody, "<p>") {
+			t.Errorf("unexpected content in payload: %s", request.body)
+		}
+		if len(payload.Attachments) != 2 || payload.Attachments[0].ContentID != "" ||
+			payload.Attachments[1].ContentID != "cid:logo.png" {
+			t.Errorf("unexpected attachments in payload: %s", request.body)
+		}
+	})
+	t.Run("empty server token", func(t *testing.T) {
+		if _, err := NewPostmark(""); !errors.Is(err, ErrAPIKeyEmpty) {
+			t.Errorf("expected error: %s, got: %s", ErrAPIKeyEmpty, err)
+		}
+	})
+}
+
+func TestWithEncoder(t *testing.T) {
+	if _, err := New("https://api.example.com", WithEncoder(nil)); !errors.Is(err, ErrEncoderIsNil) {
+		t.Errorf("expected error: %s, got: %s", ErrEncoderIsNil, err)
+	}
+	if _, err := NewSendGrid("key", WithEndpoint("ftp://api.example.com")); !errors.Is(err, ErrInvalidEndpoint) {
+		t.Errorf("expected error: %s, got: %s", ErrInvalidEndpoint, err)
+	}
+}
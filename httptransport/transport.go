@@ -0,0 +1,274 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+// Package httptransport implements a mail.Transport that hands the messages over to an HTTP API
+// instead of an SMTP server, i. e. a generic endpoint that accepts message/rfc822 bodies or the
+// APIs of SendGrid, Mailgun and Postmark. Under GOOS=js and GOARCH=wasm, the requests are performed
+// via the Fetch API of the browser or edge runtime, so that messages can be sent from environments
+// without raw network sockets
+package httptransport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/wneessen/go-mail"
+)
+
+const (
+	// HeaderEnvelopeFrom is the HTTP header that holds the envelope sender of the message.
+	HeaderEnvelopeFrom = "X-Envelope-From"
+
+	// HeaderEnvelopeTo is the HTTP header that holds the envelope recipients of the message. The
+	// header is repeated for each recipient.
+	HeaderEnvelopeTo = "X-Envelope-To"
+)
+
+var (
+	// ErrInvalidEndpoint is returned if the endpoint of the Transport is not an absolute "http" or
+	// "https" URL.
+	ErrInvalidEndpoint = errors.New("endpoint must be an absolute http or https URL")
+
+	// ErrHTTPClientIsNil is returned if the http.Client provided to WithHTTPClient is nil.
+	ErrHTTPClientIsNil = errors.New("http client is nil")
+
+	// ErrEncoderIsNil is returned if the Encoder provided to WithEncoder is nil.
+	ErrEncoderIsNil = errors.New("encoder is nil")
+
+	// ErrUnexpectedStatus is returned if the HTTP API responds with a status code other than 2xx.
+	ErrUnexpectedStatus = errors.New("unexpected HTTP response status")
+)
+
+type (
+	// Option is a function that is used to configure the Transport.
+	Option func(*Transport) error
+
+	// Encoder is a function that encodes a message into the body of an HTTP request. It returns the
+	// body and the HTTP headers that describe it, i. e. the "Content-Type" header.
+	Encoder func(message *mail.Msg) ([]byte, http.Header, error)
+)
+
+// Transport is a mail.Transport that sends each message as HTTP POST request to an HTTP API.
+//
+// The request body is created by the Encoder of the Transport. By default, it holds the complete
+// message in RFC 5322 format, as encoded by EncodeRFC822. A Transport is safe for concurrent use
+// by multiple goroutines.
+type Transport struct {
+	// client is the http.Client that performs the requests.
+	client *http.Client
+
+	// encoder encodes the messages into the request bodies.
+	encoder Encoder
+
+	// endpoint is the URL of the HTTP API that the messages are posted to.
+	endpoint string
+
+	// header holds the additional HTTP headers of each request, i. e. for authorization.
+	header http.Header
+}
+
+// Ensure that the Transport satisfies the mail.Transport interface.
+var _ mail.Transport = (*Transport)(nil)
+
+// New returns a new Transport that posts the messages to the given endpoint.
+//
+// Parameters:
+//   - endpoint: The absolute "http" or "https" URL of the HTTP API.
+//   - opts: Optional Option functions to configure the Transport.
+//
+// Returns:
+//   - A pointer to the new Transport.
+//   - An error if the endpoint is invalid or any of the options fails; otherwise, returns nil.
+func New(endpoint string, opts ...Option) (*Transport, error) {
+	transport := &Transport{
+		client:   http.DefaultClient,
+		encoder:  EncodeRFC822,
+		endpoint: endpoint,
+		header:   make(http.Header),
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(transport); err != nil {
+			return nil, fmt.Errorf("failed to apply option: %w", err)
+		}
+	}
+	parsed, err := url.Parse(transport.endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidEndpoint, transport.endpoint)
+	}
+	return transport, nil
+}
+
+// WithHTTPClient sets the http.Client that performs the requests of the Transport. By default,
+// http.DefaultClient is used.
+//
+// Parameters:
+//   - client: The http.Client to use.
+//
+// Returns:
+//   - An Option function that sets the http.Client of the Transport.
+func WithHTTPClient(client *http.Client) Option {
+	return func(t *Transport) error {
+		if client == nil {
+			return ErrHTTPClientIsNil
+		}
+		t.client = client
+		return nil
+	}
+}
+
+// WithEncoder sets the Encoder that creates the request bodies of the Transport. By default,
+// EncodeRFC822 is used.
+//
+// Parameters:
+//   - encoder: The Encoder to use.
+//
+// Returns:
+//   - An Option function that sets the Encoder of the Transport.
+func WithEncoder(encoder Encoder) Option {
+	return func(t *Transport) error {
+		if encoder == nil {
+			return ErrEncoderIsNil
+		}
+		t.encoder = encoder
+		return nil
+	}
+}
+
+// WithEndpoint overrides the endpoint of the Transport, i. e. to use the EU region of an HTTP API
+// with one of the provider specific constructors.
+//
+// Parameters:
+//   - endpoint: The absolute "http" or "https" URL of the HTTP API.
+//
+// Returns:
+//   - An Option function that sets the endpoint of the Transport.
+func WithEndpoint(endpoint string) Option {
+	return func(t *Transport) error {
+		t.endpoint = endpoint
+		return nil
+	}
+}
+
+// WithHeader adds an HTTP header that is sent with each request of the Transport, i. e. the
+// "Authorization" header of the HTTP API.
+//
+// Parameters:
+//   - key: The name of the HTTP header.
+//   - value: The value of the HTTP header.
+//
+// Returns:
+//   - An Option function that adds the HTTP header to the Transport.
+func WithHeader(key, value string) Option {
+	return func(t *Transport) error {
+		t.header.Add(key, value)
+		return nil
+	}
+}
+
+// Send sends the given messages, like SendWithContext with a background context.
+//
+// Parameters:
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error if any of the messages could not be sent; otherwise, returns nil.
+func (t *Transport) Send(messages ...*mail.Msg) error {
+	return t.SendWithContext(context.Background(), messages...)
+}
+
+// SendWithContext posts each of the given messages to the HTTP API of the Transport. Sending stops
+// at the first message that fails.
+//
+// Parameters:
+//   - ctx: The context.Context that controls the cancellation of the requests.
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error if any of the messages has no sender or recipients, cannot be written or is not
+//     accepted by the HTTP API; otherwise, returns nil.
+func (t *Transport) SendWithContext(ctx context.Context, messages ...*mail.Msg) error {
+	for i, message := range messages {
+		if message == nil {
+			return fmt.Errorf("message %d is nil", i)
+		}
+		if err := t.send(ctx, message); err != nil {
+			return fmt.Errorf("failed to send message %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// send posts the given message to the HTTP API of the Transport.
+func (t *Transport) send(ctx context.Context, message *mail.Msg) error {
+	body, header, err := t.encoder(message)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range t.header {
+		request.Header[key] = append([]string(nil), values...)
+	}
+	for key, values := range header {
+		request.Header[key] = append([]string(nil), values...)
+	}
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, response.Status)
+	}
+	return nil
+}
+
+// EncodeRFC822 is the default Encoder of a Transport. It encodes the complete message in RFC 5322
+// format with the Content-Type "message/rfc822". The envelope sender and recipients, including the
+// "Bcc" recipients that are not part of the message, are passed in the HeaderEnvelopeFrom and
+// HeaderEnvelopeTo headers.
+//
+// Parameters:
+//   - message: The Msg to encode.
+//
+// Returns:
+//   - The message in RFC 5322 format.
+//   - The HTTP headers of the request.
+//   - An error if the message has no sender or recipients or cannot be written; otherwise, nil.
+func EncodeRFC822(message *mail.Msg) ([]byte, http.Header, error) {
+	from, err := message.GetSender(false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get sender: %w", err)
+	}
+	rcpts, err := message.GetRecipients()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get recipients: %w", err)
+	}
+	buffer := bytes.NewBuffer(nil)
+	if _, err = message.WriteTo(buffer); err != nil {
+		return nil, nil, fmt.Errorf("failed to write message: %w", err)
+	}
+	header := make(http.Header)
+	header.Set("Content-Type", string(mail.TypeMessageRFC822))
+	header.Set(HeaderEnvelopeFrom, from)
+	for _, rcpt := range rcpts {
+		header.Add(HeaderEnvelopeTo, rcpt)
+	}
+	return buffer.Bytes(), header, nil
+}
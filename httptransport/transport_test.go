@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package httptransport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wneessen/go-mail"
+)
+
+// testMessage returns a Msg from a test sender to the given recipient.
+func testMessage(t *testing.T, rcpt string) *mail.Msg {
+	t.Helper()
+	message := mail.NewMsg()
+	if err := message.From("sender@example.com"); err != nil {
+		t.Fatalf("failed to set sender address: %s", err)
+	}
+	if err := message.To(rcpt); err != nil {
+		t.Fatalf("failed to set recipient address: %s", err)
+	}
+	message.Subject("Test")
+	message.SetBodyString(mail.TypeTextPlain, "Test")
+	return message
+}
+
+// recordedRequest holds the relevant parts of a request received by the test server.
+type recordedRequest struct {
+	body   string
+	header http.Header
+}
+
+// testServer returns an httptest.Server that records the received requests and responds with
+// the given status code.
+func testServer(t *testing.T, status int) (*httptest.Server, *[]recordedRequest) {
+	t.Helper()
+	var requests []recordedRequest
+	var mutex sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read request body: %s", err)
+		}
+		mutex.Lock()
+		requests = append(requests, recordedRequest{body: string(body), header: r.Header.Clone()})
+		mutex.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestNew(t *testing.T) {
+	t.Run("valid endpoint", func(t *testing.T) {
+		transport, err := New("https://api.example.com/send", WithHeader("Authorization", "Bearer token"))
+		if err != nil {
+			t.Fatalf("failed to create transport: %s", err)
+		}
+		if transport.client != http.DefaultClient {
+			t.Error("expected default HTTP client")
+		}
+		if got := transport.header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("expected authorization header, got: %q", got)
+		}
+	})
+	t.Run("invalid endpoints", func(t *testing.T) {
+		for _, endpoint := range []string{"", "api.example.com/send", "ftp://api.example.com", "https://", "%zz"} {
+			if _, err := New(endpoint); !errors.Is(err, ErrInvalidEndpoint) {
+				t.Errorf("expected error for endpoint %q: %s, got: %s", endpoint, ErrInvalidEndpoint, err)
+			}
+		}
+	})
+	t.Run("nil HTTP client", func(t *testing.T) {
+		if _, err := New("https://api.example.com", WithHTTPClient(nil)); !errors.Is(err, ErrHTTPClientIsNil) {
+			t.Errorf("expected error: %s, got: %s", ErrHTTPClientIsNil, err)
+		}
+	})
+}
+
+func TestTransport_SendWithContext(t *testing.T) {
+	t.Run("messages are posted", func(t *testing.T) {
+		server, requests := testServer(t, http.StatusAccepted)
+		transport, err := New(server.URL, WithHTTPClient(server.Client()), WithHeader("Authorization", "Bearer token"))
+		if err != nil {
+			t.Fatalf("failed to create transport: %s", err)
+		}
+		message := testMessage(t, "alice@example.com")
+		if err = message.Bcc("bob@example.com"); err != nil {
+			t.Fatalf("failed to set bcc address: %s", err)
+		}
+		var sender mail.Transport = transport
+		if err = sender.SendWithContext(context.Background(), message, testMessage(t, "carol@example.com")); err != nil {
+			t.Fatalf("failed to send messages: %s", err)
+		}
+		if len(*requests) != 2 {
+			t.Fatalf("expected 2 requests, got: %d", len(*requests))
+		}
+		request := (*requests)[0]
+		if got := request.header.Get("Content-Type"); got != "message/rfc822" {
+			t.Errorf("unexpected content type: %s", got)
+		}
+		if got := request.header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("unexpected authorization header: %s", got)
+		}
+		if got := request.header.Get(HeaderEnvelopeFrom); got != "sender@example.com" {
+			t.Errorf("unexpected envelope sender: %s", got)
+		}
+		if got := request.header.Values(HeaderEnvelopeTo); len(got) != 2 || got[0] != "alice@example.com" ||
+			got[1] != "bob@example.com" {
+			t.Errorf("unexpected envelope recipients: %v", got)
+		}
+		if !strings.Contains(request.body, "To: <alice@example.com>") || strings.Contains(request.body, "bob@") {
+			t.Errorf("unexpected message in request body: %s", request.body)
+		}
+	})
+	t.Run("unexpected status", func(t *testing.T) {
+		server, _ := testServer(t, http.StatusUnauthorized)
+		transport, err := New(server.URL, WithHTTPClient(server.Client()))
+		if err != nil {
+			t.Fatalf("failed to create transport: %s", err)
+		}
+		if err = transport.Send(testMessage(t, "alice@example.com")); !errors.Is(err, ErrUnexpectedStatus) {
+			t.Errorf("expected error: %s, got: %s", ErrUnexpectedStatus, err)
+		}
+	})
+	t.Run("invalid messages are not posted", func(t *testing.T) {
+		server, requests := testServer(t, http.StatusOK)
+		transport, err := New(server.URL, WithHTTPClient(server.Client()))
+		if err != nil {
+			t.Fatalf("failed to create transport: %s", err)
+		}
+		if err = transport.Send(nil); err == nil {
+			t.Error("expected nil message to fail")
+		}
+		if err = transport.Send(mail.NewMsg()); err == nil {
+			t.Error("expected message without sender to fail")
+		}
+		if len(*requests) != 0 {
+			t.Errorf("expected no requests, got: %d", len(*requests))
+		}
+	})
+	t.Run("canceled context", func(t *testing.T) {
+		server, requests := testServer(t, http.StatusOK)
+		transport, err := New(server.URL, WithHTTPClient(server.Client()))
+		if err != nil {
+			t.Fatalf("failed to create transport: %s", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err = transport.SendWithContext(ctx, testMessage(t, "alice@example.com")); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected error: %s, got: %s", context.Canceled, err)
+		}
+		if len(*requests) != 0 {
+			t.Errorf("expected no requests, got: %d", len(*requests))
+		}
+	})
+}
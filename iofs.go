@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+)
+
+// errFSIsNil indicates that the provided fs.FS is nil.
+const errFSIsNil = "fs.FS must not be nil"
+
+// AttachFromFS adds an attachment File from an fs.FS to the Msg.
+//
+// This method allows you to attach a file from any filesystem that implements fs.FS, i. e. an
+// embed.FS, an fstest.MapFS, a zip.Reader or an adapter for a remote object storage, without
+// writing it to a temporary file first. The file is read from the filesystem each time the Msg
+// is written.
+//
+// Parameters:
+//   - fsys: The fs.FS from which the file will be retrieved.
+//   - name: The slash-separated path of the file in the filesystem.
+//   - opts: Optional parameters for customizing the attachment.
+//
+// Returns:
+//   - An error if the fs.FS is nil or the file cannot be retrieved, otherwise nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2183
+func (m *Msg) AttachFromFS(fsys fs.FS, name string, opts ...FileOption) error {
+	if fsys == nil {
+		return errors.New(errFSIsNil)
+	}
+	file, err := fileFromIOFS(name, fsys)
+	if err != nil {
+		return err
+	}
+	m.attachments = m.appendFile(m.attachments, file, opts...)
+	return nil
+}
+
+// EmbedFromFS adds an embedded File from an fs.FS to the Msg.
+//
+// This method embeds a file from any filesystem that implements fs.FS into the email message,
+// like AttachFromFS does for attachments.
+//
+// Parameters:
+//   - fsys: The fs.FS from which the file will be retrieved.
+//   - name: The slash-separated path of the file in the filesystem.
+//   - opts: Optional parameters for customizing the embedded file.
+//
+// Returns:
+//   - An error if the fs.FS is nil or the file cannot be retrieved, otherwise nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2183
+func (m *Msg) EmbedFromFS(fsys fs.FS, name string, opts ...FileOption) error {
+	if fsys == nil {
+		return errors.New(errFSIsNil)
+	}
+	file, err := fileFromIOFS(name, fsys)
+	if err != nil {
+		return err
+	}
+	m.embeds = m.appendFile(m.embeds, file, opts...)
+	return nil
+}
+
+// fileFromIOFS returns a File pointer from a given file in the provided fs.FS.
+//
+// This method verifies that the file exists in the filesystem and returns a File structure that can
+// be used as an attachment or embed in the email message. The file's content is read when writing to
+// an io.Writer, and the file is identified by its base name.
+//
+// Parameters:
+//   - name: The slash-separated path of the file in the filesystem.
+//   - fsys: The fs.FS from which the file will be opened.
+//
+// Returns:
+//   - A pointer to the File structure representing the file.
+//   - An error if the file cannot be opened from the filesystem.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2183
+func fileFromIOFS(name string, fsys fs.FS) (*File, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file from fs.FS: %w", err)
+	}
+	_ = file.Close()
+	return &File{
+		Name:   path.Base(name),
+		Header: make(map[string][]string),
+		Writer: func(writer io.Writer) (int64, error) {
+			file, err := fsys.Open(name)
+			if err != nil {
+				return 0, err
+			}
+			numBytes, err := io.Copy(writer, file)
+			if err != nil {
+				_ = file.Close()
+				return numBytes, fmt.Errorf("failed to copy file to io.Writer: %w", err)
+			}
+			return numBytes, file.Close()
+		},
+	}, nil
+}
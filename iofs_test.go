@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testMapFS is an in-memory fs.FS used in the fs.FS tests.
+var testMapFS = fstest.MapFS{
+	"files/attachment.txt": {Data: []byte("This is a test attachment")},
+	"files/embed.png":      {Data: []byte("PNG")},
+}
+
+func TestMsg_AttachFromFS(t *testing.T) {
+	t.Run("AttachFromFS successful", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.AttachFromFS(testMapFS, "files/attachment.txt"); err != nil {
+			t.Fatalf("failed to attach from fs.FS: %s", err)
+		}
+		attachments := message.GetAttachments()
+		if len(attachments) != 1 || attachments[0].Name != "attachment.txt" {
+			t.Fatalf("expected attachment named attachment.txt, got: %v", attachments)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err := attachments[0].Writer(buffer); err != nil {
+			t.Fatalf("failed to write attachment: %s", err)
+		}
+		if buffer.String() != "This is a test attachment" {
+			t.Errorf("unexpected attachment content: %s", buffer.String())
+		}
+	})
+	t.Run("AttachFromFS with invalid path", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.AttachFromFS(testMapFS, "files/invalid.txt"); err == nil {
+			t.Error("expected invalid path to fail")
+		}
+		if len(message.GetAttachments()) != 0 {
+			t.Error("expected no attachment")
+		}
+	})
+	t.Run("AttachFromFS with nil fs.FS", func(t *testing.T) {
+		if err := testMessage(t).AttachFromFS(nil, "files/attachment.txt"); err == nil {
+			t.Error("expected nil fs.FS to fail")
+		}
+	})
+}
+
+func TestMsg_EmbedFromFS(t *testing.T) {
+	t.Run("EmbedFromFS successful", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.EmbedFromFS(testMapFS, "files/embed.png", WithFileContentID("logo")); err != nil {
+			t.Fatalf("failed to embed from fs.FS: %s", err)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if !strings.Contains(buffer.String(), `filename="embed.png"`) {
+			t.Errorf("expected embedded file in message, got: %s", buffer.String())
+		}
+	})
+	t.Run("EmbedFromFS with invalid path", func(t *testing.T) {
+		if err := testMessage(t).EmbedFromFS(testMapFS, "files/invalid.png"); err == nil {
+			t.Error("expected invalid path to fail")
+		}
+	})
+	t.Run("EmbedFromFS with nil fs.FS", func(t *testing.T) {
+		if err := testMessage(t).EmbedFromFS(nil, "files/embed.png"); err == nil {
+			t.Error("expected nil fs.FS to fail")
+		}
+	})
+}
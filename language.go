@@ -0,0 +1,285 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// MiddlewareContentLanguage is the MiddlewareType of the Middleware added by
+// WithContentLanguageDetection.
+const MiddlewareContentLanguage MiddlewareType = "content-language"
+
+// ngramMinTrigrams is the minimum number of trigrams a text must have for the NGramDetector to
+// detect its language.
+const ngramMinTrigrams = 10
+
+// ErrLanguageNotDetected is returned if the language of a Msg could not be detected.
+var ErrLanguageNotDetected = errors.New("language of message could not be detected")
+
+// builtinLanguageSamples holds the sample texts of the built-in n-gram model, keyed by the language tag.
+var builtinLanguageSamples = map[string]string{
+	"de": "Sehr geehrte Damen und Herren, vielen Dank für Ihre Nachricht. Wir haben Ihre Anfrage erhalten " +
+		"und werden uns so schnell wie möglich bei Ihnen melden. Bitte beachten Sie, dass die Bearbeitung " +
+		"einige Tage dauern kann. Die Rechnung finden Sie im Anhang dieser E-Mail. Wenn Sie Fragen haben, " +
+		"können Sie sich jederzeit an unseren Kundendienst wenden. Mit freundlichen Grüßen und einen schönen " +
+		"Tag noch. Ich habe das Paket gestern nicht bekommen, weil niemand zu Hause war. Das ist leider schon " +
+		"das zweite Mal in dieser Woche, aber wir finden sicher eine Lösung.",
+	"en": "Dear customer, thank you for your message. We have received your request and will get back to " +
+		"you as soon as possible. Please note that processing may take a few days. You will find the invoice " +
+		"attached to this email. If you have any questions, you can always contact our support team. Kind " +
+		"regards and have a nice day. I did not receive the package yesterday because nobody was at home. " +
+		"This is already the second time this week, but we will certainly find a solution that works for " +
+		"everyone who is involved with the order.",
+	"es": "Estimado cliente, gracias por su mensaje. Hemos recibido su solicitud y nos pondremos en contacto " +
+		"con usted lo antes posible. Tenga en cuenta que el proceso puede tardar unos días. Encontrará la " +
+		"factura adjunta en este correo. Si tiene alguna pregunta, puede ponerse en contacto con nuestro " +
+		"equipo de atención al cliente. Un cordial saludo y que tenga un buen día. Ayer no recibí el paquete " +
+		"porque no había nadie en casa. Es la segunda vez esta semana, pero seguro que encontraremos una " +
+		"solución para todos los que están relacionados con el pedido.",
+	"fr": "Cher client, merci pour votre message. Nous avons bien reçu votre demande et nous vous répondrons " +
+		"dans les plus brefs délais. Veuillez noter que le traitement peut prendre quelques jours. Vous " +
+		"trouverez la facture en pièce jointe de ce courriel. Si vous avez des questions, vous pouvez " +
+		"toujours contacter notre service client. Cordialement et bonne journée. Je n'ai pas reçu le colis " +
+		"hier parce qu'il n'y avait personne à la maison. C'est déjà la deuxième fois cette semaine, mais " +
+		"nous trouverons certainement une solution pour tous ceux qui sont concernés par la commande.",
+	"it": "Gentile cliente, grazie per il suo messaggio. Abbiamo ricevuto la sua richiesta e la contatteremo " +
+		"il prima possibile. La preghiamo di notare che l'elaborazione potrebbe richiedere alcuni giorni. " +
+		"Troverà la fattura allegata a questa email. Se ha delle domande, può sempre contattare il nostro " +
+		"servizio clienti. Cordiali saluti e buona giornata. Ieri non ho ricevuto il pacco perché non c'era " +
+		"nessuno a casa. È già la seconda volta questa settimana, ma troveremo sicuramente una soluzione per " +
+		"tutti quelli che sono coinvolti nell'ordine.",
+	"nl": "Geachte klant, bedankt voor uw bericht. Wij hebben uw aanvraag ontvangen en nemen zo snel mogelijk " +
+		"contact met u op. Houd er rekening mee dat de verwerking enkele dagen kan duren. U vindt de factuur " +
+		"als bijlage bij deze e-mail. Als u vragen heeft, kunt u altijd contact opnemen met onze " +
+		"klantenservice. Met vriendelijke groet en een fijne dag. Ik heb het pakket gisteren niet ontvangen " +
+		"omdat er niemand thuis was. Het is al de tweede keer deze week, maar we vinden zeker een oplossing " +
+		"voor iedereen die bij de bestelling betrokken is.",
+	"pt": "Prezado cliente, obrigado pela sua mensagem. Recebemos o seu pedido e entraremos em contato com " +
+		"você o mais rápido possível. Observe que o processamento pode levar alguns dias. Você encontrará a " +
+		"fatura em anexo neste email. Se tiver alguma dúvida, pode sempre entrar em contato com a nossa " +
+		"equipe de atendimento. Atenciosamente e tenha um bom dia. Ontem não recebi o pacote porque não " +
+		"havia ninguém em casa. Já é a segunda vez nesta semana, mas com certeza vamos encontrar uma solução " +
+		"para todos os que estão envolvidos com a encomenda.",
+}
+
+var (
+	// defaultLanguageDetector is the NGramDetector with the built-in model returned by
+	// DefaultLanguageDetector.
+	defaultLanguageDetector *NGramDetector
+
+	// defaultLanguageDetectorOnce makes sure that the built-in model is only built once.
+	defaultLanguageDetectorOnce sync.Once
+)
+
+type (
+	// LanguageDetector is an interface for detecting the natural language of a text.
+	//
+	// The LanguageDetector of a Msg is used by Msg.DetectLanguage and by the Middleware added with
+	// WithContentLanguageDetection. Custom implementations can be set with WithLanguageDetector, for
+	// example to wrap a more comprehensive language detection library.
+	LanguageDetector interface {
+		// DetectLanguage returns the language tag of the given text, i. e. "en" or "de", together
+		// with a confidence between 0 and 1. If the language cannot be detected, an empty language
+		// tag is returned.
+		DetectLanguage(text string) (string, float64)
+	}
+
+	// NGramDetector is a LanguageDetector based on a character trigram model of sample texts.
+	//
+	// The detector is deliberately small. It is meant to tell apart a handful of common languages in
+	// texts of at least a few words, i. e. for routing or analytics of received mail, and not to
+	// replace a full language identification library.
+	NGramDetector struct {
+		profiles map[string]ngramProfile
+	}
+
+	// ngramProfile holds the logarithmic trigram probabilities of a single language.
+	ngramProfile struct {
+		logProbs map[string]float64
+		unseen   float64
+	}
+
+	// contentLanguageMiddleware is a Middleware that sets the "Content-Language" header of a Msg to the
+	// detected language.
+	contentLanguageMiddleware struct{}
+)
+
+// NewNGramDetector returns a new NGramDetector with a trigram model built from the given sample texts.
+//
+// Parameters:
+//   - samples: A map of language tags to sample texts in the language. The longer and more typical
+//     the sample texts are, the better the detection works.
+//
+// Returns:
+//   - A pointer to the new NGramDetector.
+func NewNGramDetector(samples map[string]string) *NGramDetector {
+	detector := &NGramDetector{profiles: make(map[string]ngramProfile, len(samples))}
+	for language, sample := range samples {
+		counts := make(map[string]int)
+		total := 0
+		for _, trigram := range textTrigrams(sample) {
+			counts[trigram]++
+			total++
+		}
+		vocabulary := float64(len(counts) + 1)
+		profile := ngramProfile{
+			logProbs: make(map[string]float64, len(counts)),
+			unseen:   math.Log(1 / (float64(total) + vocabulary)),
+		}
+		for trigram, count := range counts {
+			profile.logProbs[trigram] = math.Log((float64(count) + 1) / (float64(total) + vocabulary))
+		}
+		detector.profiles[language] = profile
+	}
+	return detector
+}
+
+// DefaultLanguageDetector returns the LanguageDetector with the built-in n-gram model, which covers
+// Dutch, English, French, German, Italian, Portuguese and Spanish.
+//
+// Returns:
+//   - The built-in LanguageDetector.
+func DefaultLanguageDetector() LanguageDetector {
+	defaultLanguageDetectorOnce.Do(func() {
+		defaultLanguageDetector = NewNGramDetector(builtinLanguageSamples)
+	})
+	return defaultLanguageDetector
+}
+
+// DetectLanguage satisfies the LanguageDetector interface for the NGramDetector type.
+//
+// The language whose trigram model assigns the highest probability to the text is returned. The
+// confidence reflects the distance to the second most probable language.
+//
+// Parameters:
+//   - text: The text to detect the language of.
+//
+// Returns:
+//   - The language tag of the detected language, or an empty string if the text is too short.
+//   - The confidence of the detection between 0 and 1.
+func (d *NGramDetector) DetectLanguage(text string) (string, float64) {
+	trigrams := textTrigrams(text)
+	if len(trigrams) < ngramMinTrigrams || len(d.profiles) == 0 {
+		return "", 0
+	}
+	bestLanguage := ""
+	bestScore, secondScore := math.Inf(-1), math.Inf(-1)
+	for language, profile := range d.profiles {
+		score := 0.0
+		for _, trigram := range trigrams {
+			logProb, ok := profile.logProbs[trigram]
+			if !ok {
+				logProb = profile.unseen
+			}
+			score += logProb
+		}
+		switch {
+		case score > bestScore || (score == bestScore && language < bestLanguage):
+			bestLanguage, bestScore, secondScore = language, score, bestScore
+		case score > secondScore:
+			secondScore = score
+		}
+	}
+	if math.IsInf(secondScore, -1) {
+		return bestLanguage, 1
+	}
+	// The confidence is the probability of the best language compared to the second best one
+	return bestLanguage, 1 / (1 + math.Exp((secondScore-bestScore)/math.Sqrt(float64(len(trigrams)))))
+}
+
+// WithLanguageDetector sets the LanguageDetector that is used to detect the language of the Msg. If no
+// LanguageDetector is set, the DefaultLanguageDetector is used.
+//
+// Parameters:
+//   - detector: The LanguageDetector to use for the Msg.
+//
+// Returns:
+//   - A MsgOption function that sets the LanguageDetector of the Msg.
+func WithLanguageDetector(detector LanguageDetector) MsgOption {
+	return func(m *Msg) {
+		m.languageDetector = detector
+	}
+}
+
+// WithContentLanguageDetection adds a Middleware that sets the "Content-Language" header of the Msg to
+// the language detected by Msg.DetectLanguage when the Msg is written. An existing "Content-Language"
+// header is not changed and if the language cannot be detected, no header is set.
+//
+// Returns:
+//   - A MsgOption function that adds the Content-Language Middleware to the Msg.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc3282
+func WithContentLanguageDetection() MsgOption {
+	return WithMiddleware(contentLanguageMiddleware{})
+}
+
+// DetectLanguage detects the natural language of the body of the Msg.
+//
+// The text of the Msg is determined with Msg.PlainText, so that HTML-only messages are supported as
+// well, and passed to the LanguageDetector of the Msg. This is useful to route or analyze received
+// mail by language.
+//
+// Returns:
+//   - The language tag of the detected language, i. e. "en" or "de".
+//   - An error if the Msg has no text part or if the language could not be detected; otherwise,
+//     returns nil.
+func (m *Msg) DetectLanguage() (string, error) {
+	text, err := m.PlainText()
+	if err != nil {
+		return "", fmt.Errorf("failed to get text of message: %w", err)
+	}
+	detector := m.languageDetector
+	if detector == nil {
+		detector = DefaultLanguageDetector()
+	}
+	language, _ := detector.DetectLanguage(text)
+	if language == "" {
+		return "", ErrLanguageNotDetected
+	}
+	return language, nil
+}
+
+// Handle sets the "Content-Language" header of the given Msg to the detected language if the Msg
+// does not have one yet.
+func (contentLanguageMiddleware) Handle(msg *Msg) *Msg {
+	if len(msg.GetGenHeader(HeaderContentLang)) > 0 {
+		return msg
+	}
+	if language, err := msg.DetectLanguage(); err == nil {
+		msg.SetGenHeader(HeaderContentLang, language)
+	}
+	return msg
+}
+
+// Type returns the MiddlewareType of the Content-Language Middleware.
+//
+// Returns:
+//   - The MiddlewareContentLanguage type.
+func (contentLanguageMiddleware) Type() MiddlewareType {
+	return MiddlewareContentLanguage
+}
+
+// textTrigrams returns the character trigrams of the words of the given text. Words are lower-cased
+// and padded with spaces, so that the beginnings and ends of words become part of the model.
+func textTrigrams(text string) []string {
+	var trigrams []string
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			trigrams = append(trigrams, string(runes[i:i+3]))
+		}
+	}
+	return trigrams
+}
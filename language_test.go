@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testLanguageDetector is a LanguageDetector that always returns the same language.
+type testLanguageDetector struct {
+	language string
+}
+
+func (d testLanguageDetector) DetectLanguage(string) (string, float64) {
+	return d.language, 1
+}
+
+func TestNGramDetector_DetectLanguage(t *testing.T) {
+	tests := []struct {
+		want string
+		text string
+	}{
+		{"de", "Hallo Peter, kannst du mir bitte die Unterlagen für das Treffen morgen schicken? Danke!"},
+		{"en", "Hi Peter, could you please send me the documents for the meeting tomorrow? Thanks!"},
+		{"es", "Hola Pedro, ¿podrías enviarme los documentos para la reunión de mañana? ¡Gracias!"},
+		{"fr", "Salut Pierre, pourrais-tu m'envoyer les documents pour la réunion de demain ? Merci !"},
+		{"it", "Ciao Pietro, potresti mandarmi i documenti per la riunione di domani? Grazie!"},
+		{"nl", "Hoi Peter, kun je mij alsjeblieft de documenten voor de vergadering van morgen sturen? Bedankt!"},
+		{"pt", "Olá Pedro, você poderia me enviar os documentos para a reunião de amanhã? Obrigado!"},
+	}
+	detector := DefaultLanguageDetector()
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			language, confidence := detector.DetectLanguage(tt.text)
+			if language != tt.want {
+				t.Errorf("unexpected language, want: %s, got: %s", tt.want, language)
+			}
+			if confidence <= 0.5 || confidence > 1 {
+				t.Errorf("expected confidence between 0.5 and 1, got: %f", confidence)
+			}
+		})
+	}
+	t.Run("short text is not detected", func(t *testing.T) {
+		if language, _ := detector.DetectLanguage("Hi!"); language != "" {
+			t.Errorf("expected no language for short text, got: %s", language)
+		}
+	})
+	t.Run("custom samples", func(t *testing.T) {
+		custom := NewNGramDetector(map[string]string{"x-digits": "zero one two three four five six seven"})
+		if language, confidence := custom.DetectLanguage("three four five six seven"); language != "x-digits" ||
+			confidence != 1 {
+			t.Errorf("expected x-digits with confidence 1, got: %s, %f", language, confidence)
+		}
+	})
+}
+
+func TestMsg_DetectLanguage(t *testing.T) {
+	t.Run("language of HTML body is detected", func(t *testing.T) {
+		message := NewMsg()
+		message.SetBodyString(TypeTextHTML, "<p>Vielen Dank für Ihre Bestellung. Wir melden uns bald bei Ihnen.</p>")
+		language, err := message.DetectLanguage()
+		if err != nil {
+			t.Fatalf("failed to detect language: %s", err)
+		}
+		if language != "de" {
+			t.Errorf("unexpected language, want: de, got: %s", language)
+		}
+	})
+	t.Run("custom detector is used", func(t *testing.T) {
+		message := NewMsg(WithLanguageDetector(testLanguageDetector{language: "sv"}))
+		message.SetBodyString(TypeTextPlain, "Hej")
+		if language, err := message.DetectLanguage(); err != nil || language != "sv" {
+			t.Errorf("expected language sv, got: %s, %v", language, err)
+		}
+	})
+	t.Run("undetectable language fails", func(t *testing.T) {
+		message := NewMsg()
+		message.SetBodyString(TypeTextPlain, "Hi!")
+		if _, err := message.DetectLanguage(); !errors.Is(err, ErrLanguageNotDetected) {
+			t.Errorf("expected error: %s, got: %s", ErrLanguageNotDetected, err)
+		}
+	})
+	t.Run("message without text part fails", func(t *testing.T) {
+		if _, err := NewMsg().DetectLanguage(); !errors.Is(err, ErrNoTextPart) {
+			t.Errorf("expected error: %s, got: %s", ErrNoTextPart, err)
+		}
+	})
+}
+
+func TestWithContentLanguageDetection(t *testing.T) {
+	t.Run("Content-Language is set", func(t *testing.T) {
+		message := testMessage(t, WithContentLanguageDetection())
+		message.SetBodyString(TypeTextPlain, "Merci beaucoup pour votre commande, nous vous répondrons bientôt.")
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if !strings.Contains(buffer.String(), "Content-Language: fr\r\n") {
+			t.Errorf("expected Content-Language header to be set, got: %s", buffer.String())
+		}
+	})
+	t.Run("existing Content-Language is kept", func(t *testing.T) {
+		message := testMessage(t, WithContentLanguageDetection())
+		message.SetGenHeader(HeaderContentLang, "en-GB")
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if !strings.Contains(buffer.String(), "Content-Language: en-GB\r\n") {
+			t.Errorf("expected Content-Language header to be kept, got: %s", buffer.String())
+		}
+	})
+}
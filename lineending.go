@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"io"
+)
+
+// LineEnding is a type that determines the line endings of a Msg written with
+// Msg.WriteToWithLineEnding.
+type LineEnding int
+
+const (
+	// LineEndingCRLF writes the lines with CRLF line endings, as required by SMTP and RFC 5322. This
+	// is the line ending of Msg.WriteTo.
+	LineEndingCRLF LineEnding = iota
+
+	// LineEndingLF writes the lines with bare LF line endings, as used by Unix tools, i. e. for mail
+	// fixtures stored in a git repository.
+	LineEndingLF
+)
+
+// lfWriter is an io.Writer that converts the CRLF line endings of the written data to LF before
+// passing it to the underlying io.Writer.
+type lfWriter struct {
+	bytesWritten int64
+	pendingCR    bool
+	writer       io.Writer
+}
+
+// WriteToWithLineEnding writes the formatted Msg into the given io.Writer with the given LineEnding.
+//
+// The Msg is written like with WriteTo, which always uses CRLF line endings. With LineEndingLF,
+// every CRLF of the output is converted to LF, which includes the content of parts and files that
+// are not encoded, i. e. with NoEncoding. Base64 and quoted-printable encoded content is not
+// affected. Messages with LF line endings must not be sent via SMTP.
+//
+// Parameters:
+//   - writer: The io.Writer to which the formatted message will be written.
+//   - ending: The LineEnding of the written lines.
+//
+// Returns:
+//   - The total number of bytes written to the io.Writer.
+//   - An error if any occurred during the writing process, otherwise nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5322#section-2.1
+func (m *Msg) WriteToWithLineEnding(writer io.Writer, ending LineEnding) (int64, error) {
+	if ending != LineEndingLF {
+		return m.WriteTo(writer)
+	}
+	converter := &lfWriter{writer: writer}
+	if _, err := m.WriteTo(converter); err != nil {
+		return converter.bytesWritten, err
+	}
+	return converter.bytesWritten, converter.flush()
+}
+
+// String satisfies the fmt.Stringer interface for the LineEnding type.
+func (l LineEnding) String() string {
+	switch l {
+	case LineEndingCRLF:
+		return "CRLF"
+	case LineEndingLF:
+		return "LF"
+	default:
+		return "unknown"
+	}
+}
+
+// Write satisfies the io.Writer interface for the lfWriter type.
+//
+// A CR at the end of the payload is held back until the next call, since it might be followed by an
+// LF.
+//
+// Parameters:
+//   - payload: The data to be written.
+//
+// Returns:
+//   - The number of bytes of the payload that have been processed.
+//   - An error if writing to the underlying io.Writer failed, otherwise nil.
+func (w *lfWriter) Write(payload []byte) (int, error) {
+	converted := make([]byte, 0, len(payload)+1)
+	for _, char := range payload {
+		if w.pendingCR {
+			w.pendingCR = false
+			if char != '\n' {
+				converted = append(converted, '\r')
+			}
+		}
+		if char == '\r' {
+			w.pendingCR = true
+			continue
+		}
+		converted = append(converted, char)
+	}
+	n, err := w.writer.Write(converted)
+	w.bytesWritten += int64(n)
+	if err != nil {
+		return 0, err
+	}
+	return len(payload), nil
+}
+
+// flush writes a CR that has been held back at the end of the written data.
+//
+// Returns:
+//   - An error if writing to the underlying io.Writer failed, otherwise nil.
+func (w *lfWriter) flush() error {
+	if !w.pendingCR {
+		return nil
+	}
+	w.pendingCR = false
+	n, err := w.writer.Write([]byte{'\r'})
+	w.bytesWritten += int64(n)
+	return err
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMsg_WriteToWithLineEnding(t *testing.T) {
+	t.Run("CRLF line endings", func(t *testing.T) {
+		message := testMessage(t)
+		buffer := bytes.NewBuffer(nil)
+		n, err := message.WriteToWithLineEnding(buffer, LineEndingCRLF)
+		if err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if n != int64(buffer.Len()) {
+			t.Errorf("expected %d bytes written, got: %d", buffer.Len(), n)
+		}
+		if !strings.Contains(buffer.String(), "Subject: Testmail\r\n") {
+			t.Errorf("expected CRLF line endings, got: %q", buffer.String())
+		}
+	})
+	t.Run("LF line endings", func(t *testing.T) {
+		message := testMessage(t)
+		message.AttachFile("testdata/attachment.txt")
+		buffer := bytes.NewBuffer(nil)
+		n, err := message.WriteToWithLineEnding(buffer, LineEndingLF)
+		if err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if n != int64(buffer.Len()) {
+			t.Errorf("expected %d bytes written, got: %d", buffer.Len(), n)
+		}
+		if strings.Contains(buffer.String(), "\r") {
+			t.Errorf("expected no CR in message, got: %q", buffer.String())
+		}
+		if !strings.Contains(buffer.String(), "Subject: Testmail\n") {
+			t.Errorf("expected LF line endings, got: %q", buffer.String())
+		}
+		parsed, err := EMLToMsgFromString(buffer.String(), WithLenientParsing())
+		if err != nil {
+			t.Fatalf("failed to parse message with LF line endings: %s", err)
+		}
+		if len(parsed.GetAttachments()) != 1 {
+			t.Errorf("expected 1 attachment, got: %d", len(parsed.GetAttachments()))
+		}
+	})
+	t.Run("LF line endings fail on broken writer", func(t *testing.T) {
+		message := testMessage(t)
+		if _, err := message.WriteToWithLineEnding(failReadWriteSeekCloser{}, LineEndingLF); err == nil {
+			t.Error("expected writing to broken writer to fail")
+		}
+	})
+}
+
+func TestLineEnding_String(t *testing.T) {
+	tests := []struct {
+		ending LineEnding
+		want   string
+	}{
+		{LineEndingCRLF, "CRLF"},
+		{LineEndingLF, "LF"},
+		{LineEnding(-1), "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.ending.String(); got != tt.want {
+				t.Errorf("expected %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLfWriter_Write(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   string
+	}{
+		{"CRLF is converted", []string{"a\r\nb\r\n"}, "a\nb\n"},
+		{"CRLF split across writes", []string{"a\r", "\nb"}, "a\nb"},
+		{"bare CR is kept", []string{"a\rb"}, "a\rb"},
+		{"trailing CR is flushed", []string{"a\r"}, "a\r"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := bytes.NewBuffer(nil)
+			writer := &lfWriter{writer: buffer}
+			for _, chunk := range tt.chunks {
+				if n, err := writer.Write([]byte(chunk)); err != nil || n != len(chunk) {
+					t.Fatalf("failed to write chunk: %d, %v", n, err)
+				}
+			}
+			if err := writer.flush(); err != nil {
+				t.Fatalf("failed to flush: %s", err)
+			}
+			if buffer.String() != tt.want {
+				t.Errorf("expected %q, got: %q", tt.want, buffer.String())
+			}
+			if writer.bytesWritten != int64(len(tt.want)) {
+				t.Errorf("expected %d bytes written, got: %d", len(tt.want), writer.bytesWritten)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultLinkCheckConcurrency is the default number of links that are checked concurrently.
+	defaultLinkCheckConcurrency = 4
+
+	// defaultLinkCheckTimeout is the default timeout for the check of a single link.
+	defaultLinkCheckTimeout = time.Second * 10
+)
+
+var (
+	// ErrBrokenLink indicates that an HTTP link of a Msg could not be resolved or that the server
+	// responded with a client or server error status.
+	ErrBrokenLink = errors.New("broken link")
+
+	// ErrMissingEmbed indicates that a "cid:" reference of a Msg does not match any embedded File.
+	ErrMissingEmbed = errors.New("cid reference does not match any embedded file")
+)
+
+// plainTextLinkRegexp matches the HTTP links in a "text/plain" part.
+var plainTextLinkRegexp = regexp.MustCompile(`(?i)https?://[^\s<>"']+`)
+
+type (
+	// LinkCheckOption is a function type that configures the checks of Msg.CheckLinks.
+	LinkCheckOption func(*linkCheckOptions)
+
+	// LinkProblem describes a link of a Msg that failed the check of Msg.CheckLinks.
+	LinkProblem struct {
+		// Err is the reason of the problem. It wraps either ErrBrokenLink or ErrMissingEmbed.
+		Err error
+
+		// StatusCode is the HTTP status code the server responded with, or zero if no response was
+		// received.
+		StatusCode int
+
+		// URL is the link as it appears in the Msg.
+		URL string
+	}
+
+	// linkCheckOptions holds the configuration of the link checks.
+	linkCheckOptions struct {
+		// client is the http.Client that performs the requests.
+		client *http.Client
+
+		// concurrency is the number of links that are checked concurrently.
+		concurrency int
+
+		// timeout is the timeout for the check of a single link.
+		timeout time.Duration
+	}
+)
+
+// WithLinkCheckConcurrency sets the number of HTTP links that are checked concurrently. Values
+// lower than one are ignored. The default is 4.
+//
+// Parameters:
+//   - concurrency: The number of concurrent link checks.
+//
+// Returns:
+//   - A LinkCheckOption that sets the concurrency of the link checks.
+func WithLinkCheckConcurrency(concurrency int) LinkCheckOption {
+	return func(o *linkCheckOptions) {
+		if concurrency > 0 {
+			o.concurrency = concurrency
+		}
+	}
+}
+
+// WithLinkCheckTimeout sets the timeout for the check of a single HTTP link. Values lower than or
+// equal to zero are ignored. The default is 10 seconds.
+//
+// Parameters:
+//   - timeout: The timeout for a single link check.
+//
+// Returns:
+//   - A LinkCheckOption that sets the timeout of the link checks.
+func WithLinkCheckTimeout(timeout time.Duration) LinkCheckOption {
+	return func(o *linkCheckOptions) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+// WithLinkCheckHTTPClient sets the http.Client that performs the requests of the link checks, i. e.
+// to use a proxy. A nil client is ignored. By default, http.DefaultClient is used.
+//
+// Parameters:
+//   - client: The http.Client to use.
+//
+// Returns:
+//   - A LinkCheckOption that sets the http.Client of the link checks.
+func WithLinkCheckHTTPClient(client *http.Client) LinkCheckOption {
+	return func(o *linkCheckOptions) {
+		if client != nil {
+			o.client = client
+		}
+	}
+}
+
+// CheckLinks verifies the links in the body parts of the Msg before it is sent.
+//
+// All "http" and "https" links in the "href" and "src" attributes of the "text/html" parts and in
+// the text of the "text/plain" parts are requested with a HEAD request. If the server does not
+// allow the HEAD method, a GET request is made instead. A link fails the check if the request
+// fails or the server responds with a status code of 400 or above. Each "cid:" reference in the
+// "text/html" parts must match the Content-ID of an embedded File, as defined by RFC 2392. Each
+// link is only checked once.
+//
+// Parameters:
+//   - ctx: The context.Context that controls the cancellation of the link checks.
+//   - opts: Optional LinkCheckOption functions to configure the link checks.
+//
+// Returns:
+//   - A LinkProblem for each link that failed the check, in the order of their first appearance.
+//   - An error if the content of a part cannot be read or the context is cancelled; otherwise, nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2392
+func (m *Msg) CheckLinks(ctx context.Context, opts ...LinkCheckOption) ([]LinkProblem, error) {
+	options := &linkCheckOptions{
+		client:      http.DefaultClient,
+		concurrency: defaultLinkCheckConcurrency,
+		timeout:     defaultLinkCheckTimeout,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(options)
+		}
+	}
+	links, err := m.bodyLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	contentIDs := make(map[string]bool)
+	for _, file := range m.embeds {
+		contentID, ok := file.getHeader(HeaderContentID)
+		if !ok {
+			contentID = file.Name
+		}
+		contentIDs[strings.Trim(contentID, "<>")] = true
+	}
+	results := make([]*LinkProblem, len(links))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < options.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = options.checkHTTPLink(ctx, links[index])
+			}
+		}()
+	}
+	for i, link := range links {
+		if hasURLScheme(link, "cid") {
+			contentID, err := url.PathUnescape(link[len("cid:"):])
+			if err != nil || !contentIDs[contentID] {
+				results[i] = &LinkProblem{Err: ErrMissingEmbed, URL: link}
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
+	var problems []LinkProblem
+	for _, result := range results {
+		if result != nil {
+			problems = append(problems, *result)
+		}
+	}
+	return problems, nil
+}
+
+// HasBrokenLinks returns a MsgPredicate that matches a Msg, if any of its links fails the check of
+// Msg.CheckLinks or the links cannot be checked. It is meant to be used as Deny predicate of a
+// PolicyRule, so that broken links are caught before a Msg is sent. Since the links are checked
+// while the Client is sending, a short timeout should be configured.
+//
+// Parameters:
+//   - opts: Optional LinkCheckOption functions to configure the link checks.
+//
+// Returns:
+//   - A MsgPredicate that matches messages with broken links.
+func HasBrokenLinks(opts ...LinkCheckOption) MsgPredicate {
+	return func(msg *Msg) bool {
+		problems, err := msg.CheckLinks(context.Background(), opts...)
+		return err != nil || len(problems) > 0
+	}
+}
+
+// Error implements the error interface for the LinkProblem type.
+//
+// Returns:
+//   - A string with the link and the reason of the problem.
+func (p LinkProblem) Error() string {
+	if p.StatusCode != 0 {
+		return fmt.Sprintf("%s: %s (status %d)", p.URL, p.Err, p.StatusCode)
+	}
+	return fmt.Sprintf("%s: %s", p.URL, p.Err)
+}
+
+// Unwrap returns the reason of the LinkProblem.
+//
+// Returns:
+//   - The underlying error of the LinkProblem.
+func (p LinkProblem) Unwrap() error {
+	return p.Err
+}
+
+// bodyLinks returns the unique "http", "https" and "cid" links of the body parts of the Msg in the
+// order of their first appearance.
+func (m *Msg) bodyLinks() ([]string, error) {
+	var links []string
+	seen := make(map[string]bool)
+	addLink := func(link string) {
+		if seen[link] || (!hasURLScheme(link, "http") && !hasURLScheme(link, "https") &&
+			!hasURLScheme(link, "cid")) {
+			return
+		}
+		seen[link] = true
+		links = append(links, link)
+	}
+	for _, part := range m.parts {
+		if part.isDeleted {
+			continue
+		}
+		contentType := part.GetContentType()
+		if contentType != TypeTextHTML && contentType != TypeTextPlain {
+			continue
+		}
+		content, err := part.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s part: %w", contentType, err)
+		}
+		if contentType == TypeTextPlain {
+			for _, link := range plainTextLinkRegexp.FindAllString(string(content), -1) {
+				addLink(strings.TrimRight(link, ".,;:!?)]}"))
+			}
+			continue
+		}
+		for _, token := range tokenizeHTML(string(content)) {
+			if token.kind != htmlTokenStartTag {
+				continue
+			}
+			for _, name := range []string{"href", "src"} {
+				if value, ok := token.attribute(name); ok {
+					addLink(strings.TrimSpace(value))
+				}
+			}
+		}
+	}
+	return links, nil
+}
+
+// checkHTTPLink requests the given HTTP link and returns a LinkProblem if the link is broken, or
+// nil otherwise.
+func (o *linkCheckOptions) checkHTTPLink(ctx context.Context, link string) *LinkProblem {
+	statusCode, err := o.requestLink(ctx, http.MethodHead, link)
+	if err == nil && (statusCode == http.StatusMethodNotAllowed || statusCode == http.StatusNotImplemented) {
+		statusCode, err = o.requestLink(ctx, http.MethodGet, link)
+	}
+	if err != nil {
+		return &LinkProblem{Err: fmt.Errorf("%w: %s", ErrBrokenLink, err), URL: link}
+	}
+	if statusCode >= http.StatusBadRequest {
+		return &LinkProblem{Err: ErrBrokenLink, StatusCode: statusCode, URL: link}
+	}
+	return nil
+}
+
+// requestLink requests the given link with the given method and returns the status code of the
+// response. The response body is not read.
+func (o *linkCheckOptions) requestLink(ctx context.Context, method, link string) (int, error) {
+	ctxTimeout, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctxTimeout, method, link, nil)
+	if err != nil {
+		return 0, err
+	}
+	response, err := o.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	_ = response.Body.Close()
+	return response.StatusCode, nil
+}
+
+// hasURLScheme reports whether the given link starts with the given URL scheme followed by a colon.
+// The comparison is case-insensitive.
+func hasURLScheme(link, scheme string) bool {
+	return len(link) > len(scheme) && link[len(scheme)] == ':' && strings.EqualFold(link[:len(scheme)], scheme)
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testLinkServer returns an httptest.Server that responds with 404 for "/missing", rejects HEAD
+// requests for "/nohead" and responds with 200 otherwise. The number of requests is counted.
+func testLinkServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	requests := new(int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		switch {
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/nohead" && r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.URL.Path == "/slow":
+			time.Sleep(time.Millisecond * 200)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestMsg_CheckLinks(t *testing.T) {
+	server, requests := testLinkServer(t)
+	t.Run("links are checked", func(t *testing.T) {
+		message := testMessage(t)
+		message.SetBodyString(TypeTextPlain, fmt.Sprintf("See %s/ok, %s/missing.", server.URL, server.URL))
+		message.AddAlternativeString(TypeTextHTML, fmt.Sprintf(`<a href="%s/ok">ok</a><a href="%s/nohead">x</a>`+
+			`<img src="cid:logo.png"><img src="cid:missing.png"><a href="mailto:a@example.com">mail</a>`,
+			server.URL, server.URL))
+		if err := message.EmbedReader("logo.png", strings.NewReader("logo")); err != nil {
+			t.Fatalf("failed to embed file: %s", err)
+		}
+		atomic.StoreInt32(requests, 0)
+		problems, err := message.CheckLinks(context.Background(), WithLinkCheckHTTPClient(server.Client()))
+		if err != nil {
+			t.Fatalf("failed to check links: %s", err)
+		}
+		if len(problems) != 2 {
+			t.Fatalf("expected 2 problems, got: %v", problems)
+		}
+		if problems[0].URL != server.URL+"/missing" || problems[0].StatusCode != http.StatusNotFound ||
+			!errors.Is(problems[0], ErrBrokenLink) {
+			t.Errorf("unexpected problem: %s", problems[0])
+		}
+		if problems[1].URL != "cid:missing.png" || !errors.Is(problems[1], ErrMissingEmbed) {
+			t.Errorf("unexpected problem: %s", problems[1])
+		}
+		if got := atomic.LoadInt32(requests); got != 4 {
+			t.Errorf("expected 4 requests for 3 unique links, got: %d", got)
+		}
+	})
+	t.Run("timeout", func(t *testing.T) {
+		message := testMessage(t)
+		message.SetBodyString(TypeTextPlain, server.URL+"/slow")
+		problems, err := message.CheckLinks(context.Background(), WithLinkCheckTimeout(time.Millisecond*10),
+			WithLinkCheckConcurrency(1))
+		if err != nil {
+			t.Fatalf("failed to check links: %s", err)
+		}
+		if len(problems) != 1 || problems[0].StatusCode != 0 || !errors.Is(problems[0], ErrBrokenLink) {
+			t.Errorf("expected slow link to fail, got: %v", problems)
+		}
+	})
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		message := testMessage(t)
+		message.SetBodyString(TypeTextPlain, server.URL+"/ok")
+		if _, err := message.CheckLinks(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected error: %s, got: %s", context.Canceled, err)
+		}
+	})
+}
+
+func TestHasBrokenLinks(t *testing.T) {
+	server, _ := testLinkServer(t)
+	predicate := HasBrokenLinks(WithLinkCheckHTTPClient(server.Client()))
+	message := testMessage(t)
+	message.SetBodyString(TypeTextHTML, fmt.Sprintf(`<a href="%s/ok">ok</a>`, server.URL))
+	if predicate(message) {
+		t.Error("expected message without broken links not to match")
+	}
+	message.SetBodyString(TypeTextHTML, fmt.Sprintf(`<a href="%s/missing">missing</a>`, server.URL))
+	if !predicate(message) {
+		t.Error("expected message with broken link to match")
+	}
+}
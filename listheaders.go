@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// listUnsubscribeOneClick is the value of the "List-Unsubscribe-Post" header for one-click
+// unsubscription.
+const listUnsubscribeOneClick = "List-Unsubscribe=One-Click"
+
+var (
+	// ErrInvalidListURL is returned if a URL of a List-* header is not a valid absolute "mailto",
+	// "http" or "https" URL.
+	ErrInvalidListURL = errors.New("invalid list header URL")
+
+	// ErrInvalidListID is returned if the name or the domain of the "List-Id" header is invalid.
+	ErrInvalidListID = errors.New("invalid list ID")
+)
+
+// SetListUnsubscribe sets the "List-Unsubscribe" header of the Msg to the given URLs, through which
+// the recipient can unsubscribe from the mailing list.
+//
+// Each URL is enclosed in angle brackets and the URLs are separated by commas, in the order of
+// preference. Only "mailto", "http" and "https" URLs are accepted. For one-click unsubscription
+// as described in RFC 8058, at least one "https" URL must be given and SetListUnsubscribePost must
+// be called as well.
+//
+// Parameters:
+//   - urls: The URLs through which the recipient can unsubscribe, i. e.
+//     "mailto:unsubscribe@example.com" or "https://example.com/unsubscribe?id=123".
+//
+// Returns:
+//   - An error if no URL is given or any of the URLs is invalid; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2369#section-3.2
+//   - https://datatracker.ietf.org/doc/html/rfc8058
+func (m *Msg) SetListUnsubscribe(urls ...string) error {
+	return m.setListURLs(HeaderListUnsubscribe, urls)
+}
+
+// SetListUnsubscribePost sets the "List-Unsubscribe-Post" header of the Msg to
+// "List-Unsubscribe=One-Click", which signals that the "https" URL of the "List-Unsubscribe" header
+// supports one-click unsubscription via a POST request.
+//
+// RFC 8058 additionally requires the Msg to carry a valid DKIM signature that covers both headers.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc8058#section-3.1
+func (m *Msg) SetListUnsubscribePost() {
+	m.SetGenHeader(HeaderListUnsubscribePost, listUnsubscribeOneClick)
+}
+
+// SetListID sets the "List-Id" header of the Msg, which identifies the mailing list the Msg was sent
+// through, to "<name.domain>".
+//
+// Parameters:
+//   - name: The name of the mailing list, which is unique within the domain, i. e. "newsletter".
+//   - domain: The domain the list identifier belongs to, i. e. "example.com".
+//
+// Returns:
+//   - An error if the name or the domain is empty or contains characters that are not allowed in
+//     a list identifier; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2919#section-2
+func (m *Msg) SetListID(name, domain string) error {
+	listID := name + "." + domain
+	if name == "" || domain == "" || !isDotAtom(listID) {
+		return fmt.Errorf("%w: %q", ErrInvalidListID, listID)
+	}
+	m.SetGenHeader(HeaderListID, "<"+listID+">")
+	return nil
+}
+
+// SetListHelp sets the "List-Help" header of the Msg to the given URLs, through which the recipient
+// can get help about the mailing list.
+//
+// Parameters:
+//   - urls: The URLs with help about the mailing list.
+//
+// Returns:
+//   - An error if no URL is given or any of the URLs is invalid; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2369#section-3.1
+func (m *Msg) SetListHelp(urls ...string) error {
+	return m.setListURLs(HeaderListHelp, urls)
+}
+
+// SetListSubscribe sets the "List-Subscribe" header of the Msg to the given URLs, through which the
+// recipient can subscribe to the mailing list.
+//
+// Parameters:
+//   - urls: The URLs through which the recipient can subscribe.
+//
+// Returns:
+//   - An error if no URL is given or any of the URLs is invalid; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2369#section-3.3
+func (m *Msg) SetListSubscribe(urls ...string) error {
+	return m.setListURLs(HeaderListSubscribe, urls)
+}
+
+// SetListPost sets the "List-Post" header of the Msg to the given URLs, through which the recipient
+// can post to the mailing list. If no URL is given, the header is set to "NO", indicating that
+// posting to the list is not allowed.
+//
+// Parameters:
+//   - urls: The URLs through which the recipient can post to the mailing list.
+//
+// Returns:
+//   - An error if any of the URLs is invalid; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2369#section-3.4
+func (m *Msg) SetListPost(urls ...string) error {
+	if len(urls) == 0 {
+		m.SetGenHeader(HeaderListPost, "NO")
+		return nil
+	}
+	return m.setListURLs(HeaderListPost, urls)
+}
+
+// SetListOwner sets the "List-Owner" header of the Msg to the given URLs, through which the recipient
+// can contact the owner of the mailing list.
+//
+// Parameters:
+//   - urls: The URLs through which the owner of the mailing list can be contacted.
+//
+// Returns:
+//   - An error if no URL is given or any of the URLs is invalid; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2369#section-3.5
+func (m *Msg) SetListOwner(urls ...string) error {
+	return m.setListURLs(HeaderListOwner, urls)
+}
+
+// SetListArchive sets the "List-Archive" header of the Msg to the given URLs, through which the
+// archive of the mailing list can be accessed.
+//
+// Parameters:
+//   - urls: The URLs to the archive of the mailing list.
+//
+// Returns:
+//   - An error if no URL is given or any of the URLs is invalid; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2369#section-3.6
+func (m *Msg) SetListArchive(urls ...string) error {
+	return m.setListURLs(HeaderListArchive, urls)
+}
+
+// setListURLs validates the given URLs and sets them, enclosed in angle brackets, as values of the
+// given List-* header.
+func (m *Msg) setListURLs(header Header, urls []string) error {
+	if len(urls) == 0 {
+		return fmt.Errorf("%w: no URL given for %s", ErrInvalidListURL, header)
+	}
+	values := make([]string, 0, len(urls))
+	for _, rawURL := range urls {
+		rawURL = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(rawURL), "<"), ">")
+		if strings.ContainsAny(rawURL, " \t\r\n<>,") {
+			return fmt.Errorf("%w: %q", ErrInvalidListURL, rawURL)
+		}
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("%w: %q: %s", ErrInvalidListURL, rawURL, err)
+		}
+		switch strings.ToLower(parsed.Scheme) {
+		case "mailto":
+			if parsed.Opaque == "" {
+				return fmt.Errorf("%w: %q", ErrInvalidListURL, rawURL)
+			}
+		case "http", "https":
+			if parsed.Host == "" {
+				return fmt.Errorf("%w: %q", ErrInvalidListURL, rawURL)
+			}
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidListURL, rawURL)
+		}
+		values = append(values, "<"+rawURL+">")
+	}
+	m.SetGenHeader(header, values...)
+	return nil
+}
+
+// isDotAtom returns true if the given value is a dot-atom as defined in RFC 5322, i. e. one or more
+// atoms of printable US-ASCII characters other than specials, separated by single dots.
+func isDotAtom(value string) bool {
+	for _, atom := range strings.Split(value, ".") {
+		if atom == "" {
+			return false
+		}
+		for i := 0; i < len(atom); i++ {
+			char := atom[i]
+			if char <= ' ' || char > '~' || strings.IndexByte(`()<>[]:;@\,."`, char) >= 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMsg_SetListUnsubscribe(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    []string
+		want    string
+		wantErr bool
+	}{
+		{
+			"mailto and https URL",
+			[]string{"mailto:unsubscribe@example.com?subject=unsubscribe", "https://example.com/unsubscribe?id=123"},
+			"List-Unsubscribe: <mailto:unsubscribe@example.com?subject=unsubscribe>,\r\n" +
+				" <https://example.com/unsubscribe?id=123>",
+			false,
+		},
+		{"URL with angle brackets", []string{"<https://example.com/u>"}, "List-Unsubscribe: <https://example.com/u>", false},
+		{"no URL fails", nil, "", true},
+		{"relative URL fails", []string{"/unsubscribe"}, "", true},
+		{"unsupported scheme fails", []string{"ftp://example.com/unsubscribe"}, "", true},
+		{"mailto without address fails", []string{"mailto:"}, "", true},
+		{"URL with comma fails", []string{"https://example.com/a,b"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := testMessage(t)
+			err := message.SetListUnsubscribe(tt.urls...)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidListURL) {
+					t.Errorf("expected error: %s, got: %s", ErrInvalidListURL, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to set List-Unsubscribe header: %s", err)
+			}
+			buffer := bytes.NewBuffer(nil)
+			if _, err = message.WriteTo(buffer); err != nil {
+				t.Fatalf("failed to write message: %s", err)
+			}
+			if !strings.Contains(buffer.String(), tt.want+"\r\n") {
+				t.Errorf("expected message to contain %q, got: %s", tt.want, buffer.String())
+			}
+		})
+	}
+}
+
+func TestMsg_SetListUnsubscribePost(t *testing.T) {
+	message := NewMsg()
+	message.SetListUnsubscribePost()
+	if values := message.GetGenHeader(HeaderListUnsubscribePost); len(values) != 1 ||
+		values[0] != "List-Unsubscribe=One-Click" {
+		t.Errorf("unexpected List-Unsubscribe-Post header: %v", values)
+	}
+}
+
+func TestMsg_SetListID(t *testing.T) {
+	message := NewMsg()
+	if err := message.SetListID("newsletter", "example.com"); err != nil {
+		t.Fatalf("failed to set List-Id header: %s", err)
+	}
+	if values := message.GetGenHeader(HeaderListID); len(values) != 1 || values[0] != "<newsletter.example.com>" {
+		t.Errorf("unexpected List-Id header: %v", values)
+	}
+	for _, invalid := range [][2]string{{"", "example.com"}, {"newsletter", ""}, {"news letter", "example.com"}} {
+		if err := message.SetListID(invalid[0], invalid[1]); !errors.Is(err, ErrInvalidListID) {
+			t.Errorf("expected error for %v: %s, got: %s", invalid, ErrInvalidListID, err)
+		}
+	}
+}
+
+func TestMsg_SetListHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		header Header
+		set    func(*Msg, ...string) error
+	}{
+		{"List-Help", HeaderListHelp, (*Msg).SetListHelp},
+		{"List-Subscribe", HeaderListSubscribe, (*Msg).SetListSubscribe},
+		{"List-Post", HeaderListPost, (*Msg).SetListPost},
+		{"List-Owner", HeaderListOwner, (*Msg).SetListOwner},
+		{"List-Archive", HeaderListArchive, (*Msg).SetListArchive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := NewMsg()
+			if err := tt.set(message, "mailto:list@example.com"); err != nil {
+				t.Fatalf("failed to set %s header: %s", tt.header, err)
+			}
+			if values := message.GetGenHeader(tt.header); len(values) != 1 || values[0] != "<mailto:list@example.com>" {
+				t.Errorf("unexpected %s header: %v", tt.header, values)
+			}
+			if err := tt.set(message, "invalid"); !errors.Is(err, ErrInvalidListURL) {
+				t.Errorf("expected error: %s, got: %s", ErrInvalidListURL, err)
+			}
+		})
+	}
+	t.Run("List-Post without URL", func(t *testing.T) {
+		message := NewMsg()
+		if err := message.SetListPost(); err != nil {
+			t.Fatalf("failed to set List-Post header: %s", err)
+		}
+		if values := message.GetGenHeader(HeaderListPost); len(values) != 1 || values[0] != "NO" {
+			t.Errorf("unexpected List-Post header: %v", values)
+		}
+	})
+}
@@ -43,10 +43,16 @@ func NewJSON(output io.Writer, level Level) *JSONlog {
 
 // logMessage is a helper function to handle different log levels and formats.
 func logMessage(level Level, log *slog.Logger, logData Log, formatFunc func(string, ...interface{}) string) {
-	lGroup := log.WithGroup(DirString).With(
-		slog.String(DirFromString, logData.directionFrom()),
-		slog.String(DirToString, logData.directionTo()),
-	)
+	for _, field := range logData.Fields {
+		log = log.With(slog.Any(field.Key, field.Value))
+	}
+	lGroup := log
+	if logData.Direction != DirNone {
+		lGroup = log.WithGroup(DirString).With(
+			slog.String(DirFromString, logData.directionFrom()),
+			slog.String(DirToString, logData.directionTo()),
+		)
+	}
 	switch level {
 	case LevelDebug:
 		lGroup.Debug(formatFunc(logData.Format, logData.Messages...))
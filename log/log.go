@@ -5,11 +5,20 @@
 // Package log implements a logger interface that can be used within the go-mail package
 package log
 
+import (
+	"fmt"
+	"strings"
+)
+
 const (
 	DirServerToClient Direction = iota // Server to Client communication
 	DirClientToServer                  // Client to Server communication
 )
 
+// DirNone is the Direction of a log message that is not part of the SMTP communication, like
+// a client activity event
+const DirNone Direction = -1
+
 const (
 	// LevelError is the Level for only ERROR log messages
 	LevelError Level = iota
@@ -30,18 +39,36 @@ const (
 	DirToString = "to"
 )
 
+const (
+	// FieldDuration is the Field key for the duration of an operation
+	FieldDuration = "duration"
+	// FieldError is the Field key for an error message
+	FieldError = "error"
+	// FieldHost is the Field key for the address of the SMTP server
+	FieldHost = "host"
+	// FieldMessageID is the Field key for the Message-ID of a message
+	FieldMessageID = "message-id"
+)
+
 // Direction is a type wrapper for the direction a debug log message goes
 type Direction int
 
 // Level is a type wrapper for an int
 type Level int
 
-// Log represents a log message type that holds a log Direction, a Format string
-// and a slice of Messages
+// Field represents a key/value pair that is attached to a Log for structured logging
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Log represents a log message type that holds a log Direction, a Format string,
+// a slice of Messages and optional Fields
 type Log struct {
 	Direction Direction
 	Format    string
 	Messages  []interface{}
+	Fields    []Field
 }
 
 // Logger is the log interface for go-mail
@@ -54,6 +81,9 @@ type Logger interface {
 
 // directionPrefix will return a prefix string depending on the Direction.
 func (l Log) directionPrefix() string {
+	if l.Direction == DirNone {
+		return ""
+	}
 	p := "C <-- S:"
 	if l.Direction == DirClientToServer {
 		p = "C --> S:"
@@ -78,3 +108,12 @@ func (l Log) directionTo() string {
 	}
 	return p
 }
+
+// fieldSuffix will return the Fields of the Log as a string of key=value pairs.
+func (l Log) fieldSuffix() string {
+	var suffix strings.Builder
+	for _, field := range l.Fields {
+		suffix.WriteString(fmt.Sprintf(" %s=%v", field.Key, field.Value))
+	}
+	return suffix.String()
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Slog is an adapter that satisfies the Logger interface and routes all log messages to an
+// existing *slog.Logger. Filtering of the log levels is left to the slog.Handler of the logger.
+type Slog struct {
+	log *slog.Logger
+}
+
+// NewSlog returns a new Slog type that satisfies the Logger interface and logs to the given
+// *slog.Logger. If logger is nil, slog.Default is used.
+func NewSlog(logger *slog.Logger) *Slog {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Slog{log: logger}
+}
+
+// Debugf logs a debug message via the slog.Logger
+func (l *Slog) Debugf(log Log) {
+	logMessage(LevelDebug, l.log, log, fmt.Sprintf)
+}
+
+// Infof logs a info message via the slog.Logger
+func (l *Slog) Infof(log Log) {
+	logMessage(LevelInfo, l.log, log, fmt.Sprintf)
+}
+
+// Warnf logs a warn message via the slog.Logger
+func (l *Slog) Warnf(log Log) {
+	logMessage(LevelWarn, l.log, log, fmt.Sprintf)
+}
+
+// Errorf logs an error message via the slog.Logger
+func (l *Slog) Errorf(log Log) {
+	logMessage(LevelError, l.log, log, fmt.Sprintf)
+}
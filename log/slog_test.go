@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestNewSlog(t *testing.T) {
+	l := NewSlog(nil)
+	if l.log != slog.Default() {
+		t.Error("Expected default slog logger for nil logger")
+	}
+}
+
+func TestSlog(t *testing.T) {
+	var b bytes.Buffer
+	l := NewSlog(slog.New(slog.NewJSONHandler(&b, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	l.Debugf(Log{Direction: DirServerToClient, Format: "test %s", Messages: []interface{}{"foo"}})
+	if b.String() != "" {
+		t.Error("Debug message was not expected to be logged")
+	}
+
+	tests := []struct {
+		level string
+		fn    func(Log)
+	}{
+		{"INFO", l.Infof},
+		{"WARN", l.Warnf},
+		{"ERROR", l.Errorf},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			b.Reset()
+			tt.fn(Log{Direction: DirClientToServer, Format: "test %s", Messages: []interface{}{"foo"}})
+			jl, err := unmarshalLog(b.Bytes())
+			if err != nil {
+				t.Fatalf("unmarshal json log message failed: %s", err)
+			}
+			if jl.Level != tt.level {
+				t.Errorf("Expected level %s, got %s", tt.level, jl.Level)
+			}
+			if jl.Message != "test foo" || jl.Direction.From != "client" || jl.Direction.To != "server" {
+				t.Errorf("Unexpected log message: %+v", jl)
+			}
+		})
+	}
+}
+
+func TestSlog_Fields(t *testing.T) {
+	var b bytes.Buffer
+	l := NewSlog(slog.New(slog.NewJSONHandler(&b, nil)))
+
+	l.Infof(Log{
+		Direction: DirNone, Format: "message sent",
+		Fields: []Field{{Key: FieldHost, Value: "localhost:25"}, {Key: FieldMessageID, Value: "<id@domain.tld>"}},
+	})
+	var entry map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unmarshal json log message failed: %s", err)
+	}
+	if entry[FieldHost] != "localhost:25" || entry[FieldMessageID] != "<id@domain.tld>" {
+		t.Errorf("Expected fields in log message, got: %s", b.String())
+	}
+	if _, ok := entry[DirString]; ok {
+		t.Errorf("Expected no direction for DirNone, got: %s", b.String())
+	}
+}
@@ -37,8 +37,11 @@ func New(output io.Writer, level Level) *Stdlog {
 
 // logStdMessage is a helper function to handle different log levels and formats for Stdlog.
 func logStdMessage(logger *log.Logger, logData Log, callDepth int) {
-	format := fmt.Sprintf("%s %s", logData.directionPrefix(), logData.Format)
-	_ = logger.Output(callDepth, fmt.Sprintf(format, logData.Messages...))
+	format := logData.Format
+	if prefix := logData.directionPrefix(); prefix != "" {
+		format = fmt.Sprintf("%s %s", prefix, logData.Format)
+	}
+	_ = logger.Output(callDepth, fmt.Sprintf(format, logData.Messages...)+logData.fieldSuffix())
 }
 
 // Debugf performs a Printf() on the debug logger
@@ -112,3 +112,17 @@ func TestErrorf(t *testing.T) {
 		t.Error("Error message was not expected to be logged")
 	}
 }
+
+func TestStdlog_Fields(t *testing.T) {
+	var b bytes.Buffer
+	l := New(&b, LevelInfo)
+
+	l.Infof(Log{
+		Direction: DirNone, Format: "message sent",
+		Fields: []Field{{Key: FieldHost, Value: "localhost:25"}, {Key: FieldMessageID, Value: "<id@domain.tld>"}},
+	})
+	expected := " INFO: message sent host=localhost:25 message-id=<id@domain.tld>\n"
+	if !strings.HasSuffix(b.String(), expected) {
+		t.Errorf("Expected %q, got %q", expected, b.String())
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// maildirCounter is used to guarantee unique Maildir filenames for deliveries within the same
+// process and microsecond.
+var maildirCounter uint64
+
+// WriteToMaildir delivers the Msg into the Maildir at the given directory.
+//
+// The Msg is first written into a uniquely named file in the "tmp" subdirectory of the Maildir,
+// synced to disk and then moved into the "new" subdirectory, so that readers of the Maildir never
+// see partially written messages. The unique filename follows the recommended
+// "time.MusecPpidQcounter.hostname" scheme. Missing "tmp", "new" and "cur" subdirectories are
+// created. As usual for Maildir, the Msg is stored with LF line endings.
+//
+// Parameters:
+//   - dir: The path of the Maildir.
+//
+// Returns:
+//   - The path of the delivered message file in the "new" subdirectory.
+//   - An error if the Maildir could not be created or the Msg could not be written, otherwise nil.
+//
+// References:
+//   - https://cr.yp.to/proto/maildir.html
+func (m *Msg) WriteToMaildir(dir string) (string, error) {
+	for _, subdir := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, subdir), 0o700); err != nil {
+			return "", fmt.Errorf("failed to create maildir: %w", err)
+		}
+	}
+	buffer := bytes.Buffer{}
+	if _, err := m.WriteTo(&buffer); err != nil {
+		return "", fmt.Errorf("failed to write message to buffer: %w", err)
+	}
+
+	name := maildirUniqueName()
+	tmpPath := filepath.Join(dir, "tmp", name)
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create maildir file: %w", err)
+	}
+	_, err = file.Write(bytes.ReplaceAll(buffer.Bytes(), []byte(SingleNewLine), []byte("\n")))
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write maildir file: %w", err)
+	}
+
+	newPath := filepath.Join(dir, "new", name)
+	if err = os.Rename(tmpPath, newPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to move maildir file to new: %w", err)
+	}
+	return newPath, nil
+}
+
+// maildirUniqueName returns a unique filename for a Maildir delivery.
+//
+// Returns:
+//   - The unique filename in the form "time.MusecPpidQcounter.hostname".
+func maildirUniqueName() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost.localdomain"
+	}
+	// Slashes and colons are not allowed in Maildir hostnames and need to be replaced by their
+	// octal escape sequences
+	hostname = strings.NewReplacer("/", `\057`, ":", `\072`).Replace(hostname)
+	now := time.Now()
+	return fmt.Sprintf("%d.M%dP%dQ%d.%s", now.Unix(), now.Nanosecond()/1000, os.Getpid(),
+		atomic.AddUint64(&maildirCounter, 1), hostname)
+}
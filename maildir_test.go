@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMsg_WriteToMaildir(t *testing.T) {
+	t.Run("write messages to new maildir", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "Maildir")
+		first, err := testMessage(t).WriteToMaildir(dir)
+		if err != nil {
+			t.Fatalf("failed to write message to maildir: %s", err)
+		}
+		second, err := testMessage(t).WriteToMaildir(dir)
+		if err != nil {
+			t.Fatalf("failed to write message to maildir: %s", err)
+		}
+		if first == second {
+			t.Errorf("expected unique maildir filenames, got: %s twice", first)
+		}
+		for _, subdir := range []string{"tmp", "new", "cur"} {
+			if info, err := os.Stat(filepath.Join(dir, subdir)); err != nil || !info.IsDir() {
+				t.Errorf("expected maildir subdirectory %s to exist", subdir)
+			}
+		}
+		if filepath.Dir(first) != filepath.Join(dir, "new") {
+			t.Errorf("expected message to be delivered to new, got: %s", first)
+		}
+		entries, err := os.ReadDir(filepath.Join(dir, "tmp"))
+		if err != nil {
+			t.Fatalf("failed to read tmp directory: %s", err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("expected tmp directory to be empty, got %d entries", len(entries))
+		}
+		content, err := os.ReadFile(first)
+		if err != nil {
+			t.Fatalf("failed to read delivered message: %s", err)
+		}
+		if bytes.Contains(content, []byte("\r\n")) {
+			t.Error("expected message to be stored with LF line endings")
+		}
+		if !bytes.Contains(content, []byte("Subject: Testmail\n")) {
+			t.Errorf("expected message to contain subject, got:\n%s", content)
+		}
+	})
+	t.Run("write message fails on invalid maildir", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		if err := os.WriteFile(file, []byte("test"), 0o600); err != nil {
+			t.Fatalf("failed to create test file: %s", err)
+		}
+		if _, err := testMessage(t).WriteToMaildir(file); err == nil {
+			t.Error("expected error when maildir is a file")
+		}
+	})
+}
+
+func TestMaildirUniqueName(t *testing.T) {
+	name := maildirUniqueName()
+	if strings.ContainsAny(name, "/:") {
+		t.Errorf("expected maildir name without slashes and colons, got: %s", name)
+	}
+	if name == maildirUniqueName() {
+		t.Error("expected maildir names to be unique")
+	}
+}
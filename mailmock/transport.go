@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+// Package mailmock implements an in-memory mail.Transport that records the sent messages, so that
+// applications can assert on their sends in unit tests without any network connection or SMTP server
+package mailmock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/wneessen/go-mail"
+)
+
+// Transport is an in-memory mail.Transport that records all sent messages instead of delivering
+// them.
+//
+// Like the mail.Client, the Transport requires each message to have a sender and at least one
+// recipient. The zero value is ready to use and a Transport is safe for concurrent use by multiple
+// goroutines.
+type Transport struct {
+	// err is the error that is returned for each send, if set.
+	err error
+
+	// messages holds the recorded messages in the order they were sent.
+	messages []*mail.Msg
+
+	// mutex is used to synchronize access to the state of the Transport.
+	mutex sync.RWMutex
+}
+
+// Ensure that the Transport satisfies the mail.Transport interface.
+var _ mail.Transport = (*Transport)(nil)
+
+// NewTransport returns a new, empty Transport.
+func NewTransport() *Transport {
+	return &Transport{}
+}
+
+// Send records the given messages, like SendWithContext with a background context.
+//
+// Parameters:
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error if a failure was set with SetError or any of the messages has no sender or no
+//     recipients; otherwise, returns nil.
+func (t *Transport) Send(messages ...*mail.Msg) error {
+	return t.SendWithContext(context.Background(), messages...)
+}
+
+// SendWithContext records the given messages. If any of the messages has no sender or no
+// recipients, or if a failure was set with SetError, none of the messages is recorded.
+//
+// Parameters:
+//   - ctx: The context.Context; if it is already done, no message is recorded.
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error if the context is done, a failure was set with SetError or any of the messages has
+//     no sender or no recipients; otherwise, returns nil.
+func (t *Transport) SendWithContext(ctx context.Context, messages ...*mail.Msg) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.err != nil {
+		return t.err
+	}
+	for i, message := range messages {
+		if message == nil {
+			return fmt.Errorf("message %d is nil", i)
+		}
+		if _, err := message.GetSender(false); err != nil {
+			return fmt.Errorf("failed to get sender of message %d: %w", i, err)
+		}
+		if _, err := message.GetRecipients(); err != nil {
+			return fmt.Errorf("failed to get recipients of message %d: %w", i, err)
+		}
+	}
+	t.messages = append(t.messages, messages...)
+	return nil
+}
+
+// SetError sets the error that is returned for each subsequent send, i. e. to test the error
+// handling of an application. A nil error restores the default behavior.
+//
+// Parameters:
+//   - err: The error to return for each send, or nil.
+func (t *Transport) SetError(err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.err = err
+}
+
+// Messages returns all recorded messages in the order they were sent.
+//
+// Returns:
+//   - A slice of pointers to the recorded Msg.
+func (t *Transport) Messages() []*mail.Msg {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	messages := make([]*mail.Msg, len(t.messages))
+	copy(messages, t.messages)
+	return messages
+}
+
+// LastMessage returns the most recently recorded message.
+//
+// Returns:
+//   - A pointer to the last recorded Msg, or nil if no message has been recorded.
+func (t *Transport) LastMessage() *mail.Msg {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	if len(t.messages) == 0 {
+		return nil
+	}
+	return t.messages[len(t.messages)-1]
+}
+
+// SentTo returns the recorded messages that have the given address as "To", "Cc" or "Bcc"
+// recipient. The address is matched case-insensitively.
+//
+// Parameters:
+//   - addr: The recipient address to look for, i. e. "toni.tester@example.com".
+//
+// Returns:
+//   - A slice of pointers to the recorded Msg for the recipient, in the order they were sent.
+func (t *Transport) SentTo(addr string) []*mail.Msg {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	var messages []*mail.Msg
+	for _, message := range t.messages {
+		recipients, _ := message.GetRecipients()
+		for _, recipient := range recipients {
+			if strings.EqualFold(recipient, addr) {
+				messages = append(messages, message)
+				break
+			}
+		}
+	}
+	return messages
+}
+
+// Reset removes all recorded messages and the error set with SetError.
+func (t *Transport) Reset() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.err = nil
+	t.messages = nil
+}
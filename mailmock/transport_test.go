@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mailmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wneessen/go-mail"
+)
+
+// testMessage returns a Msg from a test sender to the given recipient.
+func testMessage(t *testing.T, rcpt string) *mail.Msg {
+	t.Helper()
+	message := mail.NewMsg()
+	if err := message.From("sender@example.com"); err != nil {
+		t.Fatalf("failed to set sender address: %s", err)
+	}
+	if err := message.To(rcpt); err != nil {
+		t.Fatalf("failed to set recipient address: %s", err)
+	}
+	message.Subject("Test")
+	message.SetBodyString(mail.TypeTextPlain, "Test")
+	return message
+}
+
+func TestTransport_Send(t *testing.T) {
+	t.Run("messages are recorded", func(t *testing.T) {
+		var transport mail.Transport = NewTransport()
+		first := testMessage(t, "alice@example.com")
+		second := testMessage(t, "bob@example.com")
+		if err := transport.SendWithContext(context.Background(), first, second); err != nil {
+			t.Fatalf("failed to send messages: %s", err)
+		}
+		mock := transport.(*Transport)
+		if messages := mock.Messages(); len(messages) != 2 || messages[0] != first || messages[1] != second {
+			t.Errorf("unexpected recorded messages: %v", messages)
+		}
+		if mock.LastMessage() != second {
+			t.Error("expected last message to be the second message")
+		}
+		if messages := mock.SentTo("Alice@Example.com"); len(messages) != 1 || messages[0] != first {
+			t.Errorf("unexpected messages sent to alice: %v", messages)
+		}
+		if messages := mock.SentTo("carol@example.com"); len(messages) != 0 {
+			t.Errorf("expected no messages sent to carol, got: %v", messages)
+		}
+	})
+	t.Run("invalid message is not recorded", func(t *testing.T) {
+		transport := NewTransport()
+		if err := transport.Send(testMessage(t, "alice@example.com"), mail.NewMsg()); err == nil {
+			t.Error("expected message without sender to fail")
+		}
+		if err := transport.Send(nil); err == nil {
+			t.Error("expected nil message to fail")
+		}
+		if transport.LastMessage() != nil {
+			t.Error("expected no message to be recorded")
+		}
+	})
+	t.Run("set error fails the send", func(t *testing.T) {
+		transport := NewTransport()
+		errSend := errors.New("send failed")
+		transport.SetError(errSend)
+		if err := transport.Send(testMessage(t, "alice@example.com")); !errors.Is(err, errSend) {
+			t.Errorf("expected error: %s, got: %s", errSend, err)
+		}
+		transport.Reset()
+		if err := transport.Send(testMessage(t, "alice@example.com")); err != nil {
+			t.Errorf("failed to send message after reset: %s", err)
+		}
+		if len(transport.Messages()) != 1 {
+			t.Errorf("expected 1 recorded message, got: %d", len(transport.Messages()))
+		}
+	})
+	t.Run("done context fails the send", func(t *testing.T) {
+		transport := NewTransport()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := transport.SendWithContext(ctx, testMessage(t, "alice@example.com")); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected error: %s, got: %s", context.Canceled, err)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+// Package mbox implements a writer for the mboxrd mailbox format, that can be used to store
+// messages created with the go-mail package
+package mbox
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSender is the envelope sender used in the From_ line if no sender is given
+const DefaultSender = "MAILER-DAEMON"
+
+// fromLineFormat is the date format of the From_ line, as produced by asctime(3)
+const fromLineFormat = "Mon Jan _2 15:04:05 2006"
+
+var (
+	// ErrLocked is returned by Append if the lock of the mbox file could not be acquired in time
+	ErrLocked = errors.New("mbox file is locked")
+
+	// LockTimeout is the maximum time Append waits for the lock of the mbox file
+	LockTimeout = time.Second * 10
+
+	// StaleLockAge is the age after which an existing lock file is considered stale and removed
+	StaleLockAge = time.Minute * 5
+)
+
+// Writer appends messages in the mboxrd format to an io.Writer
+//
+// A Writer is safe for concurrent use. Each message is written in a single Write call to the
+// underlying io.Writer, prefixed by a From_ line and terminated by an empty line.
+type Writer struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// NewWriter returns a new Writer that appends messages to the given io.Writer
+func NewWriter(writer io.Writer) *Writer {
+	return &Writer{writer: writer}
+}
+
+// WriteMessage appends the message to the mbox. The from parameter is the envelope sender
+// address used in the From_ line and date is the delivery time. A zero date is replaced by the
+// current time. The message is typically a *mail.Msg.
+//
+// The message is converted to LF line endings and all lines in the message that start with
+// any number of ">" followed by "From " are quoted with an additional ">", as required by the
+// mboxrd format, so that the message can be restored unaltered.
+func (w *Writer) WriteMessage(from string, date time.Time, message io.WriterTo) error {
+	buffer := bytes.Buffer{}
+	if _, err := message.WriteTo(&buffer); err != nil {
+		return fmt.Errorf("failed to write message to buffer: %w", err)
+	}
+	data := encode(from, date, buffer.Bytes())
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if _, err := w.writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write message to mbox: %w", err)
+	}
+	return nil
+}
+
+// Append appends the message to the mbox file at the given path, creating the file if it does
+// not exist. See Writer.WriteMessage for the meaning of the parameters.
+//
+// To prevent concurrent writers from corrupting the mbox, the file is locked with a
+// "<path>.lock" dot-lock file while the message is written. If the lock cannot be acquired
+// within LockTimeout, ErrLocked is returned. Lock files older than StaleLockAge are considered
+// stale and removed. The mbox file is synced to disk before the lock is released.
+func Append(path, from string, date time.Time, message io.WriterTo) error {
+	buffer := bytes.Buffer{}
+	if _, err := message.WriteTo(&buffer); err != nil {
+		return fmt.Errorf("failed to write message to buffer: %w", err)
+	}
+	data := encode(from, date, buffer.Bytes())
+
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open mbox file: %w", err)
+	}
+	if _, err = file.Write(data); err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write message to mbox file: %w", err)
+	}
+	return nil
+}
+
+// encode returns the message in the mboxrd format, including the From_ line and the
+// terminating empty line
+func encode(from string, date time.Time, message []byte) []byte {
+	from = strings.Trim(strings.TrimSpace(from), "<>")
+	if from == "" || strings.ContainsAny(from, " \t\r\n") {
+		from = DefaultSender
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+	message = bytes.ReplaceAll(message, []byte("\r\n"), []byte("\n"))
+
+	buffer := bytes.Buffer{}
+	buffer.Grow(len(message) + len(from) + 64)
+	buffer.WriteString(fmt.Sprintf("From %s %s\n", from, date.UTC().Format(fromLineFormat)))
+	for len(message) > 0 {
+		line := message
+		if i := bytes.IndexByte(message, '\n'); i >= 0 {
+			line = message[:i+1]
+		}
+		message = message[len(line):]
+		if isFromLine(line) {
+			buffer.WriteByte('>')
+		}
+		buffer.Write(line)
+	}
+	if !bytes.HasSuffix(buffer.Bytes(), []byte("\n")) {
+		buffer.WriteByte('\n')
+	}
+	buffer.WriteByte('\n')
+	return buffer.Bytes()
+}
+
+// isFromLine reports whether the line starts with any number of ">" followed by "From "
+func isFromLine(line []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(line, ">"), []byte("From "))
+}
+
+// lock acquires the dot-lock for the mbox file at the given path and returns a function that
+// releases the lock
+func lock(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		file, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err == nil {
+			_, _ = fmt.Fprintf(file, "%d\n", os.Getpid())
+			_ = file.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create mbox lock file: %w", err)
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > StaleLockAge {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrLocked
+		}
+		time.Sleep(time.Millisecond * 50)
+	}
+}
@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mbox
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wneessen/go-mail"
+)
+
+// rawMessage is a io.WriterTo that writes a raw message
+type rawMessage string
+
+func (r rawMessage) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, string(r))
+	return int64(n), err
+}
+
+// failMessage is a io.WriterTo that always fails
+type failMessage struct{}
+
+func (failMessage) WriteTo(io.Writer) (int64, error) {
+	return 0, errors.New("intentional failure")
+}
+
+func TestWriter_WriteMessage(t *testing.T) {
+	date := time.Date(2024, 10, 1, 12, 0, 5, 0, time.UTC)
+	t.Run("write message with From_ quoting", func(t *testing.T) {
+		buffer := bytes.NewBuffer(nil)
+		writer := NewWriter(buffer)
+		message := rawMessage("Subject: Test\r\n\r\nFrom here\r\n>From there\r\nNot From here\r\n")
+		if err := writer.WriteMessage("<toni.tester@example.com>", date, message); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		want := "From toni.tester@example.com Tue Oct  1 12:00:05 2024\n" +
+			"Subject: Test\n\n>From here\n>>From there\nNot From here\n\n"
+		if buffer.String() != want {
+			t.Errorf("unexpected mbox output:\nwant: %q\ngot:  %q", want, buffer.String())
+		}
+	})
+	t.Run("write message without trailing newline and sender", func(t *testing.T) {
+		buffer := bytes.NewBuffer(nil)
+		writer := NewWriter(buffer)
+		if err := writer.WriteMessage("", date, rawMessage("Subject: Test\r\n\r\nBody")); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if !strings.HasPrefix(buffer.String(), "From "+DefaultSender+" ") {
+			t.Errorf("expected default sender in From_ line, got: %q", buffer.String())
+		}
+		if !strings.HasSuffix(buffer.String(), "Body\n\n") {
+			t.Errorf("expected message to be terminated by an empty line, got: %q", buffer.String())
+		}
+	})
+	t.Run("write go-mail message", func(t *testing.T) {
+		message := mail.NewMsg()
+		if err := message.From("toni.tester@example.com"); err != nil {
+			t.Fatalf("failed to set sender: %s", err)
+		}
+		message.Subject("Test")
+		message.SetBodyString(mail.TypeTextPlain, "From the go-mail team")
+		buffer := bytes.NewBuffer(nil)
+		if err := NewWriter(buffer).WriteMessage("toni.tester@example.com", time.Time{}, message); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if !strings.Contains(buffer.String(), "\n>From the go-mail team\n") {
+			t.Errorf("expected body From line to be quoted, got: %q", buffer.String())
+		}
+	})
+	t.Run("write message fails on message error", func(t *testing.T) {
+		if err := NewWriter(io.Discard).WriteMessage("", date, failMessage{}); err == nil {
+			t.Error("expected error on failing message")
+		}
+	})
+}
+
+func TestAppend(t *testing.T) {
+	date := time.Date(2024, 10, 1, 12, 0, 5, 0, time.UTC)
+	t.Run("append concurrently", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "mbox")
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := Append(path, "toni.tester@example.com", date, rawMessage("Subject: Test\r\n\r\nBody\r\n")); err != nil {
+					t.Errorf("failed to append message: %s", err)
+				}
+			}()
+		}
+		wg.Wait()
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read mbox file: %s", err)
+		}
+		if count := strings.Count(string(content), "\nFrom toni.tester@example.com ") + 1; count != 10 {
+			t.Errorf("expected 10 messages in mbox, got: %d", count)
+		}
+		if _, err = os.Stat(path + ".lock"); !errors.Is(err, os.ErrNotExist) {
+			t.Error("expected lock file to be removed")
+		}
+	})
+	t.Run("append fails on held lock", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "mbox")
+		if err := os.WriteFile(path+".lock", nil, 0o600); err != nil {
+			t.Fatalf("failed to create lock file: %s", err)
+		}
+		defaultTimeout := LockTimeout
+		LockTimeout = time.Millisecond * 100
+		t.Cleanup(func() { LockTimeout = defaultTimeout })
+		if err := Append(path, "", date, rawMessage("Body")); !errors.Is(err, ErrLocked) {
+			t.Errorf("expected error: %s, got: %s", ErrLocked, err)
+		}
+	})
+	t.Run("append removes stale lock", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "mbox")
+		if err := os.WriteFile(path+".lock", nil, 0o600); err != nil {
+			t.Fatalf("failed to create lock file: %s", err)
+		}
+		staleTime := time.Now().Add(-StaleLockAge * 2)
+		if err := os.Chtimes(path+".lock", staleTime, staleTime); err != nil {
+			t.Fatalf("failed to change lock file time: %s", err)
+		}
+		if err := Append(path, "", date, rawMessage("Body")); err != nil {
+			t.Errorf("failed to append message with stale lock: %s", err)
+		}
+	})
+	t.Run("append fails on message error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "mbox")
+		if err := Append(path, "", date, failMessage{}); err == nil {
+			t.Error("expected error on failing message")
+		}
+	})
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"time"
+)
+
+// MetricsCollector is an interface for the instrumentation of a Client.
+//
+// The Client calls the methods of the MetricsCollector synchronously with the counters and timings
+// of its operations, so that they can be exported to a metrics system like Prometheus. An
+// implementation must therefore return quickly and, if the Client is used concurrently, be safe for
+// concurrent use. The host passed to each method is the address of the SMTP server. An error passed
+// to ObserveMessage is a *SendError, whose Reason can be used to count errors by category.
+type MetricsCollector interface {
+	// ObserveDial is called after each attempt to connect to the SMTP server, including the attempt on
+	// the fallback port. For implicit TLS connections, the TLS handshake is part of the dial.
+	ObserveDial(host string, duration time.Duration, err error)
+
+	// ObserveTLSHandshake is called after each STARTTLS handshake.
+	ObserveTLSHandshake(host string, duration time.Duration, err error)
+
+	// ObserveAuth is called after each SMTP authentication attempt. A non-nil err indicates an
+	// authentication failure.
+	ObserveAuth(host string, authType SMTPAuthType, err error)
+
+	// ObserveMessage is called after each Msg that was processed by the Client, with the number of
+	// bytes written to the server and the time it took to deliver the Msg.
+	ObserveMessage(host string, bytesWritten int64, duration time.Duration, err error)
+}
+
+// WithMetricsCollector sets a MetricsCollector that is called by the Client with the counters and
+// timings of dial attempts, TLS handshakes, authentication attempts and processed messages.
+//
+// Parameters:
+//   - collector: The MetricsCollector that receives the metrics of the Client.
+//
+// Returns:
+//   - An Option function that sets the MetricsCollector for the Client.
+func WithMetricsCollector(collector MetricsCollector) Option {
+	return func(c *Client) error {
+		if collector == nil {
+			return ErrMetricsCollectorIsNil
+		}
+		c.metricsCollector = collector
+		return nil
+	}
+}
+
+// observeDial passes the outcome of a dial attempt to the MetricsCollector of the Client, if set.
+//
+// Parameters:
+//   - host: The address that was dialed.
+//   - startTime: The point in time at which the dial attempt started.
+//   - err: The error of the dial attempt, or nil.
+func (c *Client) observeDial(host string, startTime time.Time, err error) {
+	if c.metricsCollector == nil {
+		return
+	}
+	c.metricsCollector.ObserveDial(host, time.Since(startTime), err)
+}
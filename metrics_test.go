@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testMetricsCollector is a MetricsCollector that records all observations for the tests.
+type testMetricsCollector struct {
+	mutex     sync.Mutex
+	auths     []error
+	authTypes []SMTPAuthType
+	bytes     int64
+	dials     []error
+	handshake []error
+	messages  []error
+}
+
+func (m *testMetricsCollector) ObserveDial(_ string, _ time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.dials = append(m.dials, err)
+}
+
+func (m *testMetricsCollector) ObserveTLSHandshake(_ string, _ time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.handshake = append(m.handshake, err)
+}
+
+func (m *testMetricsCollector) ObserveAuth(_ string, authType SMTPAuthType, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.auths = append(m.auths, err)
+	m.authTypes = append(m.authTypes, authType)
+}
+
+func (m *testMetricsCollector) ObserveMessage(_ string, bytesWritten int64, _ time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.bytes += bytesWritten
+	m.messages = append(m.messages, err)
+}
+
+func TestWithMetricsCollector(t *testing.T) {
+	t.Run("nil collector fails", func(t *testing.T) {
+		_, err := NewClient(DefaultHost, WithMetricsCollector(nil))
+		if !errors.Is(err, ErrMetricsCollectorIsNil) {
+			t.Errorf("expected error: %s, got: %s", ErrMetricsCollectorIsNil, err)
+		}
+	})
+	t.Run("metrics of dial, STARTTLS, auth and send are collected", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250-STARTTLS\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+		ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+		t.Cleanup(cancelDial)
+
+		collector := &testMetricsCollector{}
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(TLSMandatory),
+			WithTLSConfig(&tls.Config{InsecureSkipVerify: true}), WithSMTPAuth(SMTPAuthPlain),
+			WithUsername("test"), WithPassword("password"), WithMetricsCollector(collector))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(ctxDial); err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				t.Skip("failed to connect to the test server due to timeout")
+			}
+			t.Fatalf("failed to connect to the test server: %s", err)
+		}
+		t.Cleanup(func() {
+			if err := client.Close(); err != nil {
+				t.Errorf("failed to close client: %s", err)
+			}
+		})
+		invalidMessage := testMessage(t)
+		if err = invalidMessage.To("invalid@domain.tld"); err != nil {
+			t.Fatalf("failed to set recipient: %s", err)
+		}
+		if err = client.Send(testMessage(t), invalidMessage); err == nil {
+			t.Error("expected sending to invalid recipient to fail")
+		}
+
+		if len(collector.dials) != 1 || collector.dials[0] != nil {
+			t.Errorf("expected 1 successful dial, got: %v", collector.dials)
+		}
+		if len(collector.handshake) != 1 || collector.handshake[0] != nil {
+			t.Errorf("expected 1 successful TLS handshake, got: %v", collector.handshake)
+		}
+		if len(collector.auths) != 1 || collector.auths[0] != nil || collector.authTypes[0] != SMTPAuthPlain {
+			t.Errorf("expected 1 successful PLAIN auth, got: %v", collector.auths)
+		}
+		if len(collector.messages) != 2 {
+			t.Fatalf("expected 2 observed messages, got: %d", len(collector.messages))
+		}
+		if collector.messages[0] != nil {
+			t.Errorf("expected first message to succeed, got: %s", collector.messages[0])
+		}
+		var sendErr *SendError
+		if !errors.As(collector.messages[1], &sendErr) || sendErr.Reason != ErrSMTPRcptTo {
+			t.Errorf("expected second message to fail with RCPT TO error, got: %s", collector.messages[1])
+		}
+		if collector.bytes <= 0 {
+			t.Errorf("expected bytes written to be observed, got: %d", collector.bytes)
+		}
+	})
+	t.Run("auth failure is collected", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FailOnAuth: true,
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+		ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+		t.Cleanup(cancelDial)
+
+		collector := &testMetricsCollector{}
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS),
+			WithSMTPAuth(SMTPAuthPlain), WithUsername("invalid"), WithPassword("invalid"),
+			WithMetricsCollector(collector))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(ctxDial); err == nil {
+			t.Fatal("connection was supposed to fail, but didn't")
+		}
+		if len(collector.auths) != 1 || collector.auths[0] == nil {
+			t.Errorf("expected 1 failed auth, got: %v", collector.auths)
+		}
+		if len(collector.handshake) != 0 {
+			t.Errorf("expected no TLS handshake, got: %v", collector.handshake)
+		}
+	})
+	t.Run("failed dial is collected", func(t *testing.T) {
+		collector := &testMetricsCollector{}
+		client, err := NewClient(DefaultHost, WithPort(1), WithTLSPolicy(NoTLS), WithMetricsCollector(collector))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(context.Background()); err == nil {
+			t.Fatal("connection was supposed to fail, but didn't")
+		}
+		if len(collector.dials) != 1 || collector.dials[0] == nil {
+			t.Errorf("expected 1 failed dial, got: %v", collector.dials)
+		}
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	ht "html/template"
@@ -17,7 +18,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"syscall"
 	tt "text/template"
 	"time"
@@ -29,6 +32,9 @@ var (
 
 	// ErrNoRcptAddresses indicates that no recipient addresses have been set.
 	ErrNoRcptAddresses = errors.New("no recipient addresses set")
+
+	// ErrPartIndexOutOfRange indicates that a part index does not refer to a part of the Msg.
+	ErrPartIndexOutOfRange = errors.New("part index out of range")
 )
 
 const (
@@ -42,6 +48,10 @@ const (
 	// errParseMailAddr indicates that parsing of a mail address has failed, including the problematic address
 	// and error.
 	errParseMailAddr = "failed to parse mail address %q: %w"
+
+	// messageIDTagSeparator separates the random string of a generated "Message-ID" from the tag set with
+	// WithMessageIDTag. It is neither part of the random string nor of the encoded tag.
+	messageIDTagSeparator = "+"
 )
 
 const (
@@ -81,13 +91,36 @@ type PGPType int
 // The Msg is the central part of go-mail. It provided a lot of methods that you would expect in a mail
 // user agent (MUA). Msg satisfies the io.WriterTo and io.Reader interfaces.
 type Msg struct {
+	// addrGroups holds the address groups of the address headers. The members of the groups are also
+	// part of addrHeader.
+	addrGroups map[AddrHeader][]*addrGroup
+
 	// addrHeader holds a mapping between AddrHeader keys and their corresponding slices of mail.Address pointers.
 	addrHeader map[AddrHeader][]*mail.Address
 
+	// addrValidation is the AddressValidation that determines how the addresses of the Msg are validated.
+	addrValidation AddressValidation
+
+	// addrValidator is the AddressValidatorFunc that is used to validate the addresses of the Msg, if the
+	// addrValidation is AddressValidationCustom.
+	addrValidator AddressValidatorFunc
+
+	// alternativeOrder holds the content types that define the order of the alternative body parts, if
+	// set with Msg.SetAlternativeOrder.
+	alternativeOrder []ContentType
+
 	// attachments holds a list of File pointers that represent files either as attachments or embeds files in
 	// a Msg.
 	attachments []*File
 
+	// autoFileEncoding indicates whether the encoding of text-like attachments and embeds is chosen based
+	// on their content, if set with WithAutoFileEncoding.
+	autoFileEncoding bool
+
+	// bodyCache is the BodyCache that the encoded content of the body parts is looked up in, if set with
+	// WithBodyCache.
+	bodyCache *BodyCache
+
 	// boundary represents the delimiter for separating parts in a multipart message.
 	boundary string
 
@@ -96,9 +129,17 @@ type Msg struct {
 	// By default we set CharsetUTF8 for a Msg unless overridden by a corresponding MsgOption.
 	charset Charset
 
+	// dateLocation is the time.Location that the "Date" header of the Msg is formatted in. If it is nil,
+	// the location of the given time value is used.
+	dateLocation *time.Location
+
 	// embeds contains a slice of File pointers representing the embedded files in a Msg.
 	embeds []*File
 
+	// emlDiagnostics holds the deviations from the mail standards that were tolerated while parsing the
+	// Msg from an EML. It is nil, if the Msg was not parsed from an EML.
+	emlDiagnostics *EMLDiagnostics
+
 	// encoder is a mime.WordEncoder used to encode strings (such as email headers) using a specified
 	// Encoding.
 	encoder mime.WordEncoder
@@ -110,9 +151,29 @@ type Msg struct {
 	// representing header values.
 	genHeader map[Header][]string
 
+	// headerFolding is the FoldingPolicy that determines how the headers of the Msg are folded.
+	headerFolding FoldingPolicy
+
 	// isDelivered indicates wether the Msg has been delivered.
 	isDelivered bool
 
+	// languageDetector is the LanguageDetector used to detect the language of the Msg.
+	languageDetector LanguageDetector
+
+	// mailFromParams holds the additional ESMTP parameters of the MAIL FROM command.
+	mailFromParams []string
+
+	// maxDepth is the maximum nesting depth of the multipart bodies of the Msg, if set with
+	// WithStructureLimits.
+	maxDepth int
+
+	// maxParts is the maximum number of MIME parts of the Msg, if set with WithStructureLimits.
+	maxParts int
+
+	// messageIDTag is the tag that is embedded into the generated "Message-ID" of the Msg, if set with
+	// WithMessageIDTag.
+	messageIDTag string
+
 	// middlewares is a slice of Middleware used for modifying or handling messages before they are processed.
 	//
 	// middlewares are processed in FIFO order.
@@ -133,12 +194,31 @@ type Msg struct {
 	// different Content-Type settings in the msgWriter.
 	pgptype PGPType
 
+	// rcptParams maps the lower-case recipient addresses to the additional ESMTP parameters of their
+	// RCPT TO command.
+	rcptParams map[string][]string
+
+	// reportType is the report type of a multipart/report Msg, as created by NewDSNMsg and NewMDNMsg. If it
+	// is set, the parts of the Msg are written as parts of a multipart/report body instead of alternatives.
+	reportType string
+
+	// requireTLS indicates whether the Msg must only be delivered over connections that are protected
+	// by TLS, as defined in RFC 8689.
+	requireTLS bool
+
 	// sendError represents an error encountered during the process of sending a Msg during the
 	// Client.Send operation.
 	//
 	// sendError will hold an error of type SendError.
 	sendError error
 
+	// subjectTemplate holds the text/template.Template and data that the subject is rendered from,
+	// if set with Msg.SubjectTemplate.
+	subjectTemplate *subjectTemplate
+
+	// values holds the metadata of the Msg, as set with Msg.SetValue.
+	values map[interface{}]interface{}
+
 	// noDefaultUserAgent indicates whether the default User-Agent will be omitted for the Msg when it is
 	// being sent.
 	//
@@ -149,6 +229,13 @@ type Msg struct {
 // SendmailPath is the default system path to the sendmail binary - at least on standard Unix-like OS.
 const SendmailPath = "/usr/sbin/sendmail"
 
+// subjectTemplate holds the text/template.Template and data that the subject of a Msg is rendered
+// from.
+type subjectTemplate struct {
+	data interface{}
+	tpl  *tt.Template
+}
+
 // MsgOption is a function type that modifies a Msg instance during its creation or initialization.
 type MsgOption func(*Msg)
 
@@ -275,6 +362,29 @@ func WithBoundary(boundary string) MsgOption {
 	}
 }
 
+// WithDateLocation sets the time.Location that the "Date" header of the Msg is formatted in during
+// its creation or initialization.
+//
+// By default, the "Date" header is formatted in the local time zone when set via Msg.SetDate and in the
+// location of the given time value when set via Msg.SetDateWithValue. Some compliance regimes require the
+// headers of a message to be in a specific zone, and tests might require fixed zones for deterministic
+// output. With this MsgOption, the "Date" header is always converted to the given location. A nil
+// location restores the default behaviour.
+//
+// Parameters:
+//   - location: The time.Location that the "Date" header is formatted in.
+//
+// Returns:
+//   - A MsgOption function that can be used to customize the Msg instance.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.3
+func WithDateLocation(location *time.Location) MsgOption {
+	return func(m *Msg) {
+		m.dateLocation = location
+	}
+}
+
 // WithMiddleware adds the given Middleware to the end of the list of the Client middlewares slice.
 // Middleware are processed in FIFO order.
 //
@@ -333,6 +443,50 @@ func WithNoDefaultUserAgent() MsgOption {
 	}
 }
 
+// WithAutoFileEncoding enables the automatic choice of the encoding of text-like attachments and embeds
+// of the Msg during its creation or initialization.
+//
+// By default, attachments and embeds are Base64 encoded, which grows text files like logs or CSV
+// exports by a third. With this option, files with a text-like content type, i. e. "text/*",
+// "application/json" or "application/xml", are quoted-printable encoded if their content is mostly
+// ASCII, which keeps them smaller and readable in the raw mail. Files with other content types or
+// with binary content remain Base64 encoded. The encoding set for a File with WithFileEncoding takes
+// precedence over the automatic choice.
+//
+// Returns:
+//   - A MsgOption function that enables the automatic encoding of text-like files for the Msg.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2045#section-6.8
+//   - https://datatracker.ietf.org/doc/html/rfc2045#section-6.7
+func WithAutoFileEncoding() MsgOption {
+	return func(m *Msg) {
+		m.autoFileEncoding = true
+	}
+}
+
+// WithMessageIDTag embeds the given tag into the "Message-ID" that is generated for the Msg.
+//
+// The tag, i. e. the ID of a campaign or batch, can be extracted from the "Message-ID" with
+// MessageIDTag. This allows bounce processors to attribute delivery status notifications, which
+// reference the "Message-ID" of the original Msg, without storing the "Message-ID" of every Msg that
+// has been sent. The tag is encoded, but not encrypted, so it must not contain sensitive data. It has
+// no effect on a "Message-ID" set with Msg.SetMessageIDWithValue.
+//
+// Parameters:
+//   - tag: The opaque tag to embed into the "Message-ID".
+//
+// Returns:
+//   - A MsgOption function that sets the tag of the "Message-ID" of the Msg.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.6.4
+func WithMessageIDTag(tag string) MsgOption {
+	return func(m *Msg) {
+		m.messageIDTag = tag
+	}
+}
+
 // SetCharset sets or overrides the currently set encoding charset of the Msg.
 //
 // This method allows you to specify a character set for the email message. The charset is
@@ -537,6 +691,9 @@ func (m *Msg) SetGenHeaderPreformatted(header Header, value string) {
 // provided addresses are properly formatted and parsed. Using this method helps maintain the
 // integrity of the email addresses within the message.
 //
+// For the "To", "Cc" and "Bcc" headers, a value may also be an address group in the group syntax of
+// RFC 5322, i. e. "Team: alice@example.com, bob@example.com;" or "Undisclosed recipients:;".
+//
 // Parameters:
 //   - header: The AddrHeader to set in the Msg (e.g., "From", "To", "Cc", "Bcc").
 //   - values: One or more string values representing the email addresses to associate with
@@ -549,13 +706,26 @@ func (m *Msg) SetAddrHeader(header AddrHeader, values ...string) error {
 		m.addrHeader = make(map[AddrHeader][]*mail.Address)
 	}
 	var addresses []*mail.Address
+	var groups []*addrGroup
 	for _, addrVal := range values {
-		address, err := mail.ParseAddress(addrVal)
+		if supportsAddrGroups(header) {
+			group, isGroup, err := m.parseAddrGroup(addrVal)
+			if err != nil {
+				return err
+			}
+			if isGroup {
+				groups = append(groups, group)
+				addresses = append(addresses, group.members...)
+				continue
+			}
+		}
+		address, err := m.parseAddress(addrVal)
 		if err != nil {
 			return fmt.Errorf(errParseMailAddr, addrVal, err)
 		}
 		addresses = append(addresses, address)
 	}
+	m.setAddrGroups(header, groups)
 	switch header {
 	case HeaderFrom:
 		if len(addresses) > 0 {
@@ -587,13 +757,25 @@ func (m *Msg) SetAddrHeaderIgnoreInvalid(header AddrHeader, values ...string) {
 		m.addrHeader = make(map[AddrHeader][]*mail.Address)
 	}
 	var addresses []*mail.Address
+	var groups []*addrGroup
 	for _, addrVal := range values {
-		address, err := mail.ParseAddress(m.encodeString(addrVal))
+		if supportsAddrGroups(header) {
+			group, isGroup, err := m.parseAddrGroup(addrVal)
+			if isGroup {
+				if err == nil {
+					groups = append(groups, group)
+					addresses = append(addresses, group.members...)
+				}
+				continue
+			}
+		}
+		address, err := m.parseAddress(m.encodeString(addrVal))
 		if err != nil {
 			continue
 		}
 		addresses = append(addresses, address)
 	}
+	m.setAddrGroups(header, groups)
 	switch header {
 	case HeaderFrom:
 		if len(addresses) > 0 {
@@ -967,7 +1149,7 @@ func (m *Msg) BccFromString(rcpts string) error {
 // References:
 //   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.6.2
 func (m *Msg) ReplyTo(addr string) error {
-	replyTo, err := mail.ParseAddress(addr)
+	replyTo, err := m.parseAddress(addr)
 	if err != nil {
 		return fmt.Errorf("failed to parse reply-to address: %w", err)
 	}
@@ -1004,9 +1186,44 @@ func (m *Msg) ReplyToFormat(name, addr string) error {
 // References:
 //   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.6.5
 func (m *Msg) Subject(subj string) {
+	m.subjectTemplate = nil
 	m.SetGenHeader(HeaderSubject, subj)
 }
 
+// SubjectTemplate sets the "Subject" header for the Msg from the output of the given
+// text/template.Template.
+//
+// The template is executed with the provided data, just like the templates of the body parts. Line
+// breaks in the output are replaced with spaces and leading and trailing whitespace is removed, so
+// that the subject is always a single line. The template and data are kept with the Msg: each time
+// the Msg is written, i. e. when a send is retried, the subject is rendered again before the
+// middlewares are applied. This way, middlewares that modify the subject do not stack up their
+// changes, and changes to the data are reflected. If the template fails on a later rendering, the
+// previously rendered subject is kept. Calling Msg.Subject replaces the template.
+//
+// Parameters:
+//   - tpl: A pointer to the text/template.Template to be used for the subject.
+//   - data: The data to populate the template.
+//
+// Returns:
+//   - An error if the template is nil or fails to execute, otherwise nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.6.5
+func (m *Msg) SubjectTemplate(tpl *tt.Template, data interface{}) error {
+	if tpl == nil {
+		return errors.New(errTplPointerNil)
+	}
+	subjectTpl := &subjectTemplate{data: data, tpl: tpl}
+	subject, err := subjectTpl.render()
+	if err != nil {
+		return err
+	}
+	m.Subject(subject)
+	m.subjectTemplate = subjectTpl
+	return nil
+}
+
 // SetMessageID generates and sets a unique "Message-ID" header for the Msg.
 //
 // This method creates a "Message-ID" string using a randomly generated string and the hostname of the machine.
@@ -1014,7 +1231,7 @@ func (m *Msg) Subject(subj string) {
 // duplication. If the hostname cannot be retrieved, it defaults to "localhost.localdomain".
 //
 // The generated Message-ID follows the format
-// "<randomString@hostname>".
+// "<randomString@hostname>", or "<randomString+tag@hostname>" if a tag is set with WithMessageIDTag.
 //
 // References:
 //   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.6.4
@@ -1025,9 +1242,38 @@ func (m *Msg) SetMessageID() {
 	}
 	// We have 64 possible characters, which for a 22 character string, provides approx. 132 bits of entropy.
 	randString, _ := randomStringSecure(22)
+	if m.messageIDTag != "" {
+		randString += messageIDTagSeparator + base64.RawURLEncoding.EncodeToString([]byte(m.messageIDTag))
+	}
 	m.SetMessageIDWithValue(fmt.Sprintf("%s@%s", randString, hostname))
 }
 
+// MessageIDTag extracts the tag that has been embedded into the given "Message-ID" with WithMessageIDTag.
+//
+// Parameters:
+//   - messageID: The "Message-ID", with or without the enclosing angle brackets, i. e. as referenced by a
+//     delivery status notification.
+//
+// Returns:
+//   - The tag of the "Message-ID".
+//   - A boolean indicating whether the "Message-ID" holds a tag.
+func MessageIDTag(messageID string) (string, bool) {
+	messageID = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(messageID), "<"), ">")
+	at := strings.LastIndex(messageID, "@")
+	if at < 0 {
+		return "", false
+	}
+	separator := strings.LastIndex(messageID[:at], messageIDTagSeparator)
+	if separator < 0 {
+		return "", false
+	}
+	tag, err := base64.RawURLEncoding.DecodeString(messageID[separator+1 : at])
+	if err != nil || len(tag) == 0 {
+		return "", false
+	}
+	return string(tag), true
+}
+
 // GetMessageID retrieves the "Message-ID" header from the Msg.
 //
 // This method checks if a "Message-ID" has been set in the message's generated headers. If a valid "Message-ID"
@@ -1077,11 +1323,37 @@ func (m *Msg) SetBulk() {
 	m.SetGenHeader(HeaderXAutoResponseSuppress, "All")
 }
 
+// RequireTLS controls whether the Msg must be delivered over TLS protected connections only, as
+// defined in RFC 8689.
+//
+// If require is true, the Client adds the REQUIRETLS parameter to the MAIL FROM command, which asks
+// the SMTP server and all following hops to only relay the Msg over TLS connections with a verified
+// server certificate. The delivery fails with a SendError of reason ErrRequireTLS if the connection
+// to the SMTP server is not encrypted, the certificate of the server is not verified or the server
+// does not advertise the REQUIRETLS extension. If require is false, the "TLS-Required: No" header is
+// set instead, which asks the receiving servers to deliver the Msg even if their TLS policies, like
+// MTA-STS or DANE, cannot be satisfied.
+//
+// Parameters:
+//   - require: Whether TLS is required for the delivery of the Msg.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc8689
+func (m *Msg) RequireTLS(require bool) {
+	m.requireTLS = require
+	if require {
+		delete(m.genHeader, HeaderTLSRequired)
+		return
+	}
+	m.SetGenHeader(HeaderTLSRequired, "No")
+}
+
 // SetDate sets the "Date" header for the Msg to the current time in a valid RFC 1123 format.
 //
 // This method retrieves the current time and formats it according to RFC 1123, ensuring that the "Date"
 // header is compliant with email standards. The "Date" header indicates when the message was created,
-// providing recipients with context for the timing of the email.
+// providing recipients with context for the timing of the email. If a location was set with
+// WithDateLocation, the current time in that location is used.
 //
 // References:
 //   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.3
@@ -1095,7 +1367,8 @@ func (m *Msg) SetDate() {
 // This method takes a `time.Time` value as input and formats it according to RFC 1123, ensuring that the "Date"
 // header is compliant with email standards. The "Date" header indicates when the message was created,
 // providing recipients with context for the timing of the email. This allows for setting a custom date
-// rather than using the current time.
+// rather than using the current time. If a location was set with WithDateLocation, the time value is
+// converted to that location first.
 //
 // Parameters:
 //   - timeVal: The time value used to set the "Date" header.
@@ -1104,6 +1377,9 @@ func (m *Msg) SetDate() {
 //   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.3
 //   - https://datatracker.ietf.org/doc/html/rfc1123
 func (m *Msg) SetDateWithValue(timeVal time.Time) {
+	if m.dateLocation != nil {
+		timeVal = timeVal.In(m.dateLocation)
+	}
 	m.SetGenHeader(HeaderDate, timeVal.Format(time.RFC1123Z))
 }
 
@@ -1192,7 +1468,7 @@ func (m *Msg) RequestMDNTo(rcpts ...string) error {
 	}
 	var addresses []string
 	for _, addrVal := range rcpts {
-		address, err := mail.ParseAddress(addrVal)
+		address, err := m.parseAddress(addrVal)
 		if err != nil {
 			return fmt.Errorf(errParseMailAddr, addrVal, err)
 		}
@@ -1232,7 +1508,7 @@ func (m *Msg) RequestMDNToFormat(name, addr string) error {
 // References:
 //   - https://datatracker.ietf.org/doc/html/rfc8098
 func (m *Msg) RequestMDNAddTo(rcpt string) error {
-	address, err := mail.ParseAddress(rcpt)
+	address, err := m.parseAddress(rcpt)
 	if err != nil {
 		return fmt.Errorf(errParseMailAddr, rcpt, err)
 	}
@@ -1765,6 +2041,95 @@ func (m *Msg) AddAlternativeWriter(
 	m.parts = append(m.parts, part)
 }
 
+// ReplaceBodyString replaces the content of the body part with the given content type in place.
+//
+// Unlike SetBodyString, which discards all body parts and creates a new one, this method only swaps
+// the content of the first part that matches the content type, while the other alternative parts,
+// the attachments and the embeds of the Msg are kept as they are. This allows a Msg to be corrected
+// or re-rendered before it is sent again. Content types are matched without their parameters. The
+// charset, encoding and description of the part are kept, unless they are overridden by the given
+// PartOption. If the Msg has no such part, the content is added as alternative body part, like with
+// AddAlternativeString.
+//
+// Parameters:
+//   - contentType: The ContentType of the body part to replace (e.g., plain text, HTML).
+//   - content: The string content to set as the content of the body part.
+//   - opts: Optional parameters for customizing the body part.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2045
+//   - https://datatracker.ietf.org/doc/html/rfc2046
+func (m *Msg) ReplaceBodyString(contentType ContentType, content string, opts ...PartOption) {
+	mediaType := alternativeMediaType(contentType)
+	for index, part := range m.parts {
+		if !part.isDeleted && alternativeMediaType(part.contentType) == mediaType {
+			_ = m.ReplaceAlternative(index, contentType, content, opts...)
+			return
+		}
+	}
+	m.AddAlternativeString(contentType, content, opts...)
+}
+
+// ReplaceAlternative replaces the content type and the content of the body part at the given index in
+// place.
+//
+// The index refers to the parts as returned by GetParts, where 0 is the body part set with
+// SetBodyString and the following indexes are the alternative body parts in the order they have been
+// added. The position of the part, the attachments and the embeds of the Msg are kept. The charset,
+// encoding and description of the part are kept, unless they are overridden by the given PartOption.
+//
+// Parameters:
+//   - index: The index of the body part to replace.
+//   - contentType: The new ContentType of the body part.
+//   - content: The string content to set as the content of the body part.
+//   - opts: Optional parameters for customizing the body part.
+//
+// Returns:
+//   - ErrPartIndexOutOfRange if the index does not refer to a part of the Msg or the part has been
+//     deleted, otherwise nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2045
+//   - https://datatracker.ietf.org/doc/html/rfc2046
+func (m *Msg) ReplaceAlternative(index int, contentType ContentType, content string, opts ...PartOption) error {
+	if index < 0 || index >= len(m.parts) || m.parts[index].isDeleted {
+		return ErrPartIndexOutOfRange
+	}
+	part := m.parts[index]
+	part.contentType = contentType
+	part.SetContent(content)
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(part)
+	}
+	return nil
+}
+
+// SetAlternativeOrder sets the order in which the alternative body parts of the Msg are written.
+//
+// Mail clients usually display the last alternative part that they support, so the order of the
+// parts expresses the preference of the sender, as defined in RFC 2046. By default, the parts are
+// written in the order in which they were added. With SetAlternativeOrder, the parts are written in
+// the order of the given content types instead, i. e. "text/plain", "text/x-amp-html" and
+// "text/html" to prefer HTML over AMP. Content types are matched without their parameters, so that
+// TypeTextCalendar matches calendar parts with any method. Parts with a content type that is not
+// listed keep their relative order and are written before the listed parts, as the least preferred
+// alternatives. Calling SetAlternativeOrder without content types restores the default order.
+//
+// Parameters:
+//   - types: The content types of the alternative body parts in the order of increasing preference.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2046#section-5.1.4
+func (m *Msg) SetAlternativeOrder(types ...ContentType) {
+	m.alternativeOrder = nil
+	for _, contentType := range types {
+		m.alternativeOrder = append(m.alternativeOrder, alternativeMediaType(contentType))
+	}
+}
+
 // AddAlternativeHTMLTemplate sets the alternative body of the message to an html/template.Template output.
 //
 // The content type will be set to "text/html" automatically. This method executes the provided HTML template
@@ -1849,7 +2214,7 @@ func (m *Msg) AttachFile(name string, opts ...FileOption) {
 //
 // This method allows you to attach a file to the message using an io.Reader. It reads all data from the
 // io.Reader into memory before attaching the file, which may not be suitable for large data sources.
-// For larger files, it is recommended to use AttachFile or AttachReadSeeker instead.
+// For larger files, it is recommended to use AttachFile, AttachReadSeeker or AttachStream instead.
 //
 // Parameters:
 //   - name: The name of the file to be attached.
@@ -1964,12 +2329,7 @@ func (m *Msg) AttachFromEmbedFS(name string, fs *embed.FS, opts ...FileOption) e
 	if fs == nil {
 		return fmt.Errorf("embed.FS must not be nil")
 	}
-	file, err := fileFromEmbedFS(name, fs)
-	if err != nil {
-		return err
-	}
-	m.attachments = m.appendFile(m.attachments, file, opts...)
-	return nil
+	return m.AttachFromFS(fs, name, opts...)
 }
 
 // EmbedFile adds an embedded File to the Msg.
@@ -2110,12 +2470,7 @@ func (m *Msg) EmbedFromEmbedFS(name string, fs *embed.FS, opts ...FileOption) er
 	if fs == nil {
 		return fmt.Errorf("embed.FS must not be nil")
 	}
-	file, err := fileFromEmbedFS(name, fs)
-	if err != nil {
-		return err
-	}
-	m.embeds = m.appendFile(m.embeds, file, opts...)
-	return nil
+	return m.EmbedFromFS(fs, name, opts...)
 }
 
 // Reset resets all headers, body parts, attachments, and embeds of the Msg.
@@ -2127,6 +2482,7 @@ func (m *Msg) EmbedFromEmbedFS(name string, fs *embed.FS, opts ...FileOption) er
 // References:
 //   - https://datatracker.ietf.org/doc/html/rfc5322
 func (m *Msg) Reset() {
+	m.addrGroups = nil
 	m.addrHeader = make(map[AddrHeader][]*mail.Address)
 	m.attachments = nil
 	m.embeds = nil
@@ -2134,6 +2490,39 @@ func (m *Msg) Reset() {
 	m.parts = nil
 }
 
+// SetValue stores a metadata value for the given key in the Msg.
+//
+// The metadata is not part of the mail. Middlewares, hooks and the code that creates and sends the
+// Msg can use it to pass state along with the Msg, i. e. the chosen DKIM selector or the ID of a
+// campaign, instead of storing it in headers. Like the keys of a context.Context, the key must be
+// comparable and should be of an unexported type of the package that sets it, to avoid collisions
+// with other packages. Setting a nil value removes the key.
+//
+// Parameters:
+//   - key: The comparable key of the value.
+//   - value: The value to store for the key.
+func (m *Msg) SetValue(key, value interface{}) {
+	if value == nil {
+		delete(m.values, key)
+		return
+	}
+	if m.values == nil {
+		m.values = make(map[interface{}]interface{})
+	}
+	m.values[key] = value
+}
+
+// Value returns the metadata value stored for the given key with Msg.SetValue.
+//
+// Parameters:
+//   - key: The key of the value.
+//
+// Returns:
+//   - The value stored for the key, or nil if no value is stored for it.
+func (m *Msg) Value(key interface{}) interface{} {
+	return m.values[key]
+}
+
 // ApplyMiddlewares applies the list of middlewares to a Msg.
 //
 // This method sequentially applies each middleware function in the list to the message (in FIFO order).
@@ -2146,9 +2535,17 @@ func (m *Msg) Reset() {
 // Returns:
 //   - The modified Msg after all middleware functions have been applied.
 func (m *Msg) applyMiddlewares(msg *Msg) *Msg {
+	subjectTpl := m.subjectTemplate
+	if subjectTpl != nil {
+		if subject, err := subjectTpl.render(); err == nil {
+			msg.SetGenHeader(HeaderSubject, subject)
+		}
+	}
 	for _, middleware := range m.middlewares {
 		msg = middleware.Handle(msg)
 	}
+	// Middlewares that set the subject must not detach it from its template.
+	m.subjectTemplate = subjectTpl
 	return msg
 }
 
@@ -2168,7 +2565,10 @@ func (m *Msg) applyMiddlewares(msg *Msg) *Msg {
 // References:
 //   - https://datatracker.ietf.org/doc/html/rfc5322
 func (m *Msg) WriteTo(writer io.Writer) (int64, error) {
-	mw := &msgWriter{writer: writer, charset: m.charset, encoder: m.encoder}
+	mw := &msgWriter{
+		writer: writer, charset: m.charset, encoder: m.encoder, folding: m.headerFolding,
+		autoFileEncoding: m.autoFileEncoding, bodyCache: m.bodyCache,
+	}
 	mw.writeMsg(m.applyMiddlewares(m))
 	return mw.bytesWritten, mw.err
 }
@@ -2200,7 +2600,10 @@ func (m *Msg) WriteToSkipMiddleware(writer io.Writer, middleWareType MiddlewareT
 		middlewares = append(middlewares, m.middlewares[i])
 	}
 	m.middlewares = middlewares
-	mw := &msgWriter{writer: writer, charset: m.charset, encoder: m.encoder}
+	mw := &msgWriter{
+		writer: writer, charset: m.charset, encoder: m.encoder, folding: m.headerFolding,
+		autoFileEncoding: m.autoFileEncoding, bodyCache: m.bodyCache,
+	}
 	mw.writeMsg(m.applyMiddlewares(m))
 	m.middlewares = origMiddlewares
 	return mw.bytesWritten, mw.err
@@ -2305,8 +2708,21 @@ func (m *Msg) WriteToSendmailWithCommand(sendmailPath string) error {
 // References:
 //   - https://datatracker.ietf.org/doc/html/rfc5321
 func (m *Msg) WriteToSendmailWithContext(ctx context.Context, sendmailPath string, args ...string) error {
+	return m.writeToSendmail(ctx, sendmailPath, append([]string{"-oi", "-t"}, args...))
+}
+
+// writeToSendmail executes the sendmail binary with the given arguments and writes the Msg to
+// its STDIN.
+//
+// Parameters:
+//   - ctx: The context to control the timeout and cancellation of the sendmail process.
+//   - sendmailPath: The path to the sendmail executable.
+//   - args: The complete list of arguments for the sendmail binary.
+//
+// Returns:
+//   - An error if sending the message via sendmail fails, otherwise nil.
+func (m *Msg) writeToSendmail(ctx context.Context, sendmailPath string, args []string) error {
 	cmdCtx := exec.CommandContext(ctx, sendmailPath)
-	cmdCtx.Args = append(cmdCtx.Args, "-oi", "-t")
 	cmdCtx.Args = append(cmdCtx.Args, args...)
 
 	stdErr, err := cmdCtx.StderrPipe()
@@ -2455,14 +2871,28 @@ func (m *Msg) SendError() error {
 // References:
 //   - https://datatracker.ietf.org/doc/html/rfc5322
 func (m *Msg) addAddr(header AddrHeader, addr string) error {
-	var addresses []string
-	for _, address := range m.addrHeader[header] {
-		addresses = append(addresses, address.String())
-	}
+	addresses := m.addrHeaderValues(header)
 	addresses = append(addresses, addr)
 	return m.SetAddrHeader(header, addresses...)
 }
 
+// setAddrGroups sets the address groups of the given address header, removing the previous groups
+// of the header.
+//
+// Parameters:
+//   - header: The AddrHeader the groups belong to.
+//   - groups: The address groups of the header.
+func (m *Msg) setAddrGroups(header AddrHeader, groups []*addrGroup) {
+	if len(groups) == 0 {
+		delete(m.addrGroups, header)
+		return
+	}
+	if m.addrGroups == nil {
+		m.addrGroups = make(map[AddrHeader][]*addrGroup)
+	}
+	m.addrGroups[header] = groups
+}
+
 // appendFile adds a File to the Msg, either as an attachment or an embed.
 //
 // This method appends a File to the list of files (attachments or embeds) for the message. It applies
@@ -2533,7 +2963,44 @@ func (m *Msg) hasAlt() bool {
 			count++
 		}
 	}
-	return count > 1 && m.pgptype == 0
+	return count > 1 && m.pgptype == 0 && m.reportType == ""
+}
+
+// orderedParts returns the parts of the Msg in the order in which they are written. If an order of the
+// alternative parts is set with Msg.SetAlternativeOrder, the parts are sorted accordingly; otherwise,
+// the parts are returned in the order in which they were added.
+//
+// Returns:
+//   - A slice of Part pointers in the order in which they are written.
+func (m *Msg) orderedParts() []*Part {
+	if len(m.alternativeOrder) == 0 {
+		return m.parts
+	}
+	rank := func(part *Part) int {
+		mediaType := alternativeMediaType(part.contentType)
+		for i, contentType := range m.alternativeOrder {
+			if contentType == mediaType {
+				return i
+			}
+		}
+		return -1
+	}
+	parts := make([]*Part, len(m.parts))
+	copy(parts, m.parts)
+	sort.SliceStable(parts, func(i, j int) bool {
+		return rank(parts[i]) < rank(parts[j])
+	})
+	return parts
+}
+
+// alternativeMediaType returns the lower-cased media type of the given ContentType, without any
+// parameters.
+func alternativeMediaType(contentType ContentType) ContentType {
+	mediaType := string(contentType)
+	if index := strings.IndexByte(mediaType, ';'); index >= 0 {
+		mediaType = mediaType[:index]
+	}
+	return ContentType(strings.ToLower(strings.TrimSpace(mediaType)))
 }
 
 // hasMixed returns true if the Msg has mixed parts.
@@ -2580,6 +3047,18 @@ func (m *Msg) hasPGPType() bool {
 	return m.pgptype > 0
 }
 
+// hasReport returns true if the Msg should be written as multipart/report, as created by NewDSNMsg and
+// NewMDNMsg.
+//
+// Returns:
+//   - A boolean value indicating whether the Msg is a report.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc6522
+func (m *Msg) hasReport() bool {
+	return m.reportType != "" && m.pgptype == 0
+}
+
 // newPart returns a new Part for the Msg.
 //
 // This method creates a new Part for the message with the specified content type,
@@ -2666,45 +3145,6 @@ func (m *Msg) addDefaultHeader() {
 	m.SetGenHeader(HeaderMIMEVersion, string(m.mimever))
 }
 
-// fileFromEmbedFS returns a File pointer from a given file in the provided embed.FS.
-//
-// This method retrieves a file from the embedded filesystem (embed.FS) and returns a File structure
-// that can be used as an attachment or embed in the email message. The file's content is read when
-// writing to an io.Writer, and the file is identified by its base name.
-//
-// Parameters:
-//   - name: The name of the file to retrieve from the embedded filesystem.
-//   - fs: A pointer to the embed.FS from which the file will be opened.
-//
-// Returns:
-//   - A pointer to the File structure representing the embedded file.
-//   - An error if the file cannot be opened or read from the embedded filesystem.
-//
-// References:
-//   - https://datatracker.ietf.org/doc/html/rfc2183
-func fileFromEmbedFS(name string, fs *embed.FS) (*File, error) {
-	_, err := fs.Open(name)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file from embed.FS: %w", err)
-	}
-	return &File{
-		Name:   filepath.Base(name),
-		Header: make(map[string][]string),
-		Writer: func(writer io.Writer) (int64, error) {
-			file, err := fs.Open(name)
-			if err != nil {
-				return 0, err
-			}
-			numBytes, err := io.Copy(writer, file)
-			if err != nil {
-				_ = file.Close()
-				return numBytes, fmt.Errorf("failed to copy file to io.Writer: %w", err)
-			}
-			return numBytes, file.Close()
-		},
-	}, nil
-}
-
 // fileFromFS returns a File pointer from a given file in the system's file system.
 //
 // This method retrieves a file from the system's file system and returns a File structure
@@ -2765,17 +3205,11 @@ func fileFromReader(name string, reader io.Reader) (*File, error) {
 	if err != nil {
 		return &File{}, err
 	}
-	byteReader := bytes.NewReader(d)
 	return &File{
 		Name:   name,
 		Header: make(map[string][]string),
 		Writer: func(writer io.Writer) (int64, error) {
-			readBytes, copyErr := io.Copy(writer, byteReader)
-			if copyErr != nil {
-				return readBytes, copyErr
-			}
-			_, copyErr = byteReader.Seek(0, io.SeekStart)
-			return readBytes, copyErr
+			return io.Copy(writer, bytes.NewReader(d))
 		},
 	}, nil
 }
@@ -2784,7 +3218,9 @@ func fileFromReader(name string, reader io.Reader) (*File, error) {
 //
 // This method creates a File structure from an io.ReadSeeker, allowing efficient handling of file content
 // by seeking and reading from the source without fully loading it into memory. The content is written
-// to an io.Writer when needed, and the reader's position is reset to the start after writing.
+// to an io.Writer when needed, and the reader's position is reset to the start after writing, even if
+// writing fails, so that a retried delivery writes the complete content. Concurrent writes, i. e. of
+// copies of the Msg created with Clone, are serialized.
 //
 // Parameters:
 //   - name: The name of the file to be represented by the io.ReadSeeker.
@@ -2796,15 +3232,17 @@ func fileFromReader(name string, reader io.Reader) (*File, error) {
 // References:
 //   - https://datatracker.ietf.org/doc/html/rfc2183
 func fileFromReadSeeker(name string, reader io.ReadSeeker) *File {
+	var mutex sync.Mutex
 	return &File{
 		Name:   name,
 		Header: make(map[string][]string),
 		Writer: func(writer io.Writer) (int64, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
 			readBytes, err := io.Copy(writer, reader)
-			if err != nil {
-				return readBytes, err
+			if _, seekErr := reader.Seek(0, io.SeekStart); err == nil {
+				err = seekErr
 			}
-			_, err = reader.Seek(0, io.SeekStart)
 			return readBytes, err
 		},
 	}
@@ -2866,6 +3304,20 @@ func fileFromTextTemplate(name string, tpl *tt.Template, data interface{}) (*Fil
 	return fileFromReader(name, &buffer)
 }
 
+// render executes the subject template and returns its output as a single line.
+//
+// Returns:
+//   - The rendered subject.
+//   - An error if the template fails to execute, otherwise nil.
+func (t *subjectTemplate) render() (string, error) {
+	buffer := bytes.NewBuffer(nil)
+	if err := t.tpl.Execute(buffer, t.data); err != nil {
+		return "", fmt.Errorf(errTplExecuteFailed, err)
+	}
+	subject := strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(buffer.String())
+	return strings.TrimSpace(subject), nil
+}
+
 // getEncoder creates a new mime.WordEncoder based on the encoding setting of the message.
 //
 // This function returns a mime.WordEncoder based on the specified encoding (e.g., quoted-printable or base64).
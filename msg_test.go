@@ -1619,6 +1619,60 @@ func TestMsg_Subject(t *testing.T) {
 	}
 }
 
+func TestMsg_SubjectTemplate(t *testing.T) {
+	t.Run("subject is rendered", func(t *testing.T) {
+		message := NewMsg()
+		tpl := ttpl.Must(ttpl.New("subject").Parse("Order {{.}}\nshipped\n"))
+		if err := message.SubjectTemplate(tpl, 42); err != nil {
+			t.Fatalf("failed to set subject template: %s", err)
+		}
+		checkGenHeader(t, message, HeaderSubject, "SubjectTemplate", 0, 1, "Order 42 shipped")
+	})
+	t.Run("subject is rendered again with middlewares", func(t *testing.T) {
+		message := NewMsg(WithMiddleware(uppercaseMiddleware{}))
+		data := &struct{ Attempt int }{Attempt: 1}
+		tpl := ttpl.Must(ttpl.New("subject").Parse("attempt {{.Attempt}}"))
+		if err := message.SubjectTemplate(tpl, data); err != nil {
+			t.Fatalf("failed to set subject template: %s", err)
+		}
+		for attempt := 1; attempt <= 2; attempt++ {
+			data.Attempt = attempt
+			buffer := bytes.NewBuffer(nil)
+			if _, err := message.WriteTo(buffer); err != nil {
+				t.Fatalf("failed to write message: %s", err)
+			}
+			want := fmt.Sprintf("Subject: ATTEMPT %d\r\n", attempt)
+			if !strings.Contains(buffer.String(), want) {
+				t.Errorf("expected subject %q, got: %s", want, buffer.String())
+			}
+		}
+	})
+	t.Run("Subject replaces the template", func(t *testing.T) {
+		message := NewMsg()
+		tpl := ttpl.Must(ttpl.New("subject").Parse("templated"))
+		if err := message.SubjectTemplate(tpl, nil); err != nil {
+			t.Fatalf("failed to set subject template: %s", err)
+		}
+		message.Subject("fixed")
+		message = message.applyMiddlewares(message)
+		checkGenHeader(t, message, HeaderSubject, "SubjectTemplate", 0, 1, "fixed")
+	})
+	t.Run("nil template fails", func(t *testing.T) {
+		if err := NewMsg().SubjectTemplate(nil, nil); err == nil {
+			t.Error("expected nil template to fail")
+		}
+	})
+	t.Run("failing template keeps the subject", func(t *testing.T) {
+		message := NewMsg()
+		message.Subject("unchanged")
+		tpl := ttpl.Must(ttpl.New("subject").Parse("{{.Missing}}"))
+		if err := message.SubjectTemplate(tpl, struct{}{}); err == nil {
+			t.Error("expected failing template to return an error")
+		}
+		checkGenHeader(t, message, HeaderSubject, "SubjectTemplate", 0, 1, "unchanged")
+	})
+}
+
 func TestMsg_SetMessageID(t *testing.T) {
 	t.Run("SetMessageID randomness", func(t *testing.T) {
 		var mids []string
@@ -1641,6 +1695,51 @@ func TestMsg_SetMessageID(t *testing.T) {
 			}
 		}
 	})
+	t.Run("SetMessageID with tag", func(t *testing.T) {
+		for _, tag := range []string{"campaign-2024/11", "batch 7+8@list", "ü"} {
+			message := NewMsg(WithMessageIDTag(tag))
+			message.SetMessageID()
+			messageID := message.GetMessageID()
+			if !strings.HasPrefix(messageID, "<") || !strings.HasSuffix(messageID, ">") {
+				t.Fatalf("expected Message-ID to be enclosed in angle brackets, got: %s", messageID)
+			}
+			if strings.ContainsAny(messageID, " /=") {
+				t.Errorf("expected Message-ID %q to contain only valid characters", messageID)
+			}
+			got, ok := MessageIDTag(messageID)
+			if !ok {
+				t.Fatalf("expected Message-ID %q to hold a tag", messageID)
+			}
+			if got != tag {
+				t.Errorf("expected tag %q, got: %q", tag, got)
+			}
+			if got, ok = MessageIDTag(strings.Trim(messageID, "<>")); !ok || got != tag {
+				t.Errorf("expected tag %q without angle brackets, got: %q", tag, got)
+			}
+		}
+	})
+}
+
+func TestMessageIDTag(t *testing.T) {
+	message := NewMsg()
+	message.SetMessageID()
+	tests := []struct {
+		name      string
+		messageID string
+	}{
+		{"generated without tag", message.GetMessageID()},
+		{"no at sign", "<abcdef>"},
+		{"invalid tag encoding", "<abc+!!!@example.com>"},
+		{"empty tag", "<abc+@example.com>"},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tag, ok := MessageIDTag(tt.messageID); ok {
+				t.Errorf("expected no tag, got: %q", tag)
+			}
+		})
+	}
 }
 
 func TestMsg_GetMessageID(t *testing.T) {
@@ -1696,6 +1795,25 @@ func TestMsg_SetBulk(t *testing.T) {
 	checkGenHeader(t, message, HeaderXAutoResponseSuppress, "Bulk", 0, 1, "All")
 }
 
+func TestMsg_RequireTLS(t *testing.T) {
+	message := NewMsg()
+	if message == nil {
+		t.Fatal("message is nil")
+	}
+	message.RequireTLS(false)
+	if message.requireTLS {
+		t.Error("expected message to not require TLS")
+	}
+	checkGenHeader(t, message, HeaderTLSRequired, "RequireTLS", 0, 1, "No")
+	message.RequireTLS(true)
+	if !message.requireTLS {
+		t.Error("expected message to require TLS")
+	}
+	if _, ok := message.genHeader[HeaderTLSRequired]; ok {
+		t.Error("expected TLS-Required header to be removed")
+	}
+}
+
 func TestMsg_SetDate(t *testing.T) {
 	t.Run("SetDate and compare date down to the minute", func(t *testing.T) {
 		message := NewMsg()
@@ -1725,6 +1843,21 @@ func TestMsg_SetDate(t *testing.T) {
 				nowNoSec.String())
 		}
 	})
+	t.Run("SetDate with WithDateLocation", func(t *testing.T) {
+		location := time.FixedZone("UTC+9", 9*60*60)
+		message := NewMsg(WithDateLocation(location))
+		if message == nil {
+			t.Fatal("message is nil")
+		}
+		message.SetDate()
+		values := message.GetGenHeader(HeaderDate)
+		if len(values) != 1 {
+			t.Fatalf("failed to set SetDate, genHeader value count is: %d, want: %d", len(values), 1)
+		}
+		if !strings.HasSuffix(values[0], "+0900") {
+			t.Errorf("SetDate failed, expected date in location %s, got: %s", location, values[0])
+		}
+	})
 }
 
 func TestMsg_SetDateWithValue(t *testing.T) {
@@ -1753,6 +1886,32 @@ func TestMsg_SetDateWithValue(t *testing.T) {
 				parsed.Format(time.RFC1123Z))
 		}
 	})
+	t.Run("SetDateWithValue with WithDateLocation", func(t *testing.T) {
+		message := NewMsg(WithDateLocation(time.UTC))
+		if message == nil {
+			t.Fatal("message is nil")
+		}
+		date := time.Date(2024, 10, 1, 14, 30, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+		message.SetDateWithValue(date)
+		values := message.GetGenHeader(HeaderDate)
+		want := "Tue, 01 Oct 2024 19:30:00 +0000"
+		if len(values) != 1 || values[0] != want {
+			t.Errorf("SetDateWithValue failed, retrieved date mismatch, got: %v, want: %s", values, want)
+		}
+	})
+	t.Run("SetDateWithValue with nil WithDateLocation", func(t *testing.T) {
+		message := NewMsg(WithDateLocation(nil))
+		if message == nil {
+			t.Fatal("message is nil")
+		}
+		date := time.Date(2024, 10, 1, 14, 30, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+		message.SetDateWithValue(date)
+		values := message.GetGenHeader(HeaderDate)
+		want := "Tue, 01 Oct 2024 14:30:00 -0500"
+		if len(values) != 1 || values[0] != want {
+			t.Errorf("SetDateWithValue failed, retrieved date mismatch, got: %v, want: %s", values, want)
+		}
+	})
 }
 
 func TestMsg_SetImportance(t *testing.T) {
@@ -4284,6 +4443,118 @@ func TestMsg_AddAlternativeWriter(t *testing.T) {
 	})
 }
 
+func TestMsg_ReplaceBodyString(t *testing.T) {
+	t.Run("ReplaceBodyString keeps other parts and files", func(t *testing.T) {
+		message := testMessage(t)
+		message.AddAlternativeString(TypeTextHTML, "<p>Testmail</p>", WithPartContentDescription("html"))
+		if err := message.AttachReader("report.txt", strings.NewReader("report")); err != nil {
+			t.Fatalf("failed to attach file: %s", err)
+		}
+		htmlPart := message.GetParts()[1]
+		message.ReplaceBodyString(TypeTextHTML, "<p>Corrected</p>")
+		parts := message.GetParts()
+		if len(parts) != 2 || parts[1] != htmlPart {
+			t.Fatalf("expected HTML part to be replaced in place, got %d parts", len(parts))
+		}
+		content, err := parts[1].GetContent()
+		if err != nil {
+			t.Fatalf("failed to get part content: %s", err)
+		}
+		if string(content) != "<p>Corrected</p>" || parts[1].GetDescription() != "html" {
+			t.Errorf("unexpected replaced part: %q, %q", content, parts[1].GetDescription())
+		}
+		if content, err = parts[0].GetContent(); err != nil || string(content) != "Testmail" {
+			t.Errorf("expected text part to be kept, got: %q", content)
+		}
+		if len(message.GetAttachments()) != 1 {
+			t.Error("expected attachment to be kept")
+		}
+	})
+	t.Run("ReplaceBodyString without matching part adds it", func(t *testing.T) {
+		message := testMessage(t)
+		message.ReplaceBodyString(TypeTextHTML, "<p>Testmail</p>")
+		parts := message.GetParts()
+		if len(parts) != 2 || parts[1].GetContentType() != TypeTextHTML {
+			t.Errorf("expected HTML part to be added, got %d parts", len(parts))
+		}
+	})
+}
+
+func TestMsg_ReplaceAlternative(t *testing.T) {
+	t.Run("ReplaceAlternative replaces part at index", func(t *testing.T) {
+		message := testMessage(t)
+		message.AddAlternativeString(TypeTextHTML, "<p>Variant A</p>")
+		if err := message.ReplaceAlternative(1, TypeTextHTML, "<p>Variant B</p>",
+			WithPartCharset(CharsetISO88591)); err != nil {
+			t.Fatalf("failed to replace alternative: %s", err)
+		}
+		part := message.GetParts()[1]
+		content, err := part.GetContent()
+		if err != nil {
+			t.Fatalf("failed to get part content: %s", err)
+		}
+		if string(content) != "<p>Variant B</p>" || part.GetCharset() != CharsetISO88591 {
+			t.Errorf("unexpected replaced part: %q, %s", content, part.GetCharset())
+		}
+	})
+	t.Run("ReplaceAlternative fails on invalid index", func(t *testing.T) {
+		message := testMessage(t)
+		message.AddAlternativeString(TypeTextHTML, "<p>Testmail</p>")
+		message.GetParts()[1].Delete()
+		for _, index := range []int{-1, 1, 2} {
+			if err := message.ReplaceAlternative(index, TypeTextHTML, "test"); !errors.Is(err, ErrPartIndexOutOfRange) {
+				t.Errorf("expected error %s for index %d, got: %v", ErrPartIndexOutOfRange, index, err)
+			}
+		}
+	})
+}
+
+func TestMsg_SetAlternativeOrder(t *testing.T) {
+	message := testMessage(t)
+	message.AddAlternativeString(TypeTextHTML, "<p>html</p>")
+	message.AddAlternativeString(ContentType("text/calendar; method=REQUEST"), "BEGIN:VCALENDAR")
+	message.AddAlternativeString(ContentType("text/x-amp-html"), "<html amp4email></html>")
+	writeMessage := func() string {
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		return buffer.String()
+	}
+	tests := []struct {
+		name  string
+		order []ContentType
+		want  string
+	}{
+		{"default order", nil, "text/plain,text/html,text/calendar,text/x-amp-html"},
+		{
+			"explicit order", []ContentType{TypeTextPlain, "text/x-amp-html", TypeTextHTML, TypeTextCalendar},
+			"text/plain,text/x-amp-html,text/html,text/calendar",
+		},
+		{
+			"unlisted parts come first", []ContentType{"TEXT/HTML; charset=UTF-8", TypeTextPlain},
+			"text/calendar,text/x-amp-html,text/html,text/plain",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message.SetAlternativeOrder(tt.order...)
+			output := writeMessage()
+			last := -1
+			for _, mediaType := range strings.Split(tt.want, ",") {
+				index := strings.Index(output, "Content-Type: "+mediaType)
+				if index < 0 || index < last {
+					t.Fatalf("unexpected part order, want: %s, got: %s", tt.want, output)
+				}
+				last = index
+			}
+		})
+	}
+	if parts := message.GetParts(); parts[0].GetContentType() != TypeTextPlain {
+		t.Error("expected the parts of the message to keep their order")
+	}
+}
+
 func TestMsg_AddAlternativeHTMLTemplate(t *testing.T) {
 	tplString := `<p>{{.teststring}}</p>`
 	invalidTplString := `<p>{{call $.invalid .teststring}}</p>`
@@ -5473,6 +5744,42 @@ func TestMsg_Reset(t *testing.T) {
 	}
 }
 
+func TestMsg_SetValue(t *testing.T) {
+	type testKey struct{}
+	t.Run("value is stored and returned", func(t *testing.T) {
+		message := NewMsg()
+		if message.Value(testKey{}) != nil {
+			t.Error("expected value of new message to be nil")
+		}
+		message.SetValue(testKey{}, "selector1")
+		message.SetValue("campaign", 42)
+		if value, ok := message.Value(testKey{}).(string); !ok || value != "selector1" {
+			t.Errorf("expected value to be %q, got: %v", "selector1", message.Value(testKey{}))
+		}
+		if value, ok := message.Value("campaign").(int); !ok || value != 42 {
+			t.Errorf("expected value to be %d, got: %v", 42, message.Value("campaign"))
+		}
+		message.SetValue(testKey{}, nil)
+		if message.Value(testKey{}) != nil {
+			t.Errorf("expected value to be removed, got: %v", message.Value(testKey{}))
+		}
+		message.SetValue("campaign", "campaign-metadata")
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if strings.Contains(buffer.String(), "campaign-metadata") {
+			t.Errorf("expected metadata not to be written to the message, got: %s", buffer.String())
+		}
+	})
+	t.Run("value is passed to middleware", func(t *testing.T) {
+		message := NewMsg(WithMiddleware(valueMiddleware{}))
+		message.SetValue(valueMiddlewareKey{}, "campaign-1")
+		message = message.applyMiddlewares(message)
+		checkGenHeader(t, message, "X-Campaign", "applyMiddleware", 0, 1, "campaign-1")
+	})
+}
+
 func TestMsg_applyMiddlewares(t *testing.T) {
 	t.Run("new message with middleware: uppercase", func(t *testing.T) {
 		tests := []struct {
@@ -6482,6 +6789,25 @@ func (mw encodeMiddleware) Type() MiddlewareType {
 	return "encode"
 }
 
+// valueMiddlewareKey is the key of the metadata value that is read by the valueMiddleware.
+type valueMiddlewareKey struct{}
+
+// valueMiddleware is a middleware type that sets the "X-Campaign" header from the metadata of the message
+type valueMiddleware struct{}
+
+// Handle satisfies the Middleware interface for the valueMiddleware
+func (mw valueMiddleware) Handle(m *Msg) *Msg {
+	if campaign, ok := m.Value(valueMiddlewareKey{}).(string); ok {
+		m.SetGenHeader("X-Campaign", campaign)
+	}
+	return m
+}
+
+// Type satisfies the Middleware interface for the valueMiddleware
+func (mw valueMiddleware) Type() MiddlewareType {
+	return "value"
+}
+
 // failReadWriteSeekCloser is a type that always returns an error. It satisfies the io.Reader, io.Writer
 // io.Closer, io.Seeker, io.WriteSeeker, io.ReadSeeker, io.ReadCloser and io.WriteCloser interfaces
 type failReadWriteSeekCloser struct{}
@@ -0,0 +1,287 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/textproto"
+	"time"
+)
+
+// msgBinaryVersion is the version of the binary representation of a Msg, as written by
+// Msg.MarshalBinary.
+const msgBinaryVersion = 1
+
+type (
+	// msgBinary is the binary representation of a Msg.
+	msgBinary struct {
+		Version            int
+		AddrHeader         map[AddrHeader][]mail.Address
+		AddrGroups         map[AddrHeader][]addrGroupBinary
+		AlternativeOrder   []ContentType
+		Attachments        []fileBinary
+		AutoFileEncoding   bool
+		Boundary           string
+		Charset            Charset
+		Embeds             []fileBinary
+		Encoding           Encoding
+		GenHeader          map[Header][]string
+		HeaderFolding      FoldingPolicy
+		MailFromParams     []string
+		MaxDepth           int
+		MaxParts           int
+		MessageIDTag       string
+		MIMEVersion        MIMEVersion
+		NoDefaultUserAgent bool
+		Parts              []partBinary
+		PGPType            PGPType
+		PreformHeader      map[Header]string
+		RcptParams         map[string][]string
+		ReportType         string
+		RequireTLS         bool
+	}
+
+	// addrGroupBinary is the binary representation of an addrGroup. The members are stored as indices
+	// of the addresses of the address header, since they are shared with it.
+	addrGroupBinary struct {
+		Name    string
+		Members []int
+	}
+
+	// partBinary is the binary representation of a Part, with its content materialized.
+	partBinary struct {
+		Charset     Charset
+		Content     []byte
+		ContentType ContentType
+		Description string
+		Encoding    Encoding
+		IsDeleted   bool
+	}
+
+	// fileBinary is the binary representation of a File, with its content materialized.
+	fileBinary struct {
+		Content     []byte
+		ContentType ContentType
+		Desc        string
+		Disposition Disposition
+		Enc         Encoding
+		Header      textproto.MIMEHeader
+		ModDate     time.Time
+		Name        string
+		Size        int64
+	}
+)
+
+// MarshalBinary satisfies the encoding.BinaryMarshaler interface for the Msg type.
+//
+// It returns a compact representation of the Msg, like for the persistence in a job queue, from
+// which the Msg can be restored with Msg.UnmarshalBinary. The content of the parts, attachments and
+// embeds is read at the time of the call and stored with their encodings and charsets, so that the
+// restored Msg does not depend on the sources of the content. Files that are streamed with
+// AttachStream are consumed by the call. Middlewares, address validators, the subject template, the
+// metadata set with Msg.SetValue, the BodyCache, the location of the date and the delivery state of
+// the Msg are not part of the representation.
+//
+// Returns:
+//   - The binary representation of the Msg.
+//   - An error if the content of a part or file cannot be read or the Msg cannot be encoded;
+//     otherwise, returns nil.
+func (m *Msg) MarshalBinary() ([]byte, error) {
+	binary := msgBinary{
+		Version:            msgBinaryVersion,
+		AddrHeader:         make(map[AddrHeader][]mail.Address, len(m.addrHeader)),
+		AlternativeOrder:   m.alternativeOrder,
+		AutoFileEncoding:   m.autoFileEncoding,
+		Boundary:           m.boundary,
+		Charset:            m.charset,
+		Encoding:           m.encoding,
+		GenHeader:          m.genHeader,
+		HeaderFolding:      m.headerFolding,
+		MailFromParams:     m.mailFromParams,
+		MaxDepth:           m.maxDepth,
+		MaxParts:           m.maxParts,
+		MessageIDTag:       m.messageIDTag,
+		MIMEVersion:        m.mimever,
+		NoDefaultUserAgent: m.noDefaultUserAgent,
+		PGPType:            m.pgptype,
+		PreformHeader:      m.preformHeader,
+		RcptParams:         m.rcptParams,
+		ReportType:         m.reportType,
+		RequireTLS:         m.requireTLS,
+	}
+	for header, addresses := range m.addrHeader {
+		for _, address := range addresses {
+			binary.AddrHeader[header] = append(binary.AddrHeader[header], *address)
+		}
+	}
+	if m.addrGroups != nil {
+		binary.AddrGroups = make(map[AddrHeader][]addrGroupBinary, len(m.addrGroups))
+		for header, groups := range m.addrGroups {
+			for _, group := range groups {
+				groupBinary := addrGroupBinary{Name: group.name}
+				for _, member := range group.members {
+					for i, address := range m.addrHeader[header] {
+						if address == member {
+							groupBinary.Members = append(groupBinary.Members, i)
+							break
+						}
+					}
+				}
+				binary.AddrGroups[header] = append(binary.AddrGroups[header], groupBinary)
+			}
+		}
+	}
+	for _, part := range m.parts {
+		content, err := part.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read content of part: %w", err)
+		}
+		binary.Parts = append(binary.Parts, partBinary{
+			Charset: part.charset, Content: content, ContentType: part.contentType,
+			Description: part.description, Encoding: part.encoding, IsDeleted: part.isDeleted,
+		})
+	}
+	var err error
+	if binary.Attachments, err = filesToBinary(m.attachments); err != nil {
+		return nil, err
+	}
+	if binary.Embeds, err = filesToBinary(m.embeds); err != nil {
+		return nil, err
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	if err = gob.NewEncoder(buffer).Encode(binary); err != nil {
+		return nil, fmt.Errorf("failed to encode message: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalBinary satisfies the encoding.BinaryUnmarshaler interface for the Msg type.
+//
+// It replaces the Msg with the Msg restored from the representation returned by Msg.MarshalBinary.
+// The state of the Msg that is not part of the representation is reset to the defaults of NewMsg.
+//
+// Parameters:
+//   - data: The binary representation of the Msg.
+//
+// Returns:
+//   - An error if the data cannot be decoded or has an unsupported version; otherwise, returns nil.
+func (m *Msg) UnmarshalBinary(data []byte) error {
+	var binary msgBinary
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&binary); err != nil {
+		return fmt.Errorf("failed to decode message: %w", err)
+	}
+	if binary.Version != msgBinaryVersion {
+		return fmt.Errorf("unsupported version of binary message: %d", binary.Version)
+	}
+
+	msg := NewMsg(WithCharset(binary.Charset), WithEncoding(binary.Encoding), WithMIMEVersion(binary.MIMEVersion),
+		WithBoundary(binary.Boundary), WithPGPType(binary.PGPType), WithHeaderFolding(binary.HeaderFolding),
+		WithStructureLimits(binary.MaxParts, binary.MaxDepth), WithMessageIDTag(binary.MessageIDTag))
+	msg.alternativeOrder = binary.AlternativeOrder
+	msg.autoFileEncoding = binary.AutoFileEncoding
+	msg.mailFromParams = binary.MailFromParams
+	msg.noDefaultUserAgent = binary.NoDefaultUserAgent
+	msg.rcptParams = binary.RcptParams
+	msg.reportType = binary.ReportType
+	msg.requireTLS = binary.RequireTLS
+	if binary.GenHeader != nil {
+		msg.genHeader = binary.GenHeader
+	}
+	if binary.PreformHeader != nil {
+		msg.preformHeader = binary.PreformHeader
+	}
+	for header, addresses := range binary.AddrHeader {
+		for i := range addresses {
+			address := addresses[i]
+			msg.addrHeader[header] = append(msg.addrHeader[header], &address)
+		}
+	}
+	if binary.AddrGroups != nil {
+		msg.addrGroups = make(map[AddrHeader][]*addrGroup, len(binary.AddrGroups))
+		for header, groups := range binary.AddrGroups {
+			for _, groupBinary := range groups {
+				group := &addrGroup{name: groupBinary.Name}
+				for _, index := range groupBinary.Members {
+					if index < 0 || index >= len(msg.addrHeader[header]) {
+						return fmt.Errorf("invalid member of address group %q", groupBinary.Name)
+					}
+					group.members = append(group.members, msg.addrHeader[header][index])
+				}
+				msg.addrGroups[header] = append(msg.addrGroups[header], group)
+			}
+		}
+	}
+	for _, partBinary := range binary.Parts {
+		part := &Part{
+			contentType: partBinary.ContentType, charset: partBinary.Charset,
+			description: partBinary.Description, encoding: partBinary.Encoding, isDeleted: partBinary.IsDeleted,
+		}
+		part.SetContent(string(partBinary.Content))
+		msg.parts = append(msg.parts, part)
+	}
+	msg.attachments = filesFromBinary(binary.Attachments)
+	msg.embeds = filesFromBinary(binary.Embeds)
+
+	*m = *msg
+	return nil
+}
+
+// filesToBinary returns the binary representation of the given files, with their content read
+// from their writers.
+//
+// Parameters:
+//   - files: The files to convert.
+//
+// Returns:
+//   - The binary representations of the files.
+//   - An error if the content of a file cannot be read; otherwise, returns nil.
+func filesToBinary(files []*File) ([]fileBinary, error) {
+	var binaries []fileBinary
+	for _, file := range files {
+		buffer := bytes.NewBuffer(nil)
+		if _, err := file.Writer(buffer); err != nil {
+			return nil, fmt.Errorf("failed to read content of file %q: %w", file.Name, err)
+		}
+		binaries = append(binaries, fileBinary{
+			Content: buffer.Bytes(), ContentType: file.ContentType, Desc: file.Desc,
+			Disposition: file.Disposition, Enc: file.Enc, Header: file.Header, ModDate: file.ModDate,
+			Name: file.Name, Size: file.Size,
+		})
+	}
+	return binaries, nil
+}
+
+// filesFromBinary returns the files for the given binary representations. The content of the files
+// is written from memory.
+//
+// Parameters:
+//   - binaries: The binary representations of the files.
+//
+// Returns:
+//   - The restored files.
+func filesFromBinary(binaries []fileBinary) []*File {
+	var files []*File
+	for _, binary := range binaries {
+		content := binary.Content
+		file := &File{
+			ContentType: binary.ContentType, Desc: binary.Desc, Disposition: binary.Disposition,
+			Enc: binary.Enc, Header: binary.Header, ModDate: binary.ModDate, Name: binary.Name,
+			Size: binary.Size,
+			Writer: func(writer io.Writer) (int64, error) {
+				return io.Copy(writer, bytes.NewReader(content))
+			},
+		}
+		if file.Header == nil {
+			file.Header = make(textproto.MIMEHeader)
+		}
+		files = append(files, file)
+	}
+	return files
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"encoding"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Make sure the Msg satisfies the encoding.BinaryMarshaler and encoding.BinaryUnmarshaler interfaces
+var (
+	_ encoding.BinaryMarshaler   = (*Msg)(nil)
+	_ encoding.BinaryUnmarshaler = (*Msg)(nil)
+)
+
+func TestMsg_MarshalBinary(t *testing.T) {
+	t.Run("round-trip preserves the message", func(t *testing.T) {
+		message := NewMsg(WithBoundary("testboundary"), WithCharset(CharsetISO88591))
+		if err := message.From(TestSenderValid); err != nil {
+			t.Fatalf("failed to set FROM address: %s", err)
+		}
+		if err := message.To(TestRcptValid); err != nil {
+			t.Fatalf("failed to set TO address: %s", err)
+		}
+		if err := message.ToGroup("Team", "team@domain.tld"); err != nil {
+			t.Fatalf("failed to set group address: %s", err)
+		}
+		if err := message.AddRcptParam(TestRcptValid, "X-TRACKING", "binary"); err != nil {
+			t.Fatalf("failed to add recipient parameter: %s", err)
+		}
+		message.Subject("Testmail")
+		message.RequireTLS(true)
+		message.SetMessageIDWithValue("binary@domain.tld")
+		message.SetDateWithValue(time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC))
+		message.SetBodyString(TypeTextPlain, "Grüße", WithPartCharset(CharsetUTF8), WithPartEncoding(EncodingB64))
+		message.AddAlternativeString(TypeTextHTML, "<p>Testmail</p>", WithPartContentDescription("HTML"))
+		message.AttachFile("testdata/attachment.txt", WithFileDescription("Attachment"))
+		if err := message.AttachReader("report.csv", strings.NewReader("id;name\r\n1;Toni\r\n"),
+			WithFileContentType("text/csv"), WithFileEncoding(NoEncoding)); err != nil {
+			t.Fatalf("failed to attach file: %s", err)
+		}
+		message.EmbedFile("testdata/embed.txt")
+		message.SetValue("campaign", "not persisted")
+
+		data, err := message.MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to marshal message: %s", err)
+		}
+		restored := &Msg{}
+		if err = restored.UnmarshalBinary(data); err != nil {
+			t.Fatalf("failed to unmarshal message: %s", err)
+		}
+
+		parts := restored.GetParts()
+		if len(parts) != 2 {
+			t.Fatalf("expected 2 parts, got: %d", len(parts))
+		}
+		if parts[0].GetCharset() != CharsetUTF8 || parts[0].GetEncoding() != EncodingB64 {
+			t.Errorf("expected charset and encoding of part to be preserved, got: %s, %s",
+				parts[0].GetCharset(), parts[0].GetEncoding())
+		}
+		if content, _ := parts[0].GetContent(); string(content) != "Grüße" {
+			t.Errorf("expected content of part to be preserved, got: %s", content)
+		}
+		if parts[1].GetDescription() != "HTML" {
+			t.Errorf("expected description of part to be preserved, got: %s", parts[1].GetDescription())
+		}
+		if restored.addrGroups[HeaderTo][0].members[0] != restored.GetAddrHeader(HeaderTo)[1] {
+			t.Error("expected group member to be the address of the address header")
+		}
+		if !restored.requireTLS || restored.charset != CharsetISO88591 {
+			t.Error("expected settings of message to be preserved")
+		}
+		if params := restored.rcptParamsFor(TestRcptValid); len(params) != 1 || params[0] != "X-TRACKING=binary" {
+			t.Errorf("expected recipient parameters to be preserved, got: %v", params)
+		}
+		if restored.Value("campaign") != nil {
+			t.Error("expected metadata not to be preserved")
+		}
+
+		want := bytes.NewBuffer(nil)
+		if _, err = message.WriteTo(want); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		got := bytes.NewBuffer(nil)
+		if _, err = restored.WriteTo(got); err != nil {
+			t.Fatalf("failed to write restored message: %s", err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("expected restored message to be written like the original\nwant: %s\ngot: %s",
+				want.String(), got.String())
+		}
+		if !strings.Contains(got.String(), "id;name\r\n1;Toni") {
+			t.Errorf("expected attachment to be written unencoded, got: %s", got.String())
+		}
+	})
+	t.Run("marshal fails on unreadable file", func(t *testing.T) {
+		message := testMessage(t)
+		message.AttachReadSeeker("fail.txt", &failReadWriteSeekCloser{})
+		if _, err := message.MarshalBinary(); err == nil {
+			t.Error("expected marshaling of message with unreadable file to fail")
+		}
+	})
+	t.Run("unmarshal fails on invalid data", func(t *testing.T) {
+		if err := (&Msg{}).UnmarshalBinary([]byte("invalid")); err == nil {
+			t.Error("expected unmarshaling of invalid data to fail")
+		}
+	})
+}
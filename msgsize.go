@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"strconv"
+	"strings"
+)
+
+// ErrMessageSizeExceeded is returned if the estimated size of a Msg exceeds the maximum message size.
+var ErrMessageSizeExceeded = errors.New("message size exceeds the maximum message size")
+
+// sizeCounter is an io.Writer that discards all data and only counts the number of written bytes.
+type sizeCounter struct {
+	size int64
+}
+
+// Write satisfies the io.Writer interface for the sizeCounter type.
+func (c *sizeCounter) Write(p []byte) (int, error) {
+	c.size += int64(len(p))
+	return len(p), nil
+}
+
+// EstimatedSize returns the size in bytes of the Msg as it would be sent to the SMTP server.
+//
+// The headers and the MIME structure of the Msg are rendered with all middlewares applied, but the
+// content of the parts and attachments is not encoded. The size of Base64 encoded content, i. e. of
+// attachments, is computed from the length of the content, and only quoted-printable content is
+// passed through an encoder that counts the output. Nothing is buffered, so that large attachments
+// do not have to be held in memory. The size does not include the dot-stuffing of the SMTP DATA
+// command and is therefore an estimate. Streamed attachments added with Msg.AttachStream are not
+// consumed; their announced size is used instead.
+//
+// Returns:
+//   - The estimated size of the Msg in bytes. If the Msg cannot be rendered, the number of bytes up
+//     to the failure is returned.
+func (m *Msg) EstimatedSize() int64 {
+	estimate := m.estimationMsg()
+	mw := &msgWriter{
+		writer: io.Discard, charset: estimate.charset, encoder: estimate.encoder, estimate: true,
+		folding: estimate.headerFolding, autoFileEncoding: estimate.autoFileEncoding,
+	}
+	mw.writeMsg(estimate.applyMiddlewares(estimate))
+	return mw.bytesWritten
+}
+
+// estimateBody adds the size of the given body content in the given encoding to the bytes written by
+// the msgWriter, like writeBody would write it, without writing the content.
+//
+// Parameters:
+//   - writeFunc: A function that writes the body content to the given io.Writer.
+//   - encoding: The encoding type of the content.
+func (mw *msgWriter) estimateBody(writeFunc func(io.Writer) (int64, error), encoding Encoding) {
+	counter := &sizeCounter{}
+	var err error
+	switch encoding {
+	case EncodingB64:
+		_, err = writeFunc(counter)
+		counter.size = base64EncodedSize(counter.size)
+	case NoEncoding, EncodingBinary:
+		_, err = writeFunc(counter)
+	default:
+		encoder := quotedprintable.NewWriter(counter)
+		_, err = writeFunc(encoder)
+		if closeErr := encoder.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	mw.bytesWritten += counter.size
+	if err != nil && mw.err == nil {
+		mw.err = fmt.Errorf("bodyWriter function: %w", err)
+	}
+}
+
+// base64EncodedSize returns the size of content of the given size after Base64 encoding, including
+// the line breaks inserted by the Base64LineBreaker.
+//
+// Parameters:
+//   - size: The size of the content in bytes.
+//
+// Returns:
+//   - The size of the encoded content in bytes.
+func base64EncodedSize(size int64) int64 {
+	encoded := (size + 2) / 3 * 4
+	lines := (encoded + MaxBodyLength - 1) / MaxBodyLength
+	return encoded + lines*int64(len(SingleNewLine))
+}
+
+// WithMaxMessageSize sets the maximum size in bytes of a Msg that is sent by the Client.
+//
+// Before a Msg is sent, its EstimatedSize is compared with the given size and, if the SMTP server
+// advertises the SIZE extension, with the size limit of the server, whichever is smaller. A Msg
+// that exceeds the limit is rejected client-side with a SendError of reason ErrMessageTooLarge,
+// without transmitting it to the server.
+//
+// The client-side check is opt-in, since it requires the content of each Msg to be read once more
+// for its EstimatedSize. Without this option, the size limit advertised by the server is not checked
+// by the Client, and an oversized Msg is rejected by the server instead. To only enforce the limit of
+// the server, a size of math.MaxInt64 can be used.
+//
+// Parameters:
+//   - size: The maximum message size in bytes. Must be greater than zero.
+//
+// Returns:
+//   - An Option function that sets the maximum message size for the Client.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc1870
+func WithMaxMessageSize(size int64) Option {
+	return func(c *Client) error {
+		if size <= 0 {
+			return ErrInvalidMaxMessageSize
+		}
+		c.maxMessageSize = size
+		return nil
+	}
+}
+
+// checkMessageSize checks the given estimated size of a Msg against the maximum message size of
+// the Client and the size limit advertised by the SMTP server. Both limits are only checked, if a
+// maximum message size is set with WithMaxMessageSize.
+//
+// Parameters:
+//   - size: The estimated size of the Msg in bytes.
+//
+// Returns:
+//   - An error wrapping ErrMessageSizeExceeded if the Msg is too large; otherwise, returns nil.
+func (c *Client) checkMessageSize(size int64) error {
+	if c.maxMessageSize <= 0 {
+		return nil
+	}
+	limit := c.maxMessageSize
+	if ok, param := c.smtpClient.Extension("SIZE"); ok {
+		serverLimit, err := strconv.ParseInt(strings.TrimSpace(param), 10, 64)
+		if err == nil && serverLimit > 0 && serverLimit < limit {
+			limit = serverLimit
+		}
+	}
+	if size > limit {
+		return fmt.Errorf("%w: %d bytes, maximum is %d bytes", ErrMessageSizeExceeded, size, limit)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMsg_EstimatedSize(t *testing.T) {
+	t.Run("size matches the rendered message", func(t *testing.T) {
+		message := testMessage(t)
+		message.AttachReader("data.bin", bytes.NewReader(bytes.Repeat([]byte{0xff}, 3000)))
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if got := message.EstimatedSize(); got != int64(buffer.Len()) {
+			t.Errorf("unexpected estimated size, want: %d, got: %d", buffer.Len(), got)
+		}
+	})
+	t.Run("size matches the rendered message for all encodings", func(t *testing.T) {
+		for _, size := range []int{0, 1, 56, 57, 58, 114, 3000} {
+			for _, encoding := range []Encoding{EncodingQP, EncodingB64, NoEncoding} {
+				message := testMessage(t, WithEncoding(encoding))
+				message.SetBodyString(TypeTextPlain, strings.Repeat("Grüße = ", size/8+1))
+				message.AddAlternativeString(TypeTextHTML, "<p>"+strings.Repeat("x", size)+"</p>")
+				message.AttachReader("data.bin", bytes.NewReader(bytes.Repeat([]byte{0xff}, size)))
+				message.EmbedReader("image.png", bytes.NewReader(bytes.Repeat([]byte{0x01}, size)))
+				buffer := bytes.NewBuffer(nil)
+				if _, err := message.WriteTo(buffer); err != nil {
+					t.Fatalf("failed to write message: %s", err)
+				}
+				if got := message.EstimatedSize(); got != int64(buffer.Len()) {
+					t.Errorf("unexpected estimated size for %d bytes with encoding %s, want: %d, got: %d",
+						size, encoding, buffer.Len(), got)
+				}
+			}
+		}
+	})
+	t.Run("base64 content is counted without encoding", func(t *testing.T) {
+		message := testMessage(t)
+		message.AttachReader("data.bin", bytes.NewReader(bytes.Repeat([]byte{0xff}, 3000)))
+		attachment := message.GetAttachments()[0]
+		writeFunc := attachment.Writer
+		var writers []io.Writer
+		attachment.Writer = func(writer io.Writer) (int64, error) {
+			writers = append(writers, writer)
+			return writeFunc(writer)
+		}
+		message.EstimatedSize()
+		if len(writers) != 1 {
+			t.Fatalf("expected attachment content to be written once, got: %d", len(writers))
+		}
+		if _, ok := writers[0].(*sizeCounter); !ok {
+			t.Errorf("expected attachment content to be counted, got writer: %T", writers[0])
+		}
+	})
+	t.Run("size includes base64 overhead", func(t *testing.T) {
+		message := testMessage(t)
+		baseSize := message.EstimatedSize()
+		message.AttachReader("data.bin", bytes.NewReader(bytes.Repeat([]byte{0xff}, 3000)))
+		if got := message.EstimatedSize() - baseSize; got < 4000 {
+			t.Errorf("expected estimated size to grow by at least 4000 bytes, got: %d", got)
+		}
+	})
+}
+
+func TestWithMaxMessageSize(t *testing.T) {
+	t.Run("invalid size fails", func(t *testing.T) {
+		_, err := NewClient(DefaultHost, WithMaxMessageSize(0))
+		if !errors.Is(err, ErrInvalidMaxMessageSize) {
+			t.Errorf("expected error: %s, got: %s", ErrInvalidMaxMessageSize, err)
+		}
+	})
+	tests := []struct {
+		name       string
+		featureSet string
+		maxSize    int64
+		wantErr    bool
+	}{
+		{"message within limits is sent", "250-SIZE 10240000\r\n250 8BITMIME", 10240, false},
+		{"message exceeding client limit fails", "250 8BITMIME", 100, true},
+		{"message exceeding server limit fails", "250-SIZE 100\r\n250 8BITMIME", 10240, true},
+		{"only server limit is enforced", "250-SIZE 100\r\n250 8BITMIME", math.MaxInt64, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			PortAdder.Add(1)
+			serverPort := int(TestServerPortBase + PortAdder.Load())
+			go func() {
+				if err := simpleSMTPServer(ctx, t, &serverProps{
+					FeatureSet: tt.featureSet,
+					ListenPort: serverPort,
+				}); err != nil {
+					t.Errorf("failed to start test server: %s", err)
+					return
+				}
+			}()
+			time.Sleep(time.Millisecond * 30)
+			ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+			t.Cleanup(cancelDial)
+
+			client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS),
+				WithMaxMessageSize(tt.maxSize))
+			if err != nil {
+				t.Fatalf("failed to create new client: %s", err)
+			}
+			if err = client.DialWithContext(ctxDial); err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					t.Skip("failed to connect to the test server due to timeout")
+				}
+				t.Fatalf("failed to connect to the test server: %s", err)
+			}
+			t.Cleanup(func() {
+				if err := client.Close(); err != nil {
+					t.Errorf("failed to close client: %s", err)
+				}
+			})
+			err = client.Send(testMessage(t))
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("failed to send message: %s", err)
+				}
+				return
+			}
+			var sendErr *SendError
+			if !errors.As(err, &sendErr) || sendErr.Reason != ErrMessageTooLarge {
+				t.Fatalf("expected SendError with reason %s, got: %s", ErrMessageTooLarge, err)
+			}
+			if !errors.Is(err, ErrMessageSizeExceeded) || !strings.Contains(err.Error(), "maximum is 100 bytes") {
+				t.Errorf("expected error to wrap %s with the limit, got: %s", ErrMessageSizeExceeded, err)
+			}
+		})
+	}
+}
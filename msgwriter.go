@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 )
 
 const (
@@ -44,6 +45,11 @@ const (
 	//
 	// This constant can be used by the msgWriter to indicate a new segment of the mail when writing mail content.
 	DoubleNewLine = "\r\n\r\n"
+
+	// maxQPEscapeRatio is the ratio of bytes that need to be escaped, above which the content of a
+	// text-like File is Base64 encoded instead of quoted-printable encoded. Each escaped byte takes
+	// three bytes in quoted-printable, so that the encoded content would not be smaller than Base64.
+	maxQPEscapeRatio = 0.15
 )
 
 // msgWriter handles the I/O operations for writing to the io.WriteCloser of the SMTP client.
@@ -52,14 +58,18 @@ const (
 // current multipart section. It also handles encoding, error tracking, and managing multipart and part
 // writers for constructing the email message body.
 type msgWriter struct {
-	bytesWritten    int64
-	charset         Charset
-	depth           int8
-	encoder         mime.WordEncoder
-	err             error
-	multiPartWriter [3]*multipart.Writer
-	partWriter      io.Writer
-	writer          io.Writer
+	autoFileEncoding bool
+	bodyCache        *BodyCache
+	bytesWritten     int64
+	charset          Charset
+	depth            int8
+	encoder          mime.WordEncoder
+	err              error
+	estimate         bool
+	folding          FoldingPolicy
+	multiPartWriter  [3]*multipart.Writer
+	partWriter       io.Writer
+	writer           io.Writer
 }
 
 // Write implements the io.Writer interface for msgWriter.
@@ -99,6 +109,10 @@ func (mw *msgWriter) Write(payload []byte) (int, error) {
 //   - https://datatracker.ietf.org/doc/html/rfc2045 (Multipurpose Internet Mail Extensions - MIME)
 //   - https://datatracker.ietf.org/doc/html/rfc5322 (Internet Message Format)
 func (mw *msgWriter) writeMsg(msg *Msg) {
+	if err := msg.checkStructureLimits(); err != nil {
+		mw.err = err
+		return
+	}
 	msg.addDefaultHeader()
 	msg.checkUserAgent()
 	mw.writeGenHeader(msg)
@@ -119,13 +133,7 @@ func (mw *msgWriter) writeMsg(msg *Msg) {
 
 	// Set the rest of the address headers
 	for _, to := range []AddrHeader{HeaderTo, HeaderCc} {
-		if addresses, ok := msg.addrHeader[to]; ok {
-			var val []string
-			for _, addr := range addresses {
-				val = append(val, addr.String())
-			}
-			mw.writeHeader(Header(to), val...)
-		}
+		mw.writeHeader(Header(to), msg.addrHeaderValues(to)...)
 	}
 
 	if msg.hasMixed() {
@@ -140,6 +148,10 @@ func (mw *msgWriter) writeMsg(msg *Msg) {
 		mw.startMP(MIMEAlternative, msg.boundary)
 		mw.writeString(DoubleNewLine)
 	}
+	if msg.hasReport() {
+		mw.startMP(MIMEType(fmt.Sprintf("report; report-type=%s", msg.reportType)), msg.boundary)
+		mw.writeString(DoubleNewLine)
+	}
 	if msg.hasPGPType() {
 		switch msg.pgptype {
 		case PGPEncrypt:
@@ -153,13 +165,13 @@ func (mw *msgWriter) writeMsg(msg *Msg) {
 		mw.writeString(DoubleNewLine)
 	}
 
-	for _, part := range msg.parts {
+	for _, part := range msg.orderedParts() {
 		if !part.isDeleted {
 			mw.writePart(part, msg.charset)
 		}
 	}
 
-	if msg.hasAlt() {
+	if msg.hasAlt() || msg.hasReport() {
 		mw.stopMP()
 	}
 
@@ -276,9 +288,15 @@ func (mw *msgWriter) addFiles(files []*File, isAttachment bool) {
 				mw.encoder.Encode(mw.charset.String(), file.Name)))
 		}
 
-		if _, ok := file.getHeader(HeaderContentTransferEnc); !ok {
-			if file.Enc != "" {
+		writeFunc := file.Writer
+		if value, ok := file.getHeader(HeaderContentTransferEnc); ok {
+			encoding = Encoding(strings.ToLower(value))
+		} else {
+			switch {
+			case file.Enc != "":
 				encoding = file.Enc
+			case mw.autoFileEncoding && isTextMediaType(policyFileContentType(file)):
+				writeFunc, encoding = textFileEncoding(file.Writer)
 			}
 			file.setHeader(HeaderContentTransferEnc, string(encoding))
 		}
@@ -290,12 +308,15 @@ func (mw *msgWriter) addFiles(files []*File, isAttachment bool) {
 		}
 
 		if _, ok := file.getHeader(HeaderContentDisposition); !ok {
-			disposition := "inline"
-			if isAttachment {
-				disposition = "attachment"
+			dispositionHeader := fmt.Sprintf(`%s; filename="%s"`, file.disposition(isAttachment),
+				mw.encoder.Encode(mw.charset.String(), file.Name))
+			if file.Size > 0 {
+				dispositionHeader += fmt.Sprintf("; size=%d", file.Size)
+			}
+			if !file.ModDate.IsZero() {
+				dispositionHeader += fmt.Sprintf(`; modification-date="%s"`, file.ModDate.Format(time.RFC1123Z))
 			}
-			file.setHeader(HeaderContentDisposition, fmt.Sprintf(`%s; filename="%s"`,
-				disposition, mw.encoder.Encode(mw.charset.String(), file.Name)))
+			file.setHeader(HeaderContentDisposition, dispositionHeader)
 		}
 
 		if !isAttachment {
@@ -314,11 +335,69 @@ func (mw *msgWriter) addFiles(files []*File, isAttachment bool) {
 		}
 
 		if mw.err == nil {
-			mw.writeBody(file.Writer, encoding)
+			mw.writeBody(writeFunc, encoding)
 		}
 	}
 }
 
+// textFileEncoding reads the content of a text-like File and chooses its encoding.
+//
+// Quoted-printable is chosen if the content does not hold NUL bytes and less than maxQPEscapeRatio
+// of its bytes need to be escaped, Base64 otherwise. The content is only read once, so the returned
+// write function writes the buffered content, or returns the error of reading the File.
+//
+// Parameters:
+//   - writeFunc: The write function of the File.
+//
+// Returns:
+//   - A write function that writes the buffered content of the File.
+//   - The Encoding for the content of the File.
+func textFileEncoding(writeFunc func(io.Writer) (int64, error)) (func(io.Writer) (int64, error), Encoding) {
+	buffer := bytes.NewBuffer(nil)
+	if _, err := writeFunc(buffer); err != nil {
+		return func(io.Writer) (int64, error) { return 0, err }, EncodingB64
+	}
+	content := buffer.Bytes()
+	bufferedFunc := func(writer io.Writer) (int64, error) {
+		n, err := writer.Write(content)
+		return int64(n), err
+	}
+
+	escaped := 0
+	for _, char := range content {
+		switch {
+		case char == 0:
+			return bufferedFunc, EncodingB64
+		case char == '\t', char == '\r', char == '\n':
+		case char < ' ', char > '~', char == '=':
+			escaped++
+		}
+	}
+	if float64(escaped) >= float64(len(content))*maxQPEscapeRatio {
+		return bufferedFunc, EncodingB64
+	}
+	return bufferedFunc, EncodingQP
+}
+
+// isTextMediaType reports whether the given media type holds text, and therefore qualifies for the
+// quoted-printable encoding.
+//
+// Parameters:
+//   - mediaType: The media type without parameters, i. e. "text/csv".
+//
+// Returns:
+//   - true if the media type is "text/*", a structured text type like "application/json" or
+//     "application/xml", or has a "+json" or "+xml" suffix; false otherwise.
+func isTextMediaType(mediaType string) bool {
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/x-ndjson",
+		"application/yaml", "application/x-yaml", "application/x-sh", "application/sql":
+		return true
+	}
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+json") ||
+		strings.HasSuffix(mediaType, "+xml")
+}
+
 // newPart creates a new MIME multipart io.Writer and sets the partWriter to it.
 //
 // This function creates a new MIME part using the provided header information and assigns it
@@ -348,6 +427,10 @@ func (mw *msgWriter) writePart(part *Part, charset Charset) {
 		partCharset = charset
 	}
 	contentType := fmt.Sprintf("%s; charset=%s", part.contentType, partCharset)
+	if strings.HasPrefix(strings.ToLower(string(part.contentType)), "message/") {
+		// The message media types do not have a charset parameter
+		contentType = string(part.contentType)
+	}
 	contentTransferEnc := part.encoding.String()
 	if mw.depth == 0 {
 		mw.writeHeader(HeaderContentType, contentType)
@@ -363,6 +446,10 @@ func (mw *msgWriter) writePart(part *Part, charset Charset) {
 		mimeHeader.Add(string(HeaderContentTransferEnc), contentTransferEnc)
 		mw.newPart(mimeHeader)
 	}
+	if mw.bodyCache != nil && (part.encoding == EncodingQP || part.encoding == EncodingB64) {
+		mw.writeCachedBody(part.writeFunc, part.encoding)
+		return
+	}
 	mw.writeBody(part.writeFunc, part.encoding)
 }
 
@@ -385,63 +472,41 @@ func (mw *msgWriter) writeString(s string) {
 
 // writeHeader writes a header into the msgWriter's io.Writer.
 //
-// This function writes a header key and its associated values to the msgWriter. It ensures
-// proper formatting of long headers by inserting line breaks as needed. The header values
-// are joined and split into words to ensure compliance with the maximum header length
-// (MaxHeaderLength). After processing the header, it is written to the underlying writer.
+// This function writes a header key and its associated values to the msgWriter. Long headers
+// are folded according to the FoldingPolicy of the msgWriter, by default so that they comply
+// with the maximum header length (MaxHeaderLength). After processing the header, it is written
+// to the underlying writer.
 //
 // Parameters:
 //   - key: The Header key to be written.
 //   - values: A variadic parameter representing the values associated with the header.
 func (mw *msgWriter) writeHeader(key Header, values ...string) {
-	buffer := strings.Builder{}
-	charLength := MaxHeaderLength - 2
-	buffer.WriteString(string(key))
-	charLength -= len(key)
 	if len(values) == 0 {
-		buffer.WriteString(":\r\n")
 		return
 	}
-	buffer.WriteString(": ")
-	charLength -= 2
-
-	fullValueStr := strings.Join(values, ", ")
-	words := strings.Split(fullValueStr, " ")
-	for i, val := range words {
-		if charLength-len(val) <= 1 {
-			buffer.WriteString(fmt.Sprintf("%s ", SingleNewLine))
-			charLength = MaxHeaderLength - 3
-		}
-		buffer.WriteString(val)
-		if i < len(words)-1 {
-			buffer.WriteString(" ")
-			charLength -= 1
-		}
-		charLength -= len(val)
-	}
-
-	bufferString := buffer.String()
-	bufferString = strings.ReplaceAll(bufferString, fmt.Sprintf(" %s", SingleNewLine),
-		SingleNewLine)
-	mw.writeString(bufferString)
+	mw.writeString(FoldHeader(key, mw.folding, values...))
 	mw.writeString("\r\n")
 }
 
 // writeBody writes an io.Reader into an io.Writer using the provided Encoding.
 //
 // This function writes data from an io.Reader to the underlying writer using a specified
-// encoding (quoted-printable, base64, or no encoding). It handles encoding of the content
-// and manages writing the encoded data to the appropriate writer, depending on the depth
-// (whether the data is part of a multipart structure or not). It also tracks the number
-// of bytes written and manages any errors encountered during the process.
+// encoding (quoted-printable, base64, or no encoding). The content is streamed from the
+// write function through the encoder directly into the appropriate writer, depending on the
+// depth (whether the data is part of a multipart structure or not), so that large bodies and
+// attachments are not copied into memory. It also tracks the number of bytes written and
+// manages any errors encountered during the process.
 //
 // Parameters:
 //   - writeFunc: A function that writes the body content to the given io.Writer.
 //   - encoding: The encoding type to use when writing the content (e.g., base64, quoted-printable).
 func (mw *msgWriter) writeBody(writeFunc func(io.Writer) (int64, error), encoding Encoding) {
+	if mw.estimate {
+		mw.estimateBody(writeFunc, encoding)
+		return
+	}
 	var writer io.Writer
 	var encodedWriter io.WriteCloser
-	var n int64
 	var err error
 	if mw.depth == 0 {
 		writer = mw.writer
@@ -449,52 +514,88 @@ func (mw *msgWriter) writeBody(writeFunc func(io.Writer) (int64, error), encodin
 	if mw.depth > 0 {
 		writer = mw.partWriter
 	}
-	writeBuffer := bytes.Buffer{}
+	output := &bodyWriter{writer: writer}
 	lineBreaker := Base64LineBreaker{}
-	lineBreaker.out = &writeBuffer
+	lineBreaker.out = output
 
 	switch encoding {
-	case EncodingQP:
-		encodedWriter = quotedprintable.NewWriter(&writeBuffer)
 	case EncodingB64:
 		encodedWriter = base64.NewEncoder(base64.StdEncoding, &lineBreaker)
-	case NoEncoding:
-		_, err = writeFunc(&writeBuffer)
-		if err != nil {
-			mw.err = fmt.Errorf("bodyWriter function: %w", err)
-		}
-		n, err = io.Copy(writer, &writeBuffer)
-		if err != nil && mw.err == nil {
-			mw.err = fmt.Errorf("bodyWriter io.Copy: %w", err)
-		}
-		if mw.depth == 0 {
-			mw.bytesWritten += n
-		}
+	case NoEncoding, EncodingBinary:
+		_, err = writeFunc(output)
+		mw.setBodyError(output, err)
+		mw.addBodyBytes(output)
 		return
 	default:
-		encodedWriter = quotedprintable.NewWriter(writer)
+		encodedWriter = quotedprintable.NewWriter(output)
 	}
 
 	_, err = writeFunc(encodedWriter)
-	if err != nil {
-		mw.err = fmt.Errorf("bodyWriter function: %w", err)
-	}
+	mw.setBodyError(output, err)
 	err = encodedWriter.Close()
 	if err != nil && mw.err == nil {
 		mw.err = fmt.Errorf("bodyWriter close encoded writer: %w", err)
 	}
-	err = lineBreaker.Close()
-	if err != nil && mw.err == nil {
-		mw.err = fmt.Errorf("bodyWriter close linebreaker: %w", err)
+	if encoding == EncodingB64 {
+		err = lineBreaker.Close()
+		if err != nil && mw.err == nil {
+			mw.err = fmt.Errorf("bodyWriter close linebreaker: %w", err)
+		}
 	}
-	n, err = io.Copy(writer, &writeBuffer)
-	if err != nil && mw.err == nil {
-		mw.err = fmt.Errorf("bodyWriter io.Copy: %w", err)
+	mw.addBodyBytes(output)
+}
+
+// setBodyError sets the error of the msgWriter after the body content was written. An error of the
+// underlying io.Writer takes precedence over the error returned by the body write function.
+//
+// Parameters:
+//   - output: The bodyWriter the body content was written to.
+//   - err: The error returned by the body write function.
+func (mw *msgWriter) setBodyError(output *bodyWriter, err error) {
+	if output.err != nil {
+		mw.err = fmt.Errorf("bodyWriter io.Copy: %w", output.err)
+		return
 	}
+	if err != nil {
+		mw.err = fmt.Errorf("bodyWriter function: %w", err)
+	}
+}
 
-	// Since the part writer uses the WriteTo() method, we don't need to add the
-	// bytes twice
+// addBodyBytes adds the bytes written to the given bodyWriter to the bytes written by the msgWriter.
+//
+// Since the part writer uses the msgWriter's Write() method, the bytes are only added at the top
+// level, so that they are not counted twice.
+//
+// Parameters:
+//   - output: The bodyWriter the body content was written to.
+func (mw *msgWriter) addBodyBytes(output *bodyWriter) {
 	if mw.depth == 0 {
-		mw.bytesWritten += n
+		mw.bytesWritten += output.bytesWritten
 	}
 }
+
+// bodyWriter is an io.Writer that passes the body content through to the underlying io.Writer while
+// keeping track of the number of bytes written and the first write error.
+type bodyWriter struct {
+	bytesWritten int64
+	err          error
+	writer       io.Writer
+}
+
+// Write satisfies the io.Writer interface for the bodyWriter type.
+//
+// Parameters:
+//   - payload: A byte slice containing the data to be written.
+//
+// Returns:
+//   - The number of bytes successfully written.
+//   - An error if the writing process fails, or if a previous write failed.
+func (w *bodyWriter) Write(payload []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.writer.Write(payload)
+	w.bytesWritten += int64(n)
+	w.err = err
+	return n, err
+}
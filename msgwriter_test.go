@@ -6,6 +6,7 @@ package mail
 
 import (
 	"bytes"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -494,6 +495,74 @@ func TestMsgWriter_addFiles(t *testing.T) {
 			t.Errorf("Content-Transfer-Encoding header not found for attachment. Mail: %s", buffer.String())
 		}
 	})
+	t.Run("message with automatic file encoding", func(t *testing.T) {
+		csvContent := "id;name;comment\r\n1;Toni Tester;Grüße\r\n2;Tina Tester;sehr gut\r\n"
+		tests := []struct {
+			name     string
+			content  string
+			opts     []FileOption
+			auto     bool
+			encoding Encoding
+		}{
+			{"mostly ascii csv", csvContent, []FileOption{WithFileContentType("text/csv")}, true, EncodingQP},
+			{
+				"csv without automatic encoding", csvContent, []FileOption{WithFileContentType("text/csv")},
+				false, EncodingB64,
+			},
+			{
+				"csv with explicit encoding", csvContent,
+				[]FileOption{WithFileContentType("text/csv"), WithFileEncoding(EncodingB64)}, true, EncodingB64,
+			},
+			{"text with nul bytes", "log\x00line\r\n", []FileOption{WithFileContentType(TypeTextPlain)}, true, EncodingB64},
+			{"mostly non-ascii text", "Äöüßäöü", []FileOption{WithFileContentType(TypeTextPlain)}, true, EncodingB64},
+			{"binary file", "log line\r\n", []FileOption{WithFileContentType(TypeAppOctetStream)}, true, EncodingB64},
+			{"structured text", `{"log":"line"}`, []FileOption{WithFileContentType("application/json")}, true, EncodingQP},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				buffer := bytes.NewBuffer(nil)
+				autowriter := &msgWriter{
+					writer: buffer, charset: CharsetUTF8, encoder: getEncoder(EncodingQP), autoFileEncoding: tt.auto,
+				}
+				message := testMessage(t)
+				if err := message.AttachReader("data.txt", strings.NewReader(tt.content), tt.opts...); err != nil {
+					t.Fatalf("failed to attach reader: %s", err)
+				}
+				autowriter.writeMsg(message)
+				if autowriter.err != nil {
+					t.Fatalf("msgWriter failed to write: %s", autowriter.err)
+				}
+				wantHeader := "Content-Transfer-Encoding: " + string(tt.encoding)
+				if !strings.Contains(buffer.String(), wantHeader) {
+					t.Errorf("Content-Transfer-Encoding header %q not found for attachment. Mail: %s",
+						wantHeader, buffer.String())
+				}
+				// Only the first line of the Base64 encoded content is compared, as it is wrapped.
+				encoded := base64.StdEncoding.EncodeToString([]byte(tt.content))[:16]
+				if tt.encoding == EncodingQP && strings.Contains(buffer.String(), encoded) {
+					t.Errorf("quoted-printable attachment was Base64 encoded. Mail: %s", buffer.String())
+				}
+				if tt.encoding == EncodingB64 && !strings.Contains(buffer.String(), encoded) {
+					t.Errorf("Base64 encoded attachment not found in mail message. Mail: %s", buffer.String())
+				}
+
+				// A second write must use the encoding of the first one.
+				buffer.Reset()
+				autowriter.writeMsg(message)
+				if autowriter.err != nil {
+					t.Fatalf("msgWriter failed to write: %s", autowriter.err)
+				}
+				if !strings.Contains(buffer.String(), wantHeader) {
+					t.Errorf("Content-Transfer-Encoding header %q not found on second write. Mail: %s",
+						wantHeader, buffer.String())
+				}
+				if tt.encoding == EncodingQP && strings.Contains(buffer.String(), encoded) {
+					t.Errorf("quoted-printable attachment was Base64 encoded on second write. Mail: %s",
+						buffer.String())
+				}
+			})
+		}
+	})
 }
 
 func TestMsgWriter_writePart(t *testing.T) {
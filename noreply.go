@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+
+	"github.com/wneessen/go-mail/log"
+)
+
+// ErrNoReplyConflict is returned if a Msg from a no-reply sender expects replies or read receipts
+// at a no-reply address.
+var ErrNoReplyConflict = errors.New("no-reply sender conflicts with reply expectations")
+
+// noReplyLocalParts holds the normalized local parts that identify a no-reply address.
+var noReplyLocalParts = map[string]bool{
+	"donotreply": true, "noreply": true, "noresponse": true,
+}
+
+type (
+	// NoReplyGuardOption is a function type that configures the no-reply guard of a Client.
+	NoReplyGuardOption func(*noReplyGuard)
+
+	// noReplyGuard holds the configuration of the no-reply guard.
+	noReplyGuard struct {
+		// block controls whether a Msg with a conflict is rejected instead of only logged.
+		block bool
+
+		// replyTo is the "Reply-To" address that is set on messages from a no-reply sender without a
+		// "Reply-To" address.
+		replyTo string
+	}
+)
+
+// WithNoReplyGuard enables the no-reply guard of the Client.
+//
+// The guard checks each Msg with a no-reply "From" address, i. e. "noreply@example.com" or
+// "do-not-reply@example.com", before its delivery. If such a Msg requests read receipts with the
+// "Disposition-Notification-To" header or directs replies with the "Reply-To" header to a no-reply
+// address, the answers of the recipients are lost. By default, the guard logs a warning for such a
+// conflict. With WithNoReplyGuardBlock, the delivery is aborted with a SendError of the reason
+// ErrPolicyViolation instead. With WithNoReplyGuardReplyTo, a proper "Reply-To" address, i. e. of a
+// support mailbox, is set on each Msg from a no-reply sender that has none.
+//
+// Parameters:
+//   - opts: Optional NoReplyGuardOption functions that configure the guard.
+//
+// Returns:
+//   - An Option function that enables the no-reply guard for the Client.
+func WithNoReplyGuard(opts ...NoReplyGuardOption) Option {
+	return func(c *Client) error {
+		guard := &noReplyGuard{}
+		for _, opt := range opts {
+			if opt != nil {
+				opt(guard)
+			}
+		}
+		if guard.replyTo != "" {
+			replyTo, err := mail.ParseAddress(guard.replyTo)
+			if err != nil {
+				return fmt.Errorf("failed to parse no-reply guard reply-to address: %w", err)
+			}
+			if isNoReplyAddress(replyTo.Address) {
+				return fmt.Errorf("%w: reply-to address %s is a no-reply address", ErrNoReplyConflict,
+					replyTo.Address)
+			}
+			guard.replyTo = replyTo.String()
+		}
+		c.noReplyGuard = guard
+		return nil
+	}
+}
+
+// WithNoReplyGuardBlock configures the no-reply guard to abort the delivery of a Msg with a conflict
+// instead of logging a warning.
+//
+// Returns:
+//   - A NoReplyGuardOption function that enables blocking for the no-reply guard.
+func WithNoReplyGuardBlock() NoReplyGuardOption {
+	return func(guard *noReplyGuard) {
+		guard.block = true
+	}
+}
+
+// WithNoReplyGuardReplyTo configures the no-reply guard to set the given "Reply-To" address on each
+// Msg from a no-reply sender that has no "Reply-To" address.
+//
+// Parameters:
+//   - addr: The "Reply-To" address, i. e. "Support <support@example.com>". It must not be a no-reply
+//     address itself.
+//
+// Returns:
+//   - A NoReplyGuardOption function that sets the "Reply-To" address for the no-reply guard.
+func WithNoReplyGuardReplyTo(addr string) NoReplyGuardOption {
+	return func(guard *noReplyGuard) {
+		guard.replyTo = addr
+	}
+}
+
+// checkNoReplyGuard applies the no-reply guard of the Client to the given Msg, if enabled. If the
+// "From" address of the Msg is a no-reply address, a missing "Reply-To" address is set and the
+// "Reply-To" and "Disposition-Notification-To" addresses are checked for no-reply addresses.
+//
+// Parameters:
+//   - message: The Msg that is checked.
+//
+// Returns:
+//   - An error if the Msg has a conflict and the guard blocks such messages; otherwise, returns nil.
+func (c *Client) checkNoReplyGuard(message *Msg) error {
+	if c.noReplyGuard == nil {
+		return nil
+	}
+	from := message.GetFrom()
+	if len(from) == 0 || !isNoReplyAddress(from[0].Address) {
+		return nil
+	}
+	if c.noReplyGuard.replyTo != "" && len(message.GetGenHeader(HeaderReplyTo)) == 0 {
+		if err := message.ReplyTo(c.noReplyGuard.replyTo); err != nil {
+			return err
+		}
+	}
+
+	var conflicts []string
+	for _, header := range []Header{HeaderReplyTo, HeaderDispositionNotificationTo} {
+		for _, value := range message.GetGenHeader(header) {
+			address, err := mail.ParseAddress(value)
+			if err == nil && isNoReplyAddress(address.Address) {
+				conflicts = append(conflicts, fmt.Sprintf("%s: %s", header, address.Address))
+			}
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	err := fmt.Errorf("%w: %s", ErrNoReplyConflict, strings.Join(conflicts, ", "))
+	if c.noReplyGuard.block {
+		return err
+	}
+	logger := c.logger
+	if logger == nil {
+		logger = log.New(os.Stderr, log.LevelWarn)
+	}
+	logger.Warnf(log.Log{
+		Direction: log.DirNone, Format: "message from no-reply sender expects answers",
+		Fields: []log.Field{
+			{Key: log.FieldMessageID, Value: message.GetMessageID()},
+			{Key: log.FieldError, Value: err.Error()},
+		},
+	})
+	return nil
+}
+
+// isNoReplyAddress reports whether the given address is a no-reply address. The local part of the
+// address is normalized with normalizeAddress and compared without dashes, dots and underscores, so
+// that i. e. "No-Reply+news@example.com" and "do_not_reply@example.com" are no-reply addresses.
+//
+// Parameters:
+//   - address: The addr-spec of the address, i. e. "noreply@example.com".
+//
+// Returns:
+//   - true if the address is a no-reply address, false otherwise.
+func isNoReplyAddress(address string) bool {
+	normalized, err := normalizeAddress(address)
+	if err != nil {
+		return false
+	}
+	localPart := normalized[:strings.LastIndex(normalized, "@")]
+	return noReplyLocalParts[strings.NewReplacer("-", "", ".", "", "_", "").Replace(localPart)]
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wneessen/go-mail/log"
+)
+
+func TestWithNoReplyGuard(t *testing.T) {
+	t.Run("invalid reply-to address fails", func(t *testing.T) {
+		if _, err := NewClient(DefaultHost, WithNoReplyGuard(WithNoReplyGuardReplyTo("invalid"))); err == nil {
+			t.Error("expected invalid reply-to address to fail")
+		}
+	})
+	t.Run("no-reply reply-to address fails", func(t *testing.T) {
+		_, err := NewClient(DefaultHost, WithNoReplyGuard(WithNoReplyGuardReplyTo("noreply@domain.tld")))
+		if !errors.Is(err, ErrNoReplyConflict) {
+			t.Errorf("expected error %s, got: %s", ErrNoReplyConflict, err)
+		}
+	})
+
+	tests := []struct {
+		name        string
+		from        string
+		replyTo     string
+		mdn         string
+		guardOpts   []NoReplyGuardOption
+		wantReplyTo string
+		wantErr     bool
+		wantWarning bool
+	}{
+		{"regular sender", "valid-from@domain.tld", "noreply@domain.tld", "", nil, "<noreply@domain.tld>", false, false},
+		{"no-reply sender without conflict", "noreply@domain.tld", "", "", nil, "", false, false},
+		{"MDN to no-reply address warns", "noreply@domain.tld", "", "no-reply@domain.tld", nil, "", false, true},
+		{
+			"reply-to no-reply address blocks", "Do-Not-Reply+news@domain.tld", "donotreply@domain.tld", "",
+			[]NoReplyGuardOption{WithNoReplyGuardBlock()}, "<donotreply@domain.tld>", true, false,
+		},
+		{
+			"reply-to is set", "noreply@domain.tld", "", "",
+			[]NoReplyGuardOption{WithNoReplyGuardReplyTo("Support <support@domain.tld>")},
+			`"Support" <support@domain.tld>`, false, false,
+		},
+		{
+			"reply-to of the message is kept", "noreply@domain.tld", "team@domain.tld", "",
+			[]NoReplyGuardOption{WithNoReplyGuardReplyTo("support@domain.tld")}, "<team@domain.tld>", false, false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := bytes.NewBuffer(nil)
+			client, err := NewClient(DefaultHost, WithNoReplyGuard(tt.guardOpts...),
+				WithLogger(log.New(buffer, log.LevelWarn)))
+			if err != nil {
+				t.Fatalf("failed to create new client: %s", err)
+			}
+			message := testMessage(t)
+			if err = message.From(tt.from); err != nil {
+				t.Fatalf("failed to set from address: %s", err)
+			}
+			if tt.replyTo != "" {
+				if err = message.ReplyTo(tt.replyTo); err != nil {
+					t.Fatalf("failed to set reply-to address: %s", err)
+				}
+			}
+			if tt.mdn != "" {
+				if err = message.RequestMDNTo(tt.mdn); err != nil {
+					t.Fatalf("failed to request MDN: %s", err)
+				}
+			}
+			err = client.checkNoReplyGuard(message)
+			if tt.wantErr != errors.Is(err, ErrNoReplyConflict) {
+				t.Errorf("unexpected error, want error: %t, got: %v", tt.wantErr, err)
+			}
+			if got := strings.Contains(buffer.String(), "message from no-reply sender expects answers"); got != tt.wantWarning {
+				t.Errorf("unexpected warning, want warning: %t, got: %q", tt.wantWarning, buffer.String())
+			}
+			replyTo := strings.Join(message.GetGenHeader(HeaderReplyTo), ", ")
+			if replyTo != tt.wantReplyTo {
+				t.Errorf("unexpected reply-to address, want: %s, got: %s", tt.wantReplyTo, replyTo)
+			}
+		})
+	}
+}
+
+func TestIsNoReplyAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{"noreply@domain.tld", true},
+		{"No-Reply@domain.tld", true},
+		{"do_not_reply@domain.tld", true},
+		{"no.reply+alerts@domain.tld", true},
+		{"noresponse@domain.tld", true},
+		{"noreplies@domain.tld", false},
+		{"support@noreply.domain.tld", false},
+		{"invalid", false},
+	}
+	for _, tt := range tests {
+		if got := isNoReplyAddress(tt.address); got != tt.want {
+			t.Errorf("isNoReplyAddress(%q) = %t, want %t", tt.address, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"mime"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// MAPI property types used in Outlook MSG files.
+const (
+	mapiTypeBinary  uint16 = 0x0102
+	mapiTypeBoolean uint16 = 0x000b
+	mapiTypeLong    uint16 = 0x0003
+	mapiTypeString  uint16 = 0x001f
+	mapiTypeTime    uint16 = 0x0040
+)
+
+// MAPI property IDs used in Outlook MSG files.
+const (
+	mapiAddrType             uint16 = 0x3002
+	mapiAttachContentID      uint16 = 0x3712
+	mapiAttachDataBin        uint16 = 0x3701
+	mapiAttachFilename       uint16 = 0x3704
+	mapiAttachLongFilename   uint16 = 0x3707
+	mapiAttachMethod         uint16 = 0x3705
+	mapiAttachMimeTag        uint16 = 0x370e
+	mapiAttachNum            uint16 = 0x0e21
+	mapiAttachmentHidden     uint16 = 0x7ffe
+	mapiBody                 uint16 = 0x1000
+	mapiClientSubmitTime     uint16 = 0x0039
+	mapiDisplayBcc           uint16 = 0x0e02
+	mapiDisplayCc            uint16 = 0x0e03
+	mapiDisplayName          uint16 = 0x3001
+	mapiDisplayTo            uint16 = 0x0e04
+	mapiEmailAddress         uint16 = 0x3003
+	mapiHasAttach            uint16 = 0x0e1b
+	mapiHTML                 uint16 = 0x1013
+	mapiInternetCPID         uint16 = 0x3fde
+	mapiInternetMessageID    uint16 = 0x1035
+	mapiMessageClass         uint16 = 0x001a
+	mapiMessageDeliveryTime  uint16 = 0x0e06
+	mapiMessageFlags         uint16 = 0x0e07
+	mapiRecipientType        uint16 = 0x0c15
+	mapiRowID                uint16 = 0x3000
+	mapiSenderAddrType       uint16 = 0x0c1e
+	mapiSenderEmailAddress   uint16 = 0x0c1f
+	mapiSenderName           uint16 = 0x0c1a
+	mapiSentRepresentingAddr uint16 = 0x0065
+	mapiSentRepresentingName uint16 = 0x0042
+	mapiSentRepresentingType uint16 = 0x0064
+	mapiSMTPAddress          uint16 = 0x39fe
+	mapiStoreSupportMask     uint16 = 0x340d
+	mapiSubject              uint16 = 0x0037
+)
+
+const (
+	// mapiCodePageUTF8 is the Windows code page identifier of UTF-8.
+	mapiCodePageUTF8 = 65001
+
+	// mapiFileTimeOffset is the number of 100-nanosecond intervals between the FILETIME epoch on
+	// January 1, 1601 and the Unix epoch.
+	mapiFileTimeOffset = 116444736000000000
+
+	// mapiMessageFlagHasAttach indicates in the message flags that the message has attachments.
+	mapiMessageFlagHasAttach = 0x00000010
+
+	// mapiMessageFlagRead indicates in the message flags that the message has been read.
+	mapiMessageFlagRead = 0x00000001
+
+	// mapiPropertyFlags marks a property as readable and writable.
+	mapiPropertyFlags = 0x00000006
+
+	// mapiStoreUnicodeOK indicates in the store support mask that the strings are stored in UTF-16.
+	mapiStoreUnicodeOK = 0x00040000
+)
+
+// mapiProperties collects the MAPI properties of an object of an Outlook MSG file.
+type mapiProperties struct {
+	// entries holds the property entries of the property stream.
+	entries bytes.Buffer
+
+	// streams holds the streams of the variable-length properties.
+	streams []mapiStream
+}
+
+// mapiStream is the stream of a variable-length MAPI property.
+type mapiStream struct {
+	// data is the content of the stream.
+	data []byte
+
+	// name is the name of the stream.
+	name string
+}
+
+// WriteToMSG writes the Msg as an Outlook MSG file into the given io.Writer, for workflows that
+// require messages to be archived in the format of Microsoft Outlook.
+//
+// The MSG file is a compound file that holds the subject, the sender, the recipients, the
+// "text/plain" and "text/html" body parts and the attachments and embeds of the Msg as MAPI
+// properties. Like with WriteTo, the middlewares of the Msg are applied and the "Date" and
+// "Message-ID" headers are set if they are missing. Other body parts, i. e. calendar parts, and
+// the remaining headers are not part of the MSG file.
+//
+// Parameters:
+//   - writer: The io.Writer to which the MSG file will be written.
+//
+// Returns:
+//   - The total number of bytes written.
+//   - An error if the content of a part or file cannot be read or writing fails; otherwise, nil.
+//
+// References:
+//   - https://learn.microsoft.com/en-us/openspecs/exchange_server_protocols/ms-oxmsg
+func (m *Msg) WriteToMSG(writer io.Writer) (int64, error) {
+	msg := m.applyMiddlewares(m)
+	msg.addDefaultHeader()
+	root := newCFBStorage("Root Entry")
+	properties := &mapiProperties{}
+	properties.addString(mapiMessageClass, "IPM.Note")
+	properties.addLong(mapiStoreSupportMask, mapiStoreUnicodeOK)
+	subject := firstHeaderValue(msg.GetGenHeader(HeaderSubject))
+	if decoded, err := (&mime.WordDecoder{}).DecodeHeader(subject); err == nil {
+		subject = decoded
+	}
+	properties.addString(mapiSubject, subject)
+	properties.addString(mapiInternetMessageID, msg.GetMessageID())
+	if date, err := mail.ParseDate(firstHeaderValue(msg.GetGenHeader(HeaderDate))); err == nil {
+		properties.addTime(mapiClientSubmitTime, date)
+		properties.addTime(mapiMessageDeliveryTime, date)
+	}
+	if from := msg.GetAddrHeader(HeaderFrom); len(from) > 0 {
+		properties.addString(mapiSenderName, mapiDisplayNameOf(from[0]))
+		properties.addString(mapiSenderEmailAddress, from[0].Address)
+		properties.addString(mapiSenderAddrType, "SMTP")
+		properties.addString(mapiSentRepresentingName, mapiDisplayNameOf(from[0]))
+		properties.addString(mapiSentRepresentingAddr, from[0].Address)
+		properties.addString(mapiSentRepresentingType, "SMTP")
+	}
+
+	recipientCount := 0
+	for _, recipientHeader := range []struct {
+		header  AddrHeader
+		display uint16
+		kind    uint32
+	}{
+		{HeaderTo, mapiDisplayTo, 1},
+		{HeaderCc, mapiDisplayCc, 2},
+		{HeaderBcc, mapiDisplayBcc, 3},
+	} {
+		var names []string
+		for _, address := range msg.GetAddrHeader(recipientHeader.header) {
+			names = append(names, mapiDisplayNameOf(address))
+			recipient := &mapiProperties{}
+			recipient.addLong(mapiRowID, uint32(recipientCount))
+			recipient.addLong(mapiRecipientType, recipientHeader.kind)
+			recipient.addString(mapiDisplayName, mapiDisplayNameOf(address))
+			recipient.addString(mapiEmailAddress, address.Address)
+			recipient.addString(mapiAddrType, "SMTP")
+			recipient.addString(mapiSMTPAddress, address.Address)
+			recipient.writeTo(root.addStorage(fmt.Sprintf("__recip_version1.0_#%08X", recipientCount)),
+				make([]byte, 8))
+			recipientCount++
+		}
+		properties.addString(recipientHeader.display, strings.Join(names, "; "))
+	}
+
+	var hasText, hasHTML bool
+	for _, part := range msg.parts {
+		if part.isDeleted {
+			continue
+		}
+		contentType := part.GetContentType()
+		if (contentType != TypeTextPlain || hasText) && (contentType != TypeTextHTML || hasHTML) {
+			continue
+		}
+		content, err := part.GetContent()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s part: %w", contentType, err)
+		}
+		if contentType == TypeTextPlain {
+			properties.addString(mapiBody, string(content))
+			hasText = true
+			continue
+		}
+		properties.addBinary(mapiHTML, content)
+		properties.addLong(mapiInternetCPID, mapiCodePageUTF8)
+		hasHTML = true
+	}
+
+	attachmentCount := 0
+	for _, files := range []struct {
+		files  []*File
+		inline bool
+	}{
+		{msg.attachments, false},
+		{msg.embeds, true},
+	} {
+		for _, file := range files.files {
+			if err := file.writeMAPIAttachment(root, attachmentCount, files.inline); err != nil {
+				return 0, err
+			}
+			attachmentCount++
+		}
+	}
+	flags := uint32(mapiMessageFlagRead)
+	if attachmentCount > 0 {
+		flags |= mapiMessageFlagHasAttach
+	}
+	properties.addLong(mapiMessageFlags, flags)
+	properties.addBool(mapiHasAttach, attachmentCount > 0)
+
+	nameID := root.addStorage("__nameid_version1.0")
+	for _, name := range []string{"__substg1.0_00020102", "__substg1.0_00030102", "__substg1.0_00040102"} {
+		nameID.addStream(name, nil)
+	}
+	header := make([]byte, 32)
+	binary.LittleEndian.PutUint32(header[8:], uint32(recipientCount))
+	binary.LittleEndian.PutUint32(header[12:], uint32(attachmentCount))
+	binary.LittleEndian.PutUint32(header[16:], uint32(recipientCount))
+	binary.LittleEndian.PutUint32(header[20:], uint32(attachmentCount))
+	properties.writeTo(root, header)
+	return writeCFB(writer, root)
+}
+
+// writeMAPIAttachment adds the File as attachment storage with the given index to the given root
+// storage of an Outlook MSG file. Inline files carry their Content-ID and are marked as hidden, unless
+// their disposition type is "attachment".
+func (f *File) writeMAPIAttachment(root *cfbEntry, index int, inline bool) error {
+	if f.Writer == nil {
+		return fmt.Errorf("%w: %s", ErrFileWriterIsNil, f.Name)
+	}
+	buffer := bytes.NewBuffer(nil)
+	if _, err := f.Writer(buffer); err != nil {
+		return fmt.Errorf("failed to read file %s: %w", f.Name, err)
+	}
+	attachment := &mapiProperties{}
+	attachment.addLong(mapiAttachNum, uint32(index))
+	attachment.addLong(mapiAttachMethod, 1)
+	attachment.addString(mapiDisplayName, f.Name)
+	attachment.addString(mapiAttachFilename, f.Name)
+	attachment.addString(mapiAttachLongFilename, f.Name)
+	attachment.addString(mapiAttachMimeTag, policyFileContentType(f))
+	attachment.addBinary(mapiAttachDataBin, buffer.Bytes())
+	if inline {
+		contentID, ok := f.getHeader(HeaderContentID)
+		if !ok {
+			contentID = f.Name
+		}
+		attachment.addString(mapiAttachContentID, strings.Trim(contentID, "<>"))
+		if f.disposition(false) == DispositionInline {
+			attachment.addBool(mapiAttachmentHidden, true)
+		}
+	}
+	attachment.writeTo(root.addStorage(fmt.Sprintf("__attach_version1.0_#%08X", index)), make([]byte, 8))
+	return nil
+}
+
+// addString adds a string property. Empty strings are omitted.
+func (p *mapiProperties) addString(id uint16, value string) {
+	if value == "" {
+		return
+	}
+	units := utf16.Encode([]rune(value))
+	data := make([]byte, len(units)*2)
+	for i, unit := range units {
+		binary.LittleEndian.PutUint16(data[i*2:], unit)
+	}
+	p.addStream(id, mapiTypeString, data, uint32(len(data)+2))
+}
+
+// addBinary adds a binary property.
+func (p *mapiProperties) addBinary(id uint16, value []byte) {
+	p.addStream(id, mapiTypeBinary, value, uint32(len(value)))
+}
+
+// addLong adds a 32-bit integer property.
+func (p *mapiProperties) addLong(id uint16, value uint32) {
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint32(data, value)
+	p.addEntry(id, mapiTypeLong, data)
+}
+
+// addBool adds a boolean property.
+func (p *mapiProperties) addBool(id uint16, value bool) {
+	data := make([]byte, 8)
+	if value {
+		data[0] = 1
+	}
+	p.addEntry(id, mapiTypeBoolean, data)
+}
+
+// addTime adds a time property, which is stored as FILETIME.
+func (p *mapiProperties) addTime(id uint16, value time.Time) {
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint64(data, uint64(value.UnixNano()/100+mapiFileTimeOffset))
+	p.addEntry(id, mapiTypeTime, data)
+}
+
+// addStream adds a variable-length property, whose content is stored in its own stream, with the
+// given size in the property stream.
+func (p *mapiProperties) addStream(id, propType uint16, data []byte, size uint32) {
+	value := make([]byte, 8)
+	binary.LittleEndian.PutUint32(value, size)
+	p.addEntry(id, propType, value)
+	p.streams = append(p.streams, mapiStream{data: data, name: fmt.Sprintf("__substg1.0_%04X%04X", id, propType)})
+}
+
+// addEntry adds an entry with the given 8-byte value to the property stream.
+func (p *mapiProperties) addEntry(id, propType uint16, value []byte) {
+	entry := make([]byte, 8)
+	binary.LittleEndian.PutUint32(entry, uint32(id)<<16|uint32(propType))
+	binary.LittleEndian.PutUint32(entry[4:], mapiPropertyFlags)
+	p.entries.Write(entry)
+	p.entries.Write(value)
+}
+
+// writeTo adds the property stream with the given header and the streams of the variable-length
+// properties to the given storage.
+func (p *mapiProperties) writeTo(storage *cfbEntry, header []byte) {
+	storage.addStream("__properties_version1.0", append(header, p.entries.Bytes()...))
+	for _, stream := range p.streams {
+		storage.addStream(stream.name, stream.data)
+	}
+}
+
+// mapiDisplayNameOf returns the display name of the given address, or the address itself if it has
+// no name.
+func mapiDisplayNameOf(address *mail.Address) string {
+	if address.Name != "" {
+		return address.Name
+	}
+	return address.Address
+}
+
+// firstHeaderValue returns the first of the given header values, or an empty string if there are
+// none.
+func firstHeaderValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// testMAPIString returns the UTF-16LE representation of the given string, as it is stored in the
+// stream of a string property.
+func testMAPIString(value string) string {
+	units := utf16.Encode([]rune(value))
+	data := make([]byte, len(units)*2)
+	for i, unit := range units {
+		binary.LittleEndian.PutUint16(data[i*2:], unit)
+	}
+	return string(data)
+}
+
+// testMAPIProperty returns the 8-byte value of the property entry with the given tag in the given
+// property stream.
+func testMAPIProperty(t *testing.T, stream []byte, headerSize int, tag uint32) []byte {
+	t.Helper()
+	for offset := headerSize; offset+16 <= len(stream); offset += 16 {
+		if binary.LittleEndian.Uint32(stream[offset:]) == tag {
+			return stream[offset+8 : offset+16]
+		}
+	}
+	t.Fatalf("property %08X not found", tag)
+	return nil
+}
+
+func TestMsg_WriteToMSG(t *testing.T) {
+	message := testMessage(t)
+	if err := message.FromFormat("Toni Tester", "valid-from@domain.tld"); err != nil {
+		t.Fatalf("failed to set from address: %s", err)
+	}
+	if err := message.Cc("cc@domain.tld"); err != nil {
+		t.Fatalf("failed to set cc address: %s", err)
+	}
+	message.Subject("Grüße")
+	message.SetDateWithValue(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	message.AddAlternativeString(TypeTextHTML, "<p>Testmail</p>")
+	if err := message.AttachReader("report.txt", strings.NewReader("report")); err != nil {
+		t.Fatalf("failed to attach file: %s", err)
+	}
+	if err := message.EmbedReader("logo.png", strings.NewReader("logo")); err != nil {
+		t.Fatalf("failed to embed file: %s", err)
+	}
+	buffer := bytes.NewBuffer(nil)
+	n, err := message.WriteToMSG(buffer)
+	if err != nil {
+		t.Fatalf("failed to write message as MSG: %s", err)
+	}
+	if n != int64(buffer.Len()) {
+		t.Errorf("expected %d bytes written, got: %d", buffer.Len(), n)
+	}
+	streams := readTestCFB(t, buffer.Bytes())
+
+	wantStrings := map[string]string{
+		"__substg1.0_001A001F":                               "IPM.Note",
+		"__substg1.0_0037001F":                               "Grüße",
+		"__substg1.0_0C1A001F":                               "Toni Tester",
+		"__substg1.0_0C1F001F":                               "valid-from@domain.tld",
+		"__substg1.0_0E04001F":                               "valid-to@domain.tld",
+		"__substg1.0_0E03001F":                               "cc@domain.tld",
+		"__substg1.0_1000001F":                               "Testmail",
+		"__recip_version1.0_#00000001/__substg1.0_3003001F":  "cc@domain.tld",
+		"__attach_version1.0_#00000000/__substg1.0_3707001F": "report.txt",
+		"__attach_version1.0_#00000001/__substg1.0_3712001F": "logo.png",
+		"__attach_version1.0_#00000001/__substg1.0_370E001F": "image/png",
+	}
+	for name, want := range wantStrings {
+		if got, ok := streams[name]; !ok || string(got) != testMAPIString(want) {
+			t.Errorf("unexpected content of stream %s: %q", name, got)
+		}
+	}
+	if string(streams["__substg1.0_10130102"]) != "<p>Testmail</p>" {
+		t.Errorf("unexpected HTML body: %q", streams["__substg1.0_10130102"])
+	}
+	if string(streams["__attach_version1.0_#00000000/__substg1.0_37010102"]) != "report" {
+		t.Errorf("unexpected attachment data: %q", streams["__attach_version1.0_#00000000/__substg1.0_37010102"])
+	}
+	if _, ok := streams["__nameid_version1.0/__substg1.0_00020102"]; !ok {
+		t.Error("expected named property storage")
+	}
+
+	properties := streams["__properties_version1.0"]
+	if len(properties) < 32 || (len(properties)-32)%16 != 0 {
+		t.Fatalf("unexpected size of property stream: %d", len(properties))
+	}
+	if binary.LittleEndian.Uint32(properties[16:]) != 2 || binary.LittleEndian.Uint32(properties[20:]) != 2 {
+		t.Errorf("expected 2 recipients and 2 attachments in property stream header")
+	}
+	if size := binary.LittleEndian.Uint32(testMAPIProperty(t, properties, 32, 0x0037001f)); size != 12 {
+		t.Errorf("expected subject size to include the terminator, got: %d", size)
+	}
+	fileTime := binary.LittleEndian.Uint64(testMAPIProperty(t, properties, 32, 0x00390040))
+	want := uint64(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).UnixNano()/100 + mapiFileTimeOffset)
+	if fileTime != want {
+		t.Errorf("unexpected submit time, want: %d, got: %d", want, fileTime)
+	}
+	recipient := streams["__recip_version1.0_#00000001/__properties_version1.0"]
+	if kind := binary.LittleEndian.Uint32(testMAPIProperty(t, recipient, 8, 0x0c150003)); kind != 2 {
+		t.Errorf("expected cc recipient type, got: %d", kind)
+	}
+	embed := streams["__attach_version1.0_#00000001/__properties_version1.0"]
+	if hidden := testMAPIProperty(t, embed, 8, 0x7ffe000b); hidden[0] != 1 {
+		t.Error("expected embed to be hidden")
+	}
+}
+
+func TestMsg_WriteToMSG_failingFile(t *testing.T) {
+	message := testMessage(t)
+	message.attachments = append(message.attachments, &File{
+		Name: "broken.txt",
+		Writer: func(io.Writer) (int64, error) {
+			return 0, errors.New("broken")
+		},
+	})
+	if _, err := message.WriteToMSG(bytes.NewBuffer(nil)); err == nil {
+		t.Error("expected writing a message with a broken attachment to fail")
+	}
+}
@@ -166,6 +166,14 @@ func (p *Part) Delete() {
 	p.isDeleted = true
 }
 
+// IsDeleted reports whether the Part has been removed from the Msg with Delete.
+//
+// Returns:
+//   - True if the Part is marked as deleted, otherwise false.
+func (p *Part) IsDeleted() bool {
+	return p.isDeleted
+}
+
 // WithPartCharset overrides the default Part charset.
 //
 // This function returns a PartOption that allows the charset of a Part to be overridden
@@ -333,10 +333,16 @@ func TestPart_Delete(t *testing.T) {
 		t.Errorf("failed: %s", err)
 		return
 	}
+	if pl[0].IsDeleted() {
+		t.Errorf("IsDeleted failed. Expected: %t, got: %t", false, pl[0].IsDeleted())
+	}
 	pl[0].Delete()
 	if !pl[0].isDeleted {
 		t.Errorf("Delete failed. Expected: %t, got: %t", true, pl[0].isDeleted)
 	}
+	if !pl[0].IsDeleted() {
+		t.Errorf("IsDeleted failed. Expected: %t, got: %t", true, pl[0].IsDeleted())
+	}
 }
 
 // getPartList is a helper function
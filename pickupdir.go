@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// pickupDirExtension is the file extension of the message files in a pickup directory.
+	pickupDirExtension = ".eml"
+
+	// pickupDirTmpExtension is the file extension of the message files while they are written.
+	pickupDirTmpExtension = ".tmp"
+)
+
+// pickupDirCounter is used to guarantee unique pickup directory filenames within the same process.
+var pickupDirCounter uint64
+
+// ErrPickupDirNotADirectory is returned by NewPickupDir if the given path is not a directory.
+var ErrPickupDirNotADirectory = errors.New("pickup directory path is not a directory")
+
+// PickupDir is a Transport that delivers messages by writing them into the pickup directory of an
+// IIS SMTP service or an Exchange transport server, which then sends them on.
+//
+// Each Msg is written with CRLF line endings into a uniquely named file with the ".eml" extension.
+// To prevent the server from picking up partially written messages, the file is first written with
+// a ".tmp" extension and renamed once it is complete. The envelope sender and recipients, including
+// "Bcc" recipients, are passed in the "X-Sender" and "X-Receiver" headers in front of the Msg, which
+// the pickup service uses instead of the message headers.
+type PickupDir struct {
+	// dir is the path of the pickup directory.
+	dir string
+}
+
+// Ensure that the PickupDir satisfies the Transport interface.
+var _ Transport = (*PickupDir)(nil)
+
+// NewPickupDir returns a new PickupDir that writes messages into the given directory, i. e.
+// "C:\inetpub\mailroot\Pickup".
+//
+// Parameters:
+//   - dir: The path of the pickup directory, which must exist.
+//
+// Returns:
+//   - A pointer to the new PickupDir.
+//   - An error if the directory does not exist or is not a directory; otherwise, returns nil.
+func NewPickupDir(dir string) (*PickupDir, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access pickup directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", ErrPickupDirNotADirectory, dir)
+	}
+	return &PickupDir{dir: dir}, nil
+}
+
+// SendWithContext writes each of the given messages into the pickup directory. Writing stops at the
+// first message that fails or when the context is done.
+//
+// Parameters:
+//   - ctx: The context.Context that controls the cancellation of the delivery.
+//   - messages: A variadic list of pointers to Msg objects to be delivered.
+//
+// Returns:
+//   - An error if the context is done or any of the messages could not be written; otherwise,
+//     returns nil.
+func (p *PickupDir) SendWithContext(ctx context.Context, messages ...*Msg) error {
+	for i, message := range messages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := p.WriteMsg(message); err != nil {
+			return fmt.Errorf("failed to deliver message %d to pickup directory: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// WriteMsg writes the given Msg into the pickup directory.
+//
+// Parameters:
+//   - message: A pointer to the Msg to be delivered.
+//
+// Returns:
+//   - The path of the message file in the pickup directory.
+//   - An error if the Msg has no sender or recipients or could not be written; otherwise, returns nil.
+func (p *PickupDir) WriteMsg(message *Msg) (string, error) {
+	if message == nil {
+		return "", errors.New("message is nil")
+	}
+	from, err := message.GetSender(false)
+	if err != nil {
+		return "", err
+	}
+	rcpts, err := message.GetRecipients()
+	if err != nil {
+		return "", err
+	}
+	buffer := bytes.NewBufferString("X-Sender: " + from + SingleNewLine)
+	for _, rcpt := range rcpts {
+		buffer.WriteString("X-Receiver: " + rcpt + SingleNewLine)
+	}
+	if _, err = message.WriteTo(buffer); err != nil {
+		return "", fmt.Errorf("failed to write message to buffer: %w", err)
+	}
+
+	name, err := pickupDirUniqueName()
+	if err != nil {
+		return "", err
+	}
+	tmpPath := filepath.Join(p.dir, name+pickupDirTmpExtension)
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create pickup file: %w", err)
+	}
+	_, err = file.Write(buffer.Bytes())
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write pickup file: %w", err)
+	}
+
+	path := filepath.Join(p.dir, name+pickupDirExtension)
+	if err = os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to rename pickup file: %w", err)
+	}
+	message.isDelivered = true
+	return path, nil
+}
+
+// pickupDirUniqueName returns a unique filename without extension for a pickup directory delivery.
+//
+// Returns:
+//   - The unique filename in the form "timestamp-counter-random".
+//   - An error if the random part could not be generated.
+func pickupDirUniqueName() (string, error) {
+	random, err := randomStringSecure(16)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate pickup file name: %w", err)
+	}
+	return fmt.Sprintf("%s-%d-%s", time.Now().UTC().Format("20060102T150405.000000000"),
+		atomic.AddUint64(&pickupDirCounter, 1), random), nil
+}
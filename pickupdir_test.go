@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewPickupDir(t *testing.T) {
+	t.Run("existing directory", func(t *testing.T) {
+		if _, err := NewPickupDir(t.TempDir()); err != nil {
+			t.Errorf("failed to create pickup directory transport: %s", err)
+		}
+	})
+	t.Run("missing directory", func(t *testing.T) {
+		if _, err := NewPickupDir(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected error: %s, got: %s", os.ErrNotExist, err)
+		}
+	})
+	t.Run("file instead of directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "file")
+		if err := os.WriteFile(path, []byte("test"), 0o600); err != nil {
+			t.Fatalf("failed to create test file: %s", err)
+		}
+		if _, err := NewPickupDir(path); !errors.Is(err, ErrPickupDirNotADirectory) {
+			t.Errorf("expected error: %s, got: %s", ErrPickupDirNotADirectory, err)
+		}
+	})
+}
+
+func TestPickupDir_SendWithContext(t *testing.T) {
+	t.Run("messages are written into the pickup directory", func(t *testing.T) {
+		dir := t.TempDir()
+		pickup, err := NewPickupDir(dir)
+		if err != nil {
+			t.Fatalf("failed to create pickup directory transport: %s", err)
+		}
+		message := testMessage(t)
+		if err = message.Bcc("bcc@domain.tld"); err != nil {
+			t.Fatalf("failed to set bcc address: %s", err)
+		}
+		var transport Transport = pickup
+		if err = transport.SendWithContext(context.Background(), message, testMessage(t)); err != nil {
+			t.Fatalf("failed to deliver messages: %s", err)
+		}
+		if !message.IsDelivered() {
+			t.Error("expected message to be delivered")
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("failed to read pickup directory: %s", err)
+		}
+		if len(entries) != 2 || entries[0].Name() == entries[1].Name() {
+			t.Fatalf("expected 2 uniquely named files, got: %v", entries)
+		}
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), ".eml") {
+				t.Errorf("expected .eml file, got: %s", entry.Name())
+			}
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+		if err != nil {
+			t.Fatalf("failed to read pickup file: %s", err)
+		}
+		prefix := "X-Sender: valid-from@domain.tld\r\nX-Receiver: valid-to@domain.tld\r\n"
+		if !strings.HasPrefix(string(content), prefix) {
+			t.Errorf("expected envelope headers, got: %s", content)
+		}
+		if strings.Contains(strings.ReplaceAll(string(content), "\r\n", ""), "\n") {
+			t.Error("expected CRLF line endings only")
+		}
+	})
+	t.Run("bcc recipients are part of the envelope", func(t *testing.T) {
+		pickup, err := NewPickupDir(t.TempDir())
+		if err != nil {
+			t.Fatalf("failed to create pickup directory transport: %s", err)
+		}
+		message := testMessage(t)
+		if err = message.Bcc("bcc@domain.tld"); err != nil {
+			t.Fatalf("failed to set bcc address: %s", err)
+		}
+		path, err := pickup.WriteMsg(message)
+		if err != nil {
+			t.Fatalf("failed to deliver message: %s", err)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read pickup file: %s", err)
+		}
+		if !strings.Contains(string(content), "X-Receiver: bcc@domain.tld\r\n") {
+			t.Errorf("expected bcc recipient in envelope, got: %s", content)
+		}
+	})
+	t.Run("invalid messages are not written", func(t *testing.T) {
+		dir := t.TempDir()
+		pickup, err := NewPickupDir(dir)
+		if err != nil {
+			t.Fatalf("failed to create pickup directory transport: %s", err)
+		}
+		if err = pickup.SendWithContext(context.Background(), nil); err == nil {
+			t.Error("expected nil message to fail")
+		}
+		if err = pickup.SendWithContext(context.Background(), NewMsg()); err == nil {
+			t.Error("expected message without sender to fail")
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err = pickup.SendWithContext(ctx, testMessage(t)); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected error: %s, got: %s", context.Canceled, err)
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Errorf("expected empty pickup directory, got: %v", entries)
+		}
+	})
+}
@@ -0,0 +1,301 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrPolicyDenied is returned if a Msg matches the Deny predicate of a PolicyRule.
+	ErrPolicyDenied = errors.New("message denied by policy")
+
+	// ErrPolicyTLSRequired is returned if a PolicyRule requires an encrypted connection for a Msg,
+	// but the Client connection is not encrypted.
+	ErrPolicyTLSRequired = errors.New("encrypted connection required by policy")
+)
+
+type (
+	// MsgPredicate is a function type that reports whether a given Msg matches a condition.
+	//
+	// A MsgPredicate is used by a PolicyRule to determine whether the rule applies to a Msg and
+	// whether the Msg violates the rule.
+	MsgPredicate func(*Msg) bool
+
+	// PolicyRule represents a single rule of a content policy for outbound mail.
+	//
+	// A PolicyRule is evaluated by the Client for each Msg, right before the delivery of the Msg
+	// starts. If the When predicate of the rule matches the Msg, the Msg is checked against the
+	// Deny predicate and the RequireTLS setting of the rule. A Msg that violates the rule is not
+	// delivered.
+	PolicyRule struct {
+		// Deny reports whether a Msg, that the rule applies to, violates the rule. If Deny is nil,
+		// the rule does not deny any Msg.
+		Deny MsgPredicate
+
+		// Name identifies the rule in a PolicyError.
+		Name string
+
+		// RequireTLS requires the Client connection to be encrypted for the delivery of a Msg, that
+		// the rule applies to.
+		RequireTLS bool
+
+		// When reports whether the rule applies to a Msg. If When is nil, the rule applies to all
+		// messages.
+		When MsgPredicate
+	}
+
+	// PolicyError is an error type that is returned if a Msg violates a PolicyRule.
+	PolicyError struct {
+		// Err is the reason for the violation, i. e. ErrPolicyDenied or ErrPolicyTLSRequired.
+		Err error
+
+		// Rule is the name of the violated PolicyRule.
+		Rule string
+	}
+)
+
+// WithPolicy adds the given PolicyRule to the content policy of the Client.
+//
+// The content policy is evaluated for each Msg before its delivery starts. All rules are evaluated
+// and if the Msg violates any of them, the delivery of that Msg is aborted with a SendError with
+// the reason ErrPolicyViolation that holds a PolicyError for each violated rule. WithPolicy can be
+// used multiple times to add further rules.
+//
+// Parameters:
+//   - rules: The PolicyRule values that are added to the content policy of the Client.
+//
+// Returns:
+//   - An Option function that adds the rules to the Client.
+func WithPolicy(rules ...PolicyRule) Option {
+	return func(c *Client) error {
+		c.policyRules = append(c.policyRules, rules...)
+		return nil
+	}
+}
+
+// Error implements the error interface for the PolicyError type.
+//
+// Returns:
+//   - A string describing the violated rule and the reason for the violation.
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("policy rule %q violated: %s", e.Rule, e.Err)
+}
+
+// Unwrap returns the reason for the policy violation.
+//
+// Returns:
+//   - The underlying error of the PolicyError.
+func (e *PolicyError) Unwrap() error {
+	return e.Err
+}
+
+// SubjectContains returns a MsgPredicate that matches a Msg, if its subject contains the given
+// substring. The comparison is case-insensitive.
+//
+// Parameters:
+//   - substr: The substring to look for in the subject of the Msg.
+//
+// Returns:
+//   - A MsgPredicate that matches messages by their subject.
+func SubjectContains(substr string) MsgPredicate {
+	substr = strings.ToLower(substr)
+	return func(msg *Msg) bool {
+		for _, subject := range msg.GetGenHeader(HeaderSubject) {
+			if strings.Contains(strings.ToLower(subject), substr) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HasRecipientDomain returns a MsgPredicate that matches a Msg, if any of its "To", "Cc" or "Bcc"
+// recipients is an address of one of the given domains. The comparison is case-insensitive.
+//
+// Parameters:
+//   - domains: The domains to look for in the recipient addresses of the Msg.
+//
+// Returns:
+//   - A MsgPredicate that matches messages by the domains of their recipients.
+func HasRecipientDomain(domains ...string) MsgPredicate {
+	return func(msg *Msg) bool {
+		for _, domain := range policyRecipientDomains(msg) {
+			if policyDomainListed(domain, domains) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HasExternalRecipients returns a MsgPredicate that matches a Msg, if any of its "To", "Cc" or
+// "Bcc" recipients is an address outside of the given internal domains. The comparison is
+// case-insensitive. Subdomains of the internal domains are not considered internal, unless they
+// are listed as well.
+//
+// Parameters:
+//   - internalDomains: The domains that are considered internal.
+//
+// Returns:
+//   - A MsgPredicate that matches messages with external recipients.
+func HasExternalRecipients(internalDomains ...string) MsgPredicate {
+	return func(msg *Msg) bool {
+		for _, domain := range policyRecipientDomains(msg) {
+			if !policyDomainListed(domain, internalDomains) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HasAttachmentType returns a MsgPredicate that matches a Msg, if any of its attachments or embeds
+// is of one of the given content types. A content type with a "*" subtype, like "image/*", matches
+// all subtypes of the type.
+//
+// The content type of a file is determined the same way as the content type that is written for
+// it, so files without an explicit content type are matched by the extension of their name.
+//
+// Parameters:
+//   - contentTypes: The content types to look for in the attachments and embeds of the Msg.
+//
+// Returns:
+//   - A MsgPredicate that matches messages by the content types of their files.
+func HasAttachmentType(contentTypes ...ContentType) MsgPredicate {
+	return func(msg *Msg) bool {
+		for _, file := range policyFiles(msg) {
+			if policyContentTypeListed(policyFileContentType(file), contentTypes) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HasDisallowedAttachment returns a MsgPredicate that matches a Msg, if any of its attachments or
+// embeds is not of one of the given allowed content types. Content types are matched the same
+// way as in HasAttachmentType.
+//
+// Parameters:
+//   - allowed: The content types that are allowed for the attachments and embeds of the Msg.
+//
+// Returns:
+//   - A MsgPredicate that matches messages with files of content types that are not allowed.
+func HasDisallowedAttachment(allowed ...ContentType) MsgPredicate {
+	return func(msg *Msg) bool {
+		for _, file := range policyFiles(msg) {
+			if !policyContentTypeListed(policyFileContentType(file), allowed) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a MsgPredicate that negates the given MsgPredicate.
+//
+// Parameters:
+//   - predicate: The MsgPredicate to negate.
+//
+// Returns:
+//   - A MsgPredicate that matches all messages the given predicate does not match.
+func Not(predicate MsgPredicate) MsgPredicate {
+	return func(msg *Msg) bool {
+		return !predicate(msg)
+	}
+}
+
+// checkPolicy evaluates the given rules for a Msg and returns a PolicyError for each violated rule.
+//
+// Parameters:
+//   - msg: The Msg to evaluate the rules for.
+//   - rules: The PolicyRule values to evaluate.
+//   - encrypted: Indicates whether the connection the Msg is delivered over is encrypted.
+//
+// Returns:
+//   - A slice of errors with a PolicyError for each violated rule, or nil if no rule is violated.
+func checkPolicy(msg *Msg, rules []PolicyRule, encrypted bool) []error {
+	var violations []error
+	for _, rule := range rules {
+		if rule.When != nil && !rule.When(msg) {
+			continue
+		}
+		if rule.Deny != nil && rule.Deny(msg) {
+			violations = append(violations, &PolicyError{Err: ErrPolicyDenied, Rule: rule.Name})
+			continue
+		}
+		if rule.RequireTLS && !encrypted {
+			violations = append(violations, &PolicyError{Err: ErrPolicyTLSRequired, Rule: rule.Name})
+		}
+	}
+	return violations
+}
+
+// policyRecipientDomains returns the lower-cased domains of all recipient addresses of a Msg.
+func policyRecipientDomains(msg *Msg) []string {
+	var domains []string
+	for _, header := range []AddrHeader{HeaderTo, HeaderCc, HeaderBcc} {
+		for _, addr := range msg.addrHeader[header] {
+			if index := strings.LastIndex(addr.Address, "@"); index >= 0 {
+				domains = append(domains, strings.ToLower(addr.Address[index+1:]))
+			}
+		}
+	}
+	return domains
+}
+
+// policyDomainListed reports whether the domain is part of the given list of domains.
+func policyDomainListed(domain string, domains []string) bool {
+	for _, listed := range domains {
+		if strings.EqualFold(domain, strings.TrimPrefix(listed, "@")) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyFiles returns all attachments and embeds of a Msg.
+func policyFiles(msg *Msg) []*File {
+	files := make([]*File, 0, len(msg.attachments)+len(msg.embeds))
+	files = append(files, msg.attachments...)
+	return append(files, msg.embeds...)
+}
+
+// policyFileContentType returns the lower-cased media type of a File, without any parameters.
+func policyFileContentType(file *File) string {
+	contentType := mime.TypeByExtension(filepath.Ext(file.Name))
+	if contentType == "" {
+		contentType = TypeAppOctetStream.String()
+	}
+	if file.ContentType != "" {
+		contentType = file.ContentType.String()
+	}
+	if values, ok := file.getHeader(HeaderContentType); ok {
+		contentType = values
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(contentType)
+}
+
+// policyContentTypeListed reports whether the media type matches any of the given content types.
+func policyContentTypeListed(mediaType string, contentTypes []ContentType) bool {
+	for _, contentType := range contentTypes {
+		listed := strings.ToLower(contentType.String())
+		if listed == mediaType {
+			return true
+		}
+		if strings.HasSuffix(listed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(listed, "*")) {
+			return true
+		}
+	}
+	return false
+}
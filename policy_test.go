@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMsgPredicates(t *testing.T) {
+	message := testMessage(t)
+	message.Subject("CONFIDENTIAL: Quarterly report")
+	if err := message.AddCc("toni.tester@partner.example"); err != nil {
+		t.Fatalf("failed to add recipient: %s", err)
+	}
+	if err := message.AttachReader("report.pdf", bytes.NewBufferString("report")); err != nil {
+		t.Fatalf("failed to attach file: %s", err)
+	}
+	if err := message.EmbedReader("logo", bytes.NewBufferString("logo"), WithFileContentType("image/png")); err != nil {
+		t.Fatalf("failed to embed file: %s", err)
+	}
+	tests := []struct {
+		name      string
+		predicate MsgPredicate
+		want      bool
+	}{
+		{"SubjectContains matches case-insensitive", SubjectContains("confidential"), true},
+		{"SubjectContains does not match", SubjectContains("internal"), false},
+		{"HasRecipientDomain matches Cc", HasRecipientDomain("PARTNER.example"), true},
+		{"HasRecipientDomain does not match", HasRecipientDomain("example.com"), false},
+		{"HasExternalRecipients with external Cc", HasExternalRecipients("domain.tld"), true},
+		{"HasExternalRecipients with all internal", HasExternalRecipients("@domain.tld", "partner.example"), false},
+		{"HasAttachmentType matches extension", HasAttachmentType("application/pdf"), true},
+		{"HasAttachmentType matches wildcard", HasAttachmentType("image/*"), true},
+		{"HasAttachmentType does not match", HasAttachmentType("application/zip"), false},
+		{"HasDisallowedAttachment with allowed types", HasDisallowedAttachment("application/pdf", "image/*"), false},
+		{"HasDisallowedAttachment with disallowed type", HasDisallowedAttachment("application/pdf"), true},
+		{"Not negates predicate", Not(SubjectContains("confidential")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.predicate(message); got != tt.want {
+				t.Errorf("unexpected predicate result, want: %t, got: %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCheckPolicy(t *testing.T) {
+	message := testMessage(t)
+	message.Subject("CONFIDENTIAL")
+	rules := []PolicyRule{
+		{Name: "always"},
+		{Name: "confidential", When: SubjectContains("confidential"), Deny: HasExternalRecipients("example.com")},
+		{Name: "tls", When: HasRecipientDomain("domain.tld"), RequireTLS: true},
+		{Name: "not applicable", When: HasRecipientDomain("example.com"), Deny: SubjectContains("")},
+	}
+	t.Run("violations on unencrypted connection", func(t *testing.T) {
+		violations := checkPolicy(message, rules, false)
+		if len(violations) != 2 {
+			t.Fatalf("expected 2 violations, got: %d", len(violations))
+		}
+		var policyErr *PolicyError
+		if !errors.As(violations[0], &policyErr) || policyErr.Rule != "confidential" {
+			t.Errorf("expected violation of rule %q, got: %s", "confidential", violations[0])
+		}
+		if !errors.Is(violations[0], ErrPolicyDenied) {
+			t.Errorf("expected error: %s, got: %s", ErrPolicyDenied, violations[0])
+		}
+		if !errors.Is(violations[1], ErrPolicyTLSRequired) {
+			t.Errorf("expected error: %s, got: %s", ErrPolicyTLSRequired, violations[1])
+		}
+		want := `policy rule "tls" violated: encrypted connection required by policy`
+		if violations[1].Error() != want {
+			t.Errorf("unexpected error message, want: %s, got: %s", want, violations[1])
+		}
+	})
+	t.Run("encrypted connection satisfies RequireTLS", func(t *testing.T) {
+		if violations := checkPolicy(message, rules, true); len(violations) != 1 {
+			t.Errorf("expected 1 violation, got: %d", len(violations))
+		}
+	})
+}
+
+func TestClient_Send_withPolicy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	PortAdder.Add(1)
+	serverPort := int(TestServerPortBase + PortAdder.Load())
+	featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+	go func() {
+		if err := simpleSMTPServer(ctx, t, &serverProps{FeatureSet: featureSet, ListenPort: serverPort}); err != nil {
+			t.Errorf("failed to start test server: %s", err)
+			return
+		}
+	}()
+	time.Sleep(time.Millisecond * 30)
+
+	client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS),
+		WithPolicy(PolicyRule{
+			Name: "confidential", When: SubjectContains("CONFIDENTIAL"),
+			Deny: HasExternalRecipients("example.com"),
+		}))
+	if err != nil {
+		t.Fatalf("failed to create new client: %s", err)
+	}
+	if err = client.DialWithContext(context.Background()); err != nil {
+		t.Fatalf("failed to connect to the test server: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Errorf("failed to close client: %s", err)
+		}
+	})
+
+	t.Run("message without violation is delivered", func(t *testing.T) {
+		if err := client.Send(testMessage(t)); err != nil {
+			t.Errorf("failed to send message: %s", err)
+		}
+	})
+	t.Run("message with violation is not delivered", func(t *testing.T) {
+		message := testMessage(t)
+		message.Subject("CONFIDENTIAL")
+		err := client.Send(message)
+		var sendErr *SendError
+		if !errors.As(err, &sendErr) || sendErr.Reason != ErrPolicyViolation {
+			t.Fatalf("expected SendError with reason %s, got: %s", ErrPolicyViolation, err)
+		}
+		if sendErr.IsTemp() {
+			t.Error("expected policy violation to be a permanent error")
+		}
+		if len(sendErr.errlist) != 1 || !errors.Is(sendErr.errlist[0], ErrPolicyDenied) {
+			t.Errorf("expected error: %s, got: %s", ErrPolicyDenied, err)
+		}
+		if message.IsDelivered() {
+			t.Error("expected message not to be delivered")
+		}
+	})
+}
@@ -0,0 +1,650 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"sync"
+	"time"
+)
+
+// List of PoolLane values
+const (
+	// PoolLaneInteractive is the lane of the messages sent with ClientPool.Send, i. e. transactional
+	// mail that a user is waiting for.
+	PoolLaneInteractive PoolLane = iota
+
+	// PoolLaneBulk is the lane of the messages sent with ClientPool.SendBulk, i. e. newsletters or
+	// the work of a mail queue.
+	PoolLaneBulk
+)
+
+const (
+	// DefaultPoolSize is the default number of persistent connections maintained by a ClientPool.
+	DefaultPoolSize = 4
+
+	// DefaultPoolIdleTimeout is the default duration after which an idle connection of a ClientPool
+	// is closed and re-established on next use.
+	DefaultPoolIdleTimeout = time.Minute * 5
+
+	// DefaultPoolHealthCheckInterval is the default duration after which an idle connection of a
+	// ClientPool is health checked before it is used again.
+	DefaultPoolHealthCheckInterval = time.Second * 30
+
+	// DefaultPoolInteractiveWeight is the default weight of the PoolLaneInteractive of a ClientPool.
+	DefaultPoolInteractiveWeight = 4
+
+	// DefaultPoolBulkWeight is the default weight of the PoolLaneBulk of a ClientPool.
+	DefaultPoolBulkWeight = 1
+)
+
+var (
+	// ErrPoolClosed is returned when a ClientPool is used after it has been closed.
+	ErrPoolClosed = errors.New("client pool is closed")
+
+	// ErrInvalidPoolSize is returned when the specified size of a ClientPool is zero or negative.
+	ErrInvalidPoolSize = errors.New("pool size must be greater than zero")
+
+	// ErrInvalidPoolDuration is returned when a specified ClientPool duration is negative.
+	ErrInvalidPoolDuration = errors.New("pool duration cannot be negative")
+
+	// ErrInvalidPoolLaneWeight is returned when the weight of a PoolLane is invalid.
+	ErrInvalidPoolLaneWeight = errors.New("pool lane weight is invalid")
+)
+
+type (
+	// PoolLane is the lane of a send of a ClientPool, which determines the priority of the send when
+	// waiting for a connection.
+	PoolLane int
+
+	// PoolOption is a function type that modifies the configuration or behavior of a ClientPool instance.
+	PoolOption func(*ClientPool) error
+
+	// ClientPool maintains a fixed number of persistent, authenticated connections to a SMTP server.
+	//
+	// Dialing a new connection for every batch of messages is expensive, especially when TLS and SMTP
+	// authentication are involved. A ClientPool keeps up to a given number of Client connections open
+	// and hands them out to concurrent senders. Connections are established lazily on first use,
+	// health checked after being idle, closed after an idle timeout and transparently re-established
+	// if they were lost.
+	//
+	// A ClientPool is safe for concurrent use by multiple goroutines.
+	ClientPool struct {
+		// clients is a buffered channel holding the currently unused pooled connections. Its capacity
+		// equals the size of the pool.
+		clients chan *pooledClient
+
+		// clientOpts holds the Option functions that are applied to each Client of the pool.
+		clientOpts []Option
+
+		// healthCheckInterval specifies the duration after which an idle connection is health checked
+		// with a NOOP command before it is used again.
+		healthCheckInterval time.Duration
+
+		// host is the hostname of the SMTP server the ClientPool connects to.
+		host string
+
+		// idleTimeout specifies the duration after which an idle connection is closed and re-established
+		// on next use.
+		idleTimeout time.Duration
+
+		// isClosed indicates whether the ClientPool has been closed.
+		isClosed bool
+
+		// laneCredits holds the number of connections that are still handed out to the waiting senders
+		// of each PoolLane in the current round, while senders of both lanes are waiting.
+		laneCredits [2]int
+
+		// laneMutex is used to synchronize the handout of connections to the waiting senders.
+		laneMutex sync.Mutex
+
+		// laneWaiters holds the channels of the senders of each PoolLane that are waiting for a
+		// connection, in the order of their arrival.
+		laneWaiters [2][]chan *pooledClient
+
+		// laneWeights holds the weights of PoolLaneInteractive and PoolLaneBulk.
+		laneWeights [2]int
+
+		// members holds all connections of the ClientPool, whether they are currently in use or not.
+		members []*pooledClient
+
+		// mutex is used to synchronize access to the state of the ClientPool.
+		mutex sync.RWMutex
+
+		// size is the maximum number of connections the ClientPool maintains.
+		size int
+	}
+
+	// pooledClient represents a single connection of a ClientPool.
+	pooledClient struct {
+		// client is the Client that holds the connection to the SMTP server.
+		client *Client
+
+		// lastUsed is the point in time the connection was last used.
+		lastUsed time.Time
+	}
+)
+
+// NewClientPool creates a new ClientPool for the provided host, configured with optional PoolOption
+// functions.
+//
+// The pool is initialized with DefaultPoolSize connections, DefaultPoolIdleTimeout and
+// DefaultPoolHealthCheckInterval, unless overridden by the provided PoolOption functions. The Clients
+// of the pool are created immediately, so that invalid Client options are reported early, but no
+// connection to the SMTP server is established until the pool is used for sending.
+//
+// Parameters:
+//   - host: The hostname of the SMTP server to connect to.
+//   - opts: Optional configuration functions to override the default settings.
+//
+// Returns:
+//   - A pointer to the initialized ClientPool.
+//   - An error if any of the options or the creation of the Clients fails.
+func NewClientPool(host string, opts ...PoolOption) (*ClientPool, error) {
+	pool := &ClientPool{
+		healthCheckInterval: DefaultPoolHealthCheckInterval,
+		host:                host,
+		idleTimeout:         DefaultPoolIdleTimeout,
+		laneWeights:         [2]int{DefaultPoolInteractiveWeight, DefaultPoolBulkWeight},
+		size:                DefaultPoolSize,
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(pool); err != nil {
+			return pool, err
+		}
+	}
+
+	pool.clients = make(chan *pooledClient, pool.size)
+	for i := 0; i < pool.size; i++ {
+		client, err := NewClient(host, pool.clientOpts...)
+		if err != nil {
+			return pool, err
+		}
+		member := &pooledClient{client: client}
+		pool.members = append(pool.members, member)
+		pool.clients <- member
+	}
+
+	return pool, nil
+}
+
+// WithPoolSize sets the number of persistent connections maintained by the ClientPool.
+//
+// Parameters:
+//   - size: The maximum number of concurrent connections to the SMTP server.
+//
+// Returns:
+//   - A PoolOption function that sets the pool size, or an error if the size is invalid.
+func WithPoolSize(size int) PoolOption {
+	return func(p *ClientPool) error {
+		if size < 1 {
+			return ErrInvalidPoolSize
+		}
+		p.size = size
+		return nil
+	}
+}
+
+// WithPoolIdleTimeout sets the duration after which an idle connection of the ClientPool is closed
+// and re-established on next use. An idle timeout of zero disables the timeout.
+//
+// Parameters:
+//   - timeout: The duration a connection may stay unused.
+//
+// Returns:
+//   - A PoolOption function that sets the idle timeout, or an error if the duration is negative.
+func WithPoolIdleTimeout(timeout time.Duration) PoolOption {
+	return func(p *ClientPool) error {
+		if timeout < 0 {
+			return ErrInvalidPoolDuration
+		}
+		p.idleTimeout = timeout
+		return nil
+	}
+}
+
+// WithPoolHealthCheckInterval sets the duration after which an idle connection of the ClientPool is
+// health checked with a NOOP command before it is used again. An interval of zero disables the
+// health checks of the pool. Please note, that the Client itself still performs its connection check
+// on each Send, unless it was configured WithoutNoop.
+//
+// Parameters:
+//   - interval: The duration after which an idle connection needs to be health checked.
+//
+// Returns:
+//   - A PoolOption function that sets the health check interval, or an error if the duration
+//     is negative.
+func WithPoolHealthCheckInterval(interval time.Duration) PoolOption {
+	return func(p *ClientPool) error {
+		if interval < 0 {
+			return ErrInvalidPoolDuration
+		}
+		p.healthCheckInterval = interval
+		return nil
+	}
+}
+
+// WithPoolClientOptions sets the Option functions that are applied to each Client of the ClientPool.
+//
+// This allows to configure the connections of the pool the same way as a single Client, i. e. with
+// WithPort, WithSMTPAuth, WithUsername or WithTLSPolicy.
+//
+// Parameters:
+//   - opts: The Option functions applied to each Client of the pool.
+//
+// Returns:
+//   - A PoolOption function that sets the Client options.
+func WithPoolClientOptions(opts ...Option) PoolOption {
+	return func(p *ClientPool) error {
+		p.clientOpts = append(p.clientOpts, opts...)
+		return nil
+	}
+}
+
+// WithPoolLaneWeights sets the weights of the PoolLaneInteractive and the PoolLaneBulk of the
+// ClientPool.
+//
+// While senders of both lanes are waiting for a connection, the released connections are handed out
+// in rounds of interactive plus bulk connections: interactive connections to the senders of
+// PoolLaneInteractive, followed by bulk connections to the senders of PoolLaneBulk. This way,
+// transactional mail sent with Send preempts bulk work sent with SendBulk on the shared connections,
+// while the bulk work still progresses. A bulk weight of zero gives the interactive lane strict
+// priority. If only one of the lanes is waiting, it gets all released connections. By default,
+// DefaultPoolInteractiveWeight and DefaultPoolBulkWeight are used.
+//
+// Parameters:
+//   - interactive: The weight of the PoolLaneInteractive. Must be greater than zero.
+//   - bulk: The weight of the PoolLaneBulk. Must not be negative.
+//
+// Returns:
+//   - A PoolOption function that sets the lane weights, or an error if a weight is invalid.
+func WithPoolLaneWeights(interactive, bulk int) PoolOption {
+	return func(p *ClientPool) error {
+		if interactive < 1 || bulk < 0 {
+			return ErrInvalidPoolLaneWeight
+		}
+		p.laneWeights = [2]int{interactive, bulk}
+		return nil
+	}
+}
+
+// Size returns the maximum number of connections maintained by the ClientPool.
+//
+// Returns:
+//   - The size of the ClientPool.
+func (p *ClientPool) Size() int {
+	return p.size
+}
+
+// Send sends one or more Msg using the connections of the ClientPool in the PoolLaneInteractive.
+//
+// The messages are distributed over the available connections of the pool and sent concurrently.
+// Connections that are not yet established, or that have been lost, are (re-)established before
+// use. For each of the provided Msg, a SendError is associated with the Msg in case of a
+// transmission or delivery error, so that the per-message result can be checked with Msg.HasSendError
+// and Msg.SendError.
+//
+// Parameters:
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error that aggregates any errors encountered during the sending process; otherwise, returns nil.
+func (p *ClientPool) Send(messages ...*Msg) error {
+	return p.SendWithContext(context.Background(), messages...)
+}
+
+// SendWithContext sends one or more Msg using the connections of the ClientPool, like Send. The
+// provided context.Context is used to control the waiting for available connections and the dialing
+// of new connections.
+//
+// Parameters:
+//   - ctx: The context.Context to control the connection retrieval and the dial.
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error that aggregates any errors encountered during the sending process; otherwise, returns nil.
+func (p *ClientPool) SendWithContext(ctx context.Context, messages ...*Msg) error {
+	return p.send(ctx, PoolLaneInteractive, messages...)
+}
+
+// SendBulk sends one or more Msg using the connections of the ClientPool in the PoolLaneBulk, like
+// Send. While messages of the PoolLaneInteractive are waiting for a connection, the bulk messages
+// only get their share of the released connections, as set with WithPoolLaneWeights.
+//
+// Parameters:
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error that aggregates any errors encountered during the sending process; otherwise, returns nil.
+func (p *ClientPool) SendBulk(messages ...*Msg) error {
+	return p.SendBulkWithContext(context.Background(), messages...)
+}
+
+// SendBulkWithContext sends one or more Msg using the connections of the ClientPool in the
+// PoolLaneBulk, like SendBulk. The provided context.Context is used to control the waiting for
+// available connections and the dialing of new connections.
+//
+// Parameters:
+//   - ctx: The context.Context to control the connection retrieval and the dial.
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error that aggregates any errors encountered during the sending process; otherwise, returns nil.
+func (p *ClientPool) SendBulkWithContext(ctx context.Context, messages ...*Msg) error {
+	return p.send(ctx, PoolLaneBulk, messages...)
+}
+
+// send sends one or more Msg concurrently using the connections of the ClientPool in the given
+// PoolLane.
+//
+// Parameters:
+//   - ctx: The context.Context to control the connection retrieval and the dial.
+//   - lane: The PoolLane in which the connections are acquired.
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - An error that aggregates any errors encountered during the sending process; otherwise, returns nil.
+func (p *ClientPool) send(ctx context.Context, lane PoolLane, messages ...*Msg) error {
+	if p.closed() {
+		return ErrPoolClosed
+	}
+
+	workers := p.size
+	if len(messages) < workers {
+		workers = len(messages)
+	}
+	queue := make(chan int, len(messages))
+	for id := range messages {
+		queue <- id
+	}
+	close(queue)
+
+	errs := make([]error, len(messages))
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				errs[id] = p.sendSingleMsg(ctx, lane, messages[id])
+			}
+		}()
+	}
+	wg.Wait()
+
+	var returnErrs []error
+	for _, err := range errs {
+		if err != nil {
+			returnErrs = append(returnErrs, err)
+		}
+	}
+	return joinErrors(returnErrs)
+}
+
+// UpdateAuth replaces the SMTP authentication settings of all Clients of the ClientPool, i. e. after
+// the credentials have been rotated.
+//
+// Messages that are currently being sent complete on the previous settings. Established connections
+// stay authenticated with the previous credentials until they are re-established, i. e. after the
+// idle timeout or a failed health check, at which point the new settings are used.
+//
+// Parameters:
+//   - authtype: The SMTPAuthType to be used for the Clients.
+//   - username: The username to be used for SMTP authentication.
+//   - password: The password to be used for SMTP authentication.
+func (p *ClientPool) UpdateAuth(authtype SMTPAuthType, username, password string) {
+	for _, member := range p.members {
+		member.client.UpdateAuth(authtype, username, password)
+	}
+}
+
+// UpdateTLSConfig replaces the tls.Config of all Clients of the ClientPool, i. e. after a client
+// certificate has been rotated. Like with UpdateAuth, the new tls.Config is applied when a connection
+// is re-established.
+//
+// Parameters:
+//   - tlsconfig: A pointer to the tls.Config struct to be used for the Clients. Must not be nil.
+//
+// Returns:
+//   - An error if the provided tls.Config is nil.
+func (p *ClientPool) UpdateTLSConfig(tlsconfig *tls.Config) error {
+	if tlsconfig == nil {
+		return ErrInvalidTLSConfig
+	}
+	for _, member := range p.members {
+		if err := member.client.UpdateTLSConfig(tlsconfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes all connections of the ClientPool. Connections that are currently in use are closed
+// as soon as they are returned to the pool. After Close the ClientPool cannot be used anymore.
+//
+// Returns:
+//   - An error if the pool was already closed; otherwise, returns nil.
+func (p *ClientPool) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.isClosed {
+		return ErrPoolClosed
+	}
+	p.isClosed = true
+
+	for {
+		select {
+		case pc := <-p.clients:
+			closeClient(pc.client)
+		default:
+			return nil
+		}
+	}
+}
+
+// sendSingleMsg sends a single Msg using a connection of the ClientPool.
+//
+// If the connection check of the Client fails while sending, the connection is re-established and
+// the Msg is sent once more.
+//
+// Parameters:
+//   - ctx: The context.Context to control the connection retrieval and the dial.
+//   - lane: The PoolLane in which the connection is acquired.
+//   - message: A pointer to the Msg to be sent.
+//
+// Returns:
+//   - An error if no connection could be acquired or the Msg could not be sent; otherwise, returns nil.
+func (p *ClientPool) sendSingleMsg(ctx context.Context, lane PoolLane, message *Msg) error {
+	pc, err := p.acquire(ctx, lane)
+	if err != nil {
+		message.sendError = &SendError{Reason: ErrConnCheck, errlist: []error{err}, isTemp: isTempError(err)}
+		return message.sendError
+	}
+	defer p.release(pc)
+
+	err = pc.client.Send(message)
+	var sendErr *SendError
+	if errors.As(err, &sendErr) && sendErr.Reason == ErrConnCheck {
+		closeClient(pc.client)
+		if err = pc.client.DialWithContext(ctx); err != nil {
+			message.sendError = &SendError{Reason: ErrConnCheck, errlist: []error{err}, isTemp: isTempError(err)}
+			return message.sendError
+		}
+		err = pc.client.Send(message)
+	}
+	if err != nil && message.sendError == nil {
+		message.sendError = err
+	}
+	return err
+}
+
+// acquire retrieves a connection from the ClientPool and makes sure it is usable.
+//
+// Connections that have been idle for longer than the idle timeout are closed and re-established.
+// Connections that have been idle for longer than the health check interval are checked with a
+// NOOP command and re-established if the check fails.
+//
+// Parameters:
+//   - ctx: The context.Context to control the connection retrieval and the dial.
+//   - lane: The PoolLane in which the sender waits for a connection, if none is available.
+//
+// Returns:
+//   - A pointer to the pooledClient holding an established connection.
+//   - An error if the pool is closed, the context is done or the dial fails.
+func (p *ClientPool) acquire(ctx context.Context, lane PoolLane) (*pooledClient, error) {
+	pc, err := p.wait(ctx, lane)
+	if err != nil {
+		return nil, err
+	}
+	if p.closed() {
+		p.release(pc)
+		return nil, ErrPoolClosed
+	}
+
+	client := pc.client
+	if client.smtpClient != nil && client.smtpClient.HasConnection() {
+		idle := time.Since(pc.lastUsed)
+		switch {
+		case p.idleTimeout > 0 && idle > p.idleTimeout:
+			closeClient(client)
+		case p.healthCheckInterval > 0 && idle > p.healthCheckInterval:
+			if err := client.smtpClient.Noop(); err != nil {
+				closeClient(client)
+			}
+		default:
+			return pc, nil
+		}
+	}
+	if client.smtpClient != nil && client.smtpClient.HasConnection() {
+		return pc, nil
+	}
+
+	if err := client.DialWithContext(ctx); err != nil {
+		closeClient(client)
+		p.release(pc)
+		return nil, err
+	}
+	return pc, nil
+}
+
+// release returns a connection to the ClientPool. If the pool has been closed in the meantime,
+// the connection is closed.
+//
+// Parameters:
+//   - pc: A pointer to the pooledClient to return to the pool.
+func (p *ClientPool) release(pc *pooledClient) {
+	pc.lastUsed = time.Now()
+	if p.closed() {
+		closeClient(pc.client)
+	}
+
+	p.laneMutex.Lock()
+	defer p.laneMutex.Unlock()
+	if lane, ok := p.nextLane(); ok {
+		waiter := p.laneWaiters[lane][0]
+		p.laneWaiters[lane] = p.laneWaiters[lane][1:]
+		waiter <- pc
+		return
+	}
+	p.clients <- pc
+}
+
+// wait returns an unused connection of the ClientPool, or waits in the given PoolLane until a
+// connection is released to it.
+//
+// Parameters:
+//   - ctx: The context.Context to control the waiting for a connection.
+//   - lane: The PoolLane in which the sender waits.
+//
+// Returns:
+//   - A pointer to the pooledClient.
+//   - The error of the context, if it is done before a connection is available.
+func (p *ClientPool) wait(ctx context.Context, lane PoolLane) (*pooledClient, error) {
+	p.laneMutex.Lock()
+	select {
+	case pc := <-p.clients:
+		p.laneMutex.Unlock()
+		return pc, nil
+	default:
+	}
+	waiter := make(chan *pooledClient, 1)
+	p.laneWaiters[lane] = append(p.laneWaiters[lane], waiter)
+	p.laneMutex.Unlock()
+
+	select {
+	case pc := <-waiter:
+		return pc, nil
+	case <-ctx.Done():
+	}
+
+	p.laneMutex.Lock()
+	for i, queued := range p.laneWaiters[lane] {
+		if queued == waiter {
+			p.laneWaiters[lane] = append(p.laneWaiters[lane][:i], p.laneWaiters[lane][i+1:]...)
+			p.laneMutex.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+	p.laneMutex.Unlock()
+
+	// The connection has been handed out while the context was done, so it is passed on.
+	p.release(<-waiter)
+	return nil, ctx.Err()
+}
+
+// nextLane returns the PoolLane whose first waiting sender gets the next released connection,
+// according to the lane weights. It must be called with the laneMutex held.
+//
+// Returns:
+//   - The PoolLane to hand the connection to.
+//   - False if no sender is waiting, true otherwise.
+func (p *ClientPool) nextLane() (PoolLane, bool) {
+	interactive := len(p.laneWaiters[PoolLaneInteractive]) > 0
+	bulk := len(p.laneWaiters[PoolLaneBulk]) > 0
+	switch {
+	case !interactive && !bulk:
+		return 0, false
+	case !bulk:
+		return PoolLaneInteractive, true
+	case !interactive:
+		return PoolLaneBulk, true
+	}
+	if p.laneCredits[PoolLaneInteractive] <= 0 && p.laneCredits[PoolLaneBulk] <= 0 {
+		p.laneCredits = p.laneWeights
+	}
+	if p.laneCredits[PoolLaneInteractive] > 0 {
+		p.laneCredits[PoolLaneInteractive]--
+		return PoolLaneInteractive, true
+	}
+	p.laneCredits[PoolLaneBulk]--
+	return PoolLaneBulk, true
+}
+
+// closed reports whether the ClientPool has been closed.
+//
+// Returns:
+//   - True if the pool has been closed, false otherwise.
+func (p *ClientPool) closed() bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.isClosed
+}
+
+// closeClient closes the connection of the given Client. If the connection cannot be closed
+// gracefully using the QUIT command, the underlying connection is closed forcefully.
+//
+// Parameters:
+//   - client: A pointer to the Client whose connection shall be closed.
+func closeClient(client *Client) {
+	if err := client.Close(); err != nil && client.smtpClient != nil {
+		_ = client.smtpClient.Close()
+	}
+}
@@ -0,0 +1,382 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewClientPool(t *testing.T) {
+	t.Run("new pool with defaults", func(t *testing.T) {
+		pool, err := NewClientPool(DefaultHost)
+		if err != nil {
+			t.Fatalf("failed to create new client pool: %s", err)
+		}
+		if pool.Size() != DefaultPoolSize {
+			t.Errorf("expected pool size: %d, got: %d", DefaultPoolSize, pool.Size())
+		}
+		if pool.idleTimeout != DefaultPoolIdleTimeout {
+			t.Errorf("expected idle timeout: %s, got: %s", DefaultPoolIdleTimeout, pool.idleTimeout)
+		}
+		if pool.healthCheckInterval != DefaultPoolHealthCheckInterval {
+			t.Errorf("expected health check interval: %s, got: %s", DefaultPoolHealthCheckInterval,
+				pool.healthCheckInterval)
+		}
+		if len(pool.clients) != DefaultPoolSize {
+			t.Errorf("expected %d clients in pool, got: %d", DefaultPoolSize, len(pool.clients))
+		}
+	})
+	t.Run("new pool with nil option", func(t *testing.T) {
+		if _, err := NewClientPool(DefaultHost, nil); err != nil {
+			t.Errorf("failed to create new client pool: %s", err)
+		}
+	})
+	t.Run("new pool with custom options", func(t *testing.T) {
+		pool, err := NewClientPool(DefaultHost, WithPoolSize(2), WithPoolIdleTimeout(time.Second),
+			WithPoolHealthCheckInterval(0), WithPoolClientOptions(WithPort(2525)))
+		if err != nil {
+			t.Fatalf("failed to create new client pool: %s", err)
+		}
+		if pool.Size() != 2 {
+			t.Errorf("expected pool size: %d, got: %d", 2, pool.Size())
+		}
+		if pool.idleTimeout != time.Second {
+			t.Errorf("expected idle timeout: %s, got: %s", time.Second, pool.idleTimeout)
+		}
+		if pool.healthCheckInterval != 0 {
+			t.Errorf("expected health check interval to be disabled, got: %s", pool.healthCheckInterval)
+		}
+		pc := <-pool.clients
+		if pc.client.port != 2525 {
+			t.Errorf("expected client port: %d, got: %d", 2525, pc.client.port)
+		}
+	})
+	t.Run("new pool with invalid options", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			option PoolOption
+			want   error
+		}{
+			{"zero pool size", WithPoolSize(0), ErrInvalidPoolSize},
+			{"negative idle timeout", WithPoolIdleTimeout(-1), ErrInvalidPoolDuration},
+			{"negative health check interval", WithPoolHealthCheckInterval(-1), ErrInvalidPoolDuration},
+			{"invalid client option", WithPoolClientOptions(WithPort(100000)), ErrInvalidPort},
+			{"zero interactive lane weight", WithPoolLaneWeights(0, 1), ErrInvalidPoolLaneWeight},
+			{"negative bulk lane weight", WithPoolLaneWeights(1, -1), ErrInvalidPoolLaneWeight},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := NewClientPool(DefaultHost, tt.option)
+				if !errors.Is(err, tt.want) {
+					t.Errorf("expected error: %s, got: %s", tt.want, err)
+				}
+			})
+		}
+	})
+	t.Run("new pool without hostname", func(t *testing.T) {
+		if _, err := NewClientPool(""); !errors.Is(err, ErrNoHostname) {
+			t.Errorf("expected error: %s, got: %s", ErrNoHostname, err)
+		}
+	})
+}
+
+func TestClientPool_Send(t *testing.T) {
+	t.Run("send multiple messages over multiple connections", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet:     featureSet,
+				ListenPort:     serverPort,
+				HandleParallel: true,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		pool, err := NewClientPool(DefaultHost, WithPoolSize(2),
+			WithPoolClientOptions(WithPort(serverPort), WithTLSPolicy(NoTLS)))
+		if err != nil {
+			t.Fatalf("failed to create new client pool: %s", err)
+		}
+		t.Cleanup(func() {
+			if err := pool.Close(); err != nil {
+				t.Errorf("failed to close client pool: %s", err)
+			}
+		})
+
+		messages := []*Msg{testMessage(t), testMessage(t), testMessage(t), testMessage(t), testMessage(t)}
+		if err = pool.Send(messages...); err != nil {
+			t.Fatalf("failed to send messages: %s", err)
+		}
+		for i, message := range messages {
+			if !message.IsDelivered() {
+				t.Errorf("expected message %d to be delivered", i)
+			}
+		}
+		if err = pool.Send(testMessage(t)); err != nil {
+			t.Errorf("failed to send message on re-used connection: %s", err)
+		}
+		bulk := []*Msg{testMessage(t), testMessage(t), testMessage(t)}
+		if err = pool.SendBulk(bulk...); err != nil {
+			t.Fatalf("failed to send bulk messages: %s", err)
+		}
+		for i, message := range bulk {
+			if !message.IsDelivered() {
+				t.Errorf("expected bulk message %d to be delivered", i)
+			}
+		}
+	})
+	t.Run("send with per-message errors", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		pool, err := NewClientPool(DefaultHost, WithPoolSize(1),
+			WithPoolClientOptions(WithPort(serverPort), WithTLSPolicy(NoTLS)))
+		if err != nil {
+			t.Fatalf("failed to create new client pool: %s", err)
+		}
+		t.Cleanup(func() {
+			_ = pool.Close()
+		})
+
+		invalid := testMessage(t)
+		if err = invalid.To("invalid@domain.tld"); err != nil {
+			t.Fatalf("failed to set recipient: %s", err)
+		}
+		valid := testMessage(t)
+		if err = pool.Send(invalid, valid); err == nil {
+			t.Fatal("expected send to fail for invalid recipient")
+		}
+		if !invalid.HasSendError() {
+			t.Error("expected invalid message to have a send error")
+		}
+		if valid.HasSendError() {
+			t.Errorf("expected valid message to have no send error, got: %s", valid.SendError())
+		}
+		if !valid.IsDelivered() {
+			t.Error("expected valid message to be delivered")
+		}
+	})
+	t.Run("send re-establishes lost connections", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		pool, err := NewClientPool(DefaultHost, WithPoolSize(1), WithPoolIdleTimeout(0),
+			WithPoolHealthCheckInterval(0), WithPoolClientOptions(WithPort(serverPort), WithTLSPolicy(NoTLS)))
+		if err != nil {
+			t.Fatalf("failed to create new client pool: %s", err)
+		}
+		t.Cleanup(func() {
+			_ = pool.Close()
+		})
+
+		if err = pool.Send(testMessage(t)); err != nil {
+			t.Fatalf("failed to send message: %s", err)
+		}
+		pc := <-pool.clients
+		if err = pc.client.Close(); err != nil {
+			t.Fatalf("failed to close client connection: %s", err)
+		}
+		pool.clients <- pc
+		if err = pool.Send(testMessage(t)); err != nil {
+			t.Errorf("failed to send message after connection loss: %s", err)
+		}
+	})
+	t.Run("send fails on dial error", func(t *testing.T) {
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		pool, err := NewClientPool(DefaultHost, WithPoolSize(1),
+			WithPoolClientOptions(WithPort(serverPort), WithTLSPolicy(NoTLS), WithTimeout(time.Millisecond*200)))
+		if err != nil {
+			t.Fatalf("failed to create new client pool: %s", err)
+		}
+		message := testMessage(t)
+		if err = pool.Send(message); err == nil {
+			t.Fatal("expected send to fail without server")
+		}
+		var sendErr *SendError
+		if !errors.As(message.SendError(), &sendErr) || sendErr.Reason != ErrConnCheck {
+			t.Errorf("expected SendError with reason %s, got: %s", ErrConnCheck, message.SendError())
+		}
+	})
+	t.Run("send fails on closed pool", func(t *testing.T) {
+		pool, err := NewClientPool(DefaultHost)
+		if err != nil {
+			t.Fatalf("failed to create new client pool: %s", err)
+		}
+		if err = pool.Close(); err != nil {
+			t.Fatalf("failed to close client pool: %s", err)
+		}
+		if err = pool.Send(testMessage(t)); !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("expected error: %s, got: %s", ErrPoolClosed, err)
+		}
+		if err = pool.Close(); !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("expected error on double close: %s, got: %s", ErrPoolClosed, err)
+		}
+	})
+	t.Run("send with cancelled context", func(t *testing.T) {
+		pool, err := NewClientPool(DefaultHost, WithPoolSize(1))
+		if err != nil {
+			t.Fatalf("failed to create new client pool: %s", err)
+		}
+		pc := <-pool.clients
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		message := testMessage(t)
+		if err = pool.SendWithContext(ctx, message); err == nil {
+			t.Fatal("expected send to fail with cancelled context")
+		}
+		var sendErr *SendError
+		if !errors.As(message.SendError(), &sendErr) || sendErr.Reason != ErrConnCheck {
+			t.Errorf("expected SendError with reason %s, got: %s", ErrConnCheck, message.SendError())
+		}
+		pool.clients <- pc
+	})
+}
+
+func TestClientPool_lanes(t *testing.T) {
+	tests := []struct {
+		name        string
+		interactive int
+		bulk        int
+		want        []PoolLane
+	}{
+		{
+			"interactive lane has strict priority", 1, 0,
+			[]PoolLane{PoolLaneInteractive, PoolLaneInteractive, PoolLaneInteractive, PoolLaneBulk, PoolLaneBulk, PoolLaneBulk},
+		},
+		{
+			"lanes share connections by weight", 2, 1,
+			[]PoolLane{PoolLaneInteractive, PoolLaneInteractive, PoolLaneBulk, PoolLaneInteractive, PoolLaneBulk, PoolLaneBulk},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := NewClientPool(DefaultHost, WithPoolSize(1), WithPoolLaneWeights(tt.interactive, tt.bulk))
+			if err != nil {
+				t.Fatalf("failed to create new client pool: %s", err)
+			}
+			pc := <-pool.clients
+
+			type grant struct {
+				lane PoolLane
+				pc   *pooledClient
+			}
+			grants := make(chan grant)
+			for _, lane := range []PoolLane{PoolLaneBulk, PoolLaneInteractive} {
+				for i := 0; i < 3; i++ {
+					go func(lane PoolLane) {
+						waiting, err := pool.wait(context.Background(), lane)
+						if err != nil {
+							t.Errorf("failed to wait for connection: %s", err)
+							return
+						}
+						grants <- grant{lane: lane, pc: waiting}
+					}(lane)
+				}
+			}
+			for {
+				pool.laneMutex.Lock()
+				waiters := len(pool.laneWaiters[PoolLaneInteractive]) + len(pool.laneWaiters[PoolLaneBulk])
+				pool.laneMutex.Unlock()
+				if waiters == 6 {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			for i, want := range tt.want {
+				pool.release(pc)
+				got := <-grants
+				if got.lane != want {
+					t.Errorf("expected connection %d to be handed to lane %d, got: %d", i, want, got.lane)
+				}
+				pc = got.pc
+			}
+		})
+	}
+	t.Run("cancelled waiter leaves the lane", func(t *testing.T) {
+		pool, err := NewClientPool(DefaultHost, WithPoolSize(1))
+		if err != nil {
+			t.Fatalf("failed to create new client pool: %s", err)
+		}
+		pc := <-pool.clients
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+		if _, err = pool.wait(ctx, PoolLaneBulk); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected error: %s, got: %s", context.DeadlineExceeded, err)
+		}
+		if len(pool.laneWaiters[PoolLaneBulk]) != 0 {
+			t.Errorf("expected no waiters, got: %d", len(pool.laneWaiters[PoolLaneBulk]))
+		}
+		pool.release(pc)
+		if len(pool.clients) != 1 {
+			t.Errorf("expected connection to be returned to the pool, got: %d", len(pool.clients))
+		}
+	})
+}
+
+func TestClientPool_UpdateAuth(t *testing.T) {
+	pool, err := NewClientPool(DefaultHost, WithPoolSize(2))
+	if err != nil {
+		t.Fatalf("failed to create new client pool: %s", err)
+	}
+	t.Cleanup(func() { _ = pool.Close() })
+	// Take a connection out of the pool, so that both idle and in-use connections are updated
+	pc := <-pool.clients
+	pool.UpdateAuth(SMTPAuthPlain, "rotated", "secret")
+	if err = pool.UpdateTLSConfig(nil); !errors.Is(err, ErrInvalidTLSConfig) {
+		t.Errorf("expected error: %s, got: %s", ErrInvalidTLSConfig, err)
+	}
+	tlsConfig := &tls.Config{ServerName: DefaultHost, MinVersion: tls.VersionTLS12}
+	if err = pool.UpdateTLSConfig(tlsConfig); err != nil {
+		t.Fatalf("failed to update TLS config: %s", err)
+	}
+	pool.clients <- pc
+	for i, member := range pool.members {
+		if member.client.user != "rotated" || member.client.smtpAuthType != SMTPAuthPlain {
+			t.Errorf("expected credentials of client %d to be updated", i)
+		}
+		if member.client.tlsconfig != tlsConfig {
+			t.Errorf("expected TLS config of client %d to be updated", i)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// List of ProfileConstraint values
+const (
+	// ConstraintMessageSize is the constraint of the maximum size of a Msg.
+	ConstraintMessageSize ProfileConstraint = iota
+
+	// ConstraintRecipients is the constraint of the maximum number of recipients of a Msg.
+	ConstraintRecipients
+
+	// ConstraintAttachmentType is the constraint of the file types that must not be attached to a Msg.
+	ConstraintAttachmentType
+
+	// ConstraintHeader is the constraint of the headers that a Msg must have.
+	ConstraintHeader
+)
+
+type (
+	// ProfileConstraint represents the kind of constraint of a ProviderProfile.
+	ProfileConstraint int
+
+	// ProviderProfile holds the constraints that a mail provider imposes on the messages it accepts
+	// for sending, as checked by Msg.ValidateFor.
+	//
+	// The predefined profiles reflect the documented limits of the providers at the time of writing.
+	// Providers change their limits and the limits of paid plans often differ, so a profile can be
+	// copied and adjusted as needed. A zero value of a limit means that it is not checked.
+	ProviderProfile struct {
+		// Name is the name of the provider, which is part of the ProfileViolation messages.
+		Name string
+
+		// MaxMessageSize is the maximum size of a Msg in bytes, as reported by Msg.EstimatedSize.
+		MaxMessageSize int64
+
+		// MaxRecipients is the maximum number of "To", "Cc" and "Bcc" recipients of a Msg.
+		MaxRecipients int
+
+		// ForbiddenExtensions holds the file extensions of the attachments and embeds that are
+		// rejected by the provider, i. e. ".exe". The comparison is case-insensitive.
+		ForbiddenExtensions []string
+
+		// RequiredHeaders holds the headers that a Msg must have, i. e. HeaderListUnsubscribe for bulk
+		// mail. The "Date", "Message-ID" and "MIME-Version" headers do not need to be listed, since
+		// they are added when the Msg is written.
+		RequiredHeaders []Header
+	}
+
+	// ProfileViolation represents a constraint of a ProviderProfile that a Msg violates.
+	ProfileViolation struct {
+		// Constraint is the kind of the violated constraint.
+		Constraint ProfileConstraint
+
+		// Detail describes the violation and what needs to be changed, i. e. the name of a
+		// forbidden attachment.
+		Detail string
+
+		// Profile is the name of the ProviderProfile.
+		Profile string
+	}
+)
+
+var (
+	// ProfileGmail is the ProviderProfile of Gmail.
+	//
+	// References:
+	//   - https://support.google.com/mail/answer/6590
+	//   - https://support.google.com/a/answer/166852
+	ProfileGmail = ProviderProfile{
+		Name:           "Gmail",
+		MaxMessageSize: 25 * 1024 * 1024,
+		MaxRecipients:  100,
+		ForbiddenExtensions: []string{
+			".ade", ".adp", ".apk", ".appx", ".appxbundle", ".bat", ".cab", ".chm", ".cmd", ".com", ".cpl",
+			".diagcab", ".diagcfg", ".diagpack", ".dll", ".dmg", ".ex", ".ex_", ".exe", ".hta", ".img",
+			".ins", ".iso", ".isp", ".jar", ".jnlp", ".js", ".jse", ".lib", ".lnk", ".mde", ".mjs", ".msc",
+			".msi", ".msix", ".msixbundle", ".msp", ".mst", ".nsh", ".pif", ".ps1", ".scr", ".sct", ".shb",
+			".sys", ".vb", ".vbe", ".vbs", ".vhd", ".vxd", ".wsc", ".wsf", ".wsh", ".xll",
+		},
+	}
+
+	// ProfileOutlook is the ProviderProfile of Outlook.com.
+	//
+	// References:
+	//   - https://support.microsoft.com/en-us/office/blocked-attachments-in-outlook-434752e1-02d3-4e90-9124-8b81e49a8519
+	ProfileOutlook = ProviderProfile{
+		Name:           "Outlook.com",
+		MaxMessageSize: 20 * 1024 * 1024,
+		MaxRecipients:  500,
+		ForbiddenExtensions: []string{
+			".ade", ".adp", ".app", ".appcontent-ms", ".application", ".appref-ms", ".asp", ".aspx", ".asx",
+			".bas", ".bat", ".bgi", ".cab", ".cer", ".chm", ".cmd", ".cnt", ".com", ".cpl", ".crt", ".csh",
+			".der", ".diagcab", ".exe", ".fxp", ".gadget", ".grp", ".hlp", ".hpj", ".hta", ".htc", ".inf",
+			".ins", ".iso", ".isp", ".its", ".jar", ".jnlp", ".js", ".jse", ".ksh", ".lnk", ".mad", ".maf",
+			".mag", ".mam", ".maq", ".mar", ".mas", ".mat", ".mau", ".mav", ".maw", ".mcf", ".mda", ".mdb",
+			".mde", ".mdt", ".mdw", ".mdz", ".msc", ".msh", ".msh1", ".msh2", ".mshxml", ".msh1xml",
+			".msh2xml", ".msi", ".msp", ".mst", ".msu", ".ops", ".osd", ".pcd", ".pif", ".pl", ".plg",
+			".prf", ".prg", ".printerexport", ".ps1", ".ps1xml", ".ps2", ".ps2xml", ".psc1", ".psc2",
+			".psd1", ".psdm1", ".pst", ".py", ".pyc", ".pyo", ".pyw", ".pyz", ".pyzw", ".reg", ".scf",
+			".scr", ".sct", ".settingcontent-ms", ".shb", ".shs", ".theme", ".tmp", ".udl", ".url", ".vb",
+			".vbe", ".vbp", ".vbs", ".vhd", ".vhdx", ".vsmacros", ".vsw", ".webpnp", ".website", ".ws",
+			".wsb", ".wsc", ".wsf", ".wsh", ".xbap", ".xll", ".xnk",
+		},
+	}
+
+	// ProfileSES is the ProviderProfile of Amazon Simple Email Service.
+	//
+	// References:
+	//   - https://docs.aws.amazon.com/ses/latest/dg/quotas.html
+	//   - https://docs.aws.amazon.com/ses/latest/dg/mime-types.html
+	ProfileSES = ProviderProfile{
+		Name:           "Amazon SES",
+		MaxMessageSize: 40 * 1024 * 1024,
+		MaxRecipients:  50,
+		ForbiddenExtensions: []string{
+			".ade", ".adp", ".app", ".asp", ".bas", ".bat", ".cer", ".chm", ".cmd", ".com", ".cpl", ".crt",
+			".csh", ".der", ".exe", ".fxp", ".gadget", ".hlp", ".hta", ".inf", ".ins", ".isp", ".its", ".js",
+			".jse", ".ksh", ".lib", ".lnk", ".mad", ".maf", ".mag", ".mam", ".maq", ".mar", ".mas", ".mat",
+			".mau", ".mav", ".maw", ".mda", ".mdb", ".mde", ".mdt", ".mdw", ".mdz", ".msc", ".msh", ".msh1",
+			".msh2", ".mshxml", ".msh1xml", ".msh2xml", ".msi", ".msp", ".mst", ".ops", ".pcd", ".pif",
+			".plg", ".prf", ".prg", ".reg", ".scf", ".scr", ".sct", ".shb", ".shs", ".sys", ".ps1",
+			".ps1xml", ".ps2", ".ps2xml", ".psc1", ".psc2", ".tmp", ".url", ".vb", ".vbe", ".vbs", ".vps",
+			".vsmacros", ".vss", ".vst", ".vsw", ".vxd", ".ws", ".wsc", ".wsf", ".wsh", ".xnk",
+		},
+	}
+)
+
+// ValidateFor checks the Msg against the constraints of the given ProviderProfile.
+//
+// This allows to detect messages that would be rejected or silently dropped by a provider, before
+// they are sent. All constraints are checked, so that all violations can be fixed at once. The
+// message size is determined with Msg.EstimatedSize, which renders the Msg.
+//
+// Parameters:
+//   - profile: The ProviderProfile to check the Msg against.
+//
+// Returns:
+//   - A ProfileViolation for each violated constraint, or nil if the Msg satisfies the profile.
+func (m *Msg) ValidateFor(profile ProviderProfile) []ProfileViolation {
+	var violations []ProfileViolation
+	addViolation := func(constraint ProfileConstraint, format string, args ...interface{}) {
+		violations = append(violations, ProfileViolation{
+			Constraint: constraint, Detail: fmt.Sprintf(format, args...), Profile: profile.Name,
+		})
+	}
+
+	if profile.MaxRecipients > 0 {
+		rcpts, _ := m.GetRecipients()
+		if len(rcpts) > profile.MaxRecipients {
+			addViolation(ConstraintRecipients, "%d recipients exceed the limit of %d recipients, split the "+
+				"message into multiple messages", len(rcpts), profile.MaxRecipients)
+		}
+	}
+	for _, file := range policyFiles(m) {
+		extension := strings.ToLower(filepath.Ext(file.Name))
+		for _, forbidden := range profile.ForbiddenExtensions {
+			if extension != "" && extension == strings.ToLower(forbidden) {
+				addViolation(ConstraintAttachmentType, "file %q has the forbidden extension %q, remove it "+
+					"or share it with a link", file.Name, extension)
+				break
+			}
+		}
+	}
+	for _, header := range profile.RequiredHeaders {
+		if len(m.GetGenHeader(header)) == 0 {
+			if _, ok := m.preformHeader[header]; !ok {
+				addViolation(ConstraintHeader, "required header %q is missing", header)
+			}
+		}
+	}
+	if profile.MaxMessageSize > 0 {
+		if size := m.EstimatedSize(); size > profile.MaxMessageSize {
+			addViolation(ConstraintMessageSize, "message size of %d bytes exceeds the limit of %d bytes, "+
+				"reduce the size of the attachments", size, profile.MaxMessageSize)
+		}
+	}
+	return violations
+}
+
+// Error satisfies the error interface for the ProfileViolation type.
+func (v ProfileViolation) Error() string {
+	return fmt.Sprintf("%s: %s constraint violated: %s", v.Profile, v.Constraint, v.Detail)
+}
+
+// String satisfies the fmt.Stringer interface for the ProfileConstraint type.
+func (c ProfileConstraint) String() string {
+	switch c {
+	case ConstraintMessageSize:
+		return "message size"
+	case ConstraintRecipients:
+		return "recipients"
+	case ConstraintAttachmentType:
+		return "attachment type"
+	case ConstraintHeader:
+		return "header"
+	default:
+		return "unknown"
+	}
+}
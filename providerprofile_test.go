@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMsg_ValidateFor(t *testing.T) {
+	t.Run("valid message", func(t *testing.T) {
+		message := testMessage(t)
+		message.AttachFile("testdata/attachment.txt")
+		for _, profile := range []ProviderProfile{ProfileGmail, ProfileOutlook, ProfileSES} {
+			if violations := message.ValidateFor(profile); violations != nil {
+				t.Errorf("expected no violations for %s, got: %v", profile.Name, violations)
+			}
+		}
+	})
+	t.Run("too many recipients", func(t *testing.T) {
+		message := testMessage(t)
+		rcpts := make([]string, 0, ProfileSES.MaxRecipients+1)
+		for i := 0; i <= ProfileSES.MaxRecipients; i++ {
+			rcpts = append(rcpts, fmt.Sprintf("rcpt%d@example.com", i))
+		}
+		if err := message.To(rcpts...); err != nil {
+			t.Fatalf("failed to set TO addresses: %s", err)
+		}
+		violations := message.ValidateFor(ProfileSES)
+		if len(violations) != 1 {
+			t.Fatalf("expected 1 violation, got: %v", violations)
+		}
+		if violations[0].Constraint != ConstraintRecipients {
+			t.Errorf("expected constraint %s, got: %s", ConstraintRecipients, violations[0].Constraint)
+		}
+		if !strings.HasPrefix(violations[0].Error(), "Amazon SES: recipients constraint violated: 51 recipients") {
+			t.Errorf("unexpected violation message: %s", violations[0].Error())
+		}
+		if violations = message.ValidateFor(ProfileGmail); violations != nil {
+			t.Errorf("expected no violations for Gmail, got: %v", violations)
+		}
+	})
+	t.Run("forbidden attachment types", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.AttachReader("setup.EXE", strings.NewReader("binary")); err != nil {
+			t.Fatalf("failed to attach file: %s", err)
+		}
+		if err := message.EmbedReader("script.js", strings.NewReader("alert(1)")); err != nil {
+			t.Fatalf("failed to embed file: %s", err)
+		}
+		if err := message.AttachReader("README", strings.NewReader("text")); err != nil {
+			t.Fatalf("failed to attach file: %s", err)
+		}
+		violations := message.ValidateFor(ProfileGmail)
+		if len(violations) != 2 {
+			t.Fatalf("expected 2 violations, got: %v", violations)
+		}
+		for i, name := range []string{"setup.EXE", "script.js"} {
+			if violations[i].Constraint != ConstraintAttachmentType {
+				t.Errorf("expected constraint %s, got: %s", ConstraintAttachmentType, violations[i].Constraint)
+			}
+			if !strings.Contains(violations[i].Detail, name) {
+				t.Errorf("expected violation to name file %q, got: %s", name, violations[i].Detail)
+			}
+		}
+	})
+	t.Run("required headers", func(t *testing.T) {
+		profile := ProviderProfile{
+			Name:            "bulk",
+			RequiredHeaders: []Header{HeaderListUnsubscribe, HeaderListUnsubscribePost},
+		}
+		message := testMessage(t)
+		message.SetGenHeader(HeaderListUnsubscribe, "<https://example.com/unsubscribe>")
+		violations := message.ValidateFor(profile)
+		if len(violations) != 1 {
+			t.Fatalf("expected 1 violation, got: %v", violations)
+		}
+		if violations[0].Constraint != ConstraintHeader {
+			t.Errorf("expected constraint %s, got: %s", ConstraintHeader, violations[0].Constraint)
+		}
+		if !strings.Contains(violations[0].Detail, string(HeaderListUnsubscribePost)) {
+			t.Errorf("expected violation to name the missing header, got: %s", violations[0].Detail)
+		}
+		message.SetGenHeaderPreformatted(HeaderListUnsubscribePost, "List-Unsubscribe=One-Click")
+		if violations = message.ValidateFor(profile); violations != nil {
+			t.Errorf("expected no violations, got: %v", violations)
+		}
+	})
+	t.Run("message size exceeded", func(t *testing.T) {
+		profile := ProviderProfile{Name: "small", MaxMessageSize: 1024}
+		message := testMessage(t)
+		if violations := message.ValidateFor(profile); violations != nil {
+			t.Fatalf("expected no violations, got: %v", violations)
+		}
+		if err := message.AttachReader("large.txt", strings.NewReader(strings.Repeat("a", 2048))); err != nil {
+			t.Fatalf("failed to attach file: %s", err)
+		}
+		violations := message.ValidateFor(profile)
+		if len(violations) != 1 {
+			t.Fatalf("expected 1 violation, got: %v", violations)
+		}
+		if violations[0].Constraint != ConstraintMessageSize {
+			t.Errorf("expected constraint %s, got: %s", ConstraintMessageSize, violations[0].Constraint)
+		}
+	})
+}
+
+func TestProfileConstraint_String(t *testing.T) {
+	tests := []struct {
+		constraint ProfileConstraint
+		want       string
+	}{
+		{ConstraintMessageSize, "message size"},
+		{ConstraintRecipients, "recipients"},
+		{ConstraintAttachmentType, "attachment type"},
+		{ConstraintHeader, "header"},
+		{ProfileConstraint(99), "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.constraint.String(); got != tt.want {
+				t.Errorf("expected %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
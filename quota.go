@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+type (
+	// QuotaManager is an interface for enforcing sending quotas inside the Client.
+	//
+	// The QuotaManager is consulted before each Msg is transmitted to the SMTP server, which allows
+	// multi-tenant platforms to enforce per-customer limits on the number of messages, recipients or
+	// bytes without wrapping the Client. Implementations must be safe for concurrent use if the
+	// QuotaManager is shared between multiple Client instances.
+	QuotaManager interface {
+		// CheckQuota is called before a Msg is sent. A non-nil error aborts the delivery of the Msg
+		// with a SendError of reason ErrQuotaExceeded, which wraps the returned error.
+		CheckQuota(usage QuotaUsage) error
+
+		// RecordUsage is called after the delivery of a Msg that passed CheckQuota, with the error of
+		// the delivery. A nil err indicates that the Msg was accepted by the SMTP server.
+		RecordUsage(usage QuotaUsage, err error)
+	}
+
+	// QuotaUsage represents the resources a single Msg consumes from the quota of its sender.
+	QuotaUsage struct {
+		// Recipients is the number of envelope recipients of the Msg.
+		Recipients int
+
+		// Sender is the envelope sender address of the Msg.
+		Sender string
+
+		// Size is the estimated size of the Msg in bytes, as returned by Msg.EstimatedSize.
+		Size int64
+
+		// Username is the username the Client authenticates with, if any.
+		Username string
+	}
+)
+
+// WithQuotaManager sets a QuotaManager that is consulted by the Client before each Msg is sent.
+//
+// The QuotaManager receives the sender identity, the number of recipients and the estimated size of
+// the Msg. If it rejects the Msg, the Msg is not transmitted and a temporary SendError of reason
+// ErrQuotaExceeded is returned, since quotas typically reset over time.
+//
+// Parameters:
+//   - manager: The QuotaManager to consult before each Msg is sent.
+//
+// Returns:
+//   - An Option function that sets the QuotaManager for the Client.
+func WithQuotaManager(manager QuotaManager) Option {
+	return func(c *Client) error {
+		if manager == nil {
+			return ErrQuotaManagerIsNil
+		}
+		c.quotaManager = manager
+		return nil
+	}
+}
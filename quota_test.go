@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testQuotaManager is a QuotaManager that allows a fixed number of messages.
+type testQuotaManager struct {
+	mutex    sync.Mutex
+	checked  []QuotaUsage
+	limit    int
+	recorded []error
+}
+
+var errTestQuotaExceeded = errors.New("message quota exceeded")
+
+func (q *testQuotaManager) CheckQuota(usage QuotaUsage) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.checked = append(q.checked, usage)
+	if len(q.recorded) >= q.limit {
+		return errTestQuotaExceeded
+	}
+	return nil
+}
+
+func (q *testQuotaManager) RecordUsage(_ QuotaUsage, err error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.recorded = append(q.recorded, err)
+}
+
+func TestWithQuotaManager(t *testing.T) {
+	t.Run("nil manager fails", func(t *testing.T) {
+		_, err := NewClient(DefaultHost, WithQuotaManager(nil))
+		if !errors.Is(err, ErrQuotaManagerIsNil) {
+			t.Errorf("expected error: %s, got: %s", ErrQuotaManagerIsNil, err)
+		}
+	})
+	t.Run("quota is enforced before sending", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+		ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+		t.Cleanup(cancelDial)
+
+		manager := &testQuotaManager{limit: 1}
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS),
+			WithQuotaManager(manager))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(ctxDial); err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				t.Skip("failed to connect to the test server due to timeout")
+			}
+			t.Fatalf("failed to connect to the test server: %s", err)
+		}
+		t.Cleanup(func() {
+			if err := client.Close(); err != nil {
+				t.Errorf("failed to close client: %s", err)
+			}
+		})
+		message := testMessage(t)
+		if err = client.Send(message); err != nil {
+			t.Fatalf("failed to send first message: %s", err)
+		}
+		err = client.Send(testMessage(t))
+		var sendErr *SendError
+		if !errors.As(err, &sendErr) || sendErr.Reason != ErrQuotaExceeded {
+			t.Fatalf("expected SendError with reason %s, got: %s", ErrQuotaExceeded, err)
+		}
+		if errlist := sendErr.Unwrap(); !sendErr.IsTemp() || len(errlist) != 1 ||
+			!errors.Is(errlist[0], errTestQuotaExceeded) {
+			t.Errorf("expected temporary error wrapping the quota error, got: %s", err)
+		}
+
+		if len(manager.checked) != 2 || len(manager.recorded) != 1 || manager.recorded[0] != nil {
+			t.Fatalf("unexpected quota calls, checked: %+v, recorded: %v", manager.checked, manager.recorded)
+		}
+		usage := manager.checked[0]
+		if usage.Sender != TestSenderValid || usage.Recipients != 1 || usage.Size != message.EstimatedSize() {
+			t.Errorf("unexpected quota usage: %+v", usage)
+		}
+	})
+}
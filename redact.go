@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// DefaultRedactionReplacement is the replacement text of a RedactionRule without a Replacement.
+const DefaultRedactionReplacement = "[REDACTED]"
+
+// List of the names of the RedactionRule values returned by DefaultRedactionRules
+const (
+	// RedactionRuleAWSAccessKey is the name of the rule that masks AWS access key IDs.
+	RedactionRuleAWSAccessKey = "aws-access-key"
+
+	// RedactionRuleBearerToken is the name of the rule that masks bearer tokens of authorization
+	// headers.
+	RedactionRuleBearerToken = "bearer-token"
+
+	// RedactionRuleCreditCard is the name of the rule that masks credit card numbers.
+	RedactionRuleCreditCard = "credit-card"
+
+	// RedactionRuleSecret is the name of the rule that masks the values of passwords, secrets, API
+	// keys and tokens in "key: value" or "key=value" notation.
+	RedactionRuleSecret = "secret"
+)
+
+// ErrInvalidRedactionRule is returned if a RedactionRule does not have a name or a pattern.
+var ErrInvalidRedactionRule = errors.New("redaction rule must have a name and a pattern")
+
+// RedactionRule represents a pattern that is masked by Msg.Redact.
+type RedactionRule struct {
+	// Name is the name of the rule, which is used as key of the redaction counts.
+	Name string
+
+	// Pattern is the regular expression that matches the text to be masked.
+	Pattern *regexp.Regexp
+
+	// Replacement is the text that replaces each match. It may reference submatches of the Pattern
+	// like "${1}", as supported by regexp.Regexp.Expand. If empty, DefaultRedactionReplacement is used.
+	Replacement string
+
+	// Validate is an optional function that is called with each match. A match is only masked if
+	// Validate is nil or returns true, which allows to reduce false positives, i. e. with a checksum.
+	Validate func(match string) bool
+}
+
+// DefaultRedactionRules returns the built-in RedactionRule values, which mask credit card numbers
+// that pass the Luhn check, AWS access key IDs, bearer tokens and the values of passwords, secrets,
+// API keys and tokens.
+//
+// Returns:
+//   - A slice of the built-in RedactionRule values.
+func DefaultRedactionRules() []RedactionRule {
+	return []RedactionRule{
+		{
+			Name:     RedactionRuleCreditCard,
+			Pattern:  regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+			Validate: isLuhnValid,
+		},
+		{
+			Name:    RedactionRuleAWSAccessKey,
+			Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+		},
+		{
+			Name:        RedactionRuleBearerToken,
+			Pattern:     regexp.MustCompile(`(?i)\b(bearer\s+)[a-z0-9\-._~+/]+=*`),
+			Replacement: "${1}" + DefaultRedactionReplacement,
+		},
+		{
+			Name: RedactionRuleSecret,
+			Pattern: regexp.MustCompile(`(?i)\b((?:password|passwd|pwd|secret|api[_-]?key|access[_-]?token|` +
+				`auth[_-]?token|token)\s*[:=]\s*)[^\s"'<>]+`),
+			Replacement: "${1}" + DefaultRedactionReplacement,
+		},
+	}
+}
+
+// Redact masks the text matched by the given rules in the subject, the text parts and the text
+// attachments and embeds of the Msg.
+//
+// This is meant to remove sensitive data like credit card numbers or secrets from a Msg before it is
+// archived or logged. Only parts and files with a "text/*" content type are redacted. Files added with
+// Msg.AttachStream are skipped, since reading them would consume the stream. The rules are applied
+// in order, so that a later rule only sees the text that was not yet masked by an earlier rule.
+//
+// Parameters:
+//   - rules: The RedactionRule values to apply. If no rules are given, DefaultRedactionRules is used.
+//
+// Returns:
+//   - A map of the rule names to the number of redactions the rule performed.
+//   - An error if a rule is invalid or if the content of a part or file cannot be read; otherwise,
+//     returns nil.
+func (m *Msg) Redact(rules ...RedactionRule) (map[string]int, error) {
+	if len(rules) == 0 {
+		rules = DefaultRedactionRules()
+	}
+	for _, rule := range rules {
+		if rule.Name == "" || rule.Pattern == nil {
+			return nil, ErrInvalidRedactionRule
+		}
+	}
+	counts := make(map[string]int)
+
+	if subjects := m.GetGenHeader(HeaderSubject); len(subjects) > 0 {
+		redacted := make([]string, len(subjects))
+		for i, subject := range subjects {
+			redacted[i] = redactText(subject, rules, counts)
+		}
+		m.SetGenHeader(HeaderSubject, redacted...)
+	}
+	for _, part := range m.GetParts() {
+		if !isRedactableType(part.GetContentType()) {
+			continue
+		}
+		content, err := part.GetContent()
+		if err != nil {
+			return counts, fmt.Errorf("failed to read part content: %w", err)
+		}
+		part.SetContent(redactText(string(content), rules, counts))
+	}
+	for _, files := range [][]*File{m.attachments, m.embeds} {
+		for _, file := range files {
+			if file.stream || file.Writer == nil || !isRedactableType(file.ContentType) {
+				continue
+			}
+			if err := file.redact(rules, counts); err != nil {
+				return counts, err
+			}
+		}
+	}
+	return counts, nil
+}
+
+// redact masks the text matched by the given rules in the content of the File.
+//
+// Parameters:
+//   - rules: The RedactionRule values to apply.
+//   - counts: The map of the rule names to the number of redactions, which is updated.
+//
+// Returns:
+//   - An error if the content of the File cannot be read; otherwise, returns nil.
+func (f *File) redact(rules []RedactionRule, counts map[string]int) error {
+	buffer := bytes.NewBuffer(nil)
+	if _, err := f.Writer(buffer); err != nil {
+		return fmt.Errorf("failed to read content of file %s: %w", f.Name, err)
+	}
+	content := []byte(redactText(buffer.String(), rules, counts))
+	f.Writer = func(writer io.Writer) (int64, error) {
+		return io.Copy(writer, bytes.NewReader(content))
+	}
+	return nil
+}
+
+// redactText applies the given rules to the text and adds the number of redactions to counts.
+func redactText(text string, rules []RedactionRule, counts map[string]int) string {
+	for _, rule := range rules {
+		matches := rule.Pattern.FindAllStringSubmatchIndex(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = DefaultRedactionReplacement
+		}
+		var builder strings.Builder
+		last := 0
+		for _, match := range matches {
+			if rule.Validate != nil && !rule.Validate(text[match[0]:match[1]]) {
+				continue
+			}
+			builder.WriteString(text[last:match[0]])
+			builder.Write(rule.Pattern.ExpandString(nil, replacement, text, match))
+			last = match[1]
+			counts[rule.Name]++
+		}
+		builder.WriteString(text[last:])
+		text = builder.String()
+	}
+	return text
+}
+
+// isRedactableType reports whether content of the given content type is redacted by Msg.Redact.
+func isRedactableType(contentType ContentType) bool {
+	return strings.HasPrefix(strings.ToLower(string(contentType)), "text/")
+}
+
+// isLuhnValid reports whether the digits of the given number pass the Luhn checksum. Spaces and dashes
+// are ignored.
+func isLuhnValid(number string) bool {
+	sum := 0
+	digits := 0
+	for i := len(number) - 1; i >= 0; i-- {
+		char := number[i]
+		if char == ' ' || char == '-' {
+			continue
+		}
+		if char < '0' || char > '9' {
+			return false
+		}
+		digit := int(char - '0')
+		if digits%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
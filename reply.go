@@ -0,0 +1,273 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+)
+
+const (
+	// replySubjectPrefix is the prefix of the subject of a reply.
+	replySubjectPrefix = "Re: "
+
+	// forwardSubjectPrefix is the prefix of the subject of a forwarded message.
+	forwardSubjectPrefix = "Fwd: "
+
+	// forwardSeparator is the line that introduces the original message in an inline forward.
+	forwardSeparator = "---------- Forwarded message ----------"
+)
+
+// ErrOriginalMsgIsNil is returned by NewReplyMsg and NewForwardMsg if the original Msg is nil.
+var ErrOriginalMsgIsNil = errors.New("original message is nil")
+
+// NewReplyMsg creates a new Msg that replies to the given original Msg.
+//
+// The reply is addressed to the "Reply-To" address of the original Msg, or to its "From" address if
+// no "Reply-To" is set. The "In-Reply-To" and "References" headers are set based on the
+// "Message-ID" of the original Msg, so that mail clients thread the reply with the original, and the
+// subject is prefixed with "Re:" unless it already is. If quoteBody is true, the plain text and HTML
+// bodies of the original Msg are quoted in the reply. The sender of the reply still has to be set.
+//
+// Parameters:
+//   - original: The Msg to reply to.
+//   - quoteBody: Whether the bodies of the original Msg are quoted in the reply.
+//   - opts: Optional MsgOption functions that are applied to the reply.
+//
+// Returns:
+//   - A pointer to the reply Msg.
+//   - An error if the original Msg is nil, its addresses cannot be set or its bodies cannot be read.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.6.4
+func NewReplyMsg(original *Msg, quoteBody bool, opts ...MsgOption) (*Msg, error) {
+	if original == nil {
+		return nil, ErrOriginalMsgIsNil
+	}
+	reply := NewMsg(opts...)
+	reply.Subject(prefixSubject(originalSubject(original), replySubjectPrefix, "re:"))
+	reply.ThreadFrom(original)
+
+	recipients := original.GetAddrHeaderString(HeaderFrom)
+	if replyTo := original.GetGenHeader(HeaderReplyTo); len(replyTo) > 0 {
+		recipients = replyTo
+	}
+	if len(recipients) > 0 {
+		if err := reply.To(recipients...); err != nil {
+			return nil, fmt.Errorf("failed to set reply recipients: %w", err)
+		}
+	}
+
+	if !quoteBody {
+		return reply, nil
+	}
+	attribution := replyAttribution(original)
+	plain, htmlBody, err := originalBodies(original)
+	if err != nil {
+		return nil, err
+	}
+	if plain != "" {
+		addBodyString(reply, TypeTextPlain, attribution+"\r\n"+quotePlainText(plain))
+	}
+	if htmlBody != "" {
+		addBodyString(reply, TypeTextHTML, "<p>"+html.EscapeString(attribution)+
+			`</p><blockquote type="cite">`+htmlBodyContent(htmlBody)+"</blockquote>")
+	}
+	return reply, nil
+}
+
+// NewForwardMsg creates a new Msg that forwards the given original Msg.
+//
+// The "References" header is set based on the "Message-ID" of the original Msg and the subject is
+// prefixed with "Fwd:" unless it already is. If asAttachment is true, the complete original Msg is
+// attached as "message/rfc822". Otherwise, the plain text and HTML bodies of the original Msg are
+// included inline, introduced by its most important headers, and the attachments of the original Msg
+// are attached to the forward. The sender and the recipients of the forward still have to be set.
+//
+// Parameters:
+//   - original: The Msg to forward.
+//   - asAttachment: Whether the original Msg is attached instead of included inline.
+//   - opts: Optional MsgOption functions that are applied to the forward.
+//
+// Returns:
+//   - A pointer to the forward Msg.
+//   - An error if the original Msg is nil or cannot be written or read.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2046#section-5.2.1
+func NewForwardMsg(original *Msg, asAttachment bool, opts ...MsgOption) (*Msg, error) {
+	if original == nil {
+		return nil, ErrOriginalMsgIsNil
+	}
+	subject := originalSubject(original)
+	forward := NewMsg(opts...)
+	forward.Subject(prefixSubject(subject, forwardSubjectPrefix, "fwd:", "fw:"))
+	if references := threadReferences(original); len(references) > 0 {
+		forward.setReferences(references)
+	}
+
+	if asAttachment {
+		buffer := bytes.NewBuffer(nil)
+		if _, err := original.WriteTo(buffer); err != nil {
+			return nil, fmt.Errorf("failed to write original message: %w", err)
+		}
+		name := "forwarded.eml"
+		if subject != "" {
+			name = subject + ".eml"
+		}
+		forward.attachments = forward.appendFile(forward.attachments, &File{
+			Name:        name,
+			Header:      make(map[string][]string),
+			Writer:      writeFuncFromBuffer(buffer),
+			ContentType: TypeMessageRFC822,
+			Enc:         NoEncoding,
+		})
+		return forward, nil
+	}
+
+	plain, htmlBody, err := originalBodies(original)
+	if err != nil {
+		return nil, err
+	}
+	headers := forwardHeaders(original)
+	if plain != "" || htmlBody == "" {
+		addBodyString(forward, TypeTextPlain, forwardSeparator+"\r\n"+strings.Join(headers, "\r\n")+
+			"\r\n\r\n"+plain)
+	}
+	if htmlBody != "" {
+		escaped := make([]string, len(headers))
+		for i, header := range headers {
+			escaped[i] = html.EscapeString(header)
+		}
+		addBodyString(forward, TypeTextHTML, "<p>"+forwardSeparator+"<br>"+strings.Join(escaped, "<br>")+
+			"</p>"+htmlBodyContent(htmlBody))
+	}
+	forward.SetAttachments(original.GetAttachments())
+	return forward, nil
+}
+
+// originalSubject returns the subject of the given Msg, or an empty string if it has no subject.
+func originalSubject(original *Msg) string {
+	if subject := original.GetGenHeader(HeaderSubject); len(subject) > 0 {
+		return subject[0]
+	}
+	return ""
+}
+
+// prefixSubject adds the given prefix to the subject, unless the subject already starts with one of
+// the given lower-case markers.
+func prefixSubject(subject, prefix string, markers ...string) string {
+	lower := strings.ToLower(strings.TrimSpace(subject))
+	for _, marker := range markers {
+		if strings.HasPrefix(lower, marker) {
+			return subject
+		}
+	}
+	return prefix + subject
+}
+
+// originalBodies returns the content of the first "text/plain" and the first "text/html" part of the
+// given Msg.
+func originalBodies(original *Msg) (string, string, error) {
+	var plain, htmlBody string
+	for _, part := range original.GetParts() {
+		if part.isDeleted {
+			continue
+		}
+		contentType := part.GetContentType()
+		if (contentType != TypeTextPlain || plain != "") && (contentType != TypeTextHTML || htmlBody != "") {
+			continue
+		}
+		content, err := part.GetContent()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read %s part of original message: %w", contentType, err)
+		}
+		if contentType == TypeTextPlain {
+			plain = string(content)
+			continue
+		}
+		htmlBody = string(content)
+	}
+	return plain, htmlBody, nil
+}
+
+// addBodyString sets the body of the Msg to the given content, or adds it as alternative if the Msg
+// already has a body.
+func addBodyString(message *Msg, contentType ContentType, content string) {
+	if len(message.parts) == 0 {
+		message.SetBodyString(contentType, content)
+		return
+	}
+	message.AddAlternativeString(contentType, content)
+}
+
+// replyAttribution returns the line that introduces the quoted body of the original Msg in a reply.
+func replyAttribution(original *Msg) string {
+	from := strings.Join(original.GetFromString(), ", ")
+	if from == "" {
+		from = "Unknown sender"
+	}
+	if date := original.GetGenHeader(HeaderDate); len(date) > 0 {
+		return fmt.Sprintf("On %s, %s wrote:", date[0], from)
+	}
+	return from + " wrote:"
+}
+
+// quotePlainText prefixes each line of the given text with the quote marker of RFC 3676.
+func quotePlainText(text string) string {
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n")
+	for i, line := range lines {
+		if line == "" || strings.HasPrefix(line, ">") {
+			lines[i] = ">" + line
+			continue
+		}
+		lines[i] = "> " + line
+	}
+	return strings.Join(lines, "\r\n")
+}
+
+// htmlBodyContent returns the content of the "body" element of the given HTML document, or the
+// document itself if it has no "body" element.
+func htmlBodyContent(document string) string {
+	lower := strings.ToLower(document)
+	start := strings.Index(lower, "<body")
+	if start < 0 {
+		return document
+	}
+	open := strings.Index(lower[start:], ">")
+	if open < 0 {
+		return document
+	}
+	start += open + 1
+	end := strings.LastIndex(lower, "</body")
+	if end < start {
+		return document[start:]
+	}
+	return document[start:end]
+}
+
+// forwardHeaders returns the headers of the original Msg that introduce it in an inline forward.
+func forwardHeaders(original *Msg) []string {
+	var headers []string
+	if from := original.GetFromString(); len(from) > 0 {
+		headers = append(headers, "From: "+strings.Join(from, ", "))
+	}
+	if date := original.GetGenHeader(HeaderDate); len(date) > 0 {
+		headers = append(headers, "Date: "+date[0])
+	}
+	if subject := originalSubject(original); subject != "" {
+		headers = append(headers, "Subject: "+subject)
+	}
+	if to := original.GetToString(); len(to) > 0 {
+		headers = append(headers, "To: "+strings.Join(to, ", "))
+	}
+	if cc := original.GetCcString(); len(cc) > 0 {
+		headers = append(headers, "Cc: "+strings.Join(cc, ", "))
+	}
+	return headers
+}
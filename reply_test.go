@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testOriginalMsg returns a Msg with a plain text and an HTML body that is replied to or forwarded in
+// the tests.
+func testOriginalMsg(t *testing.T) *Msg {
+	t.Helper()
+	message := NewMsg()
+	if err := message.From("Toni Sender <sender@example.com>"); err != nil {
+		t.Fatalf("failed to set sender address: %s", err)
+	}
+	if err := message.To("recipient@example.com"); err != nil {
+		t.Fatalf("failed to set recipient address: %s", err)
+	}
+	message.Subject("Quarterly report")
+	message.SetMessageIDWithValue("original@example.com")
+	message.SetGenHeader(HeaderReferences, "<first@example.com>")
+	message.SetBodyString(TypeTextPlain, "Please find the report.\r\n> Earlier quote")
+	message.AddAlternativeString(TypeTextHTML, "<html><body><p>Please find the report.</p></body></html>")
+	return message
+}
+
+func TestNewReplyMsg(t *testing.T) {
+	t.Run("nil original fails", func(t *testing.T) {
+		if _, err := NewReplyMsg(nil, true); !errors.Is(err, ErrOriginalMsgIsNil) {
+			t.Errorf("expected error: %s, got: %s", ErrOriginalMsgIsNil, err)
+		}
+	})
+	t.Run("reply sets the threading headers", func(t *testing.T) {
+		reply, err := NewReplyMsg(testOriginalMsg(t), false)
+		if err != nil {
+			t.Fatalf("failed to create reply: %s", err)
+		}
+		if subject := reply.GetGenHeader(HeaderSubject); len(subject) != 1 || subject[0] != "Re: Quarterly report" {
+			t.Errorf("unexpected subject: %v", subject)
+		}
+		if to := reply.GetToString(); len(to) != 1 || to[0] != `"Toni Sender" <sender@example.com>` {
+			t.Errorf("unexpected recipients: %v", to)
+		}
+		if inReplyTo := reply.GetGenHeader(HeaderInReplyTo); len(inReplyTo) != 1 ||
+			inReplyTo[0] != "<original@example.com>" {
+			t.Errorf("unexpected In-Reply-To header: %v", inReplyTo)
+		}
+		if references := reply.GetGenHeader(HeaderReferences); len(references) != 1 ||
+			references[0] != "<first@example.com> <original@example.com>" {
+			t.Errorf("unexpected References header: %v", references)
+		}
+		if len(reply.GetParts()) != 0 {
+			t.Errorf("expected reply without quoted body, got %d parts", len(reply.GetParts()))
+		}
+	})
+	t.Run("reply uses the Reply-To address and keeps the subject prefix", func(t *testing.T) {
+		original := testOriginalMsg(t)
+		original.Subject("RE: Quarterly report")
+		if err := original.ReplyTo("replies@example.com"); err != nil {
+			t.Fatalf("failed to set reply-to address: %s", err)
+		}
+		reply, err := NewReplyMsg(original, false)
+		if err != nil {
+			t.Fatalf("failed to create reply: %s", err)
+		}
+		if subject := reply.GetGenHeader(HeaderSubject); len(subject) != 1 || subject[0] != "RE: Quarterly report" {
+			t.Errorf("unexpected subject: %v", subject)
+		}
+		if to := reply.GetToString(); len(to) != 1 || to[0] != "<replies@example.com>" {
+			t.Errorf("unexpected recipients: %v", to)
+		}
+	})
+	t.Run("reply quotes the bodies", func(t *testing.T) {
+		reply, err := NewReplyMsg(testOriginalMsg(t), true)
+		if err != nil {
+			t.Fatalf("failed to create reply: %s", err)
+		}
+		parts := reply.GetParts()
+		if len(parts) != 2 {
+			t.Fatalf("expected 2 parts, got: %d", len(parts))
+		}
+		plain, err := parts[0].GetContent()
+		if err != nil {
+			t.Fatalf("failed to read plain text part: %s", err)
+		}
+		if !strings.Contains(string(plain), "wrote:\r\n> Please find the report.\r\n>> Earlier quote") {
+			t.Errorf("unexpected quoted plain text: %q", plain)
+		}
+		htmlBody, err := parts[1].GetContent()
+		if err != nil {
+			t.Fatalf("failed to read HTML part: %s", err)
+		}
+		if !strings.Contains(string(htmlBody), `<blockquote type="cite"><p>Please find the report.</p></blockquote>`) {
+			t.Errorf("unexpected quoted HTML: %q", htmlBody)
+		}
+	})
+}
+
+func TestNewForwardMsg(t *testing.T) {
+	t.Run("nil original fails", func(t *testing.T) {
+		if _, err := NewForwardMsg(nil, true); !errors.Is(err, ErrOriginalMsgIsNil) {
+			t.Errorf("expected error: %s, got: %s", ErrOriginalMsgIsNil, err)
+		}
+	})
+	t.Run("forward as attachment", func(t *testing.T) {
+		forward, err := NewForwardMsg(testOriginalMsg(t), true)
+		if err != nil {
+			t.Fatalf("failed to create forward: %s", err)
+		}
+		if subject := forward.GetGenHeader(HeaderSubject); len(subject) != 1 || subject[0] != "Fwd: Quarterly report" {
+			t.Errorf("unexpected subject: %v", subject)
+		}
+		if len(forward.GetGenHeader(HeaderInReplyTo)) != 0 {
+			t.Errorf("expected forward without In-Reply-To header")
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err = forward.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write forward: %s", err)
+		}
+		output := buffer.String()
+		for _, want := range []string{
+			`Content-Type: message/rfc822; name="Quarterly report.eml"`,
+			"Content-Transfer-Encoding: 8bit",
+			"Subject: Quarterly report",
+			"Please find the report.",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected forward to contain %q, got: %s", want, output)
+			}
+		}
+	})
+	t.Run("forward inline", func(t *testing.T) {
+		original := testOriginalMsg(t)
+		if err := original.AttachReader("report.txt", strings.NewReader("report")); err != nil {
+			t.Fatalf("failed to attach file: %s", err)
+		}
+		forward, err := NewForwardMsg(original, false)
+		if err != nil {
+			t.Fatalf("failed to create forward: %s", err)
+		}
+		parts := forward.GetParts()
+		if len(parts) != 2 {
+			t.Fatalf("expected 2 parts, got: %d", len(parts))
+		}
+		plain, err := parts[0].GetContent()
+		if err != nil {
+			t.Fatalf("failed to read plain text part: %s", err)
+		}
+		if !strings.HasPrefix(string(plain), forwardSeparator+"\r\nFrom: \"Toni Sender\" <sender@example.com>") ||
+			!strings.HasSuffix(string(plain), "\r\n\r\nPlease find the report.\r\n> Earlier quote") {
+			t.Errorf("unexpected forwarded plain text: %q", plain)
+		}
+		if attachments := forward.GetAttachments(); len(attachments) != 1 || attachments[0].Name != "report.txt" {
+			t.Errorf("expected attachments of the original message to be forwarded, got: %v", attachments)
+		}
+	})
+}
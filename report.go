@@ -0,0 +1,470 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// List of DSNAction values
+const (
+	// DSNActionFailed indicates that the message could not be delivered to the recipient.
+	DSNActionFailed DSNAction = "failed"
+
+	// DSNActionDelayed indicates that the delivery of the message to the recipient is delayed and
+	// will be retried.
+	DSNActionDelayed DSNAction = "delayed"
+
+	// DSNActionDelivered indicates that the message was delivered to the recipient.
+	DSNActionDelivered DSNAction = "delivered"
+
+	// DSNActionRelayed indicates that the message was relayed to an environment that does not issue
+	// delivery status notifications.
+	DSNActionRelayed DSNAction = "relayed"
+
+	// DSNActionExpanded indicates that the message was delivered to the recipient, which is a
+	// mailing list or alias, and forwarded to its members.
+	DSNActionExpanded DSNAction = "expanded"
+)
+
+// List of MDNDisposition values
+const (
+	// MDNDisplayed indicates that the message has been displayed to the recipient.
+	MDNDisplayed MDNDisposition = "displayed"
+
+	// MDNDeleted indicates that the message has been deleted without being displayed.
+	MDNDeleted MDNDisposition = "deleted"
+
+	// MDNDispatched indicates that the message has been printed, faxed or forwarded without being
+	// displayed.
+	MDNDispatched MDNDisposition = "dispatched"
+
+	// MDNProcessed indicates that the message has been processed without being displayed.
+	MDNProcessed MDNDisposition = "processed"
+)
+
+const (
+	// reportTypeDeliveryStatus is the report type of a delivery status notification.
+	reportTypeDeliveryStatus = "delivery-status"
+
+	// reportTypeDispositionNotification is the report type of a message disposition notification.
+	reportTypeDispositionNotification = "disposition-notification"
+
+	// typeMultipartReport is the content type of a report, as defined in RFC 6522.
+	typeMultipartReport ContentType = "multipart/report"
+
+	// typeDeliveryStatus is the content type of the machine-readable part of a delivery status
+	// notification.
+	typeDeliveryStatus ContentType = "message/delivery-status"
+
+	// typeDispositionNotification is the content type of the machine-readable part of a message
+	// disposition notification.
+	typeDispositionNotification ContentType = "message/disposition-notification"
+
+	// typeRFC822Headers is the content type of the headers of the original message in a report.
+	typeRFC822Headers ContentType = "text/rfc822-headers"
+
+	// maxReportDepth is the maximum nesting depth of the multipart bodies that ParseMDN searches for the
+	// disposition notification.
+	maxReportDepth = 10
+)
+
+var (
+	// ErrNoReportOriginal is returned by NewDSNMsg and NewMDNMsg if no original Msg is given.
+	ErrNoReportOriginal = errors.New("no original message given for the report")
+
+	// ErrNoReportRecipient is returned by NewDSNMsg and NewMDNMsg if the original Msg has no address
+	// the report can be sent to.
+	ErrNoReportRecipient = errors.New("original message has no address to send the report to")
+
+	// ErrNoDSNStatus is returned by NewDSNMsg if no DSNStatus is given.
+	ErrNoDSNStatus = errors.New("no delivery status given for the notification")
+
+	// ErrNoMDN is returned by ParseMDN if the message does not hold a valid disposition notification.
+	ErrNoMDN = errors.New("message holds no valid disposition notification")
+)
+
+type (
+	// DSNAction represents the action that was performed for a recipient of a delivery status
+	// notification, as defined in RFC 3464.
+	DSNAction string
+
+	// DSNStatus holds the delivery status of a single recipient of the original Msg, for a delivery
+	// status notification created with NewDSNMsg.
+	DSNStatus struct {
+		// Action is the action that was performed for the recipient.
+		Action DSNAction
+
+		// DiagnosticCode is the reply of the remote MTA, with its type, i. e.
+		// "smtp; 550 5.1.1 User unknown". It is omitted if empty.
+		DiagnosticCode string
+
+		// FinalRecipient is the address of the recipient.
+		FinalRecipient string
+
+		// LastAttemptDate is the time of the last delivery attempt. It is omitted if zero.
+		LastAttemptDate time.Time
+
+		// RemoteMTA is the host name of the remote MTA that reported the status. It is omitted if empty.
+		RemoteMTA string
+
+		// Status is the enhanced status code of the delivery, as defined in RFC 3463, i. e. "5.1.1".
+		Status string
+	}
+
+	// MDNDisposition represents the disposition type of a message disposition notification, as
+	// defined in RFC 8098.
+	MDNDisposition string
+
+	// MDNStatus holds the disposition of the original Msg, for a message disposition notification
+	// created with NewMDNMsg.
+	MDNStatus struct {
+		// Automatic indicates that the disposition was performed and the notification was sent
+		// automatically, instead of by an explicit action of the user.
+		Automatic bool
+
+		// Disposition is the disposition of the original Msg.
+		Disposition MDNDisposition
+
+		// FinalRecipient is the address of the recipient whose mailbox the original Msg was
+		// disposed of in.
+		FinalRecipient string
+
+		// ReportingUA is the name of the user agent that performed the disposition, i. e.
+		// "mail.example.com; Webmail". It is omitted if empty.
+		ReportingUA string
+	}
+
+	// MDNReport holds the fields of a message disposition notification, as returned by ParseMDN.
+	MDNReport struct {
+		// Automatic indicates that the disposition was performed and the notification was sent
+		// automatically, instead of by an explicit action of the user.
+		Automatic bool
+
+		// Disposition is the disposition of the original message, without modifiers.
+		Disposition MDNDisposition
+
+		// FinalRecipient is the address of the recipient whose mailbox the original message was
+		// disposed of in, without the address type.
+		FinalRecipient string
+
+		// OriginalMessageID is the "Message-ID" of the original message, including the angle brackets.
+		// It is empty if the notification does not reference the original message.
+		OriginalMessageID string
+
+		// OriginalRecipient is the address of the recipient as given by the sender of the original
+		// message, without the address type. It is empty if not reported.
+		OriginalRecipient string
+
+		// ReportingUA is the name of the user agent that performed the disposition. It is empty if not
+		// reported.
+		ReportingUA string
+	}
+)
+
+// NewDSNMsg creates a delivery status notification for the given original Msg, as defined in RFC 3464.
+//
+// The notification is a multipart/report Msg that consists of a human-readable description of the
+// statuses, the machine-readable message/delivery-status part and the headers of the original Msg.
+// It is addressed to the envelope sender or, if missing, the "FROM" address of the original Msg. The
+// "FROM" address of the notification, i. e. the postmaster of the reporting MTA, needs to be set by
+// the caller. Note that notifications must be sent with an empty envelope sender, so that they do not
+// cause notifications themselves. If the original Msg has no "Date" or "Message-ID" header yet, they
+// are set by the call.
+//
+// Parameters:
+//   - original: The Msg the notification is about.
+//   - reportingMTA: The host name of the MTA that creates the notification.
+//   - statuses: The delivery statuses of the recipients of the original Msg.
+//
+// Returns:
+//   - A pointer to the notification Msg.
+//   - An error if no original Msg or status is given, the original Msg has no sender or an address is
+//     invalid; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc3464
+//   - https://datatracker.ietf.org/doc/html/rfc6522
+func NewDSNMsg(original *Msg, reportingMTA string, statuses ...DSNStatus) (*Msg, error) {
+	if original == nil {
+		return nil, ErrNoReportOriginal
+	}
+	if len(statuses) == 0 {
+		return nil, ErrNoDSNStatus
+	}
+	sender := original.GetAddrHeader(HeaderEnvelopeFrom)
+	if len(sender) == 0 {
+		sender = original.GetFrom()
+	}
+	if len(sender) == 0 {
+		return nil, ErrNoReportRecipient
+	}
+	headers, err := reportHeaders(original)
+	if err != nil {
+		return nil, err
+	}
+
+	var description, fields strings.Builder
+	description.WriteString("This is an automatically generated delivery status notification.\r\n\r\n")
+	fields.WriteString(fmt.Sprintf("Reporting-MTA: dns; %s\r\n", reportingMTA))
+	for _, status := range statuses {
+		description.WriteString(fmt.Sprintf("%s: %s (%s)\r\n", status.FinalRecipient,
+			dsnActionDescription(status.Action), status.Status))
+		fields.WriteString(fmt.Sprintf("\r\nFinal-Recipient: rfc822; %s\r\n", status.FinalRecipient))
+		fields.WriteString(fmt.Sprintf("Action: %s\r\n", status.Action))
+		fields.WriteString(fmt.Sprintf("Status: %s\r\n", status.Status))
+		if status.RemoteMTA != "" {
+			fields.WriteString(fmt.Sprintf("Remote-MTA: dns; %s\r\n", status.RemoteMTA))
+		}
+		if status.DiagnosticCode != "" {
+			fields.WriteString(fmt.Sprintf("Diagnostic-Code: %s\r\n", status.DiagnosticCode))
+		}
+		if !status.LastAttemptDate.IsZero() {
+			fields.WriteString(fmt.Sprintf("Last-Attempt-Date: %s\r\n",
+				status.LastAttemptDate.Format(time.RFC1123Z)))
+		}
+	}
+
+	msg, err := newReportMsg(original, reportTypeDeliveryStatus, sender[0].String())
+	if err != nil {
+		return nil, err
+	}
+	msg.Subject(fmt.Sprintf("Delivery Status Notification (%s)", dsnActionDescription(statuses[0].Action)))
+	msg.SetBodyString(TypeTextPlain, description.String())
+	msg.AddAlternativeString(typeDeliveryStatus, fields.String(), WithPartEncoding(NoEncoding))
+	msg.AddAlternativeString(typeRFC822Headers, string(headers), WithPartEncoding(NoEncoding))
+	return msg, nil
+}
+
+// NewMDNMsg creates a message disposition notification for the given original Msg, as defined in
+// RFC 8098.
+//
+// The notification is a multipart/report Msg that consists of a human-readable description of the
+// disposition, the machine-readable message/disposition-notification part and the headers of the
+// original Msg. It is addressed to the "Disposition-Notification-To" addresses of the original Msg,
+// as requested with Msg.RequestMDNTo. The "FROM" address of the notification needs to be set by the
+// caller. If the original Msg has no "Date" or "Message-ID" header yet, they are set by the call.
+//
+// Parameters:
+//   - original: The Msg the notification is about.
+//   - status: The disposition of the original Msg.
+//
+// Returns:
+//   - A pointer to the notification Msg.
+//   - An error if no original Msg is given, the original Msg did not request a notification or an
+//     address is invalid; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc8098
+//   - https://datatracker.ietf.org/doc/html/rfc6522
+func NewMDNMsg(original *Msg, status MDNStatus) (*Msg, error) {
+	if original == nil {
+		return nil, ErrNoReportOriginal
+	}
+	rcpts := original.GetGenHeader(HeaderDispositionNotificationTo)
+	if len(rcpts) == 0 {
+		return nil, ErrNoReportRecipient
+	}
+	headers, err := reportHeaders(original)
+	if err != nil {
+		return nil, err
+	}
+
+	actionMode, sendingMode := "manual-action", "MDN-sent-manually"
+	if status.Automatic {
+		actionMode, sendingMode = "automatic-action", "MDN-sent-automatically"
+	}
+	var fields strings.Builder
+	if status.ReportingUA != "" {
+		fields.WriteString(fmt.Sprintf("Reporting-UA: %s\r\n", status.ReportingUA))
+	}
+	fields.WriteString(fmt.Sprintf("Final-Recipient: rfc822; %s\r\n", status.FinalRecipient))
+	if messageID := original.GetMessageID(); messageID != "" {
+		fields.WriteString(fmt.Sprintf("Original-Message-ID: %s\r\n", messageID))
+	}
+	fields.WriteString(fmt.Sprintf("Disposition: %s/%s; %s\r\n", actionMode, sendingMode, status.Disposition))
+
+	msg, err := newReportMsg(original, reportTypeDispositionNotification, rcpts...)
+	if err != nil {
+		return nil, err
+	}
+	msg.Subject(fmt.Sprintf("Disposition Notification (%s)", status.Disposition))
+	msg.SetBodyString(TypeTextPlain, fmt.Sprintf("The message sent to %s has been %s.\r\n",
+		status.FinalRecipient, status.Disposition))
+	msg.AddAlternativeString(typeDispositionNotification, fields.String(), WithPartEncoding(NoEncoding))
+	msg.AddAlternativeString(typeRFC822Headers, string(headers), WithPartEncoding(NoEncoding))
+	return msg, nil
+}
+
+// ParseMDN parses the message disposition notification of the given message, as defined in RFC 8098.
+//
+// The message is searched for a message/disposition-notification part, which is usually part of a
+// multipart/report body, as created by NewMDNMsg. This complements Msg.RequestMDNTo, so that the
+// notifications received for the sent messages can be processed, i. e. matched against their
+// "Message-ID".
+//
+// Parameters:
+//   - reader: The io.Reader providing the message.
+//
+// Returns:
+//   - A pointer to the MDNReport holding the fields of the notification.
+//   - An error wrapping ErrNoMDN if the message holds no notification with a "Disposition" field, or an
+//     error if the message cannot be parsed; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc8098#section-3.1
+func ParseMDN(reader io.Reader) (*MDNReport, error) {
+	message, err := netmail.ReadMessage(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+	header := textproto.MIMEHeader(message.Header)
+	body, err := findReportPart(header, message.Body, typeDispositionNotification, 0)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := textproto.NewReader(bufio.NewReader(body)).ReadMIMEHeader()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse disposition notification: %w", err)
+	}
+
+	disposition := fields.Get("Disposition")
+	separator := strings.IndexByte(disposition, ';')
+	if separator < 0 {
+		return nil, fmt.Errorf("%w: invalid disposition %q", ErrNoMDN, disposition)
+	}
+	actionMode, dispositionType := strings.TrimSpace(disposition[:separator]), disposition[separator+1:]
+	if index := strings.IndexByte(actionMode, '/'); index >= 0 {
+		actionMode = actionMode[:index]
+	}
+	dispositionType = strings.TrimSpace(dispositionType)
+	if index := strings.IndexByte(dispositionType, '/'); index >= 0 {
+		dispositionType = dispositionType[:index]
+	}
+	return &MDNReport{
+		Automatic:         strings.EqualFold(actionMode, "automatic-action"),
+		Disposition:       MDNDisposition(strings.ToLower(strings.TrimSpace(dispositionType))),
+		FinalRecipient:    reportAddress(fields.Get("Final-Recipient")),
+		OriginalMessageID: strings.TrimSpace(fields.Get("Original-Message-ID")),
+		OriginalRecipient: reportAddress(fields.Get("Original-Recipient")),
+		ReportingUA:       strings.TrimSpace(fields.Get("Reporting-UA")),
+	}, nil
+}
+
+// newReportMsg returns a new multipart/report Msg of the given report type, with the charset and
+// encoding of the given original Msg, addressed to the given recipients and referencing the original
+// Msg.
+func newReportMsg(original *Msg, reportType string, rcpts ...string) (*Msg, error) {
+	msg := NewMsg(WithCharset(original.charset), WithEncoding(original.encoding))
+	msg.reportType = reportType
+	if err := msg.To(rcpts...); err != nil {
+		return nil, err
+	}
+	if messageID := original.GetMessageID(); messageID != "" {
+		msg.SetGenHeader(HeaderInReplyTo, messageID)
+		msg.SetGenHeader(HeaderReferences, messageID)
+	}
+	return msg, nil
+}
+
+// reportHeaders returns the headers of the given original Msg, for the last part of a report. The
+// original Msg is rendered as estimated by Msg.EstimatedSize, so that its streamed files are not
+// consumed.
+func reportHeaders(original *Msg) ([]byte, error) {
+	buffer := bytes.NewBuffer(nil)
+	if _, err := original.estimationMsg().WriteTo(buffer); err != nil {
+		return nil, fmt.Errorf("failed to render headers of the original message: %w", err)
+	}
+	headers := buffer.Bytes()
+	if index := bytes.Index(headers, []byte(DoubleNewLine)); index >= 0 {
+		headers = headers[:index+len(SingleNewLine)]
+	}
+	return headers, nil
+}
+
+// dsnActionDescription returns the human-readable description of the given DSNAction.
+func dsnActionDescription(action DSNAction) string {
+	switch action {
+	case DSNActionFailed:
+		return "Failure"
+	case DSNActionDelayed:
+		return "Delay"
+	case DSNActionDelivered, DSNActionExpanded:
+		return "Success"
+	case DSNActionRelayed:
+		return "Relay"
+	default:
+		return string(action)
+	}
+}
+
+// findReportPart returns the decoded body of the first part of the given content type, searching
+// the nested multipart bodies up to maxReportDepth.
+//
+// Parameters:
+//   - header: The header of the current part.
+//   - body: The body of the current part.
+//   - contentType: The content type of the part to search for.
+//   - depth: The nesting depth of the current part.
+//
+// Returns:
+//   - An io.Reader providing the decoded body of the part.
+//   - An error wrapping ErrNoMDN if no such part exists, or an error if the multipart body cannot be
+//     parsed; otherwise, returns nil.
+func findReportPart(header textproto.MIMEHeader, body io.Reader, contentType ContentType, depth int) (
+	io.Reader, error,
+) {
+	mediaType, params, err := mime.ParseMediaType(header.Get(HeaderContentType.String()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoMDN, err)
+	}
+	if strings.EqualFold(mediaType, string(contentType)) {
+		switch strings.ToLower(strings.TrimSpace(header.Get(HeaderContentTransferEnc.String()))) {
+		case EncodingB64.String():
+			return base64.NewDecoder(base64.StdEncoding, body), nil
+		case EncodingQP.String():
+			return quotedprintable.NewReader(body), nil
+		default:
+			return body, nil
+		}
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") || depth >= maxReportDepth {
+		return nil, ErrNoMDN
+	}
+	multipartReader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := multipartReader.NextRawPart()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrNoMDN
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse multipart body: %w", err)
+		}
+		if found, err := findReportPart(part.Header, part, contentType, depth+1); !errors.Is(err, ErrNoMDN) {
+			return found, err
+		}
+	}
+}
+
+// reportAddress returns the address of the given address field of a report, without its address type,
+// i. e. "user@example.com" for "rfc822; user@example.com".
+func reportAddress(field string) string {
+	if index := strings.IndexByte(field, ';'); index >= 0 {
+		field = field[index+1:]
+	}
+	return strings.TrimSpace(field)
+}
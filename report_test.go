@@ -0,0 +1,274 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDSNMsg(t *testing.T) {
+	t.Run("delivery status notification", func(t *testing.T) {
+		original := testMessage(t)
+		original.SetMessageIDWithValue("original@example.com")
+		original.SetBodyString(TypeTextPlain, "Original body")
+		status := DSNStatus{
+			Action:          DSNActionFailed,
+			DiagnosticCode:  "smtp; 550 5.1.1 User unknown",
+			FinalRecipient:  TestRcptValid,
+			LastAttemptDate: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			RemoteMTA:       "mx.example.com",
+			Status:          "5.1.1",
+		}
+		dsn, err := NewDSNMsg(original, "mta.example.com", status)
+		if err != nil {
+			t.Fatalf("failed to create DSN: %s", err)
+		}
+		if err = dsn.From("postmaster@mta.example.com"); err != nil {
+			t.Fatalf("failed to set FROM address: %s", err)
+		}
+		if rcpts := dsn.GetToString(); len(rcpts) != 1 || rcpts[0] != "<"+TestSenderValid+">" {
+			t.Errorf("expected DSN to be addressed to the original sender, got: %v", rcpts)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err = dsn.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write DSN: %s", err)
+		}
+		output := buffer.String()
+		wants := []string{
+			"Content-Type: multipart/report; report-type=delivery-status;\r\n boundary=",
+			"In-Reply-To: <original@example.com>\r\n",
+			"Subject: Delivery Status Notification (Failure)\r\n",
+			"Content-Type: text/plain; charset=UTF-8\r\n",
+			"Content-Type: message/delivery-status\r\n",
+			"Reporting-MTA: dns; mta.example.com\r\n\r\nFinal-Recipient: rfc822; " + TestRcptValid + "\r\n" +
+				"Action: failed\r\nStatus: 5.1.1\r\nRemote-MTA: dns; mx.example.com\r\n" +
+				"Diagnostic-Code: smtp; 550 5.1.1 User unknown\r\n" +
+				"Last-Attempt-Date: Tue, 02 Jan 2024 03:04:05 +0000\r\n",
+			"Content-Type: text/rfc822-headers; charset=UTF-8\r\n",
+			"Message-ID: <original@example.com>\r\n",
+		}
+		for _, want := range wants {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected DSN to contain %q, got:\n%s", want, output)
+			}
+		}
+		if strings.Contains(output, "multipart/alternative") {
+			t.Errorf("expected DSN parts not to be alternatives, got:\n%s", output)
+		}
+		if strings.Contains(output, "Original body") {
+			t.Errorf("expected DSN not to contain the body of the original message, got:\n%s", output)
+		}
+	})
+	t.Run("envelope sender takes precedence", func(t *testing.T) {
+		original := testMessage(t)
+		if err := original.EnvelopeFrom("bounces@example.com"); err != nil {
+			t.Fatalf("failed to set envelope FROM address: %s", err)
+		}
+		dsn, err := NewDSNMsg(original, "mta.example.com", DSNStatus{Action: DSNActionDelayed, Status: "4.4.1"})
+		if err != nil {
+			t.Fatalf("failed to create DSN: %s", err)
+		}
+		if rcpts := dsn.GetToString(); len(rcpts) != 1 || rcpts[0] != "<bounces@example.com>" {
+			t.Errorf("expected DSN to be addressed to the envelope sender, got: %v", rcpts)
+		}
+	})
+	t.Run("invalid arguments", func(t *testing.T) {
+		status := DSNStatus{Action: DSNActionFailed, Status: "5.0.0"}
+		if _, err := NewDSNMsg(nil, "mta.example.com", status); !errors.Is(err, ErrNoReportOriginal) {
+			t.Errorf("expected error %q, got: %v", ErrNoReportOriginal, err)
+		}
+		if _, err := NewDSNMsg(testMessage(t), "mta.example.com"); !errors.Is(err, ErrNoDSNStatus) {
+			t.Errorf("expected error %q, got: %v", ErrNoDSNStatus, err)
+		}
+		if _, err := NewDSNMsg(NewMsg(), "mta.example.com", status); !errors.Is(err, ErrNoReportRecipient) {
+			t.Errorf("expected error %q, got: %v", ErrNoReportRecipient, err)
+		}
+	})
+}
+
+func TestNewMDNMsg(t *testing.T) {
+	t.Run("message disposition notification", func(t *testing.T) {
+		original := testMessage(t)
+		original.SetMessageIDWithValue("original@example.com")
+		if err := original.RequestMDNTo("receipts@example.com"); err != nil {
+			t.Fatalf("failed to request MDN: %s", err)
+		}
+		mdn, err := NewMDNMsg(original, MDNStatus{
+			Disposition:    MDNDisplayed,
+			FinalRecipient: TestRcptValid,
+			ReportingUA:    "mail.example.com; Webmail",
+		})
+		if err != nil {
+			t.Fatalf("failed to create MDN: %s", err)
+		}
+		if rcpts := mdn.GetToString(); len(rcpts) != 1 || rcpts[0] != "<receipts@example.com>" {
+			t.Errorf("expected MDN to be addressed to the requested address, got: %v", rcpts)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err = mdn.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write MDN: %s", err)
+		}
+		output := buffer.String()
+		wants := []string{
+			"Content-Type: multipart/report; report-type=disposition-notification;\r\n boundary=",
+			"Subject: Disposition Notification (displayed)\r\n",
+			"Content-Type: message/disposition-notification\r\n",
+			"Reporting-UA: mail.example.com; Webmail\r\nFinal-Recipient: rfc822; " + TestRcptValid + "\r\n" +
+				"Original-Message-ID: <original@example.com>\r\n" +
+				"Disposition: manual-action/MDN-sent-manually; displayed\r\n",
+			"Content-Type: text/rfc822-headers; charset=UTF-8\r\n",
+		}
+		for _, want := range wants {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected MDN to contain %q, got:\n%s", want, output)
+			}
+		}
+	})
+	t.Run("automatic disposition", func(t *testing.T) {
+		original := testMessage(t)
+		if err := original.RequestMDNTo("receipts@example.com"); err != nil {
+			t.Fatalf("failed to request MDN: %s", err)
+		}
+		mdn, err := NewMDNMsg(original, MDNStatus{Automatic: true, Disposition: MDNProcessed})
+		if err != nil {
+			t.Fatalf("failed to create MDN: %s", err)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err = mdn.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write MDN: %s", err)
+		}
+		want := "Disposition: automatic-action/MDN-sent-automatically; processed\r\n"
+		if !strings.Contains(buffer.String(), want) {
+			t.Errorf("expected MDN to contain %q, got:\n%s", want, buffer.String())
+		}
+		if !strings.Contains(buffer.String(), "Original-Message-ID: <") {
+			t.Errorf("expected MDN to contain the generated Message-ID of the original, got:\n%s", buffer.String())
+		}
+	})
+	t.Run("no MDN requested", func(t *testing.T) {
+		if _, err := NewMDNMsg(testMessage(t), MDNStatus{}); !errors.Is(err, ErrNoReportRecipient) {
+			t.Errorf("expected error %q, got: %v", ErrNoReportRecipient, err)
+		}
+		if _, err := NewMDNMsg(nil, MDNStatus{}); !errors.Is(err, ErrNoReportOriginal) {
+			t.Errorf("expected error %q, got: %v", ErrNoReportOriginal, err)
+		}
+	})
+}
+
+func TestParseMDN(t *testing.T) {
+	t.Run("round trip with NewMDNMsg", func(t *testing.T) {
+		original := testMessage(t)
+		original.SetMessageIDWithValue("original@example.com")
+		if err := original.RequestMDNTo("receipts@example.com"); err != nil {
+			t.Fatalf("failed to request MDN: %s", err)
+		}
+		mdn, err := NewMDNMsg(original, MDNStatus{
+			Automatic:      true,
+			Disposition:    MDNDeleted,
+			FinalRecipient: TestRcptValid,
+			ReportingUA:    "mail.example.com; Webmail",
+		})
+		if err != nil {
+			t.Fatalf("failed to create MDN: %s", err)
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err = mdn.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write MDN: %s", err)
+		}
+		report, err := ParseMDN(buffer)
+		if err != nil {
+			t.Fatalf("failed to parse MDN: %s", err)
+		}
+		want := MDNReport{
+			Automatic:         true,
+			Disposition:       MDNDeleted,
+			FinalRecipient:    TestRcptValid,
+			OriginalMessageID: "<original@example.com>",
+			ReportingUA:       "mail.example.com; Webmail",
+		}
+		if *report != want {
+			t.Errorf("expected report %+v, got: %+v", want, *report)
+		}
+	})
+	t.Run("encoded part nested in mixed body", func(t *testing.T) {
+		fields := base64.StdEncoding.EncodeToString([]byte("Reporting-UA: client.example.com; Mailer\r\n" +
+			"Original-Recipient: rfc822; alias@example.com\r\nFinal-Recipient: rfc822; user@example.com\r\n" +
+			"Original-Message-ID: <campaign@example.com>\r\n" +
+			"Disposition: Manual-Action/MDN-sent-manually; Displayed/error\r\n"))
+		message := "From: user@example.com\r\nTo: sender@example.com\r\nSubject: Read\r\n" +
+			"Content-Type: multipart/mixed; boundary=outer\r\n\r\n" +
+			"--outer\r\nContent-Type: text/plain\r\n\r\nSee the report.\r\n" +
+			"--outer\r\nContent-Type: multipart/report; report-type=disposition-notification; boundary=inner\r\n\r\n" +
+			"--inner\r\nContent-Type: text/plain\r\n\r\nThe message has been displayed.\r\n" +
+			"--inner\r\nContent-Type: message/disposition-notification\r\nContent-Transfer-Encoding: base64\r\n\r\n" +
+			fields + "\r\n--inner--\r\n--outer--\r\n"
+		report, err := ParseMDN(strings.NewReader(message))
+		if err != nil {
+			t.Fatalf("failed to parse MDN: %s", err)
+		}
+		want := MDNReport{
+			Disposition:       MDNDisplayed,
+			FinalRecipient:    "user@example.com",
+			OriginalMessageID: "<campaign@example.com>",
+			OriginalRecipient: "alias@example.com",
+			ReportingUA:       "client.example.com; Mailer",
+		}
+		if *report != want {
+			t.Errorf("expected report %+v, got: %+v", want, *report)
+		}
+	})
+	t.Run("no disposition notification", func(t *testing.T) {
+		messages := []string{
+			"From: user@example.com\r\nContent-Type: text/plain\r\n\r\nHello\r\n",
+			"From: user@example.com\r\n\r\nHello\r\n",
+			"From: user@example.com\r\nContent-Type: message/disposition-notification\r\n\r\n" +
+				"Final-Recipient: rfc822; user@example.com\r\nDisposition: displayed\r\n",
+		}
+		for _, message := range messages {
+			if _, err := ParseMDN(strings.NewReader(message)); !errors.Is(err, ErrNoMDN) {
+				t.Errorf("expected error %q, got: %v", ErrNoMDN, err)
+			}
+		}
+	})
+	t.Run("invalid message", func(t *testing.T) {
+		if _, err := ParseMDN(failReadWriteSeekCloser{}); err == nil {
+			t.Error("expected error for failing reader")
+		}
+	})
+}
+
+func TestEMLToMsgFromReader_report(t *testing.T) {
+	original := testMessage(t)
+	dsn, err := NewDSNMsg(original, "mta.example.com", DSNStatus{Action: DSNActionFailed, Status: "5.1.1"})
+	if err != nil {
+		t.Fatalf("failed to create DSN: %s", err)
+	}
+	if err = dsn.From("postmaster@mta.example.com"); err != nil {
+		t.Fatalf("failed to set FROM address: %s", err)
+	}
+	buffer := bytes.NewBuffer(nil)
+	if _, err = dsn.WriteTo(buffer); err != nil {
+		t.Fatalf("failed to write DSN: %s", err)
+	}
+	parsed, err := EMLToMsgFromReader(buffer)
+	if err != nil {
+		t.Fatalf("failed to parse DSN: %s", err)
+	}
+	if parsed.reportType != reportTypeDeliveryStatus {
+		t.Errorf("expected report type %q, got: %q", reportTypeDeliveryStatus, parsed.reportType)
+	}
+	attachments := parsed.GetAttachments()
+	if len(attachments) != 1 || attachments[0].ContentType != typeDeliveryStatus {
+		t.Fatalf("expected the delivery status as attachment, got: %+v", attachments)
+	}
+	if parts := parsed.GetParts(); len(parts) != 2 || parts[1].GetContentType() != typeRFC822Headers {
+		t.Errorf("expected the description and the original headers as parts, got: %+v", parts)
+	}
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// errSourceIsNil indicates that the provided ResettableSource is nil.
+const errSourceIsNil = "resettable source must not be nil"
+
+// ResettableSource is an interface for streaming file content that can be read more than once.
+//
+// A Msg is written more than once if its delivery is retried, or if it is signed, estimated or
+// archived before it is sent. Streaming sources like the body of an HTTP response or an object of
+// a remote storage are consumed after the first read. Implementations of ResettableSource re-fetch
+// or rewind their content in Reset, so that every write of the Msg holds the complete file.
+type ResettableSource interface {
+	io.Reader
+
+	// Reset prepares the source to be read again from the start, i. e. by re-issuing the request
+	// for the content. It is called before each read of the source, except for the first one.
+	Reset() error
+}
+
+// AttachResettable adds an attachment File via a ResettableSource to the Msg.
+//
+// Unlike AttachReader, the content is not read into memory, but streamed from the source each time
+// the Msg is written. The source is reset before it is read again, so that the attachment is
+// complete if the delivery of the Msg is retried.
+//
+// Parameters:
+//   - name: The name of the file to be attached.
+//   - source: The ResettableSource providing the file data to be attached.
+//   - opts: Optional parameters for customizing the attachment.
+//
+// Returns:
+//   - An error if the source is nil, otherwise nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2183
+func (m *Msg) AttachResettable(name string, source ResettableSource, opts ...FileOption) error {
+	if source == nil {
+		return errors.New(errSourceIsNil)
+	}
+	m.attachments = m.appendFile(m.attachments, fileFromResettableSource(name, source), opts...)
+	return nil
+}
+
+// EmbedResettable adds an embedded File via a ResettableSource to the Msg.
+//
+// This method embeds streamed content into the email message, like AttachResettable does for
+// attachments.
+//
+// Parameters:
+//   - name: The name of the embedded file.
+//   - source: The ResettableSource providing the file data to be embedded.
+//   - opts: Optional parameters for customizing the embedded file.
+//
+// Returns:
+//   - An error if the source is nil, otherwise nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2183
+func (m *Msg) EmbedResettable(name string, source ResettableSource, opts ...FileOption) error {
+	if source == nil {
+		return errors.New(errSourceIsNil)
+	}
+	m.embeds = m.appendFile(m.embeds, fileFromResettableSource(name, source), opts...)
+	return nil
+}
+
+// fileFromResettableSource returns a File pointer from a given ResettableSource.
+//
+// The content is copied from the source when writing to an io.Writer. The source is reset before
+// each copy but the first one, regardless of whether the previous copy succeeded. Concurrent writes,
+// i. e. of copies of the Msg created with Clone, are serialized.
+//
+// Parameters:
+//   - name: The name of the file to be represented by the ResettableSource.
+//   - source: The ResettableSource from which the file content will be read.
+//
+// Returns:
+//   - A pointer to the File structure representing the ResettableSource.
+func fileFromResettableSource(name string, source ResettableSource) *File {
+	var mutex sync.Mutex
+	consumed := false
+	return &File{
+		Name:   name,
+		Header: make(map[string][]string),
+		Writer: func(writer io.Writer) (int64, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if consumed {
+				if err := source.Reset(); err != nil {
+					return 0, fmt.Errorf("failed to reset file source: %w", err)
+				}
+			}
+			consumed = true
+			return io.Copy(writer, source)
+		},
+	}
+}
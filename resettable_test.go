@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testResettableSource is a ResettableSource that re-creates its reader on Reset and counts the calls.
+type testResettableSource struct {
+	*strings.Reader
+	content  string
+	failWith error
+	resets   int
+}
+
+func newTestResettableSource(content string) *testResettableSource {
+	return &testResettableSource{Reader: strings.NewReader(content), content: content}
+}
+
+func (s *testResettableSource) Reset() error {
+	s.resets++
+	if s.failWith != nil {
+		return s.failWith
+	}
+	s.Reader = strings.NewReader(s.content)
+	return nil
+}
+
+// testShortWriter is an io.Writer that accepts a single short write and fails afterwards.
+type testShortWriter struct {
+	written bool
+}
+
+func (w *testShortWriter) Write(p []byte) (int, error) {
+	if w.written || len(p) < 4 {
+		return 0, errors.New("short write")
+	}
+	w.written = true
+	return 4, errors.New("short write")
+}
+
+func TestMsg_AttachResettable(t *testing.T) {
+	t.Run("AttachResettable is written completely on each write", func(t *testing.T) {
+		message := testMessage(t)
+		source := newTestResettableSource("This is a test attachment")
+		if err := message.AttachResettable("attachment.txt", source); err != nil {
+			t.Fatalf("failed to attach resettable source: %s", err)
+		}
+		attachments := message.GetAttachments()
+		if len(attachments) != 1 || attachments[0].Name != "attachment.txt" {
+			t.Fatalf("expected attachment named attachment.txt, got: %v", attachments)
+		}
+		for i := 0; i < 3; i++ {
+			buffer := bytes.NewBuffer(nil)
+			if _, err := attachments[0].Writer(buffer); err != nil {
+				t.Fatalf("failed to write attachment: %s", err)
+			}
+			if buffer.String() != "This is a test attachment" {
+				t.Errorf("unexpected attachment content on write %d: %s", i+1, buffer.String())
+			}
+		}
+		if source.resets != 2 {
+			t.Errorf("expected source to be reset 2 times, got: %d", source.resets)
+		}
+	})
+	t.Run("AttachResettable fails on failing reset", func(t *testing.T) {
+		message := testMessage(t)
+		source := newTestResettableSource("test")
+		source.failWith = errors.New("failed to re-fetch")
+		if err := message.AttachResettable("attachment.txt", source); err != nil {
+			t.Fatalf("failed to attach resettable source: %s", err)
+		}
+		file := message.GetAttachments()[0]
+		if _, err := file.Writer(bytes.NewBuffer(nil)); err != nil {
+			t.Fatalf("failed to write attachment: %s", err)
+		}
+		if _, err := file.Writer(bytes.NewBuffer(nil)); !errors.Is(err, source.failWith) {
+			t.Errorf("expected error to wrap the reset error, got: %s", err)
+		}
+	})
+	t.Run("AttachResettable with nil source", func(t *testing.T) {
+		if err := testMessage(t).AttachResettable("attachment.txt", nil); err == nil {
+			t.Error("expected nil source to fail")
+		}
+	})
+}
+
+func TestMsg_EmbedResettable(t *testing.T) {
+	t.Run("EmbedResettable successful", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.EmbedResettable("embed.png", newTestResettableSource("PNG")); err != nil {
+			t.Fatalf("failed to embed resettable source: %s", err)
+		}
+		embeds := message.GetEmbeds()
+		if len(embeds) != 1 || embeds[0].Name != "embed.png" {
+			t.Fatalf("expected embed named embed.png, got: %v", embeds)
+		}
+	})
+	t.Run("EmbedResettable with nil source", func(t *testing.T) {
+		if err := testMessage(t).EmbedResettable("embed.png", nil); err == nil {
+			t.Error("expected nil source to fail")
+		}
+	})
+}
+
+func TestFileFromReadSeeker_rewindsOnFailure(t *testing.T) {
+	file := fileFromReadSeeker("attachment.txt", strings.NewReader("This is a test attachment"))
+	if _, err := file.Writer(&testShortWriter{}); err == nil {
+		t.Fatal("expected writing to a failing writer to fail")
+	}
+	buffer := bytes.NewBuffer(nil)
+	if _, err := file.Writer(buffer); err != nil {
+		t.Fatalf("failed to write attachment: %s", err)
+	}
+	if buffer.String() != "This is a test attachment" {
+		t.Errorf("expected complete content after failed write, got: %s", buffer.String())
+	}
+}
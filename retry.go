@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/textproto"
+	"time"
+)
+
+const (
+	// DefaultRetryAttempts is the maximum number of delivery attempts of SendWithRetry, if the
+	// RetryPolicy does not specify it.
+	DefaultRetryAttempts = 3
+
+	// DefaultRetryBackoff is the delay before the first retry of SendWithRetry, if the RetryPolicy
+	// does not specify it.
+	DefaultRetryBackoff = time.Second
+
+	// DefaultRetryMaxBackoff is the maximum delay between two attempts of SendWithRetry, if the
+	// RetryPolicy does not specify it.
+	DefaultRetryMaxBackoff = time.Second * 30
+)
+
+// RetryPolicy configures the delivery attempts of Client.SendWithRetry. A zero value of a field
+// means that its default is used.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of delivery attempts, including the first one. It defaults
+	// to DefaultRetryAttempts.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry. It defaults to DefaultRetryBackoff.
+	Backoff time.Duration
+
+	// MaxBackoff is the maximum delay between two attempts. It defaults to DefaultRetryMaxBackoff.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor by which the delay grows after each retry. It defaults to 2.
+	Multiplier float64
+
+	// Jitter is the fraction of the delay, between 0 and 1, by which the delay is randomly
+	// shortened, so that clients that failed at the same time do not retry at the same time.
+	Jitter float64
+
+	// ShouldRetry reports whether the delivery is retried after the given error. By default, only
+	// temporary errors are retried, as reported by SendError.IsTemp.
+	ShouldRetry func(err error) bool
+}
+
+// IsPermanentSMTPError reports whether the given error holds a permanent negative reply of the SMTP
+// server, i. e. a 5xx reply code.
+//
+// A permanent reply means that the server will reject the same transaction again, so that the
+// delivery must not be retried unchanged. Errors that do not originate from a server reply, like
+// network errors, are not considered permanent.
+//
+// Parameters:
+//   - err: The error to check, i. e. the SendError returned by Client.Send.
+//
+// Returns:
+//   - true if the error holds a 5xx reply of the SMTP server, false otherwise.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5321#section-4.2.1
+func IsPermanentSMTPError(err error) bool {
+	var sendErr *SendError
+	if errors.As(err, &sendErr) {
+		return sendErr.Code() >= 500 && sendErr.Code() < 600
+	}
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 500 && protoErr.Code < 600
+	}
+	return false
+}
+
+// SendWithRetry sends the given Msg like Send and retries its delivery according to the provided
+// RetryPolicy.
+//
+// This method uses a background context. It is equivalent to SendWithRetryContext with
+// context.Background.
+//
+// Parameters:
+//   - message: A pointer to the Msg to be sent.
+//   - policy: The RetryPolicy that controls the number of attempts and the delays between them.
+//
+// Returns:
+//   - The error of the last delivery attempt, or nil if the Msg has been delivered.
+func (c *Client) SendWithRetry(message *Msg, policy RetryPolicy) error {
+	return c.SendWithRetryContext(context.Background(), message, policy)
+}
+
+// SendWithRetryContext sends the given Msg like SendWithContext and retries its delivery according
+// to the provided RetryPolicy.
+//
+// If the connection of the Client is not usable before an attempt, i. e. because the server closed
+// it after a temporary error, the connection is re-established with DialWithContext first. The delay
+// between two attempts grows exponentially, starting with the Backoff of the RetryPolicy, and is
+// extended to the delay hinted by the server as reported by SendError.RetryAfter, but never exceeds
+// the MaxBackoff. A Msg that has been delivered to some of its recipients with WithPartialDelivery is
+// not retried.
+//
+// Parameters:
+//   - ctx: The context.Context that controls the cancellation of the delivery and of the delays.
+//   - message: A pointer to the Msg to be sent.
+//   - policy: The RetryPolicy that controls the number of attempts and the delays between them.
+//
+// Returns:
+//   - The error of the last delivery attempt, or the error of the context if it is done while
+//     waiting for the next attempt; nil if the Msg has been delivered.
+func (c *Client) SendWithRetryContext(ctx context.Context, message *Msg, policy RetryPolicy) error {
+	policy = policy.withDefaults()
+	delay := policy.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = c.sendAttempt(ctx, message)
+		if err == nil || message.IsDelivered() || attempt >= policy.MaxAttempts || !policy.ShouldRetry(err) {
+			return err
+		}
+
+		wait := delay
+		var sendErr *SendError
+		if errors.As(err, &sendErr) {
+			if retryAfter, ok := sendErr.RetryAfter(); ok && retryAfter > wait {
+				wait = retryAfter
+			}
+		}
+		if wait > policy.MaxBackoff {
+			wait = policy.MaxBackoff
+		}
+		if policy.Jitter > 0 {
+			wait -= time.Duration(rand.Float64() * policy.Jitter * float64(wait))
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay = time.Duration(float64(delay) * policy.Multiplier)
+	}
+}
+
+// sendAttempt sends the given Msg, after re-establishing the connection to the server if the
+// connection of the Client is not usable.
+//
+// Parameters:
+//   - ctx: The context.Context that controls the connection and the delivery.
+//   - message: A pointer to the Msg to be sent.
+//
+// Returns:
+//   - A SendError if the connection or the delivery fails; otherwise, returns nil.
+func (c *Client) sendAttempt(ctx context.Context, message *Msg) error {
+	if err := c.checkConn(); err != nil {
+		if c.smtpClient != nil {
+			_ = c.smtpClient.Close()
+		}
+		if err = c.DialWithContext(ctx); err != nil {
+			return &SendError{
+				Reason: ErrConnCheck, errlist: []error{err}, isTemp: ctx.Err() == nil,
+				affectedMsg: message,
+			}
+		}
+	}
+	return c.SendWithContext(ctx, message)
+}
+
+// withDefaults returns the RetryPolicy with the defaults applied to the fields that are not set.
+//
+// Returns:
+//   - The RetryPolicy with all fields set.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultRetryAttempts
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = DefaultRetryBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.Jitter > 1 {
+		p.Jitter = 1
+	}
+	if p.ShouldRetry == nil {
+		p.ShouldRetry = func(err error) bool {
+			var sendErr *SendError
+			return errors.As(err, &sendErr) && sendErr.IsTemp()
+		}
+	}
+	return p
+}
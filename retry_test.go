@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/wneessen/go-mail/log"
+)
+
+func TestIsPermanentSMTPError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil error", nil, false},
+		{"permanent server reply", &textproto.Error{Code: 550, Msg: "5.1.1 User unknown"}, true},
+		{"temporary server reply", &textproto.Error{Code: 451, Msg: "4.3.0 Try again later"}, false},
+		{
+			"SendError with permanent server reply",
+			&SendError{Reason: ErrSMTPRcptTo, errlist: []error{&textproto.Error{Code: 554, Msg: "rejected"}}},
+			true,
+		},
+		{
+			"SendError with temporary server reply",
+			&SendError{Reason: ErrSMTPData, errlist: []error{&textproto.Error{Code: 421, Msg: "closing"}}, isTemp: true},
+			false,
+		},
+		{"SendError without server reply", &SendError{Reason: ErrGetSender, errlist: []error{ErrNoFromAddress}}, false},
+		{"network error", errors.New("connection reset by peer"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermanentSMTPError(tt.err); got != tt.want {
+				t.Errorf("IsPermanentSMTPError() = %t, want: %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_withDefaults(t *testing.T) {
+	policy := RetryPolicy{Jitter: 2}.withDefaults()
+	if policy.MaxAttempts != DefaultRetryAttempts || policy.Backoff != DefaultRetryBackoff ||
+		policy.MaxBackoff != DefaultRetryMaxBackoff || policy.Multiplier != 2 || policy.Jitter != 1 {
+		t.Errorf("unexpected defaults of the retry policy: %+v", policy)
+	}
+	if policy.ShouldRetry(&SendError{Reason: ErrSMTPData, isTemp: false}) {
+		t.Error("expected permanent SendError not to be retried by default")
+	}
+	if !policy.ShouldRetry(&SendError{Reason: ErrSMTPData, isTemp: true}) {
+		t.Error("expected temporary SendError to be retried by default")
+	}
+}
+
+func TestClient_SendWithRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		props        serverProps
+		policy       RetryPolicy
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"delivery succeeds", serverProps{}, RetryPolicy{Backoff: time.Millisecond}, 1, false},
+		{"temporary error is retried", serverProps{FailTemp: true}, RetryPolicy{Backoff: time.Millisecond}, 3, true},
+		{
+			"permanent error is not retried", serverProps{FailOnDataClose: true},
+			RetryPolicy{Backoff: time.Millisecond}, 1, true,
+		},
+		{
+			"custom retry decision", serverProps{FailOnDataClose: true},
+			RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond, ShouldRetry: func(error) bool { return true }},
+			2, true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			PortAdder.Add(1)
+			serverPort := int(TestServerPortBase + PortAdder.Load())
+			props := tt.props
+			props.FeatureSet = "250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+			props.ListenPort = serverPort
+			go func() {
+				if err := simpleSMTPServer(ctx, t, &props); err != nil {
+					t.Errorf("failed to start test server: %s", err)
+					return
+				}
+			}()
+			time.Sleep(time.Millisecond * 30)
+
+			attempts := 0
+			client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS),
+				WithDebugHook(func(direction log.Direction, line string) {
+					if direction == log.DirClientToServer && line == "DATA" {
+						attempts++
+					}
+				}))
+			if err != nil {
+				t.Fatalf("failed to create new client: %s", err)
+			}
+			t.Cleanup(func() {
+				_ = client.Close()
+			})
+			err = client.SendWithRetry(testMessage(t), tt.policy)
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				t.Skip("failed to connect to the test server due to timeout")
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("unexpected error of SendWithRetry: %v", err)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("expected %d delivery attempts, got: %d", tt.wantAttempts, attempts)
+			}
+		})
+	}
+	t.Run("context is done while waiting for the next attempt", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FailTemp:   true,
+				FeatureSet: "250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8",
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		t.Cleanup(func() {
+			_ = client.Close()
+		})
+		ctxSend, cancelSend := context.WithTimeout(ctx, time.Millisecond*200)
+		defer cancelSend()
+		err = client.SendWithRetryContext(ctxSend, testMessage(t), RetryPolicy{Backoff: time.Hour})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected error to be %s, got: %v", context.DeadlineExceeded, err)
+		}
+	})
+}
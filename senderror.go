@@ -5,8 +5,28 @@
 package mail
 
 import (
+	"encoding/json"
 	"errors"
+	"net/textproto"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+)
+
+// DefaultRetryAfter is the retry delay returned by SendError.RetryAfter if the server signaled
+// throttling or a quota violation without providing an explicit delay.
+const DefaultRetryAfter = time.Minute * 15
+
+var (
+	// retryAfterRegexp matches explicit retry delays in SMTP server responses, like "try again in
+	// 3600 seconds" or "retry after 5 minutes".
+	retryAfterRegexp = regexp.MustCompile(`(?:try again|retry|wait)(?: after| in)?\s+(\d+)\s*` +
+		`(seconds?|secs?|s|minutes?|mins?|m|hours?|hrs?|h)\b`)
+
+	// throttlingHints holds the lower-cased enhanced status codes and phrases that SMTP servers use
+	// to signal throttling or quota violations.
+	throttlingHints = []string{"4.7.28", "throttl", "rate limit", "too many", "quota"}
 )
 
 // List of SendError reasons
@@ -52,6 +72,34 @@ const (
 	// ErrAmbiguous is a generalized delivery error for the SendError type that is
 	// returned if the exact reason for the delivery failure is ambiguous
 	ErrAmbiguous
+
+	// ErrContextDone is returned if the Msg delivery was aborted because the context.Context
+	// was cancelled or its deadline expired
+	ErrContextDone
+
+	// ErrPolicyViolation is returned if the Msg delivery was aborted because the Msg violates
+	// the content policy of the Client
+	ErrPolicyViolation
+
+	// ErrMessageTooLarge is returned if the Msg delivery was aborted because the estimated size
+	// of the Msg exceeds the maximum message size of the Client or the SMTP server
+	ErrMessageTooLarge
+
+	// ErrQuotaExceeded is returned if the Msg delivery was aborted because the QuotaManager of the
+	// Client rejected the Msg
+	ErrQuotaExceeded
+
+	// ErrRequireTLS is returned if the Msg delivery was aborted because the Msg requires TLS as
+	// defined in RFC 8689, but the connection to the SMTP server cannot guarantee it
+	ErrRequireTLS
+
+	// ErrNoSMTPUTF8 is returned if the Msg delivery failed because the Msg has internationalized
+	// addresses, but the server does not support SMTPUTF8
+	ErrNoSMTPUTF8
+
+	// ErrRcptGraylisted is returned if the Msg delivery was aborted because all of its recipients
+	// are on the recipient graylist of the Client
+	ErrRcptGraylisted
 )
 
 // SendError is an error wrapper for delivery errors of the Msg.
@@ -61,12 +109,29 @@ const (
 // the error is temporary or permanent. It also includes a reason code for the error.
 type SendError struct {
 	affectedMsg *Msg
+	banner      string
 	errlist     []error
 	isTemp      bool
+	msgIDs      []string
 	rcpt        []string
+	rejected    []RecipientResult
 	Reason      SendErrReason
 }
 
+// sendErrorFields holds the structured fields of a SendError, as they are used for its JSON
+// representation and its structured log value.
+type sendErrorFields struct {
+	Reason       string   `json:"reason"`
+	Temporary    bool     `json:"temporary"`
+	Stage        string   `json:"stage,omitempty"`
+	Code         int      `json:"code,omitempty"`
+	EnhancedCode string   `json:"enhanced_code,omitempty"`
+	ServerBanner string   `json:"server_banner,omitempty"`
+	Recipients   []string `json:"recipients,omitempty"`
+	MessageIDs   []string `json:"message_ids,omitempty"`
+	Errors       []string `json:"errors,omitempty"`
+}
+
 // SendErrReason represents a comparable reason on why the delivery failed
 type SendErrReason int
 
@@ -74,14 +139,14 @@ type SendErrReason int
 //
 // This function returns a detailed error message string for the SendError, including the
 // reason for failure, list of errors, affected recipients, and the message ID of the
-// affected message (if available). If the reason is unknown (greater than 10), it returns
-// "unknown reason". The error message is built dynamically based on the content of the
-// error list, recipient list, and message ID.
+// affected message (if available). If the reason is unknown, it returns "unknown reason".
+// The error message is built dynamically based on the content of the error list, recipient
+// list, and message ID.
 //
 // Returns:
 //   - A string representing the error message.
 func (e *SendError) Error() string {
-	if e.Reason > ErrAmbiguous {
+	if e.Reason > ErrRcptGraylisted {
 		return "unknown reason"
 	}
 
@@ -133,6 +198,24 @@ func (e *SendError) Is(errType error) bool {
 	return false
 }
 
+// Unwrap returns the list of errors that caused the SendError.
+//
+// Since Go 1.20, errors.Is and errors.As traverse the returned errors, so that they match the
+// underlying errors of the SendError in addition to the SendError itself. For example,
+// errors.Is(err, context.Canceled) reports true for the SendError of an aborted delivery, and
+// errors.As with a *textproto.Error target yields the reply of the SMTP server. Comparisons with a
+// SendError target are still handled by SendError.Is and match on the reason and temporary status
+// only. With earlier Go versions the wrapped errors are only accessible through Unwrap.
+//
+// Returns:
+//   - A slice of the errors wrapped by the SendError, or nil if the SendError is nil.
+func (e *SendError) Unwrap() []error {
+	if e == nil {
+		return nil
+	}
+	return e.errlist
+}
+
 // IsTemp returns true if the delivery error is of a temporary nature and can be retried.
 //
 // This function checks whether the SendError indicates a temporary error, which suggests
@@ -147,6 +230,54 @@ func (e *SendError) IsTemp() bool {
 	return e.isTemp
 }
 
+// RetryAfter returns the delay after which the delivery should be retried, as hinted by the SMTP
+// server.
+//
+// This function inspects the server responses of temporary delivery errors for throttling hints.
+// Explicit delays, like "try again in 3600 seconds", are returned as is. If the server signaled
+// throttling or a quota violation without an explicit delay, i. e. by using the enhanced status
+// code 4.7.28, DefaultRetryAfter is returned. Permanent errors never carry a retry hint.
+//
+// Returns:
+//   - The delay after which the delivery should be retried.
+//   - A boolean indicating whether the server provided a throttling hint.
+func (e *SendError) RetryAfter() (time.Duration, bool) {
+	if e == nil || !e.isTemp {
+		return 0, false
+	}
+	hasHint := false
+	for _, err := range e.errlist {
+		if err == nil {
+			continue
+		}
+		delay, ok, isThrottled := parseRetryAfter(err.Error())
+		if ok {
+			return delay, true
+		}
+		hasHint = hasHint || isThrottled
+	}
+	if hasHint {
+		return DefaultRetryAfter, true
+	}
+	return 0, false
+}
+
+// RejectedRecipients returns the response of the SMTP server for each recipient that has been
+// rejected with the RCPT TO command.
+//
+// With WithPartialDelivery, the Msg is still delivered to the accepted recipients and the returned
+// SendError only reports the rejected recipients. In this case, Msg.IsDelivered returns true. If the
+// SendError is nil or does not originate from the RCPT TO command, it returns nil.
+//
+// Returns:
+//   - A slice of RecipientResult for the rejected recipients, in the order the recipients were sent.
+func (e *SendError) RejectedRecipients() []RecipientResult {
+	if e == nil {
+		return nil
+	}
+	return e.rejected
+}
+
 // MessageID returns the message ID of the affected Msg that caused the error.
 //
 // This function retrieves the message ID of the Msg associated with the SendError.
@@ -161,6 +292,107 @@ func (e *SendError) MessageID() string {
 	return e.affectedMsg.GetMessageID()
 }
 
+// MessageIDs returns the message IDs of all messages affected by the error.
+//
+// For the SendError of a single Msg, this is the message ID of the Msg, if it has one. For an
+// aggregated SendError with the reason ErrAmbiguous, it holds the message IDs of all the messages
+// that failed to be delivered.
+//
+// Returns:
+//   - A slice of the affected message IDs, or nil if no message ID is available.
+func (e *SendError) MessageIDs() []string {
+	if e == nil {
+		return nil
+	}
+	if e.affectedMsg != nil {
+		if messageID := e.affectedMsg.GetMessageID(); messageID != "" {
+			return []string{messageID}
+		}
+		return nil
+	}
+	return e.msgIDs
+}
+
+// Code returns the SMTP reply code of the server response that caused the error.
+//
+// Returns:
+//   - The reply code of the first server response among the wrapped errors, i. e. 550, or 0 if
+//     the error was not caused by a server response.
+func (e *SendError) Code() int {
+	if protoErr := e.protoError(); protoErr != nil {
+		return protoErr.Code
+	}
+	return 0
+}
+
+// EnhancedCode returns the enhanced status code of the server response that caused the error.
+//
+// Returns:
+//   - The enhanced status code of the first server response among the wrapped errors, i. e.
+//     "5.1.1", or an empty string if the server did not provide an enhanced status code.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc3463
+func (e *SendError) EnhancedCode() string {
+	if protoErr := e.protoError(); protoErr != nil {
+		return enhancedStatusCode(protoErr.Msg)
+	}
+	return ""
+}
+
+// Stage returns the SMTP command during which the delivery failed.
+//
+// Returns:
+//   - The SMTP command, i. e. "MAIL FROM", "RCPT TO" or "DATA", or an empty string if the error
+//     occurred outside of the SMTP transaction, i. e. while checking the Msg before its delivery.
+func (e *SendError) Stage() string {
+	if e == nil {
+		return ""
+	}
+	switch e.Reason {
+	case ErrSMTPMailFrom:
+		return "MAIL FROM"
+	case ErrSMTPRcptTo:
+		return "RCPT TO"
+	case ErrSMTPData, ErrSMTPDataClose, ErrWriteContent:
+		return "DATA"
+	case ErrSMTPReset:
+		return "RSET"
+	case ErrConnCheck:
+		return "NOOP"
+	}
+	return ""
+}
+
+// ServerBanner returns the greeting message of the SMTP server the delivery failed on.
+//
+// Returns:
+//   - The greeting message without the reply code, or an empty string if the error occurred
+//     without an established connection.
+func (e *SendError) ServerBanner() string {
+	if e == nil {
+		return ""
+	}
+	return e.banner
+}
+
+// MarshalJSON implements the json.Marshaler interface for the SendError type.
+//
+// The JSON object holds the reason, the temporary status, the failed SMTP command, the reply code
+// and enhanced status code of the server, the server banner, the affected recipients and message
+// IDs and the wrapped error messages, so that delivery errors can be passed to APIs without
+// parsing the error string.
+//
+// Returns:
+//   - The JSON encoding of the SendError.
+//   - An error if the encoding fails.
+func (e *SendError) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(e.fields())
+}
+
 // Msg returns the pointer to the affected message that caused the error.
 //
 // This function retrieves the Msg associated with the SendError. If the SendError or
@@ -207,10 +439,65 @@ func (r SendErrReason) String() string {
 		return ErrServerNoUnencoded.Error()
 	case ErrAmbiguous:
 		return "ambiguous reason, check Msg.SendError for message specific reasons"
+	case ErrContextDone:
+		return "context done"
+	case ErrPolicyViolation:
+		return "checking content policy"
+	case ErrMessageTooLarge:
+		return "checking maximum message size"
+	case ErrQuotaExceeded:
+		return "checking sender quota"
+	case ErrRequireTLS:
+		return "checking TLS requirement"
+	case ErrNoSMTPUTF8:
+		return ErrServerNoSMTPUTF8.Error()
+	case ErrRcptGraylisted:
+		return "checking recipient graylist"
 	}
 	return "unknown reason"
 }
 
+// protoError returns the first server response among the errors wrapped by the SendError.
+//
+// Returns:
+//   - A pointer to the textproto.Error of the server response, or nil if none of the wrapped errors
+//     is a server response.
+func (e *SendError) protoError() *textproto.Error {
+	if e == nil {
+		return nil
+	}
+	for _, err := range e.errlist {
+		var protoErr *textproto.Error
+		if errors.As(err, &protoErr) {
+			return protoErr
+		}
+	}
+	return nil
+}
+
+// fields returns the structured fields of the SendError.
+//
+// Returns:
+//   - The sendErrorFields of the SendError.
+func (e *SendError) fields() sendErrorFields {
+	fields := sendErrorFields{
+		Reason:       e.Reason.String(),
+		Temporary:    e.isTemp,
+		Stage:        e.Stage(),
+		Code:         e.Code(),
+		EnhancedCode: e.EnhancedCode(),
+		ServerBanner: e.banner,
+		Recipients:   e.rcpt,
+		MessageIDs:   e.MessageIDs(),
+	}
+	for _, err := range e.errlist {
+		if err != nil {
+			fields.Errors = append(fields.Errors, err.Error())
+		}
+	}
+	return fields
+}
+
 // isTempError checks if the given SMTP error is of a temporary nature and should be retried.
 //
 // This function inspects the error message and returns true if the first character of the
@@ -224,3 +511,35 @@ func (r SendErrReason) String() string {
 func isTempError(err error) bool {
 	return err.Error()[0] == '4'
 }
+
+// parseRetryAfter extracts a throttling hint from the given SMTP server response.
+//
+// Parameters:
+//   - response: The server response to inspect.
+//
+// Returns:
+//   - The explicit retry delay found in the response.
+//   - A boolean indicating whether an explicit retry delay was found.
+//   - A boolean indicating whether the response signals throttling or a quota violation.
+func parseRetryAfter(response string) (time.Duration, bool, bool) {
+	response = strings.ToLower(response)
+	if matches := retryAfterRegexp.FindStringSubmatch(response); matches != nil {
+		value, err := strconv.Atoi(matches[1])
+		if err == nil && value > 0 {
+			unit := time.Second
+			switch matches[2][0] {
+			case 'm':
+				unit = time.Minute
+			case 'h':
+				unit = time.Hour
+			}
+			return time.Duration(value) * unit, true, true
+		}
+	}
+	for _, hint := range throttlingHints {
+		if strings.Contains(response, hint) {
+			return 0, false, true
+		}
+	}
+	return 0, false, false
+}
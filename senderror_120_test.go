@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build go1.20
+// +build go1.20
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"net/textproto"
+	"testing"
+)
+
+func TestSendError_Unwrap_errors(t *testing.T) {
+	protoErr := &textproto.Error{Code: 550, Msg: "5.1.1 User unknown"}
+	err := error(&SendError{
+		Reason: ErrSMTPRcptTo, errlist: []error{protoErr, context.Canceled}, isTemp: false,
+	})
+	t.Run("errors.Is matches the wrapped errors", func(t *testing.T) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected error to match: %s", context.Canceled)
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected error not to match: %s", context.DeadlineExceeded)
+		}
+	})
+	t.Run("errors.Is still compares SendError reasons", func(t *testing.T) {
+		if !errors.Is(err, &SendError{Reason: ErrSMTPRcptTo}) {
+			t.Error("expected error to match the SendError with the same reason")
+		}
+		if errors.Is(err, &SendError{Reason: ErrSMTPData}) {
+			t.Error("expected error not to match the SendError with a different reason")
+		}
+	})
+	t.Run("errors.As finds the wrapped server reply", func(t *testing.T) {
+		var target *textproto.Error
+		if !errors.As(err, &target) {
+			t.Fatal("expected to find the wrapped textproto.Error")
+		}
+		if target.Code != 550 {
+			t.Errorf("expected reply code: %d, got: %d", 550, target.Code)
+		}
+	})
+}
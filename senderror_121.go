@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build go1.21
+// +build go1.21
+
+package mail
+
+import (
+	"log/slog"
+)
+
+// LogValue implements the slog.LogValuer interface for the SendError type.
+//
+// The SendError is logged as a group with the same fields as its JSON representation, so that
+// delivery errors can be put into structured logs without parsing the error string. Empty fields
+// are omitted.
+//
+// Returns:
+//   - A slog.Value of the kind slog.KindGroup holding the structured fields of the SendError.
+func (e *SendError) LogValue() slog.Value {
+	if e == nil {
+		return slog.Value{}
+	}
+	fields := e.fields()
+	attrs := []slog.Attr{
+		slog.String("reason", fields.Reason),
+		slog.Bool("temporary", fields.Temporary),
+	}
+	if fields.Stage != "" {
+		attrs = append(attrs, slog.String("stage", fields.Stage))
+	}
+	if fields.Code != 0 {
+		attrs = append(attrs, slog.Int("code", fields.Code))
+	}
+	if fields.EnhancedCode != "" {
+		attrs = append(attrs, slog.String("enhanced_code", fields.EnhancedCode))
+	}
+	if fields.ServerBanner != "" {
+		attrs = append(attrs, slog.String("server_banner", fields.ServerBanner))
+	}
+	if len(fields.Recipients) > 0 {
+		attrs = append(attrs, slog.Any("recipients", fields.Recipients))
+	}
+	if len(fields.MessageIDs) > 0 {
+		attrs = append(attrs, slog.Any("message_ids", fields.MessageIDs))
+	}
+	if len(fields.Errors) > 0 {
+		attrs = append(attrs, slog.Any("errors", fields.Errors))
+	}
+	return slog.GroupValue(attrs...)
+}
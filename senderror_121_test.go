@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build go1.21
+// +build go1.21
+
+package mail
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/textproto"
+	"testing"
+)
+
+func TestSendError_LogValue(t *testing.T) {
+	sendErr := &SendError{
+		errlist: []error{&textproto.Error{Code: 452, Msg: "4.2.2 Mailbox full"}},
+		isTemp:  true,
+		rcpt:    []string{"<full@domain.tld>"},
+		Reason:  ErrSMTPRcptTo,
+	}
+	buffer := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	logger.Error("delivery failed", "error", sendErr)
+
+	var entry struct {
+		Error map[string]interface{} `json:"error"`
+	}
+	if err := json.Unmarshal(buffer.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to unmarshal log entry: %s", err)
+	}
+	if entry.Error["reason"] != "sending SMTP RCPT TO command" || entry.Error["temporary"] != true ||
+		entry.Error["code"] != float64(452) || entry.Error["enhanced_code"] != "4.2.2" ||
+		entry.Error["stage"] != "RCPT TO" {
+		t.Errorf("unexpected log value: %s", buffer.String())
+	}
+	if _, ok := entry.Error["server_banner"]; ok {
+		t.Errorf("expected empty server banner to be omitted: %s", buffer.String())
+	}
+}
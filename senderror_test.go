@@ -5,9 +5,13 @@
 package mail
 
 import (
+	"encoding/json"
 	"errors"
+	"net/textproto"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestSendError_Error tests the SendError and SendErrReason error handling methods
@@ -40,6 +44,20 @@ func TestSendError_Error(t *testing.T) {
 			{"ErrNoUnencoded/perm", ErrNoUnencoded, false},
 			{"ErrAmbiguous/temp", ErrAmbiguous, true},
 			{"ErrAmbiguous/perm", ErrAmbiguous, false},
+			{"ErrContextDone/temp", ErrContextDone, true},
+			{"ErrContextDone/perm", ErrContextDone, false},
+			{"ErrPolicyViolation/temp", ErrPolicyViolation, true},
+			{"ErrPolicyViolation/perm", ErrPolicyViolation, false},
+			{"ErrMessageTooLarge/temp", ErrMessageTooLarge, true},
+			{"ErrMessageTooLarge/perm", ErrMessageTooLarge, false},
+			{"ErrQuotaExceeded/temp", ErrQuotaExceeded, true},
+			{"ErrQuotaExceeded/perm", ErrQuotaExceeded, false},
+			{"ErrRequireTLS/temp", ErrRequireTLS, true},
+			{"ErrRequireTLS/perm", ErrRequireTLS, false},
+			{"ErrNoSMTPUTF8/temp", ErrNoSMTPUTF8, true},
+			{"ErrNoSMTPUTF8/perm", ErrNoSMTPUTF8, false},
+			{"ErrRcptGraylisted/temp", ErrRcptGraylisted, true},
+			{"ErrRcptGraylisted/perm", ErrRcptGraylisted, false},
 			{"Unknown/temp", 9999, true},
 			{"Unknown/perm", 9999, false},
 		}
@@ -108,6 +126,25 @@ func TestSendError_Is(t *testing.T) {
 	})
 }
 
+func TestSendError_Unwrap(t *testing.T) {
+	t.Run("TestSendError_Unwrap returns error list", func(t *testing.T) {
+		err := &SendError{Reason: ErrContextDone, errlist: []error{ErrNoFromAddress, ErrNoRcptAddresses}}
+		errlist := err.Unwrap()
+		if len(errlist) != 2 {
+			t.Fatalf("expected 2 wrapped errors, got: %d", len(errlist))
+		}
+		if !errors.Is(errlist[0], ErrNoFromAddress) || !errors.Is(errlist[1], ErrNoRcptAddresses) {
+			t.Errorf("unexpected wrapped errors: %v", errlist)
+		}
+	})
+	t.Run("TestSendError_Unwrap on nil", func(t *testing.T) {
+		var err *SendError
+		if err.Unwrap() != nil {
+			t.Error("expected nil on nil-senderror")
+		}
+	})
+}
+
 func TestSendError_IsTemp(t *testing.T) {
 	t.Run("TestSendError_IsTemp is true", func(t *testing.T) {
 		err := returnSendError(ErrAmbiguous, true)
@@ -143,6 +180,54 @@ func TestSendError_IsTemp(t *testing.T) {
 	})
 }
 
+func TestSendError_RetryAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		isTemp    bool
+		wantDelay time.Duration
+		wantOK    bool
+	}{
+		{"explicit delay in seconds", errors.New("421 4.7.0 Try again in 3600 seconds"), true, time.Hour, true},
+		{"explicit delay in minutes", errors.New("450 4.2.1 Please retry after 5 minutes"), true, time.Minute * 5, true},
+		{"explicit delay in hours", errors.New("451 Rate limited, wait 2 hours"), true, time.Hour * 2, true},
+		{
+			"enhanced throttling code", errors.New("421 4.7.28 Unusual rate of unsolicited mail"), true,
+			DefaultRetryAfter, true,
+		},
+		{"quota exceeded", errors.New("452 4.2.2 Mailbox quota exceeded"), true, DefaultRetryAfter, true},
+		{"temporary error without hint", errors.New("451 4.3.0 Local error in processing"), true, 0, false},
+		{"permanent error with delay", errors.New("550 5.7.1 Try again in 60 seconds"), false, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sendErr := &SendError{Reason: ErrSMTPRcptTo, errlist: []error{tt.err}, isTemp: tt.isTemp}
+			delay, ok := sendErr.RetryAfter()
+			if ok != tt.wantOK {
+				t.Errorf("expected retry hint: %t, got: %t", tt.wantOK, ok)
+			}
+			if delay != tt.wantDelay {
+				t.Errorf("expected retry delay: %s, got: %s", tt.wantDelay, delay)
+			}
+		})
+	}
+	t.Run("explicit delay takes precedence over throttling hint", func(t *testing.T) {
+		sendErr := &SendError{Reason: ErrSMTPRcptTo, isTemp: true, errlist: []error{
+			errors.New("421 4.7.28 Too many messages"),
+			errors.New("421 4.7.28 Too many messages, try again in 30s"),
+		}}
+		if delay, ok := sendErr.RetryAfter(); !ok || delay != time.Second*30 {
+			t.Errorf("expected retry delay: %s, got: %s", time.Second*30, delay)
+		}
+	})
+	t.Run("nil SendError has no retry hint", func(t *testing.T) {
+		var sendErr *SendError
+		if _, ok := sendErr.RetryAfter(); ok {
+			t.Error("expected no retry hint on nil SendError")
+		}
+	})
+}
+
 func TestSendError_MessageID(t *testing.T) {
 	t.Run("TestSendError_MessageID message ID is set", func(t *testing.T) {
 		var sendErr *SendError
@@ -182,6 +267,73 @@ func TestSendError_MessageID(t *testing.T) {
 	})
 }
 
+func TestSendError_structuredFields(t *testing.T) {
+	message := testMessage(t)
+	message.SetMessageIDWithValue("this.is.a.message.id")
+	sendErr := &SendError{
+		affectedMsg: message,
+		banner:      "go-mail test server ready ESMTP",
+		errlist:     []error{&textproto.Error{Code: 550, Msg: "5.1.1 User unknown"}},
+		rcpt:        []string{"<invalid@domain.tld>"},
+		Reason:      ErrSMTPRcptTo,
+	}
+	t.Run("accessors return the structured fields", func(t *testing.T) {
+		if sendErr.Code() != 550 {
+			t.Errorf("expected code 550, got: %d", sendErr.Code())
+		}
+		if sendErr.EnhancedCode() != "5.1.1" {
+			t.Errorf("expected enhanced code 5.1.1, got: %s", sendErr.EnhancedCode())
+		}
+		if sendErr.Stage() != "RCPT TO" {
+			t.Errorf("expected stage RCPT TO, got: %s", sendErr.Stage())
+		}
+		if sendErr.ServerBanner() != "go-mail test server ready ESMTP" {
+			t.Errorf("unexpected server banner: %s", sendErr.ServerBanner())
+		}
+		if ids := sendErr.MessageIDs(); len(ids) != 1 || ids[0] != "<this.is.a.message.id>" {
+			t.Errorf("unexpected message IDs: %v", ids)
+		}
+	})
+	t.Run("accessors without server response", func(t *testing.T) {
+		err := &SendError{errlist: []error{ErrNoRcptAddresses}, Reason: ErrGetRcpts}
+		if err.Code() != 0 || err.EnhancedCode() != "" || err.Stage() != "" || err.MessageIDs() != nil {
+			t.Errorf("expected empty structured fields, got: %d, %q, %q, %v", err.Code(), err.EnhancedCode(),
+				err.Stage(), err.MessageIDs())
+		}
+	})
+	t.Run("accessors on nil error", func(t *testing.T) {
+		var err *SendError
+		if err.Code() != 0 || err.EnhancedCode() != "" || err.Stage() != "" || err.ServerBanner() != "" ||
+			err.MessageIDs() != nil {
+			t.Error("expected empty structured fields on nil-senderror")
+		}
+	})
+	t.Run("MarshalJSON", func(t *testing.T) {
+		data, err := json.Marshal(sendErr)
+		if err != nil {
+			t.Fatalf("failed to marshal SendError: %s", err)
+		}
+		var got map[string]interface{}
+		if err = json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("failed to unmarshal SendError JSON: %s", err)
+		}
+		want := map[string]interface{}{
+			"reason":        "sending SMTP RCPT TO command",
+			"temporary":     false,
+			"stage":         "RCPT TO",
+			"code":          float64(550),
+			"enhanced_code": "5.1.1",
+			"server_banner": "go-mail test server ready ESMTP",
+			"recipients":    []interface{}{"<invalid@domain.tld>"},
+			"message_ids":   []interface{}{"<this.is.a.message.id>"},
+			"errors":        []interface{}{sendErr.errlist[0].Error()},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected JSON representation: %s", data)
+		}
+	})
+}
+
 func TestSendError_Msg(t *testing.T) {
 	t.Run("TestSendError_Msg message is set", func(t *testing.T) {
 		var sendErr *SendError
@@ -0,0 +1,352 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidSendmailEnvelopeFrom is returned if the envelope sender for sendmail is empty or
+	// could be mistaken for a command line flag.
+	ErrInvalidSendmailEnvelopeFrom = errors.New("invalid sendmail envelope sender address")
+
+	// ErrInvalidSendmailArg is returned if an extra argument for sendmail is empty.
+	ErrInvalidSendmailArg = errors.New("sendmail argument cannot be empty")
+)
+
+type (
+	// SendmailTransport is a Transport that delivers messages by piping them to the local sendmail
+	// binary, so that local delivery and SMTP delivery via the Client become interchangeable.
+	SendmailTransport struct {
+		// opts holds the SendmailOption values that build the command line for each message.
+		opts []SendmailOption
+
+		// path is the path to the sendmail executable.
+		path string
+
+		// timeout is the timeout for the delivery of a single message.
+		timeout time.Duration
+	}
+
+	// SendmailOption is a function type that configures the command line of the sendmail binary
+	// used by Msg.WriteToSendmailWithOptions.
+	SendmailOption func(*sendmailOptions) error
+
+	// sendmailOptions holds the configuration of the sendmail command line.
+	sendmailOptions struct {
+		// args holds extra arguments that are appended to the generated arguments.
+		args []string
+
+		// dsnNotify holds the DSN notification types for the "-N" flag.
+		dsnNotify []string
+
+		// dsnReturn holds the DSN return type for the "-R" flag.
+		dsnReturn DSNMailReturnOption
+
+		// envelopeFrom holds the envelope sender address for the "-f" flag.
+		envelopeFrom string
+
+		// explicitRecipients indicates that the recipients are passed as arguments instead of being
+		// read from the message headers.
+		explicitRecipients bool
+
+		// noDefaultArgs indicates that the default "-oi" and "-t" arguments are omitted.
+		noDefaultArgs bool
+	}
+)
+
+// WithSendmailEnvelopeFrom sets the envelope sender address that is passed to sendmail with
+// the "-f" flag.
+//
+// Without this option, sendmail determines the envelope sender itself, usually from the user
+// running the process. The address is parsed like any other mail address and only the address
+// part is used.
+//
+// Parameters:
+//   - from: The envelope sender address.
+//
+// Returns:
+//   - A SendmailOption that sets the envelope sender.
+func WithSendmailEnvelopeFrom(from string) SendmailOption {
+	return func(o *sendmailOptions) error {
+		if strings.HasPrefix(strings.TrimSpace(from), "-") {
+			return ErrInvalidSendmailEnvelopeFrom
+		}
+		address, err := mail.ParseAddress(from)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidSendmailEnvelopeFrom, err)
+		}
+		o.envelopeFrom = address.Address
+		return nil
+	}
+}
+
+// WithSendmailDSN requests Delivery Status Notifications (DSN) from sendmail by passing the
+// "-N" flag with the given notification types.
+//
+// The same validation rules as for the Client option WithDSNRcptNotifyType apply, i. e.
+// DSNRcptNotifyNever cannot be combined with any other notification type.
+//
+// Parameters:
+//   - opts: The DSNRcptNotifyOption values to request.
+//
+// Returns:
+//   - A SendmailOption that sets the DSN notification types.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc1891
+func WithSendmailDSN(opts ...DSNRcptNotifyOption) SendmailOption {
+	return func(o *sendmailOptions) error {
+		var notify []string
+		var never, other bool
+		for _, opt := range opts {
+			switch opt {
+			case DSNRcptNotifyNever:
+				never = true
+			case DSNRcptNotifySuccess, DSNRcptNotifyFailure, DSNRcptNotifyDelay:
+				other = true
+			default:
+				return ErrInvalidDSNRcptNotifyOption
+			}
+			notify = append(notify, strings.ToLower(string(opt)))
+		}
+		if never && other {
+			return ErrInvalidDSNRcptNotifyCombination
+		}
+		o.dsnNotify = notify
+		return nil
+	}
+}
+
+// WithSendmailDSNReturn sets the DSN return type that is passed to sendmail with the "-R" flag.
+//
+// Parameters:
+//   - option: The DSNMailReturnOption to request (DSNMailReturnHeadersOnly or DSNMailReturnFull).
+//
+// Returns:
+//   - A SendmailOption that sets the DSN return type.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc1891
+func WithSendmailDSNReturn(option DSNMailReturnOption) SendmailOption {
+	return func(o *sendmailOptions) error {
+		switch option {
+		case DSNMailReturnHeadersOnly, DSNMailReturnFull:
+		default:
+			return ErrInvalidDSNMailReturnOption
+		}
+		o.dsnReturn = option
+		return nil
+	}
+}
+
+// WithSendmailExplicitRecipients passes the envelope recipients of the Msg as arguments to
+// sendmail instead of letting sendmail read them from the message headers with the "-t" flag.
+//
+// This gives full control over the envelope, since sendmail implementations differ in how they
+// treat additional recipients in combination with "-t".
+//
+// Returns:
+//   - A SendmailOption that enables explicit recipients.
+func WithSendmailExplicitRecipients() SendmailOption {
+	return func(o *sendmailOptions) error {
+		o.explicitRecipients = true
+		return nil
+	}
+}
+
+// WithSendmailArgs appends extra arguments to the sendmail command line.
+//
+// Parameters:
+//   - args: The extra arguments for the sendmail binary.
+//
+// Returns:
+//   - A SendmailOption that adds the extra arguments.
+func WithSendmailArgs(args ...string) SendmailOption {
+	return func(o *sendmailOptions) error {
+		for _, arg := range args {
+			if arg == "" {
+				return ErrInvalidSendmailArg
+			}
+		}
+		o.args = append(o.args, args...)
+		return nil
+	}
+}
+
+// WithoutSendmailDefaultArgs omits the default "-oi" and "-t" arguments from the sendmail
+// command line.
+//
+// Returns:
+//   - A SendmailOption that disables the default arguments.
+func WithoutSendmailDefaultArgs() SendmailOption {
+	return func(o *sendmailOptions) error {
+		o.noDefaultArgs = true
+		return nil
+	}
+}
+
+// WriteToSendmailWithOptions opens a pipe to the local sendmail binary and tries to send the
+// email through it, with a command line that is built from the given SendmailOption values.
+//
+// By default, the arguments "-oi" and "-t" are passed, just like with WriteToSendmailWithContext.
+// The options allow to set the envelope sender, to request DSN or to pass the recipients
+// explicitly instead of reading them from the message headers.
+//
+// Parameters:
+//   - ctx: The context to control the timeout and cancellation of the sendmail process.
+//   - sendmailPath: The path to the sendmail executable.
+//   - opts: The SendmailOption values to build the command line with.
+//
+// Returns:
+//   - An error if an option is invalid or sending the message via sendmail fails, otherwise nil.
+func (m *Msg) WriteToSendmailWithOptions(ctx context.Context, sendmailPath string, opts ...SendmailOption) error {
+	args, err := m.sendmailArgs(opts...)
+	if err != nil {
+		return err
+	}
+	return m.writeToSendmail(ctx, sendmailPath, args)
+}
+
+// sendmailArgs builds the sendmail command line arguments from the given SendmailOption values.
+//
+// Parameters:
+//   - opts: The SendmailOption values to build the command line with.
+//
+// Returns:
+//   - The list of arguments for the sendmail binary.
+//   - An error if an option is invalid or the recipients of the Msg cannot be determined.
+func (m *Msg) sendmailArgs(opts ...SendmailOption) ([]string, error) {
+	options, err := applySendmailOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var args []string
+	if !options.noDefaultArgs {
+		args = append(args, "-oi")
+		if !options.explicitRecipients {
+			args = append(args, "-t")
+		}
+	}
+	if options.envelopeFrom != "" {
+		args = append(args, "-f", options.envelopeFrom)
+	}
+	if len(options.dsnNotify) > 0 {
+		args = append(args, "-N", strings.Join(options.dsnNotify, ","))
+	}
+	if options.dsnReturn != "" {
+		args = append(args, "-R", strings.ToLower(string(options.dsnReturn)))
+	}
+	args = append(args, options.args...)
+	if options.explicitRecipients {
+		recipients, err := m.GetRecipients()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--")
+		args = append(args, recipients...)
+	}
+	return args, nil
+}
+
+// applySendmailOptions applies the given SendmailOption values to a new sendmailOptions.
+//
+// Parameters:
+//   - opts: The SendmailOption values to apply.
+//
+// Returns:
+//   - A pointer to the resulting sendmailOptions.
+//   - An error if any of the options is invalid; otherwise, returns nil.
+func applySendmailOptions(opts ...SendmailOption) (*sendmailOptions, error) {
+	options := &sendmailOptions{}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(options); err != nil {
+			return nil, fmt.Errorf("failed to apply sendmail option: %w", err)
+		}
+	}
+	return options, nil
+}
+
+// Ensure that the SendmailTransport satisfies the Transport interface.
+var _ Transport = (*SendmailTransport)(nil)
+
+// NewSendmailTransport returns a new SendmailTransport that delivers messages via the sendmail
+// binary at the given path, with a command line that is built from the given SendmailOption values.
+//
+// Parameters:
+//   - sendmailPath: The path to the sendmail executable. If empty, SendmailPath is used.
+//   - timeout: The timeout for the delivery of a single message. A value lower than or equal to zero
+//     disables the timeout, so that only the context of SendWithContext applies.
+//   - opts: The SendmailOption values to build the command line with.
+//
+// Returns:
+//   - A pointer to the new SendmailTransport.
+//   - An error if any of the options is invalid; otherwise, returns nil.
+func NewSendmailTransport(sendmailPath string, timeout time.Duration, opts ...SendmailOption,
+) (*SendmailTransport, error) {
+	if sendmailPath == "" {
+		sendmailPath = SendmailPath
+	}
+	if _, err := applySendmailOptions(opts...); err != nil {
+		return nil, err
+	}
+	return &SendmailTransport{opts: opts, path: sendmailPath, timeout: timeout}, nil
+}
+
+// SendWithContext pipes each of the given messages to the sendmail binary. Delivery stops at the
+// first message that fails or when the context is done.
+//
+// Parameters:
+//   - ctx: The context.Context that controls the cancellation of the delivery.
+//   - messages: A variadic list of pointers to Msg objects to be delivered.
+//
+// Returns:
+//   - An error if the context is done or any of the messages could not be delivered; otherwise,
+//     returns nil.
+func (s *SendmailTransport) SendWithContext(ctx context.Context, messages ...*Msg) error {
+	for i, message := range messages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.send(ctx, message); err != nil {
+			return fmt.Errorf("failed to deliver message %d via sendmail: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// send pipes a single Msg to the sendmail binary, applying the timeout of the SendmailTransport.
+//
+// Parameters:
+//   - ctx: The context.Context that controls the cancellation of the delivery.
+//   - message: A pointer to the Msg to be delivered.
+//
+// Returns:
+//   - An error if the Msg is nil or could not be delivered; otherwise, returns nil.
+func (s *SendmailTransport) send(ctx context.Context, message *Msg) error {
+	if message == nil {
+		return errors.New("message is nil")
+	}
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	if err := message.WriteToSendmailWithOptions(ctx, s.path, s.opts...); err != nil {
+		return err
+	}
+	message.isDelivered = true
+	return nil
+}
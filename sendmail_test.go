@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMsg_sendmailArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []SendmailOption
+		want []string
+	}{
+		{"default arguments", nil, []string{"-oi", "-t"}},
+		{"nil option is ignored", []SendmailOption{nil}, []string{"-oi", "-t"}},
+		{
+			"envelope sender", []SendmailOption{WithSendmailEnvelopeFrom("Toni <bounce@domain.tld>")},
+			[]string{"-oi", "-t", "-f", "bounce@domain.tld"},
+		},
+		{
+			"DSN notify and return",
+			[]SendmailOption{
+				WithSendmailDSN(DSNRcptNotifyFailure, DSNRcptNotifyDelay),
+				WithSendmailDSNReturn(DSNMailReturnHeadersOnly),
+			},
+			[]string{"-oi", "-t", "-N", "failure,delay", "-R", "hdrs"},
+		},
+		{
+			"extra arguments", []SendmailOption{WithSendmailArgs("-v"), WithSendmailArgs("-Ffoo")},
+			[]string{"-oi", "-t", "-v", "-Ffoo"},
+		},
+		{
+			"without default arguments", []SendmailOption{WithoutSendmailDefaultArgs(), WithSendmailArgs("-bm")},
+			[]string{"-bm"},
+		},
+		{
+			"explicit recipients", []SendmailOption{WithSendmailExplicitRecipients()},
+			[]string{"-oi", "--", TestRcptValid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := testMessage(t)
+			args, err := message.sendmailArgs(tt.opts...)
+			if err != nil {
+				t.Fatalf("failed to build sendmail arguments: %s", err)
+			}
+			if strings.Join(args, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("unexpected sendmail arguments, want: %v, got: %v", tt.want, args)
+			}
+		})
+	}
+}
+
+func TestMsg_sendmailArgs_fails(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  SendmailOption
+		want error
+	}{
+		{"envelope sender looks like a flag", WithSendmailEnvelopeFrom("-oQ/tmp"), ErrInvalidSendmailEnvelopeFrom},
+		{"envelope sender is invalid", WithSendmailEnvelopeFrom("invalid"), ErrInvalidSendmailEnvelopeFrom},
+		{"DSN notify is invalid", WithSendmailDSN("invalid"), ErrInvalidDSNRcptNotifyOption},
+		{
+			"DSN notify combination is invalid", WithSendmailDSN(DSNRcptNotifyNever, DSNRcptNotifySuccess),
+			ErrInvalidDSNRcptNotifyCombination,
+		},
+		{"DSN return is invalid", WithSendmailDSNReturn("invalid"), ErrInvalidDSNMailReturnOption},
+		{"empty extra argument", WithSendmailArgs("-v", ""), ErrInvalidSendmailArg},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := testMessage(t)
+			if _, err := message.sendmailArgs(tt.opt); !errors.Is(err, tt.want) {
+				t.Errorf("expected error: %s, got: %s", tt.want, err)
+			}
+		})
+	}
+	t.Run("explicit recipients without recipients", func(t *testing.T) {
+		message := NewMsg()
+		if _, err := message.sendmailArgs(WithSendmailExplicitRecipients()); !errors.Is(err, ErrNoRcptAddresses) {
+			t.Errorf("expected error: %s, got: %s", ErrNoRcptAddresses, err)
+		}
+	})
+}
+
+func TestMsg_WriteToSendmailWithOptions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sendmail script requires a POSIX shell, skipping test")
+	}
+	tempDir := t.TempDir()
+	argsFile := filepath.Join(tempDir, "args")
+	script := filepath.Join(tempDir, "sendmail")
+	content := "#!/bin/sh\necho \"$@\" > " + argsFile + "\ncat > /dev/null\n"
+	if err := os.WriteFile(script, []byte(content), 0o700); err != nil {
+		t.Fatalf("failed to write sendmail script: %s", err)
+	}
+
+	t.Run("options are passed to sendmail", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		message := testMessage(t)
+		err := message.WriteToSendmailWithOptions(ctx, script, WithSendmailEnvelopeFrom("bounce@domain.tld"),
+			WithSendmailDSN(DSNRcptNotifyFailure))
+		if err != nil {
+			t.Fatalf("failed to write message to sendmail: %s", err)
+		}
+		got, err := os.ReadFile(argsFile)
+		if err != nil {
+			t.Fatalf("failed to read sendmail arguments: %s", err)
+		}
+		want := "-oi -t -f bounce@domain.tld -N failure"
+		if strings.TrimSpace(string(got)) != want {
+			t.Errorf("unexpected sendmail arguments, want: %s, got: %s", want, got)
+		}
+	})
+	t.Run("invalid option fails before execution", func(t *testing.T) {
+		message := testMessage(t)
+		err := message.WriteToSendmailWithOptions(context.Background(), "/is/invalid", WithSendmailArgs(""))
+		if !errors.Is(err, ErrInvalidSendmailArg) {
+			t.Errorf("expected error: %s, got: %s", ErrInvalidSendmailArg, err)
+		}
+	})
+}
+
+func TestSendmailTransport_SendWithContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sendmail script requires a POSIX shell, skipping test")
+	}
+	tempDir := t.TempDir()
+	argsFile := filepath.Join(tempDir, "args")
+	script := filepath.Join(tempDir, "sendmail")
+	content := "#!/bin/sh\necho \"$@\" >> " + argsFile + "\ncat > /dev/null\n"
+	if err := os.WriteFile(script, []byte(content), 0o700); err != nil {
+		t.Fatalf("failed to write sendmail script: %s", err)
+	}
+
+	t.Run("messages are delivered", func(t *testing.T) {
+		transport, err := NewSendmailTransport(script, time.Second*5, WithSendmailEnvelopeFrom("bounce@domain.tld"))
+		if err != nil {
+			t.Fatalf("failed to create sendmail transport: %s", err)
+		}
+		messages := []*Msg{testMessage(t), testMessage(t)}
+		if err = transport.SendWithContext(context.Background(), messages...); err != nil {
+			t.Fatalf("failed to deliver messages: %s", err)
+		}
+		for _, message := range messages {
+			if !message.IsDelivered() {
+				t.Error("expected message to be delivered")
+			}
+		}
+		got, err := os.ReadFile(argsFile)
+		if err != nil {
+			t.Fatalf("failed to read sendmail arguments: %s", err)
+		}
+		want := "-oi -t -f bounce@domain.tld\n-oi -t -f bounce@domain.tld"
+		if strings.TrimSpace(string(got)) != want {
+			t.Errorf("unexpected sendmail arguments, want: %s, got: %s", want, got)
+		}
+	})
+	t.Run("default path", func(t *testing.T) {
+		transport, err := NewSendmailTransport("", 0)
+		if err != nil {
+			t.Fatalf("failed to create sendmail transport: %s", err)
+		}
+		if transport.path != SendmailPath {
+			t.Errorf("expected default sendmail path %s, got: %s", SendmailPath, transport.path)
+		}
+	})
+	t.Run("invalid option fails", func(t *testing.T) {
+		if _, err := NewSendmailTransport(script, 0, WithSendmailArgs("")); !errors.Is(err, ErrInvalidSendmailArg) {
+			t.Errorf("expected error: %s, got: %s", ErrInvalidSendmailArg, err)
+		}
+	})
+	t.Run("delivery stops at first failure", func(t *testing.T) {
+		transport, err := NewSendmailTransport("/is/invalid", 0)
+		if err != nil {
+			t.Fatalf("failed to create sendmail transport: %s", err)
+		}
+		messages := []*Msg{testMessage(t), testMessage(t)}
+		if err = transport.SendWithContext(context.Background(), messages...); err == nil {
+			t.Fatal("expected delivery with invalid sendmail path to fail")
+		}
+		if messages[0].IsDelivered() || messages[1].IsDelivered() {
+			t.Error("expected messages not to be delivered")
+		}
+	})
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		transport, err := NewSendmailTransport(script, 0)
+		if err != nil {
+			t.Fatalf("failed to create sendmail transport: %s", err)
+		}
+		if err = transport.SendWithContext(ctx, testMessage(t)); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected error: %s, got: %s", context.Canceled, err)
+		}
+	})
+}
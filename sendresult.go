@@ -0,0 +1,201 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"strings"
+	"time"
+)
+
+type (
+	// SendResult represents the outcome of the delivery of a single Msg.
+	//
+	// A SendResult is returned for each Msg by Client.SendWithResults and allows callers to do their
+	// bookkeeping without having to parse the SendError of the Msg.
+	SendResult struct {
+		// BytesWritten is the number of bytes of the Msg that have been written to the server.
+		BytesWritten int64
+
+		// DSNRequested indicates whether Delivery Status Notifications were requested for the Msg.
+		DSNRequested bool
+
+		// Duration is the time it took to process the Msg.
+		Duration time.Duration
+
+		// Err is the error that occurred during the delivery of the Msg, or nil if the Msg has been
+		// delivered successfully. It is the same error as returned by Msg.SendError.
+		Err error
+
+		// MessageID is the value of the Message-ID header of the Msg.
+		MessageID string
+
+		// Recipients holds the response of the server to the RCPT TO command of each recipient, in the
+		// order the recipients were sent.
+		Recipients []RecipientResult
+
+		// Server is the address of the SMTP server, the Msg has been sent to.
+		Server string
+
+		// SkippedRecipients holds the recipients that have not been sent to the server, because they
+		// are on the recipient graylist of the Client. See WithRecipientGraylist.
+		SkippedRecipients []string
+	}
+
+	// RecipientResult represents the response of the SMTP server to the RCPT TO command of a single
+	// recipient.
+	RecipientResult struct {
+		// Accepted indicates whether the recipient has been accepted by the server.
+		Accepted bool
+
+		// Address is the recipient address.
+		Address string
+
+		// Code is the SMTP reply code returned by the server, i. e. 250 or 550. It is zero, if no
+		// reply was received from the server.
+		Code int
+
+		// EnhancedCode is the enhanced status code of the reply, i. e. "2.1.5" or "5.1.1". It is
+		// empty, if the server did not provide an enhanced status code.
+		EnhancedCode string
+
+		// Message is the reply text returned by the server, including the enhanced status code.
+		Message string
+	}
+)
+
+// AcceptedRecipients returns the addresses of the recipients that have been accepted by the server.
+//
+// Returns:
+//   - A slice of the accepted recipient addresses, in the order the recipients were sent.
+func (r SendResult) AcceptedRecipients() []string {
+	var accepted []string
+	for _, recipient := range r.Recipients {
+		if recipient.Accepted {
+			accepted = append(accepted, recipient.Address)
+		}
+	}
+	return accepted
+}
+
+// RejectedRecipients returns the RecipientResult of each recipient that has been rejected by the
+// server.
+//
+// Returns:
+//   - A slice of RecipientResult for the rejected recipients, in the order the recipients were sent.
+func (r SendResult) RejectedRecipients() []RecipientResult {
+	var rejected []RecipientResult
+	for _, recipient := range r.Recipients {
+		if !recipient.Accepted {
+			rejected = append(rejected, recipient)
+		}
+	}
+	return rejected
+}
+
+// SendWithResults attempts to send one or more Msg using the Client connection to the SMTP server,
+// like Send, and returns a SendResult for each of the provided Msg.
+//
+// The returned SendResult slice has the same length and order as the provided messages. Each
+// SendResult holds the Message-ID, the accepted and rejected recipients, the number of bytes
+// written, the time it took to process the Msg and the address of the server, so that callers
+// don't need to inspect the SendError for bookkeeping. Like with Send, a SendError is associated
+// with each Msg that failed to be delivered.
+//
+// Parameters:
+//   - messages: A variadic list of pointers to Msg objects to be sent.
+//
+// Returns:
+//   - A slice of SendResult, one for each of the provided messages. It is nil if the connection
+//     check fails.
+//   - An error that aggregates any SendErrors encountered during the sending process; otherwise,
+//     returns nil.
+func (c *Client) SendWithResults(messages ...*Msg) ([]SendResult, error) {
+	if err := c.checkConn(); err != nil {
+		return nil, &SendError{Reason: ErrConnCheck, errlist: []error{err}, isTemp: isTempError(err)}
+	}
+
+	results := make([]SendResult, len(messages))
+	var errs []error
+	for id, message := range messages {
+		results[id].DSNRequested = c.requestDSN
+		if sendErr := c.sendSingleMsgWithResult(message, &results[id]); sendErr != nil {
+			message.sendError = sendErr
+			results[id].Err = sendErr
+			errs = append(errs, sendErr)
+		}
+	}
+	return results, joinErrors(errs)
+}
+
+// SendWithResult attempts to send a single Msg using the Client connection to the SMTP server,
+// like Send, and returns its SendResult with the response of the server for each recipient.
+//
+// Instead of a single aggregated error, the SendResult holds the reply code, the enhanced
+// status code and the reply text of the RCPT TO command of every recipient, whether it has been
+// accepted or rejected. This is particularly useful in combination with Delivery Status
+// Notifications, to keep track of the recipients for which notifications are to be expected.
+//
+// Parameters:
+//   - message: A pointer to the Msg to be sent.
+//
+// Returns:
+//   - A pointer to the SendResult of the Msg. It is nil if the connection check fails.
+//   - The SendError of the Msg if the delivery failed; otherwise, returns nil.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc3461
+//   - https://datatracker.ietf.org/doc/html/rfc3463
+func (c *Client) SendWithResult(message *Msg) (*SendResult, error) {
+	results, err := c.SendWithResults(message)
+	if results == nil {
+		return nil, err
+	}
+	return &results[0], results[0].Err
+}
+
+// newRecipientResult returns a RecipientResult for the given recipient address and the response
+// of the server to the RCPT TO command.
+//
+// Parameters:
+//   - address: The recipient address.
+//   - code: The SMTP reply code returned by the server.
+//   - message: The reply text returned by the server.
+//   - err: The error returned for the RCPT TO command, or nil if the recipient has been accepted.
+//
+// Returns:
+//   - A RecipientResult holding the reply code, enhanced status code and reply text.
+func newRecipientResult(address string, code int, message string, err error) RecipientResult {
+	result := RecipientResult{Accepted: err == nil, Address: address, Code: code, Message: message}
+	if err != nil && code == 0 {
+		result.Message = err.Error()
+	}
+	result.EnhancedCode = enhancedStatusCode(result.Message)
+	return result
+}
+
+// enhancedStatusCode returns the enhanced status code at the start of the given SMTP reply text.
+//
+// Parameters:
+//   - message: The SMTP reply text.
+//
+// Returns:
+//   - The enhanced status code, i. e. "5.1.1", or an empty string if the reply text does not start
+//     with an enhanced status code.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc3463#section-2
+func enhancedStatusCode(message string) string {
+	code := strings.SplitN(message, " ", 2)[0]
+	classes := strings.Split(code, ".")
+	if len(classes) != 3 || len(classes[0]) != 1 || !strings.ContainsAny(classes[0], "245") {
+		return ""
+	}
+	for _, class := range classes[1:] {
+		if len(class) == 0 || len(class) > 3 || strings.Trim(class, "0123456789") != "" {
+			return ""
+		}
+	}
+	return code
+}
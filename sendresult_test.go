@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClient_SendWithResults(t *testing.T) {
+	t.Run("send with results for delivered and rejected messages", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS))
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(context.Background()); err != nil {
+			t.Fatalf("failed to connect to the test server: %s", err)
+		}
+		t.Cleanup(func() {
+			if err := client.Close(); err != nil {
+				t.Errorf("failed to close client: %s", err)
+			}
+		})
+
+		valid := testMessage(t)
+		valid.SetMessageIDWithValue("valid@domain.tld")
+		invalid := testMessage(t)
+		if err = invalid.AddTo("invalid@domain.tld"); err != nil {
+			t.Fatalf("failed to add recipient: %s", err)
+		}
+		results, err := client.SendWithResults(valid, invalid)
+		if err == nil {
+			t.Fatal("expected send to fail for invalid recipient")
+		}
+		if len(results) != 2 {
+			t.Fatalf("expected 2 results, got: %d", len(results))
+		}
+
+		delivered := results[0]
+		if delivered.Err != nil {
+			t.Errorf("expected no error for valid message, got: %s", delivered.Err)
+		}
+		if delivered.MessageID != "<valid@domain.tld>" {
+			t.Errorf("expected message ID: %s, got: %s", "<valid@domain.tld>", delivered.MessageID)
+		}
+		accepted := delivered.AcceptedRecipients()
+		if len(accepted) != 1 || accepted[0] != "valid-to@domain.tld" {
+			t.Errorf("unexpected accepted recipients: %v", accepted)
+		}
+		if len(delivered.RejectedRecipients()) != 0 {
+			t.Errorf("expected no rejected recipients, got: %v", delivered.RejectedRecipients())
+		}
+		if delivered.BytesWritten == 0 {
+			t.Error("expected bytes written to be recorded")
+		}
+		if delivered.Duration == 0 {
+			t.Error("expected duration to be recorded")
+		}
+		if want := fmt.Sprintf("%s:%d", DefaultHost, serverPort); delivered.Server != want {
+			t.Errorf("expected server: %s, got: %s", want, delivered.Server)
+		}
+
+		rejected := results[1]
+		var sendErr *SendError
+		if !errors.As(rejected.Err, &sendErr) || sendErr.Reason != ErrSMTPRcptTo {
+			t.Errorf("expected SendError with reason %s, got: %s", ErrSMTPRcptTo, rejected.Err)
+		}
+		if rejected.Err != invalid.SendError() {
+			t.Error("expected result error to match the SendError of the message")
+		}
+		if len(rejected.AcceptedRecipients()) != 1 {
+			t.Errorf("expected 1 accepted recipient, got: %v", rejected.AcceptedRecipients())
+		}
+		if len(rejected.RejectedRecipients()) != 1 {
+			t.Fatalf("expected 1 rejected recipient, got: %v", rejected.RejectedRecipients())
+		}
+		rcpt := rejected.RejectedRecipients()[0]
+		if rcpt.Accepted || rcpt.Address != "invalid@domain.tld" || rcpt.Code != 500 {
+			t.Errorf("unexpected rejected recipient: %+v", rcpt)
+		}
+		if rcpt.Message != "5.1.2 Invalid to: <invalid@domain.tld>" {
+			t.Errorf("unexpected rejection message: %s", rcpt.Message)
+		}
+		if rejected.BytesWritten != 0 {
+			t.Errorf("expected no bytes written for rejected message, got: %d", rejected.BytesWritten)
+		}
+	})
+	t.Run("send with results fails without connection", func(t *testing.T) {
+		client, err := NewClient(DefaultHost)
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		results, err := client.SendWithResults(testMessage(t))
+		if err == nil {
+			t.Fatal("expected send to fail without connection")
+		}
+		var sendErr *SendError
+		if !errors.As(err, &sendErr) || sendErr.Reason != ErrConnCheck {
+			t.Errorf("expected SendError with reason %s, got: %s", ErrConnCheck, err)
+		}
+		if results != nil {
+			t.Errorf("expected no results, got: %v", results)
+		}
+	})
+}
+
+func TestNewRecipientResult(t *testing.T) {
+	rejected := newRecipientResult("toni.tester@example.com", 0, "", errors.New("connection reset"))
+	if rejected.Accepted || rejected.Code != 0 {
+		t.Errorf("expected no reply code, got: %d", rejected.Code)
+	}
+	if rejected.Message != "connection reset" {
+		t.Errorf("expected message: %s, got: %s", "connection reset", rejected.Message)
+	}
+}
+
+func TestClient_SendWithResult(t *testing.T) {
+	t.Run("send with per-recipient results and DSN", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+				SupportDSN: true,
+			}); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS), WithDSN())
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		if err = client.DialWithContext(context.Background()); err != nil {
+			t.Fatalf("failed to connect to the test server: %s", err)
+		}
+		t.Cleanup(func() {
+			if err := client.Close(); err != nil {
+				t.Errorf("failed to close client: %s", err)
+			}
+		})
+
+		message := testMessage(t)
+		if err = message.AddCc("invalid@domain.tld"); err != nil {
+			t.Fatalf("failed to add recipient: %s", err)
+		}
+		result, err := client.SendWithResult(message)
+		var sendErr *SendError
+		if !errors.As(err, &sendErr) || sendErr.Reason != ErrSMTPRcptTo {
+			t.Errorf("expected SendError with reason %s, got: %s", ErrSMTPRcptTo, err)
+		}
+		if result == nil {
+			t.Fatal("expected delivery result, got nil")
+		}
+		if !result.DSNRequested {
+			t.Error("expected DSN to be requested")
+		}
+		if result.MessageID != message.GetMessageID() {
+			t.Errorf("expected message ID: %s, got: %s", message.GetMessageID(), result.MessageID)
+		}
+		want := []RecipientResult{
+			{Accepted: true, Address: TestRcptValid, Code: 250, EnhancedCode: "2.0.0", Message: "2.0.0 OK"},
+			{
+				Accepted: false, Address: "invalid@domain.tld", Code: 500, EnhancedCode: "5.1.2",
+				Message: "5.1.2 Invalid to: <invalid@domain.tld>",
+			},
+		}
+		if len(result.Recipients) != len(want) {
+			t.Fatalf("expected %d recipient results, got: %+v", len(want), result.Recipients)
+		}
+		for i := range want {
+			if result.Recipients[i] != want[i] {
+				t.Errorf("unexpected recipient result, want: %+v, got: %+v", want[i], result.Recipients[i])
+			}
+		}
+	})
+	t.Run("send with result fails without connection", func(t *testing.T) {
+		client, err := NewClient(DefaultHost)
+		if err != nil {
+			t.Fatalf("failed to create new client: %s", err)
+		}
+		result, err := client.SendWithResult(testMessage(t))
+		if err == nil {
+			t.Fatal("expected send to fail without connection")
+		}
+		if result != nil {
+			t.Errorf("expected no result, got: %+v", result)
+		}
+	})
+}
+
+func TestEnhancedStatusCode(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"2.1.5 Recipient OK", "2.1.5"},
+		{"5.1.1 User unknown", "5.1.1"},
+		{"4.7.28 Rate limited", "4.7.28"},
+		{"5.100.999", "5.100.999"},
+		{"OK", ""},
+		{"3.1.1 Invalid class", ""},
+		{"5.1 Missing detail", ""},
+		{"5.1.1000 Detail too long", ""},
+		{"5.a.1 Not a number", ""},
+		{"5.-1.1 Negative number", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := enhancedStatusCode(tt.message); got != tt.want {
+				t.Errorf("unexpected enhanced status code, want: %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
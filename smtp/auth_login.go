@@ -6,14 +6,20 @@ package smtp
 
 import (
 	"fmt"
+	"strings"
 )
 
+// loginAuthMaxPrompts is the maximum number of server prompts a tolerant LOGIN auth answers before
+// failing, to prevent a misbehaving server from keeping the client in an endless exchange.
+const loginAuthMaxPrompts = 5
+
 // loginAuth is the type that satisfies the Auth interface for the "SMTP LOGIN" auth
 type loginAuth struct {
 	username, password   string
 	host                 string
 	respStep             uint8
 	allowUnencryptedAuth bool
+	tolerant             bool
 }
 
 // LoginAuth returns an [Auth] that implements the LOGIN authentication
@@ -37,7 +43,21 @@ type loginAuth struct {
 // or is connected to localhost. Otherwise authentication will fail with an
 // error, without sending the credentials.
 func LoginAuth(username, password, host string, allowUnenc bool) Auth {
-	return &loginAuth{username, password, host, 0, allowUnenc}
+	return &loginAuth{username, password, host, 0, allowUnenc, false}
+}
+
+// LoginAuthTolerant returns an [Auth] that implements the LOGIN authentication
+// mechanism like [LoginAuth], but tolerates servers that send nonstandard prompts.
+//
+// Instead of strictly answering the first prompt with the username and the second
+// one with the password, the challenge of the server is inspected: a prompt that
+// asks for the password is answered with the password and a prompt that asks for
+// the user is answered with the username. Prompts that cannot be identified are
+// answered in the usual order. Additional prompts after both credentials have been
+// sent, i. e. an empty "334" continuation, are answered with an empty response
+// instead of failing the authentication.
+func LoginAuthTolerant(username, password, host string, allowUnenc bool) Auth {
+	return &loginAuth{username, password, host, 0, allowUnenc, true}
 }
 
 // Start begins the SMTP authentication process by validating server's TLS status and hostname.
@@ -61,6 +81,9 @@ func (a *loginAuth) Start(server *ServerInfo) (string, []byte, error) {
 // Next processes responses from the server during the SMTP authentication exchange, sending the
 // username and password.
 func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if more && a.tolerant {
+		return a.nextTolerant(fromServer)
+	}
 	if more {
 		switch a.respStep {
 		case 0:
@@ -75,3 +98,27 @@ func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
 	}
 	return nil, nil
 }
+
+// nextTolerant answers the server prompts of the tolerant LOGIN auth, based on the content of the
+// prompt where possible.
+func (a *loginAuth) nextTolerant(fromServer []byte) ([]byte, error) {
+	if a.respStep >= loginAuthMaxPrompts {
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedServerResponse, string(fromServer))
+	}
+	a.respStep++
+	prompt := strings.ToLower(string(fromServer))
+	switch {
+	case strings.Contains(prompt, "pass"):
+		return []byte(a.password), nil
+	case strings.Contains(prompt, "user"):
+		return []byte(a.username), nil
+	}
+	switch a.respStep {
+	case 1:
+		return []byte(a.username), nil
+	case 2:
+		return []byte(a.password), nil
+	default:
+		return []byte{}, nil
+	}
+}
@@ -18,6 +18,8 @@
 //	AUTH      RFC 2554
 //	STARTTLS  RFC 3207
 //	DSN       RFC 1891
+//	CHUNKING  RFC 3030
+//	PIPELINING RFC 2920
 package smtp
 
 import (
@@ -46,6 +48,14 @@ var (
 	ErrNoConnection = errors.New("connection is not established")
 )
 
+// DebugHook is a function type that is called by the Client for each line of the SMTP protocol
+// that is sent to or received from the server. The direction indicates whether the line was sent
+// by the client or by the server. The line does not include the trailing CRLF.
+//
+// Authentication data in the lines passed to a DebugHook is always redacted. The message content
+// sent after the DATA command is not passed to the DebugHook.
+type DebugHook func(direction log.Direction, line string)
+
 // A Client represents a client connection to an SMTP server.
 type Client struct {
 	// Text is the textproto.Conn used by the Client. It is exported to allow for clients to add extensions.
@@ -57,12 +67,18 @@ type Client struct {
 	// authIsActive indicates that the Client is currently during SMTP authentication
 	authIsActive bool
 
+	// banner is the greeting message of the server, without the reply code
+	banner string
+
 	// keep a reference to the connection so it can be used to create a TLS connection later
 	conn net.Conn
 
 	// debug logging is enabled
 	debug bool
 
+	// debugHook is called for each line of the SMTP protocol that is sent or received
+	debugHook DebugHook
+
 	// didHello indicates whether we've said HELO/EHLO
 	didHello bool
 
@@ -90,6 +106,14 @@ type Client struct {
 	// logger will be used for debug logging
 	logger log.Logger
 
+	// noInitialResponse holds the SASL mechanisms for which the initial response is not sent with
+	// the AUTH command. An empty, non-nil map disables the initial response for all mechanisms.
+	noInitialResponse map[string]bool
+
+	// noSMTPUTF8 indicates that the Client does not add the SMTPUTF8 parameter to the MAIL command,
+	// even if the server supports the SMTPUTF8 extension
+	noSMTPUTF8 bool
+
 	// mutex is used to synchronize access to shared resources, ensuring that only one goroutine can access
 	// the resource at a time.
 	mutex sync.RWMutex
@@ -117,7 +141,7 @@ func Dial(addr string) (*Client, error) {
 // server name to be used when authenticating.
 func NewClient(conn net.Conn, host string) (*Client, error) {
 	text := textproto.NewConn(conn)
-	_, _, err := text.ReadResponse(220)
+	_, banner, err := text.ReadResponse(220)
 	if err != nil {
 		if cerr := text.Close(); cerr != nil {
 			// Since we are being Go <1.20 compatible, we can't combine errorrs and
@@ -128,7 +152,7 @@ func NewClient(conn net.Conn, host string) (*Client, error) {
 		}
 		return nil, err
 	}
-	c := &Client{Text: text, conn: conn, serverName: host, localName: "localhost"}
+	c := &Client{Text: text, banner: banner, conn: conn, serverName: host, localName: "localhost"}
 	_, c.tls = conn.(*tls.Conn)
 	c.isConnected = true
 
@@ -183,11 +207,12 @@ func (c *Client) cmd(expectCode int, format string, args ...interface{}) (int, s
 	var logMsg []interface{}
 	logMsg = args
 	logFmt := format
-	if c.authIsActive {
+	if c.authIsActive && !c.logAuthData {
 		logMsg = []interface{}{"<SMTP auth data redacted>"}
 		logFmt = "%s"
 	}
 	c.debugLog(log.DirClientToServer, logFmt, logMsg...)
+	c.traceCommand(format, args...)
 
 	id, err := c.Text.Cmd(format, args...)
 	if err != nil {
@@ -198,10 +223,11 @@ func (c *Client) cmd(expectCode int, format string, args ...interface{}) (int, s
 	code, msg, err := c.Text.ReadResponse(expectCode)
 
 	logMsg = []interface{}{code, msg}
-	if c.authIsActive && code >= 300 && code <= 400 {
+	if c.authIsActive && !c.logAuthData && code >= 300 && code <= 400 {
 		logMsg = []interface{}{code, "<SMTP auth data redacted>"}
 	}
 	c.debugLog(log.DirServerToClient, "%d %s", logMsg...)
+	c.traceResponse(code, msg)
 
 	c.Text.EndResponse(id)
 	c.mutex.Unlock()
@@ -278,15 +304,11 @@ func (c *Client) Auth(a Auth) error {
 	}
 
 	c.mutex.Lock()
-	if !c.logAuthData {
-		c.authIsActive = true
-	}
+	c.authIsActive = true
 	c.mutex.Unlock()
 	defer func() {
 		c.mutex.Lock()
-		if !c.logAuthData {
-			c.authIsActive = false
-		}
+		c.authIsActive = false
 		c.mutex.Unlock()
 	}()
 
@@ -304,8 +326,18 @@ func (c *Client) Auth(a Auth) error {
 	}
 	resp64 := make([]byte, encoding.EncodedLen(len(resp)))
 	encoding.Encode(resp64, resp)
-	code, msg64, err := c.cmd(0, "%s", strings.TrimSpace(fmt.Sprintf("AUTH %s %s", mech,
-		resp64)))
+	var code int
+	var msg64 string
+	if len(resp) > 0 && !c.sendsInitialResponse(mech) {
+		// Without SASL-IR, the initial response is sent as answer to the empty server challenge
+		code, msg64, err = c.cmd(0, "AUTH %s", mech)
+		if err == nil && code == 334 {
+			code, msg64, err = c.cmd(0, "%s", resp64)
+		}
+	} else {
+		code, msg64, err = c.cmd(0, "%s", strings.TrimSpace(fmt.Sprintf("AUTH %s %s", mech,
+			resp64)))
+	}
 	for err == nil {
 		var msg []byte
 		switch code {
@@ -341,23 +373,40 @@ func (c *Client) Auth(a Auth) error {
 // Mail issues a MAIL command to the server using the provided email address.
 // If the server supports the 8BITMIME extension, Mail adds the BODY=8BITMIME
 // parameter. If the server supports the SMTPUTF8 extension, Mail adds the
-// SMTPUTF8 parameter.
+// SMTPUTF8 parameter, unless it is disabled with [Client.SetSMTPUTF8].
 // This initiates a mail transaction and is followed by one or more [Client.Rcpt] calls.
 func (c *Client) Mail(from string) error {
-	if err := validateLine(from); err != nil {
+	return c.MailWithParams(from)
+}
+
+// MailWithParams issues a MAIL command to the server using the provided email address,
+// like [Client.Mail], and appends the given ESMTP parameters, i. e. "AUTH=<>", to the
+// command. The parameters are sent as is and must be formatted as "KEY" or "KEY=value".
+// If a BODY parameter is given, i. e. "BODY=BINARYMIME", the BODY=8BITMIME parameter is
+// not added.
+func (c *Client) MailWithParams(from string, params ...string) error {
+	if err := validateCommand(from, params); err != nil {
 		return err
 	}
 	if err := c.hello(); err != nil {
 		return err
 	}
+	format, args := c.mailCommand(from, params)
+	_, _, err := c.cmd(250, format, args...)
+	return err
+}
+
+// mailCommand returns the format string and the arguments of the MAIL command for the given
+// address and ESMTP parameters, including the parameters of the extensions used by the Client.
+func (c *Client) mailCommand(from string, params []string) (string, []interface{}) {
 	cmdStr := "MAIL FROM:<%s>"
 
 	c.mutex.RLock()
 	if c.ext != nil {
-		if _, ok := c.ext["8BITMIME"]; ok {
+		if _, ok := c.ext["8BITMIME"]; ok && !hasBodyParam(params) {
 			cmdStr += " BODY=8BITMIME"
 		}
-		if _, ok := c.ext["SMTPUTF8"]; ok {
+		if _, ok := c.ext["SMTPUTF8"]; ok && !c.noSMTPUTF8 {
 			cmdStr += " SMTPUTF8"
 		}
 		_, ok := c.ext["DSN"]
@@ -367,28 +416,181 @@ func (c *Client) Mail(from string) error {
 	}
 	c.mutex.RUnlock()
 
-	_, _, err := c.cmd(250, cmdStr, from)
-	return err
+	return cmdStr + paramFormat(params), paramArgs(from, params)
 }
 
 // Rcpt issues a RCPT command to the server using the provided email address.
 // A call to Rcpt must be preceded by a call to [Client.Mail] and may be followed by
 // a [Client.Data] call or another Rcpt call.
 func (c *Client) Rcpt(to string) error {
-	if err := validateLine(to); err != nil {
-		return err
+	_, _, err := c.RcptWithResponse(to)
+	return err
+}
+
+// RcptWithResponse issues a RCPT command to the server using the provided email address,
+// like [Client.Rcpt], and additionally returns the reply code and text of the server's
+// response. If the server rejects the recipient, the returned error is a *textproto.Error
+// holding the same reply code and text.
+func (c *Client) RcptWithResponse(to string) (int, string, error) {
+	return c.RcptWithParams(to)
+}
+
+// RcptWithParams issues a RCPT command to the server using the provided email address,
+// like [Client.RcptWithResponse], and appends the given ESMTP parameters to the command.
+// The parameters are sent as is and must be formatted as "KEY" or "KEY=value".
+func (c *Client) RcptWithParams(to string, params ...string) (int, string, error) {
+	if err := validateCommand(to, params); err != nil {
+		return 0, "", err
 	}
+	format, args := c.rcptCommand(to, params)
+	return c.cmd(25, format, args...)
+}
 
+// rcptCommand returns the format string and the arguments of the RCPT command for the given
+// address and ESMTP parameters, including the DSN notify option of the Client.
+func (c *Client) rcptCommand(to string, params []string) (string, []interface{}) {
 	c.mutex.RLock()
 	_, ok := c.ext["DSN"]
 	c.mutex.RUnlock()
 
 	if ok && c.dsnrntype != "" {
-		_, _, err := c.cmd(25, "RCPT TO:<%s> NOTIFY=%s", to, c.dsnrntype)
-		return err
+		return "RCPT TO:<%s> NOTIFY=%s" + paramFormat(params), paramArgs(to, append([]string{c.dsnrntype},
+			params...))
 	}
-	_, _, err := c.cmd(25, "RCPT TO:<%s>", to)
-	return err
+	return "RCPT TO:<%s>" + paramFormat(params), paramArgs(to, params)
+}
+
+// RcptReply holds the reply of the server to a RCPT command that was issued with
+// [Client.MailRcptPipelined].
+type RcptReply struct {
+	// Code is the reply code of the server.
+	Code int
+
+	// Err is the error of the RCPT command, i. e. a *textproto.Error if the server rejected the
+	// recipient.
+	Err error
+
+	// Msg is the reply text of the server.
+	Msg string
+}
+
+// pipelinedCommand is a command that is sent to the server as part of a pipelined group.
+type pipelinedCommand struct {
+	args       []interface{}
+	expectCode int
+	format     string
+}
+
+// MailRcptPipelined issues the MAIL command with the given ESMTP parameters and a RCPT command for
+// each of the given recipients in a single write, as defined by the PIPELINING extension in
+// RFC 2920, and reads the replies of the server afterwards. The ESMTP parameters of a recipient are
+// looked up with rcptParams, which may be nil. The server must advertise the PIPELINING extension.
+//
+// It returns the replies to the RCPT commands in the order of the recipients. The returned error is
+// the error of the MAIL command or an error that occurred while sending the commands or reading the
+// replies. The DATA command is not part of the pipelined group, so that the caller can decide on the
+// replies to the RCPT commands whether the message is sent at all.
+func (c *Client) MailRcptPipelined(from string, params []string, rcpts []string,
+	rcptParams func(rcpt string) []string,
+) ([]RcptReply, error) {
+	if err := validateCommand(from, params); err != nil {
+		return nil, err
+	}
+	if err := c.hello(); err != nil {
+		return nil, err
+	}
+	commands := make([]pipelinedCommand, 0, len(rcpts)+1)
+	format, args := c.mailCommand(from, params)
+	commands = append(commands, pipelinedCommand{args: args, expectCode: 250, format: format})
+	for _, rcpt := range rcpts {
+		var rcptParam []string
+		if rcptParams != nil {
+			rcptParam = rcptParams(rcpt)
+		}
+		if err := validateCommand(rcpt, rcptParam); err != nil {
+			return nil, err
+		}
+		format, args = c.rcptCommand(rcpt, rcptParam)
+		commands = append(commands, pipelinedCommand{args: args, expectCode: 25, format: format})
+	}
+
+	replies, err := c.pipeline(commands)
+	if err != nil {
+		return nil, err
+	}
+	return replies[1:], replies[0].Err
+}
+
+// pipeline sends the given commands to the server in a single write and reads the replies of the
+// server afterwards. The returned error is only set if sending the commands or reading the replies
+// failed; errors returned by the server are set in the RcptReply of the command.
+func (c *Client) pipeline(commands []pipelinedCommand) ([]RcptReply, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	ids := make([]uint, 0, len(commands))
+	var err error
+	for _, command := range commands {
+		c.debugLog(log.DirClientToServer, command.format, command.args...)
+		c.traceCommand(command.format, command.args...)
+		id := c.Text.Next()
+		c.Text.StartRequest(id)
+		_, err = fmt.Fprintf(c.Text.W, command.format+"\r\n", command.args...)
+		c.Text.EndRequest(id)
+		ids = append(ids, id)
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = c.Text.W.Flush()
+	}
+
+	replies := make([]RcptReply, len(ids))
+	for i, id := range ids {
+		c.Text.StartResponse(id)
+		if err == nil {
+			replies[i].Code, replies[i].Msg, replies[i].Err = c.Text.ReadResponse(commands[i].expectCode)
+			c.debugLog(log.DirServerToClient, "%d %s", replies[i].Code, replies[i].Msg)
+			c.traceResponse(replies[i].Code, replies[i].Msg)
+			var protoErr *textproto.Error
+			if replies[i].Err != nil && !errors.As(replies[i].Err, &protoErr) {
+				err = replies[i].Err
+			}
+		}
+		c.Text.EndResponse(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return replies, nil
+}
+
+// paramFormat returns the format string for the given ESMTP parameters of a command.
+func paramFormat(params []string) string {
+	return strings.Repeat(" %s", len(params))
+}
+
+// paramArgs returns the arguments for the format string of a command with the given address and
+// ESMTP parameters.
+func paramArgs(addr string, params []string) []interface{} {
+	args := make([]interface{}, 0, len(params)+1)
+	args = append(args, addr)
+	for _, param := range params {
+		args = append(args, param)
+	}
+	return args
+}
+
+// hasBodyParam returns true if the given ESMTP parameters of a MAIL command contain a BODY
+// parameter.
+func hasBodyParam(params []string) bool {
+	for _, param := range params {
+		if len(param) >= 5 && strings.EqualFold(param[:5], "BODY=") {
+			return true
+		}
+	}
+	return false
 }
 
 type dataCloser struct {
@@ -400,7 +602,8 @@ type dataCloser struct {
 func (d *dataCloser) Close() error {
 	d.c.mutex.Lock()
 	_ = d.WriteCloser.Close()
-	_, _, err := d.c.Text.ReadResponse(250)
+	code, msg, err := d.c.Text.ReadResponse(250)
+	d.c.traceResponse(code, msg)
 	d.c.mutex.Unlock()
 	return err
 }
@@ -432,6 +635,84 @@ func (c *Client) Data() (io.WriteCloser, error) {
 	return datacloser, nil
 }
 
+// bdatChunkSize is the maximum size of a chunk of message data that is sent with a single BDAT
+// command.
+const bdatChunkSize = 1024 * 1024
+
+// bdatWriter is an io.WriteCloser that buffers the message data and transmits it in chunks with
+// the BDAT command.
+type bdatWriter struct {
+	buffer []byte
+	c      *Client
+}
+
+// Write buffers the given data and sends a BDAT chunk each time the buffer is full.
+func (b *bdatWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		copied := copy(b.buffer[len(b.buffer):cap(b.buffer)], p)
+		b.buffer = b.buffer[:len(b.buffer)+copied]
+		p = p[copied:]
+		n += copied
+		if len(b.buffer) == cap(b.buffer) {
+			if err = b.c.bdat(b.buffer, false); err != nil {
+				return n, err
+			}
+			b.buffer = b.buffer[:0]
+		}
+	}
+	return n, nil
+}
+
+// Close sends the remaining buffered data as the last BDAT chunk and waits for the response of
+// the server.
+func (b *bdatWriter) Close() error {
+	return b.c.bdat(b.buffer, true)
+}
+
+// Bdat returns a writer that can be used to write the mail headers and body, like [Client.Data],
+// but transmits the data in chunks with the BDAT command instead of the DATA command. The data
+// is sent as is, without dot-stuffing or line ending conversion, so that binary data can be
+// transmitted. The caller should close the writer before calling any more methods on c. Bdat
+// must only be used if the server supports the CHUNKING extension.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc3030#section-2
+func (c *Client) Bdat() (io.WriteCloser, error) {
+	c.mutex.RLock()
+	isConnected := c.isConnected
+	c.mutex.RUnlock()
+	if !isConnected {
+		return nil, ErrNoConnection
+	}
+	return &bdatWriter{buffer: make([]byte, 0, bdatChunkSize), c: c}, nil
+}
+
+// bdat sends the given chunk of message data with a BDAT command and reads the response of the
+// server. If last is true, the chunk is marked as the last chunk of the message.
+func (c *Client) bdat(chunk []byte, last bool) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	line := fmt.Sprintf("BDAT %d", len(chunk))
+	if last {
+		line += " LAST"
+	}
+	c.debugLog(log.DirClientToServer, "%s", line)
+	c.traceCommand("%s", line)
+	if _, err := c.Text.W.WriteString(line + "\r\n"); err != nil {
+		return err
+	}
+	if _, err := c.Text.W.Write(chunk); err != nil {
+		return err
+	}
+	if err := c.Text.W.Flush(); err != nil {
+		return err
+	}
+	code, msg, err := c.Text.ReadResponse(250)
+	c.debugLog(log.DirServerToClient, "%d %s", code, msg)
+	c.traceResponse(code, msg)
+	return err
+}
+
 var testHookStartTLS func(*tls.Config) // nil, except for tests
 
 // SendMail connects to the server at addr, switches to TLS if
@@ -532,6 +813,31 @@ func (c *Client) Extension(ext string) (bool, string) {
 	return ok, param
 }
 
+// Extensions returns a copy of all extensions advertised by the server in
+// response to EHLO, mapped to their parameters. The extension names are
+// upper-cased. If the EHLO exchange failed, Extensions returns nil.
+func (c *Client) Extensions() map[string]string {
+	if err := c.hello(); err != nil {
+		return nil
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.ext == nil {
+		return nil
+	}
+	extensions := make(map[string]string, len(c.ext))
+	for ext, param := range c.ext {
+		extensions[ext] = param
+	}
+	return extensions
+}
+
+// Banner returns the greeting message the server sent when the connection was
+// established, without the reply code.
+func (c *Client) Banner() string {
+	return c.banner
+}
+
 // Reset sends the RSET command to the server, aborting the current mail
 // transaction.
 func (c *Client) Reset() error {
@@ -599,11 +905,50 @@ func (c *Client) SetLogAuthData() {
 	c.mutex.Unlock()
 }
 
+// SetNoInitialResponse disables sending the initial response with the AUTH command for the given
+// SASL mechanisms, i. e. "PLAIN", for servers that mishandle the SASL-IR extension of RFC 4954. The
+// initial response is then sent as answer to the empty challenge of the server instead. If no
+// mechanism is given, the initial response is disabled for all mechanisms.
+func (c *Client) SetNoInitialResponse(mechanisms ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.noInitialResponse = make(map[string]bool, len(mechanisms))
+	for _, mechanism := range mechanisms {
+		c.noInitialResponse[strings.ToUpper(mechanism)] = true
+	}
+}
+
+// sendsInitialResponse reports whether the initial response of the given SASL mechanism is sent
+// with the AUTH command.
+func (c *Client) sendsInitialResponse(mechanism string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.noInitialResponse == nil {
+		return true
+	}
+	return len(c.noInitialResponse) > 0 && !c.noInitialResponse[strings.ToUpper(mechanism)]
+}
+
+// SetDebugHook sets a DebugHook that is called for each line of the SMTP protocol. The hook is
+// called independently of the debug logging. A nil hook removes a previously set hook.
+func (c *Client) SetDebugHook(hook DebugHook) {
+	c.mutex.Lock()
+	c.debugHook = hook
+	c.mutex.Unlock()
+}
+
 // SetDSNMailReturnOption sets the DSN mail return option for the Mail method
 func (c *Client) SetDSNMailReturnOption(d string) {
 	c.dsnmrtype = d
 }
 
+// SetSMTPUTF8 controls whether the MAIL command carries the SMTPUTF8 parameter if the server
+// supports the SMTPUTF8 extension. It is enabled by default and can be disabled for messages
+// that do not contain internationalized addresses or headers.
+func (c *Client) SetSMTPUTF8(enabled bool) {
+	c.noSMTPUTF8 = !enabled
+}
+
 // SetDSNRcptNotifyOption sets the DSN recipient notify option for the Mail method
 func (c *Client) SetDSNRcptNotifyOption(d string) {
 	c.dsnrntype = d
@@ -631,6 +976,22 @@ func (c *Client) UpdateDeadline(timeout time.Duration) error {
 	return nil
 }
 
+// Interrupt sets the deadline of the underlying connection to the current time,
+// causing pending and future I/O on the connection to fail immediately. Unlike
+// the other methods of Client, Interrupt does not wait for a pending command to
+// complete, so it can be used to abort a running mail transaction from another
+// goroutine. After Interrupt, the state of the SMTP session is undefined and the
+// Client should be closed.
+func (c *Client) Interrupt() error {
+	if c.conn == nil {
+		return errors.New("smtp: client has no connection")
+	}
+	if err := c.conn.SetDeadline(time.Now()); err != nil {
+		return fmt.Errorf("smtp: failed to interrupt connection: %w", err)
+	}
+	return nil
+}
+
 // GetTLSConnectionState retrieves the TLS connection state of the client's current connection.
 // Returns an error if the connection is not using TLS or if the connection is not established.
 func (c *Client) GetTLSConnectionState() (*tls.ConnectionState, error) {
@@ -658,6 +1019,58 @@ func (c *Client) debugLog(d log.Direction, f string, a ...interface{}) {
 	}
 }
 
+// traceCommand passes the given command to the DebugHook, if one is set. During the SMTP
+// authentication, everything but the AUTH command and mechanism is redacted.
+func (c *Client) traceCommand(format string, args ...interface{}) {
+	if c.debugHook == nil {
+		return
+	}
+	line := fmt.Sprintf(format, args...)
+	if c.authIsActive {
+		fields := strings.SplitN(line, " ", 3)
+		switch {
+		case !strings.EqualFold(fields[0], "AUTH"):
+			line = "<SMTP auth data redacted>"
+		case len(fields) == 3:
+			line = fields[0] + " " + fields[1] + " <SMTP auth data redacted>"
+		}
+	}
+	c.debugHook(log.DirClientToServer, line)
+}
+
+// traceResponse passes the given server response to the DebugHook, if one is set. Multi-line
+// responses are passed line by line. Server challenges during the SMTP authentication are redacted.
+func (c *Client) traceResponse(code int, msg string) {
+	if c.debugHook == nil || code == 0 {
+		return
+	}
+	if c.authIsActive && code >= 300 && code <= 400 {
+		msg = "<SMTP auth data redacted>"
+	}
+	lines := strings.Split(msg, "\n")
+	for i, line := range lines {
+		separator := " "
+		if i < len(lines)-1 {
+			separator = "-"
+		}
+		c.debugHook(log.DirServerToClient, fmt.Sprintf("%d%s%s", code, separator, line))
+	}
+}
+
+// validateCommand checks the address and the ESMTP parameters of a MAIL or RCPT command for CR or
+// LF characters with validateLine.
+func validateCommand(addr string, params []string) error {
+	if err := validateLine(addr); err != nil {
+		return err
+	}
+	for _, param := range params {
+		if err := validateLine(param); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // validateLine checks to see if a line has CR or LF as per RFC 5321.
 func validateLine(line string) error {
 	if strings.ContainsAny(line, "\n\r") {
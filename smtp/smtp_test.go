@@ -29,6 +29,7 @@ import (
 	"hash"
 	"io"
 	"net"
+	"net/textproto"
 	"os"
 	"strings"
 	"sync"
@@ -173,6 +174,22 @@ var authTests = []authTest{
 		[]bool{false, false, true},
 		false,
 	},
+	{
+		LoginAuthTolerant("user", "pass", "testserver", false),
+		[]string{"Password:", "Username:"},
+		"LOGIN",
+		[]string{"", "pass", "user"},
+		[]bool{false, false},
+		false,
+	},
+	{
+		LoginAuthTolerant("user", "pass", "testserver", false),
+		[]string{"", "", ""},
+		"LOGIN",
+		[]string{"", "user", "pass", ""},
+		[]bool{false, false, false},
+		false,
+	},
 	{
 		CRAMMD5Auth("user", "pass"),
 		[]string{"<123456.1322876914@testserver>"},
@@ -742,6 +759,22 @@ func TestLoginAuth(t *testing.T) {
 	})
 }
 
+func TestLoginAuthTolerant(t *testing.T) {
+	auth := LoginAuthTolerant("user", "pass", "servername", false)
+	if _, _, err := auth.Start(&ServerInfo{Name: "servername", TLS: true}); err != nil {
+		t.Fatalf("failed to start login authentication: %s", err)
+	}
+	for i := 0; i < loginAuthMaxPrompts; i++ {
+		if _, err := auth.Next([]byte{}, true); err != nil {
+			t.Fatalf("failed on server challenge %d: %s", i+1, err)
+		}
+	}
+	_, err := auth.Next([]byte{}, true)
+	if !errors.Is(err, ErrUnexpectedServerResponse) {
+		t.Errorf("expected error to be: %s, got: %s", ErrUnexpectedServerResponse, err)
+	}
+}
+
 func TestLoginAuth_noEnc(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -1485,6 +1518,9 @@ func TestNewClient(t *testing.T) {
 		if err != nil {
 			t.Fatalf("failed to create client: %s", err)
 		}
+		if banner := client.Banner(); banner != "go-mail test server ready ESMTP" {
+			t.Errorf("unexpected server banner: %q", banner)
+		}
 		if err := client.Close(); err != nil {
 			t.Errorf("failed to close client: %s", err)
 		}
@@ -2539,6 +2575,57 @@ func TestClient_Rcpt(t *testing.T) {
 	})
 }
 
+func TestClient_RcptWithResponse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	PortAdder.Add(1)
+	serverPort := int(TestServerPortBase + PortAdder.Load())
+	featureSet := "250-DSN\r\n250-8BITMIME\r\n250-SMTPUTF8\r\n250 STARTTLS"
+	go func() {
+		if err := simpleSMTPServer(ctx, t, &serverProps{
+			FeatureSet: featureSet,
+			ListenPort: serverPort,
+		},
+		); err != nil {
+			t.Errorf("failed to start test server: %s", err)
+			return
+		}
+	}()
+	time.Sleep(time.Millisecond * 30)
+	client, err := Dial(fmt.Sprintf("%s:%d", TestServerAddr, serverPort))
+	if err != nil {
+		t.Fatalf("failed to dial to test server: %s", err)
+	}
+	t.Cleanup(func() {
+		if err = client.Close(); err != nil {
+			t.Errorf("failed to close client: %s", err)
+		}
+	})
+	t.Run("accepted recipient returns response", func(t *testing.T) {
+		code, msg, err := client.RcptWithResponse("valid-to@domain.tld")
+		if err != nil {
+			t.Fatalf("failed to set recipient address: %s", err)
+		}
+		if code != 250 || msg != "2.0.0 OK" {
+			t.Errorf("unexpected response, want: %d %s, got: %d %s", 250, "2.0.0 OK", code, msg)
+		}
+	})
+	t.Run("rejected recipient returns response and error", func(t *testing.T) {
+		code, msg, err := client.RcptWithResponse("invalid-to@domain.tld")
+		if err == nil {
+			t.Fatal("expected rejected recipient to fail")
+		}
+		if code != 500 || msg != "5.1.2 Invalid to: <invalid-to@domain.tld>" {
+			t.Errorf("unexpected response, got: %d %s", code, msg)
+		}
+	})
+	t.Run("recipient address with newlines fails", func(t *testing.T) {
+		if _, _, err := client.RcptWithResponse("valid-to@domain.tld\r\n"); err == nil {
+			t.Error("expected recipient address with newlines to fail")
+		}
+	})
+}
+
 func TestClient_Data(t *testing.T) {
 	t.Run("normal mail data transmission succeeds", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
@@ -2612,6 +2699,81 @@ func TestClient_Data(t *testing.T) {
 	})
 }
 
+func TestClient_Bdat(t *testing.T) {
+	t.Run("message data is sent in chunks", func(t *testing.T) {
+		server := []string{
+			"220 Fake server ready ESMTP",
+			"250 2.0.0 Chunk OK",
+			"250 2.0.0 Message OK",
+			"",
+		}
+		var wrote bytes.Buffer
+		var fake faker
+		fake.ReadWriter = struct {
+			io.Reader
+			io.Writer
+		}{
+			strings.NewReader(strings.Join(server, "\r\n")),
+			&wrote,
+		}
+		client, err := NewClient(fake, "fake.host")
+		if err != nil {
+			t.Fatalf("failed to create client on faker server: %s", err)
+		}
+		writer, err := client.Bdat()
+		if err != nil {
+			t.Fatalf("failed to create BDAT writer: %s", err)
+		}
+		data := bytes.Repeat([]byte("\x00\r\n.\n"), bdatChunkSize/5+1)
+		if _, err = writer.Write(data); err != nil {
+			t.Fatalf("failed to write message data: %s", err)
+		}
+		if err = writer.Close(); err != nil {
+			t.Fatalf("failed to close BDAT writer: %s", err)
+		}
+		want := fmt.Sprintf("BDAT %d\r\n%sBDAT %d LAST\r\n%s", bdatChunkSize, data[:bdatChunkSize],
+			len(data)-bdatChunkSize, data[bdatChunkSize:])
+		if wrote.String() != want {
+			t.Errorf("unexpected BDAT transmission of %d bytes, want %d bytes", wrote.Len(), len(want))
+		}
+	})
+	t.Run("rejected chunk fails", func(t *testing.T) {
+		server := []string{
+			"220 Fake server ready ESMTP",
+			"552 5.3.4 Message too big",
+			"",
+		}
+		var fake faker
+		fake.ReadWriter = struct {
+			io.Reader
+			io.Writer
+		}{
+			strings.NewReader(strings.Join(server, "\r\n")),
+			io.Discard,
+		}
+		client, err := NewClient(fake, "fake.host")
+		if err != nil {
+			t.Fatalf("failed to create client on faker server: %s", err)
+		}
+		writer, err := client.Bdat()
+		if err != nil {
+			t.Fatalf("failed to create BDAT writer: %s", err)
+		}
+		if _, err = writer.Write([]byte("test message")); err != nil {
+			t.Fatalf("failed to write message data: %s", err)
+		}
+		if err = writer.Close(); err == nil {
+			t.Error("expected rejected BDAT chunk to fail")
+		}
+	})
+	t.Run("BDAT without connection fails", func(t *testing.T) {
+		client := &Client{}
+		if _, err := client.Bdat(); !errors.Is(err, ErrNoConnection) {
+			t.Errorf("expected error: %s, got: %s", ErrNoConnection, err)
+		}
+	})
+}
+
 func TestSendMail(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -2951,6 +3113,79 @@ func TestClient_Extension(t *testing.T) {
 	})
 }
 
+func TestClient_Extensions(t *testing.T) {
+	t.Run("extensions on functioning client connection", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-DSN\r\n250-SIZE 1000\r\n250 STARTTLS"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			},
+			); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+		client, err := Dial(fmt.Sprintf("%s:%d", TestServerAddr, serverPort))
+		if err != nil {
+			t.Fatalf("failed to dial to test server: %s", err)
+		}
+		t.Cleanup(func() {
+			if err = client.Close(); err != nil {
+				t.Errorf("failed to close client: %s", err)
+			}
+		})
+		extensions := client.Extensions()
+		if len(extensions) != 3 {
+			t.Fatalf("expected 3 extensions, got: %d", len(extensions))
+		}
+		if extensions["SIZE"] != "1000" {
+			t.Errorf("expected SIZE extension parameter to be 1000, got: %s", extensions["SIZE"])
+		}
+		extensions["SIZE"] = "1"
+		if _, param := client.Extension("SIZE"); param != "1000" {
+			t.Errorf("expected returned extensions to be a copy, got SIZE parameter: %s", param)
+		}
+	})
+	t.Run("extensions fails on EHLO/HELO", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-DSN\r\n250 STARTTLS"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FailOnEhlo: true,
+				FailOnHelo: true,
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			},
+			); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+		client, err := Dial(fmt.Sprintf("%s:%d", TestServerAddr, serverPort))
+		if err != nil {
+			t.Fatalf("failed to dial to test server: %s", err)
+		}
+		t.Cleanup(func() {
+			if err = client.Close(); err != nil {
+				t.Errorf("failed to close client: %s", err)
+			}
+		})
+		if extensions := client.Extensions(); extensions != nil {
+			t.Errorf("expected no extensions on failed EHLO/HELO, got: %v", extensions)
+		}
+	})
+}
+
 func TestClient_Reset(t *testing.T) {
 	t.Run("reset on functioning client conneciton", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
@@ -3199,6 +3434,136 @@ func TestClient_SetLogAuthData(t *testing.T) {
 	})
 }
 
+func TestClient_SetNoInitialResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		mechanisms []string
+		want       string
+	}{
+		{"initial response is sent by default", nil, "AUTH XOAUTH2 dXNlcj11c2VyAWF1dGg9QmVhcmVyIHRva2VuAQE=\r\n"},
+		{
+			"initial response is disabled for all mechanisms", []string{},
+			"AUTH XOAUTH2\r\ndXNlcj11c2VyAWF1dGg9QmVhcmVyIHRva2VuAQE=\r\n",
+		},
+		{
+			"initial response is disabled for mechanism", []string{"xoauth2"},
+			"AUTH XOAUTH2\r\ndXNlcj11c2VyAWF1dGg9QmVhcmVyIHRva2VuAQE=\r\n",
+		},
+		{
+			"initial response is kept for other mechanism", []string{"PLAIN"},
+			"AUTH XOAUTH2 dXNlcj11c2VyAWF1dGg9QmVhcmVyIHRva2VuAQE=\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := []string{
+				"220 Fake server ready ESMTP",
+				"250-fake.server",
+				"250-AUTH XOAUTH2",
+				"250 8BITMIME",
+			}
+			if tt.mechanisms != nil && !strings.Contains(tt.want, "XOAUTH2 ") {
+				server = append(server, "334 ")
+			}
+			server = append(server, "235 2.7.0 Accepted", "")
+			var wrote strings.Builder
+			var fake faker
+			fake.ReadWriter = struct {
+				io.Reader
+				io.Writer
+			}{
+				strings.NewReader(strings.Join(server, "\r\n")),
+				&wrote,
+			}
+			client, err := NewClient(fake, "fake.host")
+			if err != nil {
+				t.Fatalf("failed to create client on faker server: %s", err)
+			}
+			t.Cleanup(func() {
+				if err = client.Close(); err != nil {
+					t.Errorf("failed to close client connection: %s", err)
+				}
+			})
+			if tt.mechanisms != nil {
+				client.SetNoInitialResponse(tt.mechanisms...)
+			}
+			if err = client.Auth(XOAuth2Auth("user", "token")); err != nil {
+				t.Errorf("failed to authenticate to faker server: %s", err)
+			}
+			if !strings.HasSuffix(wrote.String(), tt.want) {
+				t.Errorf("unexpected client request, want suffix: %q, got: %q", tt.want, wrote.String())
+			}
+		})
+	}
+}
+
+func TestClient_SetDebugHook(t *testing.T) {
+	t.Run("debug hook receives redacted protocol lines", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-AUTH PLAIN\r\n250-8BITMIME\r\n250-DSN\r\n250 SMTPUTF8"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			},
+			); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+
+		client, err := Dial(fmt.Sprintf("%s:%d", TestServerAddr, serverPort))
+		if err != nil {
+			t.Fatalf("failed to connect to test server: %s", err)
+		}
+		var lines []string
+		client.SetLogAuthData()
+		client.SetDebugHook(func(direction log.Direction, line string) {
+			prefix := "S: "
+			if direction == log.DirClientToServer {
+				prefix = "C: "
+			}
+			lines = append(lines, prefix+line)
+		})
+		if err = client.Auth(PlainAuth("", "user", "pass", TestServerAddr, false)); err != nil {
+			t.Fatalf("failed to authenticate to test server: %s", err)
+		}
+		if err = client.Quit(); err != nil {
+			t.Fatalf("failed to quit: %s", err)
+		}
+		want := []string{
+			"C: EHLO localhost",
+			"S: 250-localhost.localdomain",
+			"S: 250-AUTH PLAIN",
+			"S: 250-8BITMIME",
+			"S: 250-DSN",
+			"S: 250 SMTPUTF8",
+			"C: AUTH PLAIN <SMTP auth data redacted>",
+			"S: 235 2.7.0 Authentication successful",
+			"C: QUIT",
+			"S: 221 2.0.0 Bye",
+		}
+		if len(lines) != len(want) {
+			t.Fatalf("unexpected protocol lines, want: %q, got: %q", want, lines)
+		}
+		for i := range want {
+			if lines[i] != want[i] {
+				t.Errorf("unexpected protocol line %d, want: %q, got: %q", i, want[i], lines[i])
+			}
+		}
+	})
+	t.Run("nil debug hook is ignored", func(t *testing.T) {
+		client := &Client{}
+		client.SetDebugHook(nil)
+		client.traceCommand("NOOP")
+		client.traceResponse(250, "2.0.0 OK")
+	})
+}
+
 func TestClient_SetDSNRcptNotifyOption(t *testing.T) {
 	tests := []string{"NEVER", "SUCCESS", "FAILURE", "DELAY"}
 	for _, test := range tests {
@@ -3320,6 +3685,51 @@ func TestClient_HasConnection(t *testing.T) {
 	})
 }
 
+func TestClient_Interrupt(t *testing.T) {
+	t.Run("interrupt on sane client makes commands fail", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		PortAdder.Add(1)
+		serverPort := int(TestServerPortBase + PortAdder.Load())
+		featureSet := "250-DSN\r\n250 STARTTLS"
+		go func() {
+			if err := simpleSMTPServer(ctx, t, &serverProps{
+				FeatureSet: featureSet,
+				ListenPort: serverPort,
+			},
+			); err != nil {
+				t.Errorf("failed to start test server: %s", err)
+				return
+			}
+		}()
+		time.Sleep(time.Millisecond * 30)
+		client, err := Dial(fmt.Sprintf("%s:%d", TestServerAddr, serverPort))
+		if err != nil {
+			t.Fatalf("failed to dial to test server: %s", err)
+		}
+		t.Cleanup(func() {
+			_ = client.Close()
+		})
+		if err = client.Interrupt(); err != nil {
+			t.Fatalf("failed to interrupt connection: %s", err)
+		}
+		if err = client.Noop(); err == nil {
+			t.Error("expected NOOP to fail on interrupted connection")
+		}
+	})
+	t.Run("interrupt on no connection should fail", func(t *testing.T) {
+		client := &Client{}
+		var err error
+		if err = client.Interrupt(); err == nil {
+			t.Error("expected client interrupt to fail on no connection")
+		}
+		expError := "smtp: client has no connection"
+		if !strings.EqualFold(err.Error(), expError) {
+			t.Errorf("expected error to be %q, got: %q", expError, err)
+		}
+	})
+}
+
 func TestClient_UpdateDeadline(t *testing.T) {
 	t.Run("update deadline on sane client succeeds", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
@@ -4059,3 +4469,196 @@ func getTLSConfig(t *testing.T) *tls.Config {
 		ServerName:   "example.com",
 	}
 }
+
+func TestClient_MailWithParams(t *testing.T) {
+	server := []string{
+		"220 Fake server ready ESMTP",
+		"250-fake.server",
+		"250-DSN",
+		"250 8BITMIME",
+		"250 2.1.0 Sender OK",
+		"250 2.1.5 Recipient OK",
+		"250 2.1.0 Sender OK",
+		"",
+	}
+	var wrote strings.Builder
+	var fake faker
+	fake.ReadWriter = struct {
+		io.Reader
+		io.Writer
+	}{
+		strings.NewReader(strings.Join(server, "\r\n")),
+		&wrote,
+	}
+	client, err := NewClient(fake, "fake.host")
+	if err != nil {
+		t.Fatalf("failed to create client on faker server: %s", err)
+	}
+	t.Cleanup(func() {
+		if err = client.Close(); err != nil {
+			t.Errorf("failed to close client connection: %s", err)
+		}
+	})
+	client.SetDSNRcptNotifyOption("SUCCESS")
+	if err = client.MailWithParams("valid-from@domain.tld", "AUTH=<>", "XCUSTOM"); err != nil {
+		t.Fatalf("failed to set mail from address: %s", err)
+	}
+	if _, _, err = client.RcptWithParams("valid-to@domain.tld", "ORCPT=rfc822;valid-to@domain.tld"); err != nil {
+		t.Fatalf("failed to set recipient address: %s", err)
+	}
+	want := "MAIL FROM:<valid-from@domain.tld> BODY=8BITMIME AUTH=<> XCUSTOM\r\n" +
+		"RCPT TO:<valid-to@domain.tld> NOTIFY=SUCCESS ORCPT=rfc822;valid-to@domain.tld\r\n"
+	if !strings.HasSuffix(wrote.String(), want) {
+		t.Errorf("unexpected client request, want suffix: %q, got: %q", want, wrote.String())
+	}
+	if err = client.MailWithParams("valid-from@domain.tld", "BODY=BINARYMIME"); err != nil {
+		t.Fatalf("failed to set mail from address: %s", err)
+	}
+	if !strings.HasSuffix(wrote.String(), "MAIL FROM:<valid-from@domain.tld> BODY=BINARYMIME\r\n") {
+		t.Errorf("expected BODY parameter to replace BODY=8BITMIME, got: %q", wrote.String())
+	}
+	if err = client.MailWithParams("valid-from@domain.tld", "AUTH=<>\r\nRSET"); err == nil {
+		t.Error("expected parameter with new lines to fail")
+	}
+}
+
+// writeRecorder is an io.Writer that records each call to Write separately.
+type writeRecorder struct {
+	writes []string
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestClient_MailRcptPipelined(t *testing.T) {
+	newPipeliningClient := func(t *testing.T, replies ...string) (*Client, *writeRecorder) {
+		t.Helper()
+		server := append([]string{
+			"220 Fake server ready ESMTP",
+			"250-fake.server",
+			"250-DSN",
+			"250 PIPELINING",
+		}, replies...)
+		recorder := &writeRecorder{}
+		var fake faker
+		fake.ReadWriter = struct {
+			io.Reader
+			io.Writer
+		}{
+			strings.NewReader(strings.Join(server, "\r\n")),
+			recorder,
+		}
+		client, err := NewClient(fake, "fake.host")
+		if err != nil {
+			t.Fatalf("failed to create client on faker server: %s", err)
+		}
+		return client, recorder
+	}
+	rcptParams := func(rcpt string) []string {
+		if rcpt == "second@domain.tld" {
+			return []string{"ORCPT=rfc822;second@domain.tld"}
+		}
+		return nil
+	}
+	rcpts := []string{"first@domain.tld", "second@domain.tld", "third@domain.tld"}
+
+	t.Run("commands are sent in a single write", func(t *testing.T) {
+		client, recorder := newPipeliningClient(t, "250 2.1.0 Sender OK", "250 2.1.5 Recipient OK",
+			"251 2.1.5 Recipient will be forwarded", "250 2.1.5 Recipient OK", "")
+		client.SetDSNRcptNotifyOption("FAILURE")
+		replies, err := client.MailRcptPipelined("valid-from@domain.tld", []string{"AUTH=<>"}, rcpts, rcptParams)
+		if err != nil {
+			t.Fatalf("failed to send pipelined commands: %s", err)
+		}
+		want := "MAIL FROM:<valid-from@domain.tld> AUTH=<>\r\n" +
+			"RCPT TO:<first@domain.tld> NOTIFY=FAILURE\r\n" +
+			"RCPT TO:<second@domain.tld> NOTIFY=FAILURE ORCPT=rfc822;second@domain.tld\r\n" +
+			"RCPT TO:<third@domain.tld> NOTIFY=FAILURE\r\n"
+		if len(recorder.writes) != 2 || recorder.writes[1] != want {
+			t.Errorf("expected EHLO and a single pipelined write %q, got: %q", want, recorder.writes)
+		}
+		if len(replies) != 3 || replies[1].Code != 251 || replies[1].Msg != "2.1.5 Recipient will be forwarded" {
+			t.Errorf("unexpected RCPT replies: %+v", replies)
+		}
+	})
+	t.Run("rejected recipient", func(t *testing.T) {
+		client, _ := newPipeliningClient(t, "250 2.1.0 Sender OK", "250 2.1.5 Recipient OK",
+			"550 5.1.1 Mailbox unavailable", "250 2.1.5 Recipient OK", "")
+		replies, err := client.MailRcptPipelined("valid-from@domain.tld", nil, rcpts, nil)
+		if err != nil {
+			t.Fatalf("failed to send pipelined commands: %s", err)
+		}
+		if len(replies) != 3 || replies[0].Err != nil || replies[2].Err != nil {
+			t.Fatalf("unexpected RCPT replies: %+v", replies)
+		}
+		var protoErr *textproto.Error
+		if !errors.As(replies[1].Err, &protoErr) || protoErr.Code != 550 {
+			t.Errorf("expected rejected recipient to fail with code 550, got: %s", replies[1].Err)
+		}
+	})
+	t.Run("rejected sender", func(t *testing.T) {
+		client, _ := newPipeliningClient(t, "553 5.1.8 Sender rejected", "503 5.5.1 No sender",
+			"503 5.5.1 No sender", "503 5.5.1 No sender", "250 2.0.0 OK", "")
+		if _, err := client.MailRcptPipelined("valid-from@domain.tld", nil, rcpts, nil); err == nil {
+			t.Fatal("expected rejected sender to fail")
+		}
+		if err := client.Noop(); err != nil {
+			t.Errorf("expected all replies of the pipelined group to be consumed, got: %s", err)
+		}
+	})
+	t.Run("connection closed", func(t *testing.T) {
+		client, _ := newPipeliningClient(t, "250 2.1.0 Sender OK", "")
+		if _, err := client.MailRcptPipelined("valid-from@domain.tld", nil, rcpts, nil); err == nil {
+			t.Error("expected missing replies to fail")
+		}
+	})
+	t.Run("invalid recipient", func(t *testing.T) {
+		client, recorder := newPipeliningClient(t, "")
+		if _, err := client.MailRcptPipelined("valid-from@domain.tld", nil,
+			[]string{"first@domain.tld\r\nRSET"}, nil); err == nil {
+			t.Error("expected recipient with new lines to fail")
+		}
+		if len(recorder.writes) != 1 {
+			t.Errorf("expected no commands to be sent, got: %q", recorder.writes)
+		}
+	})
+}
+
+func TestClient_SetSMTPUTF8(t *testing.T) {
+	server := []string{
+		"220 Fake server ready ESMTP",
+		"250-fake.server",
+		"250 SMTPUTF8",
+		"250 2.1.0 Sender OK",
+		"250 2.1.0 Sender OK",
+		"",
+	}
+	var wrote strings.Builder
+	var fake faker
+	fake.ReadWriter = struct {
+		io.Reader
+		io.Writer
+	}{
+		strings.NewReader(strings.Join(server, "\r\n")),
+		&wrote,
+	}
+	client, err := NewClient(fake, "fake.host")
+	if err != nil {
+		t.Fatalf("failed to create client on faker server: %s", err)
+	}
+	if err = client.Mail("valid-from@domain.tld"); err != nil {
+		t.Fatalf("failed to set mail from address: %s", err)
+	}
+	if !strings.HasSuffix(wrote.String(), "MAIL FROM:<valid-from@domain.tld> SMTPUTF8\r\n") {
+		t.Errorf("expected SMTPUTF8 parameter by default, got: %q", wrote.String())
+	}
+	client.SetSMTPUTF8(false)
+	if err = client.Mail("valid-from@domain.tld"); err != nil {
+		t.Fatalf("failed to set mail from address: %s", err)
+	}
+	if !strings.HasSuffix(wrote.String(), "MAIL FROM:<valid-from@domain.tld>\r\n") {
+		t.Errorf("expected no SMTPUTF8 parameter, got: %q", wrote.String())
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import "errors"
+
+// ErrServerNoSMTPUTF8 indicates that the server does not support SMTPUTF8 for a message with
+// internationalized addresses.
+var ErrServerNoSMTPUTF8 = errors.New("message has internationalized addresses, but server does not " +
+	"support SMTPUTF8")
+
+// hasUTF8Address returns true if the addr-spec of any envelope or header address of the Msg contains
+// non-ASCII characters. Such addresses can only be transmitted with the SMTPUTF8 extension.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc6531#section-3.3
+func (m *Msg) hasUTF8Address() bool {
+	for _, addresses := range m.addrHeader {
+		for _, address := range addresses {
+			if address != nil && !isASCII(address.Address) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasUTF8Header returns true if the value of any generic or preformatted header of the Msg contains
+// non-ASCII characters that are not encoded as encoded-words.
+func (m *Msg) hasUTF8Header() bool {
+	for _, values := range m.genHeader {
+		for _, value := range values {
+			if !isASCII(value) {
+				return true
+			}
+		}
+	}
+	for _, value := range m.preformHeader {
+		if !isASCII(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// utf8Downgraded returns a copy of the Msg in which all generic and preformatted header values with
+// non-ASCII characters are encoded as encoded-words, so that the Msg can be sent to servers without
+// the SMTPUTF8 extension. The original Msg remains unchanged.
+//
+// Returns:
+//   - A pointer to the downgraded copy of the Msg.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2047
+//   - https://datatracker.ietf.org/doc/html/rfc6857
+func (m *Msg) utf8Downgraded() *Msg {
+	downgraded := *m
+	downgraded.genHeader = make(map[Header][]string, len(m.genHeader))
+	for header, values := range m.genHeader {
+		downgraded.genHeader[header] = make([]string, len(values))
+		for i, value := range values {
+			downgraded.genHeader[header][i] = value
+			if !isASCII(value) {
+				downgraded.genHeader[header][i] = m.encodeString(value)
+			}
+		}
+	}
+	downgraded.preformHeader = make(map[Header]string, len(m.preformHeader))
+	for header, value := range m.preformHeader {
+		downgraded.preformHeader[header] = value
+		if !isASCII(value) {
+			downgraded.preformHeader[header] = m.encodeString(value)
+		}
+	}
+	return &downgraded
+}
+
+// isASCII returns true if the given string only consists of ASCII characters.
+func isASCII(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
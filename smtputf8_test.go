@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wneessen/go-mail/log"
+)
+
+func TestClient_SMTPUTF8(t *testing.T) {
+	tests := []struct {
+		name         string
+		featureSet   string
+		header       string
+		cc           string
+		wantSMTPUTF8 bool
+		wantErr      bool
+	}{
+		{"ASCII message", "250-8BITMIME\r\n250 SMTPUTF8", "", "", false, false},
+		{"UTF-8 header", "250-8BITMIME\r\n250 SMTPUTF8", "Grüße", "", true, false},
+		{"UTF-8 header is downgraded", "250 8BITMIME", "Grüße", "", false, false},
+		{"UTF-8 address", "250-8BITMIME\r\n250 SMTPUTF8", "", "jörg@domain.tld", true, true},
+		{"UTF-8 address without SMTPUTF8 fails", "250 8BITMIME", "", "jörg@domain.tld", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			PortAdder.Add(1)
+			serverPort := int(TestServerPortBase + PortAdder.Load())
+			go func() {
+				if err := simpleSMTPServer(ctx, t, &serverProps{
+					FeatureSet: tt.featureSet,
+					ListenPort: serverPort,
+				}); err != nil {
+					t.Errorf("failed to start test server: %s", err)
+					return
+				}
+			}()
+			time.Sleep(time.Millisecond * 30)
+			ctxDial, cancelDial := context.WithTimeout(ctx, time.Millisecond*500)
+			t.Cleanup(cancelDial)
+
+			var mailFrom string
+			client, err := NewClient(DefaultHost, WithPort(serverPort), WithTLSPolicy(NoTLS),
+				WithDebugHook(func(direction log.Direction, line string) {
+					if direction == log.DirClientToServer && strings.HasPrefix(line, "MAIL FROM:") {
+						mailFrom = line
+					}
+				}))
+			if err != nil {
+				t.Fatalf("failed to create new client: %s", err)
+			}
+			if err = client.DialWithContext(ctxDial); err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					t.Skip("failed to connect to the test server due to timeout")
+				}
+				t.Fatalf("failed to connect to the test server: %s", err)
+			}
+			t.Cleanup(func() {
+				if err := client.Close(); err != nil {
+					t.Errorf("failed to close client: %s", err)
+				}
+			})
+			message := testMessage(t)
+			if tt.header != "" {
+				message.SetGenHeaderPreformatted(HeaderOrganization, tt.header)
+			}
+			if tt.cc != "" {
+				if err = message.Cc(tt.cc); err != nil {
+					t.Fatalf("failed to set cc address: %s", err)
+				}
+			}
+			err = client.Send(message)
+			if tt.wantErr && err == nil {
+				t.Error("expected sending the message to fail")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("failed to send message: %s", err)
+			}
+			if tt.wantErr && !tt.wantSMTPUTF8 {
+				var sendErr *SendError
+				if !errors.As(err, &sendErr) || sendErr.Reason != ErrNoSMTPUTF8 {
+					t.Errorf("expected SendError with reason ErrNoSMTPUTF8, got: %s", err)
+				}
+				return
+			}
+			if got := strings.HasSuffix(mailFrom, " SMTPUTF8"); got != tt.wantSMTPUTF8 {
+				t.Errorf("unexpected SMTPUTF8 parameter, want: %t, got: %q", tt.wantSMTPUTF8, mailFrom)
+			}
+			if tt.header != "" && message.preformHeader[HeaderOrganization] != tt.header {
+				t.Errorf("expected message to remain unchanged, got: %s", message.preformHeader[HeaderOrganization])
+			}
+		})
+	}
+}
+
+func TestMsg_utf8Downgraded(t *testing.T) {
+	message := testMessage(t)
+	message.SetGenHeaderPreformatted(HeaderOrganization, "Grüße GmbH")
+	if !message.hasUTF8Header() || message.hasUTF8Address() {
+		t.Fatal("expected message to have a UTF-8 header and no UTF-8 address")
+	}
+	downgraded := message.utf8Downgraded()
+	if downgraded.hasUTF8Header() {
+		t.Error("expected downgraded message to have no UTF-8 header")
+	}
+	buffer := bytes.NewBuffer(nil)
+	if _, err := downgraded.WriteTo(buffer); err != nil {
+		t.Fatalf("failed to write message: %s", err)
+	}
+	if !strings.Contains(buffer.String(), "Organization: =?UTF-8?q?Gr=C3=BC=C3=9Fe_GmbH?=") {
+		t.Errorf("expected encoded Organization header, got: %s", buffer.String())
+	}
+	if message.preformHeader[HeaderOrganization] != "Grüße GmbH" {
+		t.Errorf("expected original message to remain unchanged")
+	}
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var (
+	// ErrStreamConsumed is returned if the content of a streamed File is written more than once.
+	ErrStreamConsumed = errors.New("attachment stream has already been consumed")
+
+	// ErrStreamSizeMismatch is returned if a streamed File does not provide the announced number of
+	// bytes.
+	ErrStreamSizeMismatch = errors.New("attachment stream size does not match the announced size")
+)
+
+// AttachStream adds an attachment File to the Msg whose content is streamed from the given io.Reader.
+//
+// Unlike AttachReader, the content of the io.Reader is not read into memory. It is streamed from the
+// reader through the Base64 or quoted-printable encoder into the SMTP connection or io.Writer when the
+// Msg is written, which makes AttachStream suitable for multi-gigabyte payloads. Since the io.Reader
+// can only be read once, the Msg can only be written once as well; writing it again fails with
+// ErrStreamConsumed. If the io.Reader also implements io.Closer, it is closed after it was read.
+//
+// The size is used to verify that the stream provides the complete content and allows
+// Msg.EstimatedSize to compute the size of the Msg without consuming the stream.
+//
+// Parameters:
+//   - name: The name of the file to be attached.
+//   - reader: The io.Reader providing the file data to be attached.
+//   - size: The size of the content in bytes, or a negative value if the size is unknown.
+//   - opts: Optional parameters for customizing the attachment.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc2183
+func (m *Msg) AttachStream(name string, reader io.Reader, size int64, opts ...FileOption) {
+	file := fileFromStream(name, reader, size)
+	m.attachments = m.appendFile(m.attachments, file, opts...)
+}
+
+// fileFromStream returns a File pointer whose content is streamed once from the given io.Reader.
+//
+// Parameters:
+//   - name: The name of the file to be represented by the stream.
+//   - reader: The io.Reader from which the file content will be read.
+//   - size: The size of the content in bytes, or a negative value if the size is unknown.
+//
+// Returns:
+//   - A pointer to the File structure representing the content of the stream.
+func fileFromStream(name string, reader io.Reader, size int64) *File {
+	var mutex sync.Mutex
+	consumed := false
+	return &File{
+		Name:       name,
+		Header:     make(map[string][]string),
+		stream:     true,
+		streamSize: size,
+		Writer: func(writer io.Writer) (int64, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if consumed {
+				return 0, ErrStreamConsumed
+			}
+			consumed = true
+			if closer, ok := reader.(io.Closer); ok {
+				defer func() { _ = closer.Close() }()
+			}
+			readBytes, err := io.Copy(writer, reader)
+			if err != nil {
+				return readBytes, err
+			}
+			if size >= 0 && readBytes != size {
+				return readBytes, fmt.Errorf("%w: expected %d bytes, got %d bytes", ErrStreamSizeMismatch,
+					size, readBytes)
+			}
+			return readBytes, nil
+		},
+	}
+}
+
+// estimationMsg returns a shallow copy of the Msg for the estimation of its size, in which each
+// streamed file is replaced by a copy with a writer function that writes placeholder content of the
+// announced size, so that the size can be estimated without consuming the streams. Streams of
+// unknown size are estimated as empty. The Msg itself is not modified, so that the estimation is
+// safe while the Msg is being sent.
+//
+// Returns:
+//   - A pointer to the copy of the Msg to be used for the estimation.
+func (m *Msg) estimationMsg() *Msg {
+	estimate := *m
+	estimate.attachments = estimationFiles(m.attachments)
+	estimate.embeds = estimationFiles(m.embeds)
+	return &estimate
+}
+
+// estimationFiles returns a copy of the given list of files, in which the streamed files are
+// replaced by copies that write placeholder content of the announced size.
+func estimationFiles(files []*File) []*File {
+	if files == nil {
+		return nil
+	}
+	estimates := make([]*File, len(files))
+	for i, file := range files {
+		if !file.stream {
+			estimates[i] = file
+			continue
+		}
+		size := file.streamSize
+		if size < 0 {
+			size = 0
+		}
+		estimate := *file
+		estimate.Writer = func(w io.Writer) (int64, error) {
+			if counter, ok := w.(*sizeCounter); ok {
+				counter.size += size
+				return size, nil
+			}
+			return io.CopyN(w, zeroReader{}, size)
+		}
+		estimates[i] = &estimate
+	}
+	return estimates
+}
+
+// zeroReader is an io.Reader that provides an endless stream of zero bytes.
+type zeroReader struct{}
+
+// Read satisfies the io.Reader interface for the zeroReader type.
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
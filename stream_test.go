@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// watchedReader is an io.Reader that records whether data was written to the watched counter before
+// the reader was fully consumed.
+type watchedReader struct {
+	closed    bool
+	reader    io.Reader
+	streamed  bool
+	watched   *sizeCounter
+	readCalls int
+}
+
+func (r *watchedReader) Read(p []byte) (int, error) {
+	r.readCalls++
+	if r.readCalls > 2 && r.watched.size > 0 {
+		r.streamed = true
+	}
+	return r.reader.Read(p)
+}
+
+func (r *watchedReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestMsg_AttachStream(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 64*1024)
+	t.Run("content is streamed into the writer", func(t *testing.T) {
+		counter := &sizeCounter{}
+		reader := &watchedReader{reader: bytes.NewReader(content), watched: counter}
+		message := testMessage(t)
+		message.AttachStream("large.bin", reader, int64(len(content)))
+		if _, err := message.WriteTo(counter); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if !reader.streamed {
+			t.Error("expected attachment to be streamed while reading the source")
+		}
+		if !reader.closed {
+			t.Error("expected source to be closed after reading")
+		}
+	})
+	t.Run("streamed content matches the source", func(t *testing.T) {
+		message := testMessage(t)
+		message.AttachStream("large.bin", bytes.NewReader(content), int64(len(content)))
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		parsed, err := EMLToMsgFromReader(buffer)
+		if err != nil {
+			t.Fatalf("failed to parse message: %s", err)
+		}
+		attachments := parsed.GetAttachments()
+		if len(attachments) != 1 {
+			t.Fatalf("expected 1 attachment, got: %d", len(attachments))
+		}
+		got := bytes.NewBuffer(nil)
+		if _, err = attachments[0].Writer(got); err != nil {
+			t.Fatalf("failed to read attachment: %s", err)
+		}
+		if !bytes.Equal(got.Bytes(), content) {
+			t.Errorf("streamed content does not match the source, got %d bytes", got.Len())
+		}
+	})
+	t.Run("estimated size does not consume the stream", func(t *testing.T) {
+		message := testMessage(t)
+		message.AttachStream("large.bin", bytes.NewReader(content), int64(len(content)))
+		estimated := message.EstimatedSize()
+		written, err := message.WriteTo(io.Discard)
+		if err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if estimated != written {
+			t.Errorf("unexpected estimated size, want: %d, got: %d", written, estimated)
+		}
+	})
+	t.Run("estimated size is safe while the message is written", func(t *testing.T) {
+		message := testMessage(t)
+		message.AttachStream("large.bin", bytes.NewReader(content), int64(len(content)))
+		estimated := make(chan int64, 1)
+		go func() {
+			estimated <- message.EstimatedSize()
+		}()
+		written, err := message.WriteTo(io.Discard)
+		if err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if size := <-estimated; size != written {
+			t.Errorf("unexpected estimated size, want: %d, got: %d", written, size)
+		}
+	})
+	t.Run("stream can only be written once", func(t *testing.T) {
+		message := testMessage(t)
+		message.AttachStream("data.bin", strings.NewReader("data"), -1)
+		if _, err := message.WriteTo(io.Discard); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if _, err := message.WriteTo(io.Discard); !errors.Is(err, ErrStreamConsumed) {
+			t.Errorf("expected error: %s, got: %s", ErrStreamConsumed, err)
+		}
+	})
+	t.Run("truncated stream fails", func(t *testing.T) {
+		message := testMessage(t)
+		message.AttachStream("data.bin", strings.NewReader("data"), 10)
+		if _, err := message.WriteTo(io.Discard); !errors.Is(err, ErrStreamSizeMismatch) {
+			t.Errorf("expected error: %s, got: %s", ErrStreamSizeMismatch, err)
+		}
+	})
+}
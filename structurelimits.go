@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrStructureLimitExceeded is returned when writing a Msg whose MIME structure exceeds the limits
+// set with WithStructureLimits.
+var ErrStructureLimitExceeded = errors.New("message structure exceeds limit")
+
+// WithStructureLimits limits the complexity of the MIME structure of the Msg during its creation or
+// initialization.
+//
+// A bug in a template or a loop that adds parts or attachments can create a Msg with thousands of
+// parts, which results in a huge message that is likely rejected or mangled by the receiving
+// servers. With this option, writing or sending such a Msg fails with ErrStructureLimitExceeded
+// instead. The limits are checked after the middlewares have been applied.
+//
+// Parameters:
+//   - maxParts: The maximum number of MIME parts of the Msg, counting the body parts, the
+//     attachments and the embeds. A value of 0 or less means no limit.
+//   - maxDepth: The maximum nesting depth of the multipart bodies of the Msg. A Msg with a single
+//     part has the depth 0, a Msg with a "multipart/alternative" body has the depth 1. A value of 0
+//     or less means no limit.
+//
+// Returns:
+//   - A MsgOption function that sets the structure limits of the Msg.
+func WithStructureLimits(maxParts, maxDepth int) MsgOption {
+	return func(m *Msg) {
+		m.maxParts = maxParts
+		m.maxDepth = maxDepth
+	}
+}
+
+// checkStructureLimits checks the MIME structure of the Msg against the limits set with
+// WithStructureLimits.
+//
+// Returns:
+//   - An error wrapping ErrStructureLimitExceeded if a limit is exceeded; otherwise, returns nil.
+func (m *Msg) checkStructureLimits() error {
+	if m.maxParts > 0 {
+		parts := len(m.attachments) + len(m.embeds)
+		for _, part := range m.parts {
+			if !part.isDeleted {
+				parts++
+			}
+		}
+		if parts > m.maxParts {
+			return fmt.Errorf("%w: %d parts exceed the limit of %d parts", ErrStructureLimitExceeded,
+				parts, m.maxParts)
+		}
+	}
+	if m.maxDepth > 0 {
+		depth := 0
+		for _, nested := range []bool{m.hasMixed(), m.hasRelated(), m.hasAlt(), m.hasPGPType(), m.hasReport()} {
+			if nested {
+				depth++
+			}
+		}
+		if depth > m.maxDepth {
+			return fmt.Errorf("%w: nesting depth of %d exceeds the limit of %d", ErrStructureLimitExceeded,
+				depth, m.maxDepth)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithStructureLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxParts int
+		maxDepth int
+		fails    bool
+	}{
+		{"no limits", 0, 0, false},
+		{"limits not exceeded", 4, 3, false},
+		{"parts exceeded", 3, 0, true},
+		{"depth exceeded", 0, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := NewMsg(WithStructureLimits(tt.maxParts, tt.maxDepth))
+			if err := message.From(TestSenderValid); err != nil {
+				t.Fatalf("failed to set FROM address: %s", err)
+			}
+			if err := message.To(TestRcptValid); err != nil {
+				t.Fatalf("failed to set TO address: %s", err)
+			}
+			message.SetBodyString(TypeTextPlain, "Testmail")
+			message.AddAlternativeString(TypeTextHTML, "<p>Testmail</p>")
+			message.AttachFile("testdata/attachment.txt")
+			message.EmbedFile("testdata/embed.txt")
+
+			buffer := bytes.NewBuffer(nil)
+			_, err := message.WriteTo(buffer)
+			if !tt.fails {
+				if err != nil {
+					t.Errorf("failed to write message: %s", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrStructureLimitExceeded) {
+				t.Fatalf("expected error %q, got: %v", ErrStructureLimitExceeded, err)
+			}
+			if buffer.Len() != 0 {
+				t.Errorf("expected nothing to be written, got: %s", buffer.String())
+			}
+		})
+	}
+	t.Run("deleted parts are not counted", func(t *testing.T) {
+		message := NewMsg(WithStructureLimits(1, 0))
+		message.SetBodyString(TypeTextPlain, "Testmail")
+		message.AddAlternativeString(TypeTextHTML, "<p>Testmail</p>")
+		message.GetParts()[1].Delete()
+		if _, err := message.WriteTo(bytes.NewBuffer(nil)); err != nil {
+			t.Errorf("failed to write message: %s", err)
+		}
+	})
+	t.Run("error message holds the count", func(t *testing.T) {
+		message := NewMsg(WithStructureLimits(1, 0))
+		message.SetBodyString(TypeTextPlain, "Testmail")
+		message.AddAlternativeString(TypeTextHTML, "<p>Testmail</p>")
+		_, err := message.WriteTo(bytes.NewBuffer(nil))
+		if err == nil || !strings.Contains(err.Error(), "2 parts exceed the limit of 1 parts") {
+			t.Errorf("expected error message with part count, got: %v", err)
+		}
+	})
+}
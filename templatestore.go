@@ -0,0 +1,389 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	ht "html/template"
+	"io/fs"
+	"sync"
+	tt "text/template"
+)
+
+const (
+	// templateStoreContent is the name of the template that holds the content of a registered
+	// template pair. The layout includes it with {{template "content" .}}.
+	templateStoreContent = "content"
+
+	// templateStoreLayout is the name of the layout template of a TemplateStore.
+	templateStoreLayout = "layout"
+)
+
+var (
+	// ErrTemplateNotFound is returned if no template pair is registered with the given name in the
+	// TemplateStore.
+	ErrTemplateNotFound = errors.New("template not found in template store")
+
+	// ErrTemplateEmpty is returned if a template pair is registered without a text and an HTML
+	// template.
+	ErrTemplateEmpty = errors.New("template has neither text nor HTML content")
+)
+
+type (
+	// TemplateStore is a registry of named pairs of plain text and HTML templates that share a set of
+	// partials and an optional layout.
+	//
+	// Partials are templates that are available to all template pairs and the layout, i. e. a
+	// "footer" that is included with {{template "footer" .}}. If a layout is set, it is rendered for
+	// each template pair and includes the content of the pair with {{template "content" .}}. The templates
+	// are compiled when they are registered, so that syntax errors are reported early. A TemplateStore
+	// is safe for concurrent use by multiple goroutines.
+	TemplateStore struct {
+		// compiled holds the compiled template pairs by name.
+		compiled map[string]*templatePair
+
+		// hasLayout indicates whether a layout has been set.
+		hasLayout bool
+
+		// htmlBase holds the HTML partials and the HTML layout.
+		htmlBase *ht.Template
+
+		// mutex synchronizes the access to the TemplateStore.
+		mutex sync.RWMutex
+
+		// sources holds the sources of the registered template pairs by name.
+		sources map[string]templateSource
+
+		// textBase holds the text partials and the text layout.
+		textBase *tt.Template
+	}
+
+	// templatePair holds the compiled templates of a registered template pair. Either of the
+	// templates is nil, if the pair has no content for it.
+	templatePair struct {
+		entry string
+		html  *ht.Template
+		text  *tt.Template
+	}
+
+	// templateSource holds the sources of a registered template pair.
+	templateSource struct {
+		html string
+		text string
+	}
+)
+
+// NewTemplateStore returns a new, empty TemplateStore.
+//
+// Returns:
+//   - A pointer to the new TemplateStore.
+func NewTemplateStore() *TemplateStore {
+	return &TemplateStore{
+		compiled: make(map[string]*templatePair),
+		htmlBase: ht.New(templateStoreLayout),
+		sources:  make(map[string]templateSource),
+		textBase: tt.New(templateStoreLayout),
+	}
+}
+
+// AddPartial adds a partial with the given name to the TemplateStore, which can be included by all
+// template pairs and the layout with {{template "name" .}}.
+//
+// Parameters:
+//   - name: The name of the partial.
+//   - text: The source of the plain text variant of the partial.
+//   - html: The source of the HTML variant of the partial.
+//
+// Returns:
+//   - An error if any of the sources cannot be parsed; otherwise, returns nil.
+func (s *TemplateStore) AddPartial(name, text, html string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	textBase, htmlBase, err := s.cloneBase()
+	if err != nil {
+		return err
+	}
+	if _, err = textBase.New(name).Parse(text); err != nil {
+		return fmt.Errorf("failed to parse text partial %q: %w", name, err)
+	}
+	if _, err = htmlBase.New(name).Parse(html); err != nil {
+		return fmt.Errorf("failed to parse HTML partial %q: %w", name, err)
+	}
+	return s.updateBase(textBase, htmlBase, s.hasLayout)
+}
+
+// SetLayout sets the layout of the TemplateStore, which is rendered for each template pair and
+// includes the content of the pair with {{template "content" .}}.
+//
+// Parameters:
+//   - text: The source of the plain text layout.
+//   - html: The source of the HTML layout.
+//
+// Returns:
+//   - An error if any of the sources cannot be parsed; otherwise, returns nil.
+func (s *TemplateStore) SetLayout(text, html string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	textBase, htmlBase, err := s.cloneBase()
+	if err != nil {
+		return err
+	}
+	if _, err = textBase.Parse(text); err != nil {
+		return fmt.Errorf("failed to parse text layout: %w", err)
+	}
+	if _, err = htmlBase.Parse(html); err != nil {
+		return fmt.Errorf("failed to parse HTML layout: %w", err)
+	}
+	return s.updateBase(textBase, htmlBase, true)
+}
+
+// Register registers a pair of plain text and HTML templates with the given name. Either of the
+// sources can be empty, if the Msg should have no body of this type. Registering a name again
+// replaces the previous template pair.
+//
+// Parameters:
+//   - name: The name of the template pair.
+//   - text: The source of the plain text template.
+//   - html: The source of the HTML template.
+//
+// Returns:
+//   - An error if both sources are empty or any of them cannot be parsed; otherwise, returns nil.
+func (s *TemplateStore) Register(name, text, html string) error {
+	if text == "" && html == "" {
+		return fmt.Errorf("%w: %q", ErrTemplateEmpty, name)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	source := templateSource{html: html, text: text}
+	pair, err := s.compile(source)
+	if err != nil {
+		return fmt.Errorf("failed to compile template %q: %w", name, err)
+	}
+	s.sources[name] = source
+	s.compiled[name] = pair
+	return nil
+}
+
+// Render renders the template pair with the given name with the given data.
+//
+// Parameters:
+//   - name: The name of the template pair.
+//   - data: The data to populate the templates.
+//
+// Returns:
+//   - The rendered plain text, or an empty string if the pair has no text template.
+//   - The rendered HTML, or an empty string if the pair has no HTML template.
+//   - An error if the template pair is not registered or fails to execute; otherwise, returns nil.
+func (s *TemplateStore) Render(name string, data interface{}) (string, string, error) {
+	_, text, html, err := s.render(name, data)
+	return text, html, err
+}
+
+// SetBodyFromTemplateStore sets the body of the Msg to the template pair with the given name of the
+// TemplateStore.
+//
+// The plain text template is rendered as the body and the HTML template as its alternative. If the
+// pair only has one of the templates, it is rendered as the only body. This replaces the separate
+// calls to SetBodyTextTemplate and AddAlternativeHTMLTemplate.
+//
+// Parameters:
+//   - store: The TemplateStore that holds the template pair.
+//   - name: The name of the template pair.
+//   - data: The data to populate the templates.
+//   - opts: Optional parameters for customizing the body parts.
+//
+// Returns:
+//   - An error if the store is nil, the template pair is not registered or fails to execute;
+//     otherwise, returns nil.
+func (m *Msg) SetBodyFromTemplateStore(store *TemplateStore, name string, data interface{},
+	opts ...PartOption,
+) error {
+	if store == nil {
+		return errors.New(errTplPointerNil)
+	}
+	pair, text, html, err := store.render(name, data)
+	if err != nil {
+		return err
+	}
+	if pair.text == nil {
+		m.SetBodyString(TypeTextHTML, html, opts...)
+		return nil
+	}
+	m.SetBodyString(TypeTextPlain, text, opts...)
+	if pair.html != nil {
+		m.AddAlternativeString(TypeTextHTML, html, opts...)
+	}
+	return nil
+}
+
+// pair returns the compiled template pair with the given name.
+func (s *TemplateStore) pair(name string) (*templatePair, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	pair, ok := s.compiled[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrTemplateNotFound, name)
+	}
+	return pair, nil
+}
+
+// render renders the template pair with the given name with the given data and returns the pair
+// together with the rendered plain text and HTML.
+func (s *TemplateStore) render(name string, data interface{}) (*templatePair, string, string, error) {
+	pair, err := s.pair(name)
+	if err != nil {
+		return nil, "", "", err
+	}
+	var text, html string
+	if pair.text != nil {
+		buffer := bytes.NewBuffer(nil)
+		if err = pair.text.ExecuteTemplate(buffer, pair.entry, data); err != nil {
+			return nil, "", "", fmt.Errorf(errTplExecuteFailed, err)
+		}
+		text = buffer.String()
+	}
+	if pair.html != nil {
+		buffer := bytes.NewBuffer(nil)
+		if err = pair.html.ExecuteTemplate(buffer, pair.entry, data); err != nil {
+			return nil, "", "", fmt.Errorf(errTplExecuteFailed, err)
+		}
+		html = buffer.String()
+	}
+	return pair, text, html, nil
+}
+
+// compile compiles the given template source with the partials and the layout of the TemplateStore.
+// The caller must hold the lock of the TemplateStore.
+func (s *TemplateStore) compile(source templateSource) (*templatePair, error) {
+	pair := &templatePair{entry: templateStoreContent}
+	if s.hasLayout {
+		pair.entry = templateStoreLayout
+	}
+	textBase, htmlBase, err := s.cloneBase()
+	if err != nil {
+		return nil, err
+	}
+	if source.text != "" {
+		if pair.text, err = textBase.New(templateStoreContent).Parse(source.text); err != nil {
+			return nil, fmt.Errorf("failed to parse text template: %w", err)
+		}
+	}
+	if source.html != "" {
+		if pair.html, err = htmlBase.New(templateStoreContent).Parse(source.html); err != nil {
+			return nil, fmt.Errorf("failed to parse HTML template: %w", err)
+		}
+	}
+	return pair, nil
+}
+
+// cloneBase returns clones of the text and HTML base templates of the TemplateStore.
+func (s *TemplateStore) cloneBase() (*tt.Template, *ht.Template, error) {
+	textBase, err := s.textBase.Clone()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to clone text templates: %w", err)
+	}
+	htmlBase, err := s.htmlBase.Clone()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to clone HTML templates: %w", err)
+	}
+	return textBase, htmlBase, nil
+}
+
+// updateBase replaces the base templates of the TemplateStore after verifying that all registered
+// template pairs compile with them. The caller must hold the lock of the TemplateStore.
+func (s *TemplateStore) updateBase(textBase *tt.Template, htmlBase *ht.Template, hasLayout bool) error {
+	previousText, previousHTML, previousLayout := s.textBase, s.htmlBase, s.hasLayout
+	s.textBase, s.htmlBase, s.hasLayout = textBase, htmlBase, hasLayout
+	compiled := make(map[string]*templatePair, len(s.sources))
+	for name, source := range s.sources {
+		pair, err := s.compile(source)
+		if err != nil {
+			s.textBase, s.htmlBase, s.hasLayout = previousText, previousHTML, previousLayout
+			return fmt.Errorf("failed to compile template %q: %w", name, err)
+		}
+		compiled[name] = pair
+	}
+	s.compiled = compiled
+	return nil
+}
+
+// RegisterFromFS registers a pair of plain text and HTML templates with the given name, like
+// Register, but reads the template sources from the given files of an fs.FS.
+//
+// Parameters:
+//   - fsys: The fs.FS from which the templates will be read.
+//   - name: The name of the template pair.
+//   - textPath: The path of the plain text template in the filesystem, or an empty string.
+//   - htmlPath: The path of the HTML template in the filesystem, or an empty string.
+//
+// Returns:
+//   - An error if the fs.FS is nil, any of the files cannot be read or the template pair cannot be
+//     registered; otherwise, returns nil.
+func (s *TemplateStore) RegisterFromFS(fsys fs.FS, name, textPath, htmlPath string) error {
+	text, html, err := readTemplatePair(fsys, textPath, htmlPath)
+	if err != nil {
+		return err
+	}
+	return s.Register(name, text, html)
+}
+
+// AddPartialFromFS adds a partial with the given name to the TemplateStore, like AddPartial, but reads
+// the sources from the given files of an fs.FS.
+//
+// Parameters:
+//   - fsys: The fs.FS from which the partial will be read.
+//   - name: The name of the partial.
+//   - textPath: The path of the plain text variant in the filesystem, or an empty string.
+//   - htmlPath: The path of the HTML variant in the filesystem, or an empty string.
+//
+// Returns:
+//   - An error if the fs.FS is nil, any of the files cannot be read or parsed; otherwise, returns nil.
+func (s *TemplateStore) AddPartialFromFS(fsys fs.FS, name, textPath, htmlPath string) error {
+	text, html, err := readTemplatePair(fsys, textPath, htmlPath)
+	if err != nil {
+		return err
+	}
+	return s.AddPartial(name, text, html)
+}
+
+// SetLayoutFromFS sets the layout of the TemplateStore, like SetLayout, but reads the sources from
+// the given files of an fs.FS.
+//
+// Parameters:
+//   - fsys: The fs.FS from which the layout will be read.
+//   - textPath: The path of the plain text layout in the filesystem, or an empty string.
+//   - htmlPath: The path of the HTML layout in the filesystem, or an empty string.
+//
+// Returns:
+//   - An error if the fs.FS is nil, any of the files cannot be read or parsed; otherwise, returns nil.
+func (s *TemplateStore) SetLayoutFromFS(fsys fs.FS, textPath, htmlPath string) error {
+	text, html, err := readTemplatePair(fsys, textPath, htmlPath)
+	if err != nil {
+		return err
+	}
+	return s.SetLayout(text, html)
+}
+
+// readTemplatePair reads the plain text and HTML template sources from the given files of the fs.FS.
+// An empty path results in an empty source.
+func readTemplatePair(fsys fs.FS, textPath, htmlPath string) (string, string, error) {
+	if fsys == nil {
+		return "", "", errors.New(errFSIsNil)
+	}
+	sources := make([]string, 2)
+	for i, name := range []string{textPath, htmlPath} {
+		if name == "" {
+			continue
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read template from fs.FS: %w", err)
+		}
+		sources[i] = string(content)
+	}
+	return sources[0], sources[1], nil
+}
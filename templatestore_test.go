@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+// testTemplateStore returns a TemplateStore with a layout, a partial and a "welcome" template pair.
+func testTemplateStore(t *testing.T) *TemplateStore {
+	t.Helper()
+	store := NewTemplateStore()
+	if err := store.AddPartial("footer", "-- {{.Team}}", "<footer>{{.Team}}</footer>"); err != nil {
+		t.Fatalf("failed to add partial: %s", err)
+	}
+	if err := store.SetLayout(`{{template "content" .}}`+"\n"+`{{template "footer" .}}`,
+		`<html><body>{{template "content" .}}{{template "footer" .}}</body></html>`); err != nil {
+		t.Fatalf("failed to set layout: %s", err)
+	}
+	if err := store.Register("welcome", "Hello {{.Name}}", "<p>Hello {{.Name}}</p>"); err != nil {
+		t.Fatalf("failed to register template: %s", err)
+	}
+	return store
+}
+
+// templateStoreData is the data used in the TemplateStore tests.
+type templateStoreData struct {
+	Name string
+	Team string
+}
+
+func TestTemplateStore_Render(t *testing.T) {
+	t.Run("render with layout and partial", func(t *testing.T) {
+		text, html, err := testTemplateStore(t).Render("welcome", templateStoreData{"<Toni>", "Team"})
+		if err != nil {
+			t.Fatalf("failed to render template: %s", err)
+		}
+		if text != "Hello <Toni>\n-- Team" {
+			t.Errorf("unexpected text: %q", text)
+		}
+		if html != "<html><body><p>Hello &lt;Toni&gt;</p><footer>Team</footer></body></html>" {
+			t.Errorf("unexpected HTML: %q", html)
+		}
+	})
+	t.Run("render without layout", func(t *testing.T) {
+		store := NewTemplateStore()
+		if err := store.Register("plain", "Hello {{.Name}}", ""); err != nil {
+			t.Fatalf("failed to register template: %s", err)
+		}
+		text, html, err := store.Render("plain", templateStoreData{Name: "Toni"})
+		if err != nil {
+			t.Fatalf("failed to render template: %s", err)
+		}
+		if text != "Hello Toni" || html != "" {
+			t.Errorf("unexpected rendering: %q, %q", text, html)
+		}
+	})
+	t.Run("templates registered before the layout use it", func(t *testing.T) {
+		store := NewTemplateStore()
+		if err := store.Register("plain", "Hello", ""); err != nil {
+			t.Fatalf("failed to register template: %s", err)
+		}
+		if err := store.SetLayout(`[{{template "content" .}}]`, ""); err != nil {
+			t.Fatalf("failed to set layout: %s", err)
+		}
+		if text, _, err := store.Render("plain", nil); err != nil || text != "[Hello]" {
+			t.Errorf("unexpected rendering: %q, %v", text, err)
+		}
+	})
+	t.Run("unknown template", func(t *testing.T) {
+		if _, _, err := testTemplateStore(t).Render("unknown", nil); !errors.Is(err, ErrTemplateNotFound) {
+			t.Errorf("expected error: %s, got: %s", ErrTemplateNotFound, err)
+		}
+	})
+	t.Run("failing template", func(t *testing.T) {
+		if _, _, err := testTemplateStore(t).Render("welcome", struct{}{}); err == nil {
+			t.Error("expected template with missing data to fail")
+		}
+	})
+	t.Run("concurrent rendering", func(t *testing.T) {
+		store := testTemplateStore(t)
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, _, err := store.Render("welcome", templateStoreData{"Toni", "Team"}); err != nil {
+					t.Errorf("failed to render template: %s", err)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestTemplateStore_Register(t *testing.T) {
+	store := testTemplateStore(t)
+	if err := store.Register("empty", "", ""); !errors.Is(err, ErrTemplateEmpty) {
+		t.Errorf("expected error: %s, got: %s", ErrTemplateEmpty, err)
+	}
+	if err := store.Register("invalid", "{{.Name", ""); err == nil {
+		t.Error("expected invalid text template to fail")
+	}
+	if err := store.Register("invalid", "", "{{.Name"); err == nil {
+		t.Error("expected invalid HTML template to fail")
+	}
+	if _, _, err := store.Render("invalid", nil); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("expected invalid template not to be registered, got: %v", err)
+	}
+	if err := store.AddPartial("broken", "{{", ""); err == nil {
+		t.Error("expected invalid partial to fail")
+	}
+	if err := store.SetLayout("", "{{"); err == nil {
+		t.Error("expected invalid layout to fail")
+	}
+	if err := store.SetLayout(`{{template "missing" .}}`, ""); err != nil {
+		t.Fatalf("failed to set layout: %s", err)
+	}
+	if _, _, err := store.Render("welcome", templateStoreData{"Toni", "Team"}); err == nil {
+		t.Error("expected layout with missing partial to fail")
+	}
+}
+
+func TestMsg_SetBodyFromTemplateStore(t *testing.T) {
+	store := testTemplateStore(t)
+	if err := store.Register("html-only", "", "<p>{{.Name}}</p>"); err != nil {
+		t.Fatalf("failed to register template: %s", err)
+	}
+	t.Run("text body with HTML alternative", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.SetBodyFromTemplateStore(store, "welcome", templateStoreData{"Toni", "Team"}); err != nil {
+			t.Fatalf("failed to set body from template store: %s", err)
+		}
+		parts := message.GetParts()
+		if len(parts) != 2 || parts[0].GetContentType() != TypeTextPlain || parts[1].GetContentType() != TypeTextHTML {
+			t.Fatalf("expected text body with HTML alternative, got %d parts", len(parts))
+		}
+		buffer := bytes.NewBuffer(nil)
+		if _, err := message.WriteTo(buffer); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+		if !strings.Contains(buffer.String(), "multipart/alternative") {
+			t.Errorf("expected multipart/alternative message, got: %s", buffer.String())
+		}
+	})
+	t.Run("HTML only", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.SetBodyFromTemplateStore(store, "html-only", templateStoreData{Name: "Toni"}); err != nil {
+			t.Fatalf("failed to set body from template store: %s", err)
+		}
+		parts := message.GetParts()
+		if len(parts) != 1 || parts[0].GetContentType() != TypeTextHTML {
+			t.Fatalf("expected single HTML body, got %d parts", len(parts))
+		}
+	})
+	t.Run("nil store and unknown template", func(t *testing.T) {
+		message := testMessage(t)
+		if err := message.SetBodyFromTemplateStore(nil, "welcome", nil); err == nil {
+			t.Error("expected nil store to fail")
+		}
+		if err := message.SetBodyFromTemplateStore(store, "unknown", nil); !errors.Is(err, ErrTemplateNotFound) {
+			t.Errorf("expected error: %s, got: %s", ErrTemplateNotFound, err)
+		}
+	})
+}
+
+func TestTemplateStore_FromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.txt":    {Data: []byte(`{{template "content" .}} {{template "footer" .}}`)},
+		"layout.html":   {Data: []byte(`<body>{{template "content" .}}{{template "footer" .}}</body>`)},
+		"footer.txt":    {Data: []byte("-- {{.Team}}")},
+		"footer.html":   {Data: []byte("<footer>{{.Team}}</footer>")},
+		"welcome.txt":   {Data: []byte("Hello {{.Name}}")},
+		"welcome.html":  {Data: []byte("<p>Hello {{.Name}}</p>")},
+		"invalid.html":  {Data: []byte("{{.Name")},
+		"directory/sub": {Data: []byte("")},
+	}
+	store := NewTemplateStore()
+	if err := store.AddPartialFromFS(fsys, "footer", "footer.txt", "footer.html"); err != nil {
+		t.Fatalf("failed to add partial from fs.FS: %s", err)
+	}
+	if err := store.SetLayoutFromFS(fsys, "layout.txt", "layout.html"); err != nil {
+		t.Fatalf("failed to set layout from fs.FS: %s", err)
+	}
+	if err := store.RegisterFromFS(fsys, "welcome", "welcome.txt", "welcome.html"); err != nil {
+		t.Fatalf("failed to register template from fs.FS: %s", err)
+	}
+	if err := store.RegisterFromFS(fsys, "html-only", "", "welcome.html"); err != nil {
+		t.Fatalf("failed to register template from fs.FS: %s", err)
+	}
+	text, html, err := store.Render("welcome", templateStoreData{"Toni", "Team"})
+	if err != nil {
+		t.Fatalf("failed to render template: %s", err)
+	}
+	if text != "Hello Toni -- Team" || html != "<body><p>Hello Toni</p><footer>Team</footer></body>" {
+		t.Errorf("unexpected rendering: %q, %q", text, html)
+	}
+
+	if err = store.RegisterFromFS(fsys, "missing", "missing.txt", ""); err == nil {
+		t.Error("expected missing file to fail")
+	}
+	if err = store.RegisterFromFS(fsys, "invalid", "", "invalid.html"); err == nil {
+		t.Error("expected invalid template to fail")
+	}
+	if err = store.AddPartialFromFS(nil, "footer", "footer.txt", ""); err == nil {
+		t.Error("expected nil fs.FS to fail")
+	}
+	if err = store.SetLayoutFromFS(fsys, "directory", ""); err == nil {
+		t.Error("expected directory to fail")
+	}
+}
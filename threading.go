@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"strings"
+)
+
+// maxReferencesLength is the maximum length of the value of the "References" header, so that the
+// header fits on a single line of MaxHeaderLineLength characters.
+const maxReferencesLength = MaxHeaderLineLength - len(HeaderReferences) - 2
+
+// SetInReplyTo sets the "In-Reply-To" header of the Msg to the given Message-ID of the message that
+// is replied to. Angle brackets are added to the Message-ID if missing. An empty Message-ID is
+// ignored.
+//
+// Parameters:
+//   - msgID: The Message-ID of the message that is replied to.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.6.4
+func (m *Msg) SetInReplyTo(msgID string) {
+	msgID = normalizeMessageID(msgID)
+	if msgID == "" {
+		return
+	}
+	m.SetGenHeader(HeaderInReplyTo, msgID)
+}
+
+// AddReference adds the given Message-ID to the "References" header of the Msg. Angle brackets are
+// added to the Message-ID if missing, and Message-IDs that are already referenced or empty are
+// ignored.
+//
+// If the "References" header would exceed the maximum header line length, the oldest references
+// are removed, except for the first one, which identifies the start of the thread.
+//
+// Parameters:
+//   - msgID: The Message-ID to add to the "References" header.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.6.4
+//   - https://datatracker.ietf.org/doc/html/rfc5537#section-3.4.4
+func (m *Msg) AddReference(msgID string) {
+	msgID = normalizeMessageID(msgID)
+	if msgID == "" {
+		return
+	}
+	references := messageIDList(m.GetGenHeader(HeaderReferences))
+	for _, reference := range references {
+		if reference == msgID {
+			return
+		}
+	}
+	m.setReferences(append(references, msgID))
+}
+
+// ThreadFrom sets the "In-Reply-To" and "References" headers of the Msg, so that it is threaded as a
+// reply to the given parent Msg.
+//
+// The "In-Reply-To" header is set to the Message-ID of the parent, and the "References" header to
+// the references of the parent, followed by the Message-ID of the parent. If the parent has no
+// "References" header, its "In-Reply-To" header is used instead. If the parent is nil or has no
+// Message-ID, the Msg remains unchanged.
+//
+// Parameters:
+//   - parent: The Msg that is replied to.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc5322#section-3.6.4
+func (m *Msg) ThreadFrom(parent *Msg) {
+	references := threadReferences(parent)
+	if len(references) == 0 {
+		return
+	}
+	m.SetInReplyTo(references[len(references)-1])
+	m.setReferences(references)
+}
+
+// setReferences sets the "References" header of the Msg to the given Message-IDs, truncated to the
+// maximum header line length.
+func (m *Msg) setReferences(references []string) {
+	m.SetGenHeader(HeaderReferences, strings.Join(truncateReferences(references), " "))
+}
+
+// threadReferences returns the Message-IDs a reply to the given parent Msg references, ending with
+// the Message-ID of the parent, or nil if the parent is nil or has no Message-ID.
+func threadReferences(parent *Msg) []string {
+	if parent == nil {
+		return nil
+	}
+	parentID := normalizeMessageID(parent.GetMessageID())
+	if parentID == "" {
+		return nil
+	}
+	references := messageIDList(parent.GetGenHeader(HeaderReferences))
+	if len(references) == 0 {
+		references = messageIDList(parent.GetGenHeader(HeaderInReplyTo))
+	}
+	for i := 0; i < len(references); i++ {
+		if references[i] == parentID {
+			references = append(references[:i], references[i+1:]...)
+			i--
+		}
+	}
+	return append(references, parentID)
+}
+
+// truncateReferences removes the oldest Message-IDs but the first one from the given references until
+// their space-separated length does not exceed maxReferencesLength.
+func truncateReferences(references []string) []string {
+	length := len(references) - 1
+	for _, reference := range references {
+		length += len(reference)
+	}
+	for length > maxReferencesLength && len(references) > 2 {
+		length -= len(references[1]) + 1
+		references = append(references[:1], references[2:]...)
+	}
+	return references
+}
+
+// messageIDList splits the given header values into their whitespace-separated Message-IDs.
+func messageIDList(values []string) []string {
+	var messageIDs []string
+	for _, value := range values {
+		for _, messageID := range strings.Fields(value) {
+			if messageID = normalizeMessageID(messageID); messageID != "" {
+				messageIDs = append(messageIDs, messageID)
+			}
+		}
+	}
+	return messageIDs
+}
+
+// normalizeMessageID trims the given Message-ID and encloses it in angle brackets if missing.
+func normalizeMessageID(msgID string) string {
+	msgID = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(msgID), "<"), ">")
+	if msgID == "" {
+		return ""
+	}
+	return "<" + msgID + ">"
+}
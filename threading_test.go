@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMsg_SetInReplyTo(t *testing.T) {
+	tests := []struct {
+		name  string
+		msgID string
+		want  []string
+	}{
+		{"message ID without brackets", "id@domain.tld", []string{"<id@domain.tld>"}},
+		{"message ID with brackets", " <id@domain.tld> ", []string{"<id@domain.tld>"}},
+		{"empty message ID is ignored", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := NewMsg()
+			message.SetInReplyTo(tt.msgID)
+			got := message.GetGenHeader(HeaderInReplyTo)
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("unexpected In-Reply-To header, want: %v, got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMsg_AddReference(t *testing.T) {
+	t.Run("references are added once", func(t *testing.T) {
+		message := NewMsg()
+		message.AddReference("first@domain.tld")
+		message.AddReference("<second@domain.tld>")
+		message.AddReference("first@domain.tld")
+		message.AddReference("")
+		references := message.GetGenHeader(HeaderReferences)
+		if len(references) != 1 || references[0] != "<first@domain.tld> <second@domain.tld>" {
+			t.Errorf("unexpected References header: %v", references)
+		}
+	})
+	t.Run("references are truncated", func(t *testing.T) {
+		message := NewMsg()
+		for i := 0; i < 100; i++ {
+			message.AddReference(fmt.Sprintf("message-id-%03d@domain.tld", i))
+		}
+		references := message.GetGenHeader(HeaderReferences)
+		if len(references) != 1 || len(references[0]) > maxReferencesLength {
+			t.Fatalf("expected References header to be truncated, got: %v", references)
+		}
+		if !strings.HasPrefix(references[0], "<message-id-000@domain.tld> ") ||
+			!strings.HasSuffix(references[0], " <message-id-099@domain.tld>") {
+			t.Errorf("expected first and latest references to be kept, got: %s", references[0])
+		}
+	})
+}
+
+func TestMsg_ThreadFrom(t *testing.T) {
+	t.Run("thread from parent with references", func(t *testing.T) {
+		parent := NewMsg()
+		parent.SetMessageIDWithValue("parent@domain.tld")
+		parent.SetGenHeader(HeaderReferences, "<first@domain.tld> <second@domain.tld>")
+		message := NewMsg()
+		message.ThreadFrom(parent)
+		if inReplyTo := message.GetGenHeader(HeaderInReplyTo); len(inReplyTo) != 1 ||
+			inReplyTo[0] != "<parent@domain.tld>" {
+			t.Errorf("unexpected In-Reply-To header: %v", inReplyTo)
+		}
+		if references := message.GetGenHeader(HeaderReferences); len(references) != 1 ||
+			references[0] != "<first@domain.tld> <second@domain.tld> <parent@domain.tld>" {
+			t.Errorf("unexpected References header: %v", references)
+		}
+	})
+	t.Run("thread from parent with In-Reply-To only", func(t *testing.T) {
+		parent := NewMsg()
+		parent.SetMessageIDWithValue("parent@domain.tld")
+		parent.SetInReplyTo("first@domain.tld")
+		message := NewMsg()
+		message.ThreadFrom(parent)
+		if references := message.GetGenHeader(HeaderReferences); len(references) != 1 ||
+			references[0] != "<first@domain.tld> <parent@domain.tld>" {
+			t.Errorf("unexpected References header: %v", references)
+		}
+	})
+	t.Run("parent without message ID is ignored", func(t *testing.T) {
+		message := NewMsg()
+		message.ThreadFrom(NewMsg())
+		message.ThreadFrom(nil)
+		if len(message.GetGenHeader(HeaderInReplyTo)) != 0 || len(message.GetGenHeader(HeaderReferences)) != 0 {
+			t.Errorf("expected threading headers to be unset")
+		}
+	})
+}
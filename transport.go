@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package mail
+
+import (
+	"context"
+)
+
+// Transport is an interface for sending one or more Msg.
+//
+// It is satisfied by the Client and the ClientPool, so that applications can depend on the
+// Transport interface instead of a concrete Client and replace it, i. e. with the in-memory
+// transport of the mailmock package in unit tests.
+type Transport interface {
+	// SendWithContext sends the given messages. The provided context.Context controls the
+	// cancellation of the send operation.
+	SendWithContext(ctx context.Context, messages ...*Msg) error
+}
+
+var (
+	// Ensure that the Client and the ClientPool satisfy the Transport interface.
+	_ Transport = (*Client)(nil)
+	_ Transport = (*ClientPool)(nil)
+)
@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+// Package warmup implements a scheduler that enforces daily volume ramp-up curves for new
+// sending domains or IP addresses
+package warmup
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Day is the length of a warm-up day
+const Day = time.Hour * 24
+
+var (
+	// PresetConservative is a slow ramp-up curve for senders without any reputation
+	PresetConservative = Curve{50, 100, 200, 400, 800, 1500, 3000, 5000, 8000, 12000, 20000, 30000, 50000}
+
+	// PresetModerate is a ramp-up curve for senders with an established reputation on other
+	// domains or IP addresses
+	PresetModerate = Curve{200, 500, 1000, 2000, 4000, 8000, 15000, 25000, 50000, 100000}
+
+	// PresetAggressive is a fast ramp-up curve for low-volume transactional senders
+	PresetAggressive = Curve{1000, 2500, 5000, 10000, 25000, 50000, 100000}
+)
+
+// Curve holds the maximum number of messages per day, starting with the first day of the
+// warm-up. After the last day of the Curve, the limit of the last day applies. A limit of zero or
+// an empty Curve means that the volume is not limited.
+type Curve []int
+
+// Option is a function type that modifies the configuration of a Scheduler
+type Option func(*Scheduler)
+
+// Scheduler enforces a warm-up Curve per key, i. e. a sending domain or IP address
+//
+// Each send needs to be reserved with Reserve or Wait. The Scheduler spreads the daily volume
+// evenly across the day, so that early sends are not sent out in a single burst. Once the daily
+// limit is reached, further sends are scheduled for the next warm-up day. A Scheduler is safe
+// for concurrent use.
+type Scheduler struct {
+	curve  Curve
+	curves map[string]Curve
+	mutex  sync.Mutex
+	now    func() time.Time
+	starts map[string]time.Time
+	states map[string]*state
+}
+
+// state holds the reservations of a single key
+type state struct {
+	count int
+	day   int
+	next  time.Time
+}
+
+// NewScheduler returns a new Scheduler that applies the given Curve to all keys without a
+// specific Curve
+func NewScheduler(curve Curve, opts ...Option) *Scheduler {
+	scheduler := &Scheduler{
+		curve:  curve,
+		curves: make(map[string]Curve),
+		now:    time.Now,
+		starts: make(map[string]time.Time),
+		states: make(map[string]*state),
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(scheduler)
+	}
+	return scheduler
+}
+
+// WithCurve sets a specific Curve for the given key
+func WithCurve(key string, curve Curve) Option {
+	return func(s *Scheduler) {
+		s.curves[normalizeKey(key)] = curve
+	}
+}
+
+// WithStart sets the start of the warm-up for the given key. This allows a warm-up to be
+// continued after a restart of the application. Without a start time, the warm-up of a key
+// starts with its first reservation.
+func WithStart(key string, start time.Time) Option {
+	return func(s *Scheduler) {
+		s.starts[normalizeKey(key)] = start
+	}
+}
+
+// Limit returns the daily limit that currently applies to the given key. A limit of zero means
+// that the volume is not limited.
+func (s *Scheduler) Limit(key string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	key = normalizeKey(key)
+	return s.curveFor(key).limit(s.dayIndex(key, s.now()))
+}
+
+// Reserve reserves a send for the given key and returns the point in time at which the send
+// may take place. The returned time is never before the current time.
+func (s *Scheduler) Reserve(key string) time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key = normalizeKey(key)
+	now := s.now()
+	if _, ok := s.starts[key]; !ok {
+		s.starts[key] = now
+	}
+	keyState, ok := s.states[key]
+	if !ok {
+		keyState = &state{day: -1}
+		s.states[key] = keyState
+	}
+
+	curve := s.curveFor(key)
+	slot := now
+	if keyState.next.After(slot) {
+		slot = keyState.next
+	}
+	for {
+		day := s.dayIndex(key, slot)
+		if day != keyState.day {
+			keyState.day = day
+			keyState.count = 0
+		}
+		limit := curve.limit(day)
+		if limit <= 0 {
+			return slot
+		}
+		if keyState.count >= limit {
+			slot = s.starts[key].Add(Day * time.Duration(day+1))
+			continue
+		}
+		keyState.count++
+		keyState.next = slot.Add(Day / time.Duration(limit))
+		return slot
+	}
+}
+
+// Wait reserves a send for the given key and blocks until the send may take place or the
+// context is done. Note that the reservation is not released if the context is done.
+func (s *Scheduler) Wait(ctx context.Context, key string) error {
+	delay := s.Reserve(key).Sub(s.now())
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// curveFor returns the Curve for the given key
+func (s *Scheduler) curveFor(key string) Curve {
+	if curve, ok := s.curves[key]; ok {
+		return curve
+	}
+	return s.curve
+}
+
+// dayIndex returns the warm-up day of the given key at the given time
+func (s *Scheduler) dayIndex(key string, t time.Time) int {
+	start, ok := s.starts[key]
+	if !ok || t.Before(start) {
+		return 0
+	}
+	return int(t.Sub(start) / Day)
+}
+
+// limit returns the limit of the given warm-up day
+func (c Curve) limit(day int) int {
+	if len(c) == 0 {
+		return 0
+	}
+	if day >= len(c) {
+		return c[len(c)-1]
+	}
+	return c[day]
+}
+
+// normalizeKey returns the case-insensitive representation of the given key
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.TrimSpace(key))
+}
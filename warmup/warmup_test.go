@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: Copyright (c) 2024 The go-mail Authors
+//
+// SPDX-License-Identifier: MIT
+
+package warmup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testClock is a manually advanced clock for the Scheduler
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func newTestScheduler(curve Curve, opts ...Option) (*Scheduler, *testClock) {
+	clock := &testClock{now: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)}
+	scheduler := NewScheduler(curve, opts...)
+	scheduler.now = clock.Now
+	return scheduler, clock
+}
+
+func TestScheduler_Reserve(t *testing.T) {
+	t.Run("sends are spread across the day", func(t *testing.T) {
+		scheduler, clock := newTestScheduler(Curve{4, 8})
+		start := clock.now
+		for i := 0; i < 4; i++ {
+			want := start.Add(time.Hour * 6 * time.Duration(i))
+			if got := scheduler.Reserve("example.com"); !got.Equal(want) {
+				t.Errorf("reservation %d: expected %s, got: %s", i, want, got)
+			}
+		}
+		// Daily limit is reached, the next send is scheduled for day two at a higher rate
+		for i := 0; i < 2; i++ {
+			want := start.Add(Day + time.Hour*3*time.Duration(i))
+			if got := scheduler.Reserve("EXAMPLE.com"); !got.Equal(want) {
+				t.Errorf("reservation on day two: expected %s, got: %s", want, got)
+			}
+		}
+	})
+	t.Run("reservations are never in the past", func(t *testing.T) {
+		scheduler, clock := newTestScheduler(Curve{24})
+		scheduler.Reserve("example.com")
+		clock.now = clock.now.Add(time.Hour * 5)
+		if got := scheduler.Reserve("example.com"); !got.Equal(clock.now) {
+			t.Errorf("expected reservation at current time %s, got: %s", clock.now, got)
+		}
+	})
+	t.Run("keys have independent curves", func(t *testing.T) {
+		scheduler, clock := newTestScheduler(Curve{1}, WithCurve("192.0.2.1", Curve{}))
+		scheduler.Reserve("example.com")
+		if got := scheduler.Reserve("example.com"); !got.Equal(clock.now.Add(Day)) {
+			t.Errorf("expected reservation on next day, got: %s", got)
+		}
+		for i := 0; i < 10; i++ {
+			if got := scheduler.Reserve("192.0.2.1"); !got.Equal(clock.now) {
+				t.Errorf("expected unlimited key to be reserved immediately, got: %s", got)
+			}
+		}
+	})
+	t.Run("warm-up continues from start time", func(t *testing.T) {
+		clockStart := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+		scheduler, _ := newTestScheduler(Curve{1, 2, 3}, WithStart("example.com", clockStart.Add(-Day*5)))
+		if limit := scheduler.Limit("example.com"); limit != 3 {
+			t.Errorf("expected limit of last curve day: %d, got: %d", 3, limit)
+		}
+		if limit := scheduler.Limit("unknown.example.com"); limit != 1 {
+			t.Errorf("expected limit of first curve day: %d, got: %d", 1, limit)
+		}
+	})
+}
+
+func TestScheduler_Wait(t *testing.T) {
+	t.Run("wait returns immediately for available slot", func(t *testing.T) {
+		scheduler := NewScheduler(PresetAggressive)
+		if err := scheduler.Wait(context.Background(), "example.com"); err != nil {
+			t.Errorf("failed to wait for reservation: %s", err)
+		}
+	})
+	t.Run("wait is aborted by context", func(t *testing.T) {
+		scheduler := NewScheduler(Curve{1})
+		scheduler.Reserve("example.com")
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+		if err := scheduler.Wait(ctx, "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected error: %s, got: %s", context.DeadlineExceeded, err)
+		}
+	})
+	t.Run("nil options are ignored", func(t *testing.T) {
+		scheduler := NewScheduler(PresetModerate, nil)
+		if limit := scheduler.Limit("example.com"); limit != PresetModerate[0] {
+			t.Errorf("expected limit: %d, got: %d", PresetModerate[0], limit)
+		}
+	})
+}